@@ -533,8 +533,8 @@ func runDoltSQL(cmd *cobra.Command, args []string) error {
 			"sql",
 		}
 		sqlCmd := exec.Command("dolt", sqlArgs...)
-		if config.Password != "" {
-			sqlCmd.Env = append(os.Environ(), "DOLT_CLI_PASSWORD="+config.Password)
+		if cred := config.Credential(); cred != "" {
+			sqlCmd.Env = append(os.Environ(), "DOLT_CLI_PASSWORD="+cred)
 		}
 		sqlCmd.Stdin = os.Stdin
 		sqlCmd.Stdout = os.Stdout
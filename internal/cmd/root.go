@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -226,11 +227,25 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
 		}
+		// An interrupted gt wl command says so instead of dumping usage
+		if errors.Is(err, errWlInterrupted) {
+			if isWLJSONError(cmd) {
+				writeWLJSONError(os.Stderr, err)
+			} else {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return wlInterruptExitCode
+		}
+		// gt wl --json reports errors as JSON; cobra was silenced for it
+		if isWLJSONError(cmd) {
+			writeWLJSONError(os.Stderr, err)
+			return 1
+		}
 		// Other errors already printed by cobra
 		return 1
 	}
@@ -23,7 +23,7 @@ var wlCmd = &cobra.Command{
 	Use:     "wl",
 	GroupID: GroupWork,
 	Short:   "Wasteland federation commands",
-	RunE:    requireSubcommand,
+	RunE:    runWlSummary,
 	Long: `Manage Wasteland federation — join communities, post work, earn reputation.
 
 The Wasteland is a federation of Gas Towns via DoltHub. Each rig has a
@@ -33,6 +33,35 @@ sovereign fork of a shared commons database containing the wanted board
 Getting started:
   gt wl join steveyegge/wl-commons   # Join the default wasteland
 
+Run without a subcommand in a joined town, gt wl prints how many wanted
+items have each status (e.g. "open: 12  claimed: 3  in_review: 1"); gt wl
+status has the fuller picture.
+
+Commands work on the wl_commons database unless --db, or the database
+recorded in the town's wasteland config, names another.
+
+--dry-run prints the SQL each write would run instead of running it, for
+review before trusting automation with a shared board. Reads still run,
+so checks and validation messages are real; watchers are not notified,
+nothing is logged and --hold-file markers stay put. The closing line reads
+"dry run, not executed:" in place of ✓. join and bench do not support it.
+
+Each dolt SQL call is cut off after 15s (queries) or 30s (writes). Against
+a server over a slow link, raise the limit with --timeout or the
+GT_DOLT_TIMEOUT environment variable (e.g. 2m, or 120 for seconds).
+
+Against a remote server (GT_DOLT_HOST), a call that fails on a network
+blip such as a refused connection or a dial timeout is retried 3 times
+with backoff; SQL errors fail at once. Change the count with --retries or
+GT_DOLT_RETRIES (0 turns retries off).
+
+--verbose (-v) traces every dolt invocation to stderr: the server config,
+each command line and SQL script, and the raw output of any that fail.
+Passwords and tokens are redacted.
+
+Ctrl-C (or SIGTERM) cancels the dolt call in flight and kills its process
+rather than leaving it running; gt prints "cancelled" and exits with 130.
+
 See https://github.com/steveyegge/gastown for more information.`,
 }
 
@@ -47,6 +76,11 @@ This command:
   3. Registers your rig in the rigs table
   4. Pushes the registration to your fork
   5. Saves wasteland configuration locally
+  6. Clones the fork into the Dolt server as the wl-commons database
+
+Joining again is safe: an existing join is kept, and step 6 is repeated
+if the database has gone missing. A database without the wanted and
+completions tables is rejected as not a wasteland.
 
 The upstream argument is a DoltHub path like 'steveyegge/wl-commons'.
 
@@ -79,50 +113,59 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Require DoltHub credentials
-	token := doltserver.DoltHubToken()
-	if token == "" {
-		return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
-	}
-
-	forkOrg := doltserver.DoltHubOrg()
-	if forkOrg == "" {
-		return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
-	}
-
 	// Find town root
-	townRoot, err := workspace.FindFromCwdOrError()
+	townRoot, err := wlRun.townRoot()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		return err
 	}
 
 	// Fast path: check if already joined before loading town config.
 	// This avoids failing on unrelated town-config errors for the no-op case.
-	if existing, loadErr := wasteland.LoadConfig(townRoot); loadErr == nil {
+	if existing, loadErr := wlRun.wastelandConfig(); loadErr == nil {
 		if existing.Upstream == upstream {
 			fmt.Printf("%s Already joined wasteland: %s\n", style.Bold.Render("⚠"), upstream)
 			fmt.Printf("  Handle: %s\n", existing.RigHandle)
 			fmt.Printf("  Fork: %s/%s\n", existing.ForkOrg, existing.ForkDB)
 			fmt.Printf("  Local: %s\n", existing.LocalDir)
-			return nil
+			return attachWlCommons(townRoot, existing)
 		}
 		return fmt.Errorf("already joined to %s; run gt wl leave first", existing.Upstream)
 	}
 
+	if _, err := joinWasteland(townRoot, upstream, wlJoinHandle, wlJoinDisplayName); err != nil {
+		return err
+	}
+	fmt.Printf("\n  %s\n", style.Dim.Render("Next: gt wl browse  — browse the wanted board"))
+	return nil
+}
+
+// joinWasteland forks, clones, and registers this town with upstream,
+// printing progress. Empty handle and displayName fall back to the DoltHub
+// org and the town's name.
+func joinWasteland(townRoot, upstream, handle, displayName string) (*wasteland.Config, error) {
+	// Require DoltHub credentials
+	token := doltserver.DoltHubToken()
+	if token == "" {
+		return nil, fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
+	}
+
+	forkOrg := doltserver.DoltHubOrg()
+	if forkOrg == "" {
+		return nil, fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+	}
+
 	// Load town config for identity (only needed for fresh join)
 	townConfigPath := filepath.Join(townRoot, workspace.PrimaryMarker)
 	townCfg, err := config.LoadTownConfig(townConfigPath)
 	if err != nil {
-		return fmt.Errorf("loading town config: %w", err)
+		return nil, fmt.Errorf("loading town config: %w", err)
 	}
 
 	// Determine town handle
-	handle := wlJoinHandle
 	if handle == "" {
 		handle = forkOrg
 	}
 
-	displayName := wlJoinDisplayName
 	if displayName == "" {
 		if townCfg.PublicName != "" {
 			displayName = townCfg.PublicName
@@ -142,13 +185,50 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Joining wasteland %s (fork to %s/%s)...\n", upstream, forkOrg, upstream[strings.Index(upstream, "/")+1:])
 	cfg, err := svc.Join(upstream, forkOrg, token, handle, displayName, ownerEmail, gtVersion, townRoot)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if wlDB != "" && cfg.Database != wlDB {
+		cfg.Database = wlDB
+		if err := wasteland.SaveConfig(townRoot, cfg); err != nil {
+			return nil, fmt.Errorf("saving wasteland config: %w", err)
+		}
+	}
+
+	wlRun.setWastelandConfig(cfg)
+
+	if err := attachWlCommons(townRoot, cfg); err != nil {
+		return nil, err
 	}
 
 	fmt.Printf("\n%s Joined wasteland: %s\n", style.Bold.Render("✓"), upstream)
 	fmt.Printf("  Handle: %s\n", cfg.RigHandle)
 	fmt.Printf("  Fork: %s/%s\n", cfg.ForkOrg, cfg.ForkDB)
 	fmt.Printf("  Local: %s\n", cfg.LocalDir)
-	fmt.Printf("\n  %s\n", style.Dim.Render("Next: gt wl browse  — browse the wanted board"))
+	return cfg, nil
+}
+
+// attachWlCommons clones cfg's fork into the Dolt server as the wl-commons
+// database unless it is already there.
+func attachWlCommons(townRoot string, cfg *wasteland.Config) error {
+	if doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		return nil
+	}
+	fmt.Printf("  Cloning %s/%s into database %s...\n", cfg.ForkOrg, cfg.ForkDB, doltserver.WLCommonsDB)
+	return doltserver.CloneWLCommons(townRoot, cfg.ForkOrg, cfg.ForkDB)
+}
+
+// ensureWlJoined joins upstream inline when the wl-commons database is
+// missing. It backs --ensure-joined on claim and done; an empty upstream
+// (flag not given) is a no-op so joining never happens implicitly.
+func ensureWlJoined(townRoot, upstream string) error {
+	if upstream == "" || doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		return nil
+	}
+	fmt.Printf("%s Database %q not found; joining %s first (--ensure-joined)\n",
+		style.Bold.Render("→"), doltserver.WLCommonsDB, upstream)
+	if _, err := joinWasteland(townRoot, upstream, "", ""); err != nil {
+		return fmt.Errorf("--ensure-joined: %w", err)
+	}
+	fmt.Println()
 	return nil
 }
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlApproveAllFrom string
+	wlApproveYes     bool
+)
+
+var wlApproveCmd = &cobra.Command{
+	Use:   "approve --all-from <town>",
+	Short: "Approve every in-review completion by a trusted town",
+	Long: `Approve, in one batch, every item this town posted that is in review
+with a completion submitted by <town>.
+
+Each approved completion records this town as validated_by, and its item
+moves from in_review to completed. The batch is a single Dolt commit:
+if any listed item leaves review before the write lands, nothing is
+approved.
+
+The matching items are always listed first; --yes is required to approve
+them. Only items this town posted are considered, and a town cannot
+approve its own completions.
+
+Examples:
+  gt wl approve --all-from partner-rig
+  gt wl approve --all-from partner-rig --yes`,
+	Args: cobra.NoArgs,
+	RunE: runWlApprove,
+}
+
+func init() {
+	wlApproveCmd.Flags().StringVar(&wlApproveAllFrom, "all-from", "", "Approve every in-review completion submitted by this town")
+	wlApproveCmd.Flags().BoolVarP(&wlApproveYes, "yes", "y", false, "Approve the listed items")
+	_ = wlApproveCmd.MarkFlagRequired("all-from")
+
+	wlCmd.AddCommand(wlApproveCmd)
+}
+
+func runWlApprove(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	pending, err := findApprovalsFrom(store, wlCfg.RigHandle, wlApproveAllFrom)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Printf("No in-review completions by %s on items %s posted.\n", wlApproveAllFrom, wlCfg.RigHandle)
+		return nil
+	}
+	renderPendingApprovals(os.Stdout, wlApproveAllFrom, pending)
+	if !wlApproveYes {
+		return fmt.Errorf("approving %d item(s) requires --yes", len(pending))
+	}
+
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+	ids, err := approvePending(store, wlCfg.RigHandle, pending)
+	for _, id := range ids {
+		recordWlAction(townRoot, id, "approve", err)
+	}
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		notifyWatchers(store, townRoot, id, wlCfg.RigHandle, "approved")
+	}
+
+	fmt.Printf("\n%s Approved %d item(s) from %s\n", wlSuccessMark(), len(ids), wlApproveAllFrom)
+	return nil
+}
+
+// pendingApproval is an in-review item and the completion awaiting review.
+type pendingApproval struct {
+	Item       *doltserver.WantedItem
+	Completion doltserver.WantedCompletion
+}
+
+// findApprovalsFrom lists the in-review items posted by reviewer whose
+// current completion was submitted by town, in ListWanted order.
+func findApprovalsFrom(store doltserver.WLCommonsStore, reviewer, town string) ([]pendingApproval, error) {
+	if town == reviewer {
+		return nil, fmt.Errorf("%s cannot approve its own completions", reviewer)
+	}
+	items, err := store.ListWanted(doltserver.WantedFilter{
+		Status:      doltserver.StatusInReview,
+		MinPriority: -1,
+		MaxPriority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing in-review items: %w", err)
+	}
+
+	var pending []pendingApproval
+	for _, item := range items {
+		if item.PostedBy != reviewer {
+			continue
+		}
+		detail, err := store.QueryWantedDetail(item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("querying %s: %w", item.ID, err)
+		}
+		for _, c := range detail.Completions {
+			if c.SupersededBy == "" && c.ValidatedBy == "" && c.CompletedBy == town {
+				pending = append(pending, pendingApproval{Item: item, Completion: c})
+				break
+			}
+		}
+	}
+	return pending, nil
+}
+
+// approvePending checks the status model lets every pending item complete,
+// then approves them in one write, so a single forbidden item stops the
+// batch before anything is written. It returns the IDs the write covered,
+// none when a check failed.
+func approvePending(store doltserver.WLCommonsStore, reviewer string, pending []pendingApproval) ([]string, error) {
+	ids := make([]string, len(pending))
+	for i, p := range pending {
+		if err := requireTransition(store, p.Item.ID, doltserver.StatusInReview, doltserver.StatusCompleted); err != nil {
+			return nil, err
+		}
+		ids[i] = p.Item.ID
+	}
+	return ids, store.ApproveCompletions(ids, reviewer)
+}
+
+func renderPendingApprovals(w io.Writer, town string, pending []pendingApproval) {
+	fmt.Fprintf(w, "%s\n", style.Bold.Render(fmt.Sprintf("In-review completions by %s (%d):", town, len(pending))))
+	for _, p := range pending {
+		fmt.Fprintf(w, "  %s  %s %s\n", p.Item.ID, wlFormatPriority(fmt.Sprint(p.Item.Priority)), p.Item.Title)
+		fmt.Fprintf(w, "    %s\n", style.Dim.Render("evidence: "+p.Completion.Evidence))
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestFindApprovalsFrom_OnlyMatchingItems(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, tc := range []struct {
+		id, postedBy, completer string
+		submit                  bool
+	}{
+		{"w-1", "poster", "trusted", true},
+		{"w-2", "poster", "other", true},
+		{"w-3", "someone-else", "trusted", true},
+		{"w-4", "poster", "trusted", false},
+		{"w-5", "poster", "trusted", true},
+	} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: tc.id, Title: tc.id, PostedBy: tc.postedBy})
+		if err := store.ClaimWanted(tc.id, tc.completer); err != nil {
+			t.Fatalf("ClaimWanted(%s) error: %v", tc.id, err)
+		}
+		if tc.submit {
+			if err := store.SubmitCompletion("c-"+tc.id, tc.id, tc.completer, "https://example.com/"+tc.id); err != nil {
+				t.Fatalf("SubmitCompletion(%s) error: %v", tc.id, err)
+			}
+		}
+	}
+
+	pending, err := findApprovalsFrom(store, "poster", "trusted")
+	if err != nil {
+		t.Fatalf("findApprovalsFrom() error: %v", err)
+	}
+	var ids []string
+	for _, p := range pending {
+		ids = append(ids, p.Item.ID)
+	}
+	if len(ids) != 2 || ids[0] != "w-1" || ids[1] != "w-5" {
+		t.Fatalf("pending = %v, want [w-1 w-5]", ids)
+	}
+	if pending[0].Completion.Evidence != "https://example.com/w-1" {
+		t.Errorf("evidence = %q", pending[0].Completion.Evidence)
+	}
+
+	if err := store.ApproveCompletions(ids, "poster"); err != nil {
+		t.Fatalf("ApproveCompletions() error: %v", err)
+	}
+	for id, want := range map[string]string{"w-1": "completed", "w-2": "in_review", "w-3": "in_review", "w-4": "claimed", "w-5": "completed"} {
+		if item, _ := store.QueryWanted(id); item.Status != want {
+			t.Errorf("%s status = %q, want %q", id, item.Status, want)
+		}
+	}
+
+	if again, _ := findApprovalsFrom(store, "poster", "trusted"); len(again) != 0 {
+		t.Errorf("approved items are still pending: %d", len(again))
+	}
+}
+
+func TestFindApprovalsFrom_RefusesSelf(t *testing.T) {
+	t.Parallel()
+	if _, err := findApprovalsFrom(newFakeWLCommonsStore(), "poster", "poster"); err == nil {
+		t.Error("findApprovalsFrom() should refuse a town approving itself")
+	}
+}
+
+func TestApprovePending_ChecksStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, id := range []string{"w-1", "w-2"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: id, PostedBy: "poster"})
+		_ = store.ClaimWanted(id, "trusted")
+		_ = store.SubmitCompletion("c-"+id, id, "trusted", "https://example.com/"+id)
+	}
+	store.settings[doltserver.SettingWorkflowTransitions] = "open>claimed,claimed>in_review,in_review>claimed"
+
+	pending, err := findApprovalsFrom(store, "poster", "trusted")
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("findApprovalsFrom() = %d items, %v; want 2", len(pending), err)
+	}
+	ids, err := approvePending(store, "poster", pending)
+	if err == nil || !strings.Contains(err.Error(), "in_review") {
+		t.Fatalf("approvePending() error = %v, want the forbidden transition", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("approvePending() wrote %v despite the refusal", ids)
+	}
+	for _, id := range []string{"w-1", "w-2"} {
+		if item, _ := store.QueryWanted(id); item.Status != doltserver.StatusInReview {
+			t.Errorf("%s status = %q, want in_review", id, item.Status)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlArchiveOut string
+	wlRestoreYes bool
+)
+
+var wlArchiveCmd = &cobra.Command{
+	Use:   "archive --out <file>",
+	Short: "Dump the wl-commons database to a portable archive file",
+	Args:  cobra.NoArgs,
+	RunE:  runWlArchive,
+	Long: `Dump every wl-commons table to a JSON archive file.
+
+The archive is independent of DoltHub and of Dolt's storage format. Take one
+before a risky sync or purge and reload it with gt wl restore.
+
+Examples:
+  gt wl archive --out board-backup.json`,
+}
+
+var wlRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Reload the wl-commons database from an archive file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWlRestore,
+	Long: `Reload wl-commons from a file written by gt wl archive.
+
+The file is fully validated before the database is touched. Each table in
+the archive is cleared and reloaded in a single Dolt commit; tables not in
+the archive are left alone. The database is created if it does not exist.
+
+Restore asks for confirmation unless --yes is given. The tables it will
+replace are snapshotted first; gt wl undo-last reloads them.
+
+Examples:
+  gt wl restore board-backup.json
+  gt wl restore board-backup.json --yes`,
+}
+
+func init() {
+	wlArchiveCmd.Flags().StringVar(&wlArchiveOut, "out", "", "Archive file to write (required)")
+	_ = wlArchiveCmd.MarkFlagRequired("out")
+
+	wlRestoreCmd.Flags().BoolVarP(&wlRestoreYes, "yes", "y", false, "Skip confirmation prompt")
+
+	wlCmd.AddCommand(wlArchiveCmd)
+	wlCmd.AddCommand(wlRestoreCmd)
+}
+
+func runWlArchive(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	archive, err := doltserver.ArchiveWLCommons(townRoot)
+	if err != nil {
+		return fmt.Errorf("archiving wl-commons: %w", err)
+	}
+
+	f, err := os.Create(wlArchiveOut)
+	if err != nil {
+		return fmt.Errorf("creating archive file: %w", err)
+	}
+	if err := doltserver.WriteWLArchive(f, archive); err != nil {
+		f.Close()
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	fmt.Printf("%s Archived %d rows to %s\n", style.Bold.Render("✓"), archive.RowCount(), wlArchiveOut)
+	printArchiveSummary(archive)
+	return nil
+}
+
+func runWlRestore(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	archive, err := readArchiveFile(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Archive %s (created %s):\n", path, archive.CreatedAt.Format("2006-01-02 15:04:05 UTC"))
+	printArchiveSummary(archive)
+
+	if !wlRestoreYes {
+		fmt.Println()
+		if !promptYesNo(fmt.Sprintf("Replace these tables in %s?", doltserver.WLCommonsDB)) {
+			fmt.Println("Restore cancelled.")
+			return nil
+		}
+	}
+
+	if wlRun.commonsExists() {
+		tables := make([]string, 0, len(archive.Tables))
+		for t := range archive.Tables {
+			tables = append(tables, t)
+		}
+		if err := snapshotBeforeWrite(townRoot, "restore", true, func() (*doltserver.WLArchive, error) {
+			return doltserver.SnapshotWLCommons(townRoot, tables)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := doltserver.RestoreWLCommons(townRoot, archive); err != nil {
+		return fmt.Errorf("restoring wl-commons: %w", err)
+	}
+
+	fmt.Printf("%s Restored %d rows from %s\n", style.Bold.Render("✓"), archive.RowCount(), path)
+	return nil
+}
+
+// readArchiveFile opens and validates an archive file.
+func readArchiveFile(path string) (*doltserver.WLArchive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	archive, err := doltserver.ReadWLArchive(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive %s: %w", path, err)
+	}
+	return archive, nil
+}
+
+func printArchiveSummary(archive *doltserver.WLArchive) {
+	tables := make([]string, 0, len(archive.Tables))
+	for t := range archive.Tables {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	for _, t := range tables {
+		fmt.Printf("  %-16s %d rows\n", t, len(archive.Tables[t]))
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlAssignClear bool
+
+var wlAssignCmd = &cobra.Command{
+	Use:   "assign <wanted-id> [worker]",
+	Short: "Record which worker in this town is on a claimed item",
+	Long: `Record the worker inside this town who is doing a claimed item, such as
+a polecat or crew member. The claim stays with the town; the assignee only
+says who within it has the work, and is shown by gt wl show and gt wl mine.
+
+The item must be claimed, drafted, or in review, and claimed by this town
+(directly or for a group this town belongs to). Assigning again replaces
+the previous worker; --clear removes the assignee. Releasing, reaping, or
+reopening the claim clears it as well.
+
+Examples:
+  gt wl assign w-abc123 nux
+  gt wl assign w-abc123 --clear`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runWlAssign,
+}
+
+func init() {
+	wlAssignCmd.Flags().BoolVar(&wlAssignClear, "clear", false, "Remove the item's assignee")
+
+	wlCmd.AddCommand(wlAssignCmd)
+}
+
+func runWlAssign(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	var assignee string
+	switch {
+	case wlAssignClear && len(args) == 2:
+		return fmt.Errorf("--clear takes no worker")
+	case !wlAssignClear && len(args) < 2:
+		return fmt.Errorf("a worker is required (or --clear to remove the assignee)")
+	case len(args) == 2:
+		assignee = strings.TrimSpace(args[1])
+		if assignee == "" {
+			return fmt.Errorf("worker must not be empty (use --clear to remove the assignee)")
+		}
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+	rigHandle := wlCfg.RigHandle
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+
+	change := "unassigned"
+	if assignee != "" {
+		change = "assigned to " + assignee
+	}
+	var item *doltserver.WantedItem
+	err = commitThenNotify(store, townRoot, wantedID, rigHandle, "assign", change, func() error {
+		var err error
+		item, err = assignWanted(store, wantedID, rigHandle, assignee)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlItemResultJSON{ID: wantedID, Title: item.Title, Status: item.Status, ClaimedBy: item.ClaimedBy, Assignee: item.Assignee}, wlJSONPrettyOutput())
+	}
+	if item.Assignee == "" {
+		fmt.Printf("%s Cleared the assignee of %s\n", wlSuccessMark(), style.Bold.Render(wantedID))
+	} else {
+		fmt.Printf("%s Assigned %s to %s\n", wlSuccessMark(), style.Bold.Render(wantedID), item.Assignee)
+	}
+	fmt.Printf("  Title: %s\n", item.Title)
+	return nil
+}
+
+// maxAssigneeLen is the width of the wanted.assignee column.
+const maxAssigneeLen = 255
+
+// validateAssignee checks a worker identity is one line that fits the
+// assignee column. Empty clears the assignee.
+func validateAssignee(assignee string) error {
+	if len(assignee) > maxAssigneeLen {
+		return fmt.Errorf("assignee is %d bytes; at most %d are allowed", len(assignee), maxAssigneeLen)
+	}
+	if strings.IndexFunc(assignee, unicode.IsControl) >= 0 {
+		return fmt.Errorf("assignee %q contains control characters", assignee)
+	}
+	return nil
+}
+
+// assignWanted checks that rigHandle holds the claim on wantedID, then sets
+// its assignee (clearing it when assignee is empty). It returns the item as
+// it is after the assignment.
+func assignWanted(store doltserver.WLCommonsStore, wantedID, rigHandle, assignee string) (*doltserver.WantedItem, error) {
+	assignee = strings.TrimSpace(assignee)
+	if err := validateAssignee(assignee); err != nil {
+		return nil, err
+	}
+
+	item, err := store.QueryWanted(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying wanted item: %w", err)
+	}
+	if err := requireAssignable(store, item); err != nil {
+		return nil, err
+	}
+	if err := requireClaimHolder(store, item, rigHandle); err != nil {
+		return nil, err
+	}
+
+	if err := store.AssignWanted(wantedID, rigHandle, assignee); err != nil {
+		return nil, fmt.Errorf("assigning: %w", err)
+	}
+	item.Assignee = assignee
+	return item, nil
+}
+
+// requireAssignable checks the status model treats item as claimed work:
+// a status the model defines that is neither open nor closed. Assigning
+// leaves the status as it is, so there is no transition to check.
+func requireAssignable(store doltserver.WLCommonsStore, item *doltserver.WantedItem) error {
+	model, err := doltserver.QueryStatusModel(store)
+	if err != nil {
+		return err
+	}
+	if !model.Known(item.Status) {
+		return model.CheckTransition(item.ID, item.Status, item.Status)
+	}
+	if item.Status == doltserver.StatusOpen || model.Closed(item.Status) {
+		return fmt.Errorf("%s is %s; only claimed items can be assigned", item.ID, item.Status)
+	}
+	return nil
+}
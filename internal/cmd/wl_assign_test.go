@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestAssignWanted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Flaky fix", PostedBy: "poster"})
+
+	if _, err := assignWanted(store, "w-1", "my-rig", "nux"); err == nil || !strings.Contains(err.Error(), "is open") {
+		t.Errorf("assigning an open item error = %v", err)
+	}
+
+	_ = store.ClaimWanted("w-1", "my-rig")
+	if _, err := assignWanted(store, "w-1", "other-rig", "slit"); err == nil || !strings.Contains(err.Error(), `claimed by "my-rig"`) {
+		t.Errorf("assigning another town's claim error = %v", err)
+	}
+
+	item, err := assignWanted(store, "w-1", "my-rig", "  nux ")
+	if err != nil {
+		t.Fatalf("assignWanted() error: %v", err)
+	}
+	if item.Assignee != "nux" {
+		t.Errorf("returned assignee %q, want nux", item.Assignee)
+	}
+	if got, _ := store.QueryWanted("w-1"); got.Assignee != "nux" {
+		t.Errorf("stored assignee %q, want nux", got.Assignee)
+	}
+
+	if _, err := assignWanted(store, "w-1", "my-rig", ""); err != nil {
+		t.Fatalf("clearing the assignee error: %v", err)
+	}
+	if got, _ := store.QueryWanted("w-1"); got.Assignee != "" {
+		t.Errorf("assignee after clear = %q, want empty", got.Assignee)
+	}
+
+	events, _ := store.QueryWantedHistory("w-1")
+	if len(events) != 2 || events[0].Action != "assign" || events[0].Detail != "nux" || events[1].Detail != "" {
+		t.Errorf("history = %+v, want an assign and a clear", events)
+	}
+}
+
+func TestAssignWanted_ChecksStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Flaky fix", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+
+	store.settings[doltserver.SettingWorkflowClosed] = "claimed,completed"
+	if _, err := assignWanted(store, "w-1", "my-rig", "nux"); err == nil || !strings.Contains(err.Error(), "only claimed items") {
+		t.Errorf("assigning an item the model closes error = %v", err)
+	}
+
+	store.settings[doltserver.SettingWorkflowClosed] = ""
+	store.settings[doltserver.SettingWorkflowStatuses] = "open,in_review,completed"
+	if _, err := assignWanted(store, "w-1", "my-rig", "nux"); err == nil || !strings.Contains(err.Error(), "does not define") {
+		t.Errorf("assigning an item in an undefined status error = %v", err)
+	}
+	if got, _ := store.QueryWanted("w-1"); got.Assignee != "" {
+		t.Errorf("assignee = %q after refusals, want empty", got.Assignee)
+	}
+}
+
+func TestAssignWanted_InvalidAssignee(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Flaky fix", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+
+	for _, assignee := range []string{strings.Repeat("x", maxAssigneeLen+1), "nux\nslit"} {
+		if _, err := assignWanted(store, "w-1", "my-rig", assignee); err == nil {
+			t.Errorf("assignWanted(%q) should fail", assignee)
+		}
+	}
+}
+
+func TestRenderMine_ShowsAssignee(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	renderMine(&b, []*doltserver.WantedItem{
+		{ID: "w-1", Title: "Assigned", Status: doltserver.StatusClaimed, Assignee: "nux"},
+		{ID: "w-2", Title: "Unassigned", Status: doltserver.StatusClaimed},
+	}, "my-rig")
+	out := b.String()
+	if !strings.Contains(out, "ASSIGNEE") || !strings.Contains(out, "nux") {
+		t.Errorf("mine output missing the assignee:\n%s", out)
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// wlBenchTag marks the wanted items gt wl bench posts.
+const wlBenchTag = "bench"
+
+var (
+	wlBenchWorkers    int
+	wlBenchItems      int
+	wlBenchUnderstand bool
+)
+
+var wlBenchCmd = &cobra.Command{
+	Use:    "bench",
+	Short:  "Simulate concurrent claims to measure board contention",
+	Hidden: true,
+	Long: `Post --items throwaway wanted items (tagged bench, IDs bench-<hash>) and
+have --workers simulated towns race to claim them, each through the normal
+claim checks and guarded write. Reports throughput, the share of claim
+writes lost to another worker (the affected-row race detection), and
+claim latency percentiles.
+
+The bench items stay on the board, claimed. A town joined to an upstream
+wasteland, or a Dolt server reached over GT_DOLT_HOST, may be shared, so
+the bench refuses to run there without --i-understand.
+
+Examples:
+  gt wl bench --workers 8 --items 50`,
+	Args: cobra.NoArgs,
+	RunE: runWlBench,
+}
+
+func init() {
+	wlBenchCmd.Flags().IntVar(&wlBenchWorkers, "workers", 8, "Number of simulated towns claiming concurrently")
+	wlBenchCmd.Flags().IntVar(&wlBenchItems, "items", 20, "Number of bench items to post and claim")
+	wlBenchCmd.Flags().BoolVar(&wlBenchUnderstand, "i-understand", false, "Run even though the board may be shared")
+
+	wlCmd.AddCommand(wlBenchCmd)
+}
+
+func runWlBench(cmd *cobra.Command, args []string) error {
+	if wlBenchWorkers < 1 || wlBenchItems < 1 {
+		return fmt.Errorf("--workers and --items must be >= 1")
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	wlCfg, _ := wlRun.wastelandConfig()
+	doltCfg, err := wlRun.doltConfig()
+	if err != nil {
+		return err
+	}
+	if reason := wlBenchSharedReason(wlCfg, doltCfg.Host); reason != "" && !wlBenchUnderstand {
+		return fmt.Errorf("refusing to bench: %s\nBench items are posted and claimed for real; pass --i-understand to run anyway", reason)
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	report, err := runClaimBench(cmd.Context(), store, wlBenchWorkers, wlBenchItems)
+	if err != nil {
+		return err
+	}
+	renderClaimBench(os.Stdout, report)
+	return nil
+}
+
+// wlBenchSharedReason explains why the board may be shared with others, or
+// returns "" for a purely local board.
+func wlBenchSharedReason(wlCfg *wasteland.Config, doltHost string) string {
+	switch {
+	case wlCfg != nil && wlCfg.Upstream != "":
+		return fmt.Sprintf("this town is joined to %s", wlCfg.Upstream)
+	case doltHost != "":
+		return fmt.Sprintf("the Dolt server is remote (GT_DOLT_HOST=%s)", doltHost)
+	}
+	return ""
+}
+
+// claimBenchReport summarizes a bench run.
+type claimBenchReport struct {
+	Workers   int
+	Items     int
+	Elapsed   time.Duration
+	Metrics   claimMetrics
+	Latencies []time.Duration // of every claim attempt, sorted
+}
+
+// runClaimBench posts items bench items and has workers goroutines, each
+// claiming as its own town, work through them in random order until every
+// item is claimed. Every claim goes through claimWanted, so a refused
+// check (already claimed) is not counted and a lost write is a race.
+func runClaimBench(ctx context.Context, store doltserver.WLCommonsStore, workers, items int) (*claimBenchReport, error) {
+	ids := make([]string, items)
+	for i := range ids {
+		title := fmt.Sprintf("wl bench item %d", i+1)
+		item := &doltserver.WantedItem{
+			ID:          doltserver.GenerateWantedID(wlBenchTag, title),
+			Title:       title,
+			Priority:    4,
+			EffortLevel: "trivial",
+			Tags:        []string{wlBenchTag},
+			PostedBy:    "bench-poster",
+		}
+		if err := store.InsertWanted(item); err != nil {
+			return nil, fmt.Errorf("posting bench item: %w", err)
+		}
+		ids[i] = item.ID
+	}
+
+	report := &claimBenchReport{Workers: workers, Items: items}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			town := fmt.Sprintf("bench-worker-%d", worker)
+			order := rand.New(rand.NewSource(int64(worker))).Perm(len(ids)) //nolint:gosec // shuffle, not security
+			metrics := &claimMetrics{}
+			var latencies []time.Duration
+			for _, i := range order {
+				if ctx.Err() != nil {
+					break
+				}
+				before := metrics.Attempted
+				t0 := time.Now()
+				_, _ = claimWanted(ctx, store, ids[i], town, claimOptions{Metrics: metrics})
+				if metrics.Attempted > before {
+					latencies = append(latencies, time.Since(t0))
+				}
+			}
+			mu.Lock()
+			report.Metrics = report.Metrics.add(*metrics)
+			report.Latencies = append(report.Latencies, latencies...)
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	sort.Slice(report.Latencies, func(i, j int) bool { return report.Latencies[i] < report.Latencies[j] })
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("bench interrupted: %w", err)
+	}
+	return report, nil
+}
+
+// latencyPercentile returns the p-th percentile (0-100) of sorted
+// latencies, by the nearest-rank method.
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted)) + 0.999999)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func renderClaimBench(w io.Writer, r *claimBenchReport) {
+	m := r.Metrics
+	fmt.Fprintf(w, "%s %d worker(s), %d item(s), %s\n\n", style.Bold.Render("Claim bench:"), r.Workers, r.Items, r.Elapsed.Round(time.Millisecond))
+	throughput := 0.0
+	if secs := r.Elapsed.Seconds(); secs > 0 {
+		throughput = float64(m.Attempted) / secs
+	}
+	lossRate := 0.0
+	if m.Attempted > 0 {
+		lossRate = 100 * float64(m.LostRace) / float64(m.Attempted)
+	}
+	fmt.Fprintf(w, "  Claim writes:  %d (%.1f/s)\n", m.Attempted, throughput)
+	fmt.Fprintf(w, "  Won:           %d\n", m.Won)
+	fmt.Fprintf(w, "  Lost races:    %d (%.1f%%)\n", m.LostRace, lossRate)
+	if m.Failed > 0 {
+		fmt.Fprintf(w, "  Failed:        %d\n", m.Failed)
+	}
+	fmt.Fprintf(w, "  Latency:       p50 %s  p90 %s  p99 %s\n",
+		latencyPercentile(r.Latencies, 50).Round(time.Microsecond),
+		latencyPercentile(r.Latencies, 90).Round(time.Microsecond),
+		latencyPercentile(r.Latencies, 99).Round(time.Microsecond))
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+func TestRunClaimBench_Smoke(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+
+	report, err := runClaimBench(context.Background(), store, 4, 10)
+	if err != nil {
+		t.Fatalf("runClaimBench() error: %v", err)
+	}
+	if report.Metrics.Won != 10 {
+		t.Errorf("Won = %d, want every item claimed once", report.Metrics.Won)
+	}
+	if m := report.Metrics; m.Attempted != m.Won+m.LostRace+m.Failed || len(report.Latencies) != m.Attempted {
+		t.Errorf("metrics %+v and %d latencies do not add up", m, len(report.Latencies))
+	}
+	items, _ := store.ListWanted(doltserver.WantedFilter{Tags: []string{wlBenchTag}, MinPriority: -1, MaxPriority: -1})
+	for _, item := range items {
+		if item.Status != doltserver.StatusClaimed || !strings.HasPrefix(item.ClaimedBy, "bench-worker-") {
+			t.Errorf("%s: status %q claimed by %q", item.ID, item.Status, item.ClaimedBy)
+		}
+	}
+
+	var buf bytes.Buffer
+	renderClaimBench(&buf, report)
+	for _, want := range []string{"4 worker(s), 10 item(s)", "Won:           10", "p50"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	t.Parallel()
+	var sorted []time.Duration
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{50: 5 * time.Millisecond, 90: 9 * time.Millisecond, 99: 10 * time.Millisecond, 0: time.Millisecond} {
+		if got := latencyPercentile(sorted, p); got != want {
+			t.Errorf("latencyPercentile(%v) = %s, want %s", p, got, want)
+		}
+	}
+	if got := latencyPercentile(nil, 50); got != 0 {
+		t.Errorf("latencyPercentile(nil) = %s, want 0", got)
+	}
+}
+
+func TestWlBenchSharedReason(t *testing.T) {
+	t.Parallel()
+	if r := wlBenchSharedReason(nil, ""); r != "" {
+		t.Errorf("local board reported shared: %q", r)
+	}
+	if r := wlBenchSharedReason(&wasteland.Config{Upstream: "org/wl-commons"}, ""); !strings.Contains(r, "org/wl-commons") {
+		t.Errorf("joined town reason = %q", r)
+	}
+	if r := wlBenchSharedReason(nil, "db.example.com"); !strings.Contains(r, "GT_DOLT_HOST") {
+		t.Errorf("remote host reason = %q", r)
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+var wlBoardURLOpen bool
+
+var wlBoardURLCmd = &cobra.Command{
+	Use:   "board-url [wanted-id]",
+	Short: "Print the DoltHub web URL for the board or a wanted item",
+	Long: `Print the DoltHub web URL for the wanted board.
+
+The org/db comes from the origin remote of the local wl-commons database,
+falling back to the upstream recorded by gt wl join. With a wanted ID, the
+URL opens a DoltHub query pre-filtered to that item.
+
+Examples:
+  gt wl board-url
+  gt wl board-url w-abc123 --open`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlBoardURL,
+}
+
+func init() {
+	wlBoardURLCmd.Flags().BoolVar(&wlBoardURLOpen, "open", false, "Open the URL in the default browser")
+
+	wlCmd.AddCommand(wlBoardURLCmd)
+}
+
+func runWlBoardURL(cmd *cobra.Command, args []string) error {
+	doltCfg, err := wlRun.doltConfig()
+	if err != nil {
+		return err
+	}
+
+	remote := ""
+	dbDir := filepath.Join(doltCfg.DataDir, doltserver.WLCommonsDB)
+	if origin, err := doltserver.HasRemote(dbDir); err == nil {
+		remote = origin
+	}
+	if remote == "" {
+		if cfg, err := wlRun.wastelandConfig(); err == nil {
+			remote = cfg.Upstream
+		}
+	}
+	if remote == "" {
+		return fmt.Errorf("no DoltHub remote configured for %s\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	}
+
+	wantedID := ""
+	if len(args) == 1 {
+		wantedID = args[0]
+	}
+	url, err := boardURL(remote, wantedID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(url)
+	if wlBoardURLOpen {
+		openBrowser(url)
+	}
+	return nil
+}
+
+// boardURL builds the DoltHub web URL for the board, or for a query
+// selecting a single wanted item when wantedID is set.
+func boardURL(remote, wantedID string) (string, error) {
+	org, repo, err := doltserver.ParseDoltHubRepo(remote)
+	if err != nil {
+		return "", err
+	}
+	if wantedID == "" {
+		return doltserver.DoltHubWebURL(org, repo), nil
+	}
+	query := fmt.Sprintf("SELECT * FROM wanted WHERE id = '%s'", doltserver.EscapeSQL(wantedID))
+	return doltserver.DoltHubQueryURL(org, repo, query), nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBoardURL(t *testing.T) {
+	t.Parallel()
+	got, err := boardURL("https://doltremoteapi.dolthub.com/hop/wl-commons", "")
+	if err != nil {
+		t.Fatalf("boardURL() error: %v", err)
+	}
+	if got != "https://www.dolthub.com/repositories/hop/wl-commons" {
+		t.Errorf("boardURL() = %q", got)
+	}
+
+	got, err = boardURL("hop/wl-commons", "w-abc123")
+	if err != nil {
+		t.Fatalf("boardURL() error: %v", err)
+	}
+	if !strings.HasPrefix(got, "https://www.dolthub.com/repositories/hop/wl-commons/query/main?q=") ||
+		!strings.Contains(got, "w-abc123") {
+		t.Errorf("boardURL(item) = %q", got)
+	}
+
+	if _, err := boardURL("not-a-remote", ""); err == nil {
+		t.Error("boardURL() should reject a non-DoltHub remote")
+	}
+}
@@ -1,16 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
@@ -19,7 +20,10 @@ var (
 	wlBrowseType     string
 	wlBrowsePriority int
 	wlBrowseLimit    int
-	wlBrowseJSON     bool
+	wlBrowseFormat   string
+	wlBrowseTags     []string
+	wlBrowseTagAny   bool
+	wlBrowseTagAll   bool
 )
 
 var wlBrowseCmd = &cobra.Command{
@@ -38,24 +42,39 @@ EXAMPLES:
   gt wl browse --type bug               # Only bugs
   gt wl browse --status claimed         # Claimed items
   gt wl browse --priority 0             # Critical priority only
+  gt wl browse --tag go --tag sql       # Tagged go or sql
+  gt wl browse --tag go,sql --tag-all   # Tagged both go and sql
   gt wl browse --limit 5               # Show 5 items
+  gt wl browse --format wide            # Add tags, claimer, and timestamps
   gt wl browse --json                   # JSON output`,
 }
 
 func init() {
 	wlBrowseCmd.Flags().StringVar(&wlBrowseProject, "project", "", "Filter by project (e.g., gastown, beads, hop)")
-	wlBrowseCmd.Flags().StringVar(&wlBrowseStatus, "status", "open", "Filter by status (open, claimed, in_review, completed, withdrawn)")
+	wlBrowseCmd.Flags().StringVar(&wlBrowseStatus, "status", "open", "Filter by status (open, claimed, draft, in_review, completed, withdrawn)")
 	wlBrowseCmd.Flags().StringVar(&wlBrowseType, "type", "", "Filter by type (feature, bug, design, rfc, docs)")
 	wlBrowseCmd.Flags().IntVar(&wlBrowsePriority, "priority", -1, "Filter by priority (0=critical, 2=medium, 4=backlog)")
+	wlBrowseCmd.Flags().StringSliceVar(&wlBrowseTags, "tag", nil, "Filter by tag (repeatable or comma-separated)")
+	wlBrowseCmd.Flags().BoolVar(&wlBrowseTagAny, "tag-any", false, "Match items carrying any --tag (the default)")
+	wlBrowseCmd.Flags().BoolVar(&wlBrowseTagAll, "tag-all", false, "Match only items carrying every --tag")
 	wlBrowseCmd.Flags().IntVar(&wlBrowseLimit, "limit", 50, "Maximum items to display")
-	wlBrowseCmd.Flags().BoolVar(&wlBrowseJSON, "json", false, "Output as JSON")
+	wlBrowseCmd.Flags().StringVar(&wlBrowseFormat, "format", "table", "Table format: table, wide")
 
 	wlCmd.AddCommand(wlBrowseCmd)
 }
 
 func runWLBrowse(cmd *cobra.Command, args []string) error {
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	if _, err := wlRun.townRoot(); err != nil {
+		return err
+	}
+
+	if wlBrowseFormat != "table" && wlBrowseFormat != "wide" {
+		return fmt.Errorf("invalid --format %q: must be table or wide", wlBrowseFormat)
+	}
+	wide := wlBrowseFormat == "wide"
+	tagsMatchAll, err := tagMatchAll(wlBrowseTags, wlBrowseTagAny, wlBrowseTagAll)
+	if err != nil {
+		return err
 	}
 
 	doltPath, err := exec.LookPath("dolt")
@@ -89,17 +108,24 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 		Type:     wlBrowseType,
 		Priority: wlBrowsePriority,
 		Limit:    wlBrowseLimit,
+		Wide:     wide,
+
+		Tags:         wlBrowseTags,
+		TagsMatchAll: tagsMatchAll,
 	})
 
-	if wlBrowseJSON {
+	if wlJSON {
 		sqlCmd := exec.Command(doltPath, "sql", "-q", query, "-r", "json")
 		sqlCmd.Dir = cloneDir
-		sqlCmd.Stdout = os.Stdout
 		sqlCmd.Stderr = os.Stderr
-		return sqlCmd.Run()
+		output, err := sqlCmd.Output()
+		if err != nil {
+			return fmt.Errorf("running query: %w", err)
+		}
+		return writeWLRawJSON(os.Stdout, output, wlJSONPrettyOutput())
 	}
 
-	return renderWLBrowseTable(doltPath, cloneDir, query)
+	return renderWLBrowseTable(doltPath, cloneDir, query, wide)
 }
 
 // BrowseFilter holds filter parameters for building a browse query.
@@ -109,6 +135,14 @@ type BrowseFilter struct {
 	Type     string
 	Priority int
 	Limit    int
+
+	// Wide selects the extra columns shown by --format wide.
+	Wide bool
+
+	// Tags keeps items carrying any of these tags, or all of them when
+	// TagsMatchAll is set.
+	Tags         []string
+	TagsMatchAll bool
 }
 
 func buildBrowseQuery(f BrowseFilter) string {
@@ -126,8 +160,15 @@ func buildBrowseQuery(f BrowseFilter) string {
 	if f.Priority >= 0 {
 		conditions = append(conditions, fmt.Sprintf("priority = %d", f.Priority))
 	}
+	if tc := doltserver.TagCondition(f.Tags, f.TagsMatchAll); tc != "" {
+		conditions = append(conditions, tc)
+	}
 
-	query := "SELECT id, title, project, type, priority, posted_by, status, effort_level FROM wanted"
+	query := "SELECT id, title, project, type, priority, posted_by, status, effort_level"
+	if f.Wide {
+		query += ", tags, claimed_by, created_at, updated_at, COALESCE(completion_count, 0) AS completion_count"
+	}
+	query += " FROM wanted"
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
@@ -137,7 +178,7 @@ func buildBrowseQuery(f BrowseFilter) string {
 	return query
 }
 
-func renderWLBrowseTable(doltPath, cloneDir, query string) error {
+func renderWLBrowseTable(doltPath, cloneDir, query string, wide bool) error {
 	sqlCmd := exec.Command(doltPath, "sql", "-q", query, "-r", "csv")
 	sqlCmd.Dir = cloneDir
 	output, err := sqlCmd.Output()
@@ -154,29 +195,107 @@ func renderWLBrowseTable(doltPath, cloneDir, query string) error {
 		return nil
 	}
 
-	tbl := style.NewTable(
-		style.Column{Name: "ID", Width: 12},
-		style.Column{Name: "TITLE", Width: 40},
-		style.Column{Name: "PROJECT", Width: 12},
-		style.Column{Name: "TYPE", Width: 10},
-		style.Column{Name: "PRI", Width: 4, Align: style.AlignRight},
-		style.Column{Name: "POSTED BY", Width: 16},
-		style.Column{Name: "STATUS", Width: 10},
-		style.Column{Name: "EFFORT", Width: 8},
-	)
+	fmt.Printf("Wanted items (%d):\n\n", len(rows)-1)
+	fmt.Print(buildWLBrowseTable(rows[1:], wide).Render())
+
+	return nil
+}
+
+// buildWLBrowseTable lays out browse rows (header excluded). The default
+// table uses fixed widths; wide mode sizes each column to its content,
+// capped so long titles are truncated rather than wrapping the terminal.
+func buildWLBrowseTable(rows [][]string, wide bool) *style.Table {
+	if !wide {
+		tbl := style.NewTable(
+			style.Column{Name: "ID", Width: 12},
+			style.Column{Name: "TITLE", Width: 40},
+			style.Column{Name: "PROJECT", Width: 12},
+			style.Column{Name: "TYPE", Width: 10},
+			style.Column{Name: "PRI", Width: 4, Align: style.AlignRight},
+			style.Column{Name: "POSTED BY", Width: 16},
+			style.Column{Name: "STATUS", Width: 10},
+			style.Column{Name: "EFFORT", Width: 8},
+		)
+		for _, row := range rows {
+			if len(row) < 8 {
+				continue
+			}
+			pri := wlFormatPriority(row[4])
+			tbl.AddRow(row[0], row[1], row[2], row[3], pri, row[5], row[6], row[7])
+		}
+		return tbl
+	}
 
-	for _, row := range rows[1:] {
-		if len(row) < 8 {
+	columns := []style.Column{
+		{Name: "ID", Width: 24},
+		{Name: "TITLE", Width: 50},
+		{Name: "PROJECT", Width: 16},
+		{Name: "TYPE", Width: 10},
+		{Name: "PRI", Width: 4, Align: style.AlignRight},
+		{Name: "POSTED BY", Width: 20},
+		{Name: "STATUS", Width: 10},
+		{Name: "EFFORT", Width: 8},
+		{Name: "TAGS", Width: 30},
+		{Name: "CLAIMED BY", Width: 20},
+		{Name: "CREATED", Width: 19},
+		{Name: "UPDATED", Width: 19},
+		{Name: "DONE", Width: 12, Align: style.AlignRight},
+	}
+
+	var values [][]string
+	for _, row := range rows {
+		if len(row) < len(columns) {
 			continue
 		}
-		pri := wlFormatPriority(row[4])
-		tbl.AddRow(row[0], row[1], row[2], row[3], pri, row[5], row[6], row[7])
+		vals := append([]string(nil), row[:len(columns)]...)
+		vals[4] = wlFormatPriority(vals[4])
+		vals[8] = wlFormatTags(vals[8])
+		vals[12] = wlFormatCompletionCount(vals[12])
+		values = append(values, vals)
 	}
 
-	fmt.Printf("Wanted items (%d):\n\n", len(rows)-1)
-	fmt.Print(tbl.Render())
+	// Shrink each column to the widest cell (or header), never past its cap.
+	for i := range columns {
+		width := len(columns[i].Name)
+		for _, vals := range values {
+			if n := len(vals[i]); n > width {
+				width = n
+			}
+		}
+		if width < columns[i].Width {
+			columns[i].Width = width
+		}
+	}
 
-	return nil
+	tbl := style.NewTable(columns...)
+	for _, vals := range values {
+		tbl.AddRow(vals...)
+	}
+	return tbl
+}
+
+// wlFormatCompletionCount renders a completion count for the wide browse
+// table, flagging items past the default dispute threshold. Browse reads the
+// upstream clone directly, so per-wasteland thresholds are not consulted.
+func wlFormatCompletionCount(raw string) string {
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return raw
+	}
+	if isDisputed(n, defaultDisputeThreshold) {
+		return raw + " disputed"
+	}
+	return raw
+}
+
+// wlFormatTags renders a JSON tag array (as stored in wanted.tags) as a
+// comma-separated list. Non-JSON values are returned unchanged.
+func wlFormatTags(raw string) string {
+	var tags []string
+	if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+		return raw
+	}
+	return strings.Join(tags, ",")
 }
 
 func wlParseCSV(data string) [][]string {
@@ -193,3 +193,62 @@ func TestBuildBrowseQuery_EscapesSQL(t *testing.T) {
 	}
 }
 
+func TestBuildBrowseQuery_Wide(t *testing.T) {
+	t.Parallel()
+	got := buildBrowseQuery(BrowseFilter{Status: "open", Priority: -1, Limit: 10, Wide: true})
+	want := "SELECT id, title, project, type, priority, posted_by, status, effort_level, tags, claimed_by, created_at, updated_at, COALESCE(completion_count, 0) AS completion_count FROM wanted WHERE status = 'open' ORDER BY priority ASC, created_at DESC LIMIT 10"
+	if got != want {
+		t.Errorf("buildBrowseQuery(wide) =\n  %q\nwant\n  %q", got, want)
+	}
+}
+
+func TestBuildBrowseQuery_Tags(t *testing.T) {
+	t.Parallel()
+	anyQ := buildBrowseQuery(BrowseFilter{Status: "open", Priority: -1, Limit: 10, Tags: []string{"go", "sql"}})
+	if want := `WHERE status = 'open' AND (JSON_CONTAINS(tags, '"go"') OR JSON_CONTAINS(tags, '"sql"')) ORDER BY`; !strings.Contains(anyQ, want) {
+		t.Errorf("buildBrowseQuery(any) = %q, want substring %q", anyQ, want)
+	}
+	allQ := buildBrowseQuery(BrowseFilter{Status: "open", Priority: -1, Limit: 10, Tags: []string{"go", "sql"}, TagsMatchAll: true})
+	if want := `WHERE status = 'open' AND (JSON_CONTAINS(tags, '"go"') AND JSON_CONTAINS(tags, '"sql"')) ORDER BY`; !strings.Contains(allQ, want) {
+		t.Errorf("buildBrowseQuery(all) = %q, want substring %q", allQ, want)
+	}
+}
+
+func TestWlFormatTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`["go","auth"]`, "go,auth"},
+		{`[]`, ""},
+		{"", ""},
+		{"not-json", "not-json"},
+	}
+	for _, tt := range tests {
+		if got := wlFormatTags(tt.input); got != tt.want {
+			t.Errorf("wlFormatTags(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBuildWLBrowseTable_Wide(t *testing.T) {
+	t.Parallel()
+	longTitle := strings.Repeat("x", 80)
+	rows := [][]string{
+		{"w-abc", longTitle, "gastown", "bug", "1", "poster", "claimed", "small", `["go","auth"]`, "worker", "2026-01-01 00:00:00", "2026-01-02 00:00:00", "3"},
+	}
+	out := buildWLBrowseTable(rows, true).Render()
+
+	for _, want := range []string{"TAGS", "CLAIMED BY", "go,auth", "worker", "2026-01-02 00:00:00", "P1", "DONE", "3 disputed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("wide table missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, longTitle) {
+		t.Error("wide table should truncate long titles")
+	}
+	if !strings.Contains(out, "...") {
+		t.Error("truncated title should end with an ellipsis")
+	}
+}
@@ -1,83 +1,1083 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/wasteland"
-	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Wasteland settings (_meta keys) consulted by claim.
+const (
+	// wlSettingRequireDepsClosed makes strict dependency mode the default
+	// for every rig on a wasteland.
+	wlSettingRequireDepsClosed = "claim.require_deps_closed"
+
+	// wlSettingCoordinators is a comma-separated list of rig handles allowed
+	// to claim on behalf of other rigs.
+	wlSettingCoordinators = "roles.coordinators"
+
+	// wlSettingConfirmPriority is the priority at or above which (numerically
+	// at or below) a claim needs confirmation, e.g. 0 guards only P0 items.
+	wlSettingConfirmPriority = "claim.confirm_priority"
+
+	// wlSettingAutoPull turns --auto-pull on by default.
+	wlSettingAutoPull = "claim.auto_pull"
+
+	// wlSettingMaxOpenClaims caps how many items one rig may hold
+	// (claimed or in draft) at once; 1 allows a single open claim.
+	wlSettingMaxOpenClaims = "claim.max_open_claims"
+)
+
+// errClaimCancelled is returned when the user declines a claim confirmation.
+var errClaimCancelled = errors.New("claim cancelled")
+
+// errNoClaimableWork is returned by auto-claim when every candidate it tried
+// was lost to another rig or refused.
+var errNoClaimableWork = errors.New("no claimable work")
+
+var (
+	wlClaimRequireDepsClosed bool
+	wlClaimOnBehalfOf        string
+	wlClaimMinPriority       int
+	wlClaimMaxPriority       int
+	wlClaimPreferOwnPosts    bool
+	wlClaimFromFeed          bool
+	wlClaimMaxAttempts       int
+	wlClaimTags              []string
+	wlClaimTagAny            bool
+	wlClaimTagAll            bool
+	wlClaimHoldFile          string
+	wlClaimOutputIDFile      string
+	wlClaimMetricsFile       string
+	wlClaimTownFile          string
+	wlClaimEchoPre           bool
+	wlClaimOnConflict        string
+	wlClaimConflictRetries   int
+	wlClaimWaitTimeout       time.Duration
+	wlClaimAutoPull          bool
+	wlClaimGroup             string
+	wlClaimNote              string
+	wlClaimEdit              bool
+	wlClaimDryRun            bool
+	wlClaimExplain           bool
+	wlClaimDryRunExplain     bool
+	wlClaimEnsureJoined      string
+	wlClaimOutputTemplate    string
+	wlClaimConfirm           bool
+	wlClaimTitle             string
+	wlClaimLabels            []string
+	wlClaimLock              bool
+	wlClaimLockTTL           time.Duration
 )
 
 var wlClaimCmd = &cobra.Command{
-	Use:   "claim <wanted-id>",
+	Use:   "claim [wanted-id...]",
 	Short: "Claim a wanted item",
 	Long: `Claim a wanted item on the shared wanted board.
 
 Updates the wanted row: claimed_by=<your rig handle>, status='claimed'.
 The item must exist and have status='open'.
 
+Claiming an item your rig already holds succeeds without writing
+anything and reports "already claimed by you", so a claim is safe to
+retry after a crash. Only a claim held by another town is an error.
+
+Several wanted IDs are claimed one after another, each as its own write
+with its own race check. An item that cannot be claimed is reported and
+skipped, a summary follows, and the command fails only if nothing was
+claimed. With --json each claim is written as its own JSON object and
+failures go to stderr. --hold-file, --output-id-file, --output-template,
+and the previews take a single ID.
+
+Without a wanted ID, claim picks the highest-priority open item (lowest
+priority number, oldest first). --min-priority and --max-priority restrict
+auto-claim to an inclusive priority band, e.g. --min-priority 1 never
+auto-claims P0 items. --prefer-own-posts picks items this rig posted ahead
+of others of the same priority, for work a town means to do itself if
+nobody else does; it never outranks a more urgent item. The board has no
+reward column, so reward plays no part in the ordering. Candidates lost to
+another rig are skipped; --max-attempts N stops after N candidates with
+"no claimable work", so worker loops terminate predictably.
+
+--from-feed picks by recency instead: the most recently posted open item,
+for towns that watch the feed and pounce. It combines with --tag and the
+priority band, and prints how long ago the chosen item was posted.
+
+If the item depends on other wanted items that are not yet completed, the
+claim proceeds with a warning listing the outstanding blockers. With
+--require-open-deps-closed (or the wasteland setting
+claim.require_deps_closed=true), outstanding blockers are a hard error.
+
+Larger efforts can be claimed for a group with --group <name> (see gt wl
+group). The claiming rig must be a member; it is recorded as claimed_by,
+and every member of the group may then run gt wl done on the item.
+
+Instead of an ID, --title names the item by its exact title. It must match
+exactly one open item; an ambiguous title lists the candidate IDs.
+
+Coordinators (rigs listed in the wasteland setting roles.coordinators) can
+claim for a partner rig with --on-behalf-of. The partner becomes claimed_by;
+the coordinator is recorded in claimed_via and in the item's history.
+
+--label key=value stamps metadata on the item (ticket number, sprint, cost
+center); repeat it for several labels. Keys are lowercase (letters, digits,
+'.', '-', '_'), setting a key again replaces its value, and the gt. and
+delivery namespaces are reserved. Labels appear in gt wl show.
+
+A claim note (--note, or --edit to write it in $EDITOR) is recorded as a
+comment on the item. With --edit, an empty editor buffer aborts the claim.
+
+To diagnose a claim that "should work but doesn't", --dry-run-explain
+prints the item's current state, each precondition check, whether the claim
+would succeed, and the exact SQL it would run — without writing anything.
+--dry-run prints only the SQL and --explain only the checks. All three exit
+non-zero when the claim would be refused, and require a wanted ID.
+
+--on-conflict picks what happens when another rig wins the item. A claim
+is written as an UPDATE guarded by status='open'; when it changes no row
+(Dolt then has nothing to commit), the item was taken between the checks
+and the write, and that is the conflict. Other write failures (network
+errors, timeouts, a write queued for gt wl sync) are never conflicts and
+fail at once. fail (the default) reports a conflict.
+retry re-reads the item and runs the checks and the write again, up to
+--conflict-retries more times, stopping as soon as a check refuses. wait
+also applies when the item is already held: it re-reads the item every
+few seconds until it is open again and claims it, giving up after
+--wait-timeout. Items that are completed or withdrawn are never waited on.
+retry and wait need a wanted ID; auto-claim already skips lost races.
+
+--echo-preconditions claims as usual but first prints each precondition it
+verified (the item exists, it is open, the policy checks) as a checklist.
+The list stops at the first check that fails, naming why. When
+auto-claiming, each candidate tried gets its own checklist.
+
+--output-template replaces the success output with a Go text/template
+executed against the claim result. Fields: .ID, .Title, .ClaimedBy,
+.ClaimedVia (coordinator, when claiming on behalf), .Status, and .Blockers
+(outstanding dependency IDs). The template is checked before anything is
+written, so a typo never leaves a claim without its output. --json writes
+the same fields as one JSON object; --pretty/--compact pick the layout
+(pretty on a terminal, compact otherwise).
+
+Wastelands can guard their most important work with the setting
+claim.confirm_priority=N: claiming an item of priority N or more urgent
+(P0..PN) prints the item and asks for confirmation before writing. Pass
+--confirm (or --yes) to skip the prompt; when stdin is not a terminal or
+--output-template or --json is set, there is no prompt and --confirm is required.
+
+The setting claim.max_open_claims=N caps how many items one rig may hold
+(claimed or in draft) at once; a claim past the cap is refused until the
+rig finishes or releases something.
+
+Looping workers can be rate-limited with --throttle N (board writes per
+minute, also read from the wasteland setting client.writes_per_minute).
+The budget is a token bucket shared by every gt wl claim and gt wl done in
+the town; when it is spent the command waits for the next token instead of
+failing.
+
+On synced boards, --auto-pull first pulls upstream into the local
+wl-commons clone so the claim decides on current data instead of losing a
+race it could have seen coming. The wasteland setting claim.auto_pull=true
+turns it on by default. A pull that stops on merge conflicts aborts the
+claim with instructions for resolving them.
+
+--lock takes an advisory lock on the item before checking and claiming
+it, and releases it afterwards. The lock is a row in the wl_locks table
+(created by the first lock), so a second town locking the same item fails
+with "item is locked by <town> until <time>" instead of racing. Locks
+expire after --lock-ttl (10m), so a town that crashes mid-claim never
+blocks the item for good. The status='open' guard on the claim write only
+protects towns sharing one database; towns writing to separate clones
+both succeed and collide only when the clones merge. A lock narrows that
+window for towns that pull before claiming (--auto-pull) and push soon
+after, at the price of two extra commits per claim. Without --lock, a
+claim is a single guarded write. A lock held by another town counts as a
+conflict for --on-conflict retry and wait.
+
+--tag narrows auto-claim to items carrying a skill tag; repeat it (or
+comma-separate) for several. By default an item matches if it has any of
+the tags (--tag-any); --tag-all requires every one.
+
+--hold-file <path> writes a JSON marker ({"id", "town", "claimed_at"}) once
+the claim has committed, for pipelines that hand a claim between stages.
+gt wl done --hold-file <path> removes it after a successful submission.
+
+--output-id-file <path> writes just the claimed wanted ID (and a newline)
+to path once the claim commits, for scripts that should not parse stdout.
+The file is replaced atomically; a failed or refused claim leaves any
+previous file untouched.
+
+--metrics-file <path> keeps Prometheus counters for a node_exporter
+textfile collector: gt_wl_claims_attempted_total, gt_wl_claims_won_total,
+gt_wl_claims_lost_race_total, and gt_wl_claims_failed_total. A claim write
+is attempted once the item passes every check, lost to a race when another
+rig changes the item first, and failed when the write itself fails (a
+network error or timeout). Each run adds its counts to those already in the file and rewrites
+it atomically, so a worker loop's counters accumulate.
+
+New to a wasteland? --ensure-joined <org/db> runs gt wl join inline when
+the wl-commons database is missing, then claims. Joining never happens
+without the flag.
+
+Outside a Gas Town workspace (a container or CI job), --town-file <path>
+or $GASTOWN_TOWN supplies the town handle instead; the working directory's
+.dolt-data must then hold the wl-commons database.
+
 In wild-west mode (Phase 1), this writes directly to the local wl-commons
 database. In PR mode, this will create a DoltHub PR instead.
 
 Examples:
-  gt wl claim w-abc123`,
-	Args: cobra.ExactArgs(1),
+  gt wl claim w-abc123
+  gt wl claim w-abc123 w-def456 w-0a1b2c
+  gt wl claim --title "Fix the login bug"
+  gt wl claim --min-priority 1 --max-priority 2
+  gt wl claim --prefer-own-posts
+  gt wl claim --from-feed --tag go
+  gt wl claim --tag go --tag sql --tag-all
+  gt wl claim --max-attempts 3
+  gt wl claim --auto-pull
+  gt wl claim w-abc123 --lock --auto-pull
+  gt wl claim --hold-file .claim.json
+  gt wl claim --output-id-file .claimed-id
+  gt wl claim --metrics-file /var/lib/node_exporter/textfile/gt_wl_claim.prom
+  gt wl claim w-abc123 --edit
+  gt wl claim w-abc123 --label ticket=OPS-142 --label sprint=2026-10
+  gt wl claim w-abc123 --require-open-deps-closed
+  gt wl claim w-abc123 --on-behalf-of partner-rig
+  gt wl claim w-abc123 --group auth-squad
+  gt wl claim w-abc123 --dry-run-explain
+  gt wl claim w-abc123 --confirm
+  gt wl claim --output-template '{{.ID}}\t{{.Title}}'
+  gt wl claim w-abc123 --json --compact
+  gt wl claim w-abc123 --ensure-joined steveyegge/wl-commons`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runWlClaim,
 }
 
 func init() {
+	wlClaimCmd.Flags().BoolVar(&wlClaimRequireDepsClosed, "require-open-deps-closed", false, "Refuse to claim while any dependency is not completed")
+	wlClaimCmd.Flags().StringVar(&wlClaimTitle, "title", "", "Claim the open item with this exact title instead of an ID")
+	wlClaimCmd.Flags().StringVar(&wlClaimOnBehalfOf, "on-behalf-of", "", "Claim for another rig (coordinators only)")
+	wlClaimCmd.Flags().StringVar(&wlClaimGroup, "group", "", "Claim for a group you belong to; any member can then run wl done")
+	wlClaimCmd.Flags().IntVar(&wlClaimMinPriority, "min-priority", -1, "Auto-claim only items with priority >= N")
+	wlClaimCmd.Flags().IntVar(&wlClaimMaxPriority, "max-priority", -1, "Auto-claim only items with priority <= N")
+	wlClaimCmd.Flags().StringSliceVar(&wlClaimTags, "tag", nil, "Auto-claim only items with this tag (repeatable or comma-separated)")
+	wlClaimCmd.Flags().BoolVar(&wlClaimTagAny, "tag-any", false, "Match items carrying any --tag (the default)")
+	wlClaimCmd.Flags().BoolVar(&wlClaimTagAll, "tag-all", false, "Match only items carrying every --tag")
+	wlClaimCmd.Flags().BoolVar(&wlClaimPreferOwnPosts, "prefer-own-posts", false, "Auto-claim items this rig posted first within a priority")
+	wlClaimCmd.Flags().BoolVar(&wlClaimFromFeed, "from-feed", false, "Auto-claim the most recently posted open item")
+	wlClaimCmd.MarkFlagsMutuallyExclusive("from-feed", "prefer-own-posts")
+	wlClaimCmd.Flags().BoolVar(&wlClaimAutoPull, "auto-pull", false, "Pull upstream into the local wl-commons clone before checking the claim")
+	wlClaimCmd.Flags().BoolVar(&wlClaimLock, "lock", false, "Hold an advisory lock on the item while claiming it")
+	wlClaimCmd.Flags().DurationVar(&wlClaimLockTTL, "lock-ttl", doltserver.DefaultClaimLockTTL, "With --lock, how long the lock lasts if never released")
+	wlClaimCmd.Flags().StringVar(&wlClaimHoldFile, "hold-file", "", "Write a JSON marker for the claimed item to this path once the claim commits")
+	wlClaimCmd.Flags().StringVar(&wlClaimOutputIDFile, "output-id-file", "", "Write just the claimed wanted ID to this file once the claim commits")
+	wlClaimCmd.Flags().StringVar(&wlClaimMetricsFile, "metrics-file", "", "Accumulate Prometheus claim counters in this textfile")
+	wlClaimCmd.Flags().StringVar(&wlClaimTownFile, "town-file", "", "Outside a workspace, read the town handle from this file (else $GASTOWN_TOWN)")
+	wlClaimCmd.Flags().StringVar(&wlClaimOnConflict, "on-conflict", string(claimConflictFail), "When another rig claims first: fail, retry, or wait for the item to reopen")
+	wlClaimCmd.Flags().IntVar(&wlClaimConflictRetries, "conflict-retries", 3, "With --on-conflict retry, how many more times to try")
+	wlClaimCmd.Flags().DurationVar(&wlClaimWaitTimeout, "wait-timeout", 10*time.Minute, "With --on-conflict wait, how long to wait for the item to reopen")
+	wlClaimCmd.Flags().IntVar(&wlClaimMaxAttempts, "max-attempts", 0, "Auto-claim tries at most N candidates before giving up (0 = all)")
+	wlClaimCmd.Flags().StringArrayVar(&wlClaimLabels, "label", nil, "Label the item key=value (repeatable)")
+	wlClaimCmd.Flags().StringVar(&wlClaimNote, "note", "", "Claim note recorded as a comment")
+	wlClaimCmd.Flags().BoolVar(&wlClaimEdit, "edit", false, "Write the claim note in $EDITOR (--note takes precedence)")
+	wlClaimCmd.Flags().BoolVar(&wlClaimDryRun, "dry-run", false, "Print the SQL the claim would run, without writing")
+	wlClaimCmd.Flags().BoolVar(&wlClaimExplain, "explain", false, "Explain whether the claim would succeed, without writing")
+	wlClaimCmd.Flags().BoolVar(&wlClaimDryRunExplain, "dry-run-explain", false, "Show item state, checks, and SQL, without writing")
+	wlClaimCmd.MarkFlagsMutuallyExclusive("dry-run", "explain", "dry-run-explain")
+	wlClaimCmd.Flags().BoolVar(&wlClaimEchoPre, "echo-preconditions", false, "Print each precondition check as it passes or fails before claiming")
+	wlClaimCmd.Flags().StringVar(&wlClaimOutputTemplate, "output-template", "", "Go text/template for the success output (fields: .ID .Title .ClaimedBy .ClaimedVia .Status .Blockers)")
+	wlClaimCmd.Flags().BoolVar(&wlClaimConfirm, "confirm", false, "Claim high-priority items without prompting (see claim.confirm_priority)")
+	wlClaimCmd.Flags().BoolVar(&wlClaimConfirm, "yes", false, "Alias for --confirm")
+	wlClaimCmd.Flags().StringVar(&wlClaimEnsureJoined, "ensure-joined", "", "Join this wasteland (org/db) first if not yet joined")
+
 	wlCmd.AddCommand(wlClaimCmd)
 }
 
 func runWlClaim(cmd *cobra.Command, args []string) error {
-	wantedID := args[0]
+	if len(args) > 0 && wlClaimTitle != "" {
+		return fmt.Errorf("pass either a wanted ID or --title, not both")
+	}
+	if len(args) > 1 && (wlClaimHoldFile != "" || wlClaimOutputIDFile != "" || wlClaimOutputTemplate != "") {
+		return fmt.Errorf("--hold-file, --output-id-file, and --output-template take a single wanted ID")
+	}
+	band := priorityBand{Min: wlClaimMinPriority, Max: wlClaimMaxPriority}
+	if (len(args) > 0 || wlClaimTitle != "") && band.bounded() {
+		return fmt.Errorf("--min-priority/--max-priority only apply when auto-claiming (no wanted ID)")
+	}
+	if wlClaimGroup != "" && wlClaimOnBehalfOf != "" {
+		return fmt.Errorf("--group and --on-behalf-of cannot be combined")
+	}
+	if wlClaimGroup != "" {
+		if err := doltserver.ValidateGroupName(wlClaimGroup); err != nil {
+			return err
+		}
+	}
+	if (len(args) > 0 || wlClaimTitle != "") && wlClaimPreferOwnPosts {
+		return fmt.Errorf("--prefer-own-posts only applies when auto-claiming (no wanted ID)")
+	}
+	if (len(args) > 0 || wlClaimTitle != "") && wlClaimFromFeed {
+		return fmt.Errorf("--from-feed only applies when auto-claiming (no wanted ID)")
+	}
+	if wlClaimMaxAttempts < 0 {
+		return fmt.Errorf("--max-attempts must be >= 0, got %d", wlClaimMaxAttempts)
+	}
+	if (len(args) > 0 || wlClaimTitle != "") && wlClaimMaxAttempts > 0 {
+		return fmt.Errorf("--max-attempts only applies when auto-claiming (no wanted ID)")
+	}
+	if err := band.validate(); err != nil {
+		return err
+	}
+	tagsMatchAll, err := tagMatchAll(wlClaimTags, wlClaimTagAny, wlClaimTagAll)
+	if err != nil {
+		return err
+	}
+	conflictMode, err := parseClaimConflictMode(wlClaimOnConflict)
+	if err != nil {
+		return err
+	}
+	if conflictMode != claimConflictFail && len(args) == 0 && wlClaimTitle == "" {
+		return fmt.Errorf("--on-conflict %s requires a wanted ID or --title; auto-claim already moves on to the next item", conflictMode)
+	}
+	if wlClaimConflictRetries < 0 {
+		return fmt.Errorf("--conflict-retries must be >= 0, got %d", wlClaimConflictRetries)
+	}
+	if wlClaimWaitTimeout <= 0 {
+		return fmt.Errorf("--wait-timeout must be positive, got %s", wlClaimWaitTimeout)
+	}
+	if wlClaimLockTTL < time.Second {
+		return fmt.Errorf("--lock-ttl must be at least 1s, got %s", wlClaimLockTTL)
+	}
+	if (len(args) > 0 || wlClaimTitle != "") && len(wlClaimTags) > 0 {
+		return fmt.Errorf("--tag only applies when auto-claiming (no wanted ID)")
+	}
+	preview := claimPreviewNone
+	switch {
+	case wlClaimDryRun:
+		preview = claimPreviewSQL
+	case wlClaimExplain:
+		preview = claimPreviewReasons
+	case wlClaimDryRunExplain:
+		preview = claimPreviewFull
+	}
+	if preview != claimPreviewNone && len(args) == 0 && wlClaimTitle == "" {
+		return fmt.Errorf("--dry-run, --explain, and --dry-run-explain require a wanted ID or --title")
+	}
+	if preview != claimPreviewNone && len(args) > 1 {
+		return fmt.Errorf("--dry-run, --explain, and --dry-run-explain take a single wanted ID")
+	}
+	if wlClaimEnsureJoined != "" {
+		if _, _, err := wasteland.ParseUpstream(wlClaimEnsureJoined); err != nil {
+			return err
+		}
+	}
+	labels, err := parseClaimLabels(wlClaimLabels)
+	if err != nil {
+		return err
+	}
+	if wlClaimEchoPre && (wlJSON || wlClaimOutputTemplate != "" || preview != claimPreviewNone) {
+		return fmt.Errorf("--echo-preconditions cannot be combined with --json, --output-template, or a preview (previews already list the checks)")
+	}
+	if wlJSON && (wlClaimOutputTemplate != "" || preview != claimPreviewNone) {
+		return fmt.Errorf("--json cannot be combined with --output-template, --dry-run, --explain, or --dry-run-explain")
+	}
+	var outTmpl *template.Template
+	if wlClaimOutputTemplate != "" {
+		if preview != claimPreviewNone {
+			return fmt.Errorf("--output-template cannot be combined with --dry-run, --explain, or --dry-run-explain")
+		}
+		if outTmpl, err = parseClaimOutputTemplate(wlClaimOutputTemplate); err != nil {
+			return err
+		}
+	}
 
-	townRoot, err := workspace.FindFromCwdOrError()
+	wlRun.allowTownHandle(wlClaimTownFile)
+	townRoot, err := wlRun.townRoot()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		return err
 	}
 
-	wlCfg, err := wasteland.LoadConfig(townRoot)
+	if err := ensureWlJoined(townRoot, wlClaimEnsureJoined); err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
 	if err != nil {
-		return fmt.Errorf("loading wasteland config: %w", err)
+		return err
 	}
 	rigHandle := wlCfg.RigHandle
 
-	if !doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
-		return fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	if err := wlRun.requireCommons(); err != nil {
+		return err
 	}
 
 	store := doltserver.NewWLCommons(townRoot)
-	item, err := claimWanted(store, wantedID, rigHandle)
+	opts := claimOptions{
+		RequireDepsClosed: wlClaimRequireDepsClosed,
+		OnBehalfOf:        wlClaimOnBehalfOf,
+		PreferOwnPosts:    wlClaimPreferOwnPosts,
+		NewestFirst:       wlClaimFromFeed,
+		MaxAttempts:       wlClaimMaxAttempts,
+		Tags:              wlClaimTags,
+		TagsMatchAll:      tagsMatchAll,
+		Group:             wlClaimGroup,
+		Labels:            labels,
+	}
+	if wlClaimLock {
+		opts.LockTTL = wlClaimLockTTL
+	}
+	if wlClaimMetricsFile != "" {
+		opts.Metrics = &claimMetrics{}
+	}
+	if wlClaimEchoPre {
+		opts.Echo = os.Stdout
+	}
+	if opts.Refresh, err = claimAutoPull(store, wlClaimAutoPull, wlCommonsCloneDir(townRoot, wlCfg)); err != nil {
+		return err
+	}
+
+	// Title lookup and previews query the board too, so they pull first.
+	if wlClaimTitle != "" || preview != claimPreviewNone {
+		if err := opts.refresh(); err != nil {
+			return err
+		}
+	}
+	if wlClaimTitle != "" {
+		id, err := resolveWantedByTitle(store, wlClaimTitle)
+		if err != nil {
+			return err
+		}
+		args = []string{id}
+	}
+
+	if preview != claimPreviewNone {
+		// Never open the editor for a preview; only report that a note
+		// would be recorded.
+		plan, err := planClaim(cmd.Context(), store, args[0], rigHandle, opts, wlClaimNote != "" || wlClaimEdit)
+		if err != nil {
+			return err
+		}
+		return renderClaimPlan(os.Stdout, plan, preview)
+	}
+
+	hint := "the auto-claimed item"
+	switch {
+	case len(args) == 1:
+		hint = args[0]
+	case len(args) > 1:
+		hint = "each claimed item"
+	}
+	note, err := resolveNote(wlClaimNote, wlClaimEdit, "claim note", hint)
+	if err != nil {
+		return err
+	}
+
+	opts.Note = note
+	opts.Confirmed = wlClaimConfirm
+	if outTmpl == nil && !wlJSON && isStdinTerminal() {
+		opts.Confirm = confirmClaim
+	}
+	policy := claimConflictPolicy{
+		Mode:        conflictMode,
+		Retries:     wlClaimConflictRetries,
+		WaitTimeout: wlClaimWaitTimeout,
+	}
+	if len(args) > 1 {
+		out := claimBatchOutput{Out: os.Stdout, Err: os.Stderr, JSON: wlJSON}
+		_, err := claimWantedBatch(cmd.Context(), out, store, townRoot, args, rigHandle, opts, policy)
+		if wlClaimMetricsFile != "" {
+			if merr := writeClaimMetrics(wlClaimMetricsFile, rigHandle, *opts.Metrics); merr != nil {
+				style.PrintWarning("%v", merr)
+			}
+		}
+		return err
+	}
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+	var res *claimResult
+	if len(args) == 1 {
+		res, err = claimWantedOnConflict(cmd.Context(), store, args[0], rigHandle, opts, policy)
+	} else {
+		res, err = autoClaimWanted(cmd.Context(), store, rigHandle, band, opts)
+	}
+	logID := ""
+	switch {
+	case res != nil:
+		logID = res.Item.ID
+	case len(args) == 1:
+		logID = args[0]
+	}
+	recordWlAction(townRoot, logID, "claim", err)
+	if wlClaimMetricsFile != "" {
+		if merr := writeClaimMetrics(wlClaimMetricsFile, rigHandle, *opts.Metrics); merr != nil {
+			style.PrintWarning("%v", merr)
+		}
+	}
 	if err != nil {
 		return err
 	}
+	wantedID := res.Item.ID
+
+	if !res.AlreadyClaimed {
+		change := "claimed"
+		if res.ClaimedBy != rigHandle {
+			change = "claimed for " + res.ClaimedBy
+		}
+		notifyWatchers(store, townRoot, wantedID, rigHandle, change)
+	}
+
+	// The claim only succeeds when the write changed the row or the rig
+	// already held the claim, so the marker never describes a claim that
+	// did not land.
+	if wlClaimHoldFile != "" {
+		if err := writeWlHoldFile(wlClaimHoldFile, wantedID, rigHandle, time.Now()); err != nil {
+			return fmt.Errorf("%s was claimed, but --hold-file failed: %w", wantedID, err)
+		}
+	}
+	if wlClaimOutputIDFile != "" {
+		if err := writeWlOutputIDFile(wlClaimOutputIDFile, wantedID); err != nil {
+			return fmt.Errorf("%s was claimed, but --output-id-file failed: %w", wantedID, err)
+		}
+	}
+
+	if outTmpl != nil {
+		return renderClaimOutputTemplate(os.Stdout, outTmpl, res, rigHandle)
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, newClaimTemplateData(res, rigHandle), wlJSONPrettyOutput())
+	}
 
-	fmt.Printf("%s Claimed %s\n", style.Bold.Render("✓"), wantedID)
-	fmt.Printf("  Claimed by: %s\n", rigHandle)
-	fmt.Printf("  Title: %s\n", item.Title)
+	if res.AlreadyClaimed {
+		fmt.Printf("%s %s is already claimed by you\n", wlSuccessMark(), wantedID)
+		fmt.Printf("  Title: %s\n", res.Item.Title)
+		return nil
+	}
+	fmt.Printf("%s Claimed %s\n", wlSuccessMark(), wantedID)
+	if res.ClaimedBy != rigHandle {
+		fmt.Printf("  Claimed by: %s (via %s)\n", res.ClaimedBy, rigHandle)
+	} else {
+		fmt.Printf("  Claimed by: %s\n", res.ClaimedBy)
+	}
+	if res.Group != "" {
+		fmt.Printf("  Group: %s (any member can run gt wl done)\n", res.Group)
+	}
+	fmt.Printf("  Title: %s\n", res.Item.Title)
+	if wlClaimFromFeed && !res.Item.CreatedAt.IsZero() {
+		fmt.Printf("  Posted: %s ago (newest open item)\n", formatDuration(time.Since(res.Item.CreatedAt)))
+	}
+	if len(res.Blockers) > 0 {
+		style.PrintWarning("%s has outstanding dependencies: %s", wantedID, formatBlockers(res.Blockers))
+	}
 
 	return nil
 }
 
+// claimTemplateData is the value --output-template is executed against,
+// and the object written by --json.
+type claimTemplateData struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	ClaimedBy  string   `json:"claimed_by"`
+	ClaimedVia string   `json:"claimed_via"`
+	Status     string   `json:"status"`
+	Blockers   []string `json:"blockers"`
+
+	// AlreadyClaimed is true when the rig already held the claim and
+	// nothing was written.
+	AlreadyClaimed bool `json:"already_claimed,omitempty"`
+}
+
+// newClaimTemplateData describes a successful claim made by rigHandle.
+// Blockers is non-nil so it encodes as [] rather than null.
+func newClaimTemplateData(res *claimResult, rigHandle string) claimTemplateData {
+	data := claimTemplateData{
+		ID:        res.Item.ID,
+		Title:     res.Item.Title,
+		ClaimedBy: res.ClaimedBy,
+		Status:    "claimed",
+		Blockers:  []string{},
+
+		AlreadyClaimed: res.AlreadyClaimed,
+	}
+	if res.ClaimedBy != rigHandle {
+		data.ClaimedVia = rigHandle
+	}
+	for _, b := range res.Blockers {
+		data.Blockers = append(data.Blockers, b.ID)
+	}
+	return data
+}
+
+// parseClaimOutputTemplate parses and dry-runs an --output-template value,
+// so syntax errors and unknown fields are reported before the claim.
+func parseClaimOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output-template").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, claimTemplateData{}); err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderClaimOutputTemplate writes res through tmpl, adding a trailing
+// newline when the template does not end with one.
+func renderClaimOutputTemplate(w io.Writer, tmpl *template.Template, res *claimResult, rigHandle string) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newClaimTemplateData(res, rigHandle)); err != nil {
+		return fmt.Errorf("rendering --output-template: %w", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// claimOptions controls optional claim preconditions.
+type claimOptions struct {
+	// RequireDepsClosed turns outstanding dependencies into a hard error.
+	// The wasteland setting claim.require_deps_closed can also enable it.
+	RequireDepsClosed bool
+
+	// OnBehalfOf claims for another rig. The caller must be a coordinator.
+	OnBehalfOf string
+
+	// Group claims the item for a named group. The claiming rig must be a
+	// member; every member may then complete the item.
+	Group string
+
+	// PreferOwnPosts makes auto-claim try items posted by the claiming rig
+	// before others of the same priority. Ignored when claiming by ID.
+	PreferOwnPosts bool
+
+	// NewestFirst makes auto-claim try the most recently posted items
+	// first instead of the most urgent (--from-feed). Ignored when claiming
+	// by ID.
+	NewestFirst bool
+
+	// MaxAttempts caps how many candidates auto-claim tries; 0 tries all.
+	MaxAttempts int
+
+	// Tags limits auto-claim to items carrying any of them, or all of
+	// them with TagsMatchAll. Ignored when claiming by ID.
+	Tags         []string
+	TagsMatchAll bool
+
+	// LockTTL, when positive, makes the claim hold an advisory lock on the
+	// item from before its checks until after its write (--lock). The lock
+	// lapses after LockTTL if it is never released.
+	LockTTL time.Duration
+
+	// Refresh, when set, brings the local board up to date (--auto-pull).
+	// It runs once, before the first query that decides the claim.
+	Refresh func() error
+
+	// Note is recorded as a comment by the claiming rig after the claim.
+	Note string
+
+	// Labels are written on the item by the claiming rig after the claim.
+	Labels []doltserver.WantedLabel
+
+	// Metrics, when set, counts claim writes and their outcomes
+	// (--metrics-file).
+	Metrics *claimMetrics
+
+	// Echo, when set, receives each evaluated precondition as a checklist
+	// before the write (--echo-preconditions).
+	Echo io.Writer
+
+	// Confirmed skips the claim.confirm_priority prompt (--confirm).
+	Confirmed bool
+
+	// Confirm asks the user to approve claiming item. Nil means no one can
+	// be asked, so guarded items fail unless Confirmed is set.
+	Confirm func(item *doltserver.WantedItem) bool
+}
+
+// refresh runs Refresh once; later calls on the same options do nothing.
+func (o *claimOptions) refresh() error {
+	if o.Refresh == nil {
+		return nil
+	}
+	refresh := o.Refresh
+	o.Refresh = nil
+	return refresh()
+}
+
+// claimResult describes a successful claim.
+type claimResult struct {
+	// Item reflects pre-claim state (status "open", empty ClaimedBy);
+	// callers needing post-claim state should re-query.
+	Item *doltserver.WantedItem
+
+	// ClaimedBy is the rig now holding the claim.
+	ClaimedBy string
+
+	// Group is the group the claim is held for, if any.
+	Group string
+
+	// Blockers lists dependencies that were not completed at claim time.
+	// Always empty when RequireDepsClosed is set.
+	Blockers []*doltserver.WantedItem
+
+	// AlreadyClaimed is set when the rig already held the claim, so
+	// nothing was written (see heldBySelf).
+	AlreadyClaimed bool
+}
+
 // claimWanted contains the testable business logic for claiming a wanted item.
-// The returned WantedItem reflects pre-claim state (status "open", empty ClaimedBy);
-// callers needing post-claim state should re-query.
-func claimWanted(store doltserver.WLCommonsStore, wantedID, rigHandle string) (*doltserver.WantedItem, error) {
+func claimWanted(ctx context.Context, store doltserver.WLCommonsStore, wantedID, rigHandle string, opts claimOptions) (*claimResult, error) {
+	if opts.LockTTL > 0 {
+		if err := store.AcquireClaimLock(wantedID, rigHandle, opts.LockTTL); err != nil {
+			echoClaimChecks(opts.Echo, wantedID, nil, err)
+			return nil, err
+		}
+		defer func() {
+			if err := store.ReleaseClaimLock(wantedID, rigHandle); err != nil {
+				style.PrintWarning("%v (the lock expires on its own)", err)
+			}
+		}()
+	}
+	if err := opts.refresh(); err != nil {
+		return nil, err
+	}
 	item, err := store.QueryWanted(wantedID)
 	if err != nil {
+		echoClaimChecks(opts.Echo, wantedID, nil, fmt.Errorf("item %s does not exist: %w", wantedID, err))
 		return nil, fmt.Errorf("querying wanted item: %w", err)
 	}
+	if heldBySelf(item, rigHandle, opts) {
+		echoClaimChecks(opts.Echo, wantedID, []claimStep{{OK: true, Desc: fmt.Sprintf("item %s is already claimed by %s", wantedID, rigHandle)}}, nil)
+		return &claimResult{Item: item, ClaimedBy: rigHandle, Group: opts.Group, AlreadyClaimed: true}, nil
+	}
+
+	check, err := checkClaim(ctx, store, item, rigHandle, opts)
+	echoClaimChecks(opts.Echo, wantedID, append([]claimStep{{OK: true, Desc: fmt.Sprintf("item %s exists", wantedID)}}, check.Steps...), err)
+	if err != nil {
+		return nil, &claimRefusedError{err}
+	}
+	claimant, blockers := check.Claimant, check.Blockers
+
+	if check.NeedsConfirm {
+		if opts.Confirm == nil {
+			return nil, &claimRefusedError{fmt.Errorf("wanted item %s is %s (setting %s); pass --confirm to claim it without a prompt",
+				wantedID, wlFormatPriority(strconv.Itoa(item.Priority)), wlSettingConfirmPriority)}
+		}
+		if !opts.Confirm(item) {
+			return nil, errClaimCancelled
+		}
+	}
+
+	switch {
+	case opts.Group != "":
+		err = store.ClaimWantedForGroup(wantedID, rigHandle, opts.Group)
+	case claimant != rigHandle:
+		err = store.ClaimWantedFor(wantedID, claimant, rigHandle)
+	default:
+		err = store.ClaimWanted(wantedID, rigHandle)
+	}
+	opts.Metrics.recordWrite(err)
+	if isClaimConflict(err) {
+		// A concurrent retry of this same claim may have won the race.
+		if now, qerr := store.QueryWanted(wantedID); qerr == nil && heldBySelf(now, rigHandle, opts) {
+			return &claimResult{Item: item, ClaimedBy: rigHandle, Group: opts.Group, Blockers: blockers, AlreadyClaimed: true}, nil
+		}
+	}
+	if err != nil {
+		return nil, lostClaimError(store, wantedID, err)
+	}
+
+	if opts.Note != "" {
+		if err := store.AddComment(wantedID, rigHandle, opts.Note); err != nil {
+			return nil, fmt.Errorf("claimed %s but recording the claim note failed: %w", wantedID, err)
+		}
+	}
+
+	if len(opts.Labels) > 0 {
+		if err := store.SetLabels(wantedID, rigHandle, opts.Labels); err != nil {
+			return nil, fmt.Errorf("claimed %s but recording labels failed: %w", wantedID, err)
+		}
+	}
+
+	return &claimResult{Item: item, ClaimedBy: claimant, Group: opts.Group, Blockers: blockers}, nil
+}
+
+// heldBySelf reports whether item is already claimed by rigHandle itself,
+// in the way opts asks for (the same group, or none). Claiming it again is
+// then a no-op rather than an error, so a retried gt wl claim succeeds. A
+// claim on behalf of another rig never counts.
+func heldBySelf(item *doltserver.WantedItem, rigHandle string, opts claimOptions) bool {
+	if opts.OnBehalfOf != "" && opts.OnBehalfOf != rigHandle {
+		return false
+	}
+	return item.Status == doltserver.StatusClaimed && item.ClaimedBy == rigHandle && item.ClaimedGroup == opts.Group
+}
+
+// claimCheck records how each claim precondition was evaluated.
+type claimCheck struct {
+	// Claimant is the rig that would hold the claim.
+	Claimant string
+
+	// Blockers lists dependencies that are not completed.
+	Blockers []*doltserver.WantedItem
+
+	// NeedsConfirm is set when claim.confirm_priority guards the item and
+	// the claim was not pre-confirmed.
+	NeedsConfirm bool
+
+	// Steps describes each precondition in evaluation order, ending with
+	// the failing one when the claim is refused.
+	Steps []claimStep
+}
+
+// claimStep is one evaluated claim precondition.
+type claimStep struct {
+	OK bool
+	// Warn marks a passing step that still deserves attention.
+	Warn bool
+	Desc string
+}
+
+// checkClaim evaluates every claim precondition for item without writing,
+// under the wasteland's ClaimPolicy. The returned claimCheck is non-nil
+// even on error so callers can explain which step failed.
+func checkClaim(ctx context.Context, store doltserver.WLCommonsStore, item *doltserver.WantedItem, rigHandle string, opts claimOptions) (*claimCheck, error) {
+	policy, err := loadClaimPolicy(store, opts)
+	if err != nil {
+		return &claimCheck{Claimant: rigHandle}, err
+	}
+	return policy.Evaluate(ctx, rigHandle, item)
+}
+
+// confirmClaim prints item and asks whether to claim it.
+func confirmClaim(item *doltserver.WantedItem) bool {
+	fmt.Printf("%s %s %s\n", style.Bold.Render(item.ID), wlFormatPriority(strconv.Itoa(item.Priority)), item.Title)
+	fmt.Printf("  Status: %s\n", item.Status)
+	return promptYesNo(fmt.Sprintf("Claim this %s item?", wlFormatPriority(strconv.Itoa(item.Priority))))
+}
+
+// priorityBand is an inclusive priority range for auto-claim. -1 leaves a
+// side unbounded.
+type priorityBand struct {
+	Min int
+	Max int
+}
+
+func (b priorityBand) bounded() bool { return b.Min >= 0 || b.Max >= 0 }
+
+func (b priorityBand) validate() error {
+	if b.Min < -1 || b.Max < -1 {
+		return fmt.Errorf("priority bounds must be non-negative")
+	}
+	if b.Min >= 0 && b.Max >= 0 && b.Min > b.Max {
+		return fmt.Errorf("--min-priority (%d) must be <= --max-priority (%d)", b.Min, b.Max)
+	}
+	return nil
+}
+
+// describe renders the band for error messages, e.g. " with priority 1..2".
+func (b priorityBand) describe() string {
+	switch {
+	case b.Min >= 0 && b.Max >= 0:
+		return fmt.Sprintf(" with priority %d..%d", b.Min, b.Max)
+	case b.Min >= 0:
+		return fmt.Sprintf(" with priority >= %d", b.Min)
+	case b.Max >= 0:
+		return fmt.Sprintf(" with priority <= %d", b.Max)
+	}
+	return ""
+}
+
+// autoClaimWanted claims the highest-priority open item within band. Items
+// whose preconditions fail (e.g. strict dependency mode) or that are lost
+// to another rig are skipped; any other failure stops the search.
+func autoClaimWanted(ctx context.Context, store doltserver.WLCommonsStore, rigHandle string, band priorityBand, opts claimOptions) (*claimResult, error) {
+	if err := opts.refresh(); err != nil {
+		return nil, err
+	}
+	candidates, err := store.ListWanted(doltserver.WantedFilter{
+		Status:       "open",
+		MinPriority:  band.Min,
+		MaxPriority:  band.Max,
+		Tags:         opts.Tags,
+		TagsMatchAll: opts.TagsMatchAll,
+		NewestFirst:  opts.NewestFirst,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing open wanted items: %w", err)
+	}
+	filter := band.describe() + describeTagFilter(opts.Tags, opts.TagsMatchAll)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no open wanted items%s", filter)
+	}
+	if opts.PreferOwnPosts {
+		preferOwnPosts(candidates, rigHandle)
+	}
+
+	if opts.MaxAttempts > 0 && len(candidates) > opts.MaxAttempts {
+		candidates = candidates[:opts.MaxAttempts]
+	}
+
+	var lastErr error
+	for _, c := range candidates {
+		res, err := claimWanted(ctx, store, c.ID, rigHandle, opts)
+		if err == nil && res.Item.CreatedAt.IsZero() {
+			// The claim re-reads the item without created_at; keep the
+			// listing's so callers can say how old the pick is.
+			res.Item.CreatedAt = c.CreatedAt
+		}
+		if err == nil || errors.Is(err, errClaimCancelled) {
+			return res, err
+		}
+		if !isClaimConflict(err) && !isClaimRefused(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%w%s after %d attempt(s): %w", errNoClaimableWork, filter, len(candidates), lastErr)
+}
+
+// tagMatchAll resolves --tag-any/--tag-all for a --tag filter. Matching
+// any tag is the default.
+func tagMatchAll(tags []string, anyFlag, allFlag bool) (bool, error) {
+	if anyFlag && allFlag {
+		return false, fmt.Errorf("--tag-any and --tag-all cannot be combined")
+	}
+	if (anyFlag || allFlag) && len(tags) == 0 {
+		return false, fmt.Errorf("--tag-any and --tag-all require at least one --tag")
+	}
+	return allFlag, nil
+}
+
+// describeTagFilter renders a tag filter for error messages, e.g.
+// " tagged go or sql".
+func describeTagFilter(tags []string, matchAll bool) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	sep := " or "
+	if matchAll {
+		sep = " and "
+	}
+	return " tagged " + strings.Join(tags, sep)
+}
+
+// preferOwnPosts reorders auto-claim candidates, already sorted by priority
+// then age, so items posted by rigHandle come first within each priority.
+func preferOwnPosts(candidates []*doltserver.WantedItem, rigHandle string) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.PostedBy == rigHandle && b.PostedBy != rigHandle
+	})
+}
+
+// parseClaimLabels validates --label values, rejecting repeated keys.
+func parseClaimLabels(raw []string) ([]doltserver.WantedLabel, error) {
+	seen := make(map[string]bool, len(raw))
+	labels := make([]doltserver.WantedLabel, 0, len(raw))
+	for _, r := range raw {
+		l, err := doltserver.ParseWantedLabel(r)
+		if err != nil {
+			return nil, err
+		}
+		if seen[l.Key] {
+			return nil, fmt.Errorf("--label %q given more than once", l.Key)
+		}
+		seen[l.Key] = true
+		labels = append(labels, l)
+	}
+	return labels, nil
+}
+
+// resolveWantedByTitle returns the ID of the single open item titled title.
+func resolveWantedByTitle(store doltserver.WLCommonsStore, title string) (string, error) {
+	items, err := store.ListWanted(doltserver.WantedFilter{
+		Status:      "open",
+		Title:       title,
+		MinPriority: -1,
+		MaxPriority: -1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("looking up wanted item by title: %w", err)
+	}
+	switch len(items) {
+	case 0:
+		return "", fmt.Errorf("no open wanted item titled %q", title)
+	case 1:
+		return items[0].ID, nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return "", fmt.Errorf("title %q matches %d open items: %s; claim by ID instead", title, len(items), strings.Join(ids, ", "))
+}
 
-	if item.Status != "open" {
-		return nil, fmt.Errorf("wanted item %s is not open (status: %s)", wantedID, item.Status)
+// outstandingBlockers returns the dependencies of wantedID that have not
+// reached a status model closes.
+func outstandingBlockers(store doltserver.WLCommonsStore, wantedID string, model *doltserver.StatusModel) ([]*doltserver.WantedItem, error) {
+	deps, err := store.QueryBlockers(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying dependencies: %w", err)
+	}
+	var open []*doltserver.WantedItem
+	for _, dep := range deps {
+		if !model.Closed(dep.Status) {
+			open = append(open, dep)
+		}
 	}
+	return open, nil
+}
+
+// formatBlockers renders blockers as "w-a (open), w-b (claimed)".
+func formatBlockers(blockers []*doltserver.WantedItem) string {
+	parts := make([]string, len(blockers))
+	for i, b := range blockers {
+		parts[i] = fmt.Sprintf("%s (%s)", b.ID, b.Status)
+	}
+	return strings.Join(parts, ", ")
+}
 
-	if err := store.ClaimWanted(wantedID, rigHandle); err != nil {
-		return nil, fmt.Errorf("claiming wanted item: %w", err)
+// settingListContains reports whether a comma-separated wasteland setting
+// includes value.
+func settingListContains(settings map[string]string, key, value string) bool {
+	for _, entry := range splitCommaList(settings[key]) {
+		if entry == value {
+			return true
+		}
 	}
+	return false
+}
+
+// settingInt reads an integer wasteland setting. ok is false when the
+// setting is missing or unparseable.
+func settingInt(settings map[string]string, key string) (n int, ok bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(settings[key]))
+	return n, err == nil
+}
 
-	return item, nil
+// settingBool reads a boolean wasteland setting, treating missing or
+// unparseable values as false.
+func settingBool(settings map[string]string, key string) bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(settings[key]))
+	return err == nil && v
 }
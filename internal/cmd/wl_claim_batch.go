@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// claimBatchOutput is where claimWantedBatch reports. Under --json each
+// successful claim is written to Out as its own JSON object and failures go
+// to Err, so Out stays a parseable stream.
+type claimBatchOutput struct {
+	Out  io.Writer
+	Err  io.Writer
+	JSON bool
+}
+
+// claimWantedBatch claims each of wantedIDs in turn for rigHandle. Every
+// claim is a separate write with its own race guard, so one failure is
+// reported and skipped rather than aborting the rest. It returns how many
+// were claimed, and an error only when none were.
+func claimWantedBatch(ctx context.Context, out claimBatchOutput, store doltserver.WLCommonsStore, townRoot string, wantedIDs []string, rigHandle string, opts claimOptions, policy claimConflictPolicy) (int, error) {
+	claimed := 0
+	for _, id := range wantedIDs {
+		if err := throttleWlWrite(ctx, store, townRoot); err != nil {
+			return claimed, err
+		}
+		res, err := claimWantedOnConflict(ctx, store, id, rigHandle, opts, policy)
+		recordWlAction(townRoot, id, "claim", err)
+		if err != nil {
+			if out.JSON {
+				fmt.Fprintf(out.Err, "skipping %s: %v\n", id, err)
+			} else {
+				fmt.Fprintf(out.Out, "%s %s: %v\n", style.Warning.Render("✗"), style.Bold.Render(id), err)
+			}
+			continue
+		}
+		claimed++
+
+		if !res.AlreadyClaimed {
+			change := "claimed"
+			if res.ClaimedBy != rigHandle {
+				change = "claimed for " + res.ClaimedBy
+			}
+			notifyWatchers(store, townRoot, id, rigHandle, change)
+		}
+
+		if out.JSON {
+			if err := writeWLJSON(out.Out, newClaimTemplateData(res, rigHandle), wlJSONPrettyOutput()); err != nil {
+				return claimed, err
+			}
+			continue
+		}
+		if res.AlreadyClaimed {
+			fmt.Fprintf(out.Out, "%s %s %s (already claimed by you)\n", wlSuccessMark(), style.Bold.Render(id), res.Item.Title)
+			continue
+		}
+		fmt.Fprintf(out.Out, "%s Claimed %s %s\n", wlSuccessMark(), style.Bold.Render(id), res.Item.Title)
+		if res.ClaimedBy != rigHandle {
+			fmt.Fprintf(out.Out, "  %s\n", style.Dim.Render("for "+res.ClaimedBy))
+		}
+		if len(res.Blockers) > 0 {
+			fmt.Fprintf(out.Out, "  %s\n", style.Dim.Render("outstanding dependencies: "+formatBlockers(res.Blockers)))
+		}
+	}
+
+	failed := len(wantedIDs) - claimed
+	if !out.JSON {
+		fmt.Fprintf(out.Out, "\nClaimed %d of %d item(s); %d failed.\n", claimed, len(wantedIDs), failed)
+	}
+	if claimed == 0 {
+		return 0, fmt.Errorf("none of the %d items could be claimed", len(wantedIDs))
+	}
+	return claimed, nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestClaimWantedBatch_ReportsEachItem(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "First"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-2", Title: "Taken"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-3", Title: "Third"})
+	_ = store.ClaimWanted("w-2", "other-rig")
+
+	var out bytes.Buffer
+	claimed, err := claimWantedBatch(context.Background(), claimBatchOutput{Out: &out, Err: &out}, store, t.TempDir(),
+		[]string{"w-1", "w-2", "w-3"}, "my-rig", claimOptions{}, claimConflictPolicy{})
+	if err != nil || claimed != 2 {
+		t.Fatalf("claimWantedBatch() = %d, %v; want 2 claimed and no error", claimed, err)
+	}
+	for _, id := range []string{"w-1", "w-3"} {
+		if item, _ := store.QueryWanted(id); item.ClaimedBy != "my-rig" {
+			t.Errorf("%s claimed by %q, want my-rig", id, item.ClaimedBy)
+		}
+	}
+	if item, _ := store.QueryWanted("w-2"); item.ClaimedBy != "other-rig" {
+		t.Errorf("w-2 claimed by %q, want other-rig untouched", item.ClaimedBy)
+	}
+	got := out.String()
+	for _, want := range []string{"Claimed w-1", "w-2:", "Claimed w-3", "Claimed 2 of 3 item(s); 1 failed."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestClaimWantedBatch_FailsOnlyWhenNoneClaimed(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+
+	var out bytes.Buffer
+	claimed, err := claimWantedBatch(context.Background(), claimBatchOutput{Out: &out, Err: &out}, store, t.TempDir(),
+		[]string{"w-missing", "w-gone"}, "my-rig", claimOptions{}, claimConflictPolicy{})
+	if err == nil || claimed != 0 || !strings.Contains(err.Error(), "none of the 2 items") {
+		t.Errorf("claimWantedBatch() = %d, %v; want an error naming the whole batch", claimed, err)
+	}
+}
+
+func TestClaimWantedBatch_JSONStream(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "First"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-2", Title: "Second"})
+
+	var stdout, stderr bytes.Buffer
+	out := claimBatchOutput{Out: &stdout, Err: &stderr, JSON: true}
+	if _, err := claimWantedBatch(context.Background(), out, store, t.TempDir(),
+		[]string{"w-1", "w-missing", "w-2"}, "my-rig", claimOptions{}, claimConflictPolicy{}); err != nil {
+		t.Fatalf("claimWantedBatch() error: %v", err)
+	}
+
+	dec := json.NewDecoder(&stdout)
+	var ids []string
+	for dec.More() {
+		var data claimTemplateData
+		if err := dec.Decode(&data); err != nil {
+			t.Fatalf("stdout is not a JSON stream: %v", err)
+		}
+		ids = append(ids, data.ID)
+	}
+	if strings.Join(ids, ",") != "w-1,w-2" {
+		t.Errorf("JSON claims = %v, want w-1 and w-2", ids)
+	}
+	if !strings.Contains(stderr.String(), "skipping w-missing") {
+		t.Errorf("stderr = %q, want the failed item", stderr.String())
+	}
+}
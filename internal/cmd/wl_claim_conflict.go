@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// claimConflictMode is how gt wl claim --on-conflict handles contention.
+type claimConflictMode string
+
+const (
+	// claimConflictFail reports a conflict immediately (the default).
+	claimConflictFail claimConflictMode = "fail"
+	// claimConflictRetry re-reads the item and tries again, up to a limit.
+	claimConflictRetry claimConflictMode = "retry"
+	// claimConflictWait polls until the item is open again, then claims it.
+	claimConflictWait claimConflictMode = "wait"
+)
+
+// defaultClaimWaitPoll is how often --on-conflict wait re-reads the item.
+const defaultClaimWaitPoll = 5 * time.Second
+
+func parseClaimConflictMode(s string) (claimConflictMode, error) {
+	switch m := claimConflictMode(s); m {
+	case claimConflictFail, claimConflictRetry, claimConflictWait:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid --on-conflict %q: must be one of fail, retry, wait", s)
+}
+
+// claimConflictPolicy configures claimWantedOnConflict.
+type claimConflictPolicy struct {
+	Mode claimConflictMode
+
+	// Retries is how many more times retry mode claims after a conflict.
+	Retries int
+
+	// WaitTimeout bounds how long wait mode waits for the item to reopen.
+	WaitTimeout time.Duration
+
+	// Poll is how often wait mode re-reads the item; zero means
+	// defaultClaimWaitPoll.
+	Poll time.Duration
+}
+
+// lostClaimError describes a failed claim write. Only a write whose guard
+// matched no row (doltserver.ErrWantedNotOpen) was lost to another rig; the
+// item is then read again to name the town that claimed it in between.
+// Any other failure (network, timeout, a queued write) is returned as is.
+func lostClaimError(store doltserver.WLCommonsStore, wantedID string, err error) error {
+	if !errors.Is(err, doltserver.ErrWantedNotOpen) {
+		return err
+	}
+	if now, qerr := store.QueryWanted(wantedID); qerr == nil && now.ClaimedBy != "" {
+		err = fmt.Errorf("%s already claimed by another town (%s): %w", wantedID, now.ClaimedBy, err)
+	}
+	return fmt.Errorf("claiming wanted item: %w", err)
+}
+
+// claimRefusedError is a claim turned down by its preconditions before any
+// write: the item is held, its dependencies are open, a policy forbids it.
+type claimRefusedError struct{ err error }
+
+func (e *claimRefusedError) Error() string { return e.err.Error() }
+func (e *claimRefusedError) Unwrap() error { return e.err }
+
+// isClaimRefused reports whether err is a claim refused by a precondition.
+func isClaimRefused(err error) bool {
+	var refused *claimRefusedError
+	return errors.As(err, &refused)
+}
+
+// isClaimConflict reports whether err is a claim lost to another rig: a
+// write that found the item no longer open, or a claim lock another rig
+// holds. This is the conflict --on-conflict acts on.
+func isClaimConflict(err error) bool {
+	return errors.Is(err, doltserver.ErrWantedNotOpen) || doltserver.IsClaimLockHeld(err)
+}
+
+// claimWantedOnConflict claims wantedID like claimWanted, handling a
+// conflict as policy says. Retry re-reads the item and repeats the checks
+// and the write; a check that now refuses (the rival's claim stuck) ends
+// it. Wait also covers an item that is already held: it polls until the
+// item is open again or the timeout passes. Items that are completed or
+// withdrawn never reopen, so wait fails on them at once.
+func claimWantedOnConflict(ctx context.Context, store doltserver.WLCommonsStore, wantedID, rigHandle string, opts claimOptions, policy claimConflictPolicy) (*claimResult, error) {
+	switch policy.Mode {
+	case claimConflictRetry:
+		var res *claimResult
+		var err error
+		for attempt := 0; attempt <= policy.Retries; attempt++ {
+			res, err = claimWanted(ctx, store, wantedID, rigHandle, opts)
+			if !isClaimConflict(err) {
+				return res, err
+			}
+		}
+		return nil, fmt.Errorf("%w (gave up after %d retries)", err, policy.Retries)
+
+	case claimConflictWait:
+		poll := policy.Poll
+		if poll <= 0 {
+			poll = defaultClaimWaitPoll
+		}
+		deadline := time.Now().Add(policy.WaitTimeout)
+		for {
+			res, err := claimWanted(ctx, store, wantedID, rigHandle, opts)
+			if err == nil || !claimMayReopen(store, wantedID, err) {
+				return res, err
+			}
+			if time.Now().Add(poll).After(deadline) {
+				return nil, fmt.Errorf("%s did not reopen within %s: %w", wantedID, policy.WaitTimeout, err)
+			}
+			if err := sleepCtx(ctx, poll); err != nil {
+				return nil, fmt.Errorf("waiting for %s to reopen: %w", wantedID, err)
+			}
+		}
+
+	default:
+		return claimWanted(ctx, store, wantedID, rigHandle, opts)
+	}
+}
+
+// claimMayReopen reports whether a claim that failed with err is worth
+// waiting on: it lost a race, or a check refused it because the item is
+// held but not finished. A failed write or query is never waited on.
+func claimMayReopen(store doltserver.WLCommonsStore, wantedID string, err error) bool {
+	if isClaimConflict(err) {
+		return true
+	}
+	if !isClaimRefused(err) {
+		return false
+	}
+	item, qerr := store.QueryWanted(wantedID)
+	if qerr != nil {
+		return false
+	}
+	switch item.Status {
+	case doltserver.StatusOpen, doltserver.StatusCompleted, doltserver.StatusWithdrawn:
+		// Open means a policy check refused, which waiting will not fix.
+		return false
+	}
+	return true
+}
+
+// sleepCtx sleeps for d unless ctx ends first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
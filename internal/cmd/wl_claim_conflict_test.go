@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// conflictClaimStore loses the first conflicts claim writes as if another
+// writer changed the row first, without changing it.
+type conflictClaimStore struct {
+	*fakeWLCommonsStore
+	conflicts int
+	writes    int
+}
+
+func (s *conflictClaimStore) ClaimWanted(wantedID, rigHandle string) error {
+	s.writes++
+	if s.writes <= s.conflicts {
+		return fmt.Errorf("wanted item %q is %w", wantedID, doltserver.ErrWantedNotOpen)
+	}
+	return s.fakeWLCommonsStore.ClaimWanted(wantedID, rigHandle)
+}
+
+// reopeningClaimStore holds an item claimed by a rival and reopens it
+// after the item has been read reads times.
+type reopeningClaimStore struct {
+	*fakeWLCommonsStore
+	reads int
+}
+
+func (s *reopeningClaimStore) QueryWanted(wantedID string) (*doltserver.WantedItem, error) {
+	s.reads--
+	if s.reads == 0 {
+		s.mu.Lock()
+		s.items[wantedID].Status = doltserver.StatusOpen
+		s.items[wantedID].ClaimedBy = ""
+		s.mu.Unlock()
+	}
+	return s.fakeWLCommonsStore.QueryWanted(wantedID)
+}
+
+func TestClaimWantedOnConflict_Fail(t *testing.T) {
+	t.Parallel()
+	store := &conflictClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), conflicts: 1}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+
+	_, err := claimWantedOnConflict(context.Background(), store, "w-1", "my-rig", claimOptions{}, claimConflictPolicy{Mode: claimConflictFail})
+	if !isClaimConflict(err) {
+		t.Fatalf("error = %v, want a claim conflict", err)
+	}
+	if store.writes != 1 {
+		t.Errorf("writes = %d, want 1", store.writes)
+	}
+}
+
+func TestClaimWantedOnConflict_Retry(t *testing.T) {
+	t.Parallel()
+	store := &conflictClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), conflicts: 2}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+
+	res, err := claimWantedOnConflict(context.Background(), store, "w-1", "my-rig", claimOptions{}, claimConflictPolicy{Mode: claimConflictRetry, Retries: 2})
+	if err != nil {
+		t.Fatalf("claimWantedOnConflict() error: %v", err)
+	}
+	if res.ClaimedBy != "my-rig" || store.writes != 3 {
+		t.Errorf("claimed by %q after %d writes, want my-rig after 3", res.ClaimedBy, store.writes)
+	}
+
+	store = &conflictClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), conflicts: 5}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_, err = claimWantedOnConflict(context.Background(), store, "w-1", "my-rig", claimOptions{}, claimConflictPolicy{Mode: claimConflictRetry, Retries: 2})
+	if err == nil || !strings.Contains(err.Error(), "gave up after 2 retries") {
+		t.Errorf("error = %v, want gave up after 2 retries", err)
+	}
+
+	// A rival's claim that sticks turns the retry into a refused check.
+	racing := &racingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), lose: map[string]bool{"w-1": true}}
+	_ = racing.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_, err = claimWantedOnConflict(context.Background(), racing, "w-1", "my-rig", claimOptions{}, claimConflictPolicy{Mode: claimConflictRetry, Retries: 5})
+	if err == nil || isClaimConflict(err) || !strings.Contains(err.Error(), "is not open") {
+		t.Errorf("error = %v, want the not-open check", err)
+	}
+}
+
+func TestClaimWantedOnConflict_Wait(t *testing.T) {
+	t.Parallel()
+	store := &reopeningClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), reads: 3}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_ = store.fakeWLCommonsStore.ClaimWanted("w-1", "rival-rig")
+
+	res, err := claimWantedOnConflict(context.Background(), store, "w-1", "my-rig", claimOptions{},
+		claimConflictPolicy{Mode: claimConflictWait, WaitTimeout: time.Second, Poll: time.Millisecond})
+	if err != nil {
+		t.Fatalf("claimWantedOnConflict() error: %v", err)
+	}
+	if res.ClaimedBy != "my-rig" {
+		t.Errorf("ClaimedBy = %q, want my-rig", res.ClaimedBy)
+	}
+}
+
+func TestClaimWantedOnConflict_WaitTimesOut(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_ = store.ClaimWanted("w-1", "rival-rig")
+
+	_, err := claimWantedOnConflict(context.Background(), store, "w-1", "my-rig", claimOptions{},
+		claimConflictPolicy{Mode: claimConflictWait, WaitTimeout: 20 * time.Millisecond, Poll: 5 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "did not reopen within 20ms") {
+		t.Errorf("error = %v, want a wait timeout", err)
+	}
+
+	// Finished items never reopen, so wait does not wait on them.
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-2", Title: "Two", Status: doltserver.StatusCompleted})
+	start := time.Now()
+	_, err = claimWantedOnConflict(context.Background(), store, "w-2", "my-rig", claimOptions{},
+		claimConflictPolicy{Mode: claimConflictWait, WaitTimeout: time.Minute, Poll: time.Minute})
+	if err == nil || time.Since(start) > time.Second {
+		t.Errorf("error = %v after %s, want an immediate refusal", err, time.Since(start))
+	}
+}
+
+func TestParseClaimConflictMode(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"fail", "retry", "wait"} {
+		if m, err := parseClaimConflictMode(s); err != nil || string(m) != s {
+			t.Errorf("parseClaimConflictMode(%q) = %q, %v", s, m, err)
+		}
+	}
+	if _, err := parseClaimConflictMode("yolo"); err == nil {
+		t.Error("parseClaimConflictMode(yolo) should fail")
+	}
+}
+
+func TestLostClaimError_PassesHardFailuresThrough(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_ = store.ClaimWanted("w-1", "rival-rig")
+
+	hard := fmt.Errorf("claim failed: %w", doltserver.ErrWriteQueued)
+	if err := lostClaimError(store, "w-1", hard); err != hard || isClaimConflict(err) {
+		t.Errorf("lostClaimError(queued) = %v, want it unchanged and not a conflict", err)
+	}
+
+	lost := lostClaimError(store, "w-1", fmt.Errorf("wanted item %q is %w", "w-1", doltserver.ErrWantedNotOpen))
+	if !isClaimConflict(lost) || !strings.Contains(lost.Error(), "already claimed by another town (rival-rig)") {
+		t.Errorf("lostClaimError(not open) = %v, want a conflict naming rival-rig", lost)
+	}
+}
+
+// failingClaimStore fails every claim write with a hard error after a
+// rival's claim has landed, as if our write timed out while another town
+// claimed the item.
+type failingClaimStore struct {
+	*fakeWLCommonsStore
+	writes int
+}
+
+func (s *failingClaimStore) ClaimWanted(wantedID, rigHandle string) error {
+	s.writes++
+	_ = s.fakeWLCommonsStore.ClaimWanted(wantedID, "rival-rig")
+	return fmt.Errorf("claim failed: exit status 1 (output: i/o timeout)")
+}
+
+func TestClaimWantedOnConflict_HardFailureNotRetried(t *testing.T) {
+	t.Parallel()
+	for _, policy := range []claimConflictPolicy{
+		{Mode: claimConflictRetry, Retries: 3},
+		{Mode: claimConflictWait, WaitTimeout: time.Second, Poll: time.Millisecond},
+	} {
+		store := &failingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore()}
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+		metrics := &claimMetrics{}
+
+		_, err := claimWantedOnConflict(context.Background(), store, "w-1", "my-rig", claimOptions{Metrics: metrics}, policy)
+		if err == nil || isClaimConflict(err) || !strings.Contains(err.Error(), "i/o timeout") {
+			t.Errorf("%s: error = %v, want the write failure as is", policy.Mode, err)
+		}
+		if store.writes != 1 {
+			t.Errorf("%s: writes = %d, want 1", policy.Mode, store.writes)
+		}
+		if want := (claimMetrics{Attempted: 1, Failed: 1}); *metrics != want {
+			t.Errorf("%s: metrics = %+v, want %+v", policy.Mode, *metrics, want)
+		}
+	}
+}
+
+func TestAutoClaimWanted_StopsOnHardFailure(t *testing.T) {
+	t.Parallel()
+	store := &failingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore()}
+	for _, id := range []string{"w-1", "w-2"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: id, Priority: 2})
+	}
+
+	_, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: -1, Max: -1}, claimOptions{})
+	if err == nil || errors.Is(err, errNoClaimableWork) {
+		t.Errorf("autoClaimWanted() error = %v, want the write failure", err)
+	}
+	if store.writes != 1 {
+		t.Errorf("writes = %d, want auto-claim to stop after the first failure", store.writes)
+	}
+}
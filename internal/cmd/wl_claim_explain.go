@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// claimPreviewMode selects what gt wl claim prints instead of claiming.
+type claimPreviewMode int
+
+const (
+	claimPreviewNone claimPreviewMode = iota
+	// claimPreviewSQL prints only the statement (--dry-run).
+	claimPreviewSQL
+	// claimPreviewReasons prints only the precondition checks (--explain).
+	claimPreviewReasons
+	// claimPreviewFull prints state, checks, and statement (--dry-run-explain).
+	claimPreviewFull
+)
+
+// claimPlan is everything a claim would do, computed without writing.
+type claimPlan struct {
+	Detail *doltserver.WantedDetail
+	Check  *claimCheck
+	// Err is why the claim would fail, or nil if it would succeed.
+	Err error
+	// SQL is the script the claim would execute.
+	SQL string
+	// Note is true when a claim note would also be recorded.
+	Note bool
+}
+
+// planClaim evaluates a claim of wantedID by rigHandle without writing. It
+// only returns an error when the item cannot be read; a claim that would be
+// refused is reported in claimPlan.Err.
+func planClaim(ctx context.Context, store doltserver.WLCommonsStore, wantedID, rigHandle string, opts claimOptions, withNote bool) (*claimPlan, error) {
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying wanted item: %w", err)
+	}
+
+	plan := &claimPlan{Detail: detail, Note: withNote}
+	plan.Check, plan.Err = checkClaim(ctx, store, detail.Item, rigHandle, opts)
+	switch claimant := plan.Check.Claimant; {
+	case opts.Group != "":
+		plan.SQL = doltserver.ClaimWantedForGroupScript(wantedID, rigHandle, opts.Group)
+	case claimant != rigHandle:
+		plan.SQL = doltserver.ClaimWantedForScript(wantedID, claimant, rigHandle)
+	default:
+		plan.SQL = doltserver.ClaimWantedScript(wantedID, rigHandle)
+	}
+	return plan, nil
+}
+
+// renderClaimPlan writes the preview selected by mode and returns plan.Err,
+// so a refused claim still exits non-zero.
+func renderClaimPlan(w io.Writer, plan *claimPlan, mode claimPreviewMode) error {
+	item := plan.Detail.Item
+
+	if mode == claimPreviewFull {
+		fmt.Fprintf(w, "%s %s %s\n\n", style.Bold.Render("Claim preview for"), item.ID, style.Dim.Render("(no changes written)"))
+		fmt.Fprintf(w, "%s\n", style.Bold.Render("Current state:"))
+		fmt.Fprintf(w, "  Title:      %s\n", item.Title)
+		fmt.Fprintf(w, "  Status:     %s\n", item.Status)
+		fmt.Fprintf(w, "  Priority:   %s\n", wlFormatPriority(fmt.Sprint(item.Priority)))
+		fmt.Fprintf(w, "  Claimed by: %s\n", valueOrDash(item.ClaimedBy))
+		if len(plan.Detail.Dependencies) > 0 {
+			fmt.Fprintf(w, "  Depends on: %s\n", formatBlockers(plan.Detail.Dependencies))
+		}
+		if n := len(plan.Detail.Completions); n > 0 {
+			fmt.Fprintf(w, "  Completions: %d\n", n)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if mode == claimPreviewReasons || mode == claimPreviewFull {
+		fmt.Fprintf(w, "%s\n", style.Bold.Render("Checks:"))
+		for _, step := range plan.Check.Steps {
+			mark := "✓"
+			switch {
+			case !step.OK:
+				mark = "✗"
+			case step.Warn:
+				mark = "!"
+			}
+			fmt.Fprintf(w, "  %s %s\n", mark, step.Desc)
+		}
+		if plan.Note && plan.Err == nil {
+			fmt.Fprintf(w, "  ✓ claim note would be recorded as a comment\n")
+		}
+		if plan.Err != nil {
+			fmt.Fprintf(w, "\nResult: claim would fail\n")
+		} else {
+			fmt.Fprintf(w, "\nResult: claim would succeed (claimed_by=%s)\n", plan.Check.Claimant)
+		}
+	}
+
+	if mode == claimPreviewSQL || mode == claimPreviewFull {
+		if mode == claimPreviewFull {
+			fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Statement:"))
+			for _, line := range strings.Split(strings.TrimRight(plan.SQL, "\n"), "\n") {
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+		} else {
+			fmt.Fprint(w, plan.SQL)
+		}
+	}
+
+	return plan.Err
+}
+
+// valueOrDash renders empty strings as "-".
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// echoClaimChecks writes the preconditions evaluated for wantedID to w as
+// a checklist, for --echo-preconditions. When err is non-nil the list ends
+// at the failing check, which is err itself if no step recorded it. A nil w
+// writes nothing.
+func echoClaimChecks(w io.Writer, wantedID string, steps []claimStep, err error) {
+	if w == nil {
+		return
+	}
+	if err != nil && (len(steps) == 0 || steps[len(steps)-1].OK) {
+		steps = append(steps, claimStep{Desc: err.Error()})
+	}
+	fmt.Fprintf(w, "%s %s\n", style.Bold.Render("Preconditions for"), wantedID)
+	for _, step := range steps {
+		mark := style.SuccessPrefix
+		switch {
+		case !step.OK:
+			mark = style.ErrorPrefix
+		case step.Warn:
+			mark = style.WarningPrefix
+		}
+		fmt.Fprintf(w, "  %s %s\n", mark, step.Desc)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render("stopped at the first failed check; nothing was written"))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestPlanClaim_WouldSucceedWritesNothing(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Dep"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", DependsOn: []string{"w-dep"}})
+
+	plan, err := planClaim(context.Background(), store, "w-main", "my-rig", claimOptions{}, false)
+	if err != nil {
+		t.Fatalf("planClaim() error: %v", err)
+	}
+	if plan.Err != nil {
+		t.Fatalf("plan.Err = %v, want nil", plan.Err)
+	}
+	if item, _ := store.QueryWanted("w-main"); item.Status != "open" {
+		t.Errorf("planClaim wrote: status = %q", item.Status)
+	}
+
+	var buf bytes.Buffer
+	if err := renderClaimPlan(&buf, plan, claimPreviewFull); err != nil {
+		t.Fatalf("renderClaimPlan() error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Status:     open",
+		"✓ item is open",
+		"! outstanding dependencies w-dep (open)",
+		"claim would succeed (claimed_by=my-rig)",
+		"UPDATE wanted SET claimed_by='my-rig'",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlanClaim_WouldFail(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", Status: "claimed", ClaimedBy: "other-rig"})
+
+	plan, err := planClaim(context.Background(), store, "w-main", "my-rig", claimOptions{}, false)
+	if err != nil {
+		t.Fatalf("planClaim() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = renderClaimPlan(&buf, plan, claimPreviewReasons)
+	if err == nil || !strings.Contains(err.Error(), "not open") {
+		t.Fatalf("renderClaimPlan() error = %v, want not-open", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "✗ wanted item w-main is not open (status: claimed)") || !strings.Contains(out, "claim would fail") {
+		t.Errorf("output = %s", out)
+	}
+	if strings.Contains(out, "UPDATE wanted") {
+		t.Errorf("--explain should not print SQL:\n%s", out)
+	}
+}
+
+func TestPlanClaim_DryRunOnBehalfOf(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingCoordinators] = "coord"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main"})
+
+	plan, err := planClaim(context.Background(), store, "w-main", "coord", claimOptions{OnBehalfOf: "partner"}, false)
+	if err != nil {
+		t.Fatalf("planClaim() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := renderClaimPlan(&buf, plan, claimPreviewSQL); err != nil {
+		t.Fatalf("renderClaimPlan() error: %v", err)
+	}
+	if got, want := buf.String(), doltserver.ClaimWantedForScript("w-main", "partner", "coord"); got != want {
+		t.Errorf("--dry-run output = %q, want exactly the claim script %q", got, want)
+	}
+}
+
+func TestClaimWanted_EchoPreconditions(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-open", Title: "Open"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-taken", Title: "Taken", Status: "claimed", ClaimedBy: "other-rig"})
+
+	var buf bytes.Buffer
+	if _, err := claimWanted(context.Background(), store, "w-open", "my-rig", claimOptions{Echo: &buf}); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Preconditions for w-open", "item w-open exists", "item is open", "no outstanding dependencies"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "stopped") {
+		t.Errorf("successful claim reported a stop:\n%s", out)
+	}
+
+	buf.Reset()
+	if _, err := claimWanted(context.Background(), store, "w-taken", "my-rig", claimOptions{Echo: &buf}); err == nil {
+		t.Fatal("claimWanted() on a claimed item should fail")
+	}
+	out = buf.String()
+	if !strings.Contains(out, "wanted item w-taken is not open (status: claimed)") || !strings.Contains(out, "stopped at the first failed check") {
+		t.Errorf("output = %s", out)
+	}
+	if strings.Contains(out, "dependencies") {
+		t.Errorf("checks after the failure were printed:\n%s", out)
+	}
+
+	buf.Reset()
+	if _, err := claimWanted(context.Background(), store, "w-missing", "my-rig", claimOptions{Echo: &buf}); err == nil {
+		t.Fatal("claimWanted() on a missing item should fail")
+	}
+	if out := buf.String(); !strings.Contains(out, "item w-missing does not exist") {
+		t.Errorf("output = %s", out)
+	}
+}
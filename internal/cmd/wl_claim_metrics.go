@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// Prometheus counters written by gt wl claim --metrics-file.
+const (
+	wlMetricClaimsAttempted = "gt_wl_claims_attempted_total"
+	wlMetricClaimsWon       = "gt_wl_claims_won_total"
+	wlMetricClaimsLostRace  = "gt_wl_claims_lost_race_total"
+	wlMetricClaimsFailed    = "gt_wl_claims_failed_total"
+)
+
+// claimMetrics counts claim writes. A claim is attempted once it has passed
+// every precondition and its write is sent; it is won if the write lands,
+// lost to a race if the item was no longer open because another rig
+// changed it in between, and failed if the write itself failed (network,
+// timeout, a queued write).
+type claimMetrics struct {
+	Attempted int
+	Won       int
+	LostRace  int
+	Failed    int
+}
+
+// recordWrite counts one claim write and its outcome. A nil receiver
+// records nothing, so callers need not check whether metrics are on.
+func (m *claimMetrics) recordWrite(err error) {
+	if m == nil {
+		return
+	}
+	m.Attempted++
+	switch {
+	case err == nil:
+		m.Won++
+	case errors.Is(err, doltserver.ErrWantedNotOpen):
+		m.LostRace++
+	default:
+		m.Failed++
+	}
+}
+
+// add returns the sum of m and o.
+func (m claimMetrics) add(o claimMetrics) claimMetrics {
+	return claimMetrics{
+		Attempted: m.Attempted + o.Attempted,
+		Won:       m.Won + o.Won,
+		LostRace:  m.LostRace + o.LostRace,
+		Failed:    m.Failed + o.Failed,
+	}
+}
+
+// formatClaimMetrics renders m in the Prometheus text exposition format,
+// labelled with the claiming rig, for a node_exporter textfile collector.
+func formatClaimMetrics(rig string, m claimMetrics) string {
+	var b strings.Builder
+	for _, c := range []struct {
+		name, help string
+		value      int
+	}{
+		{wlMetricClaimsAttempted, "Claim writes attempted after passing preconditions.", m.Attempted},
+		{wlMetricClaimsWon, "Claim writes that landed.", m.Won},
+		{wlMetricClaimsLostRace, "Claim writes lost to another rig.", m.LostRace},
+		{wlMetricClaimsFailed, "Claim writes that failed for another reason.", m.Failed},
+	} {
+		fmt.Fprintf(&b, "# HELP %s %s\n", c.name, c.help)
+		fmt.Fprintf(&b, "# TYPE %s counter\n", c.name)
+		fmt.Fprintf(&b, "%s{rig=%q} %d\n", c.name, rig, c.value)
+	}
+	return b.String()
+}
+
+// readClaimMetrics reads the counters in a metrics file written by
+// formatClaimMetrics. A missing file reads as zero.
+func readClaimMetrics(path string) (claimMetrics, error) {
+	var m claimMetrics
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, fmt.Errorf("reading metrics file: %w", err)
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		name, _, _ := strings.Cut(fields[0], "{")
+		n, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			return m, fmt.Errorf("metrics file %s: bad sample %q", path, line)
+		}
+		switch name {
+		case wlMetricClaimsAttempted:
+			m.Attempted += n
+		case wlMetricClaimsWon:
+			m.Won += n
+		case wlMetricClaimsLostRace:
+			m.LostRace += n
+		case wlMetricClaimsFailed:
+			m.Failed += n
+		}
+	}
+	return m, sc.Err()
+}
+
+// writeClaimMetrics adds run to the counters already in path and rewrites
+// it atomically, so a textfile collector never scrapes a partial file and
+// the counters keep growing across runs of a worker loop.
+func writeClaimMetrics(path, rig string, run claimMetrics) error {
+	total, err := readClaimMetrics(path)
+	if err != nil {
+		return err
+	}
+	if err := writeWlFileAtomic(path, []byte(formatClaimMetrics(rig, total.add(run)))); err != nil {
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	return nil
+}
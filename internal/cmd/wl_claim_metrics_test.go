@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestClaimMetrics_CountsRaces(t *testing.T) {
+	t.Parallel()
+	store := &racingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), lose: map[string]bool{"w-1": true, "w-2": true}}
+	for _, id := range []string{"w-1", "w-2", "w-3"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: id, Priority: 2})
+	}
+
+	metrics := &claimMetrics{}
+	if _, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: -1, Max: -1}, claimOptions{Metrics: metrics}); err != nil {
+		t.Fatalf("autoClaimWanted() error: %v", err)
+	}
+	if want := (claimMetrics{Attempted: 3, Won: 1, LostRace: 2}); *metrics != want {
+		t.Errorf("metrics = %+v, want %+v", *metrics, want)
+	}
+
+	// Refused claims never reach the write and are not attempts, and
+	// neither is re-claiming an item the rig already holds.
+	if _, err := claimWanted(context.Background(), store, "w-3", "other-rig", claimOptions{Metrics: metrics}); err == nil {
+		t.Fatal("claimWanted() on an item claimed by another rig should fail")
+	}
+	if res, err := claimWanted(context.Background(), store, "w-3", "my-rig", claimOptions{Metrics: metrics}); err != nil || !res.AlreadyClaimed {
+		t.Fatalf("claimWanted() on our own claim = %+v, %v; want an already-claimed no-op", res, err)
+	}
+	if metrics.Attempted != 3 {
+		t.Errorf("Attempted = %d after refused and no-op claims, want 3", metrics.Attempted)
+	}
+}
+
+func TestClaimMetrics_FailedWritesAreNotRaces(t *testing.T) {
+	t.Parallel()
+	metrics := &claimMetrics{}
+	metrics.recordWrite(nil)
+	metrics.recordWrite(fmt.Errorf("wanted item %q is %w", "w-1", doltserver.ErrWantedNotOpen))
+	metrics.recordWrite(fmt.Errorf("claim failed: %w", doltserver.ErrWriteQueued))
+	metrics.recordWrite(errors.New("claim failed: exit status 1 (output: i/o timeout)"))
+	if want := (claimMetrics{Attempted: 4, Won: 1, LostRace: 1, Failed: 2}); *metrics != want {
+		t.Errorf("metrics = %+v, want %+v", *metrics, want)
+	}
+}
+
+func TestWriteClaimMetrics_TextfileFormat(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "gt_wl_claim.prom")
+
+	if err := writeClaimMetrics(path, "my-rig", claimMetrics{Attempted: 3, Won: 1, LostRace: 2}); err != nil {
+		t.Fatalf("writeClaimMetrics() error: %v", err)
+	}
+	if err := writeClaimMetrics(path, "my-rig", claimMetrics{Attempted: 2, Won: 1, Failed: 1}); err != nil {
+		t.Fatalf("writeClaimMetrics() second run error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP gt_wl_claims_attempted_total Claim writes attempted after passing preconditions.
+# TYPE gt_wl_claims_attempted_total counter
+gt_wl_claims_attempted_total{rig="my-rig"} 5
+# HELP gt_wl_claims_won_total Claim writes that landed.
+# TYPE gt_wl_claims_won_total counter
+gt_wl_claims_won_total{rig="my-rig"} 2
+# HELP gt_wl_claims_lost_race_total Claim writes lost to another rig.
+# TYPE gt_wl_claims_lost_race_total counter
+gt_wl_claims_lost_race_total{rig="my-rig"} 2
+# HELP gt_wl_claims_failed_total Claim writes that failed for another reason.
+# TYPE gt_wl_claims_failed_total counter
+gt_wl_claims_failed_total{rig="my-rig"} 1
+`
+	if string(data) != want {
+		t.Errorf("metrics file =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestReadClaimMetrics_Invalid(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "bad.prom")
+	if err := os.WriteFile(path, []byte("gt_wl_claims_won_total{rig=\"x\"} lots\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readClaimMetrics(path); err == nil {
+		t.Error("readClaimMetrics() should reject a non-numeric sample")
+	}
+	if err := writeClaimMetrics(path, "x", claimMetrics{Won: 1}); err == nil {
+		t.Error("writeClaimMetrics() should not overwrite a file it cannot read")
+	}
+}
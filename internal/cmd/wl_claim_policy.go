@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// ClaimPolicy is the set of rules a wasteland applies before a claim is
+// written: its settings, tightened by the claim's own options. Each rule is
+// one method listed in claimPolicyRules, so a new guard is added and tested
+// in one place. Rate limiting is not a rule: --throttle delays writes
+// instead of refusing them.
+type ClaimPolicy struct {
+	store doltserver.WLCommonsStore
+	opts  claimOptions
+
+	// Model is the wasteland's status workflow.
+	Model *doltserver.StatusModel
+
+	// Coordinators may claim on behalf of other rigs (roles.coordinators).
+	Coordinators []string
+
+	// RequireDepsClosed refuses items with outstanding dependencies
+	// (claim.require_deps_closed or --require-open-deps-closed).
+	RequireDepsClosed bool
+
+	// ConfirmPriority guards items of this priority or more urgent behind
+	// a confirmation (claim.confirm_priority); -1 guards nothing.
+	ConfirmPriority int
+
+	// MaxOpenClaims caps the items one rig may hold at once
+	// (claim.max_open_claims); 0 is unlimited.
+	MaxOpenClaims int
+}
+
+// loadClaimPolicy reads the claim policy from store's settings and applies
+// opts, the options of the claim being evaluated.
+func loadClaimPolicy(store doltserver.WLCommonsStore, opts claimOptions) (*ClaimPolicy, error) {
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return nil, fmt.Errorf("loading wasteland settings: %w", err)
+	}
+	model, err := doltserver.StatusModelFromSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	p := &ClaimPolicy{
+		store:             store,
+		opts:              opts,
+		Model:             model,
+		Coordinators:      splitCommaList(settings[wlSettingCoordinators]),
+		RequireDepsClosed: opts.RequireDepsClosed || settingBool(settings, wlSettingRequireDepsClosed),
+		ConfirmPriority:   -1,
+	}
+	if n, ok := settingInt(settings, wlSettingConfirmPriority); ok {
+		p.ConfirmPriority = n
+	}
+	if raw := settings[wlSettingMaxOpenClaims]; raw != "" {
+		n, ok := settingInt(settings, wlSettingMaxOpenClaims)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("setting %s must be a non-negative integer, got %q", wlSettingMaxOpenClaims, raw)
+		}
+		p.MaxOpenClaims = n
+	}
+	return p, nil
+}
+
+// claimPolicyRules are evaluated in order; the first refusal stops the
+// claim. Each rule records its outcome on check, and refuses through
+// refuseClaim so the failing step is explained.
+var claimPolicyRules = []func(p *ClaimPolicy, check *claimCheck, town string, item *doltserver.WantedItem) error{
+	(*ClaimPolicy).checkStatus,
+	(*ClaimPolicy).checkOnBehalfOf,
+	(*ClaimPolicy).checkGroup,
+	(*ClaimPolicy).checkOpenClaims,
+	(*ClaimPolicy).checkDependencies,
+	(*ClaimPolicy).checkConfirmPriority,
+}
+
+// Evaluate checks a claim of item by town against every rule without
+// writing. The returned claimCheck is non-nil even on error so callers can
+// explain which step failed.
+func (p *ClaimPolicy) Evaluate(ctx context.Context, town string, item *doltserver.WantedItem) (*claimCheck, error) {
+	check := &claimCheck{Claimant: town}
+	for _, rule := range claimPolicyRules {
+		if err := ctx.Err(); err != nil {
+			return check, err
+		}
+		if err := rule(p, check, town, item); err != nil {
+			return check, err
+		}
+	}
+	return check, nil
+}
+
+// refuseClaim records err as the failing step and returns it.
+func refuseClaim(check *claimCheck, err error) error {
+	check.Steps = append(check.Steps, claimStep{Desc: err.Error()})
+	return err
+}
+
+func (p *ClaimPolicy) checkStatus(check *claimCheck, town string, item *doltserver.WantedItem) error {
+	if item.Status != doltserver.StatusOpen {
+		return refuseClaim(check, fmt.Errorf("wanted item %s is not open (status: %s)", item.ID, item.Status))
+	}
+	check.Steps = append(check.Steps, claimStep{OK: true, Desc: "item is open"})
+	if err := p.Model.CheckTransition(item.ID, item.Status, doltserver.StatusClaimed); err != nil {
+		return refuseClaim(check, err)
+	}
+	return nil
+}
+
+func (p *ClaimPolicy) checkOnBehalfOf(check *claimCheck, town string, item *doltserver.WantedItem) error {
+	partner := p.opts.OnBehalfOf
+	if partner == "" || partner == town {
+		return nil
+	}
+	if !slices.Contains(p.Coordinators, town) {
+		return refuseClaim(check, fmt.Errorf("rig %q is not a coordinator on this wasteland (see setting %s)", town, wlSettingCoordinators))
+	}
+	check.Claimant = partner
+	check.Steps = append(check.Steps, claimStep{OK: true, Desc: fmt.Sprintf("%s is a coordinator and may claim for %s", town, partner)})
+	return nil
+}
+
+func (p *ClaimPolicy) checkGroup(check *claimCheck, town string, item *doltserver.WantedItem) error {
+	group := p.opts.Group
+	if group == "" {
+		return nil
+	}
+	members, err := p.store.QueryGroupMembers(group)
+	if err != nil {
+		return fmt.Errorf("loading members of group %s: %w", group, err)
+	}
+	if !slices.Contains(members, town) {
+		return refuseClaim(check, fmt.Errorf("rig %q is not a member of group %q", town, group))
+	}
+	check.Steps = append(check.Steps, claimStep{OK: true, Desc: fmt.Sprintf("%s is a member of group %s", town, group)})
+	return nil
+}
+
+// checkOpenClaims counts the items the claimant already holds, claimed or
+// in draft, against MaxOpenClaims. Items held through a group count too.
+func (p *ClaimPolicy) checkOpenClaims(check *claimCheck, town string, item *doltserver.WantedItem) error {
+	if p.MaxOpenClaims == 0 {
+		return nil
+	}
+	items, err := p.store.ListWanted(doltserver.WantedFilter{
+		HeldBy:      check.Claimant,
+		Statuses:    []string{doltserver.StatusClaimed, doltserver.StatusDraft},
+		MinPriority: -1,
+		MaxPriority: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("counting open claims: %w", err)
+	}
+	held := len(items)
+	if held >= p.MaxOpenClaims {
+		return refuseClaim(check, fmt.Errorf("rig %q already holds %d open claim(s); the limit is %d (setting %s)",
+			check.Claimant, held, p.MaxOpenClaims, wlSettingMaxOpenClaims))
+	}
+	check.Steps = append(check.Steps, claimStep{OK: true, Desc: fmt.Sprintf("%s holds %d of %d allowed open claim(s)", check.Claimant, held, p.MaxOpenClaims)})
+	return nil
+}
+
+func (p *ClaimPolicy) checkDependencies(check *claimCheck, town string, item *doltserver.WantedItem) error {
+	blockers, err := outstandingBlockers(p.store, item.ID, p.Model)
+	if err != nil {
+		return err
+	}
+	check.Blockers = blockers
+	switch {
+	case len(blockers) > 0 && p.RequireDepsClosed:
+		return refuseClaim(check, fmt.Errorf("wanted item %s has outstanding dependencies: %s", item.ID, formatBlockers(blockers)))
+	case len(blockers) > 0:
+		check.Steps = append(check.Steps, claimStep{OK: true, Warn: true,
+			Desc: fmt.Sprintf("outstanding dependencies %s (allowed; strict mode is off)", formatBlockers(blockers))})
+	default:
+		check.Steps = append(check.Steps, claimStep{OK: true, Desc: "no outstanding dependencies"})
+	}
+	return nil
+}
+
+// checkConfirmPriority never refuses; it marks guarded items as needing
+// confirmation, which the caller asks for before writing.
+func (p *ClaimPolicy) checkConfirmPriority(check *claimCheck, town string, item *doltserver.WantedItem) error {
+	if p.ConfirmPriority < 0 || item.Priority > p.ConfirmPriority {
+		return nil
+	}
+	pri := wlFormatPriority(strconv.Itoa(item.Priority))
+	if p.opts.Confirmed {
+		check.Steps = append(check.Steps, claimStep{OK: true, Desc: fmt.Sprintf("%s item confirmed with --confirm", pri)})
+		return nil
+	}
+	check.NeedsConfirm = true
+	check.Steps = append(check.Steps, claimStep{OK: true, Warn: true,
+		Desc: fmt.Sprintf("%s item needs confirmation (%s=%d)", pri, wlSettingConfirmPriority, p.ConfirmPriority)})
+	return nil
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestClaimPolicy_Rules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		setup func(store *fakeWLCommonsStore)
+		item  doltserver.WantedItem
+		rig   string
+		opts  claimOptions
+
+		wantErr      string
+		wantClaimant string
+		wantBlockers int
+		wantConfirm  bool
+	}{
+		{
+			name:         "open item passes",
+			item:         doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:          "my-rig",
+			wantClaimant: "my-rig",
+		},
+		{
+			name:    "status must be open",
+			item:    doltserver.WantedItem{ID: "w-1", Status: "claimed"},
+			rig:     "my-rig",
+			wantErr: "is not open (status: claimed)",
+		},
+		{
+			name:    "on-behalf-of requires coordinator",
+			item:    doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:     "my-rig",
+			opts:    claimOptions{OnBehalfOf: "partner-rig"},
+			wantErr: "not a coordinator",
+		},
+		{
+			name: "coordinator claims for partner",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingCoordinators] = "hub-rig"
+			},
+			item:         doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:          "hub-rig",
+			opts:         claimOptions{OnBehalfOf: "partner-rig"},
+			wantClaimant: "partner-rig",
+		},
+		{
+			name: "group claim requires membership",
+			setup: func(store *fakeWLCommonsStore) {
+				_ = store.AddGroupMembers("squad", "hub-rig", []string{"partner-rig"})
+			},
+			item:    doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:     "my-rig",
+			opts:    claimOptions{Group: "squad"},
+			wantErr: `not a member of group "squad"`,
+		},
+		{
+			name: "max open claims reached",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingMaxOpenClaims] = "1"
+				_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-held", Title: "Held"})
+				_ = store.ClaimWanted("w-held", "my-rig")
+			},
+			item:    doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:     "my-rig",
+			wantErr: "already holds 1 open claim(s); the limit is 1",
+		},
+		{
+			name: "max open claims counts only the claimant",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingMaxOpenClaims] = "1"
+				_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-held", Title: "Held"})
+				_ = store.ClaimWanted("w-held", "other-rig")
+			},
+			item:         doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:          "my-rig",
+			wantClaimant: "my-rig",
+		},
+		{
+			name: "max open claims counts group claims",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingMaxOpenClaims] = "1"
+				_ = store.AddGroupMembers("squad", "hub-rig", []string{"my-rig", "partner-rig"})
+				_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-held", Title: "Held"})
+				_ = store.ClaimWantedForGroup("w-held", "partner-rig", "squad")
+			},
+			item:    doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:     "my-rig",
+			wantErr: "already holds 1 open claim(s); the limit is 1",
+		},
+		{
+			name: "max open claims must be a number",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingMaxOpenClaims] = "lots"
+			},
+			item:    doltserver.WantedItem{ID: "w-1", Status: "open"},
+			rig:     "my-rig",
+			wantErr: "must be a non-negative integer",
+		},
+		{
+			name: "outstanding dependencies warn by default",
+			setup: func(store *fakeWLCommonsStore) {
+				_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Blocker"})
+			},
+			item:         doltserver.WantedItem{ID: "w-1", Status: "open", DependsOn: []string{"w-dep"}},
+			rig:          "my-rig",
+			wantClaimant: "my-rig",
+			wantBlockers: 1,
+		},
+		{
+			name: "outstanding dependencies refused in strict mode",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingRequireDepsClosed] = "true"
+				_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Blocker"})
+			},
+			item:    doltserver.WantedItem{ID: "w-1", Status: "open", DependsOn: []string{"w-dep"}},
+			rig:     "my-rig",
+			wantErr: "outstanding dependencies: w-dep",
+		},
+		{
+			name: "urgent item needs confirmation",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingConfirmPriority] = "1"
+			},
+			item:         doltserver.WantedItem{ID: "w-1", Status: "open", Priority: 0},
+			rig:          "my-rig",
+			wantClaimant: "my-rig",
+			wantConfirm:  true,
+		},
+		{
+			name: "urgent item confirmed up front",
+			setup: func(store *fakeWLCommonsStore) {
+				store.settings[wlSettingConfirmPriority] = "1"
+			},
+			item:         doltserver.WantedItem{ID: "w-1", Status: "open", Priority: 0},
+			rig:          "my-rig",
+			opts:         claimOptions{Confirmed: true},
+			wantClaimant: "my-rig",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := newFakeWLCommonsStore()
+			if tt.setup != nil {
+				tt.setup(store)
+			}
+			item := tt.item
+			item.Title = "Policy test"
+			if err := store.InsertWanted(&item); err != nil {
+				t.Fatalf("InsertWanted() error: %v", err)
+			}
+
+			policy, err := loadClaimPolicy(store, tt.opts)
+			var check *claimCheck
+			if err == nil {
+				check, err = policy.Evaluate(context.Background(), tt.rig, &item)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() error: %v", err)
+			}
+			if check.Claimant != tt.wantClaimant {
+				t.Errorf("Claimant = %q, want %q", check.Claimant, tt.wantClaimant)
+			}
+			if len(check.Blockers) != tt.wantBlockers {
+				t.Errorf("Blockers = %v, want %d", check.Blockers, tt.wantBlockers)
+			}
+			if check.NeedsConfirm != tt.wantConfirm {
+				t.Errorf("NeedsConfirm = %v, want %v", check.NeedsConfirm, tt.wantConfirm)
+			}
+		})
+	}
+}
+
+func TestClaimPolicy_EvaluateCancelled(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	policy, err := loadClaimPolicy(store, claimOptions{})
+	if err != nil {
+		t.Fatalf("loadClaimPolicy() error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	check, err := policy.Evaluate(ctx, "my-rig", &doltserver.WantedItem{ID: "w-1", Status: "open"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Evaluate() error = %v, want context.Canceled", err)
+	}
+	if check == nil || len(check.Steps) != 0 {
+		t.Errorf("check = %+v, want no steps evaluated", check)
+	}
+}
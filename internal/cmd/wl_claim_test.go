@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
 )
@@ -14,12 +19,12 @@ func TestClaimWanted_Success(t *testing.T) {
 		Title: "Fix auth bug",
 	})
 
-	item, err := claimWanted(store, "w-abc123", "my-rig")
+	res, err := claimWanted(context.Background(), store, "w-abc123", "my-rig", claimOptions{})
 	if err != nil {
 		t.Fatalf("claimWanted() error: %v", err)
 	}
-	if item.Title != "Fix auth bug" {
-		t.Errorf("Title = %q, want %q", item.Title, "Fix auth bug")
+	if res.Item.Title != "Fix auth bug" {
+		t.Errorf("Title = %q, want %q", res.Item.Title, "Fix auth bug")
 	}
 
 	// Verify status was updated in store
@@ -41,18 +46,683 @@ func TestClaimWanted_NotOpen(t *testing.T) {
 		Status: "claimed",
 	})
 
-	_, err := claimWanted(store, "w-abc123", "my-rig")
+	_, err := claimWanted(context.Background(), store, "w-abc123", "my-rig", claimOptions{})
 	if err == nil {
 		t.Fatal("claimWanted() expected error for non-open item")
 	}
 }
 
+func TestClaimWanted_AlreadyClaimedBySelfIsNoOp(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc123", Title: "Fix auth bug"})
+
+	if _, err := claimWanted(context.Background(), store, "w-abc123", "my-rig", claimOptions{}); err != nil {
+		t.Fatalf("first claimWanted() error: %v", err)
+	}
+	before, _ := store.QueryWanted("w-abc123")
+
+	res, err := claimWanted(context.Background(), store, "w-abc123", "my-rig", claimOptions{Note: "retry"})
+	if err != nil {
+		t.Fatalf("retried claimWanted() error: %v", err)
+	}
+	if !res.AlreadyClaimed || res.ClaimedBy != "my-rig" {
+		t.Errorf("retried claim = %+v, want AlreadyClaimed by my-rig", res)
+	}
+	if after, _ := store.QueryWanted("w-abc123"); !after.ClaimedAt.Equal(before.ClaimedAt) {
+		t.Errorf("claimed_at moved from %s to %s; a no-op must not rewrite the claim", before.ClaimedAt, after.ClaimedAt)
+	}
+	if detail, _ := store.QueryWantedDetail("w-abc123"); len(detail.Comments) != 0 {
+		t.Errorf("comments = %v, want none from a no-op claim", detail.Comments)
+	}
+
+	// Another town's claim still refuses.
+	if _, err := claimWanted(context.Background(), store, "w-abc123", "other-rig", claimOptions{}); err == nil {
+		t.Error("claimWanted() by another rig should fail")
+	}
+	// So does a claim for a group when the rig holds it for itself.
+	if _, err := claimWanted(context.Background(), store, "w-abc123", "my-rig", claimOptions{Group: "crew"}); err == nil {
+		t.Error("claimWanted() for a group should not match a personal claim")
+	}
+}
+
+func TestClaimWanted_LostRaceToSelfIsNoOp(t *testing.T) {
+	t.Parallel()
+	store := &racingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), lose: map[string]bool{"w-1": true}, rival: "my-rig"}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Retried"})
+
+	res, err := claimWanted(context.Background(), store, "w-1", "my-rig", claimOptions{})
+	if err != nil || !res.AlreadyClaimed {
+		t.Errorf("claimWanted() = %+v, %v; want a no-op when our own earlier attempt won", res, err)
+	}
+}
+
 func TestClaimWanted_NotFound(t *testing.T) {
 	t.Parallel()
 	store := newFakeWLCommonsStore()
 
-	_, err := claimWanted(store, "w-nonexistent", "my-rig")
+	_, err := claimWanted(context.Background(), store, "w-nonexistent", "my-rig", claimOptions{})
 	if err == nil {
 		t.Fatal("claimWanted() expected error for missing item")
 	}
 }
+
+func TestClaimWanted_OutstandingDepsWarns(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Blocker"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", DependsOn: []string{"w-dep"}})
+
+	res, err := claimWanted(context.Background(), store, "w-main", "my-rig", claimOptions{})
+	if err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if len(res.Blockers) != 1 || res.Blockers[0].ID != "w-dep" {
+		t.Errorf("Blockers = %v, want [w-dep]", res.Blockers)
+	}
+}
+
+func TestClaimWanted_RequireDepsClosedBlocked(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep1", Title: "Blocker one"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep2", Title: "Blocker two", Status: "completed"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", DependsOn: []string{"w-dep1", "w-dep2"}})
+	_ = store.ClaimWanted("w-dep1", "other-rig")
+
+	_, err := claimWanted(context.Background(), store, "w-main", "my-rig", claimOptions{RequireDepsClosed: true})
+	if err == nil {
+		t.Fatal("claimWanted() expected error for outstanding dependency")
+	}
+	if !strings.Contains(err.Error(), "w-dep1 (claimed)") {
+		t.Errorf("error = %q, want outstanding blocker with status", err)
+	}
+	if strings.Contains(err.Error(), "w-dep2") {
+		t.Errorf("error = %q, should not list completed blocker", err)
+	}
+
+	got, _ := store.QueryWanted("w-main")
+	if got.Status != "open" {
+		t.Errorf("Status = %q, want %q (blocked claim must not write)", got.Status, "open")
+	}
+}
+
+func TestClaimWanted_RequireDepsClosedUnblocked(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Blocker", Status: "completed"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", DependsOn: []string{"w-dep"}})
+
+	res, err := claimWanted(context.Background(), store, "w-main", "my-rig", claimOptions{RequireDepsClosed: true})
+	if err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if len(res.Blockers) != 0 {
+		t.Errorf("Blockers = %v, want none", res.Blockers)
+	}
+}
+
+func TestSettingBool(t *testing.T) {
+	t.Parallel()
+	settings := map[string]string{"a": "true", "b": "0", "c": "garbage"}
+	if !settingBool(settings, "a") {
+		t.Error("settingBool(a) = false, want true")
+	}
+	for _, key := range []string{"b", "c", "missing"} {
+		if settingBool(settings, key) {
+			t.Errorf("settingBool(%s) = true, want false", key)
+		}
+	}
+}
+
+func TestClaimWanted_RequireDepsClosedFromSetting(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingRequireDepsClosed] = "true"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Blocker"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", DependsOn: []string{"w-dep"}})
+
+	if _, err := claimWanted(context.Background(), store, "w-main", "my-rig", claimOptions{}); err == nil {
+		t.Fatal("claimWanted() expected error when wasteland requires closed deps")
+	}
+}
+
+func TestClaimWanted_CustomClosedStatuses(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingRequireDepsClosed] = "true"
+	store.settings[doltserver.SettingWorkflowClosed] = "completed,in_review"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Blocker", Status: "in_review"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-main", Title: "Main", DependsOn: []string{"w-dep"}})
+
+	if _, err := claimWanted(context.Background(), store, "w-main", "my-rig", claimOptions{}); err != nil {
+		t.Fatalf("claimWanted() error = %v; in_review is closed under this model", err)
+	}
+}
+
+func TestClaimWanted_InvalidStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[doltserver.SettingWorkflowTransitions] = "open>archived"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix bug"})
+
+	if _, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{}); err == nil || !strings.Contains(err.Error(), "unknown status") {
+		t.Fatalf("claimWanted() error = %v, want invalid model error", err)
+	}
+}
+
+func TestClaimWanted_OnBehalfOfCoordinator(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingCoordinators] = "hub-rig, other-hub"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Delegated"})
+
+	res, err := claimWanted(context.Background(), store, "w-abc", "hub-rig", claimOptions{OnBehalfOf: "partner-rig"})
+	if err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if res.ClaimedBy != "partner-rig" {
+		t.Errorf("ClaimedBy = %q, want %q", res.ClaimedBy, "partner-rig")
+	}
+
+	got, _ := store.QueryWanted("w-abc")
+	if got.ClaimedBy != "partner-rig" {
+		t.Errorf("stored ClaimedBy = %q, want %q", got.ClaimedBy, "partner-rig")
+	}
+	if got.ClaimedVia != "hub-rig" {
+		t.Errorf("stored ClaimedVia = %q, want %q", got.ClaimedVia, "hub-rig")
+	}
+}
+
+func TestClaimWanted_GroupMembersCanComplete(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.AddGroupMembers("auth-squad", "hub-rig", []string{"my-rig", "partner-rig"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Rewrite auth"})
+
+	if _, err := claimWanted(context.Background(), store, "w-abc", "outsider", claimOptions{Group: "auth-squad"}); err == nil || !strings.Contains(err.Error(), "not a member") {
+		t.Fatalf("claimWanted(context.Background(), outsider) error = %v, want membership error", err)
+	}
+
+	res, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{Group: "auth-squad"})
+	if err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if res.Group != "auth-squad" || res.ClaimedBy != "my-rig" {
+		t.Errorf("result = group %q claimed_by %q, want auth-squad/my-rig", res.Group, res.ClaimedBy)
+	}
+	got, _ := store.QueryWanted("w-abc")
+	if got.ClaimedGroup != "auth-squad" {
+		t.Errorf("stored ClaimedGroup = %q, want auth-squad", got.ClaimedGroup)
+	}
+
+	if err := submitDone(store, "w-abc", "outsider", "https://example.com/pr/1", "c-outsider"); err == nil {
+		t.Error("submitDone() by a non-member should fail")
+	}
+	if err := submitDone(store, "w-abc", "partner-rig", "https://example.com/pr/1", "c-partner"); err != nil {
+		t.Fatalf("submitDone() by another member error: %v", err)
+	}
+	if got, _ := store.QueryWanted("w-abc"); got.Status != doltserver.StatusInReview {
+		t.Errorf("status = %q, want %q", got.Status, doltserver.StatusInReview)
+	}
+}
+
+func TestClaimWanted_OnBehalfOfRequiresCoordinator(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Delegated"})
+
+	_, err := claimWanted(context.Background(), store, "w-abc", "random-rig", claimOptions{OnBehalfOf: "partner-rig"})
+	if err == nil {
+		t.Fatal("claimWanted() expected error for non-coordinator")
+	}
+	if !strings.Contains(err.Error(), "not a coordinator") {
+		t.Errorf("error = %q, want coordinator message", err)
+	}
+
+	got, _ := store.QueryWanted("w-abc")
+	if got.Status != "open" {
+		t.Errorf("Status = %q, want %q", got.Status, "open")
+	}
+}
+
+func TestPriorityBandValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		band    priorityBand
+		wantErr bool
+	}{
+		{priorityBand{Min: -1, Max: -1}, false},
+		{priorityBand{Min: 1, Max: -1}, false},
+		{priorityBand{Min: 1, Max: 1}, false},
+		{priorityBand{Min: 1, Max: 3}, false},
+		{priorityBand{Min: 3, Max: 1}, true},
+		{priorityBand{Min: -2, Max: -1}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.band.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.validate() error = %v, wantErr %v", tt.band, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAutoClaimWanted_RespectsPriorityBand(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, item := range []*doltserver.WantedItem{
+		{ID: "w-p0", Title: "Scary", Priority: 0},
+		{ID: "w-p2", Title: "Medium", Priority: 2},
+		{ID: "w-p1", Title: "Moderate", Priority: 1},
+		{ID: "w-p4", Title: "Backlog", Priority: 4},
+	} {
+		_ = store.InsertWanted(item)
+	}
+
+	res, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: 1, Max: 3}, claimOptions{})
+	if err != nil {
+		t.Fatalf("autoClaimWanted() error: %v", err)
+	}
+	if res.Item.ID != "w-p1" {
+		t.Errorf("claimed %s, want w-p1 (highest priority within band)", res.Item.ID)
+	}
+	if p0, _ := store.QueryWanted("w-p0"); p0.Status != "open" {
+		t.Errorf("w-p0 status = %q, want open (outside band)", p0.Status)
+	}
+}
+
+func TestPreferOwnPosts_Ordering(t *testing.T) {
+	t.Parallel()
+	candidates := []*doltserver.WantedItem{
+		{ID: "w-p1-other", Priority: 1, PostedBy: "other"},
+		{ID: "w-p2-other-old", Priority: 2, PostedBy: "other"},
+		{ID: "w-p2-mine-old", Priority: 2, PostedBy: "my-rig"},
+		{ID: "w-p2-other-new", Priority: 2, PostedBy: "other"},
+		{ID: "w-p2-mine-new", Priority: 2, PostedBy: "my-rig"},
+		{ID: "w-p3-mine", Priority: 3, PostedBy: "my-rig"},
+	}
+	preferOwnPosts(candidates, "my-rig")
+
+	want := []string{"w-p1-other", "w-p2-mine-old", "w-p2-mine-new", "w-p2-other-old", "w-p2-other-new", "w-p3-mine"}
+	for i, c := range candidates {
+		if c.ID != want[i] {
+			t.Fatalf("order = %v, want %v", wantedIDs(candidates), want)
+		}
+	}
+}
+
+func TestAutoClaimWanted_PreferOwnPosts(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-theirs", Title: "Theirs", Priority: 2, PostedBy: "other"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-mine", Title: "Mine", Priority: 2, PostedBy: "my-rig"})
+
+	res, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: -1, Max: -1}, claimOptions{PreferOwnPosts: true})
+	if err != nil {
+		t.Fatalf("autoClaimWanted() error: %v", err)
+	}
+	if res.Item.ID != "w-mine" {
+		t.Errorf("claimed %s, want w-mine", res.Item.ID)
+	}
+}
+
+// racingClaimStore loses the claim on IDs in lose to a rival rig, as if
+// another town claimed the item between listing and claiming.
+type racingClaimStore struct {
+	*fakeWLCommonsStore
+	lose map[string]bool
+	// rival is the rig whose claim lands first; empty means "rival-rig".
+	rival string
+}
+
+func (s *racingClaimStore) ClaimWanted(wantedID, rigHandle string) error {
+	if s.lose[wantedID] {
+		rival := s.rival
+		if rival == "" {
+			rival = "rival-rig"
+		}
+		_ = s.fakeWLCommonsStore.ClaimWanted(wantedID, rival)
+	}
+	return s.fakeWLCommonsStore.ClaimWanted(wantedID, rigHandle)
+}
+
+func TestClaimWanted_LostRaceNamesWinner(t *testing.T) {
+	t.Parallel()
+	store := &racingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), lose: map[string]bool{"w-1": true}}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Contested"})
+
+	// The item passes the open check, then a rival's UPDATE lands first and
+	// ours matches zero rows.
+	res, err := claimWanted(context.Background(), store, "w-1", "my-rig", claimOptions{})
+	if err == nil {
+		t.Fatalf("claimWanted() = %+v, want an error for the lost race", res)
+	}
+	if !isClaimConflict(err) || !strings.Contains(err.Error(), "already claimed by another town (rival-rig)") {
+		t.Errorf("error = %v, want a conflict naming rival-rig", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.ClaimedBy != "rival-rig" {
+		t.Errorf("claimed by %q, want the rival's claim untouched", item.ClaimedBy)
+	}
+}
+
+func TestAutoClaimWanted_MaxAttemptsSkipsLostRaces(t *testing.T) {
+	t.Parallel()
+	newStore := func() *racingClaimStore {
+		store := &racingClaimStore{fakeWLCommonsStore: newFakeWLCommonsStore(), lose: map[string]bool{"w-1": true, "w-2": true}}
+		for _, id := range []string{"w-1", "w-2", "w-3"} {
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: id, Priority: 2})
+		}
+		return store
+	}
+	anyBand := priorityBand{Min: -1, Max: -1}
+
+	res, err := autoClaimWanted(context.Background(), newStore(), "my-rig", anyBand, claimOptions{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("autoClaimWanted() error: %v", err)
+	}
+	if res.Item.ID != "w-3" {
+		t.Errorf("claimed %s, want w-3 after losing w-1 and w-2", res.Item.ID)
+	}
+
+	store := newStore()
+	_, err = autoClaimWanted(context.Background(), store, "my-rig", anyBand, claimOptions{MaxAttempts: 2})
+	if !errors.Is(err, errNoClaimableWork) {
+		t.Fatalf("autoClaimWanted(context.Background(), max 2) error = %v, want errNoClaimableWork", err)
+	}
+	if !strings.Contains(err.Error(), "after 2 attempt(s)") {
+		t.Errorf("error = %q, want attempt count", err)
+	}
+	if w3, _ := store.QueryWanted("w-3"); w3.Status != doltserver.StatusOpen {
+		t.Errorf("w-3 status = %q, want open (beyond --max-attempts)", w3.Status)
+	}
+}
+
+func wantedIDs(items []*doltserver.WantedItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestAutoClaimWanted_NoneInBand(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-p0", Title: "Scary", Priority: 0})
+
+	_, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: 1, Max: -1}, claimOptions{})
+	if err == nil || !strings.Contains(err.Error(), "priority >= 1") {
+		t.Fatalf("autoClaimWanted() error = %v, want no items with priority >= 1", err)
+	}
+}
+
+func TestAutoClaimWanted_TagMatch(t *testing.T) {
+	t.Parallel()
+	newStore := func() *fakeWLCommonsStore {
+		store := newFakeWLCommonsStore()
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-go", Title: "Go only", Priority: 1, Tags: []string{"go"}})
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-both", Title: "Go and SQL", Priority: 2, Tags: []string{"go", "sql"}})
+		return store
+	}
+	band := priorityBand{Min: -1, Max: -1}
+
+	res, err := autoClaimWanted(context.Background(), newStore(), "my-rig", band, claimOptions{Tags: []string{"sql", "go"}})
+	if err != nil || res.Item.ID != "w-go" {
+		t.Fatalf("any: autoClaimWanted() = %v, %v; want w-go", res, err)
+	}
+	res, err = autoClaimWanted(context.Background(), newStore(), "my-rig", band, claimOptions{Tags: []string{"sql", "go"}, TagsMatchAll: true})
+	if err != nil || res.Item.ID != "w-both" {
+		t.Fatalf("all: autoClaimWanted() = %v, %v; want w-both", res, err)
+	}
+	_, err = autoClaimWanted(context.Background(), newStore(), "my-rig", band, claimOptions{Tags: []string{"go", "rust"}, TagsMatchAll: true})
+	if err == nil || !strings.Contains(err.Error(), "tagged go and rust") {
+		t.Fatalf("autoClaimWanted() error = %v, want no items tagged go and rust", err)
+	}
+}
+
+func TestAutoClaimWanted_NewestFirst(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-urgent", Title: "Old and urgent", Priority: 0, Tags: []string{"go"}, CreatedAt: base})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-fresh", Title: "Just posted", Priority: 3, Tags: []string{"go"}, CreatedAt: base.Add(2 * time.Hour)})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-fresher", Title: "Newer, other tag", Priority: 3, Tags: []string{"sql"}, CreatedAt: base.Add(3 * time.Hour)})
+	band := priorityBand{Min: -1, Max: -1}
+
+	res, err := autoClaimWanted(context.Background(), store, "my-rig", band, claimOptions{NewestFirst: true, Tags: []string{"go"}})
+	if err != nil {
+		t.Fatalf("autoClaimWanted() error: %v", err)
+	}
+	if res.Item.ID != "w-fresh" {
+		t.Errorf("claimed %s, want the newest go item w-fresh", res.Item.ID)
+	}
+	if !res.Item.CreatedAt.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("CreatedAt = %v, want the listing's creation time", res.Item.CreatedAt)
+	}
+}
+
+func TestTagMatchAll(t *testing.T) {
+	t.Parallel()
+	if all, err := tagMatchAll([]string{"go"}, false, false); err != nil || all {
+		t.Errorf("default = %v, %v; want any", all, err)
+	}
+	if all, err := tagMatchAll([]string{"go"}, false, true); err != nil || !all {
+		t.Errorf("--tag-all = %v, %v; want all", all, err)
+	}
+	if _, err := tagMatchAll([]string{"go"}, true, true); err == nil {
+		t.Error("--tag-any with --tag-all should fail")
+	}
+	if _, err := tagMatchAll(nil, false, true); err == nil {
+		t.Error("--tag-all without --tag should fail")
+	}
+}
+
+func TestClaimWanted_ConfirmPriority(t *testing.T) {
+	t.Parallel()
+	newStore := func() *fakeWLCommonsStore {
+		store := newFakeWLCommonsStore()
+		store.settings[wlSettingConfirmPriority] = "1"
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-p1", Title: "Critical", Priority: 1})
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-p2", Title: "Routine", Priority: 2})
+		return store
+	}
+	yes := func(*doltserver.WantedItem) bool { return true }
+	no := func(*doltserver.WantedItem) bool { return false }
+
+	tests := []struct {
+		name    string
+		id      string
+		opts    claimOptions
+		wantErr string
+	}{
+		{"below threshold needs nothing", "w-p2", claimOptions{}, ""},
+		{"non-interactive requires --confirm", "w-p1", claimOptions{}, "pass --confirm"},
+		{"--confirm bypasses prompt", "w-p1", claimOptions{Confirmed: true, Confirm: no}, ""},
+		{"prompt accepted", "w-p1", claimOptions{Confirm: yes}, ""},
+		{"prompt declined", "w-p1", claimOptions{Confirm: no}, "claim cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore()
+			_, err := claimWanted(context.Background(), store, tt.id, "my-rig", tt.opts)
+			item, _ := store.QueryWanted(tt.id)
+			if tt.wantErr == "" {
+				if err != nil || item.Status != "claimed" {
+					t.Fatalf("claimWanted() = %v, status %q; want claimed", err, item.Status)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("claimWanted() error = %v, want %q", err, tt.wantErr)
+			}
+			if item.Status != "open" {
+				t.Errorf("status = %q, want open (nothing written)", item.Status)
+			}
+		})
+	}
+}
+
+func TestAutoClaimWanted_StopsWhenConfirmDeclined(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingConfirmPriority] = "0"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-p0", Title: "Scary", Priority: 0})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-p2", Title: "Medium", Priority: 2})
+
+	opts := claimOptions{Confirm: func(*doltserver.WantedItem) bool { return false }}
+	if _, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: -1, Max: -1}, opts); !errors.Is(err, errClaimCancelled) {
+		t.Fatalf("autoClaimWanted() error = %v, want errClaimCancelled", err)
+	}
+	if item, _ := store.QueryWanted("w-p2"); item.Status != "open" {
+		t.Errorf("w-p2 status = %q, want open (declining must not fall through)", item.Status)
+	}
+}
+
+func TestResolveWantedByTitle(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, item := range []*doltserver.WantedItem{
+		{ID: "w-login", Title: "Fix the login bug"},
+		{ID: "w-dup1", Title: "Write docs"},
+		{ID: "w-dup2", Title: "Write docs"},
+		{ID: "w-done", Title: "Ship it", Status: "completed"},
+	} {
+		_ = store.InsertWanted(item)
+	}
+
+	if id, err := resolveWantedByTitle(store, "Fix the login bug"); err != nil || id != "w-login" {
+		t.Errorf("unique title = %q, %v; want w-login", id, err)
+	}
+	if _, err := resolveWantedByTitle(store, "Write docs"); err == nil || !strings.Contains(err.Error(), "w-dup1, w-dup2") {
+		t.Errorf("ambiguous title error = %v, want candidate IDs", err)
+	}
+	if _, err := resolveWantedByTitle(store, "Ship it"); err == nil || !strings.Contains(err.Error(), "no open wanted item") {
+		t.Errorf("non-open title error = %v, want not found", err)
+	}
+	if _, err := resolveWantedByTitle(store, "fix the login bug"); err == nil {
+		t.Error("title match should be exact")
+	}
+}
+
+func TestClaimWanted_RecordsLabels(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix bug"})
+
+	labels, err := parseClaimLabels([]string{"ticket=OPS-142", "sprint = 2026-10"})
+	if err != nil {
+		t.Fatalf("parseClaimLabels() error: %v", err)
+	}
+	if _, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{Labels: labels}); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	detail, _ := store.QueryWantedDetail("w-abc")
+	want := []doltserver.WantedLabel{
+		{Key: "sprint", Value: "2026-10", SetBy: "my-rig"},
+		{Key: "ticket", Value: "OPS-142", SetBy: "my-rig"},
+	}
+	if !reflect.DeepEqual(detail.Labels, want) {
+		t.Errorf("Labels = %+v, want %+v", detail.Labels, want)
+	}
+}
+
+func TestParseClaimLabels_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, raw := range [][]string{
+		{"ticket"},
+		{"=value"},
+		{"ticket="},
+		{"Ticket=1"},
+		{"gt.owner=me"},
+		{"delivery-acked-by=me"},
+		{"ticket=1", "ticket=2"},
+	} {
+		if _, err := parseClaimLabels(raw); err == nil {
+			t.Errorf("parseClaimLabels(%q) expected error", raw)
+		}
+	}
+}
+
+func TestClaimWanted_RecordsNote(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc123", Title: "Fix auth bug"})
+
+	if _, err := claimWanted(context.Background(), store, "w-abc123", "my-rig", claimOptions{Note: "Starting with the token path"}); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	got := store.comments["w-abc123"]
+	if len(got) != 1 || got[0].Author != "my-rig" || got[0].Body != "Starting with the token path" {
+		t.Errorf("comments = %v, want claim note", got)
+	}
+}
+
+func TestClaimOutputTemplate(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingCoordinators] = "coord"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Dep"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc123", Title: "Fix auth bug", DependsOn: []string{"w-dep"}})
+
+	tmpl, err := parseClaimOutputTemplate(`{{.ID}}	{{.Title}}	{{.Status}}	{{.ClaimedBy}} via={{.ClaimedVia}} blockers={{range .Blockers}}{{.}}{{end}}`)
+	if err != nil {
+		t.Fatalf("parseClaimOutputTemplate() error: %v", err)
+	}
+	res, err := claimWanted(context.Background(), store, "w-abc123", "coord", claimOptions{OnBehalfOf: "partner"})
+	if err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := renderClaimOutputTemplate(&buf, tmpl, res, "coord"); err != nil {
+		t.Fatalf("renderClaimOutputTemplate() error: %v", err)
+	}
+	want := "w-abc123\tFix auth bug\tclaimed\tpartner via=coord blockers=w-dep\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseClaimOutputTemplate_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, text := range []string{"{{.ID", "{{.Nope}}", `{{join .Blockers ","}}`} {
+		if _, err := parseClaimOutputTemplate(text); err == nil || !strings.Contains(err.Error(), "invalid --output-template") {
+			t.Errorf("parseClaimOutputTemplate(%q) error = %v, want invalid --output-template", text, err)
+		}
+	}
+}
+
+func TestClaimWanted_LockHeldByOtherTown(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Locked"})
+	if err := store.AcquireClaimLock("w-1", "rival", time.Minute); err != nil {
+		t.Fatalf("AcquireClaimLock() error: %v", err)
+	}
+
+	_, err := claimWanted(context.Background(), store, "w-1", "my-rig", claimOptions{LockTTL: time.Minute})
+	if !doltserver.IsClaimLockHeld(err) || !strings.Contains(err.Error(), "locked by rival until") {
+		t.Fatalf("claimWanted() error = %v, want locked by rival", err)
+	}
+	if !isClaimConflict(err) {
+		t.Error("a held lock should count as a conflict for --on-conflict")
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != "open" {
+		t.Errorf("Status = %q, want the item left open", item.Status)
+	}
+}
+
+func TestClaimWanted_LockReleasedAfterClaim(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Locked"})
+
+	if _, err := claimWanted(context.Background(), store, "w-1", "my-rig", claimOptions{LockTTL: time.Minute}); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.ClaimedBy != "my-rig" {
+		t.Errorf("ClaimedBy = %q, want my-rig", item.ClaimedBy)
+	}
+	if err := store.AcquireClaimLock("w-1", "rival", time.Minute); err != nil {
+		t.Errorf("lock not released after the claim: %v", err)
+	}
+}
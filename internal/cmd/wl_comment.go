@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+var (
+	wlCommentNote string
+	wlCommentEdit bool
+)
+
+var wlCommentCmd = &cobra.Command{
+	Use:   "comment <wanted-id>",
+	Short: "Comment on a wanted item",
+	Long: `Add a comment to a wanted item's history.
+
+Comments are coordination notes visible to every rig on the wasteland.
+Pass the text with --note, or use --edit to write it in $EDITOR. An empty
+editor buffer aborts without writing anything.
+
+Examples:
+  gt wl comment w-abc123 --note "Taking the parser half; auth is free"
+  gt wl comment w-abc123 --edit`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlComment,
+}
+
+func init() {
+	wlCommentCmd.Flags().StringVar(&wlCommentNote, "note", "", "Comment text")
+	wlCommentCmd.Flags().BoolVar(&wlCommentEdit, "edit", false, "Write the comment in $EDITOR")
+
+	wlCmd.AddCommand(wlCommentCmd)
+}
+
+func runWlComment(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+
+	if wlCommentNote == "" && !wlCommentEdit {
+		return fmt.Errorf("provide the comment with --note or --edit")
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	body, err := resolveNote(wlCommentNote, wlCommentEdit, "comment", wantedID)
+	if err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	if err := commentWanted(store, wantedID, wlCfg.RigHandle, body); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Commented on %s\n", wlSuccessMark(), wantedID)
+	return nil
+}
+
+// commentWanted contains the testable business logic for commenting.
+func commentWanted(store doltserver.WLCommonsStore, wantedID, rigHandle, body string) error {
+	if err := store.AddComment(wantedID, rigHandle, body); err != nil {
+		return fmt.Errorf("adding comment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestCommentWanted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc123", Title: "Fix auth bug"})
+
+	if err := commentWanted(store, "w-abc123", "my-rig", "Blocked on review"); err != nil {
+		t.Fatalf("commentWanted() error: %v", err)
+	}
+	if got := store.comments["w-abc123"]; len(got) != 1 || got[0].Author != "my-rig" || got[0].Body != "Blocked on review" {
+		t.Errorf("comments = %v", got)
+	}
+}
+
+func TestCommentWanted_NotFound(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	if err := commentWanted(store, "w-missing", "my-rig", "hello"); err == nil {
+		t.Error("commentWanted() expected error for missing item")
+	}
+}
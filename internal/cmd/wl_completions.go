@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlCompletionsCmd = &cobra.Command{
+	Use:   "completions <wanted-id>",
+	Short: "List the completion history of a wanted item",
+	Long: `List every completion submitted for a wanted item, oldest first.
+
+Completions replaced via gt wl done --supersede are dimmed and marked with
+the completion that superseded them; the current completion is marked
+"current".
+
+--verify also checks that each completion's evidence link still resolves
+(see gt wl audit-evidence), and exits non-zero when one is dead.
+
+Examples:
+  gt wl completions w-abc123
+  gt wl completions w-abc123 --verify`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlCompletions,
+}
+
+var wlCompletionsVerify bool
+
+func init() {
+	wlCompletionsCmd.Flags().BoolVar(&wlCompletionsVerify, "verify", false, "Check that each evidence URL still resolves")
+	addEvidenceVerifyFlags(wlCompletionsCmd)
+
+	wlCmd.AddCommand(wlCompletionsCmd)
+}
+
+func runWlCompletions(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+
+	if len(detail.Completions) == 0 {
+		fmt.Printf("No completions for %s.\n", wantedID)
+		return nil
+	}
+
+	fmt.Printf("Completions for %s (%d):\n\n", wantedID, len(detail.Completions))
+	fmt.Print(buildCompletionsTable(detail.Completions).Render())
+
+	if wlCompletionsVerify {
+		fmt.Printf("\nEvidence links:\n")
+		return verifyAndReportEvidence(cmd.Context(), os.Stdout, detail.Completions)
+	}
+	return nil
+}
+
+// buildCompletionsTable renders completion history, dimming superseded rows.
+func buildCompletionsTable(completions []doltserver.WantedCompletion) *style.Table {
+	tbl := style.NewTable(
+		style.Column{Name: "ID", Width: 20},
+		style.Column{Name: "BY", Width: 16},
+		style.Column{Name: "COMPLETED", Width: 19},
+		style.Column{Name: "STATE", Width: 32},
+		style.Column{Name: "EVIDENCE", Width: 50},
+	)
+	for _, c := range completions {
+		state := "current"
+		if c.SupersededBy != "" {
+			state = "superseded by " + c.SupersededBy
+		}
+		row := []string{c.ID, c.CompletedBy, c.CompletedAt, state, c.Evidence}
+		if c.SupersededBy != "" {
+			for i := range row {
+				row[i] = style.Dim.Render(row[i])
+			}
+		}
+		tbl.AddRow(row...)
+	}
+	return tbl
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestBuildCompletionsTable_MarksSuperseded(t *testing.T) {
+	t.Parallel()
+	out := buildCompletionsTable([]doltserver.WantedCompletion{
+		{ID: "c-old", CompletedBy: "my-rig", Evidence: "pr/1", SupersededBy: "c-new"},
+		{ID: "c-new", CompletedBy: "my-rig", Evidence: "pr/2"},
+	}).Render()
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header, separator, 2 rows:\n%s", len(lines), out)
+	}
+	if !strings.Contains(lines[2], "c-old") || !strings.Contains(lines[2], "superseded by c-new") {
+		t.Errorf("superseded row = %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "c-new") || !strings.Contains(lines[3], "current") {
+		t.Errorf("current row = %q", lines[3])
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// wlRunContext caches what one gt wl invocation learns about its town: the
+// workspace root, Dolt and wasteland config, which databases exist, and
+// whether the commons schema is usable. Each is resolved on first use.
+type wlRunContext struct {
+	root     string
+	rootErr  error
+	rootDone bool
+
+	// townFile and handleFallback let a command run outside a workspace
+	// on a town handle; handle is set when it did. See allowTownHandle.
+	townFile       string
+	handleFallback bool
+	handle         string
+
+	dolt *doltserver.Config
+
+	wlCfg  *wasteland.Config
+	wlErr  error
+	wlDone bool
+
+	// databases records the databases seen to exist. A missing one is
+	// looked up again, since gt wl join and --ensure-joined create it
+	// mid-run.
+	databases map[string]bool
+
+	// migrateSchema and validateSchema upgrade and introspect the
+	// commons; tests replace them.
+	migrateSchema  func(townRoot string) ([]string, error)
+	validateSchema func(townRoot string) ([]doltserver.SchemaIssue, error)
+	schemaErr      error
+	schemaDone     bool
+}
+
+// wlRun is the context of the running gt wl command. wlPersistentPreRun
+// replaces it at the start of each invocation.
+var wlRun = newWlRunContext()
+
+func newWlRunContext() *wlRunContext {
+	return &wlRunContext{
+		databases:      make(map[string]bool),
+		migrateSchema:  doltserver.MigrateWLCommons,
+		validateSchema: doltserver.ValidateWLCommonsSchema,
+	}
+}
+
+// allowTownHandle lets the command run outside a workspace, as in a
+// container or CI job without a full town. There the handle is read from
+// townFile or, failing that, $GASTOWN_TOWN, and the working directory
+// stands in for the town root: its .dolt-data must hold the wl-commons
+// database. Call it before the town is first resolved.
+func (c *wlRunContext) allowTownHandle(townFile string) {
+	c.townFile, c.handleFallback = townFile, true
+}
+
+// townRoot returns the workspace the command runs in.
+func (c *wlRunContext) townRoot() (string, error) {
+	if !c.rootDone {
+		c.root, c.rootErr = c.resolveTownRoot()
+		c.rootDone = true
+	}
+	return c.root, c.rootErr
+}
+
+func (c *wlRunContext) resolveTownRoot() (string, error) {
+	townRoot, findErr := findWlTownRoot()
+	if findErr == nil {
+		return townRoot, nil
+	}
+	if !c.handleFallback {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", findErr)
+	}
+	handle, err := wlTownHandleFallback(c.townFile)
+	if err != nil {
+		return "", err
+	}
+	cwd, cwdErr := os.Getwd()
+	if handle == "" || cwdErr != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", findErr)
+	}
+	c.handle = handle
+	return cwd, nil
+}
+
+// doltConfig returns the town's Dolt server config.
+func (c *wlRunContext) doltConfig() (*doltserver.Config, error) {
+	if c.dolt == nil {
+		townRoot, err := c.townRoot()
+		if err != nil {
+			return nil, err
+		}
+		c.dolt = doltserver.DefaultConfig(townRoot)
+	}
+	return c.dolt, nil
+}
+
+// wastelandConfig returns the town's wasteland config. Callers share it,
+// so they must not modify it. A town run on a handle has no config on
+// disk, so only its handle is set.
+func (c *wlRunContext) wastelandConfig() (*wasteland.Config, error) {
+	if !c.wlDone {
+		townRoot, err := c.townRoot()
+		if err != nil {
+			return nil, err
+		}
+		if c.handle != "" {
+			c.wlCfg = &wasteland.Config{RigHandle: c.handle}
+		} else {
+			c.wlCfg, c.wlErr = wasteland.LoadConfig(townRoot)
+			if c.wlErr != nil {
+				c.wlErr = fmt.Errorf("loading wasteland config: %w", c.wlErr)
+			}
+		}
+		c.wlDone = true
+	}
+	return c.wlCfg, c.wlErr
+}
+
+// setWastelandConfig records cfg as the town's wasteland config, for a
+// command that has just joined a wasteland.
+func (c *wlRunContext) setWastelandConfig(cfg *wasteland.Config) {
+	c.wlCfg, c.wlErr, c.wlDone = cfg, nil, true
+}
+
+// commonsExists reports whether the active wl-commons database exists in
+// the town. Outside a workspace it does not.
+func (c *wlRunContext) commonsExists() bool {
+	db := doltserver.WLCommonsDB
+	if c.databases[db] {
+		return true
+	}
+	townRoot, err := c.townRoot()
+	if err != nil || !doltserver.DatabaseExists(townRoot, db) {
+		return false
+	}
+	c.databases[db] = true
+	return true
+}
+
+// requireCommons fails unless the command runs in a workspace that has
+// joined a wasteland whose schema gt can use.
+func (c *wlRunContext) requireCommons() error {
+	if err := c.requireJoined(); err != nil {
+		return err
+	}
+	townRoot, _ := c.townRoot()
+	return c.checkSchema(townRoot)
+}
+
+// requireCommonsRead is requireCommons for commands that only read. A
+// schema that still lacks columns after migrating is a warning rather than
+// an error, since reads of what the commons does have keep working.
+func (c *wlRunContext) requireCommonsRead() error {
+	if err := c.requireJoined(); err != nil {
+		return err
+	}
+	townRoot, _ := c.townRoot()
+	if err := c.checkSchema(townRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	return nil
+}
+
+// requireJoined fails unless the command runs in a workspace that has
+// joined a wasteland, pointing at gt wl join otherwise.
+func (c *wlRunContext) requireJoined() error {
+	if _, err := c.townRoot(); err != nil {
+		return err
+	}
+	if !c.commonsExists() {
+		return fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	}
+	return nil
+}
+
+// checkSchema first migrates a commons created by an older gt to the
+// current schema version, then fails when it still lacks tables or columns
+// this gt relies on, naming them, so the command fails up front rather than
+// with a raw SQL error mid-command. It runs once per invocation. If the
+// schema cannot be read the command goes ahead and reports its own error.
+// Under --dry-run nothing is migrated.
+func (c *wlRunContext) checkSchema(townRoot string) error {
+	if !c.schemaDone {
+		if !doltserver.WLDryRun() {
+			if applied, err := c.migrateSchema(townRoot); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			} else if len(applied) > 0 {
+				fmt.Fprintf(os.Stderr, "Migrated wl-commons schema to v%s\n", applied[len(applied)-1])
+			}
+		}
+		if issues, err := c.validateSchema(townRoot); err == nil && len(issues) > 0 {
+			c.schemaErr = schemaIncompatibleError(issues)
+		}
+		c.schemaDone = true
+	}
+	return c.schemaErr
+}
+
+// schemaIncompatibleError names what the commons is missing and points at
+// gt wl validate, which prints the SQL that adds it.
+func schemaIncompatibleError(issues []doltserver.SchemaIssue) error {
+	missing := make([]string, len(issues))
+	for i, issue := range issues {
+		if issue.Column == "" {
+			missing[i] = "table " + issue.Table
+		} else {
+			missing[i] = issue.Table + "." + issue.Column
+		}
+	}
+	return fmt.Errorf("wl-commons schema is older than this gt expects; missing %s\nRun 'gt wl validate' for the SQL that adds them", strings.Join(missing, ", "))
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// wlRunContextAt returns a context whose workspace lookup has already
+// resolved to townRoot.
+func wlRunContextAt(townRoot string) *wlRunContext {
+	c := newWlRunContext()
+	c.root, c.rootDone = townRoot, true
+	c.migrateSchema = func(string) ([]string, error) { return nil, nil }
+	c.validateSchema = func(string) ([]doltserver.SchemaIssue, error) { return nil, nil }
+	return c
+}
+
+func TestWlRunContext_RequireCommons(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	c := wlRunContextAt(townRoot)
+
+	err := c.requireCommons()
+	if err == nil || !strings.Contains(err.Error(), "gt wl join") {
+		t.Fatalf("requireCommons() without a database = %v, want a hint at gt wl join", err)
+	}
+
+	// A database created mid-run, as by gt wl join, is seen.
+	dbDir := filepath.Join(townRoot, ".dolt-data", doltserver.WLCommonsDB, ".dolt")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.requireCommons(); err != nil {
+		t.Fatalf("requireCommons() after creating the database: %v", err)
+	}
+
+	// Once seen, it is not looked up again.
+	if err := os.RemoveAll(filepath.Join(townRoot, ".dolt-data")); err != nil {
+		t.Fatal(err)
+	}
+	if !c.commonsExists() {
+		t.Error("commonsExists() should reuse the earlier lookup")
+	}
+}
+
+func TestWlRunContext_NotInWorkspace(t *testing.T) {
+	t.Parallel()
+	notFound := errors.New("not in a Gas Town workspace: no town here")
+	c := newWlRunContext()
+	c.rootErr, c.rootDone = notFound, true
+
+	if err := c.requireCommons(); err != notFound {
+		t.Errorf("requireCommons() = %v, want the workspace error", err)
+	}
+	if c.commonsExists() {
+		t.Error("commonsExists() outside a workspace should be false")
+	}
+	if _, err := c.wastelandConfig(); err != notFound {
+		t.Errorf("wastelandConfig() = %v, want the workspace error", err)
+	}
+}
+
+func TestWlRunContext_WastelandConfig(t *testing.T) {
+	t.Parallel()
+	c := wlRunContextAt(t.TempDir())
+
+	if _, err := c.wastelandConfig(); err == nil || !strings.Contains(err.Error(), "loading wasteland config") {
+		t.Errorf("wastelandConfig() before joining = %v", err)
+	}
+
+	joined := &wasteland.Config{RigHandle: "my-rig"}
+	c.setWastelandConfig(joined)
+	if cfg, err := c.wastelandConfig(); err != nil || cfg != joined {
+		t.Errorf("wastelandConfig() after join = %v, %v; want the joined config", cfg, err)
+	}
+}
+
+func TestWlRunContext_CheckSchema(t *testing.T) {
+	t.Parallel()
+	c := newWlRunContext()
+	c.migrateSchema = func(string) ([]string, error) { return nil, nil }
+	calls := 0
+	c.validateSchema = func(string) ([]doltserver.SchemaIssue, error) {
+		calls++
+		return []doltserver.SchemaIssue{
+			{Table: "wanted", Column: "assignee"},
+			{Table: "wanted_groups"},
+		}, nil
+	}
+
+	err := c.checkSchema("/town")
+	if err == nil {
+		t.Fatal("checkSchema() with missing columns succeeded")
+	}
+	for _, want := range []string{"wanted.assignee", "table wanted_groups", "gt wl validate"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("checkSchema() = %q, want it to mention %q", err, want)
+		}
+	}
+	if again := c.checkSchema("/town"); again != err || calls != 1 {
+		t.Errorf("second checkSchema() = %v after %d lookups; want the cached error after 1", again, calls)
+	}
+}
+
+func TestWlRunContext_CheckSchemaUnreadable(t *testing.T) {
+	t.Parallel()
+	c := newWlRunContext()
+	c.migrateSchema = func(string) ([]string, error) { return nil, errors.New("dolt not running") }
+	c.validateSchema = func(string) ([]doltserver.SchemaIssue, error) {
+		return nil, errors.New("dolt not running")
+	}
+	if err := c.checkSchema("/town"); err != nil {
+		t.Errorf("checkSchema() when the schema cannot be read = %v, want nil", err)
+	}
+}
+
+func TestWlRunContext_CheckSchemaMigratesFirst(t *testing.T) {
+	t.Parallel()
+	c := newWlRunContext()
+	migrated := false
+	c.migrateSchema = func(string) ([]string, error) {
+		migrated = true
+		return []string{doltserver.WLCommonsSchemaVersion}, nil
+	}
+	c.validateSchema = func(string) ([]doltserver.SchemaIssue, error) {
+		if !migrated {
+			return []doltserver.SchemaIssue{{Table: "wanted", Column: "claimed_at"}}, nil
+		}
+		return nil, nil
+	}
+	if err := c.checkSchema("/town"); err != nil {
+		t.Errorf("checkSchema() after migrating = %v, want nil", err)
+	}
+}
+
+func TestWlRunContext_RequireCommonsReadToleratesOldSchema(t *testing.T) {
+	t.Parallel()
+	c := wlRunContextAt(t.TempDir())
+	c.databases[doltserver.WLCommonsDB] = true
+	c.validateSchema = func(string) ([]doltserver.SchemaIssue, error) {
+		return []doltserver.SchemaIssue{{Table: "wanted", Column: "claimed_at"}}, nil
+	}
+
+	if err := c.requireCommonsRead(); err != nil {
+		t.Errorf("requireCommonsRead() on an old schema = %v, want reads to go ahead", err)
+	}
+	if err := c.requireCommons(); err == nil {
+		t.Error("requireCommons() on an old schema succeeded, want writes refused")
+	}
+}
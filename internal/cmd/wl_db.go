@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// wlDB is the --db flag shared by every gt wl subcommand.
+var wlDB string
+
+func init() {
+	wlCmd.PersistentFlags().StringVar(&wlDB, "db", "", "wl-commons database to use (default: from wasteland config, else "+doltserver.DefaultWLCommonsDB+")")
+}
+
+// selectWLCommonsDB makes the database named by --db, or else by the
+// town's wasteland config, the active wl-commons database.
+func selectWLCommonsDB() error {
+	var cfg *wasteland.Config
+	if wlDB == "" {
+		cfg, _ = wlRun.wastelandConfig()
+	}
+	return doltserver.SetWLCommonsDB(resolveWLCommonsDB(wlDB, cfg))
+}
+
+// resolveWLCommonsDB picks the wl-commons database: flag wins, then the
+// config's database, then the default. cfg may be nil.
+func resolveWLCommonsDB(flag string, cfg *wasteland.Config) string {
+	switch {
+	case flag != "":
+		return flag
+	case cfg != nil && cfg.Database != "":
+		return cfg.Database
+	default:
+		return doltserver.DefaultWLCommonsDB
+	}
+}
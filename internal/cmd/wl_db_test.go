@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+func TestResolveWLCommonsDB(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		cfg  *wasteland.Config
+		want string
+	}{
+		{"default", "", nil, doltserver.DefaultWLCommonsDB},
+		{"config without database", "", &wasteland.Config{}, doltserver.DefaultWLCommonsDB},
+		{"config", "", &wasteland.Config{Database: "wl_team"}, "wl_team"},
+		{"flag beats config", "wl_other", &wasteland.Config{Database: "wl_team"}, "wl_other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveWLCommonsDB(tt.flag, tt.cfg); got != tt.want {
+				t.Errorf("resolveWLCommonsDB(%q) = %q, want %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,62 @@
 package cmd
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/wasteland"
-	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-var wlDoneEvidence string
+// Completion ID sizing. The hash length is a wasteland setting
+// (ids.completion_bytes) so large wastelands can widen the id space.
+const (
+	wlSettingCompletionIDBytes = "ids.completion_bytes"
+	defaultCompletionIDBytes   = 8
+	minCompletionIDBytes       = 4
+	// maxCompletionIDLen is the width of completions.id.
+	maxCompletionIDLen = 64
+)
+
+// maxCompletionIDBytes is the longest hash, in bytes, that still fits
+// completions.id after prefix and its '-'.
+func maxCompletionIDBytes(prefix string) int {
+	return (maxCompletionIDLen - len(prefix) - 1) / 2
+}
+
+// wlSettingRequireClaimNote makes done refuse completions whose item has no
+// claim note from the claiming rig, unless --summary supplies one.
+const wlSettingRequireClaimNote = "done.require_claim_note"
+
+var (
+	wlDoneEvidence  string
+	wlDoneEvidFile  string
+	wlDoneDraft     bool
+	wlDoneFinal     bool
+	wlDoneSupersede string
+	wlDoneAmend     bool
+	wlDoneHoldFile  string
+	wlDoneIDFile    string
+	wlDoneEnsure    string
+	wlDoneGitNotes  string
+	wlDoneCloseDeps bool
+	wlDoneNotify    bool
+	wlDoneSummary   string
+	wlDoneTownFile  string
+	wlDoneNoCheck   bool
+	wlDoneBatch     string
+)
 
 var wlDoneCmd = &cobra.Command{
 	Use:   "done <wanted-id>",
@@ -23,82 +67,792 @@ Inserts a completion record and updates the wanted item status to 'in_review'.
 The item must be claimed by your rig.
 
 The --evidence flag provides the evidence URL (PR link, commit hash, etc.).
+For long writeups or logs, --evidence-file <path> reads the evidence from a
+file instead, and --evidence - reads it from stdin. The text is stored
+as-is, trailing newlines aside.
 
 A completion ID is generated as c-<hash> where hash is derived from the
-wanted ID, rig handle, and timestamp.
+wanted ID, rig handle, and timestamp. The hash is 8 bytes (16 hex chars)
+unless the wasteland setting ids.completion_bytes selects another length
+of at least 4 bytes, and ids.completion_prefix can replace the c stem
+(e.g. done-<hash>). The whole ID must fit in 64 characters, so the longest
+hash is 31 bytes with the c stem and less with a longer one.
+
+Use --draft to register work in progress without requesting review: the
+completion is recorded and the item moves to 'draft', still owned by your
+rig and visible to the poster. Run --final later to promote the draft to
+'in_review' (optionally replacing its evidence).
+
+When resubmitting after a rejection, pass --supersede <completion-id> to
+mark the earlier completion as superseded by the new one. Both writes happen
+in one transaction, so the item never has two current completions.
+
+To fix the evidence on a completion that is still in review, pass --amend:
+the current completion's evidence and the item's evidence_url are updated
+in place and an 'amend' event is recorded in the item's history. Only the
+rig that submitted the completion can amend it, and nothing is superseded.
+
+Evidence that starts with http:// or https:// must be a well-formed URL
+with a host, so a mistyped link is caught before reviewers see it. Any
+other evidence, such as 'commit abc123def', is stored as given.
+--no-validate skips the check.
+
+--evidence-from-git-notes reads the evidence from the git note on HEAD (or
+--evidence-from-git-notes=<ref>) in the current repository. When the commit
+has no note, --evidence is used instead if given; otherwise done fails
+without writing anything.
+
+--hold-file <path> removes the marker written by gt wl claim --hold-file
+once the completion is submitted for review (not with --draft or --amend),
+if it marks this item.
+
+--batch <file> submits many completions in one run. Each line of the file
+is a wanted ID, a tab, and its evidence; blank lines and lines starting
+with # are ignored, and - reads the lines from stdin. Every line is its
+own submission with its own completion ID, so a line that fails (an item
+not claimed by your rig, malformed evidence) is reported and skipped and
+the rest still go through. A summary of how many were submitted ends the
+run, which fails only if none were. --batch takes no wanted ID and
+combines only with --no-validate, --town-file, and --ensure-joined.
+
+--output-id-file <path> writes just the completion ID (and a newline) to
+path once the write commits, for every mode including --final and --amend.
+A failed submission leaves any previous file untouched.
+
+Once a completion is up for review (not a draft), the rig that posted the
+item is mailed along with the item's watchers, at the address it
+subscribed with or at the mayor otherwise. A failed notification is a
+warning; the completion stands.
+
+--summary records a note on what was done as a comment by your rig once
+the submission commits. Wastelands with the setting
+done.require_claim_note=true refuse a completion unless the claiming rig
+left a claim note (gt wl claim --note, or gt wl comment) or --summary is
+given.
+
+--close-deps lists, once the submission commits, the items that depend on
+this one, with their claimants and any other dependencies they still wait
+on. Add --notify-deps to mail each dependent's claimant at the address it
+subscribed with (gt wl subscribe). Dependents are only reported, never
+claimed or completed.
+
+--ensure-joined <org/db> runs gt wl join inline first when the wl-commons
+database is missing.
+
+Outside a Gas Town workspace (a container or CI job), --town-file <path>
+or $GASTOWN_TOWN supplies the town handle instead; the working directory's
+.dolt-data must then hold the wl-commons database.
 
 Examples:
   gt wl done w-abc123 --evidence 'https://github.com/org/repo/pull/123'
-  gt wl done w-abc123 --evidence 'commit abc123def'`,
-	Args: cobra.ExactArgs(1),
+  gt wl done w-abc123 --evidence 'https://github.com/org/repo/pull/130' --supersede c-1a2b3c4d5e6f7a8b
+  gt wl done w-abc123 --evidence 'commit abc123def'
+  gt wl done w-abc123 --evidence-file report.md
+  generate-report | gt wl done w-abc123 --evidence -
+  gt wl done w-abc123 --evidence 'https://github.com/org/repo/pull/123' --close-deps --notify-deps
+  gt wl done --batch completions.tsv
+  gt wl done w-abc123 --amend --evidence 'https://github.com/org/repo/pull/124'
+  gt wl done w-abc123 --evidence-from-git-notes
+  gt wl done w-abc123 --evidence-from-git-notes=v1.2.0 --evidence 'tag v1.2.0'
+  gt wl done w-abc123 --draft --evidence 'https://github.com/org/repo/pull/123'
+  gt wl done w-abc123 --final`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if wlDoneBatch != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: runWlDone,
 }
 
 func init() {
-	wlDoneCmd.Flags().StringVar(&wlDoneEvidence, "evidence", "", "Evidence URL or description (required)")
-	_ = wlDoneCmd.MarkFlagRequired("evidence")
+	wlDoneCmd.Flags().StringVar(&wlDoneEvidence, "evidence", "", "Evidence URL or description, or - to read it from stdin (required unless --final or --evidence-file)")
+	wlDoneCmd.Flags().StringVar(&wlDoneEvidFile, "evidence-file", "", "Read the evidence from this file")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("evidence", "evidence-file")
+	wlDoneCmd.Flags().BoolVar(&wlDoneDraft, "draft", false, "Record a draft completion without requesting review")
+	wlDoneCmd.Flags().BoolVar(&wlDoneFinal, "final", false, "Promote an existing draft completion to review")
+	wlDoneCmd.Flags().StringVar(&wlDoneSupersede, "supersede", "", "Completion ID this submission replaces")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("draft", "final")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("supersede", "final")
+	wlDoneCmd.Flags().BoolVar(&wlDoneAmend, "amend", false, "Replace the evidence on your completion that is in review")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("amend", "draft", "final", "supersede")
+	wlDoneCmd.Flags().StringVar(&wlDoneGitNotes, "evidence-from-git-notes", "", "Read evidence from the git note on a ref (default HEAD); --evidence is the fallback")
+	wlDoneCmd.Flags().Lookup("evidence-from-git-notes").NoOptDefVal = "HEAD"
+	wlDoneCmd.Flags().BoolVar(&wlDoneNoCheck, "no-validate", false, "Store URL evidence without checking that it is well formed")
+	wlDoneCmd.Flags().StringVar(&wlDoneHoldFile, "hold-file", "", "Remove this gt wl claim --hold-file marker after submitting")
+	wlDoneCmd.Flags().StringVar(&wlDoneIDFile, "output-id-file", "", "Write just the completion ID to this file once the write commits")
+	wlDoneCmd.Flags().StringVar(&wlDoneSummary, "summary", "", "Note on what was done, recorded as a comment after submitting")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("summary", "final")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("summary", "amend")
+	wlDoneCmd.Flags().BoolVar(&wlDoneCloseDeps, "close-deps", false, "After submitting, list the items that depend on this one")
+	wlDoneCmd.Flags().BoolVar(&wlDoneNotify, "notify-deps", false, "With --close-deps, mail the claimants of dependent items")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("close-deps", "draft")
+	wlDoneCmd.MarkFlagsMutuallyExclusive("close-deps", "amend")
+	wlDoneCmd.Flags().StringVar(&wlDoneTownFile, "town-file", "", "Outside a workspace, read the town handle from this file (else $GASTOWN_TOWN)")
+	wlDoneCmd.Flags().StringVar(&wlDoneEnsure, "ensure-joined", "", "Join this wasteland (org/db) first if not yet joined")
+	wlDoneCmd.Flags().StringVar(&wlDoneBatch, "batch", "", "Submit a completion for each wanted-id<TAB>evidence line of this file (- for stdin)")
 
 	wlCmd.AddCommand(wlDoneCmd)
 }
 
-func runWlDone(cmd *cobra.Command, args []string) error {
+func runWlDone(cmd *cobra.Command, args []string) (retErr error) {
+	if wlDoneBatch != "" {
+		return runWlDoneBatch(cmd)
+	}
 	wantedID := args[0]
 
-	townRoot, err := workspace.FindFromCwdOrError()
-	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	// A draft keeps the claim in progress and an amendment happens after
+	// submission, so only submissions for review release the hold file. A
+	// dry run submits nothing, so it keeps the file too.
+	if wlDoneHoldFile != "" && !wlDoneDraft && !wlDoneAmend && !doltserver.WLDryRun() {
+		defer func() {
+			if retErr != nil {
+				return
+			}
+			if err := releaseWlHoldFile(wlDoneHoldFile, wantedID); err != nil {
+				style.PrintWarning("%v", err)
+			}
+		}()
 	}
 
-	wlCfg, err := wasteland.LoadConfig(townRoot)
+	evidence, err := readDoneEvidence(wlDoneEvidence, wlDoneEvidFile, os.Stdin)
 	if err != nil {
-		return fmt.Errorf("loading wasteland config: %w", err)
+		return err
 	}
-	rigHandle := wlCfg.RigHandle
+	wlDoneEvidence = evidence
 
-	if !doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
-		return fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	if wlDoneGitNotes != "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		evidence, err := evidenceFromGitNotes(cwd, wlDoneGitNotes, wlDoneEvidence)
+		if err != nil {
+			return err
+		}
+		wlDoneEvidence = evidence
+	}
+
+	if wlDoneNotify && !wlDoneCloseDeps {
+		return fmt.Errorf("--notify-deps requires --close-deps")
+	}
+	if wlJSON && wlDoneCloseDeps {
+		return fmt.Errorf("--json cannot be combined with --close-deps")
+	}
+
+	if wlDoneEvidence == "" && !wlDoneFinal {
+		return fmt.Errorf("one of --evidence or --evidence-file is required")
+	}
+	if !wlDoneNoCheck {
+		if err := validateDoneEvidence(wlDoneEvidence); err != nil {
+			return err
+		}
+	}
+	if wlDoneEnsure != "" {
+		if _, _, err := wasteland.ParseUpstream(wlDoneEnsure); err != nil {
+			return err
+		}
+	}
+
+	townRoot, rigHandle, err := openWlDoneTown()
+	if err != nil {
+		return err
 	}
 
 	store := doltserver.NewWLCommons(townRoot)
-	completionID := generateCompletionID(wantedID, rigHandle)
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+
+	if wlDoneFinal {
+		err := commitThenNotifyPoster(store, townRoot, wantedID, rigHandle, "done --final", "finalized for review", func() error {
+			return finalizeDone(store, wantedID, rigHandle, wlDoneEvidence)
+		})
+		if err != nil {
+			return err
+		}
+		finalID := ""
+		if wlDoneIDFile != "" || wlJSON {
+			detail, err := store.QueryWantedDetail(wantedID)
+			if err != nil {
+				return fmt.Errorf("%s was finalized, but looking up its completion ID failed: %w", wantedID, err)
+			}
+			if c := currentCompletion(detail); c != nil {
+				finalID = c.ID
+			}
+			if err := writeDoneIDFile(wantedID, finalID); err != nil {
+				return err
+			}
+		}
+		if wlJSON {
+			return writeWLJSON(os.Stdout, wlDoneJSON{ID: wantedID, Status: doltserver.StatusInReview, CompletionID: finalID, CompletedBy: rigHandle, Evidence: wlDoneEvidence}, wlJSONPrettyOutput())
+		}
+		fmt.Printf("%s Draft completion finalized for %s\n", wlSuccessMark(), wantedID)
+		if wlDoneEvidence != "" {
+			fmt.Printf("  Evidence: %s\n", wlDoneEvidence)
+		}
+		fmt.Printf("  Status: in_review\n")
+		if wlDoneCloseDeps {
+			closeDoneDeps(store, townRoot, wantedID, rigHandle, wlDoneNotify)
+		}
+		return nil
+	}
+
+	if wlDoneAmend {
+		completionID, err := amendDone(store, wantedID, rigHandle, wlDoneEvidence)
+		recordWlAction(townRoot, wantedID, "done --amend", err)
+		if err != nil {
+			return err
+		}
+		if err := writeDoneIDFile(wantedID, completionID); err != nil {
+			return err
+		}
+		if wlJSON {
+			return writeWLJSON(os.Stdout, wlDoneJSON{ID: wantedID, Status: doltserver.StatusInReview, CompletionID: completionID, CompletedBy: rigHandle, Evidence: wlDoneEvidence}, wlJSONPrettyOutput())
+		}
+		fmt.Printf("%s Completion evidence amended for %s\n", wlSuccessMark(), wantedID)
+		fmt.Printf("  Completion ID: %s\n", completionID)
+		fmt.Printf("  Evidence: %s\n", wlDoneEvidence)
+		fmt.Printf("  Status: in_review\n")
+		return nil
+	}
+
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return fmt.Errorf("loading wasteland settings: %w", err)
+	}
+	idPrefix, err := completionIDPrefix(settings)
+	if err != nil {
+		return err
+	}
+	idBytes, err := completionIDBytes(settings, idPrefix)
+	if err != nil {
+		return err
+	}
+	completionID := generateCompletionID(idPrefix, wantedID, rigHandle, idBytes)
 
-	if err := submitDone(store, wantedID, rigHandle, wlDoneEvidence, completionID); err != nil {
+	if err := checkCompletionNote(store, settings, wantedID, rigHandle, wlDoneSummary); err != nil {
 		return err
 	}
 
-	fmt.Printf("%s Completion submitted for %s\n", style.Bold.Render("✓"), wantedID)
-	fmt.Printf("  Completion ID: %s\n", completionID)
+	if wlDoneSupersede != "" {
+		// A resubmitted draft is not up for review yet.
+		notify := commitThenNotifyPoster
+		if wlDoneDraft {
+			notify = commitThenNotify
+		}
+		err := notify(store, townRoot, wantedID, rigHandle, "done --supersede", "resubmitted", func() error {
+			return resubmitDone(store, wantedID, rigHandle, wlDoneEvidence, completionID, wlDoneSupersede, wlDoneDraft)
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeDoneIDFile(wantedID, completionID); err != nil {
+			return err
+		}
+		if err := recordDoneSummary(store, wantedID, rigHandle, wlDoneSummary); err != nil {
+			return err
+		}
+		status := "in_review"
+		if wlDoneDraft {
+			status = "draft"
+		}
+		if wlJSON {
+			return writeWLJSON(os.Stdout, wlDoneJSON{ID: wantedID, Status: status, CompletionID: completionID, CompletedBy: rigHandle, Supersedes: wlDoneSupersede, Evidence: wlDoneEvidence}, wlJSONPrettyOutput())
+		}
+		fmt.Printf("%s Completion resubmitted for %s\n", wlSuccessMark(), wantedID)
+		printDoneCompletionID(completionID)
+		fmt.Printf("  Supersedes: %s\n", wlDoneSupersede)
+		fmt.Printf("  Evidence: %s\n", wlDoneEvidence)
+		fmt.Printf("  Status: %s\n", status)
+		if wlDoneCloseDeps {
+			closeDoneDeps(store, townRoot, wantedID, rigHandle, wlDoneNotify)
+		}
+		return nil
+	}
+
+	if wlDoneDraft {
+		err := commitThenNotify(store, townRoot, wantedID, rigHandle, "done --draft", "submitted as a draft", func() error {
+			return submitDraft(store, wantedID, rigHandle, wlDoneEvidence, completionID)
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeDoneIDFile(wantedID, completionID); err != nil {
+			return err
+		}
+		if err := recordDoneSummary(store, wantedID, rigHandle, wlDoneSummary); err != nil {
+			return err
+		}
+		if wlJSON {
+			return writeWLJSON(os.Stdout, wlDoneJSON{ID: wantedID, Status: doltserver.StatusDraft, CompletionID: completionID, CompletedBy: rigHandle, Evidence: wlDoneEvidence}, wlJSONPrettyOutput())
+		}
+		fmt.Printf("%s Draft completion recorded for %s\n", wlSuccessMark(), wantedID)
+		printDoneCompletionID(completionID)
+		fmt.Printf("  Evidence: %s\n", wlDoneEvidence)
+		fmt.Printf("  Status: draft\n")
+		fmt.Printf("\n  %s\n", style.Dim.Render("Next: gt wl done "+wantedID+" --final  — request review"))
+		return nil
+	}
+
+	err = commitThenNotifyPoster(store, townRoot, wantedID, rigHandle, "done", "submitted for review", func() error {
+		return submitDone(store, wantedID, rigHandle, wlDoneEvidence, completionID)
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeDoneIDFile(wantedID, completionID); err != nil {
+		return err
+	}
+	if err := recordDoneSummary(store, wantedID, rigHandle, wlDoneSummary); err != nil {
+		return err
+	}
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlDoneJSON{ID: wantedID, Status: doltserver.StatusInReview, CompletionID: completionID, CompletedBy: rigHandle, Evidence: wlDoneEvidence}, wlJSONPrettyOutput())
+	}
+	fmt.Printf("%s Completion submitted for %s\n", wlSuccessMark(), wantedID)
+	printDoneCompletionID(completionID)
 	fmt.Printf("  Completed by: %s\n", rigHandle)
 	fmt.Printf("  Evidence: %s\n", wlDoneEvidence)
 	fmt.Printf("  Status: in_review\n")
+	if wlDoneCloseDeps {
+		closeDoneDeps(store, townRoot, wantedID, rigHandle, wlDoneNotify)
+	}
+
+	return nil
+}
+
+// printDoneCompletionID prints the ID of a new completion. A dry run
+// generates one but records nothing under it.
+func printDoneCompletionID(completionID string) {
+	if doltserver.WLDryRun() {
+		fmt.Printf("  Completion ID: %s (not recorded)\n", completionID)
+		return
+	}
+	fmt.Printf("  Completion ID: %s\n", completionID)
+}
+
+// openWlDoneTown finds the town gt wl done writes for, joining its
+// wasteland first under --ensure-joined, and returns its root and rig
+// handle once its commons is known to be usable.
+func openWlDoneTown() (townRoot, rigHandle string, err error) {
+	wlRun.allowTownHandle(wlDoneTownFile)
+	townRoot, err = wlRun.townRoot()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := ensureWlJoined(townRoot, wlDoneEnsure); err != nil {
+		return "", "", err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return "", "", err
+	}
+	if err := wlRun.requireCommons(); err != nil {
+		return "", "", err
+	}
+	return townRoot, wlCfg.RigHandle, nil
+}
+
+// wlDoneJSON is the gt wl done --json result.
+type wlDoneJSON struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	CompletionID string `json:"completion_id"`
+	CompletedBy  string `json:"completed_by"`
+	Supersedes   string `json:"supersedes,omitempty"`
+	Evidence     string `json:"evidence,omitempty"`
+}
+
+// checkCompletionNote enforces done.require_claim_note: when it is set, the
+// item must carry a non-empty comment from the rig holding the claim (the
+// claimant, the coordinator that claimed for it, or rigHandle), unless a
+// summary is being submitted with the completion.
+func checkCompletionNote(store doltserver.WLCommonsStore, settings map[string]string, wantedID, rigHandle, summary string) error {
+	if !settingBool(settings, wlSettingRequireClaimNote) || strings.TrimSpace(summary) != "" {
+		return nil
+	}
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+	holders := []string{rigHandle, detail.Item.ClaimedBy, detail.Item.ClaimedVia}
+	for _, c := range detail.Comments {
+		if strings.TrimSpace(c.Body) != "" && slices.Contains(holders, c.Author) {
+			return nil
+		}
+	}
+	return fmt.Errorf("this wasteland requires a claim note before completing %s (setting %s)\n"+
+		"Add one with: gt wl comment %s --note \"what was done\"\nor pass --summary to gt wl done",
+		wantedID, wlSettingRequireClaimNote, wantedID)
+}
+
+// recordDoneSummary records a --summary as a comment by rigHandle once the
+// completion has committed.
+func recordDoneSummary(store doltserver.WLCommonsStore, wantedID, rigHandle, summary string) error {
+	if strings.TrimSpace(summary) == "" {
+		return nil
+	}
+	if err := store.AddComment(wantedID, rigHandle, summary); err != nil {
+		return fmt.Errorf("%s was submitted, but recording the summary failed: %w", wantedID, err)
+	}
+	return nil
+}
+
+// commitThenNotify runs a board write, records it in the claim log as
+// action, and only once it has committed notifies watchers of change. Each
+// write is a single Dolt commit, so a failed write leaves nothing to notify
+// about and a failed notification cannot half-undo the write.
+func commitThenNotify(store doltserver.WLCommonsStore, townRoot, wantedID, rigHandle, action, change string, write func() error) error {
+	err := write()
+	recordWlAction(townRoot, wantedID, action, err)
+	if err != nil {
+		return err
+	}
+	notifyWatchers(store, townRoot, wantedID, rigHandle, change)
+	return nil
+}
+
+// commitThenNotifyPoster is commitThenNotify for a completion going up for
+// review: once it has committed, the rig that posted the item is mailed
+// along with the watchers, so the posting town hears there is work to
+// review without subscribing to its own post.
+func commitThenNotifyPoster(store doltserver.WLCommonsStore, townRoot, wantedID, rigHandle, action, change string, write func() error) error {
+	err := write()
+	recordWlAction(townRoot, wantedID, action, err)
+	if err != nil {
+		return err
+	}
+	watchers, err := withPosterWatcher(store, wantedID)
+	if err != nil {
+		err = fmt.Errorf("loading watchers: %w", err)
+		style.PrintWarning("%s was %s, but watchers were not notified: %v", wantedID, change, err)
+		recordWlAction(townRoot, wantedID, "notify", err)
+		return nil
+	}
+	sendWatcherNotification(townRoot, wantedID, rigHandle, change, watchers)
+	return nil
+}
+
+// withPosterWatcher returns wantedID's watchers plus the rig that posted
+// it, at defaultWatcherAddress, unless the poster already watches the item
+// at an address of its own.
+func withPosterWatcher(store doltserver.WLCommonsStore, wantedID string) ([]doltserver.WantedWatcher, error) {
+	watchers, err := store.QueryWatchers(wantedID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := store.QueryWanted(wantedID)
+	if err != nil {
+		return nil, err
+	}
+	if item.PostedBy == "" || slices.ContainsFunc(watchers, func(w doltserver.WantedWatcher) bool { return w.RigHandle == item.PostedBy }) {
+		return watchers, nil
+	}
+	return append(watchers, doltserver.WantedWatcher{RigHandle: item.PostedBy, Address: defaultWatcherAddress}), nil
+}
+
+// maxEvidenceBytes caps evidence read from a file or stdin at what the
+// TEXT evidence columns hold.
+const maxEvidenceBytes = 64 << 10
+
+// readDoneEvidence resolves the evidence flags: the contents of file when
+// set, stdin when evidence is "-", and evidence itself otherwise. Trailing
+// newlines are dropped; evidence that is then empty is an error.
+func readDoneEvidence(evidence, file string, stdin io.Reader) (string, error) {
+	var src string
+	var r io.Reader
+	switch {
+	case file != "":
+		f, err := os.Open(file)
+		if err != nil {
+			return "", fmt.Errorf("reading --evidence-file: %w", err)
+		}
+		defer f.Close()
+		src, r = file, f
+	case evidence == "-":
+		src, r = "stdin", stdin
+	default:
+		return evidence, nil
+	}
 
+	data, err := io.ReadAll(io.LimitReader(r, maxEvidenceBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("reading evidence from %s: %w", src, err)
+	}
+	if len(data) > maxEvidenceBytes {
+		return "", fmt.Errorf("evidence from %s is over %d bytes", src, maxEvidenceBytes)
+	}
+	text := strings.TrimRight(string(data), "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("evidence from %s is empty", src)
+	}
+	return text, nil
+}
+
+// validateDoneEvidence rejects evidence that starts with an http(s) scheme
+// but whose leading URL does not parse or has no host. Only the first
+// whitespace-separated word is checked, so a link followed by notes is
+// fine; evidence without the scheme is free-form and always passes.
+func validateDoneEvidence(evidence string) error {
+	fields := strings.Fields(evidence)
+	if len(fields) == 0 {
+		return nil
+	}
+	link := fields[0]
+	lower := strings.ToLower(link)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return nil
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("evidence %q is not a valid URL: %w (use --no-validate to store it anyway)", link, err)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("evidence %q is not a valid URL: missing host (use --no-validate to store it anyway)", link)
+	}
 	return nil
 }
 
+// evidenceFromGitNotes returns the git note on ref in the repository at dir,
+// or fallback when ref has no note. It errors when dir is not a git
+// repository or when there is neither a note nor a fallback.
+func evidenceFromGitNotes(dir, ref, fallback string) (string, error) {
+	g := git.NewGit(dir)
+	if !g.IsRepo() {
+		return "", fmt.Errorf("--evidence-from-git-notes: %s is not a git repository", dir)
+	}
+	note, err := g.NotesShow(ref)
+	if err != nil {
+		return "", fmt.Errorf("reading git notes for %s: %w", ref, err)
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		return note, nil
+	}
+	if fallback != "" {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("no git note on %s; add one with 'git notes add' or pass --evidence", ref)
+}
+
 // submitDone contains the testable business logic for submitting a completion.
 func submitDone(store doltserver.WLCommonsStore, wantedID, rigHandle, evidence, completionID string) error {
+	if err := requireClaimedBy(store, wantedID, rigHandle); err != nil {
+		return err
+	}
+	if err := requireTransition(store, wantedID, doltserver.StatusClaimed, doltserver.StatusInReview); err != nil {
+		return err
+	}
+	if err := requireNoCurrentCompletion(store, wantedID); err != nil {
+		return err
+	}
+
+	if err := store.SubmitCompletion(completionID, wantedID, rigHandle, evidence); err != nil {
+		return fmt.Errorf("submitting completion: %w", err)
+	}
+
+	return nil
+}
+
+// submitDraft records a draft completion for an item claimed by rigHandle.
+func submitDraft(store doltserver.WLCommonsStore, wantedID, rigHandle, evidence, completionID string) error {
+	if err := requireClaimedBy(store, wantedID, rigHandle); err != nil {
+		return err
+	}
+	if err := requireTransition(store, wantedID, doltserver.StatusClaimed, doltserver.StatusDraft); err != nil {
+		return err
+	}
+	if err := requireNoCurrentCompletion(store, wantedID); err != nil {
+		return err
+	}
+
+	if err := store.SubmitDraftCompletion(completionID, wantedID, rigHandle, evidence); err != nil {
+		return fmt.Errorf("submitting draft completion: %w", err)
+	}
+
+	return nil
+}
+
+// resubmitDone records a completion that supersedes an earlier one.
+func resubmitDone(store doltserver.WLCommonsStore, wantedID, rigHandle, evidence, completionID, supersedes string, draft bool) error {
+	if err := requireClaimedBy(store, wantedID, rigHandle); err != nil {
+		return err
+	}
+	to := doltserver.StatusInReview
+	if draft {
+		to = doltserver.StatusDraft
+	}
+	if err := requireTransition(store, wantedID, doltserver.StatusClaimed, to); err != nil {
+		return err
+	}
+
+	if err := store.ResubmitCompletion(completionID, supersedes, wantedID, rigHandle, evidence, draft); err != nil {
+		return fmt.Errorf("resubmitting completion: %w", err)
+	}
+
+	return nil
+}
+
+// requireNoCurrentCompletion refuses a fresh submission for an item that
+// still has a current completion, as it does after gt wl reject: the new
+// evidence must supersede the rejected completion rather than sit beside it.
+func requireNoCurrentCompletion(store doltserver.WLCommonsStore, wantedID string) error {
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+	if c := currentCompletion(detail); c != nil {
+		return fmt.Errorf("wanted item %s already has completion %s (rejected or awaiting review)\n\nResubmit with: gt wl done %s --supersede %s --evidence <url>",
+			wantedID, c.ID, wantedID, c.ID)
+	}
+	return nil
+}
+
+// requireClaimedBy checks that wantedID is claimed and held by rigHandle.
+func requireClaimedBy(store doltserver.WLCommonsStore, wantedID, rigHandle string) error {
 	item, err := store.QueryWanted(wantedID)
 	if err != nil {
 		return fmt.Errorf("querying wanted item: %w", err)
 	}
 
-	if item.Status != "claimed" {
+	if item.Status != doltserver.StatusClaimed {
 		return fmt.Errorf("wanted item %s is not claimed (status: %s)", wantedID, item.Status)
 	}
 
-	if item.ClaimedBy != rigHandle {
-		return fmt.Errorf("wanted item %s is claimed by %q, not %q", wantedID, item.ClaimedBy, rigHandle)
+	if err := requireClaimHolder(store, item, rigHandle); err != nil {
+		return err
 	}
 
-	if err := store.SubmitCompletion(completionID, wantedID, rigHandle, evidence); err != nil {
-		return fmt.Errorf("submitting completion: %w", err)
+	return nil
+}
+
+// requireClaimHolder checks that rigHandle holds item's claim: it claimed
+// the item, or the item is claimed for a group rigHandle belongs to.
+func requireClaimHolder(store doltserver.WLCommonsStore, item *doltserver.WantedItem, rigHandle string) error {
+	if item.ClaimedBy == rigHandle {
+		return nil
+	}
+	if item.ClaimedGroup != "" {
+		members, err := store.QueryGroupMembers(item.ClaimedGroup)
+		if err != nil {
+			return fmt.Errorf("loading members of group %s: %w", item.ClaimedGroup, err)
+		}
+		if slices.Contains(members, rigHandle) {
+			return nil
+		}
+		return fmt.Errorf("wanted item %s is claimed for group %q, which %q is not a member of", item.ID, item.ClaimedGroup, rigHandle)
+	}
+	return fmt.Errorf("wanted item %s is claimed by %q, not %q", item.ID, item.ClaimedBy, rigHandle)
+}
+
+// finalizeDone promotes rigHandle's draft completion to review.
+func finalizeDone(store doltserver.WLCommonsStore, wantedID, rigHandle, evidence string) error {
+	item, err := store.QueryWanted(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+
+	if item.Status != doltserver.StatusDraft {
+		return fmt.Errorf("wanted item %s has no draft completion (status: %s)", wantedID, item.Status)
 	}
 
+	if err := requireClaimHolder(store, item, rigHandle); err != nil {
+		return err
+	}
+
+	if err := requireTransition(store, wantedID, doltserver.StatusDraft, doltserver.StatusInReview); err != nil {
+		return err
+	}
+
+	if err := store.FinalizeCompletion(wantedID, rigHandle, evidence); err != nil {
+		return fmt.Errorf("finalizing completion: %w", err)
+	}
+
+	return nil
+}
+
+// amendDone replaces the evidence on rigHandle's completion of wantedID,
+// which must be in review, and returns the amended completion's ID.
+func amendDone(store doltserver.WLCommonsStore, wantedID, rigHandle, evidence string) (string, error) {
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return "", fmt.Errorf("querying wanted item: %w", err)
+	}
+	if detail.Item.Status != doltserver.StatusInReview {
+		return "", fmt.Errorf("wanted item %s is not in review (status: %s)", wantedID, detail.Item.Status)
+	}
+
+	current := currentCompletion(detail)
+	if current == nil {
+		return "", fmt.Errorf("wanted item %s has no current completion to amend", wantedID)
+	}
+	if current.CompletedBy != rigHandle {
+		return "", fmt.Errorf("completion %s was submitted by %q; only that rig can amend it", current.ID, current.CompletedBy)
+	}
+	if current.Evidence == evidence {
+		return "", fmt.Errorf("completion %s already has that evidence", current.ID)
+	}
+
+	if err := store.AmendCompletion(wantedID, rigHandle, evidence); err != nil {
+		return "", fmt.Errorf("amending completion: %w", err)
+	}
+	return current.ID, nil
+}
+
+// currentCompletion returns the completion of detail that has not been
+// superseded, or nil if there is none.
+func currentCompletion(detail *doltserver.WantedDetail) *doltserver.WantedCompletion {
+	var current *doltserver.WantedCompletion
+	for i := range detail.Completions {
+		if detail.Completions[i].SupersededBy == "" {
+			current = &detail.Completions[i]
+		}
+	}
+	return current
+}
+
+// writeDoneIDFile writes completionID to --output-id-file, if set, after
+// the write for wantedID has committed.
+func writeDoneIDFile(wantedID, completionID string) error {
+	if wlDoneIDFile == "" || doltserver.WLDryRun() {
+		return nil
+	}
+	if completionID == "" {
+		return fmt.Errorf("completion for %s was recorded, but its ID could not be found for --output-id-file", wantedID)
+	}
+	if err := writeWlOutputIDFile(wlDoneIDFile, completionID); err != nil {
+		return fmt.Errorf("completion for %s was recorded, but --output-id-file failed: %w", wantedID, err)
+	}
 	return nil
 }
 
-func generateCompletionID(wantedID, rigHandle string) string {
-	now := time.Now().UTC().Format(time.RFC3339)
-	h := sha256.Sum256([]byte(wantedID + "|" + rigHandle + "|" + now))
-	return fmt.Sprintf("c-%x", h[:8])
+// completionIDBytes returns the configured completion hash length in bytes,
+// falling back to the default when the setting is absent. The upper bound
+// depends on prefix, so that prefix-<hex> fits completions.id.
+func completionIDBytes(settings map[string]string, prefix string) (int, error) {
+	raw := strings.TrimSpace(settings[wlSettingCompletionIDBytes])
+	if raw == "" {
+		return defaultCompletionIDBytes, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if limit := maxCompletionIDBytes(prefix); err != nil || n < minCompletionIDBytes || n > limit {
+		return 0, fmt.Errorf("invalid %s setting %q: must be an integer between %d and %d",
+			wlSettingCompletionIDBytes, raw, minCompletionIDBytes, limit)
+	}
+	return n, nil
+}
+
+// generateCompletionID returns <prefix>-<hex> with idBytes bytes of hash.
+// prefix and idBytes must already be validated by completionIDPrefix and
+// completionIDBytes. The hash covers a nanosecond timestamp and a random
+// salt, so two completions by one town for one item in the same second get
+// different IDs.
+func generateCompletionID(prefix, wantedID, rigHandle string, idBytes int) string {
+	salt := make([]byte, 8)
+	_, _ = rand.Read(salt)
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%x", wantedID, rigHandle, time.Now().UnixNano(), salt)))
+	return fmt.Sprintf("%s-%x", prefix, h[:idBytes])
 }
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// doneSingleFlags are the gt wl done flags that describe one submission,
+// which --batch refuses.
+var doneSingleFlags = []string{
+	"evidence", "evidence-file", "evidence-from-git-notes", "draft", "final", "supersede", "amend",
+	"hold-file", "output-id-file", "summary", "close-deps", "notify-deps",
+}
+
+// doneBatchLine is one line of a gt wl done --batch file.
+type doneBatchLine struct {
+	// Line is the 1-based line number, for reporting.
+	Line     int
+	WantedID string
+	Evidence string
+	// Err is set when the line is malformed; it is reported and skipped.
+	Err error
+}
+
+func runWlDoneBatch(cmd *cobra.Command) error {
+	var extra []string
+	for _, name := range doneSingleFlags {
+		if cmd.Flags().Changed(name) {
+			extra = append(extra, "--"+name)
+		}
+	}
+	if len(extra) > 0 {
+		return fmt.Errorf("--batch cannot be combined with %s", strings.Join(extra, ", "))
+	}
+
+	var r io.Reader = os.Stdin
+	if wlDoneBatch != "-" {
+		f, err := os.Open(wlDoneBatch)
+		if err != nil {
+			return fmt.Errorf("reading batch file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	lines, err := parseDoneBatch(r)
+	if err != nil {
+		return fmt.Errorf("reading batch file: %w", err)
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("batch file %s has no completions", wlDoneBatch)
+	}
+
+	townRoot, rigHandle, err := openWlDoneTown()
+	if err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	out := claimBatchOutput{Out: os.Stdout, Err: os.Stderr, JSON: wlJSON}
+	_, err = submitDoneBatch(cmd.Context(), out, store, townRoot, lines, rigHandle, !wlDoneNoCheck)
+	return err
+}
+
+// parseDoneBatch reads wanted-id<TAB>evidence lines. Blank lines and lines
+// starting with # are skipped; a line without a tab, or with an empty ID or
+// evidence, comes back with Err set so the batch can report it and go on.
+func parseDoneBatch(r io.Reader) ([]doneBatchLine, error) {
+	var lines []doneBatchLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxEvidenceBytes+1<<10)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+		line := doneBatchLine{Line: n}
+		id, evidence, ok := strings.Cut(text, "\t")
+		line.WantedID, line.Evidence = strings.TrimSpace(id), strings.TrimSpace(evidence)
+		switch {
+		case !ok:
+			line.Err = fmt.Errorf("expected wanted-id<TAB>evidence")
+		case line.WantedID == "":
+			line.Err = fmt.Errorf("missing wanted ID")
+		case line.Evidence == "":
+			line.Err = fmt.Errorf("missing evidence")
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// submitDoneBatch submits a completion for review for each line, as
+// rigHandle. Every line is its own write with its own completion ID, like
+// claimWantedBatch: a line that fails is reported and skipped rather than
+// aborting the rest. It returns how many were submitted, and an error only
+// when none were.
+func submitDoneBatch(ctx context.Context, out claimBatchOutput, store doltserver.WLCommonsStore, townRoot string, lines []doneBatchLine, rigHandle string, validate bool) (int, error) {
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return 0, fmt.Errorf("loading wasteland settings: %w", err)
+	}
+	idPrefix, err := completionIDPrefix(settings)
+	if err != nil {
+		return 0, err
+	}
+	idBytes, err := completionIDBytes(settings, idPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	submitted := 0
+	for _, line := range lines {
+		completionID, err := submitDoneBatchLine(ctx, store, townRoot, line, rigHandle, settings, idPrefix, idBytes, validate)
+		if err != nil {
+			if ctx.Err() != nil {
+				return submitted, err
+			}
+			label := fmt.Sprintf("line %d", line.Line)
+			if line.WantedID != "" {
+				label += " (" + line.WantedID + ")"
+			}
+			if out.JSON {
+				fmt.Fprintf(out.Err, "skipping %s: %v\n", label, err)
+			} else {
+				fmt.Fprintf(out.Out, "%s %s: %v\n", style.Warning.Render("✗"), style.Bold.Render(label), err)
+			}
+			continue
+		}
+		submitted++
+
+		if out.JSON {
+			result := wlDoneJSON{ID: line.WantedID, Status: doltserver.StatusInReview, CompletionID: completionID, CompletedBy: rigHandle, Evidence: line.Evidence}
+			if err := writeWLJSON(out.Out, result, wlJSONPrettyOutput()); err != nil {
+				return submitted, err
+			}
+			continue
+		}
+		fmt.Fprintf(out.Out, "%s Submitted %s as %s\n", wlSuccessMark(), style.Bold.Render(line.WantedID), completionID)
+	}
+
+	if !out.JSON {
+		fmt.Fprintf(out.Out, "\nSubmitted %d of %d completion(s); %d skipped.\n", submitted, len(lines), len(lines)-submitted)
+	}
+	if submitted == 0 {
+		return 0, fmt.Errorf("none of the %d completions could be submitted", len(lines))
+	}
+	return submitted, nil
+}
+
+// submitDoneBatchLine checks and submits one line, returning its
+// completion ID.
+func submitDoneBatchLine(ctx context.Context, store doltserver.WLCommonsStore, townRoot string, line doneBatchLine, rigHandle string, settings map[string]string, idPrefix string, idBytes int, validate bool) (string, error) {
+	if line.Err != nil {
+		return "", line.Err
+	}
+	if validate {
+		if err := validateDoneEvidence(line.Evidence); err != nil {
+			return "", err
+		}
+	}
+	if err := checkCompletionNote(store, settings, line.WantedID, rigHandle, ""); err != nil {
+		return "", err
+	}
+	if err := throttleWlWrite(ctx, store, townRoot); err != nil {
+		return "", err
+	}
+	completionID := generateCompletionID(idPrefix, line.WantedID, rigHandle, idBytes)
+	err := commitThenNotifyPoster(store, townRoot, line.WantedID, rigHandle, "done", "submitted for review", func() error {
+		return submitDone(store, line.WantedID, rigHandle, line.Evidence, completionID)
+	})
+	return completionID, err
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestParseDoneBatch(t *testing.T) {
+	t.Parallel()
+	input := "# completions for today\n" +
+		"w-1\thttps://github.com/org/repo/pull/1\n" +
+		"\n" +
+		"w-2 no tab here\n" +
+		"\tcommit abc123\n" +
+		"w-3\t\r\n" +
+		"w-4\tcommit def456 with\ttabs\r\n"
+	lines, err := parseDoneBatch(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseDoneBatch() error: %v", err)
+	}
+	want := []struct {
+		line     int
+		id       string
+		evidence string
+		bad      bool
+	}{
+		{2, "w-1", "https://github.com/org/repo/pull/1", false},
+		{4, "w-2 no tab here", "", true},
+		{5, "", "commit abc123", true},
+		{6, "w-3", "", true},
+		{7, "w-4", "commit def456 with\ttabs", false},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(lines), len(want), lines)
+	}
+	for i, w := range want {
+		got := lines[i]
+		if got.Line != w.line || got.WantedID != w.id || got.Evidence != w.evidence || (got.Err != nil) != w.bad {
+			t.Errorf("lines[%d] = %+v, want line %d %q %q bad=%v", i, got, w.line, w.id, w.evidence, w.bad)
+		}
+	}
+}
+
+func TestSubmitDoneBatch_SkipsAndReports(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, id := range []string{"w-1", "w-2", "w-3"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: "Item " + id})
+	}
+	_ = store.ClaimWanted("w-1", "my-rig")
+	_ = store.ClaimWanted("w-2", "other-rig")
+	_ = store.ClaimWanted("w-3", "my-rig")
+	lines, _ := parseDoneBatch(strings.NewReader("w-1\tcommit abc\nw-2\tcommit def\nbroken\nw-3\thttps://\n"))
+
+	var out bytes.Buffer
+	submitted, err := submitDoneBatch(context.Background(), claimBatchOutput{Out: &out, Err: &out}, store, t.TempDir(), lines, "my-rig", true)
+	if err != nil || submitted != 1 {
+		t.Fatalf("submitDoneBatch() = %d, %v; want 1 submitted", submitted, err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusInReview {
+		t.Errorf("w-1 status = %q, want in_review", item.Status)
+	}
+	for _, id := range []string{"w-2", "w-3"} {
+		if item, _ := store.QueryWanted(id); item.Status != doltserver.StatusClaimed {
+			t.Errorf("%s status = %q, want it left claimed", id, item.Status)
+		}
+	}
+	got := out.String()
+	for _, want := range []string{"Submitted w-1 as c-", "line 2 (w-2):", "line 3 (broken):", "line 4 (w-3):", "Submitted 1 of 4 completion(s); 3 skipped."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSubmitDoneBatch_DistinctCompletionIDs(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, id := range []string{"w-1", "w-2"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: "Item " + id})
+		_ = store.ClaimWanted(id, "my-rig")
+	}
+	lines, _ := parseDoneBatch(strings.NewReader("w-1\tcommit abc\nw-2\tcommit def\n"))
+
+	var out bytes.Buffer
+	if n, err := submitDoneBatch(context.Background(), claimBatchOutput{Out: &out, Err: &out}, store, t.TempDir(), lines, "my-rig", true); err != nil || n != 2 {
+		t.Fatalf("submitDoneBatch() = %d, %v; want 2", n, err)
+	}
+	first, second := store.completions["w-1"], store.completions["w-2"]
+	if len(first) != 1 || len(second) != 1 || first[0].ID == second[0].ID {
+		t.Errorf("completions = %+v and %+v, want one each with distinct IDs", first, second)
+	}
+}
+
+func TestSubmitDoneBatch_FailsOnlyWhenNoneSubmitted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	lines, _ := parseDoneBatch(strings.NewReader("w-missing\tcommit abc\n"))
+
+	var out bytes.Buffer
+	submitted, err := submitDoneBatch(context.Background(), claimBatchOutput{Out: &out, Err: &out}, store, t.TempDir(), lines, "my-rig", true)
+	if err == nil || submitted != 0 || !strings.Contains(err.Error(), "none of the 1 completions") {
+		t.Errorf("submitDoneBatch() = %d, %v; want an error naming the whole batch", submitted, err)
+	}
+}
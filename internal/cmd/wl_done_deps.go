@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// wlDependent is an item that depends on a just-completed one, with the
+// blockers it is still waiting on besides that item.
+type wlDependent struct {
+	Item          *doltserver.WantedItem
+	OtherBlockers []*doltserver.WantedItem
+}
+
+// findDependents returns the items that depend on wantedID. It only reads:
+// dependents are never claimed or completed on the completer's behalf.
+func findDependents(store doltserver.WLCommonsStore, wantedID string) ([]wlDependent, error) {
+	items, err := store.QueryDependents(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying dependents of %s: %w", wantedID, err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	model, err := doltserver.QueryStatusModel(store)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []wlDependent
+	for _, item := range items {
+		blockers, err := outstandingBlockers(store, item.ID, model)
+		if err != nil {
+			return nil, err
+		}
+		var others []*doltserver.WantedItem
+		for _, b := range blockers {
+			if b.ID != wantedID {
+				others = append(others, b)
+			}
+		}
+		dependents = append(dependents, wlDependent{Item: item, OtherBlockers: others})
+	}
+	return dependents, nil
+}
+
+// printDependents lists dependents of wantedID for gt wl done --close-deps.
+func printDependents(w io.Writer, wantedID string, dependents []wlDependent) {
+	if len(dependents) == 0 {
+		fmt.Fprintf(w, "\n  %s\n", style.Dim.Render("No items depend on "+wantedID))
+		return
+	}
+	fmt.Fprintf(w, "\nItems depending on %s (%d):\n", wantedID, len(dependents))
+	for _, d := range dependents {
+		claimant := "unclaimed"
+		if d.Item.ClaimedBy != "" {
+			claimant = "claimed by " + d.Item.ClaimedBy
+		}
+		fmt.Fprintf(w, "  %s %s [%s, %s]\n", d.Item.ID, d.Item.Title, d.Item.Status, claimant)
+		if len(d.OtherBlockers) > 0 {
+			fmt.Fprintf(w, "    %s\n", style.Dim.Render("still waiting on "+formatBlockers(d.OtherBlockers)))
+		}
+	}
+}
+
+// notifyDependentClaimants mails the claimant of each claimed dependent
+// that wantedID has been submitted for review. Claimants are reached at the addresses
+// they subscribed to the dependent with (gt wl subscribe); a claimant with
+// no subscription is reported as unreachable. Like notifyWatchers it is
+// best-effort and never fails the calling command.
+func notifyDependentClaimants(store doltserver.WLCommonsStore, townRoot, wantedID, actor string, dependents []wlDependent) {
+	for _, d := range dependents {
+		claimant := d.Item.ClaimedBy
+		if claimant == "" || claimant == actor {
+			continue
+		}
+		watchers, err := store.QueryWatchers(d.Item.ID)
+		if err != nil {
+			err = fmt.Errorf("loading watchers of %s: %w", d.Item.ID, err)
+			style.PrintWarning("%s was not notified: %v", claimant, err)
+			recordWlAction(townRoot, d.Item.ID, "notify", err)
+			continue
+		}
+		msg := buildDependentNotification(wantedID, actor, d, watchers)
+		if msg == nil {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%s has no mail address for %s; not notified", claimant, d.Item.ID)))
+			continue
+		}
+		if err := sendWlNotification(townRoot, msg); err != nil {
+			err = fmt.Errorf("notifying %s: %w", claimant, err)
+			style.PrintWarning("%v", err)
+			recordWlAction(townRoot, d.Item.ID, "notify", err)
+			continue
+		}
+		fmt.Printf("  Notified %s about %s\n", claimant, d.Item.ID)
+	}
+}
+
+// buildDependentNotification tells the claimant of d that its dependency
+// wantedID was submitted for review by actor. It returns nil when the claimant has not
+// subscribed to d with a mail address.
+func buildDependentNotification(wantedID, actor string, d wlDependent, watchers []doltserver.WantedWatcher) *mail.Message {
+	address := ""
+	for _, w := range watchers {
+		if w.RigHandle == d.Item.ClaimedBy && w.Address != "" {
+			address = w.Address
+			break
+		}
+	}
+	if address == "" {
+		return nil
+	}
+
+	state := "Once " + wantedID + " is accepted, " + d.Item.ID + " has no other open dependencies."
+	if len(d.OtherBlockers) > 0 {
+		state = d.Item.ID + " is also waiting on " + formatBlockers(d.OtherBlockers) + "."
+	}
+	return &mail.Message{
+		From:      defaultWatcherAddress,
+		To:        address,
+		Subject:   fmt.Sprintf("[wl] %s dependency %s submitted for review", d.Item.ID, wantedID),
+		Body:      fmt.Sprintf("%s, which %s depends on, was submitted for review by %s.\n%s\n\nSee: gt wl show %s", wantedID, d.Item.ID, actor, state, d.Item.ID),
+		Type:      mail.TypeNotification,
+		ThreadID:  "wl-" + d.Item.ID,
+		Timestamp: time.Now(),
+	}
+}
+
+// closeDoneDeps reports, and with notify mails, the dependents of a
+// completion that has already committed. Failures are warnings: the
+// completion itself has landed.
+func closeDoneDeps(store doltserver.WLCommonsStore, townRoot, wantedID, actor string, notify bool) {
+	dependents, err := findDependents(store, wantedID)
+	if err != nil {
+		style.PrintWarning("%s was submitted, but its dependents could not be listed: %v", wantedID, err)
+		return
+	}
+	printDependents(os.Stdout, wantedID, dependents)
+	if notify {
+		notifyDependentClaimants(store, townRoot, wantedID, actor, dependents)
+	}
+}
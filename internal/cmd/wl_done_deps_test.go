@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestFindDependents_ReportsDependent(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-base", Title: "Base"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-other", Title: "Other blocker"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-next", Title: "Next step", DependsOn: []string{"w-base"}})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-later", Title: "Later step", DependsOn: []string{"w-base", "w-other"}})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-unrelated", Title: "Unrelated"})
+	_ = store.ClaimWanted("w-base", "my-rig")
+	_ = store.ClaimWanted("w-next", "dep-rig")
+	if err := submitDone(store, "w-base", "my-rig", "pr/1", "c-1"); err != nil {
+		t.Fatalf("submitDone() error: %v", err)
+	}
+
+	dependents, err := findDependents(store, "w-base")
+	if err != nil {
+		t.Fatalf("findDependents() error: %v", err)
+	}
+	if len(dependents) != 2 {
+		t.Fatalf("findDependents() = %d items, want 2", len(dependents))
+	}
+	next, later := dependents[1], dependents[0]
+	if next.Item.ID != "w-next" || next.Item.ClaimedBy != "dep-rig" || len(next.OtherBlockers) != 0 {
+		t.Errorf("w-next = %+v, want claimed by dep-rig with no other blockers", next)
+	}
+	if later.Item.ID != "w-later" || len(later.OtherBlockers) != 1 || later.OtherBlockers[0].ID != "w-other" {
+		t.Errorf("w-later = %+v, want still waiting on w-other", later)
+	}
+
+	// Reporting must not touch the dependents.
+	if got, _ := store.QueryWanted("w-later"); got.Status != doltserver.StatusOpen {
+		t.Errorf("w-later status = %q, want open", got.Status)
+	}
+
+	var buf bytes.Buffer
+	printDependents(&buf, "w-base", dependents)
+	out := buf.String()
+	for _, want := range []string{"Items depending on w-base (2)", "w-next Next step [claimed, claimed by dep-rig]", "still waiting on w-other (open)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFindDependents_None(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-base", Title: "Base"})
+
+	dependents, err := findDependents(store, "w-base")
+	if err != nil || len(dependents) != 0 {
+		t.Fatalf("findDependents() = %v, %v; want none", dependents, err)
+	}
+}
+
+func TestNotifyDependentClaimants(t *testing.T) {
+	orig := sendWlNotification
+	t.Cleanup(func() { sendWlNotification = orig })
+	var sent []*mail.Message
+	sendWlNotification = func(townRoot string, msg *mail.Message) error {
+		sent = append(sent, msg)
+		return nil
+	}
+
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-base", Title: "Base"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-next", Title: "Next", DependsOn: []string{"w-base"}})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-quiet", Title: "Quiet", DependsOn: []string{"w-base"}})
+	_ = store.ClaimWanted("w-next", "dep-rig")
+	_ = store.ClaimWanted("w-quiet", "quiet-rig")
+	_ = store.AddWatcher("w-next", "bystander", "bystander/")
+	_ = store.AddWatcher("w-next", "dep-rig", "dep-rig/mayor")
+
+	dependents, err := findDependents(store, "w-base")
+	if err != nil {
+		t.Fatalf("findDependents() error: %v", err)
+	}
+	notifyDependentClaimants(store, t.TempDir(), "w-base", "my-rig", dependents)
+
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1 (quiet-rig has no address)", len(sent))
+	}
+	msg := sent[0]
+	if msg.To != "dep-rig/mayor" || len(msg.CC) != 0 {
+		t.Errorf("To = %q CC = %v, want only the claimant's address", msg.To, msg.CC)
+	}
+	if !strings.Contains(msg.Subject, "w-next dependency w-base submitted for review") {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !strings.Contains(msg.Body, "was submitted for review by my-rig") || !strings.Contains(msg.Body, "Once w-base is accepted") {
+		t.Errorf("Body = %q, want unblocked note", msg.Body)
+	}
+}
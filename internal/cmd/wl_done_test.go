@@ -1,26 +1,117 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/wasteland"
 )
 
 func TestGenerateCompletionID_Format(t *testing.T) {
 	t.Parallel()
-	id := generateCompletionID("w-abc123", "my-rig")
-	if !strings.HasPrefix(id, "c-") {
-		t.Errorf("generateCompletionID() = %q, want prefix 'c-'", id)
+	for _, prefix := range []string{defaultCompletionIDPrefix, "done"} {
+		for _, idBytes := range []int{minCompletionIDBytes, defaultCompletionIDBytes, 12, maxCompletionIDBytes(prefix)} {
+			id := generateCompletionID(prefix, "w-abc123", "my-rig", idBytes)
+			if !strings.HasPrefix(id, prefix+"-") {
+				t.Errorf("generateCompletionID(%q, %d) = %q, want prefix %q", prefix, idBytes, id, prefix+"-")
+			}
+			// prefix + "-" + 2 hex chars per byte
+			if want := len(prefix) + 1 + 2*idBytes; len(id) != want {
+				t.Errorf("generateCompletionID(%q, %d) length = %d, want %d", prefix, idBytes, len(id), want)
+			}
+			hexPart := id[len(prefix)+1:]
+			for _, c := range hexPart {
+				if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
+					t.Errorf("generateCompletionID(%q, %d) contains non-hex char %q in %q", prefix, idBytes, string(c), id)
+				}
+			}
+		}
+	}
+}
+
+func TestWlIDPrefix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"", defaultCompletionIDPrefix, false},
+		{"done", "done", false},
+		{" done- ", "done", false},
+		{"x9", "x9", false},
+		{"-", "", true},
+		{"Done", "", true},
+		{"done_id", "", true},
+		{"abcdefghijklm", "", true},
+	}
+	for _, tt := range tests {
+		settings := map[string]string{wlSettingCompletionIDPrefix: tt.value}
+		got, err := completionIDPrefix(settings)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("completionIDPrefix(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("completionIDPrefix(%q) = %q, want %q", tt.value, got, tt.want)
+		}
 	}
-	// "c-" + 16 hex chars = 18 chars total
-	if len(id) != 18 {
-		t.Errorf("generateCompletionID() length = %d, want 18", len(id))
+	if got, _ := wantedIDPrefix(nil); got != defaultWantedIDPrefix {
+		t.Errorf("wantedIDPrefix(nil) = %q, want %q", got, defaultWantedIDPrefix)
 	}
-	hexPart := id[2:]
-	for _, c := range hexPart {
-		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
-			t.Errorf("generateCompletionID() contains non-hex char %q in %q", string(c), id)
+}
+
+func TestCompletionIDBytes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value   string
+		prefix  string
+		want    int
+		wantErr bool
+	}{
+		{"", "c", defaultCompletionIDBytes, false},
+		{"4", "c", 4, false},
+		{" 12 ", "c", 12, false},
+		{"31", "c", 31, false},
+		{"32", "c", 0, true},
+		{"3", "c", 0, true},
+		{"eight", "c", 0, true},
+		// The longest prefix leaves room for 25 bytes: 12 + 1 + 50 = 63.
+		{"25", "abcdefghijkl", 25, false},
+		{"26", "abcdefghijkl", 0, true},
+	}
+	for _, tt := range tests {
+		settings := map[string]string{}
+		if tt.value != "" {
+			settings[wlSettingCompletionIDBytes] = tt.value
+		}
+		got, err := completionIDBytes(settings, tt.prefix)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("completionIDBytes(%q, %q) error = %v, wantErr %v", tt.value, tt.prefix, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("completionIDBytes(%q, %q) = %d, want %d", tt.value, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionIDBytes_FitsColumnAtBoundary(t *testing.T) {
+	t.Parallel()
+	for _, prefix := range []string{"c", "done", "abcdefghijkl"} {
+		id := generateCompletionID(prefix, "w-abc", "rig-1", maxCompletionIDBytes(prefix))
+		if len(id) > maxCompletionIDLen {
+			t.Errorf("longest %s ID is %d chars, over completions.id's %d", prefix, len(id), maxCompletionIDLen)
+		}
+		if longer := len(prefix) + 1 + 2*(maxCompletionIDBytes(prefix)+1); longer <= maxCompletionIDLen {
+			t.Errorf("maxCompletionIDBytes(%q) = %d, but one more byte still fits", prefix, maxCompletionIDBytes(prefix))
 		}
 	}
 }
@@ -28,9 +119,9 @@ func TestGenerateCompletionID_Format(t *testing.T) {
 func TestGenerateCompletionID_DeterministicInputs(t *testing.T) {
 	t.Parallel()
 	// Different inputs should produce different IDs (with very high probability)
-	id1 := generateCompletionID("w-abc", "rig-1")
-	id2 := generateCompletionID("w-def", "rig-1")
-	id3 := generateCompletionID("w-abc", "rig-2")
+	id1 := generateCompletionID(defaultCompletionIDPrefix, "w-abc", "rig-1", defaultCompletionIDBytes)
+	id2 := generateCompletionID(defaultCompletionIDPrefix, "w-def", "rig-1", defaultCompletionIDBytes)
+	id3 := generateCompletionID(defaultCompletionIDPrefix, "w-abc", "rig-2", defaultCompletionIDBytes)
 
 	if id1 == id2 {
 		t.Errorf("same ID for different wantedIDs: %s", id1)
@@ -40,6 +131,44 @@ func TestGenerateCompletionID_DeterministicInputs(t *testing.T) {
 	}
 }
 
+// Completions submitted back to back (done, reject, resubmit) must not reuse
+// an ID, even within the same second.
+func TestGenerateCompletionID_RapidCompletions(t *testing.T) {
+	t.Parallel()
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := generateCompletionID(defaultCompletionIDPrefix, "w-abc", "rig-1", minCompletionIDBytes)
+		if seen[id] {
+			t.Fatalf("generateCompletionID() repeated %s after %d calls", id, i)
+		}
+		seen[id] = true
+	}
+
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix it", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-abc", "rig-1")
+	first := generateCompletionID(defaultCompletionIDPrefix, "w-abc", "rig-1", defaultCompletionIDBytes)
+	if err := submitDone(store, "w-abc", "rig-1", "https://example.com/1", first); err != nil {
+		t.Fatalf("submitDone() error: %v", err)
+	}
+	if _, err := rejectCompletion(store, "w-abc", "poster", "try again"); err != nil {
+		t.Fatalf("rejectCompletion() error: %v", err)
+	}
+	second := generateCompletionID(defaultCompletionIDPrefix, "w-abc", "rig-1", defaultCompletionIDBytes)
+	if err := resubmitDone(store, "w-abc", "rig-1", "https://example.com/2", second, first, false); err != nil {
+		t.Fatalf("resubmitDone() error: %v", err)
+	}
+
+	// A reused ID is reported as taken rather than as a second completion.
+	if _, err := rejectCompletion(store, "w-abc", "poster", "still wrong"); err != nil {
+		t.Fatalf("rejectCompletion() error: %v", err)
+	}
+	err := resubmitDone(store, "w-abc", "rig-1", "https://example.com/3", first, second, false)
+	if err == nil || !strings.Contains(err.Error(), "already taken") {
+		t.Errorf("resubmitDone() with a used completion ID error = %v, want it reported as taken", err)
+	}
+}
+
 func TestSubmitDone_Success(t *testing.T) {
 	t.Parallel()
 	store := newFakeWLCommonsStore()
@@ -100,3 +229,456 @@ func TestSubmitDone_NotFound(t *testing.T) {
 		t.Fatal("submitDone() expected error for missing item")
 	}
 }
+
+func TestSubmitDraft_ThenFinalize(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+
+	if err := submitDraft(store, "w-abc", "my-rig", "https://github.com/pr/1", "c-draft1"); err != nil {
+		t.Fatalf("submitDraft() error: %v", err)
+	}
+	item, _ := store.QueryWanted("w-abc")
+	if item.Status != "draft" {
+		t.Errorf("after draft: Status = %q, want %q", item.Status, "draft")
+	}
+	if item.ClaimedBy != "my-rig" {
+		t.Errorf("after draft: ClaimedBy = %q, want %q", item.ClaimedBy, "my-rig")
+	}
+
+	if err := finalizeDone(store, "w-abc", "my-rig", ""); err != nil {
+		t.Fatalf("finalizeDone() error: %v", err)
+	}
+	item, _ = store.QueryWanted("w-abc")
+	if item.Status != "in_review" {
+		t.Errorf("after final: Status = %q, want %q", item.Status, "in_review")
+	}
+}
+
+func TestSubmitDone_RejectsDraft(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+	_ = store.SubmitDraftCompletion("c-draft1", "w-abc", "my-rig", "wip")
+
+	// Plain done keeps its claimed→in_review contract; drafts need --final.
+	if err := submitDone(store, "w-abc", "my-rig", "evidence", "c-test"); err == nil {
+		t.Fatal("submitDone() expected error for draft item")
+	}
+}
+
+func TestSubmitDraft_ForbiddenByStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.settings[doltserver.SettingWorkflowTransitions] = "open>claimed,claimed>in_review,in_review>completed"
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+
+	err := submitDraft(store, "w-abc", "my-rig", "wip", "c-draft1")
+	if err == nil || !strings.Contains(err.Error(), "cannot move from claimed to draft") {
+		t.Fatalf("submitDraft() error = %v, want transition error", err)
+	}
+	if item, _ := store.QueryWanted("w-abc"); item.Status != "claimed" {
+		t.Errorf("Status = %q, want claimed (nothing written)", item.Status)
+	}
+
+	if err := submitDone(store, "w-abc", "my-rig", "evidence", "c-test"); err != nil {
+		t.Fatalf("submitDone() error under custom model: %v", err)
+	}
+}
+
+func TestFinalizeDone_Errors(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-claimed", Title: "No draft"})
+	_ = store.ClaimWanted("w-claimed", "my-rig")
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-other", Title: "Other draft"})
+	_ = store.ClaimWanted("w-other", "other-rig")
+	_ = store.SubmitDraftCompletion("c-other", "w-other", "other-rig", "wip")
+
+	if err := finalizeDone(store, "w-claimed", "my-rig", ""); err == nil {
+		t.Error("finalizeDone() expected error for item without draft")
+	}
+	if err := finalizeDone(store, "w-other", "my-rig", ""); err == nil {
+		t.Error("finalizeDone() expected error for another rig's draft")
+	}
+}
+
+func TestResubmitDone_SupersedesPrevious(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+	if err := submitDone(store, "w-abc", "my-rig", "pr/1", "c-old"); err != nil {
+		t.Fatalf("submitDone() error: %v", err)
+	}
+	// Simulate a rejection returning the item to the claimant.
+	store.items["w-abc"].Status = "claimed"
+
+	if err := resubmitDone(store, "w-abc", "my-rig", "pr/2", "c-new", "c-old", false); err != nil {
+		t.Fatalf("resubmitDone() error: %v", err)
+	}
+
+	got := store.completions["w-abc"]
+	if len(got) != 2 {
+		t.Fatalf("completions = %+v, want 2", got)
+	}
+	if got[0].ID != "c-old" || got[0].SupersededBy != "c-new" {
+		t.Errorf("old completion = %+v, want superseded by c-new", got[0])
+	}
+	if got[1].ID != "c-new" || got[1].SupersededBy != "" {
+		t.Errorf("new completion = %+v, want current", got[1])
+	}
+	if item, _ := store.QueryWanted("w-abc"); item.Status != "in_review" {
+		t.Errorf("Status = %q, want in_review", item.Status)
+	}
+}
+
+func TestDone_CompletionCountAcrossResubmissions(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+	if err := submitDone(store, "w-abc", "my-rig", "pr/1", "c-1"); err != nil {
+		t.Fatalf("submitDone() error: %v", err)
+	}
+
+	prev := "c-1"
+	for i, id := range []string{"c-2", "c-3"} {
+		store.items["w-abc"].Status = "claimed"
+		if err := resubmitDone(store, "w-abc", "my-rig", "pr/"+id, id, prev, false); err != nil {
+			t.Fatalf("resubmitDone(%s) error: %v", id, err)
+		}
+		prev = id
+
+		item, _ := store.QueryWanted("w-abc")
+		if want := i + 2; item.CompletionCount != want {
+			t.Errorf("CompletionCount after %s = %d, want %d", id, item.CompletionCount, want)
+		}
+	}
+
+	item, _ := store.QueryWanted("w-abc")
+	if !isDisputed(item.CompletionCount, defaultDisputeThreshold) {
+		t.Errorf("item with %d completions should be disputed at threshold %d", item.CompletionCount, defaultDisputeThreshold)
+	}
+}
+
+func TestAmendDone(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.AddGroupMembers("squad", "my-rig", []string{"my-rig", "partner-rig"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	_ = store.ClaimWantedForGroup("w-abc", "my-rig", "squad")
+
+	if _, err := amendDone(store, "w-abc", "my-rig", "pr/2"); err == nil || !strings.Contains(err.Error(), "not in review") {
+		t.Fatalf("amendDone() before submission error = %v, want not in review", err)
+	}
+	if err := submitDone(store, "w-abc", "my-rig", "pr/1", "c-1"); err != nil {
+		t.Fatalf("submitDone() error: %v", err)
+	}
+
+	if _, err := amendDone(store, "w-abc", "partner-rig", "pr/2"); err == nil || !strings.Contains(err.Error(), "only that rig can amend") {
+		t.Errorf("amendDone() by another group member error = %v, want completer-only error", err)
+	}
+	if _, err := amendDone(store, "w-abc", "my-rig", "pr/1"); err == nil {
+		t.Error("amendDone() with unchanged evidence should fail")
+	}
+
+	id, err := amendDone(store, "w-abc", "my-rig", "pr/2")
+	if err != nil {
+		t.Fatalf("amendDone() error: %v", err)
+	}
+	if id != "c-1" {
+		t.Errorf("amended completion = %q, want c-1", id)
+	}
+	detail, _ := store.QueryWantedDetail("w-abc")
+	if len(detail.Completions) != 1 || detail.Completions[0].Evidence != "pr/2" {
+		t.Errorf("completions = %+v, want one completion with evidence pr/2", detail.Completions)
+	}
+	if detail.Item.Status != doltserver.StatusInReview || detail.Item.CompletionCount != 1 {
+		t.Errorf("item = status %q count %d, want in_review with 1 completion", detail.Item.Status, detail.Item.CompletionCount)
+	}
+}
+
+func TestCommitThenNotify_NotificationFailureKeepsCompletion(t *testing.T) {
+	orig := sendWlNotification
+	t.Cleanup(func() { sendWlNotification = orig })
+
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+	_ = store.AddWatcher("w-abc", "watcher", "mayor/")
+
+	var sawCommitted bool
+	sendWlNotification = func(townRoot string, msg *mail.Message) error {
+		// The completion must already be on the board when mail goes out.
+		item, _ := store.QueryWanted("w-abc")
+		sawCommitted = item.Status == "in_review" && len(store.completions["w-abc"]) == 1
+		return fmt.Errorf("bd unavailable")
+	}
+
+	townRoot := t.TempDir()
+	err := commitThenNotify(store, townRoot, "w-abc", "my-rig", "done", "submitted for review", func() error {
+		return submitDone(store, "w-abc", "my-rig", "pr/1", "c-1")
+	})
+	if err != nil {
+		t.Fatalf("commitThenNotify() error = %v, want nil on notification failure", err)
+	}
+	if !sawCommitted {
+		t.Error("notification was attempted before the completion committed")
+	}
+
+	item, _ := store.QueryWanted("w-abc")
+	if item.Status != "in_review" {
+		t.Errorf("Status = %q, want in_review", item.Status)
+	}
+	if got := store.completions["w-abc"]; len(got) != 1 || got[0].ID != "c-1" || got[0].Evidence != "pr/1" {
+		t.Errorf("completions = %+v, want one c-1", got)
+	}
+
+	entries, err := wasteland.ReadClaimLog(townRoot)
+	if err != nil {
+		t.Fatalf("ReadClaimLog() error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("claim log = %+v, want done then notify", entries)
+	}
+	if entries[0].Action != "done" || entries[0].Outcome != wasteland.ClaimLogOK {
+		t.Errorf("first entry = %+v, want ok done", entries[0])
+	}
+	if entries[1].Action != "notify" || entries[1].Outcome != wasteland.ClaimLogError || !strings.Contains(entries[1].Detail, "bd unavailable") {
+		t.Errorf("second entry = %+v, want notify error", entries[1])
+	}
+}
+
+func TestCommitThenNotifyPoster_MailsThePoster(t *testing.T) {
+	orig := sendWlNotification
+	t.Cleanup(func() { sendWlNotification = orig })
+	var sent []*mail.Message
+	sendWlNotification = func(townRoot string, msg *mail.Message) error {
+		sent = append(sent, msg)
+		return nil
+	}
+
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug", PostedBy: "poster-rig"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-sub", Title: "Subscribed poster", PostedBy: "poster-rig"})
+	_ = store.AddWatcher("w-sub", "poster-rig", "gastown/crew/max")
+	_ = store.AddWatcher("w-sub", "watcher", "mayor/")
+	for _, id := range []string{"w-abc", "w-sub"} {
+		_ = store.ClaimWanted(id, "my-rig")
+		err := commitThenNotifyPoster(store, t.TempDir(), id, "my-rig", "done", "submitted for review", func() error {
+			return submitDone(store, id, "my-rig", "pr/1", "c-"+id)
+		})
+		if err != nil {
+			t.Fatalf("commitThenNotifyPoster(%s) error: %v", id, err)
+		}
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(sent))
+	}
+	if sent[0].To != defaultWatcherAddress || len(sent[0].CC) != 0 {
+		t.Errorf("unsubscribed poster mailed at %q cc %v, want %q", sent[0].To, sent[0].CC, defaultWatcherAddress)
+	}
+	// A poster watching its own item keeps its own address.
+	got := append([]string{sent[1].To}, sent[1].CC...)
+	slices.Sort(got)
+	if want := []string{"gastown/crew/max", "mayor/"}; !slices.Equal(got, want) {
+		t.Errorf("subscribed poster recipients = %v, want %v", got, want)
+	}
+}
+
+func TestCommitThenNotify_WriteFailureSkipsNotification(t *testing.T) {
+	orig := sendWlNotification
+	t.Cleanup(func() { sendWlNotification = orig })
+	sendWlNotification = func(string, *mail.Message) error {
+		t.Error("no notification should be sent when the write fails")
+		return nil
+	}
+
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	_ = store.AddWatcher("w-abc", "watcher", "mayor/")
+
+	err := commitThenNotify(store, t.TempDir(), "w-abc", "my-rig", "done", "submitted for review", func() error {
+		return submitDone(store, "w-abc", "my-rig", "pr/1", "c-1")
+	})
+	if err == nil {
+		t.Fatal("commitThenNotify() should surface the write error for an unclaimed item")
+	}
+}
+
+func TestResubmitDone_UnknownCompletion(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+
+	if err := resubmitDone(store, "w-abc", "my-rig", "pr/2", "c-new", "c-missing", false); err == nil {
+		t.Error("resubmitDone() should fail when the superseded completion does not exist")
+	}
+	if got := store.completions["w-abc"]; len(got) != 0 {
+		t.Errorf("completions = %+v, want none written", got)
+	}
+}
+
+func TestEvidenceFromGitNotes(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if _, err := evidenceFromGitNotes(dir, "HEAD", ""); err == nil || !strings.Contains(err.Error(), "not a git repository") {
+		t.Fatalf("non-repo error = %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=T", "commit", "-q", "--allow-empty", "-m", "work"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if _, err := evidenceFromGitNotes(dir, "HEAD", ""); err == nil || !strings.Contains(err.Error(), "no git note on HEAD") {
+		t.Fatalf("missing note error = %v", err)
+	}
+	if got, err := evidenceFromGitNotes(dir, "HEAD", "fallback"); err != nil || got != "fallback" {
+		t.Fatalf("fallback = %q, %v", got, err)
+	}
+
+	cmd := exec.Command("git", "-c", "user.email=t@example.com", "-c", "user.name=T", "notes", "add", "-m", "https://github.com/org/repo/pull/7\n", "HEAD")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git notes add: %v\n%s", err, out)
+	}
+	if got, err := evidenceFromGitNotes(dir, "HEAD", "fallback"); err != nil || got != "https://github.com/org/repo/pull/7" {
+		t.Fatalf("note evidence = %q, %v", got, err)
+	}
+}
+
+func TestCheckCompletionNote(t *testing.T) {
+	t.Parallel()
+	required := map[string]string{wlSettingRequireClaimNote: "true"}
+	newStore := func() *fakeWLCommonsStore {
+		store := newFakeWLCommonsStore()
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+		_ = store.ClaimWanted("w-abc", "my-rig")
+		return store
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]string
+		comment  [2]string // author, body
+		summary  string
+		wantErr  bool
+	}{
+		{name: "relaxed without note", settings: map[string]string{}},
+		{name: "relaxed explicitly", settings: map[string]string{wlSettingRequireClaimNote: "false"}},
+		{name: "required without note", settings: required, wantErr: true},
+		{name: "required with claim note", settings: required, comment: [2]string{"my-rig", "Rewrote the token check"}},
+		{name: "required with summary", settings: required, summary: "Rewrote the token check"},
+		{name: "blank summary does not count", settings: required, summary: "  ", wantErr: true},
+		{name: "note from another rig does not count", settings: required, comment: [2]string{"bystander", "+1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := newStore()
+			if tt.comment[0] != "" {
+				_ = store.AddComment("w-abc", tt.comment[0], tt.comment[1])
+			}
+			err := checkCompletionNote(store, tt.settings, "w-abc", "my-rig", tt.summary)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "gt wl comment w-abc --note") {
+					t.Fatalf("checkCompletionNote() error = %v, want claim-note error with remedy", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkCompletionNote() error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordDoneSummary(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+
+	if err := recordDoneSummary(store, "w-abc", "my-rig", ""); err != nil || len(store.comments["w-abc"]) != 0 {
+		t.Fatalf("recordDoneSummary(empty) = %v, comments %v; want nothing recorded", err, store.comments["w-abc"])
+	}
+	if err := recordDoneSummary(store, "w-abc", "my-rig", "Rewrote the token check"); err != nil {
+		t.Fatalf("recordDoneSummary() error: %v", err)
+	}
+	if got := store.comments["w-abc"]; len(got) != 1 || got[0].Author != "my-rig" {
+		t.Errorf("comments = %+v, want one by my-rig", got)
+	}
+}
+
+func TestReadDoneEvidence(t *testing.T) {
+	t.Parallel()
+	report := "## Report\n\n- fixed it's edge case\n-- done\n"
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSuffix(report, "\n")
+
+	if got, err := readDoneEvidence("https://example.com/pr/1", "", nil); err != nil || got != "https://example.com/pr/1" {
+		t.Errorf("inline evidence = %q, %v", got, err)
+	}
+	if got, err := readDoneEvidence("", path, nil); err != nil || got != want {
+		t.Errorf("--evidence-file = %q, %v; want %q", got, err, want)
+	}
+	if got, err := readDoneEvidence("-", "", strings.NewReader(report)); err != nil || got != want {
+		t.Errorf("--evidence - = %q, %v; want %q", got, err, want)
+	}
+
+	for name, call := range map[string]func() (string, error){
+		"missing file": func() (string, error) { return readDoneEvidence("", filepath.Join(t.TempDir(), "nope"), nil) },
+		"empty stdin":  func() (string, error) { return readDoneEvidence("-", "", strings.NewReader("\n\n")) },
+		"oversized": func() (string, error) {
+			return readDoneEvidence("-", "", strings.NewReader(strings.Repeat("x", maxEvidenceBytes+1)))
+		},
+	} {
+		if _, err := call(); err == nil {
+			t.Errorf("%s: readDoneEvidence() should fail", name)
+		}
+	}
+}
+
+func TestValidateDoneEvidence(t *testing.T) {
+	t.Parallel()
+	for _, ev := range []string{
+		"https://github.com/org/repo/pull/123",
+		"http://example.com",
+		"HTTPS://Example.com/a?b=c#d",
+		"https://github.com/org/repo/pull/123 rebased onto main",
+		"commit abc123def",
+		"abc123def",
+		"fixed in https:/typo but not a link",
+		"",
+	} {
+		if err := validateDoneEvidence(ev); err != nil {
+			t.Errorf("validateDoneEvidence(%q) = %v, want nil", ev, err)
+		}
+	}
+	for _, ev := range []string{
+		"https://",
+		"https:///org/repo/pull/1",
+		"http://example.com:port/x",
+		"https://%zz/pull/1",
+		"https://[::1/pull/1",
+	} {
+		if err := validateDoneEvidence(ev); err == nil {
+			t.Errorf("validateDoneEvidence(%q) should fail", ev)
+		}
+	}
+}
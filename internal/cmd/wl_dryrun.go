@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// wlDryRun is the --dry-run flag shared by gt wl subcommands. Commands with
+// a --dry-run of their own (claim, reap, reindex, merge-duplicates, sync)
+// shadow it and keep their own preview.
+var wlDryRun bool
+
+// wlNoDryRunCommands act outside the commons database, so printing its SQL
+// would not show what they do.
+var wlNoDryRunCommands = map[string]bool{
+	"join":  true,
+	"bench": true,
+}
+
+func init() {
+	wlCmd.PersistentFlags().BoolVar(&wlDryRun, "dry-run", false, "Print the SQL writes would run, without running them")
+}
+
+// selectWLDryRun turns on dry-run SQL output for cmd when --dry-run is set.
+// The SQL goes to stdout, or to stderr under --json so the result stays
+// parseable.
+func selectWLDryRun(cmd *cobra.Command) error {
+	if !wlDryRun {
+		doltserver.SetWLDryRun(nil)
+		return nil
+	}
+	if wlNoDryRunCommands[cmd.Name()] {
+		return fmt.Errorf("%s does not support --dry-run", buildCommandPath(cmd))
+	}
+	var w io.Writer = os.Stdout
+	if wlJSON {
+		w = os.Stderr
+	}
+	doltserver.SetWLDryRun(w)
+	return nil
+}
+
+// wlSuccessMark starts the line a write command prints once it is done.
+// Under --dry-run nothing was written, so the line says so instead.
+func wlSuccessMark() string {
+	if doltserver.WLDryRun() {
+		return style.Dim.Render("dry run, not executed:")
+	}
+	return style.Bold.Render("✓")
+}
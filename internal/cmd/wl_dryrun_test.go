@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestSelectWLDryRun(t *testing.T) {
+	t.Cleanup(func() {
+		wlDryRun = false
+		doltserver.SetWLDryRun(nil)
+	})
+
+	wlDryRun = true
+	if err := selectWLDryRun(wlDoneCmd); err != nil {
+		t.Fatalf("selectWLDryRun(done) error: %v", err)
+	}
+	if !doltserver.WLDryRun() {
+		t.Error("--dry-run on done did not turn on dry-run SQL")
+	}
+	if err := selectWLDryRun(wlJoinCmd); err == nil || !strings.Contains(err.Error(), "does not support --dry-run") {
+		t.Errorf("selectWLDryRun(join) error = %v, want unsupported", err)
+	}
+
+	wlDryRun = false
+	if err := selectWLDryRun(wlDoneCmd); err != nil {
+		t.Fatalf("selectWLDryRun(done) error: %v", err)
+	}
+	if doltserver.WLDryRun() {
+		t.Error("dry-run SQL still on without --dry-run")
+	}
+
+	// Commands with their own --dry-run keep it.
+	if f := wlClaimCmd.Flags().Lookup("dry-run"); f == nil || f.Usage == wlCmd.PersistentFlags().Lookup("dry-run").Usage {
+		t.Error("claim --dry-run should be claim's own flag")
+	}
+}
+
+func TestWlSuccessMark_DryRun(t *testing.T) {
+	t.Cleanup(func() { doltserver.SetWLDryRun(nil) })
+
+	if mark := wlSuccessMark(); strings.Contains(mark, "dry run") {
+		t.Errorf("wlSuccessMark() = %q outside a dry run", mark)
+	}
+	doltserver.SetWLDryRun(io.Discard)
+	if mark := wlSuccessMark(); !strings.Contains(mark, "dry run, not executed") {
+		t.Errorf("wlSuccessMark() = %q under --dry-run, want it to say nothing ran", mark)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// runNoteEditor opens path in the user's editor. It is a var so tests can
+// substitute a fake editor.
+var runNoteEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// EDITOR may carry arguments (e.g. "code --wait").
+	parts := strings.Fields(editor)
+	editorCmd := exec.Command(parts[0], append(parts[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	return editorCmd.Run()
+}
+
+// noteFromEditor opens the editor on a template and returns the saved text,
+// git-commit style: lines starting with '#' are dropped and surrounding
+// whitespace is trimmed. An empty result aborts the operation.
+func noteFromEditor(what, hint string) (string, error) {
+	f, err := os.CreateTemp("", "gt-wl-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("creating %s file: %w", what, err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	template := fmt.Sprintf("\n# Enter the %s for %s.\n# Lines starting with '#' are ignored; an empty %s aborts.\n", what, hint, what)
+	if _, err := f.WriteString(template); err != nil {
+		f.Close()
+		return "", fmt.Errorf("writing %s file: %w", what, err)
+	}
+	f.Close()
+
+	if err := runNoteEditor(path); err != nil {
+		return "", fmt.Errorf("running editor: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s file: %w", what, err)
+	}
+
+	var kept []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	text := strings.TrimSpace(strings.Join(kept, "\n"))
+	if text == "" {
+		return "", fmt.Errorf("aborting: empty %s", what)
+	}
+	return text, nil
+}
+
+// resolveNote returns the note text from --note or, with --edit, the editor.
+// An explicit --note wins over --edit.
+func resolveNote(note string, edit bool, what, hint string) (string, error) {
+	if note != "" || !edit {
+		return note, nil
+	}
+	return noteFromEditor(what, hint)
+}
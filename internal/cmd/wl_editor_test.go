@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// stubNoteEditor replaces runNoteEditor with one that writes content.
+func stubNoteEditor(t *testing.T, content string) *bool {
+	t.Helper()
+	orig := runNoteEditor
+	t.Cleanup(func() { runNoteEditor = orig })
+
+	called := false
+	runNoteEditor = func(path string) error {
+		called = true
+		return os.WriteFile(path, []byte(content), 0644)
+	}
+	return &called
+}
+
+func TestNoteFromEditor_StripsCommentLines(t *testing.T) {
+	stubNoteEditor(t, "# template header\n\nTaking the parser half.\n# trailing hint\nAuth is free.\n\n")
+
+	got, err := noteFromEditor("claim note", "w-abc")
+	if err != nil {
+		t.Fatalf("noteFromEditor() error: %v", err)
+	}
+	if want := "Taking the parser half.\nAuth is free."; got != want {
+		t.Errorf("noteFromEditor() = %q, want %q", got, want)
+	}
+}
+
+func TestNoteFromEditor_EmptyAborts(t *testing.T) {
+	stubNoteEditor(t, "# only comments\n\n   \n")
+
+	_, err := noteFromEditor("comment", "w-abc")
+	if err == nil || !strings.Contains(err.Error(), "aborting: empty comment") {
+		t.Errorf("noteFromEditor() error = %v, want empty abort", err)
+	}
+}
+
+func TestResolveNote_NoteFlagWins(t *testing.T) {
+	called := stubNoteEditor(t, "from editor")
+
+	got, err := resolveNote("from flag", true, "comment", "w-abc")
+	if err != nil {
+		t.Fatalf("resolveNote() error: %v", err)
+	}
+	if got != "from flag" {
+		t.Errorf("resolveNote() = %q, want %q", got, "from flag")
+	}
+	if *called {
+		t.Error("editor should not open when --note is provided")
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Defaults for evidence liveness checks.
+const (
+	defaultEvidenceConcurrency = 8
+	defaultEvidenceTimeout     = 2 * time.Minute
+	evidenceRequestTimeout     = 15 * time.Second
+)
+
+var (
+	wlVerifyConcurrency int
+	wlVerifyTimeout     time.Duration
+	wlAuditStatus       string
+)
+
+var wlAuditEvidenceCmd = &cobra.Command{
+	Use:   "audit-evidence",
+	Short: "Check that the evidence links of completions still resolve",
+	Long: `Check every completion on the board for link rot.
+
+Each completion whose evidence is an http(s) URL gets a HEAD request (a GET
+when the server refuses HEAD); an error or a 4xx/5xx status marks it dead.
+Evidence that is not a URL (a commit hash, a description) is skipped.
+Requests run --concurrency at a time, and the whole audit stops at
+--timeout. Exits non-zero when any link is dead.
+
+By default only completed items are audited; --status picks another
+status, or "all".
+
+Examples:
+  gt wl audit-evidence
+  gt wl audit-evidence --status all --concurrency 16 --timeout 5m`,
+	Args: cobra.NoArgs,
+	RunE: runWlAuditEvidence,
+}
+
+func init() {
+	wlAuditEvidenceCmd.Flags().StringVar(&wlAuditStatus, "status", doltserver.StatusCompleted, `Audit items with this status ("all" for every item)`)
+	addEvidenceVerifyFlags(wlAuditEvidenceCmd)
+
+	wlCmd.AddCommand(wlAuditEvidenceCmd)
+}
+
+// addEvidenceVerifyFlags registers the flags shared by every command that
+// checks evidence links.
+func addEvidenceVerifyFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&wlVerifyConcurrency, "concurrency", defaultEvidenceConcurrency, "Check at most N evidence links at once")
+	cmd.Flags().DurationVar(&wlVerifyTimeout, "timeout", defaultEvidenceTimeout, "Stop checking evidence links after this long")
+}
+
+func runWlAuditEvidence(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	status := wlAuditStatus
+	if status == "all" {
+		status = ""
+	}
+	store := doltserver.NewWLCommons(townRoot)
+	items, err := store.ListWanted(doltserver.WantedFilter{Status: status, MinPriority: -1, MaxPriority: -1})
+	if err != nil {
+		return fmt.Errorf("listing wanted items: %w", err)
+	}
+	var completions []doltserver.WantedCompletion
+	for _, item := range items {
+		detail, err := store.QueryWantedDetail(item.ID)
+		if err != nil {
+			return fmt.Errorf("querying %s: %w", item.ID, err)
+		}
+		completions = append(completions, detail.Completions...)
+	}
+	return verifyAndReportEvidence(cmd.Context(), os.Stdout, completions)
+}
+
+// verifyAndReportEvidence checks the evidence of completions with the
+// --concurrency and --timeout flags, prints the results, and returns an
+// error when any link is dead.
+func verifyAndReportEvidence(ctx context.Context, w io.Writer, completions []doltserver.WantedCompletion) error {
+	if wlVerifyConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be >= 1, got %d", wlVerifyConcurrency)
+	}
+	ctx, cancel := context.WithTimeout(ctx, wlVerifyTimeout)
+	defer cancel()
+
+	results := verifyEvidence(ctx, http.DefaultClient, completions, wlVerifyConcurrency)
+	dead := reportEvidenceResults(w, results, len(completions))
+	if dead > 0 {
+		return fmt.Errorf("%d evidence link(s) are dead", dead)
+	}
+	return nil
+}
+
+// evidenceResult is the outcome of checking one completion's evidence URL.
+type evidenceResult struct {
+	Completion doltserver.WantedCompletion
+	// Status is the HTTP status code, or 0 when the request failed.
+	Status int
+	Err    error
+}
+
+// Dead reports whether the evidence link no longer resolves.
+func (r evidenceResult) Dead() bool { return r.Err != nil || r.Status >= 400 }
+
+// isEvidenceURL reports whether evidence is an http(s) URL worth checking.
+func isEvidenceURL(evidence string) bool {
+	u, err := url.Parse(strings.TrimSpace(evidence))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// verifyEvidence checks the URL evidence of completions, at most
+// concurrency at a time, and returns one result per URL in input order.
+// Non-URL evidence is skipped. Checks still pending when ctx ends fail
+// with its error.
+func verifyEvidence(ctx context.Context, client *http.Client, completions []doltserver.WantedCompletion, concurrency int) []evidenceResult {
+	var results []evidenceResult
+	for _, c := range completions {
+		if isEvidenceURL(c.Evidence) {
+			results = append(results, evidenceResult{Completion: c})
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(r *evidenceResult) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+			r.Status, r.Err = checkEvidenceURL(ctx, client, strings.TrimSpace(r.Completion.Evidence))
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// checkEvidenceURL returns the status of a HEAD request for rawURL,
+// falling back to GET for servers that do not allow HEAD.
+func checkEvidenceURL(ctx context.Context, client *http.Client, rawURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, evidenceRequestTimeout)
+	defer cancel()
+
+	status, err := evidenceRequest(ctx, client, http.MethodHead, rawURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = evidenceRequest(ctx, client, http.MethodGet, rawURL)
+	}
+	return status, err
+}
+
+func evidenceRequest(ctx context.Context, client *http.Client, method, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// reportEvidenceResults prints each checked link and a summary line, and
+// returns how many links are dead. total counts every completion,
+// including those skipped for non-URL evidence.
+func reportEvidenceResults(w io.Writer, results []evidenceResult, total int) int {
+	dead := 0
+	for _, r := range results {
+		c := r.Completion
+		switch {
+		case r.Err != nil:
+			dead++
+			fmt.Fprintf(w, "  %s %s %s  %s\n", style.ErrorPrefix, c.ID, c.Evidence, style.Dim.Render(r.Err.Error()))
+		case r.Dead():
+			dead++
+			fmt.Fprintf(w, "  %s %s %s  %s\n", style.ErrorPrefix, c.ID, c.Evidence, style.Dim.Render(fmt.Sprintf("HTTP %d", r.Status)))
+		default:
+			fmt.Fprintf(w, "  %s %s %s\n", style.SuccessPrefix, c.ID, c.Evidence)
+		}
+	}
+	fmt.Fprintf(w, "\nChecked %d link(s): %d dead, %d skipped (not a URL).\n", len(results), dead, total-len(results))
+	return dead
+}
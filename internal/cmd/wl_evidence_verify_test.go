@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestVerifyEvidence_MixedEndpoints(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", http.NotFound)
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	live := httptest.NewServer(mux)
+	defer live.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	completions := []doltserver.WantedCompletion{
+		{ID: "c-ok", Evidence: live.URL + "/ok"},
+		{ID: "c-gone", Evidence: live.URL + "/gone"},
+		{ID: "c-get", Evidence: live.URL + "/get-only"},
+		{ID: "c-down", Evidence: downURL + "/pr/1"},
+		{ID: "c-hash", Evidence: "abc123def"},
+		{ID: "c-text", Evidence: "merged in the release branch"},
+	}
+	results := verifyEvidence(context.Background(), live.Client(), completions, 2)
+	if len(results) != 4 {
+		t.Fatalf("checked %d links, want 4 (non-URL evidence skipped)", len(results))
+	}
+	dead := map[string]bool{}
+	for _, r := range results {
+		dead[r.Completion.ID] = r.Dead()
+	}
+	want := map[string]bool{"c-ok": false, "c-gone": true, "c-get": false, "c-down": true}
+	for id, w := range want {
+		if dead[id] != w {
+			t.Errorf("%s dead = %v, want %v", id, dead[id], w)
+		}
+	}
+
+	var buf bytes.Buffer
+	if n := reportEvidenceResults(&buf, results, len(completions)); n != 2 {
+		t.Errorf("reportEvidenceResults() = %d dead, want 2", n)
+	}
+	if out := buf.String(); !strings.Contains(out, "HTTP 404") || !strings.Contains(out, "2 dead, 2 skipped") {
+		t.Errorf("report =\n%s", out)
+	}
+}
+
+func TestVerifyEvidence_HonorsContext(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	results := verifyEvidence(ctx, slow.Client(), []doltserver.WantedCompletion{
+		{ID: "c-1", Evidence: slow.URL + "/1"},
+		{ID: "c-2", Evidence: slow.URL + "/2"},
+	}, 1)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("verifyEvidence() took %s, want it to stop at the context deadline", elapsed)
+	}
+	for _, r := range results {
+		if !r.Dead() {
+			t.Errorf("%s should fail once the context ends", r.Completion.ID)
+		}
+	}
+}
+
+func TestIsEvidenceURL(t *testing.T) {
+	t.Parallel()
+	for evidence, want := range map[string]bool{
+		"https://github.com/o/r/pull/1": true,
+		" http://example.com ":          true,
+		"ftp://example.com/file":        false,
+		"https://":                      false,
+		"abc123":                        false,
+		"see PR 12":                     false,
+	} {
+		if got := isEvidenceURL(evidence); got != want {
+			t.Errorf("isEvidenceURL(%q) = %v, want %v", evidence, got, want)
+		}
+	}
+}
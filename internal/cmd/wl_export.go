@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+var (
+	wlExportFormat string
+	wlExportSince  string
+	wlExportStatus string
+	wlExportOutput string
+)
+
+var wlExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export wanted items as JSON or CSV",
+	Long: `Export wanted items from the local wl-commons database.
+
+--format json (the default) writes a JSON array of items; --format csv
+writes a header row and one row per item. Rows are ordered by updated_at,
+oldest first.
+
+--since narrows the export to what changed, for incremental syncs:
+  - a timestamp (RFC 3339, "2006-01-02 15:04:05" UTC, or a date) keeps
+    items whose updated_at is after it;
+  - anything else is read as a Dolt commit (hash, branch, or HEAD~N) and
+    keeps items added or modified since that commit, per dolt_diff.
+Items deleted since then are not reported.
+
+--status keeps only items in one status. --output writes the export to a
+file instead of stdout; the file is replaced only once the whole export
+has been written, so a failed export leaves the previous one in place.
+
+This is a bulk dump of the whole board for spreadsheets and offline
+analysis; --json on gt wl list and gt wl show covers individual queries.
+
+Examples:
+  gt wl export > board.json
+  gt wl export --format csv --output board.csv
+  gt wl export --format csv --status open
+  gt wl export --format csv --since 2026-10-01
+  gt wl export --since HEAD~10 --compact`,
+	Args: cobra.NoArgs,
+	RunE: runWlExport,
+}
+
+func init() {
+	wlExportCmd.Flags().StringVar(&wlExportFormat, "format", "json", "Output format: json or csv")
+	wlExportCmd.Flags().StringVar(&wlExportSince, "since", "", "Only items changed after a timestamp or since a Dolt commit")
+	wlExportCmd.Flags().StringVar(&wlExportStatus, "status", "", "Only items in this status")
+	wlExportCmd.Flags().StringVarP(&wlExportOutput, "output", "o", "", "Write the export to this file instead of stdout")
+
+	wlCmd.AddCommand(wlExportCmd)
+}
+
+func runWlExport(cmd *cobra.Command, args []string) error {
+	if wlExportFormat != "json" && wlExportFormat != "csv" {
+		return fmt.Errorf("--format must be json or csv, got %q", wlExportFormat)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	filter := parseExportSince(wlExportSince)
+	filter.Status = wlExportStatus
+	if filter.Status != "" {
+		if err := requireKnownStatus(store, filter.Status); err != nil {
+			return err
+		}
+	}
+
+	if wlExportOutput == "" {
+		return exportWanted(os.Stdout, store, wlExportFormat, filter, wlJSONPrettyOutput())
+	}
+	var buf bytes.Buffer
+	if err := exportWanted(&buf, store, wlExportFormat, filter, wlJSONPrettyOutput()); err != nil {
+		return err
+	}
+	if err := writeWlFileAtomic(wlExportOutput, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing %s: %w", wlExportOutput, err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported to %s\n", wlExportOutput)
+	return nil
+}
+
+// exportTimestampLayouts are the --since forms read as timestamps.
+var exportTimestampLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// parseExportSince reads --since as a timestamp when it parses as one, and
+// as a Dolt commit reference otherwise.
+func parseExportSince(since string) doltserver.WantedExportFilter {
+	since = strings.TrimSpace(since)
+	if since == "" {
+		return doltserver.WantedExportFilter{}
+	}
+	for _, layout := range exportTimestampLayouts {
+		if t, err := time.Parse(layout, since); err == nil {
+			return doltserver.WantedExportFilter{UpdatedAfter: t}
+		}
+	}
+	return doltserver.WantedExportFilter{ChangedSince: since}
+}
+
+// wantedExportRow is one exported item, in both JSON and CSV column order.
+type wantedExportRow struct {
+	ID              string   `json:"id"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Project         string   `json:"project"`
+	Type            string   `json:"type"`
+	Priority        int      `json:"priority"`
+	Status          string   `json:"status"`
+	Tags            []string `json:"tags"`
+	PostedBy        string   `json:"posted_by"`
+	ClaimedBy       string   `json:"claimed_by"`
+	ClaimedGroup    string   `json:"claimed_group"`
+	EffortLevel     string   `json:"effort_level"`
+	CompletionCount int      `json:"completion_count"`
+	MergedInto      string   `json:"merged_into"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+var wantedExportCSVHeader = []string{
+	"id", "title", "description", "project", "type", "priority", "status", "tags",
+	"posted_by", "claimed_by", "claimed_group", "effort_level", "completion_count", "merged_into", "updated_at",
+}
+
+func newWantedExportRow(item *doltserver.WantedItem) wantedExportRow {
+	row := wantedExportRow{
+		ID:              item.ID,
+		Title:           item.Title,
+		Description:     item.Description,
+		Project:         item.Project,
+		Type:            item.Type,
+		Priority:        item.Priority,
+		Status:          item.Status,
+		Tags:            item.Tags,
+		PostedBy:        item.PostedBy,
+		ClaimedBy:       item.ClaimedBy,
+		ClaimedGroup:    item.ClaimedGroup,
+		EffortLevel:     item.EffortLevel,
+		CompletionCount: item.CompletionCount,
+		MergedInto:      item.MergedInto,
+	}
+	if row.Tags == nil {
+		row.Tags = []string{}
+	}
+	if !item.UpdatedAt.IsZero() {
+		row.UpdatedAt = item.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return row
+}
+
+func (r wantedExportRow) csvRecord() []string {
+	return []string{
+		r.ID, r.Title, r.Description, r.Project, r.Type, strconv.Itoa(r.Priority), r.Status, strings.Join(r.Tags, ","),
+		r.PostedBy, r.ClaimedBy, r.ClaimedGroup, r.EffortLevel, strconv.Itoa(r.CompletionCount), r.MergedInto, r.UpdatedAt,
+	}
+}
+
+// exportWanted writes the items matching filter to w in format; pretty
+// selects indented JSON.
+func exportWanted(w io.Writer, store doltserver.WLCommonsStore, format string, filter doltserver.WantedExportFilter, pretty bool) error {
+	items, err := store.ExportWanted(filter)
+	if err != nil {
+		return err
+	}
+	rows := make([]wantedExportRow, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, newWantedExportRow(item))
+	}
+
+	if format == "json" {
+		return writeWLJSON(w, rows, pretty)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(wantedExportCSVHeader); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	for _, r := range rows {
+		if err := cw.Write(r.csvRecord()); err != nil {
+			return fmt.Errorf("writing CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
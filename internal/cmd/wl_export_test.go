@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestExportWanted_SinceTimestampKeepsNewerRows(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	cutoff := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, item := range []*doltserver.WantedItem{
+		{ID: "w-old", Title: "Old", UpdatedAt: cutoff.Add(-time.Hour)},
+		{ID: "w-edge", Title: "At cutoff", UpdatedAt: cutoff},
+		{ID: "w-new", Title: "New, with comma", UpdatedAt: cutoff.Add(time.Hour), Tags: []string{"go", "auth"}},
+	} {
+		_ = store.InsertWanted(item)
+		store.items[item.ID].UpdatedAt = item.UpdatedAt
+	}
+	filter := parseExportSince("2026-10-01")
+
+	var js strings.Builder
+	if err := exportWanted(&js, store, "json", filter, false); err != nil {
+		t.Fatalf("exportWanted(json) error: %v", err)
+	}
+	var rows []wantedExportRow
+	if err := json.Unmarshal([]byte(js.String()), &rows); err != nil {
+		t.Fatalf("export is not JSON: %v\n%s", err, js.String())
+	}
+	if len(rows) != 1 || rows[0].ID != "w-new" || rows[0].UpdatedAt != "2026-10-01T01:00:00Z" {
+		t.Errorf("json rows = %+v, want only w-new", rows)
+	}
+
+	var out strings.Builder
+	if err := exportWanted(&out, store, "csv", filter, false); err != nil {
+		t.Fatalf("exportWanted(csv) error: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not CSV: %v\n%s", err, out.String())
+	}
+	if len(records) != 2 || records[0][0] != "id" || records[1][0] != "w-new" || records[1][1] != "New, with comma" || records[1][7] != "go,auth" {
+		t.Errorf("csv records = %q, want header plus w-new", records)
+	}
+}
+
+func TestExportWanted_SinceCommit(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-same", Title: "Untouched"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-claimed", Title: "Claimed later"})
+	store.commit("abc123")
+	_ = store.ClaimWanted("w-claimed", "my-rig")
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-added", Title: "Added later"})
+
+	var out strings.Builder
+	if err := exportWanted(&out, store, "json", parseExportSince("abc123"), false); err != nil {
+		t.Fatalf("exportWanted() error: %v", err)
+	}
+	var rows []wantedExportRow
+	_ = json.Unmarshal([]byte(out.String()), &rows)
+	var ids []string
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	if strings.Join(ids, ",") != "w-added,w-claimed" {
+		t.Errorf("exported %v, want [w-added w-claimed]", ids)
+	}
+}
+
+func TestParseExportSince(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want doltserver.WantedExportFilter
+	}{
+		{"", doltserver.WantedExportFilter{}},
+		{"2026-10-01T12:00:00Z", doltserver.WantedExportFilter{UpdatedAfter: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}},
+		{"2026-10-01 12:00:00", doltserver.WantedExportFilter{UpdatedAfter: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}},
+		{"HEAD~3", doltserver.WantedExportFilter{ChangedSince: "HEAD~3"}},
+		{"k2j4h5g6", doltserver.WantedExportFilter{ChangedSince: "k2j4h5g6"}},
+	}
+	for _, tt := range tests {
+		if got := parseExportSince(tt.in); got != tt.want {
+			t.Errorf("parseExportSince(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExportWanted_StatusFilterAndCSVQuoting(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-open", Title: `Fix "quoted", commas`})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-claimed", Title: "Claimed"})
+	_ = store.ClaimWanted("w-claimed", "my-rig")
+
+	var out strings.Builder
+	filter := doltserver.WantedExportFilter{Status: doltserver.StatusOpen}
+	if err := exportWanted(&out, store, "csv", filter, false); err != nil {
+		t.Fatalf("exportWanted(csv) error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"Fix ""quoted"", commas"`) {
+		t.Errorf("title not quoted for CSV:\n%s", out.String())
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not CSV: %v\n%s", err, out.String())
+	}
+	if len(records) != 2 || records[1][0] != "w-open" || records[1][1] != `Fix "quoted", commas` {
+		t.Errorf("csv records = %q, want header plus w-open", records)
+	}
+
+	if err := requireKnownStatus(store, "bogus"); err == nil {
+		t.Error("requireKnownStatus(bogus) should fail")
+	}
+}
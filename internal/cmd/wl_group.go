@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+var wlGroupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage groups of rigs that can hold a claim together",
+	Long: `Manage named groups of rigs.
+
+A wanted item claimed with gt wl claim --group <name> is held by the
+group: any member can submit, resubmit or finalize its completion.
+Groups are created by adding their first members.
+
+Examples:
+  gt wl group add auth-squad my-rig partner-rig
+  gt wl group members auth-squad`,
+	RunE: requireSubcommand,
+}
+
+var wlGroupAddCmd = &cobra.Command{
+	Use:   "add <group> <rig-handle>...",
+	Short: "Add rigs to a group, creating it if needed",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runWlGroupAdd,
+}
+
+var wlGroupMembersCmd = &cobra.Command{
+	Use:   "members <group>",
+	Short: "List the rigs in a group",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWlGroupMembers,
+}
+
+func init() {
+	wlGroupCmd.AddCommand(wlGroupAddCmd)
+	wlGroupCmd.AddCommand(wlGroupMembersCmd)
+
+	wlCmd.AddCommand(wlGroupCmd)
+}
+
+func runWlGroupAdd(cmd *cobra.Command, args []string) error {
+	group, rigs := args[0], args[1:]
+	if err := doltserver.ValidateGroupName(group); err != nil {
+		return err
+	}
+
+	store, rigHandle, err := openWlGroupStore()
+	if err != nil {
+		return err
+	}
+	if err := store.AddGroupMembers(group, rigHandle, rigs); err != nil {
+		return err
+	}
+	fmt.Printf("%s Added %s to group %s\n", wlSuccessMark(), strings.Join(rigs, ", "), group)
+	return nil
+}
+
+func runWlGroupMembers(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	store, _, err := openWlGroupStore()
+	if err != nil {
+		return err
+	}
+	members, err := store.QueryGroupMembers(group)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		fmt.Printf("Group %s has no members.\n", group)
+		return nil
+	}
+	for _, m := range members {
+		fmt.Println(m)
+	}
+	return nil
+}
+
+func openWlGroupStore() (doltserver.WLCommonsStore, string, error) {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return nil, "", err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return nil, "", err
+	}
+
+	return doltserver.NewWLCommons(townRoot), wlCfg.RigHandle, nil
+}
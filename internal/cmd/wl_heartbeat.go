@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// defaultClaimLease is how long a renewal extends a claim.
+const defaultClaimLease = 30 * time.Minute
+
+var (
+	wlHeartbeatLease    time.Duration
+	wlHeartbeatInterval time.Duration
+)
+
+var wlHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat <wanted-id>",
+	Short: "Keep a claim lease alive while working on an item",
+	Long: `Extend the claim lease (expires_at) on a wanted item you have claimed.
+
+Without --heartbeat-interval, the lease is renewed once and the command
+exits. With --heartbeat-interval, the command stays in the foreground and
+renews every interval until interrupted (Ctrl-C) or the claim is lost, so
+long-running work is not reclaimed while the process is alive. On Ctrl-C it
+stops renewing and leaves the last lease in place.
+
+The interval must be shorter than --lease so the lease never lapses between
+beats.
+
+Examples:
+  gt wl heartbeat w-abc123
+  gt wl heartbeat w-abc123 --heartbeat-interval 10m
+  gt wl heartbeat w-abc123 --lease 2h --heartbeat-interval 30m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlHeartbeat,
+}
+
+func init() {
+	wlHeartbeatCmd.Flags().DurationVar(&wlHeartbeatLease, "lease", defaultClaimLease, "How far past now each renewal extends the lease")
+	wlHeartbeatCmd.Flags().DurationVar(&wlHeartbeatInterval, "heartbeat-interval", 0, "Renew repeatedly at this interval until interrupted")
+
+	wlCmd.AddCommand(wlHeartbeatCmd)
+}
+
+func runWlHeartbeat(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+
+	if wlHeartbeatLease <= 0 {
+		return fmt.Errorf("--lease must be positive, got %s", wlHeartbeatLease)
+	}
+	if wlHeartbeatInterval < 0 {
+		return fmt.Errorf("--heartbeat-interval must be positive, got %s", wlHeartbeatInterval)
+	}
+	if wlHeartbeatInterval > 0 && wlHeartbeatInterval >= wlHeartbeatLease {
+		return fmt.Errorf("--heartbeat-interval (%s) must be shorter than --lease (%s)", wlHeartbeatInterval, wlHeartbeatLease)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+	rigHandle := wlCfg.RigHandle
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	renew := func() error {
+		expiresAt, err := renewClaim(store, wantedID, rigHandle, wlHeartbeatLease, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Lease on %s extended until %s\n", wlSuccessMark(), wantedID, expiresAt.Local().Format("15:04:05"))
+		return nil
+	}
+
+	if wlHeartbeatInterval == 0 {
+		return renew()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Heartbeat for %s every %s (Ctrl+C to stop)\n", wantedID, wlHeartbeatInterval)
+	if err := runHeartbeatLoop(ctx, wlHeartbeatInterval, renew); err != nil {
+		return err
+	}
+	fmt.Printf("Heartbeat stopped; lease on %s left in place.\n", wantedID)
+	return nil
+}
+
+// renewClaim extends rigHandle's lease on wantedID to now+lease and returns
+// the new expiry.
+func renewClaim(store doltserver.WLCommonsStore, wantedID, rigHandle string, lease time.Duration, now time.Time) (time.Time, error) {
+	if err := requireClaimedBy(store, wantedID, rigHandle); err != nil {
+		return time.Time{}, err
+	}
+
+	expiresAt := now.UTC().Add(lease).Truncate(time.Second)
+	if err := store.RenewClaim(wantedID, rigHandle, expiresAt); err != nil {
+		return time.Time{}, fmt.Errorf("renewing claim: %w", err)
+	}
+	return expiresAt, nil
+}
+
+// runHeartbeatLoop calls renew immediately and then every interval until ctx
+// is cancelled (returns nil) or a renewal fails (returns the error).
+func runHeartbeatLoop(ctx context.Context, interval time.Duration, renew func() error) error {
+	if err := renew(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := renew(); err != nil {
+				return err
+			}
+		}
+	}
+}
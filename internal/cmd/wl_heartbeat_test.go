@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestRenewClaim_ExtendsLease(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Long task"})
+	_ = store.ClaimWanted("w-abc", "my-rig")
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	got, err := renewClaim(store, "w-abc", "my-rig", 30*time.Minute, now)
+	if err != nil {
+		t.Fatalf("renewClaim() error: %v", err)
+	}
+	want := now.Add(30 * time.Minute)
+	if !got.Equal(want) {
+		t.Errorf("expiresAt = %v, want %v", got, want)
+	}
+	if item, _ := store.QueryWanted("w-abc"); !item.ExpiresAt.Equal(want) {
+		t.Errorf("stored ExpiresAt = %v, want %v", item.ExpiresAt, want)
+	}
+}
+
+func TestRenewClaim_NotClaimant(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Long task"})
+	_ = store.ClaimWanted("w-abc", "other-rig")
+
+	if _, err := renewClaim(store, "w-abc", "my-rig", time.Minute, time.Now()); err == nil {
+		t.Error("renewClaim() should fail for a rig that does not hold the claim")
+	}
+}
+
+func TestRunHeartbeatLoop_StopsOnCancel(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	beats := 0
+	err := runHeartbeatLoop(ctx, time.Millisecond, func() error {
+		beats++
+		if beats == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runHeartbeatLoop() error = %v, want nil on cancel", err)
+	}
+	if beats < 3 {
+		t.Errorf("beats = %d, want >= 3", beats)
+	}
+}
+
+func TestRunHeartbeatLoop_StopsOnRenewError(t *testing.T) {
+	t.Parallel()
+	lost := errors.New("claim lost")
+	beats := 0
+	err := runHeartbeatLoop(context.Background(), time.Millisecond, func() error {
+		beats++
+		if beats == 2 {
+			return lost
+		}
+		return nil
+	})
+	if !errors.Is(err, lost) {
+		t.Errorf("runHeartbeatLoop() error = %v, want %v", err, lost)
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlHistoryCmd = &cobra.Command{
+	Use:   "history <wanted-id>",
+	Short: "Show the lifecycle of a wanted item",
+	Long: `Show a wanted item's lifecycle as a timeline, oldest first.
+
+The timeline is assembled from the commons itself, so any town can audit
+an item without reading Dolt's commit log: when it was posted and by whom,
+the current claim, every completion submitted and accepted, and every
+recorded action on the item (delegated claims, rejections, releases,
+reaps, reopens, assignments, comments, and merges).
+
+Plain claims are not logged individually, so only the current claim
+appears; earlier claimants show up through their completions and the
+release, reap, or reopen that ended their claim.
+
+Examples:
+  gt wl history w-abc123
+  gt wl history w-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlHistory,
+}
+
+func init() {
+	wlCmd.AddCommand(wlHistoryCmd)
+}
+
+// wlHistoryEvent is one entry in an item's timeline.
+type wlHistoryEvent struct {
+	At     time.Time
+	Event  string
+	Actor  string
+	Detail string
+}
+
+// wlHistoryJSON is the JSON shape of gt wl history --json.
+type wlHistoryJSON struct {
+	ID     string               `json:"id"`
+	Title  string               `json:"title"`
+	Status string               `json:"status"`
+	Events []wlHistoryEventJSON `json:"events"`
+}
+
+type wlHistoryEventJSON struct {
+	At     string `json:"at"`
+	Event  string `json:"event"`
+	Actor  string `json:"actor"`
+	Detail string `json:"detail"`
+}
+
+// wlHistoryEventNames maps wanted_history actions to timeline event names.
+// Unknown actions are shown as recorded.
+var wlHistoryEventNames = map[string]string{
+	"claim":   "claimed",
+	"assign":  "assigned",
+	"comment": "comment",
+	"reject":  "rejected",
+	"unclaim": "released",
+	"reap":    "reaped",
+	"reopen":  "reopened",
+	"amend":   "amended",
+	"merge":   "merged",
+}
+
+func runWlHistory(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+	events, err := store.QueryWantedHistory(wantedID)
+	if err != nil {
+		return err
+	}
+	timeline := buildWlHistory(detail, events)
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, buildWlHistoryJSON(detail.Item, timeline), wlJSONPrettyOutput())
+	}
+	renderWlHistory(os.Stdout, detail.Item, timeline)
+	return nil
+}
+
+// buildWlHistory merges the item row, its completions, and its
+// wanted_history events into one timeline sorted by time. Approvals come
+// from the completions, which carry the accepted completion's ID, so the
+// matching 'approve' events are dropped. The row's current claim is shown
+// unless an event already accounts for it: a delegated claim logs its own
+// event, and a rejection restarts claimed_at.
+func buildWlHistory(detail *doltserver.WantedDetail, events []doltserver.WantedEvent) []wlHistoryEvent {
+	item := detail.Item
+	timeline := []wlHistoryEvent{{At: item.CreatedAt, Event: "posted", Actor: item.PostedBy}}
+
+	claimLogged := false
+	for _, e := range events {
+		if e.Action == "approve" {
+			continue
+		}
+		if (e.Action == "claim" || e.Action == "reject") && e.At.Equal(item.ClaimedAt) {
+			claimLogged = true
+		}
+		name, ok := wlHistoryEventNames[e.Action]
+		if !ok {
+			name = e.Action
+		}
+		timeline = append(timeline, wlHistoryEvent{At: e.At, Event: name, Actor: e.Actor, Detail: e.Detail})
+	}
+	if item.ClaimedBy != "" && !claimLogged {
+		ev := wlHistoryEvent{At: item.ClaimedAt, Event: "claimed", Actor: item.ClaimedBy}
+		if item.ClaimedGroup != "" {
+			ev.Detail = "for group " + item.ClaimedGroup
+		}
+		timeline = append(timeline, ev)
+	}
+
+	for _, c := range detail.Completions {
+		submitted := c.ID
+		if c.Evidence != "" {
+			submitted += ": " + c.Evidence
+		}
+		timeline = append(timeline, wlHistoryEvent{
+			At:     doltserver.ParseDoltTimestamp(c.CompletedAt),
+			Event:  "submitted",
+			Actor:  c.CompletedBy,
+			Detail: submitted,
+		})
+		if c.ValidatedBy != "" {
+			timeline = append(timeline, wlHistoryEvent{
+				At:     doltserver.ParseDoltTimestamp(c.ValidatedAt),
+				Event:  "accepted",
+				Actor:  c.ValidatedBy,
+				Detail: c.ID,
+			})
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].At.Before(timeline[j].At) })
+	return timeline
+}
+
+func buildWlHistoryJSON(item *doltserver.WantedItem, timeline []wlHistoryEvent) *wlHistoryJSON {
+	out := &wlHistoryJSON{ID: item.ID, Title: item.Title, Status: item.Status, Events: []wlHistoryEventJSON{}}
+	for _, e := range timeline {
+		out.Events = append(out.Events, wlHistoryEventJSON{
+			At:     formatShowTime(e.At),
+			Event:  e.Event,
+			Actor:  e.Actor,
+			Detail: e.Detail,
+		})
+	}
+	return out
+}
+
+func renderWlHistory(w io.Writer, item *doltserver.WantedItem, timeline []wlHistoryEvent) {
+	fmt.Fprintf(w, "%s %s\n", style.Bold.Render(item.ID), item.Title)
+	fmt.Fprintf(w, "  Status: %s\n\n", item.Status)
+
+	tbl := style.NewTable(
+		style.Column{Name: "TIME", Width: 17},
+		style.Column{Name: "EVENT", Width: 10},
+		style.Column{Name: "BY", Width: 16},
+		style.Column{Name: "DETAIL", Width: 50},
+	)
+	for _, e := range timeline {
+		at := "-"
+		if !e.At.IsZero() {
+			at = e.At.Local().Format("2006-01-02 15:04")
+		}
+		tbl.AddRow(at, e.Event, valueOrDash(e.Actor), e.Detail)
+	}
+	fmt.Fprint(w, tbl.Render())
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestBuildWlHistory(t *testing.T) {
+	t.Parallel()
+	posted := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	detail := &doltserver.WantedDetail{
+		Item: &doltserver.WantedItem{
+			ID: "w-1", Title: "Fix login", Status: doltserver.StatusCompleted,
+			PostedBy: "poster", CreatedAt: posted,
+		},
+		Completions: []doltserver.WantedCompletion{
+			{ID: "c-1", CompletedBy: "rig-a", Evidence: "https://example.com/1", CompletedAt: "2026-03-02 10:00:00", SupersededBy: "c-2"},
+			{ID: "c-2", CompletedBy: "rig-a", Evidence: "https://example.com/2", CompletedAt: "2026-03-04 10:00:00",
+				ValidatedBy: "poster", ValidatedAt: "2026-03-05 10:00:00"},
+		},
+	}
+	events := []doltserver.WantedEvent{
+		{Action: "reject", Actor: "poster", Detail: "rejected: no tests", At: posted.Add(48 * time.Hour)},
+		{Action: "approve", Actor: "poster", At: posted.Add(96 * time.Hour)},
+	}
+
+	var got []string
+	for _, e := range buildWlHistory(detail, events) {
+		got = append(got, e.Event+" "+e.Actor)
+	}
+	want := "posted poster, submitted rig-a, rejected poster, submitted rig-a, accepted poster"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("timeline = %v, want %s", got, want)
+	}
+}
+
+func TestBuildWlHistory_CurrentClaim(t *testing.T) {
+	t.Parallel()
+	claimed := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	item := &doltserver.WantedItem{ID: "w-1", Status: doltserver.StatusClaimed, PostedBy: "poster", ClaimedBy: "rig-a", ClaimedAt: claimed}
+
+	timeline := buildWlHistory(&doltserver.WantedDetail{Item: item}, nil)
+	if len(timeline) != 2 || timeline[1].Event != "claimed" || timeline[1].Actor != "rig-a" {
+		t.Errorf("timeline = %+v, want posted then claimed by rig-a", timeline)
+	}
+
+	// A delegated claim logs its own event; the row's claim is not repeated.
+	delegated := []doltserver.WantedEvent{{Action: "claim", Actor: "coord", Detail: "on behalf of rig-a", At: claimed}}
+	timeline = buildWlHistory(&doltserver.WantedDetail{Item: item}, delegated)
+	if len(timeline) != 2 || timeline[1].Actor != "coord" {
+		t.Errorf("timeline = %+v, want only the delegated claim event", timeline)
+	}
+}
+
+func TestRenderWlHistory(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix login", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-1", "rig-a")
+	_ = store.SubmitCompletion("c-1", "w-1", "rig-a", "https://example.com/pr/1")
+	_ = store.RejectCompletion("w-1", "poster", "no tests")
+
+	detail, _ := store.QueryWantedDetail("w-1")
+	events, _ := store.QueryWantedHistory("w-1")
+	var buf bytes.Buffer
+	renderWlHistory(&buf, detail.Item, buildWlHistory(detail, events))
+	out := buf.String()
+	for _, want := range []string{"Fix login", "posted", "submitted", "c-1: https://example.com/pr/1", "rejected: no tests"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("history output missing %q:\n%s", want, out)
+		}
+	}
+}
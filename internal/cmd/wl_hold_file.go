@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// wlHoldFile is the marker gt wl claim --hold-file writes once a claim has
+// committed, so later pipeline stages can check the claim without querying
+// the board.
+type wlHoldFile struct {
+	ID        string    `json:"id"`
+	Town      string    `json:"town"`
+	ClaimedAt time.Time `json:"claimed_at"`
+}
+
+// writeWlHoldFile writes the hold file for wantedID at path.
+func writeWlHoldFile(path, wantedID, town string, claimedAt time.Time) error {
+	data, err := json.MarshalIndent(wlHoldFile{ID: wantedID, Town: town, ClaimedAt: claimedAt.UTC()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling hold file: %w", err)
+	}
+	if err := writeWlFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("writing hold file: %w", err)
+	}
+	return nil
+}
+
+// writeWlOutputIDFile writes id and a newline to path, replacing any
+// previous contents, for --output-id-file.
+func writeWlOutputIDFile(path, id string) error {
+	if err := writeWlFileAtomic(path, []byte(id+"\n")); err != nil {
+		return fmt.Errorf("writing id file: %w", err)
+	}
+	return nil
+}
+
+// writeWlFileAtomic writes data to a temporary file beside path and renames
+// it into place, so readers never see a partial file and a failed write
+// leaves the previous file untouched.
+func writeWlFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// releaseWlHoldFile removes the hold file at path when it marks wantedID.
+// A missing file is not an error; a file for another item is left in place
+// and reported, since it belongs to a different pipeline stage.
+func releaseWlHoldFile(path, wantedID string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading hold file: %w", err)
+	}
+	var hold wlHoldFile
+	if err := json.Unmarshal(data, &hold); err != nil {
+		return fmt.Errorf("hold file %s is not a claim marker: %w", path, err)
+	}
+	if hold.ID != wantedID {
+		return fmt.Errorf("hold file %s marks %s, not %s; leaving it in place", path, hold.ID, wantedID)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing hold file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWlHoldFile_WriteAndRelease(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "claim.json")
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := writeWlHoldFile(path, "w-abc", "my-rig", at); err != nil {
+		t.Fatalf("writeWlHoldFile() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading hold file: %v", err)
+	}
+	var hold wlHoldFile
+	if err := json.Unmarshal(data, &hold); err != nil {
+		t.Fatalf("hold file is not JSON: %v\n%s", err, data)
+	}
+	if hold != (wlHoldFile{ID: "w-abc", Town: "my-rig", ClaimedAt: at}) {
+		t.Errorf("hold file = %+v", hold)
+	}
+
+	if err := releaseWlHoldFile(path, "w-other"); err == nil || !strings.Contains(err.Error(), "marks w-abc") {
+		t.Errorf("releaseWlHoldFile(other id) error = %v, want mismatch", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("hold file for another item should be kept: %v", err)
+	}
+
+	if err := releaseWlHoldFile(path, "w-abc"); err != nil {
+		t.Fatalf("releaseWlHoldFile() error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("hold file still present after release: %v", err)
+	}
+	if err := releaseWlHoldFile(path, "w-abc"); err != nil {
+		t.Errorf("releaseWlHoldFile() on a missing file error: %v", err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 0 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestWriteWlOutputIDFile_ReplacesContents(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "id")
+	if err := os.WriteFile(path, []byte("c-0123456789abcdef-previous\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeWlOutputIDFile(path, "w-abc"); err != nil {
+		t.Fatalf("writeWlOutputIDFile() error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "w-abc\n" {
+		t.Errorf("id file = %q, want %q", data, "w-abc\n")
+	}
+}
+
+func TestWriteDoneIDFile_FailureKeepsPriorFile(t *testing.T) {
+	old := wlDoneIDFile
+	t.Cleanup(func() { wlDoneIDFile = old })
+	wlDoneIDFile = filepath.Join(t.TempDir(), "id")
+	if err := os.WriteFile(wlDoneIDFile, []byte("c-prior\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeDoneIDFile("w-abc", ""); err == nil {
+		t.Fatal("writeDoneIDFile() without an ID should fail")
+	}
+	if data, _ := os.ReadFile(wlDoneIDFile); string(data) != "c-prior\n" {
+		t.Errorf("id file = %q, want the prior contents kept", data)
+	}
+
+	if err := writeDoneIDFile("w-abc", "c-new"); err != nil {
+		t.Fatalf("writeDoneIDFile() error: %v", err)
+	}
+	if data, _ := os.ReadFile(wlDoneIDFile); string(data) != "c-new\n" {
+		t.Errorf("id file = %q, want %q", data, "c-new\n")
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Wasteland settings naming the stems of generated IDs. The stem is
+// followed by '-' and a hash, so ids.wanted_prefix=task gives task-<hash>.
+const (
+	wlSettingWantedIDPrefix     = "ids.wanted_prefix"
+	wlSettingCompletionIDPrefix = "ids.completion_prefix"
+	defaultWantedIDPrefix       = "w"
+	defaultCompletionIDPrefix   = "c"
+)
+
+// wlIDPrefixPattern is the short alphanumeric form an ID stem must take.
+var wlIDPrefixPattern = regexp.MustCompile(`^[a-z0-9]{1,12}$`)
+
+// wantedIDPrefix returns the configured wanted ID stem, or "w".
+func wantedIDPrefix(settings map[string]string) (string, error) {
+	return wlIDPrefix(settings, wlSettingWantedIDPrefix, defaultWantedIDPrefix)
+}
+
+// completionIDPrefix returns the configured completion ID stem, or "c".
+func completionIDPrefix(settings map[string]string) (string, error) {
+	return wlIDPrefix(settings, wlSettingCompletionIDPrefix, defaultCompletionIDPrefix)
+}
+
+// wlIDPrefix reads the ID stem in setting key. A trailing '-' is accepted
+// and dropped, so "task" and "task-" mean the same.
+func wlIDPrefix(settings map[string]string, key, def string) (string, error) {
+	raw := strings.TrimSpace(settings[key])
+	if raw == "" {
+		return def, nil
+	}
+	prefix := strings.TrimSuffix(raw, "-")
+	if !wlIDPrefixPattern.MatchString(prefix) {
+		return "", fmt.Errorf("invalid %s setting %q: must be 1-12 lowercase letters or digits", key, raw)
+	}
+	return prefix, nil
+}
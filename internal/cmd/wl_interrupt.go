@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// errWlInterrupted is what an interrupted gt wl command returns, and the
+// cause of its cancelled context.
+var errWlInterrupted = errors.New("cancelled")
+
+// wlInterruptExitCode is the exit status of an interrupted gt wl command,
+// as a shell reports a process killed by SIGINT.
+const wlInterruptExitCode = 130
+
+// wlInterruptGrace is how long an interrupted command has to return once
+// its dolt calls are cancelled. Work that ignores the context, such as a
+// confirmation prompt, would otherwise keep gt waiting, so after the grace
+// period it exits anyway.
+const wlInterruptGrace = 3 * time.Second
+
+// selectWLInterrupt makes SIGINT and SIGTERM cancel cmd's context, which
+// is also the parent of every dolt SQL call (and of each call's --timeout
+// limit), so a hung dolt process is killed rather than orphaned. A second
+// signal gets the default handling and kills gt at once.
+func selectWLInterrupt(cmd *cobra.Command) {
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		signal.Stop(sigs)
+		cancel(errWlInterrupted)
+		time.AfterFunc(wlInterruptGrace, func() {
+			fmt.Fprintln(os.Stderr, errWlInterrupted)
+			os.Exit(wlInterruptExitCode)
+		})
+	}()
+
+	cmd.SetContext(ctx)
+	doltserver.SetSQLContext(ctx)
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			return wlInterruptedError(c, run(c, args))
+		}
+	}
+}
+
+// wlInterruptedError replaces err with errWlInterrupted when c was
+// interrupted, and stops cobra printing it with the usage text: Execute
+// reports the cancellation on its own.
+func wlInterruptedError(c *cobra.Command, err error) error {
+	if err == nil || c.Context() == nil || !errors.Is(context.Cause(c.Context()), errWlInterrupted) {
+		return err
+	}
+	c.SilenceErrors = true
+	c.SilenceUsage = true
+	return errWlInterrupted
+}
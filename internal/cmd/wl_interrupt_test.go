@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWlInterruptedError(t *testing.T) {
+	t.Parallel()
+	failed := errors.New("dolt query cancelled")
+
+	live := &cobra.Command{}
+	live.SetContext(context.Background())
+	if err := wlInterruptedError(live, failed); err != failed {
+		t.Errorf("uninterrupted command: %v, want the original error", err)
+	}
+	if live.SilenceErrors || live.SilenceUsage {
+		t.Error("uninterrupted command should still print its error and usage")
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errWlInterrupted)
+	interrupted := &cobra.Command{}
+	interrupted.SetContext(ctx)
+	if err := wlInterruptedError(interrupted, nil); err != nil {
+		t.Errorf("interrupted command that succeeded: %v, want nil", err)
+	}
+	if err := wlInterruptedError(interrupted, failed); !errors.Is(err, errWlInterrupted) {
+		t.Errorf("interrupted command: %v, want errWlInterrupted", err)
+	}
+	if !interrupted.SilenceErrors || !interrupted.SilenceUsage {
+		t.Error("interrupted command should not print its error or usage")
+	}
+}
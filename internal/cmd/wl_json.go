@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// JSON output flags shared by every gt wl subcommand: --json selects
+// machine-readable output, --compact/--pretty its layout.
+var (
+	wlJSON        bool
+	wlJSONCompact bool
+	wlJSONPretty  bool
+)
+
+// wlRawJSONCommands emit JSON with --json but have no fixed result type for
+// gt wl schema: browse passes dolt's rows through and log writes one object
+// per entry. Every command in wlJSONResultTypes also supports --json.
+var wlRawJSONCommands = map[string]bool{"browse": true, "log": true}
+
+// isStdoutTerminal reports whether stdout is a terminal. Tests override it.
+var isStdoutTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+func init() {
+	wlCmd.PersistentFlags().BoolVar(&wlJSON, "json", false, "Output the result as JSON, and errors as {\"error\": ...} on stderr")
+	wlCmd.PersistentFlags().BoolVar(&wlJSONCompact, "compact", false, "Emit --json output as single-line JSON (default when stdout is not a terminal)")
+	wlCmd.PersistentFlags().BoolVar(&wlJSONPretty, "pretty", false, "Emit --json output as indented JSON (default when stdout is a terminal)")
+	wlCmd.MarkFlagsMutuallyExclusive("compact", "pretty")
+
+	wlCmd.PersistentPreRunE = wlPersistentPreRun
+	cobra.OnInitialize(silenceWLErrorsForJSON)
+}
+
+// wlPersistentPreRun runs the root pre-run and selects the wl-commons
+// database, then refuses --json on a command that has no JSON output
+// rather than silently ignoring it.
+func wlPersistentPreRun(cmd *cobra.Command, args []string) error {
+	if err := persistentPreRun(cmd, args); err != nil {
+		return err
+	}
+	wlRun = newWlRunContext()
+	selectWLVerbose()
+	if err := selectWLCommonsDB(); err != nil {
+		return err
+	}
+	if err := selectWLDryRun(cmd); err != nil {
+		return err
+	}
+	if err := selectWLSQLTimeout(); err != nil {
+		return err
+	}
+	if err := selectWLSQLRetries(cmd); err != nil {
+		return err
+	}
+	selectWLInterrupt(cmd)
+	selectWLWriteQueue(cmd, args)
+	if wlJSON && !supportsWLJSON(cmd) {
+		return fmt.Errorf("%s does not support --json", buildCommandPath(cmd))
+	}
+	return nil
+}
+
+// supportsWLJSON reports whether cmd is a gt wl subcommand with --json
+// output.
+func supportsWLJSON(cmd *cobra.Command) bool {
+	if cmd.Parent() != wlCmd {
+		return false
+	}
+	_, typed := wlJSONResultTypes[cmd.Name()]
+	return typed || wlRawJSONCommands[cmd.Name()]
+}
+
+// silenceWLErrorsForJSON stops cobra printing errors and usage for gt wl
+// commands under --json, so Execute can report the error as JSON instead.
+// Cobra runs initializers after parsing flags and before validating
+// arguments, so argument errors are covered too.
+func silenceWLErrorsForJSON() {
+	if !wlJSON {
+		return
+	}
+	var silence func(c *cobra.Command)
+	silence = func(c *cobra.Command) {
+		c.SilenceErrors = true
+		c.SilenceUsage = true
+		for _, sub := range c.Commands() {
+			silence(sub)
+		}
+	}
+	silence(wlCmd)
+}
+
+// isWLJSONError reports whether err from cmd should be written with
+// writeWLJSONError: cmd is under gt wl and was run with --json.
+func isWLJSONError(cmd *cobra.Command) bool {
+	if !wlJSON {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == wlCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// writeWLJSONError writes err as a single-line {"error": "..."} object.
+func writeWLJSONError(w io.Writer, err error) {
+	_ = writeWLJSON(w, struct {
+		Error string `json:"error"`
+	}{err.Error()}, false)
+}
+
+// wlItemResultJSON is the --json result of a command that moves one wanted
+// item to a new status.
+type wlItemResultJSON struct {
+	ID        string `json:"id"`
+	Title     string `json:"title,omitempty"`
+	Status    string `json:"status"`
+	ClaimedBy string `json:"claimed_by,omitempty"`
+	Assignee  string `json:"assignee,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// wlJSONPrettyOutput resolves --compact/--pretty: an explicit flag wins,
+// otherwise JSON is pretty-printed only for a terminal.
+func wlJSONPrettyOutput() bool {
+	switch {
+	case wlJSONPretty:
+		return true
+	case wlJSONCompact:
+		return false
+	default:
+		return isStdoutTerminal()
+	}
+}
+
+// writeWLJSON encodes v as one JSON document followed by a newline.
+func writeWLJSON(w io.Writer, v any, pretty bool) error {
+	enc := json.NewEncoder(w)
+	if pretty {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
+
+// writeWLRawJSON re-renders an already-encoded JSON document (e.g. dolt's
+// -r json output) in the requested layout.
+func writeWLRawJSON(w io.Writer, raw []byte, pretty bool) error {
+	var buf bytes.Buffer
+	var err error
+	if pretty {
+		err = json.Indent(&buf, bytes.TrimSpace(raw), "", "  ")
+	} else {
+		err = json.Compact(&buf, raw)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing JSON output: %w", err)
+	}
+	buf.WriteByte('\n')
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestWriteWLJSON_CompactAndPretty(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	res, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{})
+	if err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	data := newClaimTemplateData(res, "my-rig")
+
+	var compact strings.Builder
+	if err := writeWLJSON(&compact, data, false); err != nil {
+		t.Fatalf("writeWLJSON(compact) error: %v", err)
+	}
+	wantCompact := `{"id":"w-abc","title":"Fix auth bug","claimed_by":"my-rig","claimed_via":"","status":"claimed","blockers":[]}` + "\n"
+	if compact.String() != wantCompact {
+		t.Errorf("compact = %q, want %q", compact.String(), wantCompact)
+	}
+
+	var pretty strings.Builder
+	if err := writeWLJSON(&pretty, data, true); err != nil {
+		t.Fatalf("writeWLJSON(pretty) error: %v", err)
+	}
+	wantPretty := `{
+  "id": "w-abc",
+  "title": "Fix auth bug",
+  "claimed_by": "my-rig",
+  "claimed_via": "",
+  "status": "claimed",
+  "blockers": []
+}
+`
+	if pretty.String() != wantPretty {
+		t.Errorf("pretty = %q, want %q", pretty.String(), wantPretty)
+	}
+}
+
+func TestWriteWLRawJSON(t *testing.T) {
+	t.Parallel()
+	raw := []byte("{\"rows\": [{\"id\": \"w-abc\"}]}\n")
+
+	var compact strings.Builder
+	if err := writeWLRawJSON(&compact, raw, false); err != nil {
+		t.Fatalf("writeWLRawJSON(compact) error: %v", err)
+	}
+	if want := `{"rows":[{"id":"w-abc"}]}` + "\n"; compact.String() != want {
+		t.Errorf("compact = %q, want %q", compact.String(), want)
+	}
+
+	var pretty strings.Builder
+	if err := writeWLRawJSON(&pretty, raw, true); err != nil {
+		t.Fatalf("writeWLRawJSON(pretty) error: %v", err)
+	}
+	if want := "{\n  \"rows\": [\n    {\n      \"id\": \"w-abc\"\n    }\n  ]\n}\n"; pretty.String() != want {
+		t.Errorf("pretty = %q, want %q", pretty.String(), want)
+	}
+
+	if err := writeWLRawJSON(&pretty, []byte("not json"), true); err == nil {
+		t.Error("writeWLRawJSON() should reject invalid JSON")
+	}
+}
+
+func TestWLJSONPrettyOutput_Defaults(t *testing.T) {
+	oldTTY, oldCompact, oldPretty := isStdoutTerminal, wlJSONCompact, wlJSONPretty
+	t.Cleanup(func() { isStdoutTerminal, wlJSONCompact, wlJSONPretty = oldTTY, oldCompact, oldPretty })
+
+	tests := []struct {
+		tty, compact, pretty bool
+		want                 bool
+	}{
+		{tty: true, want: true},
+		{tty: false, want: false},
+		{tty: true, compact: true, want: false},
+		{tty: false, pretty: true, want: true},
+	}
+	for _, tt := range tests {
+		tty := tt.tty
+		isStdoutTerminal = func() bool { return tty }
+		wlJSONCompact, wlJSONPretty = tt.compact, tt.pretty
+		if got := wlJSONPrettyOutput(); got != tt.want {
+			t.Errorf("wlJSONPrettyOutput(tty=%v, compact=%v, pretty=%v) = %v, want %v", tt.tty, tt.compact, tt.pretty, got, tt.want)
+		}
+	}
+}
+
+func TestSupportsWLJSON(t *testing.T) {
+	t.Parallel()
+	for _, c := range []*cobra.Command{wlClaimCmd, wlDoneCmd, wlListCmd, wlShowCmd, wlLogCmd, wlBrowseCmd} {
+		if !supportsWLJSON(c) {
+			t.Errorf("gt wl %s should support --json", c.Name())
+		}
+	}
+	for _, c := range []*cobra.Command{wlJoinCmd, wlSchemaCmd, wlCmd} {
+		if supportsWLJSON(c) {
+			t.Errorf("gt wl %s should not support --json", c.Name())
+		}
+	}
+}
+
+func TestWLJSONErrors(t *testing.T) {
+	old := wlJSON
+	t.Cleanup(func() { wlJSON = old })
+
+	wlJSON = false
+	if isWLJSONError(wlDoneCmd) {
+		t.Error("errors without --json should be left to cobra")
+	}
+	wlJSON = true
+	if !isWLJSONError(wlDoneCmd) || isWLJSONError(rootCmd) {
+		t.Error("only gt wl commands should report --json errors as JSON")
+	}
+
+	var buf strings.Builder
+	writeWLJSONError(&buf, errors.New(`item "w-1" not found`))
+	if got, want := buf.String(), `{"error":"item \"w-1\" not found"}`+"\n"; got != want {
+		t.Errorf("error JSON = %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
@@ -30,7 +31,7 @@ func TestLifecycle_PostClaimDone(t *testing.T) {
 	}
 
 	// Claim
-	_, err := claimWanted(store, "w-life1", "claimer-rig")
+	_, err := claimWanted(context.Background(), store, "w-life1", "claimer-rig", claimOptions{})
 	if err != nil {
 		t.Fatalf("claimWanted() error: %v", err)
 	}
@@ -60,13 +61,13 @@ func TestLifecycle_DoubleClaim(t *testing.T) {
 	})
 
 	// First claim succeeds
-	_, err := claimWanted(store, "w-double", "rig-1")
+	_, err := claimWanted(context.Background(), store, "w-double", "rig-1", claimOptions{})
 	if err != nil {
 		t.Fatalf("first claimWanted() error: %v", err)
 	}
 
 	// Second claim fails (status is now "claimed", not "open")
-	_, err = claimWanted(store, "w-double", "rig-2")
+	_, err = claimWanted(context.Background(), store, "w-double", "rig-2", claimOptions{})
 	if err == nil {
 		t.Fatal("second claimWanted() should fail for already-claimed item")
 	}
@@ -100,7 +101,7 @@ func TestLifecycle_ClaimCompletedItem(t *testing.T) {
 	_ = store.SubmitCompletion("c-1", "w-completed", "rig-1", "evidence")
 
 	// Trying to claim an in_review item should fail
-	_, err := claimWanted(store, "w-completed", "rig-2")
+	_, err := claimWanted(context.Background(), store, "w-completed", "rig-2", claimOptions{})
 	if err == nil {
 		t.Fatal("claimWanted() should fail on in_review item")
 	}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlListStatus   string
+	wlListLimit    int
+	wlListPriority string
+	wlListTags     []string
+	wlListTagAny   bool
+	wlListTagAll   bool
+	wlListColumns  []string
+	wlListWide     bool
+)
+
+var wlListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List wanted items on the local board",
+	Long: `List wanted items in the local wl-commons database, most urgent first,
+with their status and claimant.
+
+Unlike gt wl browse, which clones the public commons, list reads the
+board this town has joined, as of its last sync.
+
+--status keeps only items in one status (open, claimed, in_review, or any
+other status in the wasteland's workflow). --limit caps the rows shown.
+
+--priority keeps only items of one priority, given as 0-4, P0-P4, or by
+name: critical, high, medium, low, backlog. --tag keeps items carrying a
+tag; repeat it (or comma-separate) for several. By default an item matches
+if it has any of the tags (--tag-any); --tag-all requires every one.
+
+--columns picks the fields shown, in order: id, title, priority, status,
+claimed_by, claimed_group, assignee, posted_by, tags, created_at, and
+updated_at. The default is id,title,priority,status,claimed_by,tags;
+--wide shows every field. When stdout is not a terminal, rows are written
+tab-separated with no title or header line, for cut and awk. --json
+always has every field.
+
+Examples:
+  gt wl list
+  gt wl list --status open
+  gt wl list --status in_review --limit 10
+  gt wl list --priority high --tag rust
+  gt wl list --tag go --tag sql --tag-all
+  gt wl list --wide
+  gt wl list --status open --columns id | xargs -n1 gt wl show`,
+	Args: cobra.NoArgs,
+	RunE: runWlList,
+}
+
+func init() {
+	wlListCmd.Flags().StringVar(&wlListStatus, "status", "", "Only list items with this status (e.g. open, claimed, in_review)")
+	wlListCmd.Flags().IntVar(&wlListLimit, "limit", 50, "Maximum items to list")
+	wlListCmd.Flags().StringVar(&wlListPriority, "priority", "", "Only list items of this priority (0-4, or critical, high, medium, low, backlog)")
+	wlListCmd.Flags().StringSliceVar(&wlListTags, "tag", nil, "Only list items with this tag (repeatable or comma-separated)")
+	wlListCmd.Flags().BoolVar(&wlListTagAny, "tag-any", false, "Match items carrying any --tag (the default)")
+	wlListCmd.Flags().BoolVar(&wlListTagAll, "tag-all", false, "Match only items carrying every --tag")
+	wlListCmd.Flags().StringSliceVar(&wlListColumns, "columns", nil, "Fields to show, in order (e.g. id,title,status)")
+	wlListCmd.Flags().BoolVar(&wlListWide, "wide", false, "Show every field")
+	wlListCmd.MarkFlagsMutuallyExclusive("columns", "wide")
+
+	wlCmd.AddCommand(wlListCmd)
+}
+
+func runWlList(cmd *cobra.Command, args []string) error {
+	if wlListLimit < 1 {
+		return fmt.Errorf("--limit must be >= 1, got %d", wlListLimit)
+	}
+	filter := doltserver.WantedFilter{
+		Status:      wlListStatus,
+		MinPriority: -1,
+		MaxPriority: -1,
+		Limit:       wlListLimit,
+		Tags:        wlListTags,
+	}
+	if wlListPriority != "" {
+		pri, err := parseWantedPriority(wlListPriority)
+		if err != nil {
+			return err
+		}
+		filter.MinPriority, filter.MaxPriority = pri, pri
+	}
+	matchAll, err := tagMatchAll(wlListTags, wlListTagAny, wlListTagAll)
+	if err != nil {
+		return err
+	}
+	filter.TagsMatchAll = matchAll
+	columns, err := selectWantedListColumns(wlListColumns, wlListWide)
+	if err != nil {
+		return err
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	items, err := listWanted(store, filter)
+	if err != nil {
+		return err
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, buildWantedListJSON(items), wlJSONPrettyOutput())
+	}
+	if !isStdoutTerminal() {
+		return writeWantedListTSV(os.Stdout, items, columns)
+	}
+	renderWantedListColumns(os.Stdout, items, wlListStatus, columns)
+	return nil
+}
+
+// wlListJSON is the gt wl list --json result.
+type wlListJSON struct {
+	Count int              `json:"count"`
+	Items []wlListItemJSON `json:"items"`
+}
+
+// wlListItemJSON is one row of wlListJSON.
+type wlListItemJSON struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Priority  int    `json:"priority"`
+	Status    string `json:"status"`
+	ClaimedBy string `json:"claimed_by,omitempty"`
+
+	// ClaimedGroup is the group the claim is held for, if any.
+	ClaimedGroup string `json:"claimed_group,omitempty"`
+
+	// Assignee is the worker the claiming town has put on the item.
+	Assignee string `json:"assignee,omitempty"`
+
+	Tags []string `json:"tags,omitempty"`
+}
+
+func buildWantedListJSON(items []*doltserver.WantedItem) wlListJSON {
+	out := wlListJSON{Count: len(items), Items: []wlListItemJSON{}}
+	for _, item := range items {
+		out.Items = append(out.Items, wlListItemJSON{
+			ID:           item.ID,
+			Title:        item.Title,
+			Priority:     item.Priority,
+			Status:       item.Status,
+			ClaimedBy:    item.ClaimedBy,
+			ClaimedGroup: item.ClaimedGroup,
+			Assignee:     item.Assignee,
+			Tags:         item.Tags,
+		})
+	}
+	return out
+}
+
+// listWanted returns the items matching filter. A status filter must be
+// one the wasteland's workflow knows.
+func listWanted(store doltserver.WLCommonsStore, filter doltserver.WantedFilter) ([]*doltserver.WantedItem, error) {
+	if filter.Status != "" {
+		if err := requireKnownStatus(store, filter.Status); err != nil {
+			return nil, err
+		}
+	}
+	items, err := store.ListWanted(filter)
+	if err != nil {
+		return nil, fmt.Errorf("listing wanted items: %w", err)
+	}
+	return items, nil
+}
+
+// requireKnownStatus errors unless status is one the wasteland's workflow
+// knows, for --status filters.
+func requireKnownStatus(store doltserver.WLCommonsStore, status string) error {
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return fmt.Errorf("loading wasteland settings: %w", err)
+	}
+	model, err := doltserver.StatusModelFromSettings(settings)
+	if err != nil {
+		return err
+	}
+	if !model.Known(status) {
+		return fmt.Errorf("invalid --status %q: must be one of %s", status, strings.Join(model.Statuses(), ", "))
+	}
+	return nil
+}
+
+// wantedPriorityNames maps priority names to the 0-4 scale gt wl post uses.
+var wantedPriorityNames = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"backlog":  4,
+}
+
+// parseWantedPriority parses a priority given as 0-4, P0-P4, or a name
+// from wantedPriorityNames.
+func parseWantedPriority(s string) (int, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if pri, ok := wantedPriorityNames[v]; ok {
+		return pri, nil
+	}
+	if pri, err := strconv.Atoi(strings.TrimPrefix(v, "p")); err == nil && pri >= 0 && pri <= 4 {
+		return pri, nil
+	}
+	return 0, fmt.Errorf("invalid priority %q: use 0-4 or critical, high, medium, low, backlog", s)
+}
+
+// wantedListColumn is a field gt wl list can show.
+type wantedListColumn struct {
+	Name   string
+	Header string
+	Width  int
+	Align  style.Alignment
+	// Value is the field as shown in the table. Empty values show as "-".
+	Value func(*doltserver.WantedItem) string
+	// Plain is the field for tab-separated output, when it differs.
+	Plain func(*doltserver.WantedItem) string
+}
+
+// wantedListColumns are the fields gt wl list can show, in --wide order.
+var wantedListColumns = []wantedListColumn{
+	{Name: "id", Header: "ID", Width: 14, Value: func(i *doltserver.WantedItem) string { return i.ID }},
+	{Name: "title", Header: "TITLE", Width: 44, Value: func(i *doltserver.WantedItem) string { return i.Title }},
+	{Name: "priority", Header: "PRI", Width: 4, Align: style.AlignRight,
+		Value: func(i *doltserver.WantedItem) string { return wlFormatPriority(fmt.Sprint(i.Priority)) },
+		Plain: func(i *doltserver.WantedItem) string { return fmt.Sprint(i.Priority) }},
+	{Name: "status", Header: "STATUS", Width: 10, Value: func(i *doltserver.WantedItem) string { return i.Status }},
+	{Name: "claimed_by", Header: "CLAIMED BY", Width: 18, Value: func(i *doltserver.WantedItem) string { return i.ClaimedBy }},
+	{Name: "claimed_group", Header: "GROUP", Width: 14, Value: func(i *doltserver.WantedItem) string { return i.ClaimedGroup }},
+	{Name: "assignee", Header: "ASSIGNEE", Width: 14, Value: func(i *doltserver.WantedItem) string { return i.Assignee }},
+	{Name: "posted_by", Header: "POSTED BY", Width: 18, Value: func(i *doltserver.WantedItem) string { return i.PostedBy }},
+	{Name: "tags", Header: "TAGS", Width: 24, Value: func(i *doltserver.WantedItem) string { return strings.Join(i.Tags, ",") }},
+	{Name: "created_at", Header: "CREATED", Width: 16,
+		Value: func(i *doltserver.WantedItem) string { return formatListTime(i.CreatedAt, "2006-01-02 15:04") },
+		Plain: func(i *doltserver.WantedItem) string { return formatListTime(i.CreatedAt, time.RFC3339) }},
+	{Name: "updated_at", Header: "UPDATED", Width: 16,
+		Value: func(i *doltserver.WantedItem) string { return formatListTime(i.UpdatedAt, "2006-01-02 15:04") },
+		Plain: func(i *doltserver.WantedItem) string { return formatListTime(i.UpdatedAt, time.RFC3339) }},
+}
+
+// defaultWantedListColumns are the fields gt wl list shows without
+// --columns or --wide.
+var defaultWantedListColumns = []string{"id", "title", "priority", "status", "claimed_by", "tags"}
+
+func wantedListColumnNames() []string {
+	names := make([]string, len(wantedListColumns))
+	for i, c := range wantedListColumns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// selectWantedListColumns resolves --columns and --wide to the fields to
+// show. Names are case-insensitive; an unknown one is an error.
+func selectWantedListColumns(names []string, wide bool) ([]wantedListColumn, error) {
+	if wide {
+		return wantedListColumns, nil
+	}
+	if len(names) == 0 {
+		names = defaultWantedListColumns
+	}
+	columns := make([]wantedListColumn, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		i := slices.IndexFunc(wantedListColumns, func(c wantedListColumn) bool { return c.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q: must be one of %s", name, strings.Join(wantedListColumnNames(), ", "))
+		}
+		columns = append(columns, wantedListColumns[i])
+	}
+	return columns, nil
+}
+
+// formatListTime formats t with layout in local time, or "" when unset.
+func formatListTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	if layout == time.RFC3339 {
+		return t.UTC().Format(layout)
+	}
+	return t.Local().Format(layout)
+}
+
+// tsvFieldReplacer keeps a field on one line and in one column.
+var tsvFieldReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// writeWantedListTSV writes one tab-separated line per item with columns'
+// fields, and nothing else, for piping into cut or awk.
+func writeWantedListTSV(w io.Writer, items []*doltserver.WantedItem, columns []wantedListColumn) error {
+	fields := make([]string, len(columns))
+	for _, item := range items {
+		for i, c := range columns {
+			value := c.Value
+			if c.Plain != nil {
+				value = c.Plain
+			}
+			fields[i] = tsvFieldReplacer.Replace(value(item))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderWantedList(w io.Writer, items []*doltserver.WantedItem, status string) {
+	columns, _ := selectWantedListColumns(nil, false)
+	renderWantedListColumns(w, items, status, columns)
+}
+
+// renderWantedListColumns prints items as a table of columns.
+func renderWantedListColumns(w io.Writer, items []*doltserver.WantedItem, status string, columns []wantedListColumn) {
+	if len(items) == 0 {
+		if status != "" {
+			fmt.Fprintf(w, "No wanted items match --status %s.\n", status)
+		} else {
+			fmt.Fprintln(w, "No wanted items match.")
+		}
+		return
+	}
+
+	header := make([]style.Column, len(columns))
+	for i, c := range columns {
+		header[i] = style.Column{Name: c.Header, Width: c.Width, Align: c.Align}
+	}
+	tbl := style.NewTable(header...)
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = valueOrDash(c.Value(item))
+		}
+		tbl.AddRow(row...)
+	}
+	fmt.Fprintf(w, "Wanted items (%d):\n\n", len(items))
+	fmt.Fprint(w, tbl.Render())
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func listFilter(status string, limit int) doltserver.WantedFilter {
+	return doltserver.WantedFilter{Status: status, MinPriority: -1, MaxPriority: -1, Limit: limit}
+}
+
+func TestListWanted_StatusAndLimit(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, id := range []string{"w-1", "w-2", "w-3"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: "Item " + id, Priority: 2})
+	}
+	_ = store.ClaimWanted("w-2", "my-rig")
+
+	items, err := listWanted(store, listFilter(doltserver.StatusClaimed, 50))
+	if err != nil {
+		t.Fatalf("listWanted() error: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "w-2" {
+		t.Errorf("claimed items = %v, want [w-2]", items)
+	}
+
+	if items, _ := listWanted(store, listFilter("", 2)); len(items) != 2 {
+		t.Errorf("--limit 2 listed %d items", len(items))
+	}
+
+	if _, err := listWanted(store, listFilter("bogus", 50)); err == nil || !strings.Contains(err.Error(), "in_review") {
+		t.Errorf("listWanted(bogus) error = %v, want the known statuses", err)
+	}
+
+	var buf bytes.Buffer
+	renderWantedList(&buf, items, doltserver.StatusClaimed)
+	if out := buf.String(); !strings.Contains(out, "w-2") || !strings.Contains(out, "my-rig") {
+		t.Errorf("table =\n%s", out)
+	}
+}
+
+func TestRenderWantedList_Empty(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	renderWantedList(&buf, nil, doltserver.StatusInReview)
+	if got := buf.String(); got != "No wanted items match --status in_review.\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestBuildWantedListJSON(t *testing.T) {
+	t.Parallel()
+	out := buildWantedListJSON(nil)
+	if out.Count != 0 || out.Items == nil {
+		t.Errorf("empty list = %+v, want count 0 and an empty items array", out)
+	}
+
+	out = buildWantedListJSON([]*doltserver.WantedItem{{ID: "w-1", Title: "One", Priority: 1, Status: "claimed", ClaimedBy: "my-rig"}})
+	if out.Count != 1 || !reflect.DeepEqual(out.Items[0], wlListItemJSON{ID: "w-1", Title: "One", Priority: 1, Status: "claimed", ClaimedBy: "my-rig"}) {
+		t.Errorf("list JSON = %+v", out)
+	}
+}
+
+func TestListWanted_PriorityAndTags(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Rust parser", Priority: 1, Tags: []string{"rust", "parser"}})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-2", Title: "Go docs", Priority: 1, Tags: []string{"go", "docs"}})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-3", Title: "Rust docs", Priority: 3, Tags: []string{"rust", "docs"}})
+
+	filter := listFilter("", 50)
+	filter.MinPriority, filter.MaxPriority = 1, 1
+	filter.Tags = []string{"rust"}
+	items, err := listWanted(store, filter)
+	if err != nil {
+		t.Fatalf("listWanted() error: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "w-1" {
+		t.Errorf("high-priority rust items = %v, want [w-1]", items)
+	}
+
+	filter = listFilter("", 50)
+	filter.Tags, filter.TagsMatchAll = []string{"rust", "docs"}, true
+	if items, _ := listWanted(store, filter); len(items) != 1 || items[0].ID != "w-3" {
+		t.Errorf("items tagged rust and docs = %v, want [w-3]", items)
+	}
+
+	var buf bytes.Buffer
+	renderWantedList(&buf, items, "")
+	if !strings.Contains(buf.String(), "rust,parser") {
+		t.Errorf("table missing tags:\n%s", buf.String())
+	}
+}
+
+func TestParseWantedPriority(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]int{"0": 0, "4": 4, "P1": 1, "high": 1, "Low": 3, "critical": 0} {
+		if got, err := parseWantedPriority(in); err != nil || got != want {
+			t.Errorf("parseWantedPriority(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"5", "-1", "urgent", ""} {
+		if _, err := parseWantedPriority(in); err == nil {
+			t.Errorf("parseWantedPriority(%q) should fail", in)
+		}
+	}
+}
+
+func TestSelectWantedListColumns(t *testing.T) {
+	t.Parallel()
+	names := func(cols []wantedListColumn) []string {
+		var out []string
+		for _, c := range cols {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+
+	cols, err := selectWantedListColumns(nil, false)
+	if err != nil || !reflect.DeepEqual(names(cols), defaultWantedListColumns) {
+		t.Errorf("default columns = %v, %v", names(cols), err)
+	}
+	cols, _ = selectWantedListColumns(nil, true)
+	if !reflect.DeepEqual(names(cols), wantedListColumnNames()) {
+		t.Errorf("--wide columns = %v, want all", names(cols))
+	}
+	cols, err = selectWantedListColumns([]string{"Status", " id"}, false)
+	if err != nil || !reflect.DeepEqual(names(cols), []string{"status", "id"}) {
+		t.Errorf("--columns Status,id = %v, %v", names(cols), err)
+	}
+	if _, err := selectWantedListColumns([]string{"id", "owner"}, false); err == nil || !strings.Contains(err.Error(), `"owner"`) || !strings.Contains(err.Error(), "assignee") {
+		t.Errorf("unknown column error = %v, want it named with the known columns", err)
+	}
+}
+
+func TestWriteWantedListTSV(t *testing.T) {
+	t.Parallel()
+	items := []*doltserver.WantedItem{
+		{ID: "w-1", Title: "Tabs\tand\nnewlines", Priority: 1, Status: "claimed", Assignee: "nux",
+			CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)},
+		{ID: "w-2", Title: "Plain", Priority: 3, Status: "open"},
+	}
+	cols, _ := selectWantedListColumns([]string{"id", "title", "priority", "assignee", "created_at"}, false)
+
+	var buf bytes.Buffer
+	if err := writeWantedListTSV(&buf, items, cols); err != nil {
+		t.Fatal(err)
+	}
+	want := "w-1\tTabs and newlines\t1\tnux\t2026-10-01T12:00:00Z\n" +
+		"w-2\tPlain\t3\t\t\n"
+	if got := buf.String(); got != want {
+		t.Errorf("TSV =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRenderWantedListColumns_Wide(t *testing.T) {
+	t.Parallel()
+	cols, _ := selectWantedListColumns(nil, true)
+	var buf bytes.Buffer
+	renderWantedListColumns(&buf, []*doltserver.WantedItem{{ID: "w-1", Title: "One", Status: "claimed", Assignee: "nux", PostedBy: "poster"}}, "", cols)
+	out := buf.String()
+	for _, want := range []string{"ASSIGNEE", "POSTED BY", "UPDATED", "nux", "poster"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("wide table missing %q:\n%s", want, out)
+		}
+	}
+}
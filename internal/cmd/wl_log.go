@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+var (
+	wlLogLimit int
+)
+
+var wlLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show this town's local log of claims and completions",
+	Long: `Show the town-local log of wanted-board actions.
+
+Every gt wl claim and gt wl done run from this town appends a line to
+.wasteland/claims.jsonl recording the wanted ID, action, time, and whether
+it succeeded. The log lives in the workspace, so it works offline and
+survives resets of the wl-commons clone. It is rotated at 1 MiB.
+
+Examples:
+  gt wl log
+  gt wl log --limit 0
+  gt wl log --json | jq 'select(.outcome == "error")'`,
+	Args: cobra.NoArgs,
+	RunE: runWlLog,
+}
+
+func init() {
+	wlLogCmd.Flags().IntVar(&wlLogLimit, "limit", 20, "Show the N most recent entries (0 for all)")
+
+	wlCmd.AddCommand(wlLogCmd)
+}
+
+func runWlLog(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	entries, err := wasteland.ReadClaimLog(townRoot)
+	if err != nil {
+		return err
+	}
+	if wlLogLimit > 0 && len(entries) > wlLogLimit {
+		entries = entries[len(entries)-wlLogLimit:]
+	}
+
+	if wlJSON {
+		pretty := wlJSONPrettyOutput()
+		for _, e := range entries {
+			if err := writeWLJSON(os.Stdout, e, pretty); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No local wanted-board activity recorded yet.")
+		return nil
+	}
+	fmt.Print(buildClaimLogTable(entries).Render())
+	return nil
+}
+
+func buildClaimLogTable(entries []wasteland.ClaimLogEntry) *style.Table {
+	tbl := style.NewTable(
+		style.Column{Name: "TIME", Width: 20},
+		style.Column{Name: "ACTION", Width: 18},
+		style.Column{Name: "ID", Width: 14},
+		style.Column{Name: "OUTCOME", Width: 40},
+	)
+	for _, e := range entries {
+		outcome := e.Outcome
+		if e.Detail != "" {
+			outcome += ": " + e.Detail
+		}
+		tbl.AddRow(e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Action, valueOrDash(e.WantedID), outcome)
+	}
+	return tbl
+}
+
+// recordWlAction appends a wanted-board action to the town's claim log.
+// Logging is best-effort: a failure never fails the command itself. A dry
+// run logs nothing.
+func recordWlAction(townRoot, wantedID, action string, actionErr error) {
+	if doltserver.WLDryRun() {
+		return
+	}
+	entry := wasteland.ClaimLogEntry{WantedID: wantedID, Action: action, Outcome: wasteland.ClaimLogOK}
+	if actionErr != nil {
+		entry.Outcome = wasteland.ClaimLogError
+		entry.Detail = actionErr.Error()
+	}
+	_ = wasteland.AppendClaimLog(townRoot, entry)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+func TestRecordWlAction(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+
+	recordWlAction(townRoot, "w-1", "claim", nil)
+	recordWlAction(townRoot, "w-2", "done", fmt.Errorf("wanted item w-2 is not claimed"))
+
+	entries, err := wasteland.ReadClaimLog(townRoot)
+	if err != nil {
+		t.Fatalf("ReadClaimLog() error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Outcome != wasteland.ClaimLogOK || entries[0].Detail != "" {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	if entries[1].Outcome != wasteland.ClaimLogError || !strings.Contains(entries[1].Detail, "not claimed") {
+		t.Errorf("entries[1] = %+v", entries[1])
+	}
+
+	out := buildClaimLogTable(entries).Render()
+	if !strings.Contains(out, "w-2") || !strings.Contains(out, "error: wanted item") {
+		t.Errorf("table = %s", out)
+	}
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlMergeSimilarity float64
+	wlMergeDryRun     bool
+	wlMergeYes        bool
+)
+
+var wlMergeDuplicatesCmd = &cobra.Command{
+	Use:   "merge-duplicates",
+	Short: "Find open wanted items with near-identical titles and merge them",
+	Long: `Find groups of open wanted items whose titles are duplicates and fold
+each group into one item.
+
+Titles are compared after normalization (lowercased, punctuation dropped,
+whitespace collapsed). By default only identical normalized titles match;
+--similarity 0.9 also groups titles whose Levenshtein similarity is at
+least 90%.
+
+In each group the most urgent, then oldest, item is kept. The others are
+withdrawn with merged_into pointing at it, and a 'merge' event is recorded
+in their history, all in one Dolt commit per group. Each group is confirmed
+before it is written unless --yes is given; --dry-run only lists the groups.
+The duplicates' rows are snapshotted first; gt wl undo-last puts them back.
+
+When the wasteland names coordinators (roles.coordinators), only they may
+merge.
+
+Examples:
+  gt wl merge-duplicates --dry-run
+  gt wl merge-duplicates --similarity 0.85
+  gt wl merge-duplicates --yes`,
+	Args: cobra.NoArgs,
+	RunE: runWlMergeDuplicates,
+}
+
+func init() {
+	wlMergeDuplicatesCmd.Flags().Float64Var(&wlMergeSimilarity, "similarity", 1, "Minimum title similarity (0-1) for two items to be duplicates")
+	wlMergeDuplicatesCmd.Flags().BoolVar(&wlMergeDryRun, "dry-run", false, "List duplicate groups without merging")
+	wlMergeDuplicatesCmd.Flags().BoolVarP(&wlMergeYes, "yes", "y", false, "Merge every group without prompting")
+
+	wlCmd.AddCommand(wlMergeDuplicatesCmd)
+}
+
+func runWlMergeDuplicates(cmd *cobra.Command, args []string) error {
+	if wlMergeSimilarity <= 0 || wlMergeSimilarity > 1 {
+		return fmt.Errorf("--similarity must be in (0, 1], got %g", wlMergeSimilarity)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	groups, err := findDuplicateGroups(store, wlMergeSimilarity)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Println("No duplicate open items found.")
+		return nil
+	}
+	if !wlMergeDryRun {
+		if err := checkCanMerge(store, wlCfg.RigHandle); err != nil {
+			return err
+		}
+		var ids []string
+		for _, g := range groups {
+			ids = append(ids, duplicateIDs(g)...)
+		}
+		if err := snapshotBeforeWrite(townRoot, "merge-duplicates", false, func() (*doltserver.WLArchive, error) {
+			return doltserver.SnapshotWantedRows(townRoot, ids)
+		}); err != nil {
+			return err
+		}
+	}
+
+	merged := 0
+	for i, g := range groups {
+		renderDuplicateGroup(os.Stdout, i+1, g)
+		if wlMergeDryRun {
+			continue
+		}
+		if !wlMergeYes && !promptYesNo(fmt.Sprintf("Merge %d item(s) into %s?", len(g)-1, g[0].ID)) {
+			fmt.Println("  skipped")
+			continue
+		}
+		dupIDs, err := mergeDuplicateGroup(store, g, wlCfg.RigHandle)
+		for _, id := range dupIDs {
+			recordWlAction(townRoot, id, "merge", err)
+		}
+		if err != nil {
+			return fmt.Errorf("merging into %s: %w", g[0].ID, err)
+		}
+		merged += len(dupIDs)
+		fmt.Printf("  %s merged into %s\n", wlSuccessMark(), g[0].ID)
+	}
+
+	if wlMergeDryRun {
+		fmt.Printf("\n%d duplicate group(s); re-run without --dry-run to merge.\n", len(groups))
+		return nil
+	}
+	fmt.Printf("\nMerged %d item(s).\n", merged)
+	return nil
+}
+
+// checkCanMerge refuses rigs outside roles.coordinators when it is set.
+func checkCanMerge(store doltserver.WLCommonsStore, rigHandle string) error {
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return fmt.Errorf("querying wasteland settings: %w", err)
+	}
+	if len(splitCommaList(settings[wlSettingCoordinators])) > 0 && !settingListContains(settings, wlSettingCoordinators, rigHandle) {
+		return fmt.Errorf("rig %q is not a coordinator on this wasteland (see setting %s)", rigHandle, wlSettingCoordinators)
+	}
+	return nil
+}
+
+// findDuplicateGroups groups open items whose normalized titles are at least
+// threshold similar. Similarity is transitive within a group. Each group
+// keeps ListWanted order (most urgent, then oldest, first), so group[0] is
+// the item to keep.
+func findDuplicateGroups(store doltserver.WLCommonsStore, threshold float64) ([][]*doltserver.WantedItem, error) {
+	items, err := store.ListWanted(doltserver.WantedFilter{
+		Status:      doltserver.StatusOpen,
+		MinPriority: -1,
+		MaxPriority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing open items: %w", err)
+	}
+
+	titles := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = normalizeWantedTitle(item.Title)
+	}
+
+	// Union-find over item indexes.
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if titleSimilarity(titles[i], titles[j]) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	byRoot := make(map[int][]*doltserver.WantedItem)
+	var roots []int
+	for i, item := range items {
+		r := find(i)
+		if _, ok := byRoot[r]; !ok {
+			roots = append(roots, r)
+		}
+		byRoot[r] = append(byRoot[r], item)
+	}
+	var groups [][]*doltserver.WantedItem
+	for _, r := range roots {
+		if len(byRoot[r]) > 1 {
+			groups = append(groups, byRoot[r])
+		}
+	}
+	return groups, nil
+}
+
+// normalizeWantedTitle lowercases a title, drops punctuation, and collapses
+// whitespace, so "Fix: login bug!" and "fix login  bug" compare equal.
+func normalizeWantedTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// titleSimilarity returns 1 - levenshtein(a, b) / max(len(a), len(b)), in
+// runes. Two empty titles are identical.
+func titleSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein is the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// mergeDuplicateGroup checks the status model lets every duplicate in group
+// be withdrawn, then merges them into the first item. It returns the IDs the
+// merge covered, none when a check failed.
+func mergeDuplicateGroup(store doltserver.WLCommonsStore, group []*doltserver.WantedItem, rigHandle string) ([]string, error) {
+	for _, item := range group[1:] {
+		if err := requireTransition(store, item.ID, item.Status, doltserver.StatusWithdrawn); err != nil {
+			return nil, err
+		}
+	}
+	dupIDs := duplicateIDs(group)
+	return dupIDs, store.MergeWanted(group[0].ID, dupIDs, rigHandle)
+}
+
+func duplicateIDs(group []*doltserver.WantedItem) []string {
+	ids := make([]string, 0, len(group)-1)
+	for _, item := range group[1:] {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func renderDuplicateGroup(w io.Writer, n int, group []*doltserver.WantedItem) {
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render(fmt.Sprintf("Group %d:", n)))
+	fmt.Fprintf(w, "  keep   %s  %s %s\n", group[0].ID, wlFormatPriority(fmt.Sprint(group[0].Priority)), group[0].Title)
+	for _, item := range group[1:] {
+		fmt.Fprintf(w, "  merge  %s  %s %s\n", item.ID, wlFormatPriority(fmt.Sprint(item.Priority)), item.Title)
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestNormalizeWantedTitle(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in, want string
+	}{
+		{"Fix: login bug!", "fix login bug"},
+		{"  fix   LOGIN bug ", "fix login bug"},
+		{"Add v2 API", "add v2 api"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeWantedTitle(tt.in); got != tt.want {
+			t.Errorf("normalizeWantedTitle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	t.Parallel()
+	if got := titleSimilarity("fix login bug", "fix login bug"); got != 1 {
+		t.Errorf("identical titles = %v, want 1", got)
+	}
+	// One substitution in ten runes.
+	if got := titleSimilarity("fix logins", "fix login2"); got != 0.9 {
+		t.Errorf("one edit in ten = %v, want 0.9", got)
+	}
+	if got := levenshtein([]rune("kitten"), []rune("sitting")); got != 3 {
+		t.Errorf("levenshtein(kitten, sitting) = %d, want 3", got)
+	}
+}
+
+func TestFindDuplicateGroups(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "Fix login bug", Priority: 2})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-b", Title: "fix: login bug", Priority: 1})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-c", Title: "Fix logins bug", Priority: 3})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-d", Title: "Write docs", Priority: 2})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-e", Title: "Fix login bug", Priority: 2})
+	_ = store.ClaimWanted("w-e", "rig-b")
+
+	groups, err := findDuplicateGroups(store, 1)
+	if err != nil {
+		t.Fatalf("findDuplicateGroups() error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0].ID != "w-b" || groups[0][1].ID != "w-a" {
+		t.Fatalf("exact groups = %v, want [[w-b w-a]] (most urgent kept first)", groupIDs(groups))
+	}
+
+	groups, err = findDuplicateGroups(store, 0.9)
+	if err != nil {
+		t.Fatalf("findDuplicateGroups() error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 3 {
+		t.Errorf("fuzzy groups = %v, want one group of three", groupIDs(groups))
+	}
+}
+
+func TestMergeDuplicates_WithdrawsIntoKeeper(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "Fix login bug"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-b", Title: "Fix login bug"})
+
+	groups, err := findDuplicateGroups(store, 1)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("findDuplicateGroups() = %v, %v", groupIDs(groups), err)
+	}
+	keep := groups[0][0].ID
+	if _, err := mergeDuplicateGroup(store, groups[0], "curator"); err != nil {
+		t.Fatalf("mergeDuplicateGroup() error: %v", err)
+	}
+	dup := duplicateIDs(groups[0])[0]
+	item, _ := store.QueryWanted(dup)
+	if item.Status != doltserver.StatusWithdrawn || item.MergedInto != keep {
+		t.Errorf("duplicate %s = status %q merged_into %q, want withdrawn into %s", dup, item.Status, item.MergedInto, keep)
+	}
+	if groups, _ := findDuplicateGroups(store, 1); len(groups) != 0 {
+		t.Errorf("groups after merge = %v, want none", groupIDs(groups))
+	}
+}
+
+func TestMergeDuplicates_ChecksStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "Fix login bug"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-b", Title: "Fix login bug"})
+	store.settings[doltserver.SettingWorkflowTransitions] = "open>claimed,claimed>open"
+
+	groups, err := findDuplicateGroups(store, 1)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("findDuplicateGroups() = %v, %v", groupIDs(groups), err)
+	}
+	ids, err := mergeDuplicateGroup(store, groups[0], "curator")
+	if err == nil || !strings.Contains(err.Error(), "cannot move from open to withdrawn") {
+		t.Fatalf("mergeDuplicateGroup() error = %v, want the forbidden transition", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("mergeDuplicateGroup() merged %v despite the refusal", ids)
+	}
+	if groups, _ := findDuplicateGroups(store, 1); len(groups) != 1 {
+		t.Errorf("groups after a refused merge = %v, want the group intact", groupIDs(groups))
+	}
+}
+
+func TestCheckCanMerge(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	if err := checkCanMerge(store, "anyone"); err != nil {
+		t.Errorf("checkCanMerge() without coordinators = %v, want nil", err)
+	}
+	store.settings[wlSettingCoordinators] = "curator"
+	if err := checkCanMerge(store, "curator"); err != nil {
+		t.Errorf("checkCanMerge(coordinator) = %v, want nil", err)
+	}
+	if err := checkCanMerge(store, "anyone"); err == nil {
+		t.Error("checkCanMerge() should refuse non-coordinators")
+	}
+}
+
+func groupIDs(groups [][]*doltserver.WantedItem) [][]string {
+	out := make([][]string, len(groups))
+	for i, g := range groups {
+		for _, item := range g {
+			out[i] = append(out[i], item.ID)
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlMineAll bool
+
+var wlMineCmd = &cobra.Command{
+	Use:   "mine",
+	Short: "List the items this town has claimed",
+	Long: `List the wanted items this town is working on: those it has claimed,
+has a draft completion for, or has submitted for review. Items claimed
+for a group this town belongs to are included, with the group shown,
+along with the worker each item is assigned to (see gt wl assign).
+
+--all also lists completed items this town claimed, for a history of
+the work it has done.
+
+Examples:
+  gt wl mine
+  gt wl mine --all
+  gt wl mine --json`,
+	Args: cobra.NoArgs,
+	RunE: runWlMine,
+}
+
+func init() {
+	wlMineCmd.Flags().BoolVar(&wlMineAll, "all", false, "Include completed items")
+
+	wlCmd.AddCommand(wlMineCmd)
+}
+
+func runWlMine(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	items, err := listMine(store, wlCfg.RigHandle, wlMineAll)
+	if err != nil {
+		return err
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, buildWantedListJSON(items), wlJSONPrettyOutput())
+	}
+	renderMine(os.Stdout, items, wlCfg.RigHandle)
+	return nil
+}
+
+// mineStatuses are the statuses of work a town still has on its plate.
+var mineStatuses = []string{doltserver.StatusClaimed, doltserver.StatusDraft, doltserver.StatusInReview}
+
+// listMine returns the items rigHandle holds a claim on, directly or
+// through a group, that are still in progress, plus completed ones when
+// all is set.
+func listMine(store doltserver.WLCommonsStore, rigHandle string, all bool) ([]*doltserver.WantedItem, error) {
+	statuses := append([]string{}, mineStatuses...)
+	if all {
+		statuses = append(statuses, doltserver.StatusCompleted)
+	}
+	items, err := store.ListWanted(doltserver.WantedFilter{
+		Statuses:    statuses,
+		HeldBy:      rigHandle,
+		MinPriority: -1,
+		MaxPriority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing claimed items: %w", err)
+	}
+	return items, nil
+}
+
+func renderMine(w io.Writer, items []*doltserver.WantedItem, rigHandle string) {
+	if len(items) == 0 {
+		fmt.Fprintf(w, "%s has no claimed items.\n", rigHandle)
+		return
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "ID", Width: 14},
+		style.Column{Name: "TITLE", Width: 44},
+		style.Column{Name: "PRI", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "STATUS", Width: 10},
+		style.Column{Name: "GROUP", Width: 14},
+		style.Column{Name: "ASSIGNEE", Width: 16},
+	)
+	for _, item := range items {
+		tbl.AddRow(item.ID, item.Title, wlFormatPriority(fmt.Sprint(item.Priority)), item.Status, valueOrDash(item.ClaimedGroup), valueOrDash(item.Assignee))
+	}
+	fmt.Fprintf(w, "Claimed by %s (%d):\n\n", rigHandle, len(items))
+	fmt.Fprint(w, tbl.Render())
+}
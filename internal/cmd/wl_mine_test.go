@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestListMine(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, id := range []string{"w-1", "w-2", "w-3", "w-4", "w-5"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: "Item " + id, Priority: 2})
+	}
+	_ = store.AddGroupMembers("crew", "other-rig", []string{"my-rig", "other-rig"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+	_ = store.ClaimWanted("w-2", "my-rig")
+	_ = store.SubmitCompletion("c-2", "w-2", "my-rig", "https://example.com/2")
+	_ = store.ClaimWantedForGroup("w-3", "other-rig", "crew")
+	_ = store.ClaimWanted("w-4", "other-rig")
+	_ = store.ClaimWanted("w-5", "my-rig")
+	_ = store.SubmitCompletion("c-5", "w-5", "my-rig", "https://example.com/5")
+	_ = store.ApproveCompletions([]string{"w-5"}, "poster")
+
+	ids := func(items []*doltserver.WantedItem) string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	items, err := listMine(store, "my-rig", false)
+	if err != nil {
+		t.Fatalf("listMine() error: %v", err)
+	}
+	if got := ids(items); got != "w-1,w-2,w-3" {
+		t.Errorf("listMine() = %s, want w-1,w-2,w-3", got)
+	}
+
+	var buf bytes.Buffer
+	renderMine(&buf, items, "my-rig")
+	if out := buf.String(); !strings.Contains(out, "in_review") || !strings.Contains(out, "crew") {
+		t.Errorf("table =\n%s", out)
+	}
+
+	items, err = listMine(store, "my-rig", true)
+	if err != nil {
+		t.Fatalf("listMine(all) error: %v", err)
+	}
+	if got := ids(items); got != "w-1,w-2,w-3,w-5" {
+		t.Errorf("listMine(all) = %s, want w-1,w-2,w-3,w-5", got)
+	}
+}
+
+func TestRenderMine_Empty(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	renderMine(&buf, nil, "my-rig")
+	if got := buf.String(); got != "my-rig has no claimed items.\n" {
+		t.Errorf("output = %q", got)
+	}
+}
@@ -2,13 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/wasteland"
-	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
@@ -19,6 +18,9 @@ var (
 	wlPostPriority    int
 	wlPostEffort      string
 	wlPostTags        string
+	wlPostDependsOn   string
+	wlPostTimeout     string
+	wlPostQuiet       bool
 )
 
 var wlPostCmd = &cobra.Command{
@@ -28,6 +30,8 @@ var wlPostCmd = &cobra.Command{
 
 Creates a wanted item with a unique w-<hash> ID and inserts it into the
 wl-commons database. Phase 1 (wild-west): direct write to main branch.
+A wasteland can replace the w stem with its own (e.g. task-<hash>) through
+the setting ids.wanted_prefix.
 
 The posted_by field is set to the rig's DoltHub org (DOLTHUB_ORG) or
 falls back to the directory name.
@@ -35,7 +39,15 @@ falls back to the directory name.
 Examples:
   gt wl post --title "Fix auth bug" --project gastown --type bug
   gt wl post --title "Add federation sync" --type feature --priority 1 --effort large
-  gt wl post --title "Update docs" --tags "docs,federation" --effort small`,
+  gt wl post --title "Update docs" --tags "docs,federation" --effort small
+  gt wl post --title "Ship v2" --depends-on w-abc123,w-def456
+  gt wl post --title "Hotfix" --priority 0 --timeout-action escalate
+  id=$(gt wl post --title "Triage flaky tests" -q)
+
+--timeout-action declares what happens when a claim on the item lapses:
+  reopen    return the item to the board (default)
+  notify    reopen and mail the poster
+  escalate  reopen and raise the item's priority`,
 	RunE: runWlPost,
 }
 
@@ -47,6 +59,10 @@ func init() {
 	wlPostCmd.Flags().IntVar(&wlPostPriority, "priority", 2, "Priority: 0=critical, 1=high, 2=medium, 3=low, 4=backlog")
 	wlPostCmd.Flags().StringVar(&wlPostEffort, "effort", "medium", "Effort level: trivial, small, medium, large, epic")
 	wlPostCmd.Flags().StringVar(&wlPostTags, "tags", "", "Comma-separated tags (e.g., 'go,auth,federation')")
+	wlPostCmd.Flags().StringVar(&wlPostDependsOn, "depends-on", "", "Comma-separated wanted IDs this item depends on")
+	wlPostCmd.Flags().StringVar(&wlPostTimeout, "timeout-action", doltserver.TimeoutActionReopen, "On claim expiry: reopen, notify, escalate")
+
+	wlPostCmd.Flags().BoolVarP(&wlPostQuiet, "quiet", "q", false, "Just print the new wanted ID")
 
 	_ = wlPostCmd.MarkFlagRequired("title")
 
@@ -54,34 +70,28 @@ func init() {
 }
 
 func runWlPost(cmd *cobra.Command, args []string) error {
-	townRoot, err := workspace.FindFromCwdOrError()
+	townRoot, err := wlRun.townRoot()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		return err
 	}
 
-	var tags []string
-	if wlPostTags != "" {
-		for _, t := range strings.Split(wlPostTags, ",") {
-			t = strings.TrimSpace(t)
-			if t != "" {
-				tags = append(tags, t)
-			}
-		}
-	}
+	tags := splitCommaList(wlPostTags)
 
 	if err := validatePostInputs(wlPostType, wlPostEffort, wlPostPriority); err != nil {
 		return err
 	}
+	if !doltserver.ValidTimeoutAction(wlPostTimeout) {
+		return fmt.Errorf("invalid --timeout-action %q: must be one of reopen, notify, escalate", wlPostTimeout)
+	}
 
 	store := doltserver.NewWLCommons(townRoot)
 
-	wlCfg, err := wasteland.LoadConfig(townRoot)
+	wlCfg, err := wlRun.wastelandConfig()
 	if err != nil {
-		return fmt.Errorf("loading wasteland config: %w", err)
+		return err
 	}
 
 	item := &doltserver.WantedItem{
-		ID:          doltserver.GenerateWantedID(wlPostTitle),
 		Title:       wlPostTitle,
 		Description: wlPostDescription,
 		Project:     wlPostProject,
@@ -90,13 +100,25 @@ func runWlPost(cmd *cobra.Command, args []string) error {
 		Tags:        tags,
 		PostedBy:    wlCfg.RigHandle,
 		EffortLevel: wlPostEffort,
+		DependsOn:   splitCommaList(wlPostDependsOn),
+	}
+	if wlPostTimeout != doltserver.TimeoutActionReopen {
+		item.TimeoutAction = wlPostTimeout
 	}
 
 	if err := postWanted(store, item); err != nil {
 		return err
 	}
 
-	fmt.Printf("%s Posted wanted item: %s\n", style.Bold.Render("✓"), style.Bold.Render(item.ID))
+	if wlPostQuiet {
+		fmt.Println(item.ID)
+		return nil
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlItemResultJSON{ID: item.ID, Title: item.Title, Status: doltserver.StatusOpen}, wlJSONPrettyOutput())
+	}
+
+	fmt.Printf("%s Posted wanted item: %s\n", wlSuccessMark(), style.Bold.Render(item.ID))
 	fmt.Printf("  Title:    %s\n", item.Title)
 	if item.Project != "" {
 		fmt.Printf("  Project:  %s\n", item.Project)
@@ -109,11 +131,29 @@ func runWlPost(cmd *cobra.Command, args []string) error {
 	if len(item.Tags) > 0 {
 		fmt.Printf("  Tags:     %s\n", strings.Join(item.Tags, ", "))
 	}
+	if len(item.DependsOn) > 0 {
+		fmt.Printf("  Depends on: %s\n", strings.Join(item.DependsOn, ", "))
+	}
+	if item.TimeoutAction != "" {
+		fmt.Printf("  On claim timeout: %s\n", item.TimeoutAction)
+	}
 	fmt.Printf("  Posted by: %s\n", item.PostedBy)
 
 	return nil
 }
 
+// splitCommaList splits a comma-separated flag value, dropping blank entries.
+func splitCommaList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 // validatePostInputs validates the type, effort, and priority fields.
 func validatePostInputs(itemType, effort string, priority int) error {
 	validTypes := map[string]bool{
@@ -138,11 +178,24 @@ func validatePostInputs(itemType, effort string, priority int) error {
 }
 
 // postWanted contains the testable business logic for posting a wanted item.
+// An item without an ID gets one with the wasteland's ids.wanted_prefix.
 func postWanted(store doltserver.WLCommonsStore, item *doltserver.WantedItem) error {
 	if err := store.EnsureDB(); err != nil {
 		return fmt.Errorf("ensuring wl-commons database: %w", err)
 	}
 
+	if item.ID == "" {
+		settings, err := store.QuerySettings()
+		if err != nil {
+			return fmt.Errorf("loading wasteland settings: %w", err)
+		}
+		prefix, err := wantedIDPrefix(settings)
+		if err != nil {
+			return err
+		}
+		item.ID = doltserver.GenerateWantedID(prefix, item.Title)
+	}
+
 	if err := store.InsertWanted(item); err != nil {
 		return fmt.Errorf("posting wanted item: %w", err)
 	}
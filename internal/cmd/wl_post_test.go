@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
@@ -40,18 +41,32 @@ func TestPostWanted_Success(t *testing.T) {
 	}
 }
 
-func TestPostWanted_EmptyID(t *testing.T) {
+func TestPostWanted_GeneratesPrefixedID(t *testing.T) {
 	t.Parallel()
-	store := newFakeWLCommonsStore()
-
-	item := &doltserver.WantedItem{
-		ID:    "",
-		Title: "Some title",
+	for _, tc := range []struct{ setting, wantPrefix string }{
+		{"", "w-"},
+		{"task", "task-"},
+	} {
+		store := newFakeWLCommonsStore()
+		if tc.setting != "" {
+			store.settings[wlSettingWantedIDPrefix] = tc.setting
+		}
+		item := &doltserver.WantedItem{Title: "Some title"}
+		if err := postWanted(store, item); err != nil {
+			t.Fatalf("postWanted() error: %v", err)
+		}
+		if !strings.HasPrefix(item.ID, tc.wantPrefix) {
+			t.Errorf("ID = %q, want prefix %q", item.ID, tc.wantPrefix)
+		}
+		if _, err := store.QueryWanted(item.ID); err != nil {
+			t.Errorf("posted item %s not stored: %v", item.ID, err)
+		}
 	}
 
-	err := postWanted(store, item)
-	if err == nil {
-		t.Fatal("postWanted() expected error for empty ID")
+	store := newFakeWLCommonsStore()
+	store.settings[wlSettingWantedIDPrefix] = "Bad Prefix"
+	if err := postWanted(store, &doltserver.WantedItem{Title: "Some title"}); err == nil {
+		t.Error("postWanted() should reject an invalid ids.wanted_prefix")
 	}
 }
 
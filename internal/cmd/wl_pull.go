@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// buildWlDoltCmd returns a dolt command that runs in dir, a local
+// wl-commons clone. Tests replace it to observe dolt invocations.
+var buildWlDoltCmd = func(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("dolt", args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// wlCommonsCloneDir returns the town's local wl-commons clone, as gt wl sync
+// finds it: the joined fork first, then the standard locations.
+func wlCommonsCloneDir(townRoot string, cfg *wasteland.Config) string {
+	if cfg != nil && cfg.LocalDir != "" {
+		return cfg.LocalDir
+	}
+	return findWLCommonsFork(townRoot)
+}
+
+// claimAutoPull returns the Refresh hook for a claim: a pull of upstream
+// into cloneDir when --auto-pull is set or the wasteland turns on
+// claim.auto_pull, or nil when the claim should use local data as is.
+func claimAutoPull(store doltserver.WLCommonsStore, flag bool, cloneDir string) (func() error, error) {
+	if !flag {
+		settings, err := store.QuerySettings()
+		if err != nil {
+			return nil, fmt.Errorf("loading wasteland settings: %w", err)
+		}
+		if !settingBool(settings, wlSettingAutoPull) {
+			return nil, nil
+		}
+	}
+	if cloneDir == "" {
+		return nil, fmt.Errorf("--auto-pull: no local wl-commons clone found\n\nJoin a wasteland first: gt wl join <org/db>")
+	}
+	return func() error { return pullWlCommons(cloneDir) }, nil
+}
+
+// pullWlCommons pulls upstream main into the clone at dir. A pull that stops
+// on merge conflicts is reported with how to finish or undo the merge,
+// since the clone is left mid-merge.
+func pullWlCommons(dir string) error {
+	fmt.Fprintf(os.Stderr, "Pulling upstream into %s...\n", dir)
+	out, err := doltserver.RunDoltCommand(buildWlDoltCmd(dir, "pull", "upstream", "main"))
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimSpace(string(out))
+	if isDoltMergeConflict(msg) {
+		return fmt.Errorf("auto-pull stopped on merge conflicts in %s; nothing was claimed\n\n"+
+			"Resolve them there (dolt conflicts cat <table>, dolt conflicts resolve, dolt commit)\n"+
+			"or undo the pull with: dolt merge --abort\n"+
+			"then retry the claim", dir)
+	}
+	return fmt.Errorf("auto-pull: dolt pull upstream main: %w (%s)", err, msg)
+}
+
+// isDoltMergeConflict reports whether dolt output describes merge conflicts.
+func isDoltMergeConflict(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "conflict")
+}
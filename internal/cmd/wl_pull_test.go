@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// queryRecordingStore logs board reads into events, so tests can check
+// what ran before them.
+type queryRecordingStore struct {
+	*fakeWLCommonsStore
+	events *[]string
+}
+
+func (s *queryRecordingStore) QueryWanted(wantedID string) (*doltserver.WantedItem, error) {
+	*s.events = append(*s.events, "query "+wantedID)
+	return s.fakeWLCommonsStore.QueryWanted(wantedID)
+}
+
+func (s *queryRecordingStore) ListWanted(filter doltserver.WantedFilter) ([]*doltserver.WantedItem, error) {
+	*s.events = append(*s.events, "list")
+	return s.fakeWLCommonsStore.ListWanted(filter)
+}
+
+func TestClaimWanted_AutoPullRunsBeforeQuery(t *testing.T) {
+	t.Parallel()
+	var events []string
+	store := &queryRecordingStore{fakeWLCommonsStore: newFakeWLCommonsStore(), events: &events}
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	pull := func() error {
+		events = append(events, "pull")
+		return nil
+	}
+
+	if _, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{Refresh: pull}); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if len(events) < 2 || events[0] != "pull" || events[1] != "query w-abc" {
+		t.Errorf("events = %v, want pull before the first query", events)
+	}
+
+	// Auto-claim pulls once, before listing, however many candidates it tries.
+	events = nil
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-2", Title: "Two"})
+	_ = store.fakeWLCommonsStore.ClaimWanted("w-1", "rival-rig")
+	if _, err := autoClaimWanted(context.Background(), store, "my-rig", priorityBand{Min: -1, Max: -1}, claimOptions{Refresh: pull}); err != nil {
+		t.Fatalf("autoClaimWanted() error: %v", err)
+	}
+	if events[0] != "pull" || strings.Count(strings.Join(events, ","), "pull") != 1 {
+		t.Errorf("events = %v, want exactly one pull, first", events)
+	}
+}
+
+func TestClaimWanted_AutoPullFailureAborts(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug"})
+	pullErr := errors.New("auto-pull stopped on merge conflicts")
+
+	if _, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{Refresh: func() error { return pullErr }}); !errors.Is(err, pullErr) {
+		t.Fatalf("claimWanted() error = %v, want the pull error", err)
+	}
+	if got, _ := store.QueryWanted("w-abc"); got.Status != doltserver.StatusOpen {
+		t.Errorf("status = %q, want open after a failed pull", got.Status)
+	}
+}
+
+func TestClaimAutoPull_Setting(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	if refresh, err := claimAutoPull(store, false, "/clone"); err != nil || refresh != nil {
+		t.Errorf("claimAutoPull(off) = %v, %v; want no refresh", refresh != nil, err)
+	}
+	store.settings[wlSettingAutoPull] = "true"
+	if refresh, err := claimAutoPull(store, false, "/clone"); err != nil || refresh == nil {
+		t.Errorf("claimAutoPull(setting on) = %v, %v; want a refresh", refresh != nil, err)
+	}
+	if _, err := claimAutoPull(store, true, ""); err == nil {
+		t.Error("claimAutoPull() without a local clone should fail")
+	}
+}
+
+func TestPullWlCommons_Conflict(t *testing.T) {
+	orig := buildWlDoltCmd
+	t.Cleanup(func() { buildWlDoltCmd = orig })
+
+	var gotArgs []string
+	buildWlDoltCmd = func(dir string, args ...string) *exec.Cmd {
+		gotArgs = args
+		return exec.Command("sh", "-c", "echo 'Auto-merging wanted'; echo 'CONFLICT (content): Merge conflict in wanted'; exit 1")
+	}
+	err := pullWlCommons("/clone")
+	if strings.Join(gotArgs, " ") != "pull upstream main" {
+		t.Errorf("dolt args = %v, want pull upstream main", gotArgs)
+	}
+	if err == nil || !strings.Contains(err.Error(), "dolt merge --abort") || !strings.Contains(err.Error(), "nothing was claimed") {
+		t.Errorf("pullWlCommons() error = %v, want conflict guidance", err)
+	}
+
+	buildWlDoltCmd = func(dir string, args ...string) *exec.Cmd { return exec.Command("sh", "-c", "exit 0") }
+	if err := pullWlCommons("/clone"); err != nil {
+		t.Errorf("pullWlCommons() error = %v, want nil on a clean pull", err)
+	}
+}
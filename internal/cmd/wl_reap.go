@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// defaultClaimTTL is how long a claim may sit before gt wl reap releases it.
+const defaultClaimTTL = 72 * time.Hour
+
+var (
+	wlReapTTL    time.Duration
+	wlReapDryRun bool
+)
+
+var wlReapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Release stale claims back to the board",
+	Long: `Return claimed items whose claim is older than --ttl to open.
+
+Towns sometimes claim work and disappear. Any town may run reap to release
+such claims so the work is not blocked forever. A claim is stale when it
+was taken more than --ttl ago and its lease (see gt wl heartbeat) is not
+still running; a town that keeps heartbeating keeps its claim. Claims
+taken before gt recorded claim times are never reaped; use
+gt wl unclaim --force for those.
+
+Each released item is reported and recorded in its history. An item whose
+claim is renewed or retaken while reap runs is skipped.
+
+Examples:
+  gt wl reap
+  gt wl reap --ttl 168h
+  gt wl reap --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runWlReap,
+}
+
+func init() {
+	wlReapCmd.Flags().DurationVar(&wlReapTTL, "ttl", defaultClaimTTL, "Release claims older than this")
+	wlReapCmd.Flags().BoolVar(&wlReapDryRun, "dry-run", false, "List stale claims without releasing them")
+
+	wlCmd.AddCommand(wlReapCmd)
+}
+
+func runWlReap(cmd *cobra.Command, args []string) error {
+	if wlReapTTL <= 0 {
+		return fmt.Errorf("--ttl must be positive, got %s", wlReapTTL)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	cutoff := time.Now().Add(-wlReapTTL)
+	if wlReapDryRun {
+		stale, err := store.QueryStaleClaims(cutoff)
+		if err != nil {
+			return err
+		}
+		renderStaleClaims(os.Stdout, stale, wlReapTTL)
+		return nil
+	}
+	_, err = reapStaleClaims(cmd.Context(), os.Stdout, store, townRoot, wlCfg.RigHandle, cutoff, wlReapTTL)
+	return err
+}
+
+// reapStaleClaims releases every claim taken before cutoff whose lease has
+// lapsed, reporting each to w, and returns how many were released. A claim
+// the status model will not return to open, or that stops being stale
+// mid-run, is warned about and skipped.
+func reapStaleClaims(ctx context.Context, w io.Writer, store doltserver.WLCommonsStore, townRoot, actor string, cutoff time.Time, ttl time.Duration) (int, error) {
+	stale, err := store.QueryStaleClaims(cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if len(stale) == 0 {
+		fmt.Fprintf(w, "No claims older than %s.\n", ttl)
+		return 0, nil
+	}
+
+	released := 0
+	for _, item := range stale {
+		if err := requireTransition(store, item.ID, item.Status, doltserver.StatusOpen); err != nil {
+			style.PrintWarning("skipping %s: %v", item.ID, err)
+			continue
+		}
+		if err := throttleWlWrite(ctx, store, townRoot); err != nil {
+			return released, err
+		}
+		change := "released (stale claim by " + item.ClaimedBy + ")"
+		err := commitThenNotify(store, townRoot, item.ID, actor, "reap", change, func() error {
+			return store.ReapClaim(item.ID, cutoff, actor)
+		})
+		if err != nil {
+			style.PrintWarning("skipping %s: %v", item.ID, err)
+			continue
+		}
+		released++
+		fmt.Fprintf(w, "%s Released %s %s\n", wlSuccessMark(), style.Bold.Render(item.ID), item.Title)
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render(staleClaimSummary(item)))
+	}
+	fmt.Fprintf(w, "\nReleased %d of %d stale claim(s).\n", released, len(stale))
+	return released, nil
+}
+
+// renderStaleClaims lists the claims reap would release.
+func renderStaleClaims(w io.Writer, stale []*doltserver.WantedItem, ttl time.Duration) {
+	if len(stale) == 0 {
+		fmt.Fprintf(w, "No claims older than %s.\n", ttl)
+		return
+	}
+	fmt.Fprintf(w, "%d claim(s) older than %s would be released:\n", len(stale), ttl)
+	for _, item := range stale {
+		fmt.Fprintf(w, "  %s %s\n", style.Bold.Render(item.ID), item.Title)
+		fmt.Fprintf(w, "    %s\n", style.Dim.Render(staleClaimSummary(item)))
+	}
+}
+
+// staleClaimSummary describes who held item's claim and since when.
+func staleClaimSummary(item *doltserver.WantedItem) string {
+	return fmt.Sprintf("claimed by %s since %s", valueOrDash(item.ClaimedBy), item.ClaimedAt.Local().Format("2006-01-02 15:04"))
+}
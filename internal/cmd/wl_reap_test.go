@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestReapStaleClaims(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	now := time.Now()
+	for _, id := range []string{"w-stale", "w-fresh", "w-leased", "w-legacy"} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: id, Title: id})
+		_ = store.ClaimWanted(id, "gone-rig")
+	}
+	store.items["w-stale"].ClaimedAt = now.Add(-100 * time.Hour)
+	store.items["w-leased"].ClaimedAt = now.Add(-100 * time.Hour)
+	store.items["w-leased"].ExpiresAt = now.Add(time.Hour)
+	store.items["w-legacy"].ClaimedAt = time.Time{}
+
+	var out bytes.Buffer
+	released, err := reapStaleClaims(context.Background(), &out, store, t.TempDir(), "janitor", now.Add(-defaultClaimTTL), defaultClaimTTL)
+	if err != nil {
+		t.Fatalf("reapStaleClaims() error: %v", err)
+	}
+	if released != 1 {
+		t.Errorf("released %d claims, want 1\n%s", released, out.String())
+	}
+	if !strings.Contains(out.String(), "w-stale") || !strings.Contains(out.String(), "claimed by gone-rig since") {
+		t.Errorf("output does not report the released claim:\n%s", out.String())
+	}
+
+	want := map[string]string{
+		"w-stale":  doltserver.StatusOpen,
+		"w-fresh":  doltserver.StatusClaimed,
+		"w-leased": doltserver.StatusClaimed,
+		"w-legacy": doltserver.StatusClaimed,
+	}
+	for id, status := range want {
+		if item, _ := store.QueryWanted(id); item.Status != status {
+			t.Errorf("%s status = %q, want %q", id, item.Status, status)
+		}
+	}
+}
+
+func TestReapStaleClaims_None(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fresh"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+
+	var out bytes.Buffer
+	released, err := reapStaleClaims(context.Background(), &out, store, t.TempDir(), "janitor", time.Now().Add(-time.Hour), time.Hour)
+	if err != nil || released != 0 {
+		t.Fatalf("reapStaleClaims() = %d, %v; want 0, nil", released, err)
+	}
+	if !strings.Contains(out.String(), "No claims older than 1h0m0s.") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestReapStaleClaims_ChecksStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Stale"})
+	_ = store.ClaimWanted("w-1", "gone-rig")
+	store.items["w-1"].ClaimedAt = time.Now().Add(-100 * time.Hour)
+	store.settings[doltserver.SettingWorkflowTransitions] = "open>claimed,claimed>in_review"
+
+	var out bytes.Buffer
+	released, err := reapStaleClaims(context.Background(), &out, store, t.TempDir(), "janitor", time.Now().Add(-defaultClaimTTL), defaultClaimTTL)
+	if err != nil || released != 0 {
+		t.Fatalf("reapStaleClaims() = %d, %v; want 0, nil", released, err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusClaimed {
+		t.Errorf("status = %q, want claimed untouched", item.Status)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlReindexDryRun bool
+
+var wlReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Recompute derived columns on the wanted board",
+	Args:  cobra.NoArgs,
+	RunE:  runWlReindex,
+	Long: `Recompute the wanted table's derived columns from their sources and
+correct any that have drifted, for boards edited directly in Dolt:
+
+  completion_count  the item's rows in completions, superseded included
+  tags              trimmed, with empty and repeated tags dropped
+
+All corrections are written in one transaction and Dolt commit, after the
+affected rows are snapshotted (undo with gt wl undo-last). --dry-run lists
+the corrections without writing.
+
+Examples:
+  gt wl reindex --dry-run
+  gt wl reindex`,
+}
+
+func init() {
+	wlReindexCmd.Flags().BoolVar(&wlReindexDryRun, "dry-run", false, "List corrections without writing")
+
+	wlCmd.AddCommand(wlReindexCmd)
+}
+
+func runWlReindex(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	corrections, err := doltserver.PlanReindex(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(corrections) == 0 {
+		fmt.Println("All derived columns are consistent.")
+		return nil
+	}
+	renderReindexCorrections(os.Stdout, corrections)
+	if wlReindexDryRun {
+		fmt.Printf("\n%d correction(s); re-run without --dry-run to apply.\n", len(corrections))
+		return nil
+	}
+
+	ids := reindexWantedIDs(corrections)
+	if err := snapshotBeforeWrite(townRoot, "reindex", false, func() (*doltserver.WLArchive, error) {
+		return doltserver.SnapshotWantedRows(townRoot, ids)
+	}); err != nil {
+		return err
+	}
+	if err := doltserver.ApplyReindex(townRoot, corrections); err != nil {
+		return fmt.Errorf("applying corrections: %w", err)
+	}
+	fmt.Printf("\n%s Corrected %d value(s) on %d row(s).\n", wlSuccessMark(), len(corrections), len(ids))
+	return nil
+}
+
+// renderReindexCorrections prints one line per drifted value.
+func renderReindexCorrections(w io.Writer, corrections []doltserver.ReindexCorrection) {
+	for _, c := range corrections {
+		old := c.Old
+		if old == "" {
+			old = "NULL"
+		}
+		fmt.Fprintf(w, "  %s %s: %s → %s\n", c.WantedID, c.Column, style.Dim.Render(old), c.New)
+	}
+}
+
+// reindexWantedIDs returns the distinct items corrections touch, in order.
+func reindexWantedIDs(corrections []doltserver.ReindexCorrection) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, c := range corrections {
+		if !seen[c.WantedID] {
+			seen[c.WantedID] = true
+			ids = append(ids, c.WantedID)
+		}
+	}
+	return ids
+}
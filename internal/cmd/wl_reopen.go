@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlReopenForce bool
+
+var wlReopenCmd = &cobra.Command{
+	Use:   "reopen <wanted-id>",
+	Short: "Return a closed or in-review item to the board",
+	Long: `Move a wanted item back to open so it can be claimed again.
+
+The item must be in review, completed, or withdrawn. Its claim is cleared
+and its current completion is retired (shown as superseded by "reopen"),
+so the next claimant submits afresh. The reopening town and time are
+recorded on the item and shown by gt wl show.
+
+Only the town that posted the item may reopen it, as with gt wl accept and
+gt wl reject. --force reopens an item posted by another town; the forced
+reopen is recorded in the item's history.
+
+Examples:
+  gt wl reopen w-abc123
+  gt wl reopen w-abc123 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlReopen,
+}
+
+func init() {
+	wlReopenCmd.Flags().BoolVar(&wlReopenForce, "force", false, "Reopen an item posted by another town")
+
+	wlCmd.AddCommand(wlReopenCmd)
+}
+
+func runWlReopen(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	townRoot, store, actor, err := openWlReview()
+	if err != nil {
+		return err
+	}
+
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+	var item *doltserver.WantedItem
+	err = commitThenNotify(store, townRoot, wantedID, actor, "reopen", "reopened", func() error {
+		var err error
+		item, err = reopenWanted(store, wantedID, actor, wlReopenForce)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlItemResultJSON{ID: wantedID, Title: item.Title, Status: doltserver.StatusOpen}, wlJSONPrettyOutput())
+	}
+	fmt.Printf("%s Reopened %s\n", wlSuccessMark(), style.Bold.Render(wantedID))
+	fmt.Printf("  Title: %s\n", item.Title)
+	fmt.Printf("  Was: %s\n", item.Status)
+	fmt.Printf("  Status: %s\n", doltserver.StatusOpen)
+	return nil
+}
+
+// reopenWanted checks that actor may reopen wantedID, then returns it to
+// the board. It returns the item as it was before reopening.
+func reopenWanted(store doltserver.WLCommonsStore, wantedID, actor string, force bool) (*doltserver.WantedItem, error) {
+	item, err := store.QueryWanted(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying wanted item: %w", err)
+	}
+	switch item.Status {
+	case doltserver.StatusInReview, doltserver.StatusCompleted, doltserver.StatusWithdrawn:
+	default:
+		return nil, fmt.Errorf("%s is %s; only items in review, completed, or withdrawn can be reopened", wantedID, item.Status)
+	}
+
+	if !force && item.PostedBy != actor {
+		return nil, fmt.Errorf("only the poster of %s (%s) may reopen it, not %s; use --force to override", wantedID, valueOrDash(item.PostedBy), actor)
+	}
+
+	if err := requireTransition(store, wantedID, item.Status, doltserver.StatusOpen); err != nil {
+		return nil, err
+	}
+	if err := store.ReopenWanted(wantedID, actor, force); err != nil {
+		return nil, fmt.Errorf("reopening: %w", err)
+	}
+	return item, nil
+}
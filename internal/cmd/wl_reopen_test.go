@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestReopenWanted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Flaky fix", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-1", "worker")
+	_ = store.SubmitCompletion("c-1", "w-1", "worker", "https://example.com/pr/1")
+
+	if _, err := reopenWanted(store, "w-1", "worker", false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("reopen by a non-poster error = %v, want a hint at --force", err)
+	}
+
+	before, err := reopenWanted(store, "w-1", "poster", false)
+	if err != nil {
+		t.Fatalf("reopenWanted() error: %v", err)
+	}
+	if before.Status != doltserver.StatusInReview {
+		t.Errorf("returned item status %q, want the pre-reopen status", before.Status)
+	}
+	detail, _ := store.QueryWantedDetail("w-1")
+	if item := detail.Item; item.Status != doltserver.StatusOpen || item.ClaimedBy != "" || item.ReopenedBy != "poster" {
+		t.Errorf("after reopen: status %q claimed by %q reopened by %q", item.Status, item.ClaimedBy, item.ReopenedBy)
+	}
+	if c := detail.Completions[0]; c.SupersededBy != doltserver.CompletionSupersededByReopen {
+		t.Errorf("completion superseded by %q, want it retired by the reopen", c.SupersededBy)
+	}
+
+	if _, err := reopenWanted(store, "w-1", "poster", false); err == nil || !strings.Contains(err.Error(), "is open") {
+		t.Errorf("reopen of an open item error = %v", err)
+	}
+}
+
+func TestReopenWanted_ForceClosedItem(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Withdrawn too soon", PostedBy: "gone-town"})
+	store.items["w-1"].Status = doltserver.StatusWithdrawn
+
+	if _, err := reopenWanted(store, "w-1", "janitor", true); err != nil {
+		t.Fatalf("forced reopenWanted() error: %v", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusOpen {
+		t.Errorf("status = %q, want open", item.Status)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// wlRetries is the --retries flag shared by gt wl subcommands. It only
+// takes effect when set, so GT_DOLT_RETRIES and the default still apply
+// otherwise.
+var wlRetries int
+
+func init() {
+	wlCmd.PersistentFlags().IntVar(&wlRetries, "retries", 0,
+		fmt.Sprintf("Retries after a network failure talking to a remote dolt server (default: $%s, else %d)",
+			doltserver.SQLRetriesEnv, doltserver.DefaultRemoteSQLRetries))
+}
+
+// selectWLSQLRetries applies --retries to every dolt SQL call the command
+// makes against a remote server.
+func selectWLSQLRetries(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("retries") {
+		doltserver.SetSQLRetries(-1)
+		return nil
+	}
+	if wlRetries < 0 {
+		return fmt.Errorf("--retries must not be negative, got %d", wlRetries)
+	}
+	doltserver.SetSQLRetries(wlRetries)
+	return nil
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlAcceptYes    bool
+	wlRejectReason string
+)
+
+var wlAcceptCmd = &cobra.Command{
+	Use:   "accept <wanted-id>",
+	Short: "Accept the completion of an item in review",
+	Long: `Accept the current completion of a wanted item in review.
+
+The completion is shown first, with its evidence, and accepted after
+confirmation (or at once with --yes). Accepting records this town as
+validated_by with a timestamp and moves the item to completed.
+
+Only the town that posted the item may accept it, and a town cannot
+accept its own completion. To accept every completion by one town, see
+gt wl approve --all-from.
+
+Examples:
+  gt wl accept w-abc123
+  gt wl accept w-abc123 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlAccept,
+}
+
+var wlRejectCmd = &cobra.Command{
+	Use:   "reject <wanted-id> --reason <text>",
+	Short: "Send an item in review back to its claimant",
+	Long: `Reject the current completion of a wanted item in review.
+
+The item goes back to claimed, still held by its claimant, who can rework
+it and resubmit with gt wl done --supersede <completion-id>. The reason is
+recorded in the item's history and shown with its comments by gt wl show,
+so the claimant knows what to fix.
+
+Only the town that posted the item may reject it.
+
+Examples:
+  gt wl reject w-abc123 --reason "tests fail on main"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlReject,
+}
+
+func init() {
+	wlAcceptCmd.Flags().BoolVarP(&wlAcceptYes, "yes", "y", false, "Accept without prompting")
+
+	wlRejectCmd.Flags().StringVar(&wlRejectReason, "reason", "", "Why the completion was rejected (required)")
+	_ = wlRejectCmd.MarkFlagRequired("reason")
+
+	wlCmd.AddCommand(wlAcceptCmd)
+	wlCmd.AddCommand(wlRejectCmd)
+}
+
+// openWlReview finds the town and opens its commons for a review command,
+// returning the store and the reviewing rig handle.
+func openWlReview() (string, doltserver.WLCommonsStore, string, error) {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return "", nil, "", err
+	}
+	return townRoot, doltserver.NewWLCommons(townRoot), wlCfg.RigHandle, nil
+}
+
+func runWlAccept(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	townRoot, store, reviewer, err := openWlReview()
+	if err != nil {
+		return err
+	}
+
+	if wlJSON && !wlAcceptYes {
+		return fmt.Errorf("--json requires --yes")
+	}
+	review, err := findReviewable(store, wantedID, reviewer)
+	if err != nil {
+		return err
+	}
+	if !wlJSON {
+		renderReviewable(os.Stdout, review)
+	}
+	if !wlAcceptYes && !promptYesNo(fmt.Sprintf("Accept %s?", wantedID)) {
+		fmt.Println("Not accepted.")
+		return nil
+	}
+
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+	err = commitThenNotify(store, townRoot, wantedID, reviewer, "accept", "accepted", func() error {
+		return acceptCompletion(store, wantedID, reviewer)
+	})
+	if err != nil {
+		return err
+	}
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlItemResultJSON{ID: wantedID, Title: review.Item.Title, Status: doltserver.StatusCompleted}, wlJSONPrettyOutput())
+	}
+	fmt.Printf("%s Accepted %s\n", wlSuccessMark(), style.Bold.Render(wantedID))
+	fmt.Printf("  Completed by: %s\n", review.Completion.CompletedBy)
+	fmt.Printf("  Status: %s\n", doltserver.StatusCompleted)
+	return nil
+}
+
+func runWlReject(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	reason := strings.TrimSpace(wlRejectReason)
+	if reason == "" {
+		return fmt.Errorf("--reason must not be empty")
+	}
+	townRoot, store, reviewer, err := openWlReview()
+	if err != nil {
+		return err
+	}
+
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+	var review *pendingApproval
+	err = commitThenNotify(store, townRoot, wantedID, reviewer, "reject", "rejected: "+reason, func() error {
+		var err error
+		review, err = rejectCompletion(store, wantedID, reviewer, reason)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlItemResultJSON{
+			ID:        wantedID,
+			Title:     review.Item.Title,
+			Status:    doltserver.StatusClaimed,
+			ClaimedBy: review.Item.ClaimedBy,
+			Reason:    reason,
+		}, wlJSONPrettyOutput())
+	}
+	fmt.Printf("%s Rejected %s\n", wlSuccessMark(), style.Bold.Render(wantedID))
+	fmt.Printf("  Returned to: %s\n", review.Completion.CompletedBy)
+	fmt.Printf("  Reason: %s\n", reason)
+	fmt.Printf("  Status: %s\n", doltserver.StatusClaimed)
+	return nil
+}
+
+// findReviewable returns wantedID and its current completion if reviewer
+// may review it: the item is in review, reviewer posted it, and the
+// completion is not reviewer's own.
+func findReviewable(store doltserver.WLCommonsStore, wantedID, reviewer string) (*pendingApproval, error) {
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying wanted item: %w", err)
+	}
+	item := detail.Item
+	if item.Status != doltserver.StatusInReview {
+		return nil, fmt.Errorf("%s is %s, not in review", wantedID, item.Status)
+	}
+	if item.PostedBy != reviewer {
+		return nil, fmt.Errorf("only the poster of %s (%s) may review it, not %s", wantedID, valueOrDash(item.PostedBy), reviewer)
+	}
+	for _, c := range detail.Completions {
+		if c.SupersededBy != "" {
+			continue
+		}
+		if c.CompletedBy == reviewer {
+			return nil, fmt.Errorf("%s cannot review its own completion of %s", reviewer, wantedID)
+		}
+		return &pendingApproval{Item: item, Completion: c}, nil
+	}
+	return nil, fmt.Errorf("%s is in review but has no current completion", wantedID)
+}
+
+// acceptCompletion accepts wantedID's current completion on behalf of its
+// poster.
+func acceptCompletion(store doltserver.WLCommonsStore, wantedID, reviewer string) error {
+	if err := requireTransition(store, wantedID, doltserver.StatusInReview, doltserver.StatusCompleted); err != nil {
+		return err
+	}
+	return store.ApproveCompletions([]string{wantedID}, reviewer)
+}
+
+// rejectCompletion checks reviewer may review wantedID, then sends it back
+// to its claimant with reason. It returns the rejected completion.
+func rejectCompletion(store doltserver.WLCommonsStore, wantedID, reviewer, reason string) (*pendingApproval, error) {
+	review, err := findReviewable(store, wantedID, reviewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTransition(store, wantedID, doltserver.StatusInReview, doltserver.StatusClaimed); err != nil {
+		return nil, err
+	}
+	if err := store.RejectCompletion(wantedID, reviewer, reason); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func renderReviewable(w io.Writer, r *pendingApproval) {
+	fmt.Fprintf(w, "%s %s\n", style.Bold.Render(r.Item.ID), r.Item.Title)
+	fmt.Fprintf(w, "  Completion: %s by %s", r.Completion.ID, r.Completion.CompletedBy)
+	if r.Completion.CompletedAt != "" {
+		fmt.Fprintf(w, " at %s", r.Completion.CompletedAt)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  Evidence:   %s\n", r.Completion.Evidence)
+}
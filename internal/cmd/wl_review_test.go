@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// newInReviewStore returns a store with w-1, posted by poster and submitted
+// for review by worker.
+func newInReviewStore(t *testing.T) *fakeWLCommonsStore {
+	t.Helper()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-1", "worker")
+	if err := store.SubmitCompletion("c-1", "w-1", "worker", "https://example.com/pr/1"); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestFindReviewable(t *testing.T) {
+	t.Parallel()
+	store := newInReviewStore(t)
+
+	review, err := findReviewable(store, "w-1", "poster")
+	if err != nil {
+		t.Fatalf("findReviewable() error: %v", err)
+	}
+	var buf bytes.Buffer
+	renderReviewable(&buf, review)
+	if out := buf.String(); !strings.Contains(out, "c-1 by worker") || !strings.Contains(out, "https://example.com/pr/1") {
+		t.Errorf("review =\n%s", out)
+	}
+
+	if _, err := findReviewable(store, "w-1", "bystander"); err == nil || !strings.Contains(err.Error(), "only the poster") {
+		t.Errorf("review by a non-poster error = %v", err)
+	}
+}
+
+func TestFindReviewable_RefusesSelfReview(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Mine", PostedBy: "poster"})
+	_ = store.ClaimWanted("w-1", "poster")
+	_ = store.SubmitCompletion("c-1", "w-1", "poster", "https://example.com")
+
+	if _, err := findReviewable(store, "w-1", "poster"); err == nil || !strings.Contains(err.Error(), "own completion") {
+		t.Errorf("self-review error = %v", err)
+	}
+}
+
+func TestAcceptCompletion(t *testing.T) {
+	t.Parallel()
+	store := newInReviewStore(t)
+
+	if err := acceptCompletion(store, "w-1", "poster"); err != nil {
+		t.Fatalf("acceptCompletion() error: %v", err)
+	}
+	detail, _ := store.QueryWantedDetail("w-1")
+	if detail.Item.Status != doltserver.StatusCompleted || detail.Completions[0].ValidatedBy != "poster" {
+		t.Errorf("after accept: status %q validated by %q", detail.Item.Status, detail.Completions[0].ValidatedBy)
+	}
+	if _, err := findReviewable(store, "w-1", "poster"); err == nil {
+		t.Error("a completed item should no longer be reviewable")
+	}
+}
+
+func TestRejectCompletion(t *testing.T) {
+	t.Parallel()
+	store := newInReviewStore(t)
+
+	review, err := rejectCompletion(store, "w-1", "poster", "tests fail")
+	if err != nil {
+		t.Fatalf("rejectCompletion() error: %v", err)
+	}
+	if review.Completion.CompletedBy != "worker" {
+		t.Errorf("rejected completion by %q, want worker", review.Completion.CompletedBy)
+	}
+	detail, _ := store.QueryWantedDetail("w-1")
+	if detail.Item.Status != doltserver.StatusClaimed || detail.Item.ClaimedBy != "worker" {
+		t.Errorf("after reject: status %q claimed by %q", detail.Item.Status, detail.Item.ClaimedBy)
+	}
+	if len(detail.Comments) != 1 || !strings.Contains(detail.Comments[0].Body, "tests fail") {
+		t.Errorf("comments = %+v, want the reason", detail.Comments)
+	}
+
+	if _, err := rejectCompletion(store, "w-1", "poster", "again"); err == nil {
+		t.Error("rejecting an item no longer in review should fail")
+	}
+}
+
+func TestRejectThenPlainDoneRequiresSupersede(t *testing.T) {
+	t.Parallel()
+	store := newInReviewStore(t)
+	if _, err := rejectCompletion(store, "w-1", "poster", "tests fail"); err != nil {
+		t.Fatalf("rejectCompletion() error: %v", err)
+	}
+
+	err := submitDone(store, "w-1", "worker", "https://example.com/pr/2", "c-2")
+	if err == nil || !strings.Contains(err.Error(), "--supersede c-1") {
+		t.Fatalf("submitDone() after reject error = %v, want a --supersede c-1 hint", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusClaimed {
+		t.Errorf("Status = %q, want the item left claimed", item.Status)
+	}
+
+	if err := resubmitDone(store, "w-1", "worker", "https://example.com/pr/2", "c-2", "c-1", false); err != nil {
+		t.Fatalf("resubmitDone() error: %v", err)
+	}
+}
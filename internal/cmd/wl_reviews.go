@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlReviewsAllTowns bool
+
+var wlReviewsCmd = &cobra.Command{
+	Use:   "reviews",
+	Short: "List completions awaiting this town's review",
+	Long: `List the items this town posted that are in review, each with the
+completion awaiting acceptance and its evidence, oldest completion first.
+Work through the list with gt wl accept and gt wl reject, or approve a
+trusted town's batch with gt wl approve.
+
+--all-towns lists every in-review item on the board, whoever posted it,
+for operators auditing the review queue.
+
+Examples:
+  gt wl reviews
+  gt wl reviews --all-towns
+  gt wl reviews --json`,
+	Args: cobra.NoArgs,
+	RunE: runWlReviews,
+}
+
+func init() {
+	wlReviewsCmd.Flags().BoolVar(&wlReviewsAllTowns, "all-towns", false, "List in-review items posted by any town")
+
+	wlCmd.AddCommand(wlReviewsCmd)
+}
+
+func runWlReviews(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	postedBy := wlCfg.RigHandle
+	if wlReviewsAllTowns {
+		postedBy = ""
+	}
+	store := doltserver.NewWLCommons(townRoot)
+	reviews, err := store.QueryPendingReviews(postedBy)
+	if err != nil {
+		return err
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, buildReviewsJSON(reviews), wlJSONPrettyOutput())
+	}
+	renderReviews(os.Stdout, reviews, postedBy)
+	return nil
+}
+
+// wlReviewsJSON is the --json result of gt wl reviews.
+type wlReviewsJSON struct {
+	Count   int            `json:"count"`
+	Reviews []wlReviewJSON `json:"reviews"`
+}
+
+// wlReviewJSON is one in-review item of wlReviewsJSON.
+type wlReviewJSON struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Priority     int    `json:"priority"`
+	PostedBy     string `json:"posted_by"`
+	CompletionID string `json:"completion_id"`
+	CompletedBy  string `json:"completed_by"`
+	CompletedAt  string `json:"completed_at"`
+	Evidence     string `json:"evidence"`
+}
+
+func buildReviewsJSON(reviews []doltserver.PendingReview) wlReviewsJSON {
+	out := wlReviewsJSON{Count: len(reviews), Reviews: make([]wlReviewJSON, 0, len(reviews))}
+	for _, r := range reviews {
+		out.Reviews = append(out.Reviews, wlReviewJSON{
+			ID:           r.WantedID,
+			Title:        r.Title,
+			Priority:     r.Priority,
+			PostedBy:     r.PostedBy,
+			CompletionID: r.Completion.ID,
+			CompletedBy:  r.Completion.CompletedBy,
+			CompletedAt:  r.Completion.CompletedAt,
+			Evidence:     r.Completion.Evidence,
+		})
+	}
+	return out
+}
+
+// renderReviews prints reviews as a worklist. postedBy is the town whose
+// items they are, or empty for the whole board.
+func renderReviews(w io.Writer, reviews []doltserver.PendingReview, postedBy string) {
+	if len(reviews) == 0 {
+		if postedBy == "" {
+			fmt.Fprintln(w, "No completions are awaiting review.")
+		} else {
+			fmt.Fprintf(w, "No completions are awaiting review by %s.\n", postedBy)
+		}
+		return
+	}
+
+	heading := fmt.Sprintf("Awaiting review (%d):", len(reviews))
+	if postedBy != "" {
+		heading = fmt.Sprintf("Awaiting review by %s (%d):", postedBy, len(reviews))
+	}
+	fmt.Fprintf(w, "%s\n", style.Bold.Render(heading))
+	for _, r := range reviews {
+		fmt.Fprintf(w, "  %s  %s %s\n", r.WantedID, wlFormatPriority(fmt.Sprint(r.Priority)), r.Title)
+		by := "by " + r.Completion.CompletedBy
+		if r.Completion.CompletedAt != "" {
+			by += ", " + r.Completion.CompletedAt
+		}
+		if postedBy == "" {
+			by += "; posted by " + valueOrDash(r.PostedBy)
+		}
+		fmt.Fprintf(w, "    %s\n", style.Dim.Render(r.Completion.ID+" "+by))
+		fmt.Fprintf(w, "    %s\n", style.Dim.Render("evidence: "+r.Completion.Evidence))
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestQueryPendingReviews_FiltersByPoster(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	for _, tc := range []struct{ id, postedBy string }{
+		{"w-1", "poster"},
+		{"w-2", "someone-else"},
+		{"w-3", "poster"},
+	} {
+		_ = store.InsertWanted(&doltserver.WantedItem{ID: tc.id, Title: tc.id, PostedBy: tc.postedBy})
+		if err := store.ClaimWanted(tc.id, "worker"); err != nil {
+			t.Fatalf("ClaimWanted(%s) error: %v", tc.id, err)
+		}
+		if err := store.SubmitCompletion("c-"+tc.id, tc.id, "worker", "https://example.com/"+tc.id); err != nil {
+			t.Fatalf("SubmitCompletion(%s) error: %v", tc.id, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		postedBy string
+		want     []string
+	}{
+		{"poster", []string{"w-1", "w-3"}},
+		{"", []string{"w-1", "w-2", "w-3"}},
+	} {
+		reviews, err := store.QueryPendingReviews(tc.postedBy)
+		if err != nil {
+			t.Fatalf("QueryPendingReviews(%q) error: %v", tc.postedBy, err)
+		}
+		var ids []string
+		for _, r := range reviews {
+			ids = append(ids, r.WantedID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("QueryPendingReviews(%q) = %v, want %v", tc.postedBy, ids, tc.want)
+		}
+	}
+}
+
+func TestRenderReviews(t *testing.T) {
+	t.Parallel()
+	reviews := []doltserver.PendingReview{{
+		WantedID: "w-1",
+		Title:    "Fix the flux capacitor",
+		Priority: 1,
+		PostedBy: "poster",
+		Completion: doltserver.WantedCompletion{
+			ID:          "c-1",
+			CompletedBy: "worker",
+			Evidence:    "https://example.com/pr/1",
+			CompletedAt: "2026-10-01 12:00:00",
+		},
+	}}
+
+	var buf bytes.Buffer
+	renderReviews(&buf, reviews, "poster")
+	got := buf.String()
+	for _, want := range []string{"Awaiting review by poster (1)", "w-1", "Fix the flux capacitor", "c-1 by worker, 2026-10-01 12:00:00", "evidence: https://example.com/pr/1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderReviews() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "posted by") {
+		t.Errorf("renderReviews() for one town names the poster:\n%s", got)
+	}
+
+	buf.Reset()
+	renderReviews(&buf, reviews, "")
+	if !strings.Contains(buf.String(), "posted by poster") {
+		t.Errorf("renderReviews() for all towns missing the poster:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	renderReviews(&buf, nil, "poster")
+	if !strings.Contains(buf.String(), "No completions are awaiting review by poster") {
+		t.Errorf("renderReviews(nil) = %q", buf.String())
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonSchemaDialect is the JSON Schema draft gt wl schema emits.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// wlJSONResultTypes maps each gt wl subcommand with a structured --json
+// result to the Go type it encodes. gt wl browse --json is absent: it
+// passes dolt's rows through unchanged. gt wl status with a wanted ID
+// writes the "status-item" result, and gt wl watch --json writes one
+// "watch" object per line.
+var wlJSONResultTypes = map[string]reflect.Type{
+	"accept":      reflect.TypeOf(wlItemResultJSON{}),
+	"assign":      reflect.TypeOf(wlItemResultJSON{}),
+	"claim":       reflect.TypeOf(claimTemplateData{}),
+	"done":        reflect.TypeOf(wlDoneJSON{}),
+	"history":     reflect.TypeOf(wlHistoryJSON{}),
+	"list":        reflect.TypeOf(wlListJSON{}),
+	"mine":        reflect.TypeOf(wlListJSON{}),
+	"search":      reflect.TypeOf(wlListJSON{}),
+	"post":        reflect.TypeOf(wlItemResultJSON{}),
+	"reject":      reflect.TypeOf(wlItemResultJSON{}),
+	"reopen":      reflect.TypeOf(wlItemResultJSON{}),
+	"reviews":     reflect.TypeOf(wlReviewsJSON{}),
+	"show":        reflect.TypeOf(wantedShowJSON{}),
+	"status":      reflect.TypeOf(wlBoardStatus{}),
+	"status-item": reflect.TypeOf(wlItemStatusJSON{}),
+	"unclaim":     reflect.TypeOf(wlItemResultJSON{}),
+	"watch":       reflect.TypeOf(wlWatchEvent{}),
+	"whois":       reflect.TypeOf(rigProfileJSON{}),
+}
+
+var wlSchemaCmd = &cobra.Command{
+	Use:   "schema [command]",
+	Short: "Print the JSON Schema of gt wl --json output",
+	Long: `Print the JSON Schema (draft 2020-12) of the object a gt wl subcommand
+writes with --json, so integrations can validate what they parse.
+
+The schema is generated from the result types themselves and cannot drift
+from the output. With a command, prints that command's schema; without
+one, prints an object mapping every command to its schema.
+
+Commands: ` + strings.Join(wlJSONResultCommands(), ", ") + `
+
+Examples:
+  gt wl schema claim
+  gt wl schema --pretty`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlSchema,
+}
+
+func init() {
+	wlCmd.AddCommand(wlSchemaCmd)
+}
+
+func runWlSchema(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		schema, err := wlResultSchema(args[0])
+		if err != nil {
+			return err
+		}
+		return writeWLJSON(os.Stdout, schema, wlJSONPrettyOutput())
+	}
+	all := make(map[string]any, len(wlJSONResultTypes))
+	for _, name := range wlJSONResultCommands() {
+		all[name], _ = wlResultSchema(name)
+	}
+	return writeWLJSON(os.Stdout, all, wlJSONPrettyOutput())
+}
+
+// wlJSONResultCommands lists the commands wl schema describes, sorted.
+func wlJSONResultCommands() []string {
+	names := make([]string, 0, len(wlJSONResultTypes))
+	for name := range wlJSONResultTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// wlResultSchema returns the root schema of command's --json result.
+func wlResultSchema(command string) (map[string]any, error) {
+	t, ok := wlJSONResultTypes[command]
+	if !ok {
+		return nil, fmt.Errorf("no JSON schema for %q (have: %s)", command, strings.Join(wlJSONResultCommands(), ", "))
+	}
+	schema := jsonSchemaFor(t)
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = "gt wl " + command + " --json"
+	return schema, nil
+}
+
+// jsonSchemaFor describes how encoding/json encodes a value of type t.
+// Struct fields follow their json tags: "-" is skipped and omitempty
+// fields are not required. Result types build non-nil slices and maps, so
+// those are never null.
+func jsonSchemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchemaFor(t.Elem())}
+	case reflect.Struct:
+		return jsonSchemaForStruct(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+func jsonSchemaForStruct(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = jsonSchemaFor(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// checkSchemaNode asserts node is a well-formed schema of the subset
+// jsonSchemaFor emits, recursing into items and properties.
+func checkSchemaNode(t *testing.T, path string, node map[string]any) {
+	t.Helper()
+	if len(node) == 0 {
+		return
+	}
+	switch typ := node["type"]; typ {
+	case "string", "boolean", "integer", "number":
+	case "array":
+		items, ok := node["items"].(map[string]any)
+		if !ok {
+			t.Fatalf("%s: array without an items schema", path)
+		}
+		checkSchemaNode(t, path+"[]", items)
+	case "object":
+		if extra, ok := node["additionalProperties"].(map[string]any); ok {
+			checkSchemaNode(t, path+"{}", extra)
+			return
+		}
+		props, ok := node["properties"].(map[string]any)
+		if !ok {
+			t.Fatalf("%s: object without properties", path)
+		}
+		required, ok := node["required"].([]any)
+		if !ok {
+			t.Fatalf("%s: required is %T, want an array", path, node["required"])
+		}
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok || props[name] == nil {
+				t.Errorf("%s: required %v is not a property", path, r)
+			}
+		}
+		for name, p := range props {
+			sub, ok := p.(map[string]any)
+			if !ok {
+				t.Fatalf("%s.%s: property schema is %T", path, name, p)
+			}
+			checkSchemaNode(t, path+"."+name, sub)
+		}
+	default:
+		t.Fatalf("%s: unexpected type %v", path, typ)
+	}
+}
+
+func TestWlResultSchema_ValidJSONSchema(t *testing.T) {
+	t.Parallel()
+	for _, name := range wlJSONResultCommands() {
+		schema, err := wlResultSchema(name)
+		if err != nil {
+			t.Fatalf("wlResultSchema(%q) error: %v", name, err)
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", name, err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s: schema is not valid JSON: %v", name, err)
+		}
+		if decoded["$schema"] != jsonSchemaDialect {
+			t.Errorf("%s: $schema = %v", name, decoded["$schema"])
+		}
+		if decoded["type"] != "object" {
+			t.Errorf("%s: root type = %v, want object", name, decoded["type"])
+		}
+		checkSchemaNode(t, name, decoded)
+	}
+}
+
+// TestWlResultSchema_MatchesOutput encodes real results and checks their
+// top-level keys are exactly the schema's properties.
+func TestWlResultSchema_MatchesOutput(t *testing.T) {
+	t.Parallel()
+	outputs := map[string]any{
+		"claim": newClaimTemplateData(&claimResult{Item: &doltserver.WantedItem{ID: "w-1", Title: "x"}, ClaimedBy: "rig", AlreadyClaimed: true}, "rig"),
+		"show": buildWantedShowJSON(&doltserver.WantedDetail{Item: &doltserver.WantedItem{
+			ID: "w-1", CreatedAt: time.Now(), UpdatedAt: time.Now(), ExpiresAt: time.Now(),
+			ReopenedBy: "poster", ReopenedAt: time.Now(), Assignee: "polecat-1",
+		}}),
+	}
+	for name, out := range outputs {
+		schema, _ := wlResultSchema(name)
+		data, _ := json.Marshal(out)
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		var got, want []string
+		for k := range decoded {
+			got = append(got, k)
+		}
+		for k := range schema["properties"].(map[string]any) {
+			want = append(want, k)
+		}
+		slices.Sort(got)
+		slices.Sort(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: output keys %v, schema properties %v", name, got, want)
+		}
+	}
+}
+
+func TestJSONSchemaFor_OmitEmptyNotRequired(t *testing.T) {
+	t.Parallel()
+	schema := jsonSchemaFor(reflect.TypeOf(wlSyncState{}))
+	required := schema["required"].([]string)
+	if slices.Contains(required, "error") {
+		t.Errorf("required = %v, omitempty field error should be optional", required)
+	}
+	if !slices.Contains(required, "state") {
+		t.Errorf("required = %v, want state", required)
+	}
+	if _, err := wlResultSchema("browse"); err == nil {
+		t.Error("wlResultSchema(browse) should fail: browse emits raw dolt rows")
+	}
+}
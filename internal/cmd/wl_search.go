@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+var (
+	wlSearchStatus string
+	wlSearchLimit  int
+)
+
+var wlSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search wanted items by keyword",
+	Long: `Find wanted items whose title or description contains the query.
+
+Matching is case-insensitive and literal: the query is a plain substring,
+so characters such as % and _ match themselves rather than acting as
+wildcards. Several arguments are joined with spaces into one phrase.
+
+Results are listed most urgent first, like gt wl list. --status narrows
+the search to one status; --limit caps the rows shown.
+
+Examples:
+  gt wl search parser
+  gt wl search "rate limit" --status open
+  gt wl search 100% --limit 5 --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runWlSearch,
+}
+
+func init() {
+	wlSearchCmd.Flags().StringVar(&wlSearchStatus, "status", "", "Only search items with this status (e.g. open, claimed, in_review)")
+	wlSearchCmd.Flags().IntVar(&wlSearchLimit, "limit", 50, "Maximum items to list")
+
+	wlCmd.AddCommand(wlSearchCmd)
+}
+
+func runWlSearch(cmd *cobra.Command, args []string) error {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return fmt.Errorf("search query must not be empty")
+	}
+	if wlSearchLimit < 1 {
+		return fmt.Errorf("--limit must be >= 1, got %d", wlSearchLimit)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	items, err := searchWanted(store, query, wlSearchStatus, wlSearchLimit)
+	if err != nil {
+		return err
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, buildWantedListJSON(items), wlJSONPrettyOutput())
+	}
+	if len(items) == 0 {
+		fmt.Printf("No wanted items match %q.\n", query)
+		return nil
+	}
+	renderWantedList(os.Stdout, items, wlSearchStatus)
+	return nil
+}
+
+// searchWanted returns up to limit items whose title or description
+// contains query, ignoring case, optionally only those in status.
+func searchWanted(store doltserver.WLCommonsStore, query, status string, limit int) ([]*doltserver.WantedItem, error) {
+	return listWanted(store, doltserver.WantedFilter{
+		Status:      status,
+		Search:      query,
+		MinPriority: -1,
+		MaxPriority: -1,
+		Limit:       limit,
+	})
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestSearchWanted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix the Parser", Priority: 2})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-2", Title: "Docs", Description: "explain the parser flags", Priority: 1})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-3", Title: "Cut costs by 50%", Priority: 3})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-4", Title: "Cut costs by 500", Priority: 3})
+	_ = store.ClaimWanted("w-1", "my-rig")
+
+	ids := func(items []*doltserver.WantedItem) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	items, err := searchWanted(store, "PARSER", "", 50)
+	if err != nil {
+		t.Fatalf("searchWanted() error: %v", err)
+	}
+	if got := ids(items); len(got) != 2 || got[0] != "w-2" || got[1] != "w-1" {
+		t.Errorf("case-insensitive title/description search = %v, want [w-2 w-1]", got)
+	}
+
+	if items, _ := searchWanted(store, "parser", doltserver.StatusClaimed, 50); len(items) != 1 || items[0].ID != "w-1" {
+		t.Errorf("--status claimed search = %v, want [w-1]", ids(items))
+	}
+	if items, _ := searchWanted(store, "50%", "", 50); len(items) != 1 || items[0].ID != "w-3" {
+		t.Errorf("%% must match literally, got %v, want [w-3]", ids(items))
+	}
+	if items, _ := searchWanted(store, "parser", "", 1); len(items) != 1 {
+		t.Errorf("--limit 1 returned %d items", len(items))
+	}
+	if _, err := searchWanted(store, "parser", "bogus", 50); err == nil {
+		t.Error("searchWanted() with an unknown status should fail")
+	}
+}
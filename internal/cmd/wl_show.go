@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlShowDiffSince string
+)
+
+var wlShowCmd = &cobra.Command{
+	Use:   "show <wanted-id>",
+	Short: "Show a wanted item with its dependencies, comments, and completions",
+	Long: `Show a single wanted item from the local wl-commons database.
+
+The default output is a flat, human-readable summary. With --json, the item
+and its related rows are emitted as one object (indented on a terminal,
+single-line otherwise; override with --pretty or --compact):
+
+  {
+    "id": "w-abc123",
+    "title": "...",
+    "description": "...",
+    "project": "...",
+    "type": "...",
+    "priority": 1,
+    "status": "claimed",
+    "effort_level": "medium",
+    "timeout_action": "reopen",
+    "posted_by": "rig-a",
+    "claimed_by": "rig-b",
+    "claimed_via": "",
+    "claimed_group": "",
+    "tags": ["go", "auth"],
+    "watchers": 2,
+    "completion_count": 1,
+    "disputed": false,
+    "merged_into": "",
+    "labels": {"ticket": "OPS-142"},
+    "dependencies": [{"id": "w-def456", "title": "...", "status": "open"}],
+    "comments": [{"author": "rig-b", "body": "...", "created_at": "..."}],
+    "completions": [{"id": "c-...", "completed_by": "rig-b",
+                     "evidence": "...", "completed_at": "...", "validated_by": "",
+                     "superseded_by": ""}]
+  }
+
+Array fields are always present ([] when empty), as is labels ({}).
+
+--diff-since <commit> instead shows how the item's row changed since a Dolt
+commit (a hash, branch, or ancestry such as HEAD~3), using AS OF time
+travel: each changed field with its old and new value. If the item did not
+exist at that commit, every field is listed as new.
+
+An item is disputed when it has received more completions than the
+wasteland's review.dispute_threshold setting (default 2) allows.
+
+Examples:
+  gt wl show w-abc123
+  gt wl show w-abc123 --json | jq '.completions[0].evidence'
+  gt wl show w-abc123 --diff-since HEAD~5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlShow,
+}
+
+func init() {
+	wlShowCmd.Flags().StringVar(&wlShowDiffSince, "diff-since", "", "Show field changes since a Dolt commit")
+
+	wlCmd.AddCommand(wlShowCmd)
+}
+
+func runWlShow(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	if wlJSON && wlShowDiffSince != "" {
+		return fmt.Errorf("--json cannot be combined with --diff-since")
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	if wlShowDiffSince != "" {
+		return showWantedDiff(os.Stdout, store, wantedID, wlShowDiffSince)
+	}
+	detail, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return fmt.Errorf("querying wasteland settings: %w", err)
+	}
+	threshold := disputeThreshold(settings)
+
+	if wlJSON {
+		out := buildWantedShowJSON(detail)
+		out.Disputed = isDisputed(detail.Item.CompletionCount, threshold)
+		return writeWLJSON(os.Stdout, out, wlJSONPrettyOutput())
+	}
+
+	renderWantedShow(detail, threshold)
+	return nil
+}
+
+// wlSettingDisputeThreshold is the wasteland setting holding the number of
+// completions an item may receive before it is flagged as disputed.
+const wlSettingDisputeThreshold = "review.dispute_threshold"
+
+// defaultDisputeThreshold applies when the wasteland does not set
+// review.dispute_threshold: a first completion plus one resubmission.
+const defaultDisputeThreshold = 2
+
+// disputeThreshold reads review.dispute_threshold, falling back to the
+// default when it is unset or malformed.
+func disputeThreshold(settings map[string]string) int {
+	if n, ok := settingInt(settings, wlSettingDisputeThreshold); ok && n >= 0 {
+		return n
+	}
+	return defaultDisputeThreshold
+}
+
+// isDisputed reports whether completionCount exceeds the dispute threshold.
+func isDisputed(completionCount, threshold int) bool {
+	return completionCount > threshold
+}
+
+// wantedShowJSON is the nested JSON shape of gt wl show --json.
+type wantedShowJSON struct {
+	ID            string                 `json:"id"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Project       string                 `json:"project"`
+	Type          string                 `json:"type"`
+	Priority      int                    `json:"priority"`
+	Status        string                 `json:"status"`
+	EffortLevel   string                 `json:"effort_level"`
+	TimeoutAction string                 `json:"timeout_action"`
+	PostedBy      string                 `json:"posted_by"`
+	ClaimedBy     string                 `json:"claimed_by"`
+	ClaimedVia    string                 `json:"claimed_via"`
+	ClaimedGroup  string                 `json:"claimed_group"`
+	Assignee      string                 `json:"assignee,omitempty"`
+	Tags          []string               `json:"tags"`
+	Watchers      int                    `json:"watchers"`
+	Completed     int                    `json:"completion_count"`
+	Disputed      bool                   `json:"disputed"`
+	MergedInto    string                 `json:"merged_into"`
+	EvidenceURL   string                 `json:"evidence_url"`
+	CreatedAt     string                 `json:"created_at,omitempty"`
+	UpdatedAt     string                 `json:"updated_at,omitempty"`
+	ExpiresAt     string                 `json:"expires_at,omitempty"`
+	ReopenedBy    string                 `json:"reopened_by,omitempty"`
+	ReopenedAt    string                 `json:"reopened_at,omitempty"`
+	Labels        map[string]string      `json:"labels"`
+	Dependencies  []wantedShowDependency `json:"dependencies"`
+	Comments      []wantedShowComment    `json:"comments"`
+	Completions   []wantedShowCompletion `json:"completions"`
+}
+
+type wantedShowDependency struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type wantedShowComment struct {
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+}
+
+type wantedShowCompletion struct {
+	ID           string `json:"id"`
+	CompletedBy  string `json:"completed_by"`
+	Evidence     string `json:"evidence"`
+	CompletedAt  string `json:"completed_at"`
+	ValidatedBy  string `json:"validated_by"`
+	SupersededBy string `json:"superseded_by"`
+}
+
+// buildWantedShowJSON converts a WantedDetail to its JSON shape. Slices are
+// non-nil so empty relations encode as [] rather than null.
+func buildWantedShowJSON(d *doltserver.WantedDetail) *wantedShowJSON {
+	item := d.Item
+	out := &wantedShowJSON{
+		ID:            item.ID,
+		Title:         item.Title,
+		Description:   item.Description,
+		Project:       item.Project,
+		Type:          item.Type,
+		Priority:      item.Priority,
+		Status:        item.Status,
+		EffortLevel:   item.EffortLevel,
+		TimeoutAction: timeoutActionOrDefault(item.TimeoutAction),
+		PostedBy:      item.PostedBy,
+		ClaimedBy:     item.ClaimedBy,
+		ClaimedVia:    item.ClaimedVia,
+		ClaimedGroup:  item.ClaimedGroup,
+		Assignee:      item.Assignee,
+		Tags:          append([]string{}, item.Tags...),
+		Watchers:      d.WatcherCount,
+		Completed:     item.CompletionCount,
+		MergedInto:    item.MergedInto,
+		EvidenceURL:   item.EvidenceURL,
+		CreatedAt:     formatShowTime(item.CreatedAt),
+		UpdatedAt:     formatShowTime(item.UpdatedAt),
+		ExpiresAt:     formatShowTime(item.ExpiresAt),
+		ReopenedBy:    item.ReopenedBy,
+		ReopenedAt:    formatShowTime(item.ReopenedAt),
+		Labels:        make(map[string]string, len(d.Labels)),
+		Dependencies:  []wantedShowDependency{},
+		Comments:      []wantedShowComment{},
+		Completions:   []wantedShowCompletion{},
+	}
+	for _, l := range d.Labels {
+		out.Labels[l.Key] = l.Value
+	}
+	for _, dep := range d.Dependencies {
+		out.Dependencies = append(out.Dependencies, wantedShowDependency{ID: dep.ID, Title: dep.Title, Status: dep.Status})
+	}
+	for _, c := range d.Comments {
+		out.Comments = append(out.Comments, wantedShowComment{Author: c.Author, Body: c.Body, CreatedAt: c.CreatedAt})
+	}
+	for _, c := range d.Completions {
+		out.Completions = append(out.Completions, wantedShowCompletion{
+			ID:           c.ID,
+			CompletedBy:  c.CompletedBy,
+			Evidence:     c.Evidence,
+			CompletedAt:  c.CompletedAt,
+			ValidatedBy:  c.ValidatedBy,
+			SupersededBy: c.SupersededBy,
+		})
+	}
+	return out
+}
+
+func renderWantedShow(d *doltserver.WantedDetail, threshold int) {
+	item := d.Item
+	fmt.Printf("%s %s\n", style.Bold.Render(item.ID), item.Title)
+	fmt.Printf("  Status:   %s\n", item.Status)
+	if item.MergedInto != "" {
+		fmt.Printf("  Merged into: %s\n", item.MergedInto)
+	}
+	fmt.Printf("  Priority: %s\n", wlFormatPriority(fmt.Sprint(item.Priority)))
+	if item.Project != "" {
+		fmt.Printf("  Project:  %s\n", item.Project)
+	}
+	if item.Type != "" {
+		fmt.Printf("  Type:     %s\n", item.Type)
+	}
+	if item.EffortLevel != "" {
+		fmt.Printf("  Effort:   %s\n", item.EffortLevel)
+	}
+	if item.TimeoutAction != "" && item.TimeoutAction != doltserver.TimeoutActionReopen {
+		fmt.Printf("  On claim timeout: %s\n", item.TimeoutAction)
+	}
+	if item.PostedBy != "" {
+		line := "  Posted by: " + item.PostedBy
+		if !item.CreatedAt.IsZero() {
+			line += " on " + item.CreatedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Println(line)
+	}
+	if !item.UpdatedAt.IsZero() {
+		fmt.Printf("  Updated:  %s\n", item.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if item.ReopenedBy != "" {
+		line := "  Reopened by: " + item.ReopenedBy
+		if !item.ReopenedAt.IsZero() {
+			line += " on " + item.ReopenedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Println(line)
+	}
+	if item.ClaimedBy != "" {
+		switch {
+		case item.ClaimedGroup != "":
+			fmt.Printf("  Claimed by: %s (for group %s)\n", item.ClaimedBy, item.ClaimedGroup)
+		case item.ClaimedVia != "":
+			fmt.Printf("  Claimed by: %s (via %s)\n", item.ClaimedBy, item.ClaimedVia)
+		default:
+			fmt.Printf("  Claimed by: %s\n", item.ClaimedBy)
+		}
+		if item.Assignee != "" {
+			fmt.Printf("  Assignee: %s\n", item.Assignee)
+		}
+		if !item.ExpiresAt.IsZero() {
+			fmt.Printf("  Lease expires: %s\n", item.ExpiresAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+	if item.EvidenceURL != "" {
+		fmt.Printf("  Evidence: %s\n", item.EvidenceURL)
+	}
+	if len(item.Tags) > 0 {
+		fmt.Printf("  Tags:     %s\n", strings.Join(item.Tags, ", "))
+	}
+	if d.WatcherCount > 0 {
+		fmt.Printf("  Watchers: %d\n", d.WatcherCount)
+	}
+	if item.CompletionCount > 0 {
+		line := fmt.Sprintf("  Completions submitted: %d", item.CompletionCount)
+		if isDisputed(item.CompletionCount, threshold) {
+			line += " " + style.Warning.Render("(disputed)")
+		}
+		fmt.Println(line)
+	}
+	if len(d.Labels) > 0 {
+		pairs := make([]string, len(d.Labels))
+		for i, l := range d.Labels {
+			pairs[i] = l.Key + "=" + l.Value
+		}
+		fmt.Printf("  Labels:   %s\n", strings.Join(pairs, ", "))
+	}
+	if len(d.Dependencies) > 0 {
+		fmt.Printf("  Depends on: %s\n", formatBlockers(d.Dependencies))
+	}
+	if item.Description != "" {
+		fmt.Printf("\n%s\n", item.Description)
+	}
+	if len(d.Completions) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Completions:"))
+		for _, c := range d.Completions {
+			line := fmt.Sprintf("  %s by %s: %s", c.ID, c.CompletedBy, c.Evidence)
+			if c.CompletedAt != "" {
+				line += " " + style.Dim.Render("("+c.CompletedAt+")")
+			}
+			if c.ValidatedBy != "" {
+				line += " " + style.Success.Render("accepted by "+c.ValidatedBy)
+			}
+			if c.SupersededBy != "" {
+				line = style.Dim.Render(line + " (superseded by " + c.SupersededBy + ")")
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(d.Comments) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Comments:"))
+		for _, c := range d.Comments {
+			fmt.Printf("  %s %s: %s\n", style.Dim.Render(c.CreatedAt), c.Author, c.Body)
+		}
+	}
+}
+
+// formatShowTime renders t as RFC 3339 in UTC for --json, or "" when unset.
+func formatShowTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// timeoutActionOrDefault maps an unset timeout action to the reopen default.
+func timeoutActionOrDefault(action string) string {
+	if action == "" {
+		return doltserver.TimeoutActionReopen
+	}
+	return action
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// showWantedDiff renders how wantedID changed since commit.
+func showWantedDiff(w io.Writer, store doltserver.WLCommonsStore, wantedID, commit string) error {
+	cur, err := store.QueryWantedDetail(wantedID)
+	if err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+	old, err := store.QueryWantedAsOf(wantedID, commit)
+	if err != nil {
+		return err
+	}
+	renderWantedDiff(w, commit, old, cur.Item)
+	return nil
+}
+
+// wantedFieldChange is one field of a wanted item that differs between two
+// versions of its row.
+type wantedFieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// wantedDiffFields lists the wanted row fields gt wl show --diff-since
+// compares, in display order.
+var wantedDiffFields = []struct {
+	name  string
+	value func(*doltserver.WantedItem) string
+}{
+	{"title", func(i *doltserver.WantedItem) string { return i.Title }},
+	{"description", func(i *doltserver.WantedItem) string { return i.Description }},
+	{"project", func(i *doltserver.WantedItem) string { return i.Project }},
+	{"type", func(i *doltserver.WantedItem) string { return i.Type }},
+	{"priority", func(i *doltserver.WantedItem) string { return strconv.Itoa(i.Priority) }},
+	{"status", func(i *doltserver.WantedItem) string { return i.Status }},
+	{"effort_level", func(i *doltserver.WantedItem) string { return i.EffortLevel }},
+	{"timeout_action", func(i *doltserver.WantedItem) string { return i.TimeoutAction }},
+	{"tags", func(i *doltserver.WantedItem) string { return strings.Join(i.Tags, ", ") }},
+	{"posted_by", func(i *doltserver.WantedItem) string { return i.PostedBy }},
+	{"claimed_by", func(i *doltserver.WantedItem) string { return i.ClaimedBy }},
+	{"claimed_via", func(i *doltserver.WantedItem) string { return i.ClaimedVia }},
+	{"claimed_group", func(i *doltserver.WantedItem) string { return i.ClaimedGroup }},
+	{"completion_count", func(i *doltserver.WantedItem) string { return strconv.Itoa(i.CompletionCount) }},
+	{"merged_into", func(i *doltserver.WantedItem) string { return i.MergedInto }},
+}
+
+// diffWantedItems returns the fields that differ between old and cur, in
+// wantedDiffFields order. A nil old (the item did not exist yet) compares
+// every field against empty values.
+func diffWantedItems(old, cur *doltserver.WantedItem) []wantedFieldChange {
+	if old == nil {
+		old = &doltserver.WantedItem{}
+	}
+	var changes []wantedFieldChange
+	for _, f := range wantedDiffFields {
+		o, n := f.value(old), f.value(cur)
+		if o != n {
+			changes = append(changes, wantedFieldChange{Field: f.name, Old: o, New: n})
+		}
+	}
+	return changes
+}
+
+// renderWantedDiff prints how cur changed since commit, where old is its
+// state at that commit (nil if it did not exist then).
+func renderWantedDiff(w io.Writer, commit string, old, cur *doltserver.WantedItem) {
+	changes := diffWantedItems(old, cur)
+	if old == nil {
+		fmt.Fprintf(w, "%s did not exist at %s; every field is new.\n", cur.ID, commit)
+	} else {
+		fmt.Fprintf(w, "%s changes since %s:\n", style.Bold.Render(cur.ID), commit)
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "  (no changes)")
+		return
+	}
+
+	width := 0
+	for _, c := range changes {
+		width = max(width, len(c.Field))
+	}
+	for _, c := range changes {
+		if old == nil {
+			fmt.Fprintf(w, "  %-*s  %s\n", width+1, c.Field+":", wlDiffValue(c.New))
+			continue
+		}
+		fmt.Fprintf(w, "  %-*s  %s → %s\n", width+1, c.Field+":", wlDiffValue(c.Old), wlDiffValue(c.New))
+	}
+	if unchanged := len(wantedDiffFields) - len(changes); old != nil && unchanged > 0 {
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render(fmt.Sprintf("%d other field(s) unchanged", unchanged)))
+	}
+}
+
+// wlDiffValue renders one side of a field change, marking empty values and
+// keeping multi-line descriptions on one line.
+func wlDiffValue(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return strconv.Quote(v)
+}
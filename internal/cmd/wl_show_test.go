@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestBuildWantedShowJSON_Nested(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-dep", Title: "Prereq"})
+	_ = store.InsertWanted(&doltserver.WantedItem{
+		ID:        "w-abc",
+		Title:     "Fix auth bug",
+		Priority:  1,
+		Tags:      []string{"go", "auth"},
+		DependsOn: []string{"w-dep"},
+	})
+	if _, err := claimWanted(context.Background(), store, "w-abc", "my-rig", claimOptions{Note: "on it"}); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if err := store.SubmitCompletion("c-123", "w-abc", "my-rig", "https://github.com/org/repo/pull/1"); err != nil {
+		t.Fatalf("SubmitCompletion() error: %v", err)
+	}
+
+	detail, err := store.QueryWantedDetail("w-abc")
+	if err != nil {
+		t.Fatalf("QueryWantedDetail() error: %v", err)
+	}
+	data, err := json.Marshal(buildWantedShowJSON(detail))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		ID           string   `json:"id"`
+		Tags         []string `json:"tags"`
+		Dependencies []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"dependencies"`
+		Comments []struct {
+			Author string `json:"author"`
+			Body   string `json:"body"`
+		} `json:"comments"`
+		Completions []struct {
+			ID          string `json:"id"`
+			CompletedBy string `json:"completed_by"`
+			Evidence    string `json:"evidence"`
+		} `json:"completions"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+
+	if got.ID != "w-abc" {
+		t.Errorf("id = %q, want w-abc", got.ID)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "go" || got.Tags[1] != "auth" {
+		t.Errorf("tags = %v, want [go auth]", got.Tags)
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].ID != "w-dep" || got.Dependencies[0].Status != "open" {
+		t.Errorf("dependencies = %+v", got.Dependencies)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].Author != "my-rig" || got.Comments[0].Body != "on it" {
+		t.Errorf("comments = %+v", got.Comments)
+	}
+	if len(got.Completions) != 1 || got.Completions[0].ID != "c-123" || got.Completions[0].CompletedBy != "my-rig" {
+		t.Errorf("completions = %+v", got.Completions)
+	}
+}
+
+func TestBuildWantedShowJSON_EvidenceAndTimes(t *testing.T) {
+	t.Parallel()
+	posted := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	out := buildWantedShowJSON(&doltserver.WantedDetail{Item: &doltserver.WantedItem{
+		ID:          "w-abc",
+		Title:       "Fix auth bug",
+		EvidenceURL: "https://github.com/org/repo/pull/1",
+		CreatedAt:   posted,
+		UpdatedAt:   posted.Add(time.Hour),
+	}})
+
+	if out.EvidenceURL != "https://github.com/org/repo/pull/1" {
+		t.Errorf("evidence_url = %q", out.EvidenceURL)
+	}
+	if out.CreatedAt != "2026-03-01T09:30:00Z" || out.UpdatedAt != "2026-03-01T10:30:00Z" {
+		t.Errorf("created_at = %q, updated_at = %q", out.CreatedAt, out.UpdatedAt)
+	}
+	if out.ExpiresAt != "" {
+		t.Errorf("expires_at = %q, want empty for an item without a lease", out.ExpiresAt)
+	}
+}
+
+func TestBuildWantedShowJSON_EmptyRelationsAreArrays(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(buildWantedShowJSON(&doltserver.WantedDetail{
+		Item: &doltserver.WantedItem{ID: "w-abc", Title: "Bare"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"tags", "dependencies", "comments", "completions"} {
+		if string(raw[key]) != "[]" {
+			t.Errorf("%s = %s, want []", key, raw[key])
+		}
+	}
+	if string(raw["labels"]) != "{}" {
+		t.Errorf("labels = %s, want {}", raw["labels"])
+	}
+}
+
+func TestBuildWantedShowJSON_Labels(t *testing.T) {
+	t.Parallel()
+	out := buildWantedShowJSON(&doltserver.WantedDetail{
+		Item: &doltserver.WantedItem{ID: "w-abc", Title: "Labelled"},
+		Labels: []doltserver.WantedLabel{
+			{Key: "sprint", Value: "2026-10", SetBy: "rig-a"},
+			{Key: "ticket", Value: "OPS-142", SetBy: "rig-a"},
+		},
+	})
+	if len(out.Labels) != 2 || out.Labels["ticket"] != "OPS-142" || out.Labels["sprint"] != "2026-10" {
+		t.Errorf("Labels = %v", out.Labels)
+	}
+}
+
+func TestBuildWantedShowJSON_TimeoutActionDefault(t *testing.T) {
+	t.Parallel()
+	got := buildWantedShowJSON(&doltserver.WantedDetail{Item: &doltserver.WantedItem{ID: "w-a"}})
+	if got.TimeoutAction != doltserver.TimeoutActionReopen {
+		t.Errorf("TimeoutAction = %q, want %q", got.TimeoutAction, doltserver.TimeoutActionReopen)
+	}
+	got = buildWantedShowJSON(&doltserver.WantedDetail{Item: &doltserver.WantedItem{ID: "w-a", TimeoutAction: "escalate"}})
+	if got.TimeoutAction != "escalate" {
+		t.Errorf("TimeoutAction = %q, want escalate", got.TimeoutAction)
+	}
+}
+
+func TestDisputeThreshold(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		settings map[string]string
+		want     int
+	}{
+		{nil, defaultDisputeThreshold},
+		{map[string]string{wlSettingDisputeThreshold: "4"}, 4},
+		{map[string]string{wlSettingDisputeThreshold: "many"}, defaultDisputeThreshold},
+		{map[string]string{wlSettingDisputeThreshold: "-1"}, defaultDisputeThreshold},
+	}
+	for _, tt := range tests {
+		if got := disputeThreshold(tt.settings); got != tt.want {
+			t.Errorf("disputeThreshold(%v) = %d, want %d", tt.settings, got, tt.want)
+		}
+	}
+	if isDisputed(2, 2) || !isDisputed(3, 2) {
+		t.Error("isDisputed should be true only past the threshold")
+	}
+}
+
+func TestShowWantedDiff_ChangedAndUnchangedFields(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-abc", Title: "Fix auth bug", Priority: 2, Tags: []string{"go"}})
+	store.commit("c0ffee")
+	_ = store.ClaimWanted("w-abc", "my-rig")
+	store.items["w-abc"].Priority = 1
+
+	var out strings.Builder
+	if err := showWantedDiff(&out, store, "w-abc", "c0ffee"); err != nil {
+		t.Fatalf("showWantedDiff() error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"changes since c0ffee:",
+		`priority:    "2" → "1"`,
+		`status:      "open" → "claimed"`,
+		`claimed_by:  (none) → "my-rig"`,
+		"12 other field(s) unchanged",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diff missing %q:\n%s", want, got)
+		}
+	}
+	for _, unchanged := range []string{"title:", "tags:"} {
+		if strings.Contains(got, unchanged) {
+			t.Errorf("diff lists unchanged field %q:\n%s", unchanged, got)
+		}
+	}
+}
+
+func TestShowWantedDiff_ItemDidNotExist(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	store.commit("c0ffee")
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-new", Title: "Brand new"})
+
+	var out strings.Builder
+	if err := showWantedDiff(&out, store, "w-new", "c0ffee"); err != nil {
+		t.Fatalf("showWantedDiff() error: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "did not exist at c0ffee") || !strings.Contains(got, `title:   "Brand new"`) {
+		t.Errorf("diff = %q, want new-item rendering with the title", got)
+	}
+
+	if err := showWantedDiff(&out, store, "w-new", "deadbeef"); err == nil {
+		t.Error("showWantedDiff() with an unknown commit should fail")
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// wlSnapshotDir is the directory, inside the wasteland directory, where
+// destructive commands save the rows they are about to change.
+const wlSnapshotDir = "snapshots"
+
+// wlSnapshotUndoneSuffix marks a snapshot gt wl undo-last has replayed, so
+// the next undo-last reaches further back.
+const wlSnapshotUndoneSuffix = ".undone"
+
+// wlSnapshotNameLayout names snapshot files so they sort oldest first.
+const wlSnapshotNameLayout = "20060102-150405.000000"
+
+var wlUndoLastYes bool
+
+var wlUndoLastCmd = &cobra.Command{
+	Use:   "undo-last",
+	Short: "Replay the most recent pre-write snapshot",
+	Args:  cobra.NoArgs,
+	RunE:  runWlUndoLast,
+	Long: `Undo the most recent destructive wl command by replaying its snapshot.
+
+gt wl merge-duplicates, gt wl reindex, and gt wl restore save the rows
+they are about to change under .wasteland/snapshots/ before writing, and
+print the path.
+undo-last writes the newest snapshot back in one Dolt commit: rows that were
+updated in place are restored by primary key, and tables that were reloaded
+wholesale are cleared and reloaded. Rows the command added elsewhere, such
+as history events, are kept.
+
+A replayed snapshot is renamed with an .undone suffix, so running undo-last
+again undoes the command before it. Asks for confirmation unless --yes.
+
+Examples:
+  gt wl undo-last
+  gt wl undo-last --yes`,
+}
+
+func init() {
+	wlUndoLastCmd.Flags().BoolVarP(&wlUndoLastYes, "yes", "y", false, "Skip confirmation prompt")
+
+	wlCmd.AddCommand(wlUndoLastCmd)
+}
+
+// wlSnapshot is the rows a destructive command was about to change.
+type wlSnapshot struct {
+	Command string `json:"command"`
+
+	// Replace means the command reloaded whole tables, so undo clears
+	// each snapshotted table before reloading it. Otherwise undo upserts
+	// the rows.
+	Replace bool `json:"replace"`
+
+	Archive *doltserver.WLArchive `json:"archive"`
+}
+
+func wlSnapshotsPath(townRoot string) string {
+	return filepath.Join(wasteland.WastelandDir(townRoot), wlSnapshotDir)
+}
+
+// saveWlSnapshot writes snap to a timestamped file in dir and returns its
+// path.
+func saveWlSnapshot(dir string, snap *wlSnapshot) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.json", snap.Archive.CreatedAt.UTC().Format(wlSnapshotNameLayout), snap.Command)
+	path := filepath.Join(dir, name)
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("writing snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// snapshotBeforeWrite saves the rows take returns before command writes,
+// and prints where they went. The command must not proceed on error. A dry
+// run writes nothing to undo, so it saves no snapshot.
+func snapshotBeforeWrite(townRoot, command string, replace bool, take func() (*doltserver.WLArchive, error)) error {
+	if doltserver.WLDryRun() {
+		return nil
+	}
+	archive, err := take()
+	if err != nil {
+		return fmt.Errorf("taking pre-write snapshot: %w", err)
+	}
+	path, err := saveWlSnapshot(wlSnapshotsPath(townRoot), &wlSnapshot{Command: command, Replace: replace, Archive: archive})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot of %d row(s) saved to %s (undo with gt wl undo-last)\n", archive.RowCount(), style.Dim.Render(path))
+	return nil
+}
+
+// latestWlSnapshot returns the newest snapshot in dir not yet undone.
+func latestWlSnapshot(dir string) (string, *wlSnapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return "", nil, err
+	}
+	if len(paths) == 0 {
+		return "", nil, fmt.Errorf("no snapshots to undo in %s", dir)
+	}
+	sort.Strings(paths)
+	path := paths[len(paths)-1]
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("opening snapshot: %w", err)
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	var snap wlSnapshot
+	if err := dec.Decode(&snap); err != nil {
+		return "", nil, fmt.Errorf("decoding snapshot %s: %w", path, err)
+	}
+	if snap.Archive == nil {
+		return "", nil, fmt.Errorf("snapshot %s has no rows", path)
+	}
+	if err := snap.Archive.Validate(); err != nil {
+		return "", nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	return path, &snap, nil
+}
+
+// markWlSnapshotUndone retires a replayed snapshot. A dry run replayed
+// nothing, so the snapshot stays.
+func markWlSnapshotUndone(path string) error {
+	if doltserver.WLDryRun() {
+		return nil
+	}
+	if err := os.Rename(path, path+wlSnapshotUndoneSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("retiring snapshot: %w", err)
+	}
+	return nil
+}
+
+func runWlUndoLast(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	path, snap, err := latestWlSnapshot(wlSnapshotsPath(townRoot))
+	if err != nil {
+		return err
+	}
+	created := snap.Archive.CreatedAt.Format("2006-01-02 15:04:05 UTC")
+	mode := "upsert rows"
+	if snap.Replace {
+		mode = "replace tables"
+	}
+	fmt.Printf("Snapshot before gt wl %s (taken %s, %s):\n", snap.Command, created, mode)
+	printArchiveSummary(snap.Archive)
+
+	if !wlUndoLastYes {
+		fmt.Println()
+		if !promptYesNo(fmt.Sprintf("Write these rows back to %s?", doltserver.WLCommonsDB)) {
+			fmt.Println("Undo cancelled.")
+			return nil
+		}
+	}
+
+	message := fmt.Sprintf("wl undo-last: %s snapshot from %s", snap.Command, strings.TrimSuffix(created, " UTC"))
+	if err := doltserver.ReplayWLSnapshot(townRoot, snap.Archive, snap.Replace, message); err != nil {
+		return fmt.Errorf("replaying snapshot: %w", err)
+	}
+	if err := markWlSnapshotUndone(path); err != nil {
+		return err
+	}
+	fmt.Printf("%s Restored %d row(s) from %s\n", wlSuccessMark(), snap.Archive.RowCount(), path)
+	return nil
+}
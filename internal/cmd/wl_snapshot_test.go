@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func testSnapshotArchive(at time.Time, status string) *doltserver.WLArchive {
+	return &doltserver.WLArchive{
+		Format:    doltserver.WLArchiveFormat,
+		Version:   doltserver.WLArchiveVersion,
+		Database:  doltserver.WLCommonsDB,
+		CreatedAt: at,
+		Tables: map[string][]map[string]any{
+			"wanted": {{"id": "w-dup", "status": status, "priority": json.Number("2")}},
+		},
+	}
+}
+
+func TestWlSnapshot_SaveAndUndoNewestFirst(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), wlSnapshotDir)
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	older, err := saveWlSnapshot(dir, &wlSnapshot{Command: "restore", Replace: true, Archive: testSnapshotArchive(t0, "claimed")})
+	if err != nil {
+		t.Fatalf("saveWlSnapshot() error: %v", err)
+	}
+	newer, err := saveWlSnapshot(dir, &wlSnapshot{Command: "merge-duplicates", Archive: testSnapshotArchive(t0.Add(time.Second), "open")})
+	if err != nil {
+		t.Fatalf("saveWlSnapshot() error: %v", err)
+	}
+	if !strings.HasSuffix(newer, "-merge-duplicates.json") {
+		t.Errorf("snapshot path = %s, want a timestamped merge-duplicates file", newer)
+	}
+
+	path, snap, err := latestWlSnapshot(dir)
+	if err != nil {
+		t.Fatalf("latestWlSnapshot() error: %v", err)
+	}
+	if path != newer || snap.Command != "merge-duplicates" || snap.Replace {
+		t.Fatalf("latest = %s %+v, want the merge-duplicates snapshot", path, snap)
+	}
+	row := snap.Archive.Tables["wanted"][0]
+	if row["status"] != "open" || row["priority"] != json.Number("2") {
+		t.Errorf("snapshot row = %v, want the saved values with numbers intact", row)
+	}
+
+	// Undoing retires the snapshot, so the next undo reaches the older one.
+	if err := markWlSnapshotUndone(path); err != nil {
+		t.Fatalf("markWlSnapshotUndone() error: %v", err)
+	}
+	if _, err := os.Stat(newer + wlSnapshotUndoneSuffix); err != nil {
+		t.Errorf("undone snapshot should be kept with the %s suffix: %v", wlSnapshotUndoneSuffix, err)
+	}
+	if path, snap, err = latestWlSnapshot(dir); err != nil || path != older || !snap.Replace {
+		t.Fatalf("latest after undo = %s, %+v, %v; want the restore snapshot", path, snap, err)
+	}
+
+	_ = markWlSnapshotUndone(older)
+	if _, _, err := latestWlSnapshot(dir); err == nil || !strings.Contains(err.Error(), "no snapshots to undo") {
+		t.Errorf("latestWlSnapshot() with all undone error = %v, want none left", err)
+	}
+}
+
+func TestLatestWlSnapshot_RejectsInvalid(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "20261001-120000.000000-restore.json"), []byte(`{"command":"restore","archive":{"format":"nope"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := latestWlSnapshot(dir); err == nil || !strings.Contains(err.Error(), "invalid snapshot") {
+		t.Errorf("latestWlSnapshot() error = %v, want invalid snapshot", err)
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Burndown defaults and chart sizing.
+const (
+	burndownDefaultDays = 30
+	burndownBarWidth    = 50
+	burndownDateLayout  = "2006-01-02"
+)
+
+var (
+	wlStatsBurndown bool
+	wlStatsSince    string
+	wlStatsUntil    string
+	wlStatsCSV      bool
+	wlStatsTowns    bool
+	wlStatsTown     string
+)
+
+var wlStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show wanted board statistics",
+	Long: `Show statistics for the local wanted board.
+
+By default, prints the number of wanted items in each status.
+
+With --burndown, prints how many items were still open (not yet completed)
+at the end of each day in the --since..--until window, as an ASCII chart or,
+with --csv, as date,open rows. Days are bucketed in UTC. An item counts as
+open from its created_at until its first completion; items closed
+without a completion (e.g. withdrawn) close at their last update.
+
+The window defaults to the last 30 days ending today. Dates are YYYY-MM-DD.
+
+With --towns, prints a leaderboard of each town's contribution: items it
+posted, items it claimed (held now or to completion), completions it
+submitted, and completions accepted. Superseded completions are not
+counted. Towns are ranked by accepted completions, then submitted,
+claimed, and posted. --town <handle> shows just that town's row.
+
+Examples:
+  gt wl stats
+  gt wl stats --towns
+  gt wl stats --town partner-rig
+  gt wl stats --burndown
+  gt wl stats --burndown --since 2026-01-01 --until 2026-01-31 --csv`,
+	Args: cobra.NoArgs,
+	RunE: runWlStats,
+}
+
+func init() {
+	wlStatsCmd.Flags().BoolVar(&wlStatsBurndown, "burndown", false, "Show open items per day over the window")
+	wlStatsCmd.Flags().StringVar(&wlStatsSince, "since", "", "First day of the burndown window (YYYY-MM-DD, default: 30 days ago)")
+	wlStatsCmd.Flags().StringVar(&wlStatsUntil, "until", "", "Last day of the burndown window (YYYY-MM-DD, default: today)")
+	wlStatsCmd.Flags().BoolVar(&wlStatsCSV, "csv", false, "Emit the burndown as CSV instead of a chart")
+	wlStatsCmd.Flags().BoolVar(&wlStatsTowns, "towns", false, "Rank towns by their contributions")
+	wlStatsCmd.Flags().StringVar(&wlStatsTown, "town", "", "Show only this town's contributions (implies --towns)")
+	wlStatsCmd.MarkFlagsMutuallyExclusive("burndown", "towns")
+	wlStatsCmd.MarkFlagsMutuallyExclusive("burndown", "town")
+
+	wlCmd.AddCommand(wlStatsCmd)
+}
+
+func runWlStats(cmd *cobra.Command, args []string) error {
+	if !wlStatsBurndown && (wlStatsSince != "" || wlStatsUntil != "" || wlStatsCSV) {
+		return fmt.Errorf("--since, --until, and --csv require --burndown")
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	if wlStatsTowns || wlStatsTown != "" {
+		stats, err := doltserver.QueryTownStats(townRoot, wlStatsTown)
+		if err != nil {
+			return err
+		}
+		if wlStatsTown != "" && len(stats) == 0 {
+			fmt.Printf("%s has not posted, claimed, or completed anything.\n", wlStatsTown)
+			return nil
+		}
+		renderTownStats(os.Stdout, stats)
+		return nil
+	}
+
+	if wlStatsBurndown {
+		since, until, err := parseBurndownWindow(wlStatsSince, wlStatsUntil, time.Now())
+		if err != nil {
+			return err
+		}
+		model, err := doltserver.QueryStatusModel(doltserver.NewWLCommons(townRoot))
+		if err != nil {
+			return err
+		}
+		timelines, err := doltserver.QueryWantedTimelines(townRoot)
+		if err != nil {
+			return fmt.Errorf("querying wanted timelines: %w", err)
+		}
+		days := computeBurndown(timelines, model, since, until)
+		if wlStatsCSV {
+			return writeBurndownCSV(os.Stdout, days)
+		}
+		renderBurndownChart(os.Stdout, days)
+		return nil
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	items, err := store.ListWanted(doltserver.WantedFilter{MinPriority: -1, MaxPriority: -1})
+	if err != nil {
+		return fmt.Errorf("listing wanted items: %w", err)
+	}
+	fmt.Print(buildStatusCountTable(items).Render())
+	return nil
+}
+
+// buildStatusCountTable counts items per status, most common first.
+func buildStatusCountTable(items []*doltserver.WantedItem) *style.Table {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[item.Status]++
+	}
+	statuses := sortedStatusKeys(counts)
+
+	tbl := style.NewTable(
+		style.Column{Name: "STATUS", Width: 12},
+		style.Column{Name: "COUNT", Width: 8},
+	)
+	for _, s := range statuses {
+		tbl.AddRow(s, fmt.Sprint(counts[s]))
+	}
+	tbl.AddRow(style.Bold.Render("total"), fmt.Sprint(len(items)))
+	return tbl
+}
+
+// renderTownStats prints stats as a ranked table, in the order given. Towns
+// with equal counts share a rank.
+func renderTownStats(w io.Writer, stats []doltserver.TownStats) {
+	tbl := style.NewTable(
+		style.Column{Name: "RANK", Width: 5},
+		style.Column{Name: "TOWN", Width: 24},
+		style.Column{Name: "POSTED", Width: 7},
+		style.Column{Name: "CLAIMED", Width: 8},
+		style.Column{Name: "SUBMITTED", Width: 10},
+		style.Column{Name: "ACCEPTED", Width: 9},
+	)
+	rank := 0
+	for i, s := range stats {
+		if i == 0 || townStatsKey(s) != townStatsKey(stats[i-1]) {
+			rank = i + 1
+		}
+		tbl.AddRow(fmt.Sprint(rank), s.Handle, fmt.Sprint(s.Posted), fmt.Sprint(s.Claimed), fmt.Sprint(s.Submitted), fmt.Sprint(s.Accepted))
+	}
+	fmt.Fprint(w, tbl.Render())
+}
+
+// townStatsKey is the part of s the leaderboard ranks on.
+func townStatsKey(s doltserver.TownStats) [4]int {
+	return [4]int{s.Accepted, s.Submitted, s.Claimed, s.Posted}
+}
+
+// burndownDay is the number of items open at the end of Date (UTC).
+type burndownDay struct {
+	Date time.Time
+	Open int
+}
+
+// parseBurndownWindow resolves the --since/--until flags to UTC midnights.
+// Missing bounds default to the burndownDefaultDays ending on now's UTC day.
+func parseBurndownWindow(sinceFlag, untilFlag string, now time.Time) (time.Time, time.Time, error) {
+	until := utcDay(now)
+	if untilFlag != "" {
+		t, err := time.ParseInLocation(burndownDateLayout, untilFlag, time.UTC)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until %q: want YYYY-MM-DD", untilFlag)
+		}
+		until = t
+	}
+	since := until.AddDate(0, 0, -(burndownDefaultDays - 1))
+	if sinceFlag != "" {
+		t, err := time.ParseInLocation(burndownDateLayout, sinceFlag, time.UTC)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since %q: want YYYY-MM-DD", sinceFlag)
+		}
+		since = t
+	}
+	if since.After(until) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since %s is after --until %s",
+			since.Format(burndownDateLayout), until.Format(burndownDateLayout))
+	}
+	return since, until, nil
+}
+
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// computeBurndown counts, for each UTC day from since through until, the
+// items created before the day ended and not yet closed by then.
+func computeBurndown(timelines []doltserver.WantedTimeline, model *doltserver.StatusModel, since, until time.Time) []burndownDay {
+	var days []burndownDay
+	for day := utcDay(since); !day.After(until); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		open := 0
+		for _, tl := range timelines {
+			if tl.CreatedAt.IsZero() || !tl.CreatedAt.Before(end) {
+				continue
+			}
+			if closed := timelineClosedAt(tl, model); closed.IsZero() || !closed.Before(end) {
+				open++
+			}
+		}
+		days = append(days, burndownDay{Date: day, Open: open})
+	}
+	return days
+}
+
+// timelineClosedAt returns when an item stopped being open work: its first
+// completion, or its last update when it closed without one.
+func timelineClosedAt(tl doltserver.WantedTimeline, model *doltserver.StatusModel) time.Time {
+	if !tl.CompletedAt.IsZero() {
+		return tl.CompletedAt
+	}
+	if model.Closed(tl.Status) {
+		return tl.UpdatedAt
+	}
+	return time.Time{}
+}
+
+func writeBurndownCSV(w io.Writer, days []burndownDay) error {
+	if _, err := fmt.Fprintln(w, "date,open"); err != nil {
+		return err
+	}
+	for _, d := range days {
+		if _, err := fmt.Fprintf(w, "%s,%d\n", d.Date.Format(burndownDateLayout), d.Open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderBurndownChart draws one bar per day, scaled to the busiest day.
+func renderBurndownChart(w io.Writer, days []burndownDay) {
+	peak := 0
+	for _, d := range days {
+		if d.Open > peak {
+			peak = d.Open
+		}
+	}
+	for _, d := range days {
+		bar := 0
+		if peak > 0 {
+			bar = (d.Open*burndownBarWidth + peak - 1) / peak
+		}
+		fmt.Fprintf(w, "%s %s %d\n", d.Date.Format(burndownDateLayout), strings.Repeat("#", bar), d.Open)
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func burndownTime(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestComputeBurndown(t *testing.T) {
+	t.Parallel()
+	timelines := []doltserver.WantedTimeline{
+		// Open throughout.
+		{ID: "w-1", Status: "open", CreatedAt: burndownTime("2026-01-01 09:00")},
+		// Created mid-window, completed on 01-04.
+		{ID: "w-2", Status: "in_review", CreatedAt: burndownTime("2026-01-02 23:59"), CompletedAt: burndownTime("2026-01-04 10:00")},
+		// Withdrawn on 01-03 without a completion.
+		{ID: "w-3", Status: "withdrawn", CreatedAt: burndownTime("2026-01-01 00:00"), UpdatedAt: burndownTime("2026-01-03 00:00")},
+		// No created_at: ignored.
+		{ID: "w-4", Status: "open"},
+	}
+	since, until := burndownTime("2026-01-01 00:00"), burndownTime("2026-01-04 00:00")
+
+	days := computeBurndown(timelines, doltserver.DefaultStatusModel(), since, until)
+	want := []int{2, 3, 2, 1}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, d := range days {
+		if d.Open != want[i] {
+			t.Errorf("%s open = %d, want %d", d.Date.Format("2006-01-02"), d.Open, want[i])
+		}
+	}
+}
+
+func TestParseBurndownWindow(t *testing.T) {
+	t.Parallel()
+	now := burndownTime("2026-02-10 18:30")
+
+	since, until, err := parseBurndownWindow("", "", now)
+	if err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	if got := since.Format("2006-01-02") + ".." + until.Format("2006-01-02"); got != "2026-01-12..2026-02-10" {
+		t.Errorf("default window = %s", got)
+	}
+
+	if _, _, err := parseBurndownWindow("2026-02-11", "2026-02-10", now); err == nil {
+		t.Error("expected error for since after until")
+	}
+	if _, _, err := parseBurndownWindow("02/01/2026", "", now); err == nil || !strings.Contains(err.Error(), "--since") {
+		t.Errorf("bad --since error = %v", err)
+	}
+}
+
+func TestWriteBurndownCSV(t *testing.T) {
+	t.Parallel()
+	days := []burndownDay{
+		{Date: burndownTime("2026-01-01 00:00"), Open: 3},
+		{Date: burndownTime("2026-01-02 00:00"), Open: 0},
+	}
+	var buf bytes.Buffer
+	if err := writeBurndownCSV(&buf, days); err != nil {
+		t.Fatal(err)
+	}
+	if want := "date,open\n2026-01-01,3\n2026-01-02,0\n"; buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRenderBurndownChart_ScalesToPeak(t *testing.T) {
+	t.Parallel()
+	days := []burndownDay{
+		{Date: burndownTime("2026-01-01 00:00"), Open: 10},
+		{Date: burndownTime("2026-01-02 00:00"), Open: 5},
+		{Date: burndownTime("2026-01-03 00:00"), Open: 0},
+	}
+	var buf bytes.Buffer
+	renderBurndownChart(&buf, days)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	if n := strings.Count(lines[0], "#"); n != burndownBarWidth {
+		t.Errorf("peak bar = %d, want %d", n, burndownBarWidth)
+	}
+	if n := strings.Count(lines[1], "#"); n != burndownBarWidth/2 {
+		t.Errorf("half bar = %d, want %d", n, burndownBarWidth/2)
+	}
+	if strings.Contains(lines[2], "#") || !strings.HasSuffix(lines[2], " 0") {
+		t.Errorf("empty day line = %q", lines[2])
+	}
+}
+
+func TestRenderTownStats_TiesShareRank(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	renderTownStats(&buf, []doltserver.TownStats{
+		{Handle: "alpha", Posted: 2, Claimed: 4, Submitted: 3, Accepted: 2},
+		{Handle: "beta", Posted: 1, Claimed: 1, Submitted: 1, Accepted: 1},
+		{Handle: "gamma", Posted: 1, Claimed: 1, Submitted: 1, Accepted: 1},
+		{Handle: "delta", Posted: 5},
+	})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	rows := lines[len(lines)-4:]
+	for i, want := range []string{"1 alpha", "2 beta", "2 gamma", "4 delta"} {
+		if got := strings.Join(strings.Fields(rows[i])[:2], " "); got != want {
+			t.Errorf("row %d = %q, want %q\n%s", i+1, got, want, buf.String())
+		}
+	}
+}
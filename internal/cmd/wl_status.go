@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Sync states reported by gt wl status. Ahead and behind are measured
+// against upstream/main as of the clone's last fetch.
+const (
+	wlSyncNoClone  = "no_clone"
+	wlSyncUnknown  = "unknown"
+	wlSyncInSync   = "in_sync"
+	wlSyncAhead    = "ahead"
+	wlSyncBehind   = "behind"
+	wlSyncDiverged = "diverged"
+)
+
+var wlStatusCmd = &cobra.Command{
+	Use:   "status [wanted-id]",
+	Short: "Show wanted board health, or one item's status",
+	Long: `Show an aggregate view of the local wanted board: items per status,
+the age of the oldest open item, claims whose lease has lapsed, and how the
+local wl-commons clone compares with upstream.
+
+Sync state is read from the clone without fetching, so "behind" reflects
+upstream as of the last gt wl sync.
+
+With --json, the same figures are written as one JSON object with stable
+keys, for scraping into dashboards and alerts. Every known status appears
+in counts_by_status, with 0 when no item has it.
+
+With a wanted ID, only that item's status is printed (e.g. "claimed"), as
+a cheap probe for scripts that poll an item; gt wl show is the full view.
+With --json it is {"id": ..., "status": ..., "claimed_by": ...}. An item
+that does not exist is an error.
+
+Examples:
+  gt wl status
+  gt wl status --json | jq '.expired_claims'
+  gt wl status w-abc123
+  gt wl status w-abc123 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlStatus,
+}
+
+func init() {
+	wlCmd.AddCommand(wlStatusCmd)
+}
+
+func runWlStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		item, err := queryItemStatus(doltserver.NewWLCommons(townRoot), args[0])
+		if err != nil {
+			return err
+		}
+		if wlJSON {
+			return writeWLJSON(os.Stdout, item, wlJSONPrettyOutput())
+		}
+		fmt.Println(item.Status)
+		return nil
+	}
+
+	board, err := doltserver.QueryWLBoard(townRoot)
+	if err != nil {
+		return err
+	}
+	cfg, _ := wlRun.wastelandConfig()
+	status, err := buildWlBoardStatus(board, querySyncState(wlCommonsCloneDir(townRoot, cfg)), time.Now())
+	if err != nil {
+		return err
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, status, wlJSONPrettyOutput())
+	}
+	renderWlBoardStatus(os.Stdout, status)
+	return nil
+}
+
+// wlItemStatusJSON is the --json result of gt wl status <wanted-id>.
+type wlItemStatusJSON struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	ClaimedBy string `json:"claimed_by"`
+}
+
+// queryItemStatus looks up the status and claimant of one wanted item.
+func queryItemStatus(store doltserver.WLCommonsStore, wantedID string) (*wlItemStatusJSON, error) {
+	item, err := store.QueryWanted(wantedID)
+	if err != nil {
+		return nil, err
+	}
+	return &wlItemStatusJSON{ID: item.ID, Status: item.Status, ClaimedBy: item.ClaimedBy}, nil
+}
+
+// wlBoardStatus is the board summary behind both gt wl status renderings.
+type wlBoardStatus struct {
+	CountsByStatus map[string]int `json:"counts_by_status"`
+	Total          int            `json:"total"`
+
+	// OldestOpenID and OldestOpenAgeSeconds describe the longest-waiting
+	// open item; both are empty when nothing is open.
+	OldestOpenID         string `json:"oldest_open_id"`
+	OldestOpenAgeSeconds int64  `json:"oldest_open_age_seconds"`
+
+	ExpiredClaims int         `json:"expired_claims"`
+	Sync          wlSyncState `json:"sync"`
+	GeneratedAt   string      `json:"generated_at"`
+}
+
+// wlSyncState is how the local wl-commons clone compares with upstream.
+type wlSyncState struct {
+	State        string `json:"state"`
+	CloneDir     string `json:"clone_dir"`
+	Ahead        int    `json:"ahead"`
+	Behind       int    `json:"behind"`
+	LastCommitAt string `json:"last_commit_at"`
+	Error        string `json:"error,omitempty"`
+}
+
+// buildWlBoardStatus summarizes every wanted item on board as of now.
+func buildWlBoardStatus(board *doltserver.WLBoard, sync wlSyncState, now time.Time) (*wlBoardStatus, error) {
+	model, err := doltserver.StatusModelFromSettings(board.Settings)
+	if err != nil {
+		return nil, err
+	}
+	items := board.Items
+
+	status := &wlBoardStatus{
+		CountsByStatus: make(map[string]int),
+		Total:          len(items),
+		Sync:           sync,
+		GeneratedAt:    now.UTC().Format(time.RFC3339),
+	}
+	for _, s := range model.Statuses() {
+		status.CountsByStatus[s] = 0
+	}
+
+	var oldest *doltserver.WantedItem
+	for _, item := range items {
+		status.CountsByStatus[item.Status]++
+		switch item.Status {
+		case doltserver.StatusOpen:
+			if item.CreatedAt.IsZero() {
+				continue
+			}
+			if oldest == nil || item.CreatedAt.Before(oldest.CreatedAt) {
+				oldest = item
+			}
+		case doltserver.StatusClaimed:
+			if !item.ExpiresAt.IsZero() && item.ExpiresAt.Before(now) {
+				status.ExpiredClaims++
+			}
+		}
+	}
+	if oldest != nil {
+		status.OldestOpenID = oldest.ID
+		status.OldestOpenAgeSeconds = int64(now.Sub(oldest.CreatedAt).Seconds())
+	}
+	return status, nil
+}
+
+// querySyncState compares the clone at dir with its last-fetched upstream.
+// Failures are reported in the state rather than returned, so the rest of
+// the dashboard still renders.
+func querySyncState(dir string) wlSyncState {
+	if dir == "" {
+		return wlSyncState{State: wlSyncNoClone}
+	}
+	sync := wlSyncState{State: wlSyncUnknown, CloneDir: dir}
+
+	query := `SELECT
+		(SELECT COUNT(*) FROM dolt_log('upstream/main..HEAD')) AS ahead,
+		(SELECT COUNT(*) FROM dolt_log('HEAD..upstream/main')) AS behind,
+		(SELECT DATE_FORMAT(MAX(date), '%Y-%m-%dT%H:%i:%sZ') FROM dolt_log) AS last_commit`
+	out, err := doltserver.RunDoltCommand(buildWlDoltCmd(dir, "sql", "-q", query, "-r", "csv"))
+	if err != nil {
+		sync.Error = strings.TrimSpace(string(out))
+		if sync.Error == "" {
+			sync.Error = err.Error()
+		}
+		return sync
+	}
+	rows := wlParseCSV(string(out))
+	if len(rows) < 2 || len(rows[1]) < 3 {
+		sync.Error = "unexpected dolt output"
+		return sync
+	}
+	sync.Ahead, _ = strconv.Atoi(rows[1][0])
+	sync.Behind, _ = strconv.Atoi(rows[1][1])
+	sync.LastCommitAt = rows[1][2]
+	sync.State = syncStateName(sync.Ahead, sync.Behind)
+	return sync
+}
+
+func syncStateName(ahead, behind int) string {
+	switch {
+	case ahead > 0 && behind > 0:
+		return wlSyncDiverged
+	case ahead > 0:
+		return wlSyncAhead
+	case behind > 0:
+		return wlSyncBehind
+	default:
+		return wlSyncInSync
+	}
+}
+
+// renderWlBoardStatus prints the human form of gt wl status.
+func renderWlBoardStatus(w io.Writer, status *wlBoardStatus) {
+	fmt.Fprintf(w, "%s\n\n", style.Bold.Render("Wanted board"))
+
+	tbl := style.NewTable(
+		style.Column{Name: "STATUS", Width: 12},
+		style.Column{Name: "COUNT", Width: 8},
+	)
+	for _, s := range sortedStatusKeys(status.CountsByStatus) {
+		tbl.AddRow(s, fmt.Sprint(status.CountsByStatus[s]))
+	}
+	tbl.AddRow(style.Bold.Render("total"), fmt.Sprint(status.Total))
+	fmt.Fprint(w, tbl.Render())
+
+	fmt.Fprintln(w)
+	if status.OldestOpenID == "" {
+		fmt.Fprintf(w, "  Oldest open:    %s\n", style.Dim.Render("(none)"))
+	} else {
+		age := time.Duration(status.OldestOpenAgeSeconds) * time.Second
+		fmt.Fprintf(w, "  Oldest open:    %s (%s)\n", status.OldestOpenID, formatDuration(age))
+	}
+	expired := fmt.Sprint(status.ExpiredClaims)
+	if status.ExpiredClaims > 0 {
+		expired = style.Warning.Render(expired)
+	}
+	fmt.Fprintf(w, "  Expired claims: %s\n", expired)
+
+	sync := status.Sync
+	switch sync.State {
+	case wlSyncNoClone:
+		fmt.Fprintf(w, "  Sync:           %s\n", style.Dim.Render("no local wl-commons clone"))
+	case wlSyncUnknown:
+		fmt.Fprintf(w, "  Sync:           unknown (%s)\n", sync.Error)
+	default:
+		fmt.Fprintf(w, "  Sync:           %s (%d ahead, %d behind upstream/main)\n", sync.State, sync.Ahead, sync.Behind)
+		if sync.LastCommitAt != "" {
+			fmt.Fprintf(w, "  Last commit:    %s\n", sync.LastCommitAt)
+		}
+	}
+}
+
+// sortedStatusKeys orders statuses most common first, then by name.
+func sortedStatusKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for s := range counts {
+		keys = append(keys, s)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
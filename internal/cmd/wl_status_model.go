@@ -0,0 +1,15 @@
+package cmd
+
+import "github.com/steveyegge/gastown/internal/doltserver"
+
+// requireTransition checks the wasteland's status model allows wantedID to
+// move from one status to another. Every gt wl command that moves an item
+// between statuses calls it before writing; the table in
+// wl_status_model_test.go keeps new commands to that.
+func requireTransition(store doltserver.WLCommonsStore, wantedID, from, to string) error {
+	model, err := doltserver.QueryStatusModel(store)
+	if err != nil {
+		return err
+	}
+	return model.CheckTransition(wantedID, from, to)
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// wlMovesStatus classifies every gt wl subcommand by whether it moves a
+// wanted item from one status to another. A new subcommand fails
+// TestWlCommands_ClassifiedForStatusModel until it is listed here, and one
+// listed as moving items needs a case in statusModelCases.
+var wlMovesStatus = map[string]bool{
+	"accept":           true,
+	"approve":          true,
+	"claim":            true,
+	"done":             true,
+	"merge-duplicates": true,
+	"reap":             true,
+	"reject":           true,
+	"reopen":           true,
+	"unclaim":          true,
+
+	// assign keeps the status but checks it against the model too.
+	"assign": false,
+	// post adds items at open; restore and undo-last replay whole tables.
+	"post":      false,
+	"restore":   false,
+	"undo-last": false,
+
+	"archive":        false,
+	"audit-evidence": false,
+	"bench":          false,
+	"board-url":      false,
+	"browse":         false,
+	"comment":        false,
+	"completions":    false,
+	"export":         false,
+	"group":          false,
+	"group add":      false,
+	"group members":  false,
+	"heartbeat":      false,
+	"history":        false,
+	"join":           false,
+	"list":           false,
+	"log":            false,
+	"mine":           false,
+	"reindex":        false,
+	"reviews":        false,
+	"schema":         false,
+	"search":         false,
+	"show":           false,
+	"stats":          false,
+	"status":         false,
+	"subscribe":      false,
+	"sync":           false,
+	"unsubscribe":    false,
+	"validate":       false,
+	"watch":          false,
+	"whois":          false,
+}
+
+// statusModelCase puts w-1 where a command would move it, then runs the
+// command's write path.
+type statusModelCase struct {
+	setup func(t *testing.T, store *fakeWLCommonsStore)
+	run   func(store *fakeWLCommonsStore) error
+}
+
+// submitted leaves w-1, posted by poster, in review with worker's
+// completion.
+func submitted(t *testing.T, store *fakeWLCommonsStore) {
+	t.Helper()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it", PostedBy: "poster"})
+	if err := store.ClaimWanted("w-1", "worker"); err != nil {
+		t.Fatalf("ClaimWanted() error: %v", err)
+	}
+	if err := store.SubmitCompletion("c-1", "w-1", "worker", "https://example.com/pr/1"); err != nil {
+		t.Fatalf("SubmitCompletion() error: %v", err)
+	}
+}
+
+var statusModelCases = map[string]statusModelCase{
+	"accept": {
+		setup: submitted,
+		run:   func(store *fakeWLCommonsStore) error { return acceptCompletion(store, "w-1", "poster") },
+	},
+	"approve": {
+		setup: submitted,
+		run: func(store *fakeWLCommonsStore) error {
+			pending, err := findApprovalsFrom(store, "poster", "worker")
+			if err != nil {
+				return err
+			}
+			_, err = approvePending(store, "poster", pending)
+			return err
+		},
+	},
+	"claim": {
+		setup: func(t *testing.T, store *fakeWLCommonsStore) {
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it"})
+		},
+		run: func(store *fakeWLCommonsStore) error {
+			_, err := claimWanted(context.Background(), store, "w-1", "worker", claimOptions{})
+			return err
+		},
+	},
+	"done": {
+		setup: func(t *testing.T, store *fakeWLCommonsStore) {
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it"})
+			_ = store.ClaimWanted("w-1", "worker")
+		},
+		run: func(store *fakeWLCommonsStore) error {
+			return submitDone(store, "w-1", "worker", "https://example.com/pr/1", "c-1")
+		},
+	},
+	"merge-duplicates": {
+		setup: func(t *testing.T, store *fakeWLCommonsStore) {
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-0", Title: "Fix it"})
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it"})
+		},
+		run: func(store *fakeWLCommonsStore) error {
+			keep, _ := store.QueryWanted("w-0")
+			dup, _ := store.QueryWanted("w-1")
+			_, err := mergeDuplicateGroup(store, []*doltserver.WantedItem{keep, dup}, "curator")
+			return err
+		},
+	},
+	"reap": {
+		setup: func(t *testing.T, store *fakeWLCommonsStore) {
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it"})
+			_ = store.ClaimWanted("w-1", "gone-rig")
+			store.items["w-1"].ClaimedAt = time.Now().Add(-100 * time.Hour)
+		},
+		run: func(store *fakeWLCommonsStore) error {
+			// Reap skips what it may not release rather than failing.
+			if n, err := reapStaleClaims(context.Background(), io.Discard, store, "", "janitor", time.Now().Add(-time.Hour), time.Hour); err != nil || n != 0 {
+				return err
+			}
+			return requireTransition(store, "w-1", doltserver.StatusClaimed, doltserver.StatusOpen)
+		},
+	},
+	"reject": {
+		setup: submitted,
+		run: func(store *fakeWLCommonsStore) error {
+			_, err := rejectCompletion(store, "w-1", "poster", "not yet")
+			return err
+		},
+	},
+	"reopen": {
+		setup: func(t *testing.T, store *fakeWLCommonsStore) {
+			submitted(t, store)
+			if err := store.ApproveCompletions([]string{"w-1"}, "poster"); err != nil {
+				t.Fatalf("ApproveCompletions() error: %v", err)
+			}
+		},
+		run: func(store *fakeWLCommonsStore) error {
+			_, err := reopenWanted(store, "w-1", "poster", false)
+			return err
+		},
+	},
+	"unclaim": {
+		setup: func(t *testing.T, store *fakeWLCommonsStore) {
+			_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Fix it"})
+			_ = store.ClaimWanted("w-1", "worker")
+		},
+		run: func(store *fakeWLCommonsStore) error {
+			_, err := unclaimWanted(store, "w-1", "worker", false)
+			return err
+		},
+	},
+}
+
+func TestWlCommands_ClassifiedForStatusModel(t *testing.T) {
+	t.Parallel()
+	seen := make(map[string]bool)
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			name := strings.TrimPrefix(sub.CommandPath(), wlCmd.CommandPath()+" ")
+			seen[name] = true
+			moves, ok := wlMovesStatus[name]
+			if !ok {
+				t.Errorf("gt wl %s is not in wlMovesStatus; say whether it moves items between statuses", name)
+			} else if _, tested := statusModelCases[name]; moves && !tested {
+				t.Errorf("gt wl %s moves items between statuses but has no statusModelCases entry", name)
+			}
+			walk(sub)
+		}
+	}
+	walk(wlCmd)
+
+	for name := range wlMovesStatus {
+		if !seen[name] {
+			t.Errorf("wlMovesStatus lists gt wl %s, which does not exist", name)
+		}
+	}
+}
+
+func TestMutatingCommands_CheckStatusModel(t *testing.T) {
+	t.Parallel()
+	for name, tc := range statusModelCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			store := newFakeWLCommonsStore()
+			tc.setup(t, store)
+			before, _ := store.QueryWanted("w-1")
+			// A model whose only transition is one no command here makes.
+			store.settings[doltserver.SettingWorkflowTransitions] = "withdrawn>claimed"
+
+			err := tc.run(store)
+			if err == nil || !strings.Contains(err.Error(), "cannot move from") {
+				t.Errorf("error = %v, want the status model to refuse the transition", err)
+			}
+			if after, _ := store.QueryWanted("w-1"); after.Status != before.Status {
+				t.Errorf("status = %q, want %q untouched", after.Status, before.Status)
+			}
+		})
+	}
+}
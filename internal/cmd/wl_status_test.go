@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestBuildWlBoardStatus_JSONShape(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newFakeWLCommonsStore()
+	for _, item := range []*doltserver.WantedItem{
+		{ID: "w-old", Title: "Oldest", CreatedAt: now.Add(-50 * time.Hour)},
+		{ID: "w-new", Title: "Newer", CreatedAt: now.Add(-time.Hour)},
+		{ID: "w-lapsed", Title: "Lapsed", CreatedAt: now.Add(-100 * time.Hour)},
+		{ID: "w-live", Title: "Live", CreatedAt: now.Add(-100 * time.Hour)},
+	} {
+		_ = store.InsertWanted(item)
+	}
+	_ = store.ClaimWanted("w-lapsed", "rig-a")
+	_ = store.RenewClaim("w-lapsed", "rig-a", now.Add(-time.Minute))
+	_ = store.ClaimWanted("w-live", "rig-b")
+	_ = store.RenewClaim("w-live", "rig-b", now.Add(time.Hour))
+
+	sync := wlSyncState{State: wlSyncBehind, CloneDir: "/town/wl-commons", Behind: 3, LastCommitAt: "2026-10-01T11:00:00Z"}
+	settings, _ := store.QuerySettings()
+	items, _ := store.ExportWanted(doltserver.WantedExportFilter{})
+	status, err := buildWlBoardStatus(&doltserver.WLBoard{Settings: settings, Items: items}, sync, now)
+	if err != nil {
+		t.Fatalf("buildWlBoardStatus() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeWLJSON(&buf, status, false); err != nil {
+		t.Fatalf("writeWLJSON() error: %v", err)
+	}
+	want := `{"counts_by_status":{"claimed":2,"completed":0,"draft":0,"in_review":0,"open":2,"withdrawn":0},` +
+		`"total":4,"oldest_open_id":"w-old","oldest_open_age_seconds":180000,"expired_claims":1,` +
+		`"sync":{"state":"behind","clone_dir":"/town/wl-commons","ahead":0,"behind":3,"last_commit_at":"2026-10-01T11:00:00Z"},` +
+		`"generated_at":"2026-10-01T12:00:00Z"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestQueryItemStatus(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-open", Title: "Open"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-claimed", Title: "Claimed"})
+	_ = store.ClaimWanted("w-claimed", "rig-a")
+
+	var buf bytes.Buffer
+	for _, id := range []string{"w-open", "w-claimed"} {
+		item, err := queryItemStatus(store, id)
+		if err != nil {
+			t.Fatalf("queryItemStatus(%q) error: %v", id, err)
+		}
+		if err := writeWLJSON(&buf, item, false); err != nil {
+			t.Fatalf("writeWLJSON() error: %v", err)
+		}
+	}
+	want := `{"id":"w-open","status":"open","claimed_by":""}` + "\n" +
+		`{"id":"w-claimed","status":"claimed","claimed_by":"rig-a"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := queryItemStatus(store, "w-missing"); err == nil {
+		t.Error("queryItemStatus(missing) succeeded, want an error")
+	}
+}
+
+func TestQuerySyncState(t *testing.T) {
+	orig := buildWlDoltCmd
+	t.Cleanup(func() { buildWlDoltCmd = orig })
+
+	if got := querySyncState(""); got.State != wlSyncNoClone {
+		t.Errorf("no clone: state = %q, want %q", got.State, wlSyncNoClone)
+	}
+
+	buildWlDoltCmd = func(dir string, args ...string) *exec.Cmd {
+		return exec.Command("printf", `ahead,behind,last_commit\n2,1,2026-10-01T11:00:00Z\n`)
+	}
+	got := querySyncState("/clone")
+	if got.State != wlSyncDiverged || got.Ahead != 2 || got.Behind != 1 || got.LastCommitAt != "2026-10-01T11:00:00Z" {
+		t.Errorf("querySyncState() = %+v, want diverged 2/1", got)
+	}
+
+	buildWlDoltCmd = func(dir string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "echo 'branch not found: upstream/main'; exit 1")
+	}
+	got = querySyncState("/clone")
+	if got.State != wlSyncUnknown || got.Error != "branch not found: upstream/main" {
+		t.Errorf("querySyncState() on failure = %+v, want unknown with the dolt error", got)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// defaultWatcherAddress is where a town receives watcher notifications
+// unless --address says otherwise.
+const defaultWatcherAddress = "mayor/"
+
+var wlSubscribeAddress string
+
+var wlSubscribeCmd = &cobra.Command{
+	Use:   "subscribe <wanted-id>",
+	Short: "Get notified when a wanted item changes",
+	Long: `Subscribe your rig to a wanted item you did not claim.
+
+When a claim or completion changes the item's status, the rig making the
+change mails every subscriber. One message is sent with each subscriber as
+a recipient, and each recipient's ack is tracked separately. Notifications
+go to the mayor unless --address names another mail address. Subscribing
+again updates the address.
+
+Notification failures never fail the claim or completion; they are
+reported as warnings.
+
+Examples:
+  gt wl subscribe w-abc123
+  gt wl subscribe w-abc123 --address gastown/crew/max
+  gt wl unsubscribe w-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlSubscribe,
+}
+
+var wlUnsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <wanted-id>",
+	Short: "Stop notifications for a wanted item",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWlUnsubscribe,
+}
+
+func init() {
+	wlSubscribeCmd.Flags().StringVar(&wlSubscribeAddress, "address", defaultWatcherAddress, "Mail address that receives notifications")
+
+	wlCmd.AddCommand(wlSubscribeCmd)
+	wlCmd.AddCommand(wlUnsubscribeCmd)
+}
+
+func runWlSubscribe(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	store, rigHandle, err := openWlWatchStore()
+	if err != nil {
+		return err
+	}
+	if err := subscribeWanted(store, wantedID, rigHandle, wlSubscribeAddress); err != nil {
+		return err
+	}
+	fmt.Printf("%s Subscribed to %s\n", wlSuccessMark(), wantedID)
+	fmt.Printf("  Notifications: %s\n", wlSubscribeAddress)
+	return nil
+}
+
+func runWlUnsubscribe(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+	store, rigHandle, err := openWlWatchStore()
+	if err != nil {
+		return err
+	}
+	if err := store.RemoveWatcher(wantedID, rigHandle); err != nil {
+		return err
+	}
+	fmt.Printf("%s Unsubscribed from %s\n", wlSuccessMark(), wantedID)
+	return nil
+}
+
+func openWlWatchStore() (doltserver.WLCommonsStore, string, error) {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return nil, "", err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := wlRun.requireCommons(); err != nil {
+		return nil, "", err
+	}
+
+	return doltserver.NewWLCommons(townRoot), wlCfg.RigHandle, nil
+}
+
+// subscribeWanted contains the testable business logic for subscribing.
+func subscribeWanted(store doltserver.WLCommonsStore, wantedID, rigHandle, address string) error {
+	// AddWatcher cannot distinguish an unknown item from a repeat
+	// subscription, so check the item exists first.
+	if _, err := store.QueryWanted(wantedID); err != nil {
+		return fmt.Errorf("querying wanted item: %w", err)
+	}
+	if err := store.AddWatcher(wantedID, rigHandle, address); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sendWlNotification delivers a watcher notification. It is a var so tests
+// can capture messages instead of invoking bd. A dry run sends nothing.
+var sendWlNotification = func(townRoot string, msg *mail.Message) error {
+	if doltserver.WLDryRun() {
+		return nil
+	}
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	defer router.WaitPendingNotifications()
+	return router.Send(msg)
+}
+
+// notifyWatchers mails every rig subscribed to wantedID about change, made
+// by actor. Callers invoke it only after the board write has committed. It
+// is best-effort: failures are printed as warnings, recorded in the claim
+// log as a "notify" error, and never fail the calling command.
+func notifyWatchers(store doltserver.WLCommonsStore, townRoot, wantedID, actor, change string) {
+	watchers, err := store.QueryWatchers(wantedID)
+	if err != nil {
+		err = fmt.Errorf("loading watchers: %w", err)
+		style.PrintWarning("%s was %s, but watchers were not notified: %v", wantedID, change, err)
+		recordWlAction(townRoot, wantedID, "notify", err)
+		return
+	}
+	sendWatcherNotification(townRoot, wantedID, actor, change, watchers)
+}
+
+// sendWatcherNotification mails watchers about change, warning and
+// recording a "notify" error in the claim log if that fails.
+func sendWatcherNotification(townRoot, wantedID, actor, change string, watchers []doltserver.WantedWatcher) {
+	msg := buildWatcherNotification(wantedID, actor, change, watchers)
+	if msg == nil {
+		return
+	}
+	if err := sendWlNotification(townRoot, msg); err != nil {
+		err = fmt.Errorf("notifying %d watcher(s): %w", 1+len(msg.CC), err)
+		style.PrintWarning("%s was %s, but watchers were not notified: %v", wantedID, change, err)
+		recordWlAction(townRoot, wantedID, "notify", err)
+	}
+}
+
+// buildWatcherNotification returns one fan-out message addressed to every
+// watcher except actor, or nil when nobody needs to hear about the change.
+func buildWatcherNotification(wantedID, actor, change string, watchers []doltserver.WantedWatcher) *mail.Message {
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, w := range watchers {
+		if w.RigHandle == actor || w.Address == "" || seen[w.Address] {
+			continue
+		}
+		seen[w.Address] = true
+		addresses = append(addresses, w.Address)
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	return &mail.Message{
+		From:      defaultWatcherAddress,
+		To:        addresses[0],
+		CC:        addresses[1:],
+		Fanout:    true,
+		Subject:   fmt.Sprintf("[wl] %s %s", wantedID, change),
+		Body:      fmt.Sprintf("Wanted item %s was %s by %s.\n\nSee: gt wl show %s\nStop these: gt wl unsubscribe %s", wantedID, change, actor, wantedID, wantedID),
+		Type:      mail.TypeNotification,
+		ThreadID:  "wl-" + wantedID,
+		Timestamp: time.Now(),
+	}
+}
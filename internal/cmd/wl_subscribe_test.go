@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestSubscribeWanted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+
+	if err := subscribeWanted(store, "w-1", "rig-a", "mayor/"); err != nil {
+		t.Fatalf("subscribeWanted() error: %v", err)
+	}
+	// Re-subscribing updates the address rather than duplicating.
+	if err := subscribeWanted(store, "w-1", "rig-a", "gastown/crew/max"); err != nil {
+		t.Fatalf("subscribeWanted() again error: %v", err)
+	}
+	watchers, _ := store.QueryWatchers("w-1")
+	if len(watchers) != 1 || watchers[0].Address != "gastown/crew/max" {
+		t.Errorf("watchers = %+v", watchers)
+	}
+
+	detail, _ := store.QueryWantedDetail("w-1")
+	if got := buildWantedShowJSON(detail).Watchers; got != 1 {
+		t.Errorf("show watchers = %d, want 1", got)
+	}
+
+	if err := subscribeWanted(store, "w-missing", "rig-a", "mayor/"); err == nil {
+		t.Error("subscribeWanted() should fail for an unknown item")
+	}
+}
+
+func TestBuildWatcherNotification(t *testing.T) {
+	t.Parallel()
+	watchers := []doltserver.WantedWatcher{
+		{RigHandle: "rig-a", Address: "mayor/"},
+		{RigHandle: "rig-b", Address: "gastown/crew/max"},
+		{RigHandle: "rig-c", Address: "mayor/"},
+		{RigHandle: "actor", Address: "actor/mayor"},
+	}
+
+	msg := buildWatcherNotification("w-1", "actor", "claimed", watchers)
+	if msg == nil {
+		t.Fatal("buildWatcherNotification() = nil")
+	}
+	if msg.To != "mayor/" || len(msg.CC) != 1 || msg.CC[0] != "gastown/crew/max" {
+		t.Errorf("recipients = %q + %v, want deduped and actor excluded", msg.To, msg.CC)
+	}
+	if !msg.Fanout {
+		t.Error("notification should track per-recipient acks")
+	}
+	if !strings.Contains(msg.Subject, "w-1 claimed") {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+
+	if msg := buildWatcherNotification("w-1", "actor", "claimed", watchers[3:]); msg != nil {
+		t.Errorf("only the actor watching should send nothing, got %+v", msg)
+	}
+}
+
+func TestNotifyWatchers_FailureIsNonFatal(t *testing.T) {
+	orig := sendWlNotification
+	t.Cleanup(func() { sendWlNotification = orig })
+
+	var sent []*mail.Message
+	sendWlNotification = func(townRoot string, msg *mail.Message) error {
+		sent = append(sent, msg)
+		return fmt.Errorf("bd unavailable")
+	}
+
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "One"})
+	_ = store.AddWatcher("w-1", "rig-a", "mayor/")
+
+	// Must not panic or surface the error; the claim already succeeded.
+	notifyWatchers(store, t.TempDir(), "w-1", "rig-b", "claimed")
+	if len(sent) != 1 {
+		t.Errorf("sent %d messages, want 1", len(sent))
+	}
+
+	store.WatchersErr = fmt.Errorf("table missing")
+	notifyWatchers(store, t.TempDir(), "w-1", "rig-b", "claimed")
+	if len(sent) != 1 {
+		t.Errorf("watcher lookup failure should send nothing, sent %d", len(sent))
+	}
+}
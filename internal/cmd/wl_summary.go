@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// runWlSummary is bare gt wl: a one-line count of wanted items per status,
+// for a quick pulse of the board. Outside a joined town it prints help
+// instead; an unknown subcommand is still an error.
+func runWlSummary(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return requireSubcommand(cmd, args)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil || !wlRun.commonsExists() {
+		return cmd.Help()
+	}
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	counts, err := doltserver.QueryStatusCounts(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatStatusCounts(counts))
+	fmt.Println(style.Dim.Render("Run 'gt wl --help' for commands."))
+	return nil
+}
+
+// formatStatusCounts renders counts as "open: 12  claimed: 3", most common
+// status first.
+func formatStatusCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "The wanted board is empty."
+	}
+	parts := make([]string, 0, len(counts))
+	for _, s := range sortedStatusKeys(counts) {
+		parts = append(parts, fmt.Sprintf("%s: %d", s, counts[s]))
+	}
+	return strings.Join(parts, "  ")
+}
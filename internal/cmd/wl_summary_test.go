@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatStatusCounts(t *testing.T) {
+	got := formatStatusCounts(map[string]int{"claimed": 3, "open": 12, "in_review": 1, "draft": 1})
+	if want := "open: 12  claimed: 3  draft: 1  in_review: 1"; got != want {
+		t.Errorf("formatStatusCounts() = %q, want %q", got, want)
+	}
+	if got := formatStatusCounts(nil); got != "The wanted board is empty." {
+		t.Errorf("formatStatusCounts(nil) = %q", got)
+	}
+}
+
+func TestRunWlSummary_UnknownSubcommand(t *testing.T) {
+	err := runWlSummary(wlCmd, []string{"nope"})
+	if err == nil || !strings.Contains(err.Error(), `unknown command "nope"`) {
+		t.Errorf("runWlSummary(nope) = %v, want unknown command error", err)
+	}
+}
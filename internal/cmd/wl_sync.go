@@ -8,8 +8,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/wasteland"
-	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var wlSyncDryRun bool
@@ -24,8 +22,17 @@ var wlSyncCmd = &cobra.Command{
 If you have a local fork of wl-commons (created by gt wl join), this pulls
 the latest changes from upstream.
 
+Against a remote server (GT_DOLT_HOST), a claim, completion, or other
+board write that cannot reach the server after its retries is queued in
+.wasteland/write-queue.jsonl instead of being lost. Sync replays queued
+writes first, oldest first, with NOW() pinned to when each was queued. A
+write the board has since made moot (say, an item someone else claimed
+meanwhile) is dropped; any other failure stops the replay, leaving that
+write and the rest queued, and the pull is skipped. --dry-run lists the
+queue without replaying it.
+
 EXAMPLES:
-  gt wl sync                # Pull upstream changes
+  gt wl sync                # Replay queued writes, then pull upstream changes
   gt wl sync --dry-run      # Show what would change`,
 }
 
@@ -36,9 +43,13 @@ func init() {
 }
 
 func runWLSync(cmd *cobra.Command, args []string) error {
-	townRoot, err := workspace.FindFromCwdOrError()
+	townRoot, err := wlRun.townRoot()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		return err
+	}
+
+	if err := replayWLWriteQueue(os.Stdout, townRoot, wlSyncDryRun); err != nil {
+		return err
 	}
 
 	doltPath, err := exec.LookPath("dolt")
@@ -48,7 +59,7 @@ func runWLSync(cmd *cobra.Command, args []string) error {
 
 	// Try loading wasteland config first (set by gt wl join)
 	forkDir := ""
-	if cfg, err := wasteland.LoadConfig(townRoot); err == nil {
+	if cfg, err := wlRun.wastelandConfig(); err == nil {
 		forkDir = cfg.LocalDir
 	}
 
@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func TestWlCommandRegistered(t *testing.T) {
@@ -46,7 +51,7 @@ func TestWlCommandGroup(t *testing.T) {
 }
 
 func TestWlSubcommands(t *testing.T) {
-	expected := []string{"join", "post", "claim", "done", "browse", "sync"}
+	expected := []string{"join", "post", "claim", "done", "browse", "sync", "archive", "restore", "comment", "show", "board-url", "completions", "heartbeat", "validate", "log", "subscribe", "unsubscribe", "stats", "merge-duplicates", "group", "export", "status", "whois", "undo-last", "reindex", "schema", "approve", "audit-evidence", "bench", "list", "unclaim", "accept", "reject", "reap", "mine", "reopen", "history", "search", "assign", "watch", "reviews"}
 	for _, name := range expected {
 		found := false
 		for _, c := range wlCmd.Commands() {
@@ -61,13 +66,16 @@ func TestWlSubcommands(t *testing.T) {
 	}
 }
 
-func TestWlClaimRequiresArg(t *testing.T) {
-	if err := wlClaimCmd.Args(wlClaimCmd, []string{}); err == nil {
-		t.Error("claim should require exactly 1 argument")
+func TestWlClaimArgs(t *testing.T) {
+	if err := wlClaimCmd.Args(wlClaimCmd, []string{}); err != nil {
+		t.Errorf("claim should accept no arguments (auto-claim): %v", err)
 	}
 	if err := wlClaimCmd.Args(wlClaimCmd, []string{"w-abc123"}); err != nil {
 		t.Errorf("claim should accept 1 argument: %v", err)
 	}
+	if err := wlClaimCmd.Args(wlClaimCmd, []string{"w-a", "w-b"}); err != nil {
+		t.Errorf("claim should accept several arguments (batch): %v", err)
+	}
 }
 
 func TestWlDoneRequiresArg(t *testing.T) {
@@ -90,3 +98,36 @@ func TestWlSyncNoArgs(t *testing.T) {
 		t.Errorf("sync should accept 0 arguments: %v", err)
 	}
 }
+
+func TestReadArchiveFile_RejectsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte(`{"format":"other","version":1,"tables":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := readArchiveFile(path)
+	if err == nil || !strings.Contains(err.Error(), "not a wl-commons archive") {
+		t.Errorf("readArchiveFile() error = %v, want format error", err)
+	}
+}
+
+func TestEnsureWlJoined(t *testing.T) {
+	townRoot := t.TempDir()
+
+	// Without the flag, a missing database never triggers a join.
+	if err := ensureWlJoined(townRoot, ""); err != nil {
+		t.Errorf("ensureWlJoined(\"\") = %v, want nil", err)
+	}
+
+	// With the flag, the join runs and its failures are attributed.
+	t.Setenv("DOLTHUB_TOKEN", "")
+	err := ensureWlJoined(townRoot, "steveyegge/wl-commons")
+	if err == nil || !strings.Contains(err.Error(), "--ensure-joined") || !strings.Contains(err.Error(), "DOLTHUB_TOKEN") {
+		t.Errorf("ensureWlJoined() = %v, want --ensure-joined DOLTHUB_TOKEN error", err)
+	}
+
+	for _, c := range []*cobra.Command{wlClaimCmd, wlDoneCmd} {
+		if c.Flags().Lookup("ensure-joined") == nil {
+			t.Errorf("%s missing --ensure-joined flag", c.Name())
+		}
+	}
+}
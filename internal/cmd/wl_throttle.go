@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// wlSettingWritesPerMinute is the wasteland setting that caps how often a
+// town writes to the board. --throttle overrides it; 0 or unset disables
+// throttling.
+const wlSettingWritesPerMinute = "client.writes_per_minute"
+
+// wlThrottleFile holds the town's token bucket in the wasteland directory,
+// so separate gt invocations (e.g. a worker loop) share one budget.
+const wlThrottleFile = "throttle.json"
+
+var wlThrottle float64
+
+func init() {
+	wlCmd.PersistentFlags().Float64Var(&wlThrottle, "throttle", 0, "Limit board writes to N per minute, waiting when over (overrides "+wlSettingWritesPerMinute+")")
+}
+
+// tokenBucket is a token-bucket rate limiter. It holds up to Burst tokens,
+// refills at Rate tokens per second, and each operation takes one token.
+// Tokens may go negative: a reservation made while the bucket is empty is
+// queued behind the ones before it.
+type tokenBucket struct {
+	Rate   float64   `json:"rate"`
+	Burst  float64   `json:"burst"`
+	Tokens float64   `json:"tokens"`
+	Last   time.Time `json:"last"`
+}
+
+// reserve takes a token at now and returns how long the caller must wait
+// before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.Last.IsZero() {
+		b.Tokens = b.Burst
+	} else if elapsed := now.Sub(b.Last).Seconds(); elapsed > 0 {
+		b.Tokens += elapsed * b.Rate
+		if b.Tokens > b.Burst {
+			b.Tokens = b.Burst
+		}
+	}
+	if now.After(b.Last) {
+		b.Last = now
+	}
+	b.Tokens--
+	if b.Tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.Tokens / b.Rate * float64(time.Second))
+}
+
+// wlWritesPerMinute resolves the write rate: --throttle when given,
+// otherwise the wasteland setting. Zero means unthrottled.
+func wlWritesPerMinute(settings map[string]string) (float64, error) {
+	if wlThrottle < 0 {
+		return 0, fmt.Errorf("--throttle must be >= 0, got %g", wlThrottle)
+	}
+	if wlThrottle > 0 {
+		return wlThrottle, nil
+	}
+	raw := strings.TrimSpace(settings[wlSettingWritesPerMinute])
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s setting %q: must be a non-negative number", wlSettingWritesPerMinute, raw)
+	}
+	return n, nil
+}
+
+// throttleWlWrite waits until the town may make another board write, per
+// wlWritesPerMinute. It returns early with an error when ctx is done or its
+// deadline falls before the write would be allowed. A dry run never waits.
+func throttleWlWrite(ctx context.Context, store doltserver.WLCommonsStore, townRoot string) error {
+	if doltserver.WLDryRun() {
+		return nil
+	}
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return fmt.Errorf("loading wasteland settings: %w", err)
+	}
+	perMinute, err := wlWritesPerMinute(settings)
+	if err != nil || perMinute == 0 {
+		return err
+	}
+	wait, err := reserveWlWrite(filepath.Join(wasteland.WastelandDir(townRoot), wlThrottleFile), perMinute/60, time.Now())
+	if err != nil {
+		return err
+	}
+	return waitThrottle(ctx, wait)
+}
+
+// reserveWlWrite takes a token from the bucket persisted at path, creating
+// it with a burst of one, and returns how long to wait.
+func reserveWlWrite(path string, rate float64, now time.Time) (time.Duration, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("creating throttle directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return 0, fmt.Errorf("acquiring throttle lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	var b tokenBucket
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("reading throttle state: %w", err)
+	}
+	if len(data) > 0 && json.Unmarshal(data, &b) != nil {
+		b = tokenBucket{} // corrupt state: start with a full bucket
+	}
+	// A changed rate applies from now on; tokens already owed are kept.
+	b.Rate, b.Burst = rate, 1
+	wait := b.reserve(now)
+
+	if data, err = json.Marshal(b); err != nil {
+		return 0, fmt.Errorf("marshaling throttle state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: throttle state is non-sensitive
+		return 0, fmt.Errorf("writing throttle state: %w", err)
+	}
+	return wait, nil
+}
+
+// waitThrottle sleeps for wait unless ctx ends first. A deadline that falls
+// before wait elapses fails immediately rather than sleeping in vain.
+func waitThrottle(ctx context.Context, wait time.Duration) error {
+	if wait <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return fmt.Errorf("throttled: next board write allowed in %s, after the command deadline", wait.Round(time.Second))
+	}
+	fmt.Fprintf(os.Stderr, "Throttled: waiting %s before writing to the board\n", wait.Round(time.Second))
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("throttled: %w", ctx.Err())
+	}
+}
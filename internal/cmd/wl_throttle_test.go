@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_SpacesOutOperations(t *testing.T) {
+	t.Parallel()
+	b := &tokenBucket{Rate: 0.5, Burst: 2} // one token every 2s
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// A full bucket absorbs the burst, then each reservation queues 2s
+	// behind the previous one.
+	for i, want := range []time.Duration{0, 0, 2 * time.Second, 4 * time.Second} {
+		if got := b.reserve(t0); got != want {
+			t.Errorf("reserve #%d at t0 = %s, want %s", i+1, got, want)
+		}
+	}
+
+	// After the queue drains and the bucket refills, writes go straight through.
+	if got := b.reserve(t0.Add(10 * time.Second)); got != 0 {
+		t.Errorf("reserve after refill = %s, want 0", got)
+	}
+}
+
+func TestReserveWlWrite_SharedAcrossInvocations(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), wlThrottleFile)
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rate := 6.0 / 60 // 6 writes per minute
+
+	var waits []time.Duration
+	for _, at := range []time.Duration{0, time.Second, 2 * time.Second, 30 * time.Second} {
+		wait, err := reserveWlWrite(path, rate, t0.Add(at))
+		if err != nil {
+			t.Fatalf("reserveWlWrite() error: %v", err)
+		}
+		waits = append(waits, wait.Round(time.Millisecond))
+	}
+	want := []time.Duration{0, 9 * time.Second, 18 * time.Second, 0}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("waits = %v, want %v", waits, want)
+			break
+		}
+	}
+}
+
+func TestWaitThrottle_RespectsDeadline(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := waitThrottle(ctx, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "deadline") {
+		t.Fatalf("waitThrottle() error = %v, want deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waitThrottle() slept %s before failing, want immediate", elapsed)
+	}
+
+	if err := waitThrottle(context.Background(), 10*time.Millisecond); err != nil {
+		t.Errorf("waitThrottle() short wait error: %v", err)
+	}
+}
+
+func TestWlWritesPerMinute(t *testing.T) {
+	old := wlThrottle
+	t.Cleanup(func() { wlThrottle = old })
+
+	wlThrottle = 0
+	if got, err := wlWritesPerMinute(map[string]string{}); err != nil || got != 0 {
+		t.Errorf("unset = %g, %v; want 0 (unthrottled)", got, err)
+	}
+	if got, err := wlWritesPerMinute(map[string]string{wlSettingWritesPerMinute: "12"}); err != nil || got != 12 {
+		t.Errorf("setting = %g, %v; want 12", got, err)
+	}
+	if _, err := wlWritesPerMinute(map[string]string{wlSettingWritesPerMinute: "fast"}); err == nil {
+		t.Error("unparseable setting should fail")
+	}
+	wlThrottle = 3
+	if got, err := wlWritesPerMinute(map[string]string{wlSettingWritesPerMinute: "12"}); err != nil || got != 3 {
+		t.Errorf("--throttle = %g, %v; want 3 (flag overrides setting)", got, err)
+	}
+}
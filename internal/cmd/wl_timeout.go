@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// wlTimeout is the --timeout flag shared by gt wl subcommands. Zero leaves
+// the limit to GT_DOLT_TIMEOUT or the built-in defaults. audit-evidence
+// shadows it with its own --timeout.
+var wlTimeout time.Duration
+
+func init() {
+	wlCmd.PersistentFlags().DurationVar(&wlTimeout, "timeout", 0,
+		fmt.Sprintf("Limit on each dolt SQL call (default: $%s, else %s for queries and %s for writes)",
+			doltserver.SQLTimeoutEnv, doltserver.DefaultSQLQueryTimeout, doltserver.DefaultSQLScriptTimeout))
+}
+
+// selectWLSQLTimeout applies --timeout to every dolt SQL call the command
+// makes.
+func selectWLSQLTimeout() error {
+	if wlTimeout < 0 {
+		return fmt.Errorf("--timeout must be positive, got %s", wlTimeout)
+	}
+	doltserver.SetSQLTimeout(wlTimeout)
+	return nil
+}
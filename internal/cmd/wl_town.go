@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// wlTownEnv names the environment variable holding the town handle for
+// wl commands run outside a workspace.
+const wlTownEnv = "GASTOWN_TOWN"
+
+// wlHandlePattern matches a town handle: a DoltHub org or a handle chosen
+// with gt wl join --handle.
+var wlHandlePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// findWlTownRoot locates the workspace. Tests override it.
+var findWlTownRoot = workspace.FindFromCwdOrError
+
+// wlTownHandleFallback reads the town handle from townFile, or from
+// $GASTOWN_TOWN when no file is given. It returns "" when neither is set.
+func wlTownHandleFallback(townFile string) (string, error) {
+	source, handle := "$"+wlTownEnv, os.Getenv(wlTownEnv)
+	if townFile != "" {
+		data, err := os.ReadFile(townFile)
+		if err != nil {
+			return "", fmt.Errorf("reading town file: %w", err)
+		}
+		source, handle = townFile, string(data)
+	}
+	handle = strings.TrimSpace(handle)
+	if handle == "" {
+		if townFile != "" {
+			return "", fmt.Errorf("town file %s is empty", townFile)
+		}
+		return "", nil
+	}
+	if !wlHandlePattern.MatchString(handle) {
+		return "", fmt.Errorf("invalid town handle %q in %s: use letters, digits, '-' and '_' (at most 64)", handle, source)
+	}
+	return handle, nil
+}
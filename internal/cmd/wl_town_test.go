@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubWlTownRoot makes workspace resolution fail for the test.
+func stubWlTownRoot(t *testing.T) {
+	t.Helper()
+	orig := findWlTownRoot
+	findWlTownRoot = func() (string, error) { return "", errors.New("not in a workspace") }
+	t.Cleanup(func() { findWlTownRoot = orig })
+}
+
+// handleRunContext returns a run context that may fall back to townFile.
+func handleRunContext(townFile string) *wlRunContext {
+	c := newWlRunContext()
+	c.allowTownHandle(townFile)
+	return c
+}
+
+func TestWlRunContext_FallbackToTownFile(t *testing.T) {
+	stubWlTownRoot(t)
+	t.Setenv(wlTownEnv, "env-rig")
+	path := filepath.Join(t.TempDir(), "town")
+	if err := os.WriteFile(path, []byte("file-rig\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := handleRunContext(path)
+	townRoot, err := c.townRoot()
+	if err != nil {
+		t.Fatalf("townRoot() error: %v", err)
+	}
+	cwd, _ := os.Getwd()
+	if townRoot != cwd {
+		t.Errorf("townRoot() = %q, want the working directory %s", townRoot, cwd)
+	}
+	cfg, err := c.wastelandConfig()
+	if err != nil || cfg.RigHandle != "file-rig" {
+		t.Errorf("wastelandConfig() = %+v, %v; want RigHandle file-rig", cfg, err)
+	}
+}
+
+func TestWlRunContext_FallbackToEnv(t *testing.T) {
+	stubWlTownRoot(t)
+	t.Setenv(wlTownEnv, "env-rig")
+
+	c := handleRunContext("")
+	cfg, err := c.wastelandConfig()
+	if err != nil || cfg.RigHandle != "env-rig" {
+		t.Errorf("wastelandConfig() = %+v, %v; want RigHandle env-rig", cfg, err)
+	}
+}
+
+func TestWlRunContext_FallbackOnlyWhenAllowed(t *testing.T) {
+	stubWlTownRoot(t)
+	t.Setenv(wlTownEnv, "env-rig")
+
+	if _, err := newWlRunContext().townRoot(); err == nil || !strings.Contains(err.Error(), "not in a Gas Town workspace") {
+		t.Errorf("townRoot() without allowTownHandle = %v, want the workspace error", err)
+	}
+}
+
+func TestWlRunContext_FallbackErrors(t *testing.T) {
+	stubWlTownRoot(t)
+	t.Setenv(wlTownEnv, "")
+
+	if _, err := handleRunContext("").townRoot(); err == nil || !strings.Contains(err.Error(), "not in a Gas Town workspace") {
+		t.Errorf("townRoot() with no fallback = %v, want the workspace error", err)
+	}
+
+	t.Setenv(wlTownEnv, "bad handle;")
+	if _, err := handleRunContext("").townRoot(); err == nil || !strings.Contains(err.Error(), "invalid town handle") {
+		t.Errorf("townRoot() with a bad handle = %v, want invalid town handle", err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "town")
+	if err := os.WriteFile(empty, []byte("  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handleRunContext(empty).townRoot(); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("townRoot() with an empty file = %v, want is empty", err)
+	}
+}
+
+func TestWlRunContext_HandleTownWithoutCommons(t *testing.T) {
+	stubWlTownRoot(t)
+	t.Setenv(wlTownEnv, "env-rig")
+	t.Chdir(t.TempDir())
+
+	err := handleRunContext("").requireCommons()
+	if err == nil || !strings.Contains(err.Error(), "Join a wasteland first") {
+		t.Errorf("requireCommons() = %v, want database not found", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlUnclaimForce    bool
+	wlUnclaimHoldFile string
+)
+
+var wlUnclaimCmd = &cobra.Command{
+	Use:   "unclaim <wanted-id>",
+	Short: "Release a claim and return the item to the board",
+	Long: `Release your claim on a wanted item so another rig can pick it up.
+
+The item must be claimed, and claimed by this rig (directly or for a group
+this rig belongs to). It goes back to open with no claimant and no lease.
+Items in review cannot be unclaimed: a completion has been submitted, so
+the poster reviews it instead.
+
+--force releases a claim held by another rig, to clean up abandoned work.
+The forced release is recorded in the item's history.
+
+--hold-file <path> removes the marker written by gt wl claim --hold-file
+once the claim is released.
+
+Examples:
+  gt wl unclaim w-abc123
+  gt wl unclaim w-abc123 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlUnclaim,
+}
+
+func init() {
+	wlUnclaimCmd.Flags().BoolVar(&wlUnclaimForce, "force", false, "Release a claim held by another rig")
+	wlUnclaimCmd.Flags().StringVar(&wlUnclaimHoldFile, "hold-file", "", "Remove this gt wl claim --hold-file marker after releasing")
+
+	wlCmd.AddCommand(wlUnclaimCmd)
+}
+
+func runWlUnclaim(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	wlCfg, err := wlRun.wastelandConfig()
+	if err != nil {
+		return err
+	}
+	rigHandle := wlCfg.RigHandle
+
+	if err := wlRun.requireCommons(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	if err := throttleWlWrite(cmd.Context(), store, townRoot); err != nil {
+		return err
+	}
+
+	var item *doltserver.WantedItem
+	err = commitThenNotify(store, townRoot, wantedID, rigHandle, "unclaim", "unclaimed", func() error {
+		var err error
+		item, err = unclaimWanted(store, wantedID, rigHandle, wlUnclaimForce)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if wlUnclaimHoldFile != "" && !doltserver.WLDryRun() {
+		if err := releaseWlHoldFile(wlUnclaimHoldFile, wantedID); err != nil {
+			style.PrintWarning("%v", err)
+		}
+	}
+
+	if wlJSON {
+		return writeWLJSON(os.Stdout, wlItemResultJSON{ID: wantedID, Title: item.Title, Status: doltserver.StatusOpen}, wlJSONPrettyOutput())
+	}
+	fmt.Printf("%s Released %s\n", wlSuccessMark(), style.Bold.Render(wantedID))
+	fmt.Printf("  Title: %s\n", item.Title)
+	if item.ClaimedBy != rigHandle {
+		fmt.Printf("  Was claimed by: %s\n", item.ClaimedBy)
+	}
+	fmt.Printf("  Status: %s\n", doltserver.StatusOpen)
+	return nil
+}
+
+// unclaimWanted checks that rigHandle may release wantedID, then returns it
+// to the board. It returns the item as it was before the release.
+func unclaimWanted(store doltserver.WLCommonsStore, wantedID, rigHandle string, force bool) (*doltserver.WantedItem, error) {
+	item, err := store.QueryWanted(wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("querying wanted item: %w", err)
+	}
+	if err := requireTransition(store, wantedID, item.Status, doltserver.StatusOpen); err != nil {
+		return nil, err
+	}
+	// The model may send other statuses back to open too (a rejected or
+	// reopened item), but only a claim is released here.
+	if item.Status == doltserver.StatusInReview {
+		return nil, fmt.Errorf("%s is in review: a completion has already been submitted, so it cannot be unclaimed", wantedID)
+	}
+	if item.Status != doltserver.StatusClaimed {
+		return nil, fmt.Errorf("%s is %s, not claimed", wantedID, item.Status)
+	}
+
+	if !force {
+		if err := requireClaimHolder(store, item, rigHandle); err != nil {
+			return nil, fmt.Errorf("%w; use --force to release abandoned work", err)
+		}
+	}
+
+	if err := store.UnclaimWanted(wantedID, rigHandle, force); err != nil {
+		return nil, fmt.Errorf("releasing claim: %w", err)
+	}
+	return item, nil
+}
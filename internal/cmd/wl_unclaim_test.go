@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestUnclaimWanted(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Mine"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+
+	if _, err := unclaimWanted(store, "w-1", "other-rig", false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("unclaim by another rig error = %v, want a hint at --force", err)
+	}
+
+	before, err := unclaimWanted(store, "w-1", "my-rig", false)
+	if err != nil {
+		t.Fatalf("unclaimWanted() error: %v", err)
+	}
+	if before.ClaimedBy != "my-rig" {
+		t.Errorf("returned item claimed by %q, want the pre-release claimant", before.ClaimedBy)
+	}
+	item, _ := store.QueryWanted("w-1")
+	if item.Status != doltserver.StatusOpen || item.ClaimedBy != "" {
+		t.Errorf("after unclaim: status %q claimed by %q", item.Status, item.ClaimedBy)
+	}
+
+	if _, err := unclaimWanted(store, "w-1", "my-rig", false); err == nil || !strings.Contains(err.Error(), "cannot move from open to open") {
+		t.Errorf("unclaim of an open item error = %v", err)
+	}
+}
+
+func TestUnclaimWanted_Force(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Abandoned"})
+	_ = store.ClaimWanted("w-1", "gone-rig")
+
+	if _, err := unclaimWanted(store, "w-1", "janitor", true); err != nil {
+		t.Fatalf("forced unclaimWanted() error: %v", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusOpen {
+		t.Errorf("status = %q, want open", item.Status)
+	}
+}
+
+func TestUnclaimWanted_RefusesInReview(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Submitted"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+	_ = store.SubmitCompletion("c-1", "w-1", "my-rig", "https://example.com/pr/1")
+
+	_, err := unclaimWanted(store, "w-1", "my-rig", true)
+	if err == nil || !strings.Contains(err.Error(), "in review") {
+		t.Errorf("unclaim in review error = %v, want a refusal", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusInReview {
+		t.Errorf("status = %q, want in_review untouched", item.Status)
+	}
+}
+
+func TestUnclaimWanted_ChecksStatusModel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-1", Title: "Held"})
+	_ = store.ClaimWanted("w-1", "my-rig")
+	store.settings[doltserver.SettingWorkflowTransitions] = "open>claimed,claimed>in_review"
+
+	if _, err := unclaimWanted(store, "w-1", "my-rig", false); err == nil || !strings.Contains(err.Error(), "cannot move from claimed to open") {
+		t.Errorf("unclaim forbidden by the model error = %v", err)
+	}
+	if item, _ := store.QueryWanted("w-1"); item.Status != doltserver.StatusClaimed {
+		t.Errorf("status = %q, want claimed untouched", item.Status)
+	}
+}
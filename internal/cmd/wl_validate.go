@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var wlValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the local wl-commons schema against what gt expects",
+	Long: `Check that the local wl-commons database has every table and column
+this version of gt relies on.
+
+Databases joined with an older gt can lack columns added since (for example
+wanted.timeout_action or completions.superseded_by), which otherwise surface
+as cryptic SQL errors mid-command. For each missing table or column, the
+SQL that would add it is printed so it can be applied with 'dolt sql' in the
+wl-commons clone.
+
+Other gt wl commands that read the commons run the same check once per
+invocation and stop with the list of missing columns, pointing here.
+
+Extra tables and columns are ignored. Exits non-zero when the schema is
+incompatible.
+
+Examples:
+  gt wl validate`,
+	Args: cobra.NoArgs,
+	RunE: runWlValidate,
+}
+
+func init() {
+	wlCmd.AddCommand(wlValidateCmd)
+}
+
+func runWlValidate(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireJoined(); err != nil {
+		return err
+	}
+
+	issues, err := doltserver.ValidateWLCommonsSchema(townRoot)
+	if err != nil {
+		return err
+	}
+	return reportSchemaIssues(os.Stdout, issues)
+}
+
+// reportSchemaIssues prints schema issues with their suggested fixes and
+// returns an error when there are any, so the command exits non-zero.
+func reportSchemaIssues(w io.Writer, issues []doltserver.SchemaIssue) error {
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "%s Schema is compatible\n", style.Bold.Render("✓"))
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s Schema is incompatible (%d issue(s)):\n\n", style.Bold.Render("✗"), len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(w, "  %s\n", issue)
+		fmt.Fprintf(w, "    %s\n", style.Dim.Render("fix: "+issue.Fix))
+	}
+	fmt.Fprintf(w, "\nApply the fixes with 'dolt sql' in the wl-commons clone, then commit.\n")
+	return fmt.Errorf("wl-commons schema is missing %d table(s)/column(s)", len(issues))
+}
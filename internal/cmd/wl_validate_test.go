@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestReportSchemaIssues(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := reportSchemaIssues(&buf, nil); err != nil {
+		t.Fatalf("compatible schema returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "compatible") {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	buf.Reset()
+	err := reportSchemaIssues(&buf, []doltserver.SchemaIssue{
+		{Table: "wanted", Column: "expires_at", Fix: "ALTER TABLE wanted ADD COLUMN `expires_at` TIMESTAMP;"},
+		{Table: "badges", Fix: "CREATE TABLE IF NOT EXISTS badges (...);"},
+	})
+	if err == nil {
+		t.Fatal("incompatible schema should return an error")
+	}
+	out := buf.String()
+	for _, want := range []string{"missing column wanted.expires_at", "missing table badges", "ALTER TABLE wanted ADD COLUMN"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// wlVerbose is the --verbose flag shared by gt wl subcommands.
+var wlVerbose bool
+
+func init() {
+	wlCmd.PersistentFlags().BoolVarP(&wlVerbose, "verbose", "v", false, "Trace each dolt invocation (argv, server config, output on failure) to stderr")
+}
+
+// selectWLVerbose traces the command's dolt invocations to stderr under
+// --verbose, starting with the resolved server config, and keeps them
+// quiet otherwise.
+func selectWLVerbose() {
+	if !wlVerbose {
+		doltserver.SetDoltLogger(nil)
+		return
+	}
+	logger := newWLVerboseLogger(os.Stderr)
+	doltserver.SetDoltLogger(logger)
+	if cfg, err := wlRun.doltConfig(); err == nil {
+		logger.Debug("dolt config", slog.Any("config", cfg))
+	}
+}
+
+// newWLVerboseLogger returns the debug-level logger --verbose writes to w.
+func newWLVerboseLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
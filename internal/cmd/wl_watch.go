@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// defaultWatchInterval is how often gt wl watch polls the board.
+const defaultWatchInterval = 30 * time.Second
+
+var (
+	wlWatchInterval time.Duration
+	wlWatchStatus   string
+	wlWatchMine     bool
+)
+
+var wlWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print a line whenever a wanted item changes status",
+	Long: `Poll the wanted board and print a line each time an item's status
+changes, so an agent can react when new work is posted or an item it cares
+about moves on.
+
+The first poll only records the board; after that, every --interval, each
+item whose status differs from the previous poll is printed with its old
+and new status. New items show as "new". With --mine, only items this town
+holds a claim on are watched, and one that leaves that set (released,
+reaped, reopened) is printed with the status it left for.
+
+--status keeps only changes into or out of that status, so
+--status open reports items as they are posted and as they are claimed.
+
+With --json, each change is written as one JSON object per line. A failed
+poll is reported as a warning and retried at the next interval. Ctrl-C
+stops watching.
+
+Examples:
+  gt wl watch
+  gt wl watch --status open --interval 1m
+  gt wl watch --mine --json`,
+	Args: cobra.NoArgs,
+	RunE: runWlWatch,
+}
+
+func init() {
+	wlWatchCmd.Flags().DurationVar(&wlWatchInterval, "interval", defaultWatchInterval, "How often to poll the board")
+	wlWatchCmd.Flags().StringVar(&wlWatchStatus, "status", "", "Only report changes into or out of this status")
+	wlWatchCmd.Flags().BoolVar(&wlWatchMine, "mine", false, "Only watch items this town has claimed")
+
+	wlCmd.AddCommand(wlWatchCmd)
+}
+
+func runWlWatch(cmd *cobra.Command, args []string) error {
+	if wlWatchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %s", wlWatchInterval)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	if wlWatchStatus != "" {
+		if err := requireKnownStatus(store, wlWatchStatus); err != nil {
+			return err
+		}
+	}
+
+	w := &wlWatcher{store: store, status: wlWatchStatus}
+	if wlWatchMine {
+		wlCfg, err := wlRun.wastelandConfig()
+		if err != nil {
+			return err
+		}
+		w.heldBy = wlCfg.RigHandle
+	}
+
+	if !wlJSON {
+		fmt.Fprintf(os.Stderr, "Watching the wanted board every %s (Ctrl+C to stop)\n", wlWatchInterval)
+	}
+	return runWatchLoop(cmd.Context(), wlWatchInterval, w, os.Stdout)
+}
+
+// wlWatchEvent is one status change seen by gt wl watch, and its --json
+// line.
+type wlWatchEvent struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// From is empty for an item that is new since the last poll.
+	From string `json:"from"`
+	// To is empty for an item that no longer exists.
+	To        string `json:"to"`
+	ClaimedBy string `json:"claimed_by"`
+	// At is when the poll saw the change, in RFC 3339.
+	At string `json:"at"`
+}
+
+// wlWatcher remembers the status of every watched item between polls.
+type wlWatcher struct {
+	store doltserver.WLCommonsStore
+	// status, when set, keeps only changes into or out of it.
+	status string
+	// heldBy, when set, watches only items this rig holds a claim on.
+	heldBy string
+
+	seen   map[string]string
+	primed bool
+}
+
+// poll reads the board and returns the changes since the previous poll,
+// stamped with now. The first poll only records the board and returns
+// nothing. Items that left the watched set are looked up one by one to
+// find where they went.
+func (w *wlWatcher) poll(now time.Time) ([]wlWatchEvent, error) {
+	items, err := w.store.ListWanted(doltserver.WantedFilter{HeldBy: w.heldBy, MinPriority: -1, MaxPriority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing wanted items: %w", err)
+	}
+
+	at := now.UTC().Format(time.RFC3339)
+	current := make(map[string]string, len(items))
+	var events []wlWatchEvent
+	for _, item := range items {
+		current[item.ID] = item.Status
+		if from, ok := w.seen[item.ID]; w.primed && (!ok || from != item.Status) {
+			events = append(events, wlWatchEvent{ID: item.ID, Title: item.Title, From: from, To: item.Status, ClaimedBy: item.ClaimedBy, At: at})
+		}
+	}
+
+	var gone []string
+	for id := range w.seen {
+		if _, ok := current[id]; !ok {
+			gone = append(gone, id)
+		}
+	}
+	sort.Strings(gone)
+	for _, id := range gone {
+		event := wlWatchEvent{ID: id, From: w.seen[id], At: at}
+		if item, err := w.store.QueryWanted(id); err == nil {
+			event.Title, event.To, event.ClaimedBy = item.Title, item.Status, item.ClaimedBy
+		}
+		if event.To != event.From {
+			events = append(events, event)
+		}
+	}
+
+	w.seen, w.primed = current, true
+	if w.status == "" {
+		return events, nil
+	}
+	kept := events[:0]
+	for _, e := range events {
+		if e.From == w.status || e.To == w.status {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+// runWatchLoop polls w immediately and then every interval, writing each
+// change to out, until ctx is cancelled (returns nil). Only a failure of the
+// first poll is returned; later ones are warnings.
+func runWatchLoop(ctx context.Context, interval time.Duration, w *wlWatcher, out io.Writer) error {
+	if _, err := w.poll(time.Now()); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			events, err := w.poll(time.Now())
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				style.PrintWarning("poll failed, retrying in %s: %v", interval, err)
+				continue
+			}
+			for _, e := range events {
+				if err := writeWatchEvent(out, e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// writeWatchEvent prints e as a line of text, or of JSON under --json.
+func writeWatchEvent(out io.Writer, e wlWatchEvent) error {
+	if wlJSON {
+		return writeWLJSON(out, e, false)
+	}
+	from, to := e.From, e.To
+	if from == "" {
+		from = "new"
+	}
+	if to == "" {
+		to = "gone"
+	}
+	when := e.At
+	if t, err := time.Parse(time.RFC3339, e.At); err == nil {
+		when = t.Local().Format("15:04:05")
+	}
+	line := fmt.Sprintf("%s  %s  %s → %s  %s", when, style.Bold.Render(e.ID), from, to, e.Title)
+	if e.ClaimedBy != "" {
+		line += style.Dim.Render(" (" + e.ClaimedBy + ")")
+	}
+	_, err := fmt.Fprintln(out, line)
+	return err
+}
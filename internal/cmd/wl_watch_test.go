@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestWlWatcher_Poll(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "A"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-b", Title: "B"})
+	w := &wlWatcher{store: store}
+
+	if events, err := w.poll(now); err != nil || len(events) != 0 {
+		t.Fatalf("first poll = %v, %v; want no events", events, err)
+	}
+
+	_ = store.ClaimWanted("w-a", "rig-x")
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-c", Title: "C"})
+	events, err := w.poll(now)
+	if err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	want := []wlWatchEvent{
+		{ID: "w-a", Title: "A", From: "open", To: "claimed", ClaimedBy: "rig-x", At: "2026-10-01T12:00:00Z"},
+		{ID: "w-c", Title: "C", From: "", To: "open", At: "2026-10-01T12:00:00Z"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events =\n%+v\nwant\n%+v", events, want)
+	}
+
+	if events, _ := w.poll(now); len(events) != 0 {
+		t.Errorf("poll without changes = %+v, want none", events)
+	}
+}
+
+func TestWlWatcher_StatusFilter(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "A"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-b", Title: "B"})
+	_ = store.ClaimWanted("w-b", "rig-x")
+	w := &wlWatcher{store: store, status: doltserver.StatusOpen}
+	_, _ = w.poll(time.Now())
+
+	// w-a leaves open; w-b moves between two other statuses.
+	_ = store.ClaimWanted("w-a", "rig-y")
+	_ = store.SubmitCompletion("c-1", "w-b", "rig-x", "https://example.com/pr/1")
+	events, err := w.poll(time.Now())
+	if err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "w-a" {
+		t.Errorf("events = %+v, want only w-a leaving open", events)
+	}
+}
+
+func TestWlWatcher_MineReportsReleasedItems(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "A"})
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-other", Title: "Other"})
+	_ = store.ClaimWanted("w-a", "my-rig")
+	w := &wlWatcher{store: store, heldBy: "my-rig"}
+	_, _ = w.poll(time.Now())
+
+	_ = store.ClaimWanted("w-other", "rig-x")
+	_ = store.UnclaimWanted("w-a", "my-rig", false)
+	events, err := w.poll(time.Now())
+	if err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "w-a" || events[0].From != "claimed" || events[0].To != "open" {
+		t.Errorf("events = %+v, want w-a claimed → open", events)
+	}
+}
+
+func TestRunWatchLoop_StopsOnCancel(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	_ = store.InsertWanted(&doltserver.WantedItem{ID: "w-a", Title: "A"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	if err := runWatchLoop(ctx, time.Hour, &wlWatcher{store: store}, &out); err != nil {
+		t.Fatalf("runWatchLoop() = %v, want nil on cancel", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want nothing from the first poll", out.String())
+	}
+}
+
+func TestWriteWatchEvent(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeWatchEvent(&buf, wlWatchEvent{ID: "w-a", Title: "Fix the thing", To: "open", At: "2026-10-01T12:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "w-a") || !strings.Contains(got, "new → open") || !strings.Contains(got, "Fix the thing") {
+		t.Errorf("line = %q", got)
+	}
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	wlWhoisRecent int
+)
+
+var wlWhoisCmd = &cobra.Command{
+	Use:   "whois [town]",
+	Short: "Show a town's profile on the wasteland",
+	Long: `Show a town's footprint on the wasteland: its registration, skills,
+the items it currently holds, its recent completions, and where it stands
+on the completions leaderboard.
+
+Skills are the skill tags of stamps the town has received. Standing ranks
+towns by current (non-superseded) completions; ties share a rank.
+
+Without an argument, shows this town's own profile.
+
+Examples:
+  gt wl whois
+  gt wl whois partner-rig
+  gt wl whois partner-rig --recent 10 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlWhois,
+}
+
+func init() {
+	wlWhoisCmd.Flags().IntVar(&wlWhoisRecent, "recent", 5, "Number of recent completions to show")
+
+	wlCmd.AddCommand(wlWhoisCmd)
+}
+
+func runWlWhois(cmd *cobra.Command, args []string) error {
+	if wlWhoisRecent < 0 {
+		return fmt.Errorf("--recent must be >= 0, got %d", wlWhoisRecent)
+	}
+
+	townRoot, err := wlRun.townRoot()
+	if err != nil {
+		return err
+	}
+
+	handle := ""
+	if len(args) == 1 {
+		handle = args[0]
+	} else {
+		wlCfg, err := wlRun.wastelandConfig()
+		if err != nil {
+			return err
+		}
+		handle = wlCfg.RigHandle
+	}
+
+	if err := wlRun.requireCommonsRead(); err != nil {
+		return err
+	}
+
+	profile, err := doltserver.QueryRigProfile(townRoot, handle, wlWhoisRecent)
+	if err != nil {
+		return err
+	}
+	if wlJSON {
+		return writeWLJSON(os.Stdout, buildRigProfileJSON(profile), wlJSONPrettyOutput())
+	}
+	renderRigProfile(os.Stdout, profile)
+	return nil
+}
+
+// rigProfileJSON is the JSON shape of gt wl whois --json.
+type rigProfileJSON struct {
+	Handle       string `json:"handle"`
+	Registered   bool   `json:"registered"`
+	DisplayName  string `json:"display_name"`
+	RigType      string `json:"rig_type"`
+	TrustLevel   int    `json:"trust_level"`
+	RegisteredAt string `json:"registered_at"`
+	LastSeen     string `json:"last_seen"`
+
+	Skills            []rigSkillJSON      `json:"skills"`
+	Claimed           []rigClaimJSON      `json:"claimed"`
+	RecentCompletions []rigCompletionJSON `json:"recent_completions"`
+	Standing          rigStandingJSON     `json:"standing"`
+	Badges            []string            `json:"badges"`
+}
+
+type rigSkillJSON struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type rigClaimJSON struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+}
+
+type rigCompletionJSON struct {
+	ID          string `json:"id"`
+	WantedID    string `json:"wanted_id"`
+	Title       string `json:"title"`
+	CompletedAt string `json:"completed_at"`
+	Validated   bool   `json:"validated"`
+}
+
+type rigStandingJSON struct {
+	Completions int `json:"completions"`
+	Rank        int `json:"rank"`
+	RankedRigs  int `json:"ranked_rigs"`
+}
+
+// wlJSONTime renders t as RFC 3339 UTC, or "" when unset.
+func wlJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func buildRigProfileJSON(p *doltserver.RigProfile) rigProfileJSON {
+	out := rigProfileJSON{
+		Handle:            p.Handle,
+		Registered:        p.Registered,
+		DisplayName:       p.DisplayName,
+		RigType:           p.RigType,
+		TrustLevel:        p.TrustLevel,
+		RegisteredAt:      wlJSONTime(p.RegisteredAt),
+		LastSeen:          wlJSONTime(p.LastSeen),
+		Skills:            []rigSkillJSON{},
+		Claimed:           []rigClaimJSON{},
+		RecentCompletions: []rigCompletionJSON{},
+		Standing:          rigStandingJSON(p.Standing),
+		Badges:            []string{},
+	}
+	for _, s := range p.Skills {
+		out.Skills = append(out.Skills, rigSkillJSON(s))
+	}
+	for _, item := range p.Claimed {
+		out.Claimed = append(out.Claimed, rigClaimJSON{ID: item.ID, Title: item.Title, Status: item.Status, Priority: item.Priority})
+	}
+	for _, c := range p.RecentCompletions {
+		out.RecentCompletions = append(out.RecentCompletions, rigCompletionJSON{
+			ID:          c.ID,
+			WantedID:    c.WantedID,
+			Title:       c.Title,
+			CompletedAt: wlJSONTime(c.CompletedAt),
+			Validated:   c.Validated,
+		})
+	}
+	out.Badges = append(out.Badges, p.Badges...)
+	return out
+}
+
+// renderRigProfile prints the human form of gt wl whois.
+func renderRigProfile(w io.Writer, p *doltserver.RigProfile) {
+	name := style.Bold.Render(p.Handle)
+	if p.DisplayName != "" {
+		name += " (" + p.DisplayName + ")"
+	}
+	fmt.Fprintln(w, name)
+
+	if p.Registered {
+		fmt.Fprintf(w, "  Type:       %s, trust level %d\n", p.RigType, p.TrustLevel)
+		if !p.RegisteredAt.IsZero() {
+			fmt.Fprintf(w, "  Registered: %s\n", p.RegisteredAt.Format("2006-01-02"))
+		}
+		if !p.LastSeen.IsZero() {
+			fmt.Fprintf(w, "  Last seen:  %s\n", p.LastSeen.Format("2006-01-02 15:04"))
+		}
+	} else {
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render("not registered in the rigs table"))
+	}
+
+	if len(p.Skills) > 0 {
+		skills := make([]string, len(p.Skills))
+		for i, s := range p.Skills {
+			skills[i] = fmt.Sprintf("%s (%d)", s.Tag, s.Count)
+		}
+		fmt.Fprintf(w, "  Skills:     %s\n", strings.Join(skills, ", "))
+	}
+	if len(p.Badges) > 0 {
+		fmt.Fprintf(w, "  Badges:     %s\n", strings.Join(p.Badges, ", "))
+	}
+
+	s := p.Standing
+	if s.Rank == 0 {
+		fmt.Fprintf(w, "  Standing:   %s\n", style.Dim.Render("no completions yet"))
+	} else {
+		fmt.Fprintf(w, "  Standing:   #%d of %d by completions (%d completed)\n", s.Rank, s.RankedRigs, s.Completions)
+	}
+
+	fmt.Fprintf(w, "\nClaimed (%d):\n", len(p.Claimed))
+	if len(p.Claimed) == 0 {
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render("(none)"))
+	}
+	for _, item := range p.Claimed {
+		fmt.Fprintf(w, "  %s %s %s [%s]\n", item.ID, wlFormatPriority(strconv.Itoa(item.Priority)), item.Title, item.Status)
+	}
+
+	fmt.Fprintf(w, "\nRecent completions:\n")
+	if len(p.RecentCompletions) == 0 {
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render("(none)"))
+	}
+	for _, c := range p.RecentCompletions {
+		when := ""
+		if !c.CompletedAt.IsZero() {
+			when = c.CompletedAt.Format("2006-01-02")
+		}
+		validated := ""
+		if c.Validated {
+			validated = " ✓"
+		}
+		fmt.Fprintf(w, "  %s  %s %s%s\n", when, c.WantedID, c.Title, validated)
+	}
+}
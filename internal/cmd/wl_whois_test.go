@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func testRigProfile() *doltserver.RigProfile {
+	return &doltserver.RigProfile{
+		Handle:       "partner-rig",
+		Registered:   true,
+		DisplayName:  "Partner",
+		RigType:      "human",
+		TrustLevel:   2,
+		RegisteredAt: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+		Skills:       []doltserver.SkillCount{{Tag: "go", Count: 3}, {Tag: "sql", Count: 1}},
+		Claimed:      []*doltserver.WantedItem{{ID: "w-abc", Title: "Fix auth bug", Status: "claimed", Priority: 1}},
+		RecentCompletions: []doltserver.RigCompletion{
+			{ID: "c-1", WantedID: "w-old", Title: "Old task", CompletedAt: time.Date(2026, 9, 30, 8, 0, 0, 0, time.UTC), Validated: true},
+		},
+		Standing: doltserver.LeaderboardStanding{Completions: 7, Rank: 2, RankedRigs: 14},
+	}
+}
+
+func TestBuildRigProfileJSON(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := writeWLJSON(&buf, buildRigProfileJSON(testRigProfile()), false); err != nil {
+		t.Fatalf("writeWLJSON() error: %v", err)
+	}
+	want := `{"handle":"partner-rig","registered":true,"display_name":"Partner","rig_type":"human","trust_level":2,` +
+		`"registered_at":"2026-01-05T09:00:00Z","last_seen":"",` +
+		`"skills":[{"tag":"go","count":3},{"tag":"sql","count":1}],` +
+		`"claimed":[{"id":"w-abc","title":"Fix auth bug","status":"claimed","priority":1}],` +
+		`"recent_completions":[{"id":"c-1","wanted_id":"w-old","title":"Old task","completed_at":"2026-09-30T08:00:00Z","validated":true}],` +
+		`"standing":{"completions":7,"rank":2,"ranked_rigs":14},"badges":[]}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderRigProfile(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	renderRigProfile(&buf, testRigProfile())
+	out := buf.String()
+	for _, want := range []string{"Partner", "trust level 2", "go (3), sql (1)", "#2 of 14 by completions (7 completed)", "w-abc", "w-old Old task ✓"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	renderRigProfile(&buf, &doltserver.RigProfile{Handle: "stranger"})
+	if out := buf.String(); !strings.Contains(out, "not registered") || !strings.Contains(out, "no completions yet") {
+		t.Errorf("unregistered output:\n%s", out)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// selectWLWriteQueue has writes that cannot reach a remote server queued in
+// the town's write queue, recording the command line that made them, so
+// gt wl sync can replay them later.
+func selectWLWriteQueue(cmd *cobra.Command, args []string) {
+	command := strings.Join(append([]string{buildCommandPath(cmd)}, args...), " ")
+	doltserver.SetWLWriteQueue(func(townRoot, script string) error {
+		return wasteland.QueueWrite(townRoot, wasteland.QueuedWrite{
+			QueuedAt: time.Now().UTC(),
+			Command:  command,
+			Script:   script,
+		})
+	})
+}
+
+// replayWLWriteQueue is the wanted-board replay step of gt wl sync. It
+// replays the town's queued writes in order, each with its original time,
+// and stops at the first that fails, leaving it and the rest queued. A
+// write that no longer changes anything (the item moved on while it was
+// queued) is reported and dropped. With dryRun it only lists the queue.
+func replayWLWriteQueue(w io.Writer, townRoot string, dryRun bool) error {
+	queued, err := wasteland.ReadWriteQueue(townRoot)
+	if err != nil || len(queued) == 0 {
+		return err
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "%s %d queued write(s) would be replayed:\n", style.Bold.Render("~"), len(queued))
+		for _, q := range queued {
+			fmt.Fprintf(w, "  %s  %s\n", q.QueuedAt.Local().Format("2006-01-02 15:04:05"), q.Command)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(w, "Replaying %d queued write(s)...\n", len(queued))
+	replayed, err := wasteland.ReplayWriteQueue(townRoot, func(q wasteland.QueuedWrite) error {
+		applied, err := doltserver.ReplayWLWrite(townRoot, q.Script, q.QueuedAt)
+		if err != nil {
+			return fmt.Errorf("replaying %q (queued %s): %w", q.Command, q.QueuedAt.Format(time.RFC3339), err)
+		}
+		if applied {
+			fmt.Fprintf(w, "  %s %s\n", style.Bold.Render("✓"), q.Command)
+		} else {
+			fmt.Fprintf(w, "  %s %s %s\n", style.Dim.Render("-"), q.Command, style.Dim.Render("(no longer applies, dropped)"))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w\n%d of %d queued write(s) replayed; the rest stay in %s", err, replayed, len(queued), wasteland.WriteQueuePath(townRoot))
+	}
+	return nil
+}
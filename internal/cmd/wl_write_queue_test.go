@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+func TestReplayWLWriteQueue_EmptyQueue(t *testing.T) {
+	var out bytes.Buffer
+	if err := replayWLWriteQueue(&out, t.TempDir(), false); err != nil {
+		t.Fatalf("replayWLWriteQueue() error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want nothing for an empty queue", out.String())
+	}
+}
+
+func TestReplayWLWriteQueue_DryRunListsQueue(t *testing.T) {
+	townRoot := t.TempDir()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, command := range []string{"gt wl claim w-1", "gt wl done w-1 --evidence x"} {
+		if err := wasteland.QueueWrite(townRoot, wasteland.QueuedWrite{QueuedAt: at, Command: command, Script: "SELECT 1;"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := replayWLWriteQueue(&out, townRoot, true); err != nil {
+		t.Fatalf("replayWLWriteQueue(dry run) error: %v", err)
+	}
+	for _, want := range []string{"2 queued write(s) would be replayed", "gt wl claim w-1", "gt wl done w-1 --evidence x"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	left, err := wasteland.ReadWriteQueue(townRoot)
+	if err != nil || len(left) != 2 {
+		t.Errorf("queue after dry run = %v, %v; want both writes still queued", left, err)
+	}
+}
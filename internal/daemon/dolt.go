@@ -44,6 +44,10 @@ type DoltServerConfig struct {
 	// Password is the MySQL password. Empty means no password.
 	Password string `json:"password,omitempty"`
 
+	// Token is an auth token clients send instead of Password (see
+	// doltserver.Config.Token). The daemon does not use it itself.
+	Token string `json:"token,omitempty"`
+
 	// DataDir is the directory containing Dolt databases.
 	// Each subdirectory becomes a database.
 	DataDir string `json:"data_dir,omitempty"`
@@ -0,0 +1,196 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	// Also registers the "mysql" driver used for the server backend.
+	"github.com/go-sql-driver/mysql"
+)
+
+// Conn runs several queries within one WithConnection scope. Rows are
+// returned as column-name → value maps, like parseSimpleCSV; NULLs are "".
+type Conn interface {
+	// Query runs one statement and returns its rows.
+	Query(query string) ([]map[string]string, error)
+
+	// Batch runs statements in order and returns one row set per
+	// statement. The CLI backend sends the whole batch in a single dolt
+	// invocation, so prefer Batch when the queries are known up front.
+	Batch(queries ...string) ([][]map[string]string, error)
+}
+
+// WithConnection runs fn with a connection to the Dolt server described by
+// config. When the server accepts MySQL connections, every query in fn goes
+// over one held connection. Otherwise it falls back to spawning dolt sql,
+// where each Query is one process and each Batch is one process for the
+// whole batch. The connection is closed when fn returns.
+func WithConnection(ctx context.Context, config *Config, fn func(Conn) error) error {
+	conn, closeConn, err := openDoltConn(ctx, config)
+	if err != nil {
+		return fn(&cliConn{ctx: ctx, config: config})
+	}
+	defer closeConn()
+	return fn(&sqlConn{ctx: ctx, conn: conn})
+}
+
+// openDoltConn dials the server backend. It is a var so tests can substitute
+// another driver. Not safe for parallel tests.
+var openDoltConn = func(ctx context.Context, config *Config) (*sql.Conn, func(), error) {
+	if config.UsesSocket() {
+		if err := config.ValidateSocket(); err != nil {
+			return nil, nil, err
+		}
+	}
+	db, err := sql.Open("mysql", connDSN(config))
+	if err != nil {
+		return nil, nil, err
+	}
+	db.SetMaxOpenConns(1)
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, err
+	}
+	return conn, func() {
+		_ = conn.Close()
+		_ = db.Close()
+	}, nil
+}
+
+// connDSN returns the driver DSN for config. The driver formats it, so a
+// credential containing '@', ':' or '/' survives intact.
+func connDSN(config *Config) string {
+	dsn := mysql.NewConfig()
+	dsn.User = config.User
+	dsn.Passwd = config.Credential()
+	dsn.Net, dsn.Addr = "tcp", config.HostPort()
+	if config.UsesSocket() {
+		dsn.Net, dsn.Addr = "unix", config.SocketPath
+	}
+	dsn.Timeout = 2 * time.Second
+	// Token auth (e.g. Dolt's JWT users) sends the token as a cleartext
+	// password, which the driver refuses unless allowed.
+	dsn.AllowCleartextPasswords = config.Token != ""
+	return dsn.FormatDSN()
+}
+
+// sqlConn is the server backend: one held MySQL connection.
+type sqlConn struct {
+	ctx  context.Context
+	conn *sql.Conn
+}
+
+func (c *sqlConn) Query(query string) ([]map[string]string, error) {
+	rows, err := c.conn.QueryContext(c.ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("dolt query failed: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string]string
+	for rows.Next() {
+		vals := make([]sql.NullString, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("scanning dolt row: %w", err)
+		}
+		row := make(map[string]string, len(cols))
+		for i, col := range cols {
+			row[col] = vals[i].String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+func (c *sqlConn) Batch(queries ...string) ([][]map[string]string, error) {
+	results := make([][]map[string]string, 0, len(queries))
+	for _, q := range queries {
+		rows, err := c.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, rows)
+	}
+	return results, nil
+}
+
+// cliConn is the dolt sql fallback backend.
+type cliConn struct {
+	ctx    context.Context
+	config *Config
+}
+
+func (c *cliConn) Query(query string) ([]map[string]string, error) {
+	results, err := c.Batch(query)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// batchMarkerColumn prefixes the marker result sets that cliConn.Batch
+// interleaves with the caller's queries to split dolt's CSV output.
+const batchMarkerColumn = "__gt_batch_"
+
+func (c *cliConn) Batch(queries ...string) ([][]map[string]string, error) {
+	if len(queries) == 0 {
+		return nil, nil
+	}
+	var script strings.Builder
+	for i, q := range queries {
+		fmt.Fprintf(&script, "SELECT 1 AS %s%d; %s", batchMarkerColumn, i, strings.TrimSuffix(strings.TrimSpace(q), ";"))
+		script.WriteString(";\n")
+	}
+
+	cmd := buildDoltSQLCmd(c.ctx, c.config, "-r", "csv", "-q", script.String())
+	output, err := runDoltCmd(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("dolt sql query failed: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return splitBatchCSV(string(output), len(queries))
+}
+
+// splitBatchCSV splits the CSV output of a marked batch into n row sets.
+// Each marker result set is a header line naming the marker followed by a
+// "1" row; everything up to the next marker belongs to that query.
+func splitBatchCSV(output string, n int) ([][]map[string]string, error) {
+	results := make([][]map[string]string, n)
+	idx := -1
+	var section []string
+	flush := func() {
+		if idx >= 0 {
+			results[idx] = parseSimpleCSV(strings.Join(section, "\n"))
+		}
+	}
+	lines := strings.Split(output, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, batchMarkerColumn) {
+			flush()
+			if _, err := fmt.Sscanf(line, batchMarkerColumn+"%d", &idx); err != nil || idx < 0 || idx >= n {
+				return nil, fmt.Errorf("unexpected batch marker %q in dolt output", line)
+			}
+			section = nil
+			i++ // skip the marker's "1" row
+			continue
+		}
+		section = append(section, lines[i])
+	}
+	flush()
+	if idx != n-1 {
+		return nil, fmt.Errorf("dolt output ended after %d of %d batched queries", idx+1, n)
+	}
+	return results, nil
+}
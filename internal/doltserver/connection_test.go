@@ -0,0 +1,253 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// fakeDoltDriver answers every query with a single row naming the query, and
+// counts connections opened.
+type fakeDoltDriver struct{ conns atomic.Int32 }
+
+func (d *fakeDoltDriver) Open(string) (driver.Conn, error) {
+	d.conns.Add(1)
+	return fakeDoltDriverConn{}, nil
+}
+
+type fakeDoltDriverConn struct{}
+
+func (fakeDoltDriverConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeDoltStmt{query: query}, nil
+}
+func (fakeDoltDriverConn) Close() error              { return nil }
+func (fakeDoltDriverConn) Begin() (driver.Tx, error) { return nil, errors.New("no transactions") }
+
+type fakeDoltStmt struct{ query string }
+
+func (s fakeDoltStmt) Close() error  { return nil }
+func (s fakeDoltStmt) NumInput() int { return 0 }
+func (s fakeDoltStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("read-only")
+}
+func (s fakeDoltStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeDoltRows{vals: []driver.Value{s.query, int64(7), nil}}, nil
+}
+
+type fakeDoltRows struct {
+	vals []driver.Value
+	done bool
+}
+
+func (r *fakeDoltRows) Columns() []string { return []string{"query", "n", "missing"} }
+func (r *fakeDoltRows) Close() error      { return nil }
+func (r *fakeDoltRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.vals)
+	return nil
+}
+
+var fakeDolt = &fakeDoltDriver{}
+
+func init() { sql.Register("fakedolt", fakeDolt) }
+
+func TestWithConnection_ServerBackendHoldsOneConnection(t *testing.T) {
+	orig := openDoltConn
+	t.Cleanup(func() { openDoltConn = orig })
+	openDoltConn = func(ctx context.Context, config *Config) (*sql.Conn, func(), error) {
+		db, _ := sql.Open("fakedolt", "")
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, func() { _ = conn.Close(); _ = db.Close() }, nil
+	}
+	origRun := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = origRun })
+	runDoltCmd = func(*exec.Cmd) ([]byte, error) {
+		t.Fatal("server backend must not spawn dolt")
+		return nil, nil
+	}
+
+	before := fakeDolt.conns.Load()
+	err := WithConnection(context.Background(), DefaultConfig(t.TempDir()), func(c Conn) error {
+		rows, err := c.Query("SELECT a")
+		if err != nil {
+			return err
+		}
+		want := []map[string]string{{"query": "SELECT a", "n": "7", "missing": ""}}
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("Query() = %v, want %v", rows, want)
+		}
+		sets, err := c.Batch("SELECT b", "SELECT c")
+		if err != nil {
+			return err
+		}
+		if len(sets) != 2 || sets[1][0]["query"] != "SELECT c" {
+			t.Errorf("Batch() = %v", sets)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithConnection() error: %v", err)
+	}
+	if n := fakeDolt.conns.Load() - before; n != 1 {
+		t.Errorf("opened %d connections, want 1", n)
+	}
+}
+
+func stubCLIBackend(t *testing.T, output string) *[]string {
+	t.Helper()
+	orig := openDoltConn
+	t.Cleanup(func() { openDoltConn = orig })
+	openDoltConn = func(context.Context, *Config) (*sql.Conn, func(), error) {
+		return nil, nil, errors.New("connection refused")
+	}
+	origRun := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = origRun })
+	var scripts []string
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		scripts = append(scripts, cmd.Args[len(cmd.Args)-1])
+		return []byte(output), nil
+	}
+	return &scripts
+}
+
+func TestWithConnection_CLIBackendBatchesIntoOneInvocation(t *testing.T) {
+	scripts := stubCLIBackend(t, "__gt_batch_0\n1\nid,title\nw-1,\"One, two\"\nw-2,Two\n__gt_batch_1\n1\ncount\n\n__gt_batch_2\n1\nn\n3\n")
+
+	var sets [][]map[string]string
+	err := WithConnection(context.Background(), DefaultConfig(t.TempDir()), func(c Conn) error {
+		var err error
+		sets, err = c.Batch("SELECT id, title FROM wanted;", "SELECT count FROM empty", "SELECT 3 AS n")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithConnection() error: %v", err)
+	}
+	if len(*scripts) != 1 {
+		t.Fatalf("spawned dolt %d times, want 1", len(*scripts))
+	}
+	if !strings.Contains((*scripts)[0], "SELECT 1 AS __gt_batch_1; SELECT count FROM empty;") {
+		t.Errorf("batch script = %q", (*scripts)[0])
+	}
+	if len(sets) != 3 || len(sets[0]) != 2 || sets[0][0]["title"] != "One, two" || sets[1] != nil || sets[2][0]["n"] != "3" {
+		t.Errorf("Batch() = %v", sets)
+	}
+}
+
+func TestWithConnection_CLIBackendTruncatedOutput(t *testing.T) {
+	stubCLIBackend(t, "__gt_batch_0\n1\nid\nw-1\n")
+
+	err := WithConnection(context.Background(), DefaultConfig(t.TempDir()), func(c Conn) error {
+		_, err := c.Batch("SELECT id FROM a", "SELECT id FROM b")
+		return err
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Fatalf("WithConnection() error = %v, want truncated batch error", err)
+	}
+}
+
+func TestConnDSN_KeepsCredentialIntact(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		config *Config
+		net    string
+		addr   string
+	}{
+		{"tcp", &Config{User: "root", Password: "p@ss:w/rd?x=1", Host: "db.example.com", Port: 3307}, "tcp", "db.example.com:3307"},
+		{"socket", &Config{User: "root", Password: "a@b", SocketPath: "/tmp/dolt.sock"}, "unix", "/tmp/dolt.sock"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := mysql.ParseDSN(connDSN(tc.config))
+			if err != nil {
+				t.Fatalf("ParseDSN(%q) error: %v", connDSN(tc.config), err)
+			}
+			if parsed.User != tc.config.User || parsed.Passwd != tc.config.Password || parsed.Net != tc.net || parsed.Addr != tc.addr {
+				t.Errorf("DSN parses to user %q password %q %s(%s)", parsed.User, parsed.Passwd, parsed.Net, parsed.Addr)
+			}
+			if parsed.AllowCleartextPasswords {
+				t.Error("a password DSN allows cleartext passwords")
+			}
+		})
+	}
+
+	parsed, err := mysql.ParseDSN(connDSN(&Config{User: "root", Token: "tok@en", Port: 3307}))
+	if err != nil || parsed.Passwd != "tok@en" || !parsed.AllowCleartextPasswords {
+		t.Errorf("token DSN = %+v, %v; want the token sent as a cleartext password", parsed, err)
+	}
+}
+
+func TestQueryWLBoard_OneInvocation(t *testing.T) {
+	scripts := stubCLIBackend(t, "__gt_batch_0\n1\nkey,value\nworkflow.closed,completed\n"+
+		"__gt_batch_1\n1\nid,title,status,priority\nw-1,First,open,1\nw-2,Second,claimed,2\n")
+
+	board, err := QueryWLBoard(t.TempDir())
+	if err != nil {
+		t.Fatalf("QueryWLBoard() error: %v", err)
+	}
+	if len(*scripts) != 1 {
+		t.Fatalf("spawned dolt %d times, want 1", len(*scripts))
+	}
+	if board.Settings["workflow.closed"] != "completed" {
+		t.Errorf("Settings = %v", board.Settings)
+	}
+	if len(board.Items) != 2 || board.Items[1].ID != "w-2" || board.Items[1].Status != StatusClaimed {
+		t.Errorf("Items = %+v", board.Items)
+	}
+}
+
+// BenchmarkCLIBackend compares one dolt process per query with one process
+// per batch. Process spawn is simulated with /bin/true, which dominates the
+// cost of a real dolt sql call.
+func BenchmarkCLIBackend(b *testing.B) {
+	queries := []string{"SELECT 1", "SELECT 2", "SELECT 3", "SELECT 4", "SELECT 5"}
+	output := ""
+	for i := range queries {
+		output += fmt.Sprintf("%s%d\n1\nn\n1\n", batchMarkerColumn, i)
+	}
+
+	origRun := runDoltCmd
+	b.Cleanup(func() { runDoltCmd = origRun })
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		if err := exec.Command("true").Run(); err != nil {
+			return nil, err
+		}
+		if strings.Count(cmd.Args[len(cmd.Args)-1], batchMarkerColumn) == 1 {
+			return []byte(batchMarkerColumn + "0\n1\nn\n1\n"), nil
+		}
+		return []byte(output), nil
+	}
+	conn := &cliConn{ctx: context.Background(), config: DefaultConfig(b.TempDir())}
+
+	b.Run("query-each", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, q := range queries {
+				if _, err := conn.Query(q); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := conn.Batch(queries...); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package doltserver
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// doltLog traces dolt invocations at debug level. It discards everything
+// unless SetDoltLogger installs a logger, e.g. for gt wl --verbose.
+var doltLog = slog.New(slog.DiscardHandler)
+
+// SetDoltLogger sends a trace of every dolt invocation that follows to
+// logger: its argv, dolt-related environment, and any SQL script file it
+// runs, then its duration and, on failure, its raw output. Secrets are
+// redacted. A nil logger turns tracing off.
+func SetDoltLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	doltLog = logger
+}
+
+// RunDoltCommand runs cmd and returns its combined output, tracing it to
+// the dolt logger. Callers outside this package that run dolt themselves
+// use it so --verbose sees their invocations too.
+func RunDoltCommand(cmd *exec.Cmd) ([]byte, error) {
+	logDoltStart(cmd)
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	logDoltDone(cmd, output, err, time.Since(start))
+	return output, err
+}
+
+// LogValue renders the config for logging, with its credentials redacted.
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", c.Host),
+		slog.Int("port", c.Port),
+		slog.String("user", c.User),
+		slog.String("password", redactSecret(c.Password)),
+		slog.String("token", redactSecret(c.Token)),
+		slog.String("socket", c.SocketPath),
+		slog.String("data_dir", c.DataDir),
+		slog.Bool("remote", c.IsRemote()),
+	)
+}
+
+func logDoltStart(cmd *exec.Cmd) {
+	ctx := context.Background()
+	if !doltLog.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{slog.Any("argv", cmd.Args), slog.String("dir", cmd.Dir)}
+	if env := doltEnv(cmd.Env); len(env) > 0 {
+		attrs = append(attrs, slog.Any("env", env))
+	}
+	doltLog.Debug("dolt run", attrs...)
+	for i, arg := range cmd.Args {
+		if arg == "--file" && i+1 < len(cmd.Args) {
+			if script, err := os.ReadFile(cmd.Args[i+1]); err == nil {
+				doltLog.Debug("dolt script", slog.String("file", cmd.Args[i+1]), slog.String("sql", string(script)))
+			}
+		}
+	}
+}
+
+func logDoltDone(cmd *exec.Cmd, output []byte, err error, elapsed time.Duration) {
+	if err == nil {
+		doltLog.Debug("dolt ok", slog.String("cmd", doltSubcommand(cmd)), slog.Duration("elapsed", elapsed))
+		return
+	}
+	doltLog.Debug("dolt failed",
+		slog.String("cmd", doltSubcommand(cmd)),
+		slog.Duration("elapsed", elapsed),
+		slog.String("error", err.Error()),
+		slog.String("output", string(output)))
+}
+
+// doltSubcommand names cmd by its first argument, e.g. "sql" or "pull".
+func doltSubcommand(cmd *exec.Cmd) string {
+	if len(cmd.Args) > 1 {
+		return cmd.Args[1]
+	}
+	return cmd.Path
+}
+
+// doltEnv returns the entries of env that affect dolt or gt's use of it,
+// with secret values redacted. An empty env means the process environment
+// is inherited, and nothing beyond it was set.
+func doltEnv(env []string) []string {
+	var out []string
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		upper := strings.ToUpper(name)
+		if !strings.HasPrefix(upper, "DOLT") && !strings.HasPrefix(upper, "GT_DOLT") {
+			continue
+		}
+		if isSecretEnvName(upper) {
+			value = redactSecret(value)
+		}
+		out = append(out, name+"="+value)
+	}
+	return out
+}
+
+// isSecretEnvName reports whether an (upper-case) environment variable
+// name looks like it holds a credential.
+func isSecretEnvName(name string) bool {
+	for _, s := range []string{"PASSWORD", "TOKEN", "SECRET", "CREDENTIAL", "KEY"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "REDACTED"
+}
@@ -0,0 +1,72 @@
+package doltserver
+
+import (
+	"bytes"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// captureDoltLog installs a debug logger writing to the returned buffer.
+func captureDoltLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetDoltLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { SetDoltLogger(nil) })
+	return &buf
+}
+
+func TestConfigLogValue_RedactsCredentials(t *testing.T) {
+	buf := captureDoltLog(t)
+	cfg := &Config{Host: "dolt.example.com", Port: 3306, User: "rig", Password: "hunter2", Token: "eyJ.secret"}
+	doltLog.Debug("dolt config", slog.Any("config", cfg))
+
+	got := buf.String()
+	for _, secret := range []string{"hunter2", "eyJ.secret"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("log leaks %q:\n%s", secret, got)
+		}
+	}
+	for _, want := range []string{"config.host=dolt.example.com", "config.user=rig", "config.password=REDACTED", "config.remote=true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunDoltCommand_TracesArgvAndFailure(t *testing.T) {
+	buf := captureDoltLog(t)
+	cmd := exec.Command("sh", "-c", "echo 'connection refused' >&2; exit 3")
+	cmd.Env = []string{"PATH=/usr/bin:/bin", "DOLT_CLI_PASSWORD=hunter2", "GT_DOLT_HOST=dolt.example.com", "HOME=/nowhere"}
+
+	if _, err := RunDoltCommand(cmd); err == nil {
+		t.Fatal("RunDoltCommand() succeeded, want the exit status")
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "hunter2") {
+		t.Errorf("log leaks the password:\n%s", got)
+	}
+	for _, want := range []string{"msg=\"dolt run\"", "DOLT_CLI_PASSWORD=REDACTED", "GT_DOLT_HOST=dolt.example.com", "msg=\"dolt failed\"", "connection refused", "exit status 3"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "HOME=") {
+		t.Errorf("log includes unrelated environment:\n%s", got)
+	}
+}
+
+func TestRunDoltCommand_QuietByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	SetDoltLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { SetDoltLogger(nil) })
+
+	if _, err := RunDoltCommand(exec.Command("sh", "-c", "exit 1")); err == nil {
+		t.Fatal("RunDoltCommand() succeeded, want the exit status")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("info-level logger got dolt traces:\n%s", buf.String())
+	}
+}
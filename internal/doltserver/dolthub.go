@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"strings"
@@ -48,6 +49,33 @@ func DoltHubRemoteURL(org, repo string) string {
 	return fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, repo)
 }
 
+// dolthubWebBase is the DoltHub web UI base URL.
+const dolthubWebBase = "https://www.dolthub.com"
+
+// ParseDoltHubRepo extracts "org/repo" from either a bare "org/repo" path or
+// a DoltHub remote URL (https://doltremoteapi.dolthub.com/org/repo).
+func ParseDoltHubRepo(remote string) (org, repo string, err error) {
+	path := strings.TrimSpace(remote)
+	path = strings.TrimPrefix(path, dolthubRemoteBase+"/")
+	path = strings.TrimSuffix(path, "/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.Contains(parts[0], ":") {
+		return "", "", fmt.Errorf("not a DoltHub org/repo: %q", remote)
+	}
+	return parts[0], parts[1], nil
+}
+
+// DoltHubWebURL returns the DoltHub web UI URL for a repo.
+func DoltHubWebURL(org, repo string) string {
+	return fmt.Sprintf("%s/repositories/%s/%s", dolthubWebBase, org, repo)
+}
+
+// DoltHubQueryURL returns a DoltHub web UI URL that runs query against the
+// main branch of a repo.
+func DoltHubQueryURL(org, repo, query string) string {
+	return fmt.Sprintf("%s/query/main?q=%s", DoltHubWebURL(org, repo), url.QueryEscape(query))
+}
+
 // CreateDoltHubRepo creates a private repository on DoltHub via the API.
 // Returns nil if the repo was created or already exists.
 func CreateDoltHubRepo(org, repo, token string) error {
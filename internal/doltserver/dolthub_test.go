@@ -68,6 +68,40 @@ func TestDoltHubRemoteURL(t *testing.T) {
 	}
 }
 
+func TestParseDoltHubRepo(t *testing.T) {
+	tests := []struct {
+		remote  string
+		org     string
+		repo    string
+		wantErr bool
+	}{
+		{"hop/wl-commons", "hop", "wl-commons", false},
+		{"https://doltremoteapi.dolthub.com/alice-dev/wl-commons", "alice-dev", "wl-commons", false},
+		{"https://doltremoteapi.dolthub.com/alice-dev/wl-commons/", "alice-dev", "wl-commons", false},
+		{"file:///tmp/remote", "", "", true},
+		{"just-a-name", "", "", true},
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		org, repo, err := ParseDoltHubRepo(tt.remote)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDoltHubRepo(%q) error = %v, wantErr %v", tt.remote, err, tt.wantErr)
+			continue
+		}
+		if org != tt.org || repo != tt.repo {
+			t.Errorf("ParseDoltHubRepo(%q) = %q, %q; want %q, %q", tt.remote, org, repo, tt.org, tt.repo)
+		}
+	}
+}
+
+func TestDoltHubQueryURL(t *testing.T) {
+	got := DoltHubQueryURL("hop", "wl-commons", "SELECT * FROM wanted WHERE id = 'w-1'")
+	want := "https://www.dolthub.com/repositories/hop/wl-commons/query/main?q=SELECT+%2A+FROM+wanted+WHERE+id+%3D+%27w-1%27"
+	if got != want {
+		t.Errorf("DoltHubQueryURL() = %q, want %q", got, want)
+	}
+}
+
 func TestCreateDoltHubRepo_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify request
@@ -158,6 +158,14 @@ type Config struct {
 	// Empty means no password (backward-compatible default for local access).
 	Password string
 
+	// Token is an auth token (e.g. a JWT for a hosted or DoltHub-backed
+	// server) sent in place of Password. When both are set, Token wins.
+	Token string
+
+	// SocketPath is the Unix domain socket of a local sql-server. When set,
+	// local connections use it instead of TCP. Ignored for remote hosts.
+	SocketPath string
+
 	// DataDir is the root directory containing all rig databases.
 	// Each subdirectory is a separate database that will be served.
 	DataDir string
@@ -180,6 +188,11 @@ type Config struct {
 //   - GT_DOLT_PORT → Port
 //   - GT_DOLT_USER → User
 //   - GT_DOLT_PASSWORD → Password
+//   - GT_DOLT_TOKEN (or GASTOWN_DOLT_TOKEN) → Token
+//   - GT_DOLT_SOCKET → SocketPath
+//
+// Without a token variable, Token is read from dolt_server.token in the
+// town's mayor/daemon.json, so a town can keep it out of its environment.
 func DefaultConfig(townRoot string) *Config {
 	daemonDir := filepath.Join(townRoot, "daemon")
 	config := &Config{
@@ -206,10 +219,51 @@ func DefaultConfig(townRoot string) *Config {
 	if pw := os.Getenv("GT_DOLT_PASSWORD"); pw != "" {
 		config.Password = pw
 	}
+	if s := os.Getenv("GT_DOLT_SOCKET"); s != "" {
+		config.SocketPath = s
+	}
+	config.Token = doltToken(townRoot)
 
 	return config
 }
 
+// doltTokenEnvs are the environment variables that set Config.Token, in
+// order of precedence.
+var doltTokenEnvs = []string{"GT_DOLT_TOKEN", "GASTOWN_DOLT_TOKEN"}
+
+// doltToken returns the auth token from the environment, else from
+// dolt_server.token in townRoot's mayor/daemon.json. A missing or
+// unreadable file means no token.
+func doltToken(townRoot string) string {
+	for _, env := range doltTokenEnvs {
+		if tok := strings.TrimSpace(os.Getenv(env)); tok != "" {
+			return tok
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "daemon.json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return ""
+	}
+	var daemon struct {
+		DoltServer struct {
+			Token string `json:"token"`
+		} `json:"dolt_server"`
+	}
+	if err := json.Unmarshal(data, &daemon); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(daemon.DoltServer.Token)
+}
+
+// Credential returns the secret to authenticate with: Token when set,
+// otherwise Password. Empty means none.
+func (c *Config) Credential() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	return c.Password
+}
+
 // IsRemote returns true when the config points to a non-local Dolt server.
 // Empty host, "127.0.0.1", "localhost", "::1", and "[::1]" are all considered local.
 func (c *Config) IsRemote() bool {
@@ -220,9 +274,37 @@ func (c *Config) IsRemote() bool {
 	return true
 }
 
-// SQLArgs returns the dolt CLI flags needed to connect to a remote server.
-// Returns nil for local servers (dolt auto-detects the running local server).
+// UsesSocket reports whether connections go over SocketPath rather than TCP.
+func (c *Config) UsesSocket() bool {
+	return c.SocketPath != "" && !c.IsRemote()
+}
+
+// ValidateSocket checks that SocketPath, when in use, names an existing Unix
+// domain socket.
+func (c *Config) ValidateSocket() error {
+	if !c.UsesSocket() {
+		return nil
+	}
+	info, err := os.Stat(c.SocketPath)
+	if err != nil {
+		return fmt.Errorf("dolt socket %s: %w", c.SocketPath, err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("dolt socket %s is not a Unix socket", c.SocketPath)
+	}
+	return nil
+}
+
+// SQLArgs returns the dolt CLI flags needed to connect to a remote server,
+// or to a local server over SocketPath. Returns nil for other local servers
+// (dolt auto-detects the running local server).
 func (c *Config) SQLArgs() []string {
+	if c.UsesSocket() {
+		return []string{
+			"--socket", c.SocketPath,
+			"--user", c.User,
+		}
+	}
 	if !c.IsRemote() {
 		return nil
 	}
@@ -234,10 +316,10 @@ func (c *Config) SQLArgs() []string {
 	}
 }
 
-// userDSN returns the user[:password] portion of a MySQL DSN.
+// userDSN returns the user[:credential] portion of a MySQL DSN.
 func (c *Config) userDSN() string {
-	if c.Password != "" {
-		return c.User + ":" + c.Password
+	if cred := c.Credential(); cred != "" {
+		return c.User + ":" + cred
 	}
 	return c.User
 }
@@ -253,7 +335,8 @@ func (c *Config) HostPort() string {
 
 // buildDoltSQLCmd constructs a dolt sql command that works for both local and remote servers.
 // For local: runs from config.DataDir so dolt auto-detects the running server.
-// For remote: prepends connection flags and passes password via DOLT_CLI_PASSWORD env var.
+// For remote: prepends connection flags and passes the credential (token or
+// password) via DOLT_CLI_PASSWORD env var.
 func buildDoltSQLCmd(ctx context.Context, config *Config, args ...string) *exec.Cmd {
 	sqlArgs := config.SQLArgs()
 	fullArgs := make([]string, 0, len(sqlArgs)+1+len(args))
@@ -267,13 +350,18 @@ func buildDoltSQLCmd(ctx context.Context, config *Config, args ...string) *exec.
 		cmd.Dir = config.DataDir
 	}
 
-	if config.IsRemote() && config.Password != "" {
-		cmd.Env = append(os.Environ(), "DOLT_CLI_PASSWORD="+config.Password)
+	if cred := config.Credential(); (config.IsRemote() || config.UsesSocket()) && cred != "" {
+		cmd.Env = append(os.Environ(), "DOLT_CLI_PASSWORD="+cred)
 	}
 
 	return cmd
 }
 
+// runDoltCmd executes a prepared dolt command and returns its combined output.
+// It is a var (not a func) so tests can substitute a fake runner.
+// Not safe for parallel tests — tests that mutate this must not use t.Parallel().
+var runDoltCmd = RunDoltCommand
+
 // RigDatabaseDir returns the database directory for a specific rig.
 func RigDatabaseDir(townRoot, rigName string) string {
 	config := DefaultConfig(townRoot)
@@ -391,6 +479,17 @@ func IsRunning(townRoot string) (bool, int, error) {
 // Returns nil if reachable, error describing the problem otherwise.
 func CheckServerReachable(townRoot string) error {
 	config := DefaultConfig(townRoot)
+	if config.UsesSocket() {
+		if err := config.ValidateSocket(); err != nil {
+			return fmt.Errorf("Dolt server not reachable: %w\n\nStart with: gt dolt start", err)
+		}
+		conn, err := net.DialTimeout("unix", config.SocketPath, 2*time.Second)
+		if err != nil {
+			return fmt.Errorf("Dolt server not reachable at %s: %w\n\nStart with: gt dolt start", config.SocketPath, err)
+		}
+		_ = conn.Close()
+		return nil
+	}
 	addr := config.HostPort()
 	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
 	if err != nil {
@@ -759,9 +858,10 @@ func GetConnectionStringForRig(townRoot, rigName string) string {
 	return fmt.Sprintf("%s@tcp(%s)/%s", config.displayDSN(), config.HostPort(), rigName)
 }
 
-// displayDSN returns the user[:password] portion for display, masking any password.
+// displayDSN returns the user[:password] portion for display, masking any
+// password or token.
 func (c *Config) displayDSN() string {
-	if c.Password != "" {
+	if c.Credential() != "" {
 		return c.User + ":****"
 	}
 	return c.User
@@ -1993,15 +2093,22 @@ func doltSQLWithRecovery(townRoot, rigDB, query string) error {
 
 // MeasureQueryLatency times a SELECT 1 query against the Dolt server.
 func MeasureQueryLatency(townRoot string) (time.Duration, error) {
-	config := DefaultConfig(townRoot)
+	return Ping(context.Background(), DefaultConfig(townRoot))
+}
 
+// Ping runs a trivial SELECT 1 against the server described by config and
+// returns the round-trip latency. It uses the same command construction as
+// every other query, so it works for both local and remote servers.
+func Ping(ctx context.Context, config *Config) (time.Duration, error) {
 	start := time.Now()
-	ctx := context.Background()
 	cmd := buildDoltSQLCmd(ctx, config, "-q", "SELECT 1")
-	output, err := cmd.CombinedOutput()
+	output, err := runDoltCmd(cmd)
 	elapsed := time.Since(start)
 
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, fmt.Errorf("ping %s: %w", config.HostPort(), ctxErr)
+		}
 		return 0, fmt.Errorf("SELECT 1 failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
 	}
 
@@ -2179,13 +2286,18 @@ func doltSQLWithRetry(townRoot, rigDB, query string) error {
 
 // isDoltRetryableError returns true if the error is a transient Dolt failure worth retrying.
 // Covers manifest lock contention, read-only mode, optimistic lock failures, timeouts,
-// and catalog propagation delays after CREATE DATABASE.
+// catalog propagation delays after CREATE DATABASE, and DoltHub 5xx responses in
+// remote mode. DoltHub auth failures are never retryable.
 func isDoltRetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
 	msg := err.Error()
-	return strings.Contains(msg, "database is read only") ||
+	if isDoltHubAuthError(msg) {
+		return false
+	}
+	return isDoltHubTransientError(msg) ||
+		strings.Contains(msg, "database is read only") ||
 		strings.Contains(msg, "cannot update manifest") ||
 		strings.Contains(msg, "optimistic lock") ||
 		strings.Contains(msg, "serialization failure") ||
@@ -2194,6 +2306,55 @@ func isDoltRetryableError(err error) bool {
 		strings.Contains(msg, "Unknown database")
 }
 
+// doltHubTransientSignatures are substrings of dolt's stderr when DoltHub
+// answers with a gateway or availability error. Dolt reports remote failures
+// either as raw HTTP statuses or as gRPC codes.
+var doltHubTransientSignatures = []string{
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"status code 502",
+	"status code 503",
+	"status code 504",
+	"status code received from server: 502",
+	"status code received from server: 503",
+	"status code received from server: 504",
+	"code = unavailable",
+}
+
+// doltHubAuthSignatures are substrings of dolt's stderr when DoltHub rejects
+// the caller's credentials. Retrying cannot fix these.
+var doltHubAuthSignatures = []string{
+	"401 unauthorized",
+	"403 forbidden",
+	"status code 401",
+	"status code 403",
+	"status code received from server: 401",
+	"status code received from server: 403",
+	"code = unauthenticated",
+	"code = permissiondenied",
+}
+
+// isDoltHubTransientError reports whether msg carries a DoltHub 5xx signature.
+func isDoltHubTransientError(msg string) bool {
+	return containsAnyFold(msg, doltHubTransientSignatures)
+}
+
+// isDoltHubAuthError reports whether msg carries a DoltHub 401/403 signature.
+func isDoltHubAuthError(msg string) bool {
+	return containsAnyFold(msg, doltHubAuthSignatures)
+}
+
+func containsAnyFold(msg string, substrs []string) bool {
+	lower := strings.ToLower(msg)
+	for _, s := range substrs {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // validBranchNameRe matches only safe branch name characters: alphanumeric, hyphen,
 // underscore, dot, and forward slash. This prevents SQL injection via branch names
 // interpolated into Dolt stored procedure calls.
@@ -2329,6 +2490,11 @@ SET @@autocommit = 1;
 // doltSQLScript executes a multi-statement SQL script via a temp file.
 // Uses `dolt sql --file` for reliable multi-statement execution within a
 // single connection, preserving DOLT_CHECKOUT state across statements.
+//
+// The script stops at the first failing statement. In a script that wraps
+// its writes in START TRANSACTION ... COMMIT, a failure leaves the
+// transaction open when the connection closes and the server rolls it
+// back, so either every write applies or none does.
 func doltSQLScript(townRoot, script string) error {
 	config := DefaultConfig(townRoot)
 
@@ -2344,12 +2510,16 @@ func doltSQLScript(townRoot, script string) error {
 	}
 	tmpFile.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	limit := effectiveSQLTimeout(DefaultSQLScriptTimeout)
+	ctx, cancel := context.WithTimeout(sqlContext, limit)
 	defer cancel()
 
 	cmd := buildDoltSQLCmd(ctx, config, "--file", tmpFile.Name())
-	output, err := cmd.CombinedOutput()
+	output, err := runDoltCmd(cmd)
 	if err != nil {
+		if terr := sqlTimeoutError(ctx, limit); terr != nil {
+			return terr
+		}
 		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
 	}
 	return nil
@@ -2359,14 +2529,28 @@ func doltSQLScript(townRoot, script string) error {
 // Callers must ensure scripts are idempotent, as partial execution may have occurred
 // before the retry. Uses the same retry classification as doltSQLWithRetry but with
 // fewer retries and shorter backoff since multi-statement scripts are more expensive.
+// A dropped server connection is handled first by withReconnect. A write that
+// still cannot reach a remote server is queued when a write queue is set (see
+// SetWLWriteQueue).
 func doltSQLScriptWithRetry(townRoot, script string) error {
+	return queueUnreachableWrite(townRoot, script, retryDoltSQLScript(townRoot, script))
+}
+
+// retryDoltSQLScript is doltSQLScriptWithRetry without the write queue.
+func retryDoltSQLScript(townRoot, script string) error {
+	if wlDryRun != nil {
+		fmt.Fprintf(wlDryRun, "-- dry run, not executed:\n%s\n", strings.TrimRight(script, "\n"))
+		return nil
+	}
+
 	const maxRetries = 3
 	const baseBackoff = 500 * time.Millisecond
 	const maxBackoff = 8 * time.Second
 
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if err := doltSQLScript(townRoot, script); err != nil {
+		err := withReconnect(townRoot, func() error { return doltSQLScript(townRoot, script) })
+		if err != nil {
 			lastErr = err
 			if !isDoltRetryableError(err) {
 				return err
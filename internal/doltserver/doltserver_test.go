@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // =============================================================================
@@ -2480,6 +2484,32 @@ func TestIsDoltRetryableError_IncludesReadOnly(t *testing.T) {
 	}
 }
 
+func TestIsDoltRetryableError_DoltHub(t *testing.T) {
+	// In remote mode DoltHub hiccups surface in dolt's stderr. 5xx responses
+	// are retryable; auth failures must fail fast even when the same output
+	// also mentions an unavailable backend.
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{"exit status 1 (output: error: failed to get remote db\ncause: rpc error: code = Unavailable desc = unexpected HTTP status code received from server: 502 (Bad Gateway))", true},
+		{"dolt push: exit status 1 (fatal: unexpected HTTP status code received from server: 503 (Service Unavailable))", true},
+		{"error: 504 Gateway Timeout from https://doltremoteapi.dolthub.com", true},
+		{"http request failed with status code 503", true},
+		{"rpc error: code = Unauthenticated desc = invalid credentials", false},
+		{"rpc error: code = PermissionDenied desc = user does not have write access", false},
+		{"unexpected HTTP status code received from server: 401 (Unauthorized)", false},
+		{"403 Forbidden; retry later: 503 Service Unavailable", false},
+		{"status code 500", false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("%s", tt.msg)
+		if got := isDoltRetryableError(err); got != tt.want {
+			t.Errorf("isDoltRetryableError(%q) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
 func TestRecoverReadOnly_NoServer(t *testing.T) {
 	// When no server is running, CheckReadOnly returns false (can't probe),
 	// so RecoverReadOnly should be a no-op.
@@ -3288,6 +3318,54 @@ func TestSQLArgs(t *testing.T) {
 	}
 }
 
+func TestSQLArgs_Socket(t *testing.T) {
+	local := &Config{SocketPath: "/tmp/dolt.sock", Port: 3307, User: "root"}
+	want := []string{"--socket", "/tmp/dolt.sock", "--user", "root"}
+	if got := local.SQLArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("local socket SQLArgs() = %v, want %v", got, want)
+	}
+
+	// A remote host always connects over TCP, whatever the socket setting.
+	remote := &Config{Host: "10.0.0.5", SocketPath: "/tmp/dolt.sock", Port: 3307, User: "root"}
+	want = []string{"--host", "10.0.0.5", "--port", "3307", "--user", "root", "--no-tls"}
+	if got := remote.SQLArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("remote SQLArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateSocket(t *testing.T) {
+	// Unix socket paths are length-limited, so avoid the long t.TempDir().
+	dir, err := os.MkdirTemp("", "gtsock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	sock := filepath.Join(dir, "dolt.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	if err := (&Config{SocketPath: sock}).ValidateSocket(); err != nil {
+		t.Errorf("ValidateSocket(listening socket) = %v, want nil", err)
+	}
+	if err := (&Config{}).ValidateSocket(); err != nil {
+		t.Errorf("ValidateSocket(no socket) = %v, want nil", err)
+	}
+	if err := (&Config{SocketPath: filepath.Join(dir, "missing.sock")}).ValidateSocket(); err == nil {
+		t.Error("ValidateSocket(missing) should fail")
+	}
+	regular := filepath.Join(dir, "plain")
+	if err := os.WriteFile(regular, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Config{SocketPath: regular}).ValidateSocket(); err == nil || !strings.Contains(err.Error(), "not a Unix socket") {
+		t.Errorf("ValidateSocket(regular file) = %v, want not a Unix socket", err)
+	}
+}
+
 func TestUserDSN(t *testing.T) {
 	tests := []struct {
 		user     string
@@ -3477,3 +3555,116 @@ func TestBuildDoltSQLCmd_RemoteNoPassword(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildDoltSQLCmd_RemoteTokenPreferred(t *testing.T) {
+	config := &Config{
+		Host:     "10.0.0.5",
+		Port:     3307,
+		User:     "root",
+		Password: "secret",
+		Token:    "tok-123",
+	}
+
+	cmd := buildDoltSQLCmd(t.Context(), config, "-q", "SELECT 1")
+
+	var got []string
+	for _, env := range cmd.Env {
+		if strings.HasPrefix(env, "DOLT_CLI_PASSWORD=") {
+			got = append(got, env)
+		}
+	}
+	if len(got) != 1 || got[0] != "DOLT_CLI_PASSWORD=tok-123" {
+		t.Errorf("DOLT_CLI_PASSWORD env = %v, want the token only", got)
+	}
+	if dsn := config.userDSN(); dsn != "root:tok-123" {
+		t.Errorf("userDSN() = %q, want the token", dsn)
+	}
+	if dsn := config.displayDSN(); dsn != "root:****" {
+		t.Errorf("displayDSN() = %q, want the token masked", dsn)
+	}
+}
+
+func TestDefaultConfig_Token(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, env := range doltTokenEnvs {
+		t.Setenv(env, "")
+	}
+
+	if config := DefaultConfig(townRoot); config.Token != "" {
+		t.Errorf("Token = %q, want empty without env or daemon.json", config.Token)
+	}
+
+	mayor := filepath.Join(townRoot, "mayor")
+	if err := os.MkdirAll(mayor, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	daemon := `{"patrols": {}, "dolt_server": {"enabled": true, "token": "file-token"}}`
+	if err := os.WriteFile(filepath.Join(mayor, "daemon.json"), []byte(daemon), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if config := DefaultConfig(townRoot); config.Token != "file-token" {
+		t.Errorf("Token = %q, want %q from daemon.json", config.Token, "file-token")
+	}
+
+	t.Setenv("GASTOWN_DOLT_TOKEN", "gastown-token")
+	if config := DefaultConfig(townRoot); config.Token != "gastown-token" {
+		t.Errorf("Token = %q, want GASTOWN_DOLT_TOKEN over daemon.json", config.Token)
+	}
+	t.Setenv("GT_DOLT_TOKEN", "gt-token")
+	t.Setenv("GT_DOLT_PASSWORD", "mypass")
+	config := DefaultConfig(townRoot)
+	if config.Token != "gt-token" {
+		t.Errorf("Token = %q, want GT_DOLT_TOKEN first", config.Token)
+	}
+	if config.Credential() != "gt-token" {
+		t.Errorf("Credential() = %q, want the token over the password", config.Credential())
+	}
+}
+
+func TestPing_MeasuresLatency(t *testing.T) {
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+
+	var gotArgs []string
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		gotArgs = cmd.Args
+		time.Sleep(20 * time.Millisecond)
+		return []byte("1\n"), nil
+	}
+
+	config := &Config{Host: "10.0.0.5", Port: 3307, User: "root"}
+	latency, err := Ping(t.Context(), config)
+	if err != nil {
+		t.Fatalf("Ping() error: %v", err)
+	}
+	if latency < 20*time.Millisecond {
+		t.Errorf("Ping() latency = %v, want >= 20ms", latency)
+	}
+
+	argStr := strings.Join(gotArgs, " ")
+	for _, want := range []string{"sql", "--host 10.0.0.5", "-q SELECT 1"} {
+		if !strings.Contains(argStr, want) {
+			t.Errorf("args %q missing expected %q", argStr, want)
+		}
+	}
+}
+
+func TestPing_Error(t *testing.T) {
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		return []byte("connection refused"), fmt.Errorf("exit status 1")
+	}
+
+	latency, err := Ping(t.Context(), &Config{DataDir: t.TempDir()})
+	if err == nil {
+		t.Fatal("Ping() expected error")
+	}
+	if latency != 0 {
+		t.Errorf("Ping() latency = %v, want 0 on error", latency)
+	}
+	if !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Ping() error = %v, want dolt output included", err)
+	}
+}
@@ -0,0 +1,104 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reconnect policy for dropped server connections. Attempts count reconnects
+// after the first failure, so an operation runs at most maxReconnects+1 times.
+const (
+	maxReconnects       = 3
+	maxReconnectBackoff = 8 * time.Second
+)
+
+// reconnectBaseBackoff is the wait before the second reconnect (the first
+// is immediate), doubling per attempt up to maxReconnectBackoff. It is a var so tests can shorten it.
+var reconnectBaseBackoff = 500 * time.Millisecond
+
+// isDoltConnectionError reports whether err means the connection to the SQL
+// server was lost or refused, e.g. because the server process died.
+func isDoltConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "bad connection") ||
+		strings.Contains(msg, "server has gone away") ||
+		strings.Contains(msg, "lost connection")
+}
+
+// errServerNotStarted is returned by reconnectDolt when a local server was
+// never started (or was stopped on purpose), so a connection failure is not
+// a crash and nothing should be restarted behind the user's back.
+var errServerNotStarted = errors.New("dolt server was not started")
+
+// reconnectDolt restores connectivity after a dropped connection. A local
+// server whose state file says it should be running but whose process is
+// gone has crashed, and is restarted. A remote server cannot be restarted
+// from here, so only the retry happens. It is a var so tests can substitute
+// it.
+var reconnectDolt = func(townRoot string) error {
+	if DefaultConfig(townRoot).IsRemote() {
+		return nil
+	}
+	running, _, err := IsRunning(townRoot)
+	if err != nil {
+		return err
+	}
+	if running {
+		return nil
+	}
+	state, err := LoadState(townRoot)
+	if err != nil || !state.Running {
+		return errServerNotStarted
+	}
+	return Start(townRoot)
+}
+
+// withReconnect runs op, and when it fails because the server connection
+// dropped, reconnects and retries it, backing off between attempts. Against
+// a remote server any transient network failure is retried, up to the
+// configured retry count (see effectiveSQLRetries). Other errors are
+// returned unchanged, so callers keep their own retry classification.
+// Callers must ensure op is safe to repeat, as for doltSQLScriptWithRetry.
+func withReconnect(townRoot string, op func() error) error {
+	retryable, attempts := isDoltConnectionError, maxReconnects
+	if DefaultConfig(townRoot).IsRemote() {
+		retryable, attempts = isDoltNetworkError, effectiveSQLRetries()
+	}
+
+	err := op()
+	if !retryable(err) || attempts == 0 {
+		return err
+	}
+
+	backoff := reconnectBaseBackoff
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxReconnectBackoff {
+				backoff = maxReconnectBackoff
+			}
+		}
+
+		if rerr := reconnectDolt(townRoot); rerr != nil {
+			if errors.Is(rerr, errServerNotStarted) {
+				return err
+			}
+			err = fmt.Errorf("reconnecting: %w", rerr)
+			continue
+		}
+		err = op()
+		if !retryable(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("dolt server unreachable after %d reconnect attempts: %w", attempts, err)
+}
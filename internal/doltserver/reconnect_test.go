@@ -0,0 +1,106 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// stubReconnect replaces reconnectDolt and zeroes the backoff.
+func stubReconnect(t *testing.T, fn func(townRoot string) error) *int {
+	t.Helper()
+	origReconnect, origBackoff := reconnectDolt, reconnectBaseBackoff
+	t.Cleanup(func() { reconnectDolt, reconnectBaseBackoff = origReconnect, origBackoff })
+
+	calls := 0
+	reconnectDolt = func(townRoot string) error {
+		calls++
+		return fn(townRoot)
+	}
+	reconnectBaseBackoff = 0
+	return &calls
+}
+
+func TestDoltSQLQuery_ReconnectsAfterDroppedConnection(t *testing.T) {
+	reconnects := stubReconnect(t, func(string) error { return nil })
+
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+	runs := 0
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		runs++
+		if runs == 1 {
+			return []byte("dial tcp 127.0.0.1:3307: connect: connection refused"), fmt.Errorf("exit status 1")
+		}
+		return []byte("id\nw-1\n"), nil
+	}
+
+	out, err := doltSQLQuery(t.TempDir(), "SELECT id FROM wanted")
+	if err != nil {
+		t.Fatalf("doltSQLQuery() error: %v", err)
+	}
+	if rows := parseSimpleCSV(out); len(rows) != 1 || rows[0]["id"] != "w-1" {
+		t.Errorf("rows = %v", rows)
+	}
+	if runs != 2 || *reconnects != 1 {
+		t.Errorf("runs = %d, reconnects = %d; want 2 and 1", runs, *reconnects)
+	}
+}
+
+func TestWithReconnect_GivesUpAfterMaxAttempts(t *testing.T) {
+	reconnects := stubReconnect(t, func(string) error { return nil })
+
+	runs := 0
+	err := withReconnect(t.TempDir(), func() error {
+		runs++
+		return errors.New("write: broken pipe")
+	})
+	if err == nil || !strings.Contains(err.Error(), "unreachable after 3 reconnect attempts") {
+		t.Fatalf("withReconnect() error = %v, want exhausted error", err)
+	}
+	if runs != maxReconnects+1 || *reconnects != maxReconnects {
+		t.Errorf("runs = %d, reconnects = %d", runs, *reconnects)
+	}
+}
+
+func TestWithReconnect_DoesNotStartStoppedServer(t *testing.T) {
+	stubReconnect(t, func(string) error { return errServerNotStarted })
+
+	runs := 0
+	original := errors.New("connection refused")
+	err := withReconnect(t.TempDir(), func() error {
+		runs++
+		return original
+	})
+	if err != original || runs != 1 {
+		t.Errorf("withReconnect() = %v after %d runs, want the original error after 1", err, runs)
+	}
+}
+
+func TestWithReconnect_IgnoresOtherErrors(t *testing.T) {
+	reconnects := stubReconnect(t, func(string) error { return nil })
+
+	err := withReconnect(t.TempDir(), func() error { return errors.New("table not found: wanted") })
+	if err == nil || *reconnects != 0 {
+		t.Errorf("withReconnect() = %v with %d reconnects, want error and none", err, *reconnects)
+	}
+}
+
+func TestIsDoltConnectionError(t *testing.T) {
+	t.Parallel()
+	for msg, want := range map[string]bool{
+		"connect: connection refused":            true,
+		"read: connection reset by peer":         true,
+		"write: broken pipe":                     true,
+		"driver: bad connection":                 true,
+		"Error 2006: MySQL server has gone away": true,
+		"optimistic lock failed":                 false,
+		"nothing to commit":                      false,
+	} {
+		if got := isDoltConnectionError(errors.New(msg)); got != want {
+			t.Errorf("isDoltConnectionError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqlEscaper escapes a string for a single-quoted Dolt (MySQL) literal: the
+// quote itself, the backslash escape character, and the control characters
+// MySQL's own client escapes (NUL, newline, carriage return, Ctrl-Z), which
+// a script read over stdin must not see raw.
+var sqlEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"'", "''",
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+// BindSQL returns query with each ? placeholder replaced by the matching
+// argument rendered as a SQL literal, for scripts run through the dolt sql
+// CLI, which takes no bound parameters. Placeholders inside quoted literals
+// and backquoted identifiers are left alone.
+//
+// Supported arguments are string (quoted and escaped with EscapeSQL), nil
+// (NULL), bool, int, int64, float64, and time.Time (UTC DATETIME). A count
+// mismatch or an unsupported type is a programming error and panics.
+func BindSQL(query string, args ...any) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	next := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			b.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(query) {
+				i++
+				b.WriteByte(query[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case c == '?':
+			if next >= len(args) {
+				panic(fmt.Sprintf("BindSQL: more placeholders than the %d argument(s) in %q", len(args), query))
+			}
+			b.WriteString(bindLiteral(args[next]))
+			next++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if next != len(args) {
+		panic(fmt.Sprintf("BindSQL: %d placeholder(s) for %d argument(s) in %q", next, len(args), query))
+	}
+	return b.String()
+}
+
+// bindLiteral renders v as a SQL literal for BindSQL. Types the archive
+// loader already renders go through sqlLiteral.
+func bindLiteral(v any) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return "'" + v.UTC().Format("2006-01-02 15:04:05") + "'"
+	}
+	lit, err := sqlLiteral(v)
+	if err != nil {
+		panic("BindSQL: " + err.Error())
+	}
+	return lit
+}
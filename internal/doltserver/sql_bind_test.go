@@ -0,0 +1,67 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindSQL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+		want  string
+	}{
+		{"string", "WHERE id=?", []any{"w-1"}, "WHERE id='w-1'"},
+		{"backslash title", "SET title=?", []any{`C:\temp\`}, `SET title='C:\\temp\\'`},
+		{"comment markers", "SET title=?;", []any{"x'; -- /* DROP TABLE wanted; #"}, "SET title='x''; -- /* DROP TABLE wanted; #';"},
+		{"control characters", "SET d=?", []any{"a\nb\x00c\r\x1a"}, `SET d='a\nb\0c\r\Z'`},
+		{"non-string types", "VALUES (?, ?, ?, ?)", []any{nil, 3, true, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+			"VALUES (NULL, 3, TRUE, '2026-01-02 03:04:05')"},
+		{"placeholder in literal", "WHERE a='?' AND b=? AND c=`?`", []any{"x"}, "WHERE a='?' AND b='x' AND c=`?`"},
+		{"escaped quote in literal", `WHERE a='it''s ?' AND b='\'?' AND c=?`, []any{"x"}, `WHERE a='it''s ?' AND b='\'?' AND c='x'`},
+		{"bound value with placeholder", "WHERE a=? AND b=?", []any{"why?", "x"}, "WHERE a='why?' AND b='x'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := BindSQL(tt.query, tt.args...); got != tt.want {
+				t.Errorf("BindSQL(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindSQL_Mismatch(t *testing.T) {
+	t.Parallel()
+	for name, call := range map[string]func(){
+		"too few args":     func() { BindSQL("a=? AND b=?", "x") },
+		"too many args":    func() { BindSQL("a=?", "x", "y") },
+		"unsupported type": func() { BindSQL("a=?", struct{}{}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Error("BindSQL() should panic")
+				}
+			}()
+			call()
+		})
+	}
+}
+
+func TestClaimWantedScript_HostileInput(t *testing.T) {
+	t.Parallel()
+	script := ClaimWantedScript(`w-1\`, "rig'; -- x")
+	for _, want := range []string{
+		"claimed_by='rig''; -- x'",
+		`WHERE id='w-1\\' AND status='open';`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("claim script missing %q:\n%s", want, script)
+		}
+	}
+}
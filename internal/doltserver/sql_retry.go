@@ -0,0 +1,74 @@
+// Package doltserver - sql_retry.go decides how often a dolt sql call to a
+// remote server is retried after a network failure.
+package doltserver
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultRemoteSQLRetries is how many times a dolt sql call to a remote
+// server is retried after a network failure, unless configured otherwise.
+const DefaultRemoteSQLRetries = maxReconnects
+
+// SQLRetriesEnv names the environment variable that sets the retry count
+// for a remote server. 0 turns retries off.
+const SQLRetriesEnv = "GT_DOLT_RETRIES"
+
+// sqlRetries overrides the retry count when non-negative (see
+// SetSQLRetries).
+var sqlRetries = -1
+
+// SetSQLRetries makes n the retry count for every dolt sql call to a remote
+// server that follows, overriding GT_DOLT_RETRIES. A negative n restores
+// the default.
+func SetSQLRetries(n int) {
+	sqlRetries = n
+}
+
+// effectiveSQLRetries returns the retry count for a remote server: the
+// SetSQLRetries override, else a valid GT_DOLT_RETRIES, else
+// DefaultRemoteSQLRetries.
+func effectiveSQLRetries() int {
+	if sqlRetries >= 0 {
+		return sqlRetries
+	}
+	if env := os.Getenv(SQLRetriesEnv); env != "" {
+		if n, err := strconv.Atoi(strings.TrimSpace(env)); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return DefaultRemoteSQLRetries
+}
+
+// doltNetworkSignatures are substrings of dolt's stderr when a remote server
+// could not be reached in time, beyond the dropped connections
+// isDoltConnectionError already covers.
+var doltNetworkSignatures = []string{
+	"i/o timeout",
+	"connection timed out",
+	"no route to host",
+	"network is unreachable",
+	"temporary failure in name resolution",
+	"tls handshake timeout",
+}
+
+// isDoltNetworkError reports whether err is a transient network failure
+// talking to the server. Only network signatures match, so SQL errors such
+// as constraint violations never do and fail at once.
+func isDoltNetworkError(err error) bool {
+	if isDoltConnectionError(err) {
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, sig := range doltNetworkSignatures {
+		if strings.Contains(msg, sig) {
+			return true
+		}
+	}
+	return false
+}
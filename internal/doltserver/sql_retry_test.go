@@ -0,0 +1,119 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// stubDoltRuns replaces runDoltCmd with one that answers each run from
+// outputs in turn, failing on entries that start with "ERR ". It returns
+// the run count.
+func stubDoltRuns(t *testing.T, outputs ...string) *int {
+	t.Helper()
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+	runs := 0
+	runDoltCmd = func(*exec.Cmd) ([]byte, error) {
+		out := outputs[min(runs, len(outputs)-1)]
+		runs++
+		if msg, ok := strings.CutPrefix(out, "ERR "); ok {
+			return []byte(msg), fmt.Errorf("exit status 1")
+		}
+		return []byte(out), nil
+	}
+	return &runs
+}
+
+func TestDoltSQLQuery_RetriesRemoteNetworkFailure(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+	stubReconnect(t, func(string) error { return nil })
+	runs := stubDoltRuns(t, "ERR dial tcp 203.0.113.7:3306: i/o timeout", "id\nw-1\n")
+
+	out, err := doltSQLQuery(t.TempDir(), "SELECT id FROM wanted")
+	if err != nil {
+		t.Fatalf("doltSQLQuery() error: %v", err)
+	}
+	if rows := parseSimpleCSV(out); len(rows) != 1 || rows[0]["id"] != "w-1" {
+		t.Errorf("rows = %v", rows)
+	}
+	if *runs != 2 {
+		t.Errorf("runs = %d, want 2", *runs)
+	}
+}
+
+func TestDoltSQLScript_RemoteSQLErrorFailsFast(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+	stubReconnect(t, func(string) error { return nil })
+	runs := stubDoltRuns(t, "ERR duplicate primary key given: [c-1]")
+
+	err := doltSQLScriptWithRetry(t.TempDir(), "INSERT INTO completions (id) VALUES ('c-1');")
+	if err == nil || !strings.Contains(err.Error(), "duplicate primary key") {
+		t.Fatalf("doltSQLScriptWithRetry() error = %v, want the constraint violation", err)
+	}
+	if *runs != 1 {
+		t.Errorf("runs = %d, want 1: SQL errors must not be retried", *runs)
+	}
+}
+
+func TestWithReconnect_RemoteRetryCount(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+	stubReconnect(t, func(string) error { return nil })
+	t.Cleanup(func() { SetSQLRetries(-1) })
+	timeout := errors.New("dial tcp 203.0.113.7:3306: i/o timeout")
+
+	for _, tc := range []struct {
+		env      string
+		override int
+		wantRuns int
+	}{
+		{override: -1, wantRuns: DefaultRemoteSQLRetries + 1},
+		{env: "1", override: -1, wantRuns: 2},
+		{env: "5", override: 0, wantRuns: 1},
+		{env: "bogus", override: -1, wantRuns: DefaultRemoteSQLRetries + 1},
+	} {
+		t.Setenv(SQLRetriesEnv, tc.env)
+		SetSQLRetries(tc.override)
+		runs := 0
+		err := withReconnect(t.TempDir(), func() error {
+			runs++
+			return timeout
+		})
+		if !errors.Is(err, timeout) || runs != tc.wantRuns {
+			t.Errorf("env %q override %d: withReconnect() = %v after %d runs, want the timeout after %d",
+				tc.env, tc.override, err, runs, tc.wantRuns)
+		}
+	}
+}
+
+func TestWithReconnect_LocalDoesNotRetryTimeouts(t *testing.T) {
+	reconnects := stubReconnect(t, func(string) error { return nil })
+
+	runs := 0
+	err := withReconnect(t.TempDir(), func() error {
+		runs++
+		return errors.New("dial tcp 127.0.0.1:3307: i/o timeout")
+	})
+	if err == nil || runs != 1 || *reconnects != 0 {
+		t.Errorf("withReconnect() = %v after %d runs and %d reconnects, want one run", err, runs, *reconnects)
+	}
+}
+
+func TestIsDoltNetworkError(t *testing.T) {
+	t.Parallel()
+	for msg, want := range map[string]bool{
+		"connect: connection refused":                     true,
+		"dial tcp 203.0.113.7:3306: i/o timeout":          true,
+		"connect: no route to host":                       true,
+		"dial tcp: lookup dolt.example.com: no such host": false,
+		"Temporary failure in name resolution":            true,
+		"duplicate primary key given: [c-1]":              false,
+		"lock wait timeout exceeded":                      false,
+	} {
+		if got := isDoltNetworkError(errors.New(msg)); got != want {
+			t.Errorf("isDoltNetworkError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package doltserver - sql_timeout.go bounds how long one dolt sql call may
+// run, and lets the caller cancel it.
+package doltserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Built-in limits on one dolt sql invocation: a single query, and a
+// multi-statement write script.
+const (
+	DefaultSQLQueryTimeout  = 15 * time.Second
+	DefaultSQLScriptTimeout = 30 * time.Second
+)
+
+// SQLTimeoutEnv names the environment variable that overrides both limits,
+// for servers behind a slow link.
+const SQLTimeoutEnv = "GT_DOLT_TIMEOUT"
+
+// sqlTimeout overrides both limits when positive (see SetSQLTimeout).
+var sqlTimeout time.Duration
+
+// SetSQLTimeout makes d the limit on every dolt sql call that follows,
+// overriding both the built-in limits and GT_DOLT_TIMEOUT. Zero restores
+// them.
+func SetSQLTimeout(d time.Duration) {
+	sqlTimeout = d
+}
+
+// ParseSQLTimeout parses a timeout given as a Go duration ("45s", "2m") or
+// as whole seconds ("45"). It must be positive.
+func ParseSQLTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		secs, serr := strconv.Atoi(s)
+		if serr != nil {
+			return 0, fmt.Errorf("invalid timeout %q: use a duration like 45s or a number of seconds", s)
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", s)
+	}
+	return d, nil
+}
+
+// effectiveSQLTimeout returns the limit for a call whose built-in limit is
+// def: the SetSQLTimeout override, else a valid GT_DOLT_TIMEOUT, else def.
+func effectiveSQLTimeout(def time.Duration) time.Duration {
+	if sqlTimeout > 0 {
+		return sqlTimeout
+	}
+	if env := os.Getenv(SQLTimeoutEnv); env != "" {
+		if d, err := ParseSQLTimeout(env); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// ErrSQLCancelled is returned by a dolt sql call whose context (see
+// SetSQLContext) was cancelled, e.g. by Ctrl-C. The dolt child process has
+// been killed by then.
+var ErrSQLCancelled = errors.New("dolt query cancelled")
+
+// sqlContext is the parent of every dolt sql call's timeout context.
+var sqlContext = context.Background()
+
+// SetSQLContext makes ctx the parent of every dolt sql call that follows,
+// so cancelling it kills a running dolt process at once instead of
+// waiting out the timeout. The timeout still applies beneath it. A nil
+// ctx restores context.Background.
+func SetSQLContext(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sqlContext = ctx
+}
+
+// sqlTimeoutError reports a dolt sql call cut off by its deadline, so the
+// user can tell a slow server from a failed statement, or ErrSQLCancelled
+// when the call was cancelled instead. It returns nil when ctx is live.
+func sqlTimeoutError(ctx context.Context, limit time.Duration) error {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("dolt query timed out after %s; allow longer with %s (or gt wl --timeout)", limit, SQLTimeoutEnv)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return ErrSQLCancelled
+	}
+	return nil
+}
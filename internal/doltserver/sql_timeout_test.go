@@ -0,0 +1,69 @@
+package doltserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSQLTimeout(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]time.Duration{"45s": 45 * time.Second, "2m": 2 * time.Minute, "90": 90 * time.Second, " 10 ": 10 * time.Second} {
+		if got, err := ParseSQLTimeout(in); err != nil || got != want {
+			t.Errorf("ParseSQLTimeout(%q) = %s, %v; want %s", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "0", "-5s"} {
+		if _, err := ParseSQLTimeout(bad); err == nil {
+			t.Errorf("ParseSQLTimeout(%q) = nil error, want invalid", bad)
+		}
+	}
+}
+
+func TestEffectiveSQLTimeout(t *testing.T) {
+	t.Cleanup(func() { SetSQLTimeout(0) })
+
+	t.Setenv(SQLTimeoutEnv, "")
+	if got := effectiveSQLTimeout(DefaultSQLQueryTimeout); got != DefaultSQLQueryTimeout {
+		t.Errorf("default = %s, want %s", got, DefaultSQLQueryTimeout)
+	}
+	t.Setenv(SQLTimeoutEnv, "bogus")
+	if got := effectiveSQLTimeout(DefaultSQLScriptTimeout); got != DefaultSQLScriptTimeout {
+		t.Errorf("invalid %s = %s, want the default %s", SQLTimeoutEnv, got, DefaultSQLScriptTimeout)
+	}
+	t.Setenv(SQLTimeoutEnv, "2m")
+	if got := effectiveSQLTimeout(DefaultSQLQueryTimeout); got != 2*time.Minute {
+		t.Errorf("%s=2m gives %s", SQLTimeoutEnv, got)
+	}
+	SetSQLTimeout(time.Minute)
+	if got := effectiveSQLTimeout(DefaultSQLQueryTimeout); got != time.Minute {
+		t.Errorf("SetSQLTimeout(1m) gives %s, want it to beat %s", got, SQLTimeoutEnv)
+	}
+}
+
+func TestSQLTimeoutError(t *testing.T) {
+	t.Parallel()
+	if err := sqlTimeoutError(context.Background(), time.Second); err != nil {
+		t.Errorf("live context: %v, want nil", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	err := sqlTimeoutError(ctx, 10*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "dolt query timed out after 10s") {
+		t.Errorf("expired context: %v, want a timed-out error", err)
+	}
+}
+
+func TestSQLTimeoutError_Cancelled(t *testing.T) {
+	t.Parallel()
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := context.WithTimeout(parent, time.Hour)
+	defer cancel()
+	cancelParent()
+	if err := sqlTimeoutError(ctx, time.Hour); !errors.Is(err, ErrSQLCancelled) {
+		t.Errorf("cancelled parent: %v, want ErrSQLCancelled", err)
+	}
+}
@@ -0,0 +1,135 @@
+package doltserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TxResult reports the effect of one statement in an ExecTx batch.
+type TxResult struct {
+	// RowsAffected is ROW_COUNT() after the statement: rows changed by
+	// INSERT/UPDATE/DELETE, -1 for statements that return a result set.
+	RowsAffected int64
+
+	// LastInsertID is LAST_INSERT_ID() after the statement. It is only
+	// meaningful for INSERTs into tables with an AUTO_INCREMENT key.
+	LastInsertID int64
+}
+
+// ExecTx runs stmts against database inside a single SQL transaction and
+// returns one TxResult per statement.
+//
+// The batch is sent as one `dolt sql --file` script, which stops at the first
+// failing statement. COMMIT is the last transactional statement, so a failure
+// anywhere leaves the transaction open when the connection closes and the
+// server rolls it back: either every statement applies or none do.
+//
+// If commitMsg is non-empty, the working set is also recorded as a Dolt
+// commit after the SQL commit. Empty stmts is a no-op. Under dry run the
+// script is printed instead and every result is zero.
+func ExecTx(ctx context.Context, config *Config, database, commitMsg string, stmts []string) ([]TxResult, error) {
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+
+	script, err := buildTxScript(database, commitMsg, stmts)
+	if err != nil {
+		return nil, err
+	}
+	if wlDryRun != nil {
+		fmt.Fprintf(wlDryRun, "-- dry run, not executed:\n%s\n", strings.TrimRight(script, "\n"))
+		return make([]TxResult, len(stmts)), nil
+	}
+
+	tmpFile, err := os.CreateTemp("", "dolt-tx-*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp SQL file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(script); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("writing SQL script: %w", err)
+	}
+	tmpFile.Close()
+
+	cmd := buildDoltSQLCmd(ctx, config, "-r", "csv", "--file", tmpFile.Name())
+	output, err := runDoltCmd(cmd)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("transaction on %s: %w", database, ctxErr)
+		}
+		return nil, fmt.Errorf("transaction on %s rolled back: %w (output: %s)", database, err, strings.TrimSpace(string(output)))
+	}
+
+	return parseTxResults(string(output), len(stmts))
+}
+
+// buildTxScript wraps stmts in a transaction, capturing ROW_COUNT() and
+// LAST_INSERT_ID() into session variables after each statement and selecting
+// them all in one final result set.
+func buildTxScript(database, commitMsg string, stmts []string) (string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "USE `%s`;\n", database)
+	sb.WriteString("START TRANSACTION;\n")
+
+	cols := make([]string, 0, 2*len(stmts))
+	for i, stmt := range stmts {
+		stmt = strings.TrimRight(strings.TrimSpace(stmt), ";")
+		if stmt == "" {
+			return "", fmt.Errorf("statement %d is empty", i)
+		}
+		sb.WriteString(stmt)
+		sb.WriteString(";\n")
+		fmt.Fprintf(&sb, "SET @gt_tx_rows_%d = ROW_COUNT(), @gt_tx_id_%d = LAST_INSERT_ID();\n", i, i)
+		cols = append(cols,
+			fmt.Sprintf("@gt_tx_rows_%d AS rows_%d", i, i),
+			fmt.Sprintf("@gt_tx_id_%d AS id_%d", i, i))
+	}
+
+	sb.WriteString("COMMIT;\n")
+	if commitMsg != "" {
+		sb.WriteString("CALL DOLT_ADD('-A');\n")
+		fmt.Fprintf(&sb, "CALL DOLT_COMMIT('--allow-empty', '-m', '%s');\n", EscapeSQL(commitMsg))
+	}
+	fmt.Fprintf(&sb, "SELECT %s;\n", strings.Join(cols, ", "))
+	return sb.String(), nil
+}
+
+// parseTxResults extracts the final result set written by buildTxScript.
+// Earlier result sets (e.g. the DOLT_COMMIT hash) are skipped.
+func parseTxResults(output string, n int) ([]TxResult, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	header := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "rows_0,") {
+			header = i
+			break
+		}
+	}
+	if header < 0 || header+1 >= len(lines) {
+		return nil, fmt.Errorf("transaction committed but results are missing from output")
+	}
+
+	fields := parseCSVLine(strings.TrimSpace(lines[header+1]))
+	if len(fields) != 2*n {
+		return nil, fmt.Errorf("transaction committed but returned %d result fields, want %d", len(fields), 2*n)
+	}
+
+	results := make([]TxResult, n)
+	for i := range results {
+		rows, err := strconv.ParseInt(fields[2*i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing affected rows for statement %d: %w", i, err)
+		}
+		id, err := strconv.ParseInt(fields[2*i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing last insert id for statement %d: %w", i, err)
+		}
+		results[i] = TxResult{RowsAffected: rows, LastInsertID: id}
+	}
+	return results, nil
+}
@@ -0,0 +1,106 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// fakeTxRunner stubs runDoltCmd, capturing the script passed via --file.
+func fakeTxRunner(t *testing.T, output string, runErr error) *string {
+	t.Helper()
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+
+	var script string
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		for i, arg := range cmd.Args {
+			if arg == "--file" && i+1 < len(cmd.Args) {
+				data, err := os.ReadFile(cmd.Args[i+1])
+				if err != nil {
+					t.Fatalf("reading tx script: %v", err)
+				}
+				script = string(data)
+			}
+		}
+		return []byte(output), runErr
+	}
+	return &script
+}
+
+func TestExecTx_Commit(t *testing.T) {
+	output := "hash\nabc123\nrows_0,id_0,rows_1,id_1\n1,0,3,0\n"
+	script := fakeTxRunner(t, output, nil)
+
+	results, err := ExecTx(t.Context(), &Config{DataDir: t.TempDir()}, WLCommonsDB, "batch claim", []string{
+		"INSERT INTO wanted (id, title) VALUES ('w-1', 'one');",
+		"UPDATE wanted SET status = 'claimed' WHERE status = 'open'",
+	})
+	if err != nil {
+		t.Fatalf("ExecTx() error: %v", err)
+	}
+	want := []TxResult{{RowsAffected: 1}, {RowsAffected: 3}}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+
+	// COMMIT must follow every statement so a mid-batch failure never commits.
+	start := strings.Index(*script, "START TRANSACTION;")
+	update := strings.Index(*script, "UPDATE wanted")
+	commit := strings.Index(*script, "COMMIT;\n")
+	if start < 0 || update < start || commit < update {
+		t.Errorf("statements not wrapped in transaction:\n%s", *script)
+	}
+	if strings.Contains(*script, "'one');;") {
+		t.Error("trailing semicolons should be normalized")
+	}
+	if !strings.Contains(*script, "CALL DOLT_COMMIT('--allow-empty', '-m', 'batch claim');") {
+		t.Errorf("script missing Dolt commit:\n%s", *script)
+	}
+}
+
+func TestExecTx_MidBatchFailureRollsBack(t *testing.T) {
+	fakeTxRunner(t, "error on line 4: duplicate primary key", fmt.Errorf("exit status 1"))
+
+	results, err := ExecTx(t.Context(), &Config{DataDir: t.TempDir()}, WLCommonsDB, "", []string{
+		"INSERT INTO wanted (id, title) VALUES ('w-1', 'one')",
+		"INSERT INTO wanted (id, title) VALUES ('w-1', 'dup')",
+	})
+	if err == nil {
+		t.Fatal("ExecTx() expected error")
+	}
+	if results != nil {
+		t.Errorf("results = %+v, want nil on failure", results)
+	}
+	if !strings.Contains(err.Error(), "rolled back") || !strings.Contains(err.Error(), "duplicate primary key") {
+		t.Errorf("error = %v, want rollback with dolt output", err)
+	}
+}
+
+func TestExecTx_EmptyInput(t *testing.T) {
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		t.Fatal("dolt should not run for an empty batch")
+		return nil, nil
+	}
+
+	results, err := ExecTx(t.Context(), &Config{DataDir: t.TempDir()}, WLCommonsDB, "msg", nil)
+	if err != nil || results != nil {
+		t.Errorf("ExecTx(nil) = %v, %v; want nil, nil", results, err)
+	}
+}
+
+func TestBuildTxScript_RejectsEmptyStatement(t *testing.T) {
+	t.Parallel()
+	if _, err := buildTxScript(WLCommonsDB, "", []string{"SELECT 1", " ; "}); err == nil {
+		t.Error("buildTxScript() should reject an empty statement")
+	}
+}
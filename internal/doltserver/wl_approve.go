@@ -0,0 +1,91 @@
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApproveCompletions accepts the current completion of every item in
+// wantedIDs on behalf of reviewer, in one Dolt commit: each completion is
+// stamped validated_by/validated_at and its item moves from in_review to
+// completed. Either every item is approved or none is; the batch fails if
+// any item has left in_review.
+func ApproveCompletions(townRoot string, wantedIDs []string, reviewer string) error {
+	if len(wantedIDs) == 0 {
+		return fmt.Errorf("no items to approve")
+	}
+
+	err := doltSQLScriptWithRetry(townRoot, ApproveCompletionsScript(wantedIDs, reviewer))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("not every one of %s is still in review; nothing was approved", strings.Join(wantedIDs, ", "))
+	}
+	return fmt.Errorf("approve failed: %w", err)
+}
+
+// ApproveCompletionsScript returns the SQL script ApproveCompletions
+// executes. The writes are guarded by a count of items still in review, so
+// a batch with a stale item changes nothing and leaves nothing to commit.
+func ApproveCompletionsScript(wantedIDs []string, reviewer string) string {
+	quoted := make([]string, len(wantedIDs))
+	for i, id := range wantedIDs {
+		quoted[i] = "'" + EscapeSQL(id) + "'"
+	}
+	in := strings.Join(quoted, ", ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "USE %s;\nSTART TRANSACTION;\n", WLCommonsDB)
+	fmt.Fprintf(&b, "SET @ready = (SELECT COUNT(*) FROM wanted WHERE id IN (%s) AND status='%s');\n", in, StatusInReview)
+	fmt.Fprintf(&b, `UPDATE completions SET validated_by='%s', validated_at=NOW()
+  WHERE wanted_id IN (%s) AND superseded_by IS NULL AND @ready = %d;
+UPDATE wanted SET status='%s', updated_at=NOW()
+  WHERE id IN (%s) AND status='%s' AND @ready = %d;
+`, EscapeSQL(reviewer), in, len(wantedIDs), StatusCompleted, in, StatusInReview, len(wantedIDs))
+	for _, id := range wantedIDs {
+		fmt.Fprintf(&b, `INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), '%s', 'approve', '%s', NULL, NOW() FROM dual WHERE @ready = %d;
+`, EscapeSQL(id), EscapeSQL(reviewer), len(wantedIDs))
+	}
+	fmt.Fprintf(&b, "COMMIT;\nCALL DOLT_ADD('-A');\nCALL DOLT_COMMIT('-m', '%s');\n",
+		EscapeSQL(wlCommitMessage("approve", strings.Join(wantedIDs, ", "), reviewer)))
+	return b.String()
+}
+
+// RejectCompletion sends wantedID back from in_review to claimed, so its
+// claimant can rework it and resubmit (see ResubmitCompletion). The rejected
+// completion stays current until the resubmission supersedes it. reason is
+// recorded as a 'reject' event in wanted_history, shown with the item's
+// comments. claimed_at restarts, so ReapClaim gives the rework a full TTL.
+func RejectCompletion(townRoot, wantedID, reviewer, reason string) error {
+	err := doltSQLScriptWithRetry(townRoot, RejectCompletionScript(wantedID, reviewer, reason))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is not in review or does not exist", wantedID)
+	}
+	return fmt.Errorf("reject failed: %w", err)
+}
+
+// RejectCompletionScript returns the SQL script RejectCompletion executes.
+// ROW_COUNT() gates the history insert, so an item not in review leaves
+// nothing to commit.
+func RejectCompletionScript(wantedID, reviewer, reason string) string {
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`UPDATE wanted SET status=?, claimed_at=NOW(), updated_at=NOW()
+  WHERE id=? AND status=?;
+SET @rejected = ROW_COUNT();
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), ?, 'reject', ?, ?, NOW() FROM dual WHERE @rejected > 0;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		StatusClaimed, wantedID, StatusInReview,
+		wantedID, reviewer, rejectCommentPrefix+reason,
+		wlCommitMessage("reject", wantedID, reviewer))
+}
+
+// rejectCommentPrefix starts the detail of a 'reject' history event, so the
+// reason reads as such among the item's comments.
+const rejectCommentPrefix = "rejected: "
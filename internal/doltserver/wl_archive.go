@@ -0,0 +1,342 @@
+// Package doltserver - wl_archive.go provides portable backup and restore of
+// the wl-commons database.
+//
+// An archive is a single JSON document holding every row of every wl-commons
+// table. It does not depend on DoltHub or on Dolt's storage format, so a town
+// can snapshot the board before a risky sync or purge and reload it later.
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// WLArchiveFormat identifies a wl-commons archive file.
+	WLArchiveFormat = "gt-wl-archive"
+	// WLArchiveVersion is the current archive layout version.
+	WLArchiveVersion = 1
+)
+
+// wlCommonsTables lists the tables captured by an archive, parents first.
+var wlCommonsTables = []string{
+	"_meta",
+	"rigs",
+	"wanted",
+	"wanted_deps",
+	"wanted_history",
+	"wl_watchers",
+	"wanted_labels",
+	"group_members",
+	"completions",
+	"stamps",
+	"badges",
+	"chain_meta",
+}
+
+// sqlIdentRe matches identifiers safe to interpolate into SQL.
+var sqlIdentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WLArchive is a portable snapshot of the wl-commons database.
+type WLArchive struct {
+	Format    string                      `json:"format"`
+	Version   int                         `json:"version"`
+	Database  string                      `json:"database"`
+	CreatedAt time.Time                   `json:"created_at"`
+	Tables    map[string][]map[string]any `json:"tables"`
+}
+
+// RowCount returns the total number of rows across all tables.
+func (a *WLArchive) RowCount() int {
+	n := 0
+	for _, rows := range a.Tables {
+		n += len(rows)
+	}
+	return n
+}
+
+// Validate checks the archive header and table layout. It never touches the
+// database, so restore can reject a bad file before deleting anything.
+func (a *WLArchive) Validate() error {
+	if a.Format != WLArchiveFormat {
+		return fmt.Errorf("not a wl-commons archive (format %q)", a.Format)
+	}
+	if a.Version != WLArchiveVersion {
+		return fmt.Errorf("unsupported archive version %d (want %d)", a.Version, WLArchiveVersion)
+	}
+	if len(a.Tables) == 0 {
+		return fmt.Errorf("archive contains no tables")
+	}
+	for table, rows := range a.Tables {
+		if !isWLCommonsTable(table) {
+			return fmt.Errorf("archive contains unknown table %q", table)
+		}
+		for i, row := range rows {
+			if len(row) == 0 {
+				return fmt.Errorf("table %s row %d is empty", table, i)
+			}
+			for col, v := range row {
+				if !sqlIdentRe.MatchString(col) {
+					return fmt.Errorf("table %s row %d has invalid column name %q", table, i, col)
+				}
+				if _, err := sqlLiteral(v); err != nil {
+					return fmt.Errorf("table %s row %d column %s: %w", table, i, col, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isWLCommonsTable(name string) bool {
+	for _, t := range wlCommonsTables {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadWLArchive decodes and validates an archive.
+func ReadWLArchive(r io.Reader) (*WLArchive, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var a WLArchive
+	if err := dec.Decode(&a); err != nil {
+		return nil, fmt.Errorf("decoding archive: %w", err)
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// WriteWLArchive encodes an archive as indented JSON.
+func WriteWLArchive(w io.Writer, a *WLArchive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+func newWLArchive() *WLArchive {
+	return &WLArchive{
+		Format:    WLArchiveFormat,
+		Version:   WLArchiveVersion,
+		Database:  WLCommonsDB,
+		CreatedAt: time.Now().UTC(),
+		Tables:    make(map[string][]map[string]any),
+	}
+}
+
+// ArchiveWLCommons dumps every wl-commons table. Tables missing from older
+// wastelands are skipped.
+func ArchiveWLCommons(townRoot string) (*WLArchive, error) {
+	a := newWLArchive()
+	for _, table := range wlCommonsTables {
+		rows, err := doltSQLQueryJSON(townRoot, fmt.Sprintf("SELECT * FROM %s.%s;", WLCommonsDB, table))
+		if err != nil {
+			if isTableNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("dumping %s: %w", table, err)
+		}
+		a.Tables[table] = rows
+	}
+	return a, nil
+}
+
+// RestoreWLCommons replaces the contents of the archived tables with the
+// archive's rows in a single Dolt commit. The database and schema are
+// created first if missing. Tables absent from the archive are left alone.
+func RestoreWLCommons(townRoot string, a *WLArchive) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if err := EnsureWLCommons(townRoot); err != nil {
+		return fmt.Errorf("ensuring wl-commons: %w", err)
+	}
+
+	return loadWLArchive(townRoot, a, true,
+		fmt.Sprintf("Restore wl-commons from archive (%s)", a.CreatedAt.UTC().Format(time.RFC3339)))
+}
+
+// loadWLArchive writes an archive's rows in one transaction and records
+// them as a single Dolt commit with message. A failed load rolls back and
+// leaves the tables as they were.
+func loadWLArchive(townRoot string, a *WLArchive, replace bool, message string) error {
+	stmts, err := archiveLoadStatements(a, replace)
+	if err != nil {
+		return err
+	}
+	config := DefaultConfig(townRoot)
+	return withReconnect(townRoot, func() error {
+		ctx, cancel := context.WithTimeout(sqlContext, effectiveSQLTimeout(DefaultSQLScriptTimeout))
+		defer cancel()
+		_, err := ExecTx(ctx, config, WLCommonsDB, message, stmts)
+		return err
+	})
+}
+
+// archiveLoadStatements renders the statements that load an archive's
+// rows. With replace, each archived table is cleared first; otherwise rows
+// are upserted by primary key and other rows kept.
+func archiveLoadStatements(a *WLArchive, replace bool) ([]string, error) {
+	insert := "REPLACE"
+	if replace {
+		insert = "INSERT"
+	}
+	var stmts []string
+	for _, table := range wlCommonsTables {
+		rows, ok := a.Tables[table]
+		if !ok {
+			continue
+		}
+		if replace {
+			stmts = append(stmts, fmt.Sprintf("DELETE FROM %s", table))
+		}
+		for _, row := range rows {
+			cols := make([]string, 0, len(row))
+			for col := range row {
+				cols = append(cols, col)
+			}
+			sort.Strings(cols)
+
+			quoted := make([]string, len(cols))
+			values := make([]string, len(cols))
+			for i, col := range cols {
+				lit, err := sqlLiteral(row[col])
+				if err != nil {
+					return nil, fmt.Errorf("table %s column %s: %w", table, col, err)
+				}
+				quoted[i] = "`" + col + "`"
+				values[i] = lit
+			}
+			stmts = append(stmts, fmt.Sprintf("%s INTO %s (%s) VALUES (%s)",
+				insert, table, strings.Join(quoted, ", "), strings.Join(values, ", ")))
+		}
+	}
+	return stmts, nil
+}
+
+// SnapshotWLCommons archives only the given tables, for a pre-write
+// snapshot before they are replaced wholesale. Unknown and missing tables
+// are skipped.
+func SnapshotWLCommons(townRoot string, tables []string) (*WLArchive, error) {
+	a := newWLArchive()
+	for _, table := range tables {
+		if !isWLCommonsTable(table) {
+			continue
+		}
+		rows, err := doltSQLQueryJSON(townRoot, fmt.Sprintf("SELECT * FROM %s.%s;", WLCommonsDB, table))
+		if err != nil {
+			if isTableNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("snapshotting %s: %w", table, err)
+		}
+		a.Tables[table] = rows
+	}
+	return a, nil
+}
+
+// SnapshotWantedRows archives the wanted rows for ids, for a pre-write
+// snapshot before they are modified in place.
+func SnapshotWantedRows(townRoot string, ids []string) (*WLArchive, error) {
+	a := newWLArchive()
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "'" + EscapeSQL(id) + "'"
+	}
+	rows, err := doltSQLQueryJSON(townRoot, fmt.Sprintf("SELECT * FROM %s.wanted WHERE id IN (%s);",
+		WLCommonsDB, strings.Join(quoted, ", ")))
+	if err != nil {
+		return nil, fmt.Errorf("snapshotting wanted: %w", err)
+	}
+	a.Tables["wanted"] = rows
+	return a, nil
+}
+
+// ReplayWLSnapshot writes a snapshot's rows back in one Dolt commit. With
+// replace, each snapshotted table is cleared first, undoing a wholesale
+// reload; otherwise the rows are upserted, undoing in-place updates.
+func ReplayWLSnapshot(townRoot string, a *WLArchive, replace bool, message string) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	return loadWLArchive(townRoot, a, replace, message)
+}
+
+// sqlLiteral renders a decoded JSON value as a SQL literal. Nested objects and
+// arrays (JSON columns) are re-encoded and stored as strings.
+func sqlLiteral(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return "'" + EscapeSQL(val) + "'", nil
+	case json.Number:
+		if _, err := val.Float64(); err != nil {
+			return "", fmt.Errorf("invalid number %q", val)
+		}
+		return val.String(), nil
+	case float64:
+		return fmt.Sprintf("%v", val), nil
+	case bool:
+		if val {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case map[string]any, []any:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return "'" + EscapeSQL(string(b)) + "'", nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// doltSQLQueryJSON runs a query with JSON output and returns its rows.
+// Numbers are preserved as json.Number.
+func doltSQLQueryJSON(townRoot, query string) ([]map[string]any, error) {
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(sqlContext, 30*time.Second)
+	defer cancel()
+
+	cmd := buildDoltSQLCmd(ctx, config, "-r", "json", "-q", query)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, ErrSQLCancelled
+		}
+		return nil, fmt.Errorf("dolt sql query failed: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return parseDoltJSONRows(output)
+}
+
+// parseDoltJSONRows parses `dolt sql -r json` output ({"rows": [...]}).
+// Empty output means an empty result set.
+func parseDoltJSONRows(output []byte) ([]map[string]any, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(output))
+	dec.UseNumber()
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("parsing dolt json output: %w", err)
+	}
+	return result.Rows, nil
+}
@@ -0,0 +1,156 @@
+package doltserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testArchive() *WLArchive {
+	return &WLArchive{
+		Format:    WLArchiveFormat,
+		Version:   WLArchiveVersion,
+		Database:  WLCommonsDB,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Tables: map[string][]map[string]any{
+			"_meta": {{"key": "schema_version", "value": "1.0"}},
+			"wanted": {{
+				"id":       "w-abc",
+				"title":    "It's broken",
+				"priority": json.Number("1"),
+				"tags":     []any{"go", "auth"},
+				"project":  nil,
+			}},
+		},
+	}
+}
+
+func TestWLArchive_RoundTrip(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := WriteWLArchive(&buf, testArchive()); err != nil {
+		t.Fatalf("WriteWLArchive() error: %v", err)
+	}
+	got, err := ReadWLArchive(&buf)
+	if err != nil {
+		t.Fatalf("ReadWLArchive() error: %v", err)
+	}
+	if got.RowCount() != 2 {
+		t.Errorf("RowCount() = %d, want 2", got.RowCount())
+	}
+	if got.Tables["wanted"][0]["priority"] != json.Number("1") {
+		t.Errorf("priority = %#v, want json.Number(1)", got.Tables["wanted"][0]["priority"])
+	}
+}
+
+func TestWLArchive_ValidateRejectsBadFiles(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		mutate func(a *WLArchive)
+		want   string
+	}{
+		{"wrong format", func(a *WLArchive) { a.Format = "something-else" }, "not a wl-commons archive"},
+		{"future version", func(a *WLArchive) { a.Version = 99 }, "unsupported archive version"},
+		{"no tables", func(a *WLArchive) { a.Tables = nil }, "no tables"},
+		{"unknown table", func(a *WLArchive) { a.Tables["users; DROP"] = nil }, "unknown table"},
+		{"bad column", func(a *WLArchive) { a.Tables["wanted"][0]["id`; --"] = "x" }, "invalid column name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := testArchive()
+			tt.mutate(a)
+			err := a.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadWLArchive_RejectsNonJSON(t *testing.T) {
+	t.Parallel()
+	if _, err := ReadWLArchive(strings.NewReader("CREATE TABLE x;")); err == nil {
+		t.Error("ReadWLArchive() should reject non-JSON input")
+	}
+}
+
+func TestArchiveLoadStatements_Replace(t *testing.T) {
+	t.Parallel()
+	stmts, err := archiveLoadStatements(testArchive(), true)
+	if err != nil {
+		t.Fatalf("archiveLoadStatements() error: %v", err)
+	}
+	script := strings.Join(stmts, ";\n")
+	for _, want := range []string{
+		"DELETE FROM _meta",
+		"INSERT INTO _meta (`key`, `value`) VALUES ('schema_version', '1.0')",
+		"DELETE FROM wanted",
+		"INSERT INTO wanted (`id`, `priority`, `project`, `tags`, `title`) VALUES ('w-abc', 1, NULL, '[\"go\",\"auth\"]', 'It''s broken')",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("restore statements missing %q:\n%s", want, script)
+		}
+	}
+	// Tables absent from the archive must not be cleared.
+	if strings.Contains(script, "DELETE FROM completions") {
+		t.Error("restore script should not touch tables missing from the archive")
+	}
+	// Parents are restored before dependents.
+	if strings.Index(script, "DELETE FROM _meta") > strings.Index(script, "DELETE FROM wanted") {
+		t.Error("tables should be restored in schema order")
+	}
+}
+
+func TestArchiveLoadStatements_Upsert(t *testing.T) {
+	t.Parallel()
+	stmts, err := archiveLoadStatements(testArchive(), false)
+	if err != nil {
+		t.Fatalf("archiveLoadStatements() error: %v", err)
+	}
+	script := strings.Join(stmts, ";\n")
+	if strings.Contains(script, "DELETE FROM") {
+		t.Errorf("upsert statements should not clear tables:\n%s", script)
+	}
+	want := "REPLACE INTO wanted (`id`, `priority`, `project`, `tags`, `title`) VALUES ('w-abc', 1, NULL, '[\"go\",\"auth\"]', 'It''s broken')"
+	if !strings.Contains(script, want) {
+		t.Errorf("upsert statements missing %q:\n%s", want, script)
+	}
+}
+
+func TestReplayWLSnapshot_OneTransaction(t *testing.T) {
+	var buf bytes.Buffer
+	SetWLDryRun(&buf)
+	t.Cleanup(func() { SetWLDryRun(nil) })
+
+	if err := ReplayWLSnapshot(t.TempDir(), testArchive(), false, "wl undo-last: merge-duplicates snapshot from Bob's run"); err != nil {
+		t.Fatalf("ReplayWLSnapshot() error: %v", err)
+	}
+	script := buf.String()
+	for _, want := range []string{
+		"START TRANSACTION;",
+		"REPLACE INTO wanted (",
+		"COMMIT;",
+		"CALL DOLT_COMMIT('--allow-empty', '-m', 'wl undo-last: merge-duplicates snapshot from Bob''s run');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("replay script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestParseDoltJSONRows(t *testing.T) {
+	t.Parallel()
+	rows, err := parseDoltJSONRows([]byte(`{"rows": [{"id": "w-1", "priority": 2}]}`))
+	if err != nil {
+		t.Fatalf("parseDoltJSONRows() error: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["priority"] != json.Number("2") {
+		t.Errorf("rows = %#v", rows)
+	}
+	if rows, err := parseDoltJSONRows(nil); err != nil || rows != nil {
+		t.Errorf("empty output = %v, %v; want nil, nil", rows, err)
+	}
+}
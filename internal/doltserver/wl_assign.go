@@ -0,0 +1,41 @@
+package doltserver
+
+import "fmt"
+
+// AssignWanted records assignee as the worker on a claimed item, or clears
+// the assignee when it is empty. Only a rig holding the claim may assign,
+// and only while the claim is live (claimed, draft, or in review); the
+// assignee is cleared whenever the claim is released, reaped, or reopened.
+func AssignWanted(townRoot, wantedID, rigHandle, assignee string) error {
+	err := doltSQLScriptWithRetry(townRoot, AssignWantedScript(wantedID, rigHandle, assignee))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+	}
+	return fmt.Errorf("assign failed: %w", err)
+}
+
+// AssignWantedScript returns the SQL script AssignWanted executes.
+// ROW_COUNT() gates the history insert, so an item rigHandle does not hold
+// leaves nothing to commit.
+func AssignWantedScript(wantedID, rigHandle, assignee string) string {
+	var value, detail any
+	if assignee != "" {
+		value, detail = assignee, assignee
+	}
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE wanted SET assignee=?, updated_at=NOW()
+  WHERE id=? AND status IN (?, ?, ?) AND `+claimHolderCond(rigHandle)+`;
+SET @assigned = ROW_COUNT();
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), ?, 'assign', ?, ?, NOW() FROM dual WHERE @assigned > 0;
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		value, wantedID, StatusClaimed, StatusDraft, StatusInReview,
+		wantedID, rigHandle, detail,
+		wlCommitMessage("assign", wantedID, rigHandle, assignee))
+}
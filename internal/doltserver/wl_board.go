@@ -0,0 +1,44 @@
+// Package doltserver - wl_board.go reads the whole board in one round trip.
+package doltserver
+
+import (
+	"context"
+	"fmt"
+)
+
+// WLBoard is the wasteland's settings and every wanted row, read together.
+type WLBoard struct {
+	Settings map[string]string
+	Items    []*WantedItem
+}
+
+// QueryWLBoard reads the settings and every wanted row of the commons in
+// townRoot over one WithConnection scope, so a summary such as gt wl status
+// costs one dolt call rather than one per query. Rows come in ExportWanted
+// order.
+func QueryWLBoard(townRoot string) (*WLBoard, error) {
+	ctx, cancel := context.WithTimeout(sqlContext, effectiveSQLTimeout(DefaultSQLQueryTimeout))
+	defer cancel()
+
+	var sets [][]map[string]string
+	err := WithConnection(ctx, DefaultConfig(townRoot), func(c Conn) error {
+		var err error
+		sets, err = c.Batch(
+			fmt.Sprintf("SELECT %s, value FROM %s._meta", backtickKey(), WLCommonsDB),
+			fmt.Sprintf("SELECT * FROM %s.wanted ORDER BY updated_at ASC, id ASC", WLCommonsDB),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading the wanted board: %w", err)
+	}
+
+	board := &WLBoard{Settings: make(map[string]string)}
+	for _, row := range sets[0] {
+		board.Settings[row["key"]] = row["value"]
+	}
+	for _, row := range sets[1] {
+		board.Items = append(board.Items, parseWantedRow(row))
+	}
+	return board, nil
+}
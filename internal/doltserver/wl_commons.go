@@ -9,15 +9,54 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// WLCommonsDB is the database name for the wl-commons shared wanted board.
-const WLCommonsDB = "wl_commons"
+// DefaultWLCommonsDB is the database name for the wl-commons shared wanted
+// board when the town has not chosen another.
+const DefaultWLCommonsDB = "wl_commons"
+
+// WLCommonsDB is the active wl-commons database. It starts as
+// DefaultWLCommonsDB; use SetWLCommonsDB to change it.
+var WLCommonsDB = DefaultWLCommonsDB
+
+// wlCommonsDBNameRe matches names safe to write into SQL unquoted.
+var wlCommonsDBNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SetWLCommonsDB makes name the active wl-commons database for every
+// query and script that follows, so a town can keep more than one board.
+func SetWLCommonsDB(name string) error {
+	if !wlCommonsDBNameRe.MatchString(name) {
+		return fmt.Errorf("invalid wl-commons database name %q: use letters, digits, and underscores", name)
+	}
+	WLCommonsDB = name
+	return nil
+}
+
+// wlDryRun, when set, receives write scripts in place of the server (see
+// SetWLDryRun).
+var wlDryRun io.Writer
+
+// SetWLDryRun makes every write script that follows print to w instead of
+// running, and report success. Queries still run, so precondition checks
+// read the real board. A nil w restores normal execution.
+func SetWLDryRun(w io.Writer) {
+	wlDryRun = w
+}
+
+// WLDryRun reports whether write scripts are printed instead of run.
+func WLDryRun() bool {
+	return wlDryRun != nil
+}
 
 // WLCommonsStore abstracts wl-commons database operations.
 type WLCommonsStore interface {
@@ -25,8 +64,41 @@ type WLCommonsStore interface {
 	DatabaseExists(dbName string) bool
 	InsertWanted(item *WantedItem) error
 	ClaimWanted(wantedID, rigHandle string) error
+	ClaimWantedFor(wantedID, rigHandle, actor string) error
 	SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error
+	SubmitDraftCompletion(completionID, wantedID, rigHandle, evidence string) error
+	FinalizeCompletion(wantedID, rigHandle, evidence string) error
+	ResubmitCompletion(completionID, supersedes, wantedID, rigHandle, evidence string, draft bool) error
+	AmendCompletion(wantedID, rigHandle, evidence string) error
+	RenewClaim(wantedID, rigHandle string, expiresAt time.Time) error
 	QueryWanted(wantedID string) (*WantedItem, error)
+	QueryBlockers(wantedID string) ([]*WantedItem, error)
+	QueryDependents(wantedID string) ([]*WantedItem, error)
+	QuerySettings() (map[string]string, error)
+	ListWanted(filter WantedFilter) ([]*WantedItem, error)
+	AddComment(wantedID, author, body string) error
+	QueryWantedDetail(wantedID string) (*WantedDetail, error)
+	QueryWantedAsOf(wantedID, commit string) (*WantedItem, error)
+	ExportWanted(filter WantedExportFilter) ([]*WantedItem, error)
+	AddWatcher(wantedID, rigHandle, address string) error
+	RemoveWatcher(wantedID, rigHandle string) error
+	QueryWatchers(wantedID string) ([]WantedWatcher, error)
+	SetLabels(wantedID, rigHandle string, labels []WantedLabel) error
+	ClaimWantedForGroup(wantedID, rigHandle, group string) error
+	AddGroupMembers(group, addedBy string, rigHandles []string) error
+	QueryGroupMembers(group string) ([]string, error)
+	MergeWanted(keepID string, duplicateIDs []string, rigHandle string) error
+	ApproveCompletions(wantedIDs []string, reviewer string) error
+	UnclaimWanted(wantedID, rigHandle string, force bool) error
+	RejectCompletion(wantedID, reviewer, reason string) error
+	QueryStaleClaims(claimedBefore time.Time) ([]*WantedItem, error)
+	ReapClaim(wantedID string, claimedBefore time.Time, actor string) error
+	ReopenWanted(wantedID, actor string, force bool) error
+	AssignWanted(wantedID, rigHandle, assignee string) error
+	QueryWantedHistory(wantedID string) ([]WantedEvent, error)
+	QueryPendingReviews(postedBy string) ([]PendingReview, error)
+	AcquireClaimLock(wantedID, rigHandle string, ttl time.Duration) error
+	ReleaseClaimLock(wantedID, rigHandle string) error
 }
 
 // WLCommons implements WLCommonsStore using the real Dolt server.
@@ -35,35 +107,309 @@ type WLCommons struct{ townRoot string }
 // NewWLCommons creates a WLCommonsStore backed by the real Dolt server.
 func NewWLCommons(townRoot string) *WLCommons { return &WLCommons{townRoot: townRoot} }
 
-func (w *WLCommons) EnsureDB() error           { return EnsureWLCommons(w.townRoot) }
-func (w *WLCommons) DatabaseExists(db string) bool { return DatabaseExists(w.townRoot, db) }
+func (w *WLCommons) EnsureDB() error                     { return EnsureWLCommons(w.townRoot) }
+func (w *WLCommons) DatabaseExists(db string) bool       { return DatabaseExists(w.townRoot, db) }
 func (w *WLCommons) InsertWanted(item *WantedItem) error { return InsertWanted(w.townRoot, item) }
 func (w *WLCommons) ClaimWanted(wantedID, rigHandle string) error {
 	return ClaimWanted(w.townRoot, wantedID, rigHandle)
 }
+func (w *WLCommons) ClaimWantedFor(wantedID, rigHandle, actor string) error {
+	return ClaimWantedFor(w.townRoot, wantedID, rigHandle, actor)
+}
 func (w *WLCommons) SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error {
 	return SubmitCompletion(w.townRoot, completionID, wantedID, rigHandle, evidence)
 }
+func (w *WLCommons) SubmitDraftCompletion(completionID, wantedID, rigHandle, evidence string) error {
+	return SubmitDraftCompletion(w.townRoot, completionID, wantedID, rigHandle, evidence)
+}
+func (w *WLCommons) FinalizeCompletion(wantedID, rigHandle, evidence string) error {
+	return FinalizeCompletion(w.townRoot, wantedID, rigHandle, evidence)
+}
+func (w *WLCommons) ResubmitCompletion(completionID, supersedes, wantedID, rigHandle, evidence string, draft bool) error {
+	return ResubmitCompletion(w.townRoot, completionID, supersedes, wantedID, rigHandle, evidence, draft)
+}
+func (w *WLCommons) AmendCompletion(wantedID, rigHandle, evidence string) error {
+	return AmendCompletion(w.townRoot, wantedID, rigHandle, evidence)
+}
+func (w *WLCommons) RenewClaim(wantedID, rigHandle string, expiresAt time.Time) error {
+	return RenewClaim(w.townRoot, wantedID, rigHandle, expiresAt)
+}
 func (w *WLCommons) QueryWanted(wantedID string) (*WantedItem, error) {
 	return QueryWanted(w.townRoot, wantedID)
 }
+func (w *WLCommons) QueryBlockers(wantedID string) ([]*WantedItem, error) {
+	return QueryBlockers(w.townRoot, wantedID)
+}
+func (w *WLCommons) QueryDependents(wantedID string) ([]*WantedItem, error) {
+	return QueryDependents(w.townRoot, wantedID)
+}
+func (w *WLCommons) QuerySettings() (map[string]string, error) { return QuerySettings(w.townRoot) }
+func (w *WLCommons) ListWanted(filter WantedFilter) ([]*WantedItem, error) {
+	return ListWanted(w.townRoot, filter)
+}
+func (w *WLCommons) AddComment(wantedID, author, body string) error {
+	return AddComment(w.townRoot, wantedID, author, body)
+}
+func (w *WLCommons) QueryWantedDetail(wantedID string) (*WantedDetail, error) {
+	return QueryWantedDetail(w.townRoot, wantedID)
+}
+func (w *WLCommons) QueryWantedAsOf(wantedID, commit string) (*WantedItem, error) {
+	return QueryWantedAsOf(w.townRoot, wantedID, commit)
+}
+func (w *WLCommons) ExportWanted(filter WantedExportFilter) ([]*WantedItem, error) {
+	return ExportWanted(w.townRoot, filter)
+}
+func (w *WLCommons) AddWatcher(wantedID, rigHandle, address string) error {
+	return AddWatcher(w.townRoot, wantedID, rigHandle, address)
+}
+func (w *WLCommons) RemoveWatcher(wantedID, rigHandle string) error {
+	return RemoveWatcher(w.townRoot, wantedID, rigHandle)
+}
+func (w *WLCommons) QueryWatchers(wantedID string) ([]WantedWatcher, error) {
+	return QueryWatchers(w.townRoot, wantedID)
+}
+func (w *WLCommons) SetLabels(wantedID, rigHandle string, labels []WantedLabel) error {
+	return SetWantedLabels(w.townRoot, wantedID, rigHandle, labels)
+}
+func (w *WLCommons) ClaimWantedForGroup(wantedID, rigHandle, group string) error {
+	return ClaimWantedForGroup(w.townRoot, wantedID, rigHandle, group)
+}
+func (w *WLCommons) AddGroupMembers(group, addedBy string, rigHandles []string) error {
+	return AddGroupMembers(w.townRoot, group, addedBy, rigHandles)
+}
+func (w *WLCommons) QueryGroupMembers(group string) ([]string, error) {
+	return QueryGroupMembers(w.townRoot, group)
+}
+func (w *WLCommons) MergeWanted(keepID string, duplicateIDs []string, rigHandle string) error {
+	return MergeWanted(w.townRoot, keepID, duplicateIDs, rigHandle)
+}
+func (w *WLCommons) ApproveCompletions(wantedIDs []string, reviewer string) error {
+	return ApproveCompletions(w.townRoot, wantedIDs, reviewer)
+}
+func (w *WLCommons) UnclaimWanted(wantedID, rigHandle string, force bool) error {
+	return UnclaimWanted(w.townRoot, wantedID, rigHandle, force)
+}
+func (w *WLCommons) RejectCompletion(wantedID, reviewer, reason string) error {
+	return RejectCompletion(w.townRoot, wantedID, reviewer, reason)
+}
+func (w *WLCommons) QueryStaleClaims(claimedBefore time.Time) ([]*WantedItem, error) {
+	return QueryStaleClaims(w.townRoot, claimedBefore)
+}
+func (w *WLCommons) ReapClaim(wantedID string, claimedBefore time.Time, actor string) error {
+	return ReapClaim(w.townRoot, wantedID, claimedBefore, actor)
+}
+func (w *WLCommons) ReopenWanted(wantedID, actor string, force bool) error {
+	return ReopenWanted(w.townRoot, wantedID, actor, force)
+}
+func (w *WLCommons) AssignWanted(wantedID, rigHandle, assignee string) error {
+	return AssignWanted(w.townRoot, wantedID, rigHandle, assignee)
+}
+func (w *WLCommons) QueryWantedHistory(wantedID string) ([]WantedEvent, error) {
+	return QueryWantedHistory(w.townRoot, wantedID)
+}
+func (w *WLCommons) QueryPendingReviews(postedBy string) ([]PendingReview, error) {
+	return QueryPendingReviews(w.townRoot, postedBy)
+}
+func (w *WLCommons) AcquireClaimLock(wantedID, rigHandle string, ttl time.Duration) error {
+	return AcquireClaimLock(w.townRoot, wantedID, rigHandle, ttl)
+}
+func (w *WLCommons) ReleaseClaimLock(wantedID, rigHandle string) error {
+	return ReleaseClaimLock(w.townRoot, wantedID, rigHandle)
+}
 
 // WantedItem represents a row in the wanted table.
 type WantedItem struct {
-	ID              string
-	Title           string
-	Description     string
-	Project         string
-	Type            string
-	Priority        int
-	Tags            []string
-	PostedBy        string
-	ClaimedBy       string
+	ID          string
+	Title       string
+	Description string
+	Project     string
+	Type        string
+	Priority    int
+	Tags        []string
+	PostedBy    string
+	ClaimedBy   string
+	ClaimedVia  string
+	// ClaimedGroup is the group the claim is held for, if any. Every member
+	// may then complete the item; ClaimedBy is the member who claimed it.
+	ClaimedGroup    string
 	Status          string
 	EffortLevel     string
 	SandboxRequired bool
+
+	// Assignee is the worker the claiming town has put on the item, such
+	// as a polecat or crew member. Empty means no one in particular.
+	Assignee string
+
+	// ExpiresAt is when the current claim lease lapses. Zero means the
+	// claim has no lease.
+	ExpiresAt time.Time
+
+	// ClaimedAt is when the current claim was taken. Zero means the item
+	// is unclaimed or was claimed before claim times were recorded.
+	ClaimedAt time.Time
+
+	// ReopenedBy and ReopenedAt record the last gt wl reopen of the item.
+	// Only full-row reads fill them in.
+	ReopenedBy string
+	ReopenedAt time.Time
+
+	// TimeoutAction is what happens when a claim on this item lapses: one
+	// of the TimeoutAction* constants. Empty means TimeoutActionReopen.
+	TimeoutAction string
+
+	// CompletionCount is how many completions have been submitted for the
+	// item, including superseded ones. Finalizing a draft does not count.
+	CompletionCount int
+
+	// MergedInto is the surviving item when this one was withdrawn as a
+	// duplicate by gt wl merge-duplicates.
+	MergedInto string
+
+	// EvidenceURL is the evidence of the latest completion submitted for
+	// the item, kept on the row so the board shows it without a join.
+	EvidenceURL string
+
+	// DependsOn lists wanted IDs that must be completed before this item.
+	// Written to the wanted_deps table on insert.
+	DependsOn []string
+
+	// CreatedAt and UpdatedAt are when the row was posted and last
+	// changed. Only full-row reads (QueryWantedDetail, ExportWanted,
+	// QueryWantedAsOf) fill them in.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Claim timeout actions, declared by the poster and applied when a claim
+// lease lapses.
+const (
+	// TimeoutActionReopen returns the item to the board (the default).
+	TimeoutActionReopen = "reopen"
+	// TimeoutActionNotify reopens the item and mails the poster.
+	TimeoutActionNotify = "notify"
+	// TimeoutActionEscalate reopens the item and raises its priority.
+	TimeoutActionEscalate = "escalate"
+)
+
+// ValidTimeoutAction reports whether action is a known timeout action.
+func ValidTimeoutAction(action string) bool {
+	switch action {
+	case TimeoutActionReopen, TimeoutActionNotify, TimeoutActionEscalate:
+		return true
+	}
+	return false
+}
+
+// WantedComment is a comment event from wanted_history.
+type WantedComment struct {
+	Author    string
+	Body      string
+	CreatedAt string
 }
 
+// WantedCompletion is a row in the completions table.
+type WantedCompletion struct {
+	ID          string
+	CompletedBy string
+	Evidence    string
+	CompletedAt string
+	ValidatedBy string
+	ValidatedAt string
+
+	// SupersededBy is the ID of the completion that replaced this one on
+	// resubmission, or empty if this completion is current.
+	SupersededBy string
+}
+
+// WantedDetail is a wanted item joined with its related rows.
+type WantedDetail struct {
+	Item         *WantedItem
+	Dependencies []*WantedItem
+	Comments     []WantedComment
+	Completions  []WantedCompletion
+
+	// WatcherCount is the number of rigs subscribed to the item.
+	WatcherCount int
+
+	// Labels are the item's key=value labels, sorted by key.
+	Labels []WantedLabel
+}
+
+// WantedFilter selects rows for ListWanted. String fields match exactly and
+// zero values match everything; priority bounds are inclusive and -1 means
+// unbounded.
+type WantedFilter struct {
+	Status      string
+	Title       string
+	MinPriority int
+	MaxPriority int
+	Limit       int
+
+	// Tags keeps items carrying any of these tags, or all of them when
+	// TagsMatchAll is set. Empty means no tag filter.
+	Tags         []string
+	TagsMatchAll bool
+
+	// Statuses keeps items in any of these statuses. Empty means no
+	// status-set filter; it combines with Status like any other field.
+	Statuses []string
+
+	// HeldBy keeps items whose claim this rig holds, directly or through
+	// a group it belongs to. Empty means no claim filter.
+	HeldBy string
+
+	// NewestFirst orders by creation time, most recent first, instead of
+	// by priority then age.
+	NewestFirst bool
+
+	// Search keeps items whose title or description contains this text,
+	// ignoring case. % and _ match literally. Empty means no search.
+	Search string
+}
+
+// likeEscapeChar is the escape character of patterns built with
+// escapeSQLLike. A LIKE using one must say ESCAPE '!', so the pattern does
+// not depend on the server treating backslash as LIKE's escape.
+const likeEscapeChar = "!"
+
+var likeEscaper = strings.NewReplacer(likeEscapeChar, likeEscapeChar+likeEscapeChar, "%", likeEscapeChar+"%", "_", likeEscapeChar+"_")
+
+// escapeSQLLike escapes LIKE's wildcards, and likeEscapeChar itself, so s
+// matches literally inside a LIKE pattern: a search for "50%" then finds
+// "50%" and not everything starting with 50. The result still needs
+// EscapeSQL to become a string literal, and the LIKE needs
+// ESCAPE '!'. Exact comparisons use EscapeSQL alone.
+func escapeSQLLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// TagCondition returns a WHERE condition matching rows whose tags array
+// contains any of tags, or every one of them when matchAll is set. It
+// returns "" when tags is empty.
+func TagCondition(tags []string, matchAll bool) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		lit, _ := json.Marshal(tag)
+		parts[i] = fmt.Sprintf("JSON_CONTAINS(tags, '%s')", EscapeSQL(string(lit)))
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	sep := " OR "
+	if matchAll {
+		sep = " AND "
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+// ErrWantedNotOpen is wrapped by a claim whose guarded write changed no row:
+// the item was not open (another rig claimed it first) or does not exist.
+// Callers use it to tell a lost race from a failed write.
+var ErrWantedNotOpen = errors.New("not open or does not exist")
+
 // isNothingToCommit returns true if the error indicates DOLT_COMMIT found no
 // changes to commit. This happens when a conditional UPDATE matched 0 rows,
 // leaving the working set unchanged.
@@ -71,16 +417,41 @@ func isNothingToCommit(err error) bool {
 	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nothing to commit")
 }
 
-// EscapeSQL escapes backslashes and single quotes for SQL string literals.
+// isDuplicateKey returns true if the error indicates an INSERT collided with
+// an existing primary key.
+func isDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "duplicate primary key") || strings.Contains(msg, "duplicate entry")
+}
+
+// completionIDTakenError reports a completion ID that another completion
+// already uses: a generated-ID collision, not a second completion of the
+// same claim, which the status guards reject.
+func completionIDTakenError(completionID string) error {
+	return fmt.Errorf("completion ID %q is already taken by another completion; run the command again to generate a new one", completionID)
+}
+
+// isTableNotFound returns true if the error indicates a table is missing.
+// Wastelands created by older schema versions may lack optional tables.
+func isTableNotFound(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "table not found")
+}
+
+// EscapeSQL escapes s for use inside a single-quoted SQL string literal.
 // Dolt (MySQL-compatible) treats \ as an escape character, so a trailing
-// backslash in user input would escape the closing quote and break the query.
+// backslash in user input would escape the closing quote and break the query;
+// backslashes, quotes, and control characters are all escaped (see
+// sqlEscaper). Prefer BindSQL, which also adds the quotes.
 func EscapeSQL(s string) string {
-	s = strings.ReplaceAll(s, `\`, `\\`)
-	return strings.ReplaceAll(s, "'", "''")
+	return sqlEscaper.Replace(s)
 }
 
-// GenerateWantedID generates a unique wanted item ID in the format w-<10-char-hash>.
-func GenerateWantedID(title string) string {
+// GenerateWantedID generates a unique wanted item ID in the format
+// <prefix>-<10-char-hash>, e.g. w-1a2b3c4d5e for prefix "w".
+func GenerateWantedID(prefix, title string) string {
 	randomBytes := make([]byte, 8)
 	_, _ = rand.Read(randomBytes)
 
@@ -88,7 +459,7 @@ func GenerateWantedID(title string) string {
 	hash := sha256.Sum256([]byte(input))
 	hashStr := hex.EncodeToString(hash[:])[:10]
 
-	return fmt.Sprintf("w-%s", hashStr)
+	return fmt.Sprintf("%s-%s", prefix, hashStr)
 }
 
 // EnsureWLCommons ensures the wl-commons database exists and has the correct schema.
@@ -117,14 +488,20 @@ func EnsureWLCommons(townRoot string) error {
 }
 
 func initWLCommonsSchema(townRoot string) error {
-	schema := fmt.Sprintf(`USE %s;
+	return doltSQLScriptWithRetry(townRoot, wlCommonsSchemaSQL())
+}
+
+// wlCommonsSchemaSQL returns the script that creates the wl-commons schema.
+// It is also the source of truth for ValidateWLCommonsSchema.
+func wlCommonsSchemaSQL() string {
+	return fmt.Sprintf(`USE %s;
 
 CREATE TABLE IF NOT EXISTS _meta (
     %s VARCHAR(64) PRIMARY KEY,
     value TEXT
 );
 
-INSERT IGNORE INTO _meta (%s, value) VALUES ('schema_version', '1.0');
+INSERT IGNORE INTO _meta (%s, value) VALUES ('schema_version', '%s');
 INSERT IGNORE INTO _meta (%s, value) VALUES ('wasteland_name', 'Gas Town Wasteland');
 
 CREATE TABLE IF NOT EXISTS rigs (
@@ -151,8 +528,18 @@ CREATE TABLE IF NOT EXISTS wanted (
     tags JSON,
     posted_by VARCHAR(255),
     claimed_by VARCHAR(255),
+    claimed_via VARCHAR(255),
+    claimed_group VARCHAR(64),
+    assignee VARCHAR(255),
     status VARCHAR(32) DEFAULT 'open',
     effort_level VARCHAR(16) DEFAULT 'medium',
+    timeout_action VARCHAR(16) DEFAULT 'reopen',
+    expires_at TIMESTAMP,
+    claimed_at TIMESTAMP,
+    reopened_at TIMESTAMP,
+    reopened_by VARCHAR(255),
+    completion_count INT DEFAULT 0,
+    merged_into VARCHAR(64),
     evidence_url TEXT,
     sandbox_required TINYINT(1) DEFAULT 0,
     sandbox_scope JSON,
@@ -161,6 +548,46 @@ CREATE TABLE IF NOT EXISTS wanted (
     updated_at TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS wanted_deps (
+    wanted_id VARCHAR(64) NOT NULL,
+    depends_on VARCHAR(64) NOT NULL,
+    PRIMARY KEY (wanted_id, depends_on)
+);
+
+CREATE TABLE IF NOT EXISTS wanted_history (
+    id VARCHAR(36) PRIMARY KEY,
+    wanted_id VARCHAR(64) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor VARCHAR(255),
+    detail TEXT,
+    created_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS wl_watchers (
+    wanted_id VARCHAR(64) NOT NULL,
+    rig_handle VARCHAR(255) NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP,
+    PRIMARY KEY (wanted_id, rig_handle)
+);
+
+CREATE TABLE IF NOT EXISTS wanted_labels (
+    wanted_id VARCHAR(64) NOT NULL,
+    label_key VARCHAR(64) NOT NULL,
+    label_value TEXT,
+    set_by VARCHAR(255),
+    created_at TIMESTAMP,
+    PRIMARY KEY (wanted_id, label_key)
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    group_name VARCHAR(64) NOT NULL,
+    rig_handle VARCHAR(255) NOT NULL,
+    added_by VARCHAR(255),
+    created_at TIMESTAMP,
+    PRIMARY KEY (group_name, rig_handle)
+);
+
 CREATE TABLE IF NOT EXISTS completions (
     id VARCHAR(64) PRIMARY KEY,
     wanted_id VARCHAR(64),
@@ -172,7 +599,8 @@ CREATE TABLE IF NOT EXISTS completions (
     block_hash VARCHAR(64),
     hop_uri VARCHAR(512),
     completed_at TIMESTAMP,
-    validated_at TIMESTAMP
+    validated_at TIMESTAMP,
+    superseded_by VARCHAR(64)
 );
 
 CREATE TABLE IF NOT EXISTS stamps (
@@ -211,11 +639,9 @@ CREATE TABLE IF NOT EXISTS chain_meta (
 );
 
 CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('--allow-empty', '-m', 'Initialize wl-commons schema v1.0');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'Initialize wl-commons schema v%s');
 `, WLCommonsDB,
-		backtickKey(), backtickKey(), backtickKey())
-
-	return doltSQLScriptWithRetry(townRoot, schema)
+		backtickKey(), backtickKey(), WLCommonsSchemaVersion, backtickKey(), WLCommonsSchemaVersion)
 }
 
 func backtickKey() string {
@@ -270,23 +696,78 @@ func InsertWanted(townRoot string, item *WantedItem) error {
 		status = fmt.Sprintf("'%s'", EscapeSQL(item.Status))
 	}
 
-	script := fmt.Sprintf(`USE %s;
+	// timeout_action is only written when set, so posting still works
+	// against wastelands whose schema predates the column.
+	extraCols, extraVals := "", ""
+	if item.TimeoutAction != "" {
+		extraCols = ", timeout_action"
+		extraVals = fmt.Sprintf(", '%s'", EscapeSQL(item.TimeoutAction))
+	}
+
+	depsInsert := ""
+	if len(item.DependsOn) > 0 {
+		values := make([]string, len(item.DependsOn))
+		for i, dep := range item.DependsOn {
+			values[i] = fmt.Sprintf("('%s', '%s')", EscapeSQL(item.ID), EscapeSQL(dep))
+		}
+		depsInsert = fmt.Sprintf("INSERT INTO wanted_deps (wanted_id, depends_on) VALUES %s;\n", strings.Join(values, ", "))
+	}
 
-INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
-VALUES ('%s', '%s', %s, %s, %s, %d, %s, %s, %s, %s, '%s', '%s');
+	script := fmt.Sprintf(`USE %s;
 
+INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at%s)
+VALUES ('%s', '%s', %s, %s, %s, %d, %s, %s, %s, %s, '%s', '%s'%s);
+%s
 CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl post: %s');
+CALL DOLT_COMMIT('-m', '%s');
 `,
 		WLCommonsDB,
+		extraCols,
 		EscapeSQL(item.ID), EscapeSQL(item.Title), descField, projectField, typeField,
 		item.Priority, tagsJSON, postedByField, status, effortField,
-		now, now,
-		EscapeSQL(item.Title))
+		now, now, extraVals,
+		depsInsert,
+		EscapeSQL(wlCommitMessage("post", item.ID, item.PostedBy, item.Title)))
 
 	return doltSQLScriptWithRetry(townRoot, script)
 }
 
+// wlCommitMessage formats the Dolt commit message for a wanted-board write,
+// e.g. "wl claim: w-abc123 by town-x (via coordinator)". Every wild-west
+// write commits with one, so the Dolt log reads as a history of board
+// actions. Empty parts are omitted. The result is not SQL-escaped.
+func wlCommitMessage(action, wantedID, rig string, details ...string) string {
+	var b strings.Builder
+	b.WriteString("wl ")
+	b.WriteString(action)
+	b.WriteString(": ")
+	b.WriteString(wantedID)
+	if rig != "" {
+		b.WriteString(" by ")
+		b.WriteString(rig)
+	}
+	var kept []string
+	for _, d := range details {
+		if d != "" {
+			kept = append(kept, d)
+		}
+	}
+	if len(kept) > 0 {
+		b.WriteString(" (")
+		b.WriteString(strings.Join(kept, "; "))
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// doneCommitAction names a completion write in commit messages.
+func doneCommitAction(status string) string {
+	if status == "draft" {
+		return "done --draft"
+	}
+	return "done"
+}
+
 // ClaimWanted updates a wanted item's status to claimed.
 // Returns an error if the item does not exist or is not open.
 //
@@ -296,48 +777,165 @@ CALL DOLT_COMMIT('-m', 'wl post: %s');
 // map to a precondition error. This avoids splitting into separate sessions
 // and eliminates the need for DOLT_RESET on failure.
 func ClaimWanted(townRoot, wantedID, rigHandle string) error {
+	err := doltSQLScriptWithRetry(townRoot, ClaimWantedScript(wantedID, rigHandle))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is %w", wantedID, ErrWantedNotOpen)
+	}
+	return fmt.Errorf("claim failed: %w", err)
+}
+
+// ClaimWantedFor claims a wanted item on behalf of rigHandle. The rig that
+// actually performed the claim (actor) is recorded in claimed_via and in a
+// wanted_history event, so delegated claims keep a provenance trail.
+//
+// ROW_COUNT() gates the history insert so a claim that matched no rows leaves
+// the working set unchanged and DOLT_COMMIT reports "nothing to commit". The
+// writes share one SQL transaction, so a failure between them leaves
+// neither (see doltSQLScript).
+func ClaimWantedFor(townRoot, wantedID, rigHandle, actor string) error {
+	err := doltSQLScriptWithRetry(townRoot, ClaimWantedForScript(wantedID, rigHandle, actor))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is %w", wantedID, ErrWantedNotOpen)
+	}
+	return fmt.Errorf("claim failed: %w", err)
+}
+
+// ClaimWantedScript returns the SQL script ClaimWanted executes. It is
+// exported so previews (gt wl claim --dry-run) show exactly what would run.
+func ClaimWantedScript(wantedID, rigHandle string) string {
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`UPDATE wanted SET claimed_by=?, status='claimed', claimed_at=NOW(), updated_at=NOW()
+  WHERE id=? AND status='open';
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`, rigHandle, wantedID, wlCommitMessage("claim", wantedID, rigHandle))
+}
+
+// ClaimWantedForScript returns the SQL script ClaimWantedFor executes.
+func ClaimWantedForScript(wantedID, rigHandle, actor string) string {
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE wanted SET claimed_by=?, claimed_via=?, status='claimed', claimed_at=NOW(), updated_at=NOW()
+  WHERE id=? AND status='open';
+SET @claimed = ROW_COUNT();
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), ?, 'claim', ?, ?, NOW() FROM dual WHERE @claimed > 0;
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		rigHandle, actor, wantedID,
+		wantedID, actor, "on behalf of "+rigHandle,
+		wlCommitMessage("claim", wantedID, rigHandle, "via "+actor))
+}
+
+// AddComment records a free-form comment on a wanted item as a 'comment'
+// event in wanted_history. Claim notes use the same path.
+//
+// The insert selects from wanted, so an unknown ID inserts nothing and
+// DOLT_COMMIT reports "nothing to commit".
+func AddComment(townRoot, wantedID, author, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment cannot be empty")
+	}
 	script := fmt.Sprintf(`USE %s;
-UPDATE wanted SET claimed_by='%s', status='claimed', updated_at=NOW()
-  WHERE id='%s' AND status='open';
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), id, 'comment', '%s', '%s', NOW() FROM wanted WHERE id='%s';
 CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl claim: %s');
-`, WLCommonsDB, EscapeSQL(rigHandle), EscapeSQL(wantedID), EscapeSQL(wantedID))
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB,
+		EscapeSQL(author), EscapeSQL(body), EscapeSQL(wantedID),
+		EscapeSQL(wlCommitMessage("comment", wantedID, author)))
 
 	err := doltSQLScriptWithRetry(townRoot, script)
 	if err == nil {
 		return nil
 	}
 	if isNothingToCommit(err) {
-		return fmt.Errorf("wanted item %q is not open or does not exist", wantedID)
+		return fmt.Errorf("wanted item %q does not exist", wantedID)
 	}
-	return fmt.Errorf("claim failed: %w", err)
+	return fmt.Errorf("adding comment: %w", err)
 }
 
 // SubmitCompletion inserts a completion record and updates the wanted status.
-// The item must have status='claimed' AND claimed_by=rigHandle to prevent
-// completing an item claimed by another rig.
+// The item must have status='claimed' and be held by rigHandle (claimed_by,
+// or claimed for a group rigHandle belongs to) to prevent completing an item
+// claimed by another rig.
 //
-// Uses a single-script approach like ClaimWanted. The INSERT uses INSERT IGNORE
-// with a SELECT conditional on status='in_review' AND claimed_by AND NOT EXISTS
-// (prior completion). INSERT IGNORE makes the script idempotent on retry since
-// completions.id is a PRIMARY KEY. NOT EXISTS prevents multiple completions per
-// wanted item, ensuring the lifecycle is strictly post→claim→done.
+// Uses a single-script approach like ClaimWanted. The INSERT selects
+// conditionally on status='in_review' AND claimed_by AND NOT EXISTS (prior
+// completion), which makes the script idempotent on retry and prevents
+// multiple completions per wanted item, ensuring the lifecycle is strictly
+// post→claim→done. A completionID already used by another completion fails
+// the INSERT on the primary key instead of being skipped, and is reported
+// as taken.
+//
+// The UPDATE and INSERT share one SQL transaction: if the INSERT fails, the
+// connection closes with the transaction open and the server rolls back the
+// status change too (see doltSQLScript), so an item never moves to review
+// without its completion row.
 func SubmitCompletion(townRoot, completionID, wantedID, rigHandle, evidence string) error {
+	return submitCompletionWithStatus(townRoot, completionID, wantedID, rigHandle, evidence, "in_review")
+}
+
+// SubmitDraftCompletion records a work-in-progress completion without
+// requesting review. The wanted item moves to status='draft' (still owned by
+// rigHandle) and can later be promoted with FinalizeCompletion.
+func SubmitDraftCompletion(townRoot, completionID, wantedID, rigHandle, evidence string) error {
+	return submitCompletionWithStatus(townRoot, completionID, wantedID, rigHandle, evidence, "draft")
+}
+
+func submitCompletionWithStatus(townRoot, completionID, wantedID, rigHandle, evidence, status string) error {
+	err := doltSQLScriptWithRetry(townRoot, SubmitCompletionScript(completionID, wantedID, rigHandle, evidence, status))
+	if err == nil {
+		return nil
+	}
+	if isDuplicateKey(err) {
+		return completionIDTakenError(completionID)
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is not claimed by %q, already has a current completion, or does not exist", wantedID, rigHandle)
+	}
+	return fmt.Errorf("completion failed: %w", err)
+}
+
+// SubmitCompletionScript returns the SQL script SubmitCompletion (status
+// in_review) or SubmitDraftCompletion (status draft) executes.
+func SubmitCompletionScript(completionID, wantedID, rigHandle, evidence, status string) string {
+	holder := claimHolderCond(rigHandle)
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE wanted SET status=?, evidence_url=?, completion_count=COALESCE(completion_count, 0)+1, updated_at=NOW()
+  WHERE id=? AND status='claimed' AND `+holder+`
+  AND NOT EXISTS (SELECT 1 FROM completions WHERE wanted_id=? AND superseded_by IS NULL);
+INSERT INTO completions (id, wanted_id, completed_by, evidence, completed_at)
+  SELECT ?, ?, ?, ?, NOW()
+  FROM wanted WHERE id=? AND status=? AND `+holder+`
+  AND NOT EXISTS (SELECT 1 FROM completions WHERE wanted_id=? AND superseded_by IS NULL);
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		status, evidence, wantedID, wantedID,
+		completionID, wantedID, rigHandle, evidence,
+		wantedID, status, wantedID,
+		wlCommitMessage(doneCommitAction(status), wantedID, rigHandle, completionID))
+}
+
+// RenewClaim sets the claim lease on wantedID to expiresAt. The item must be
+// claimed by rigHandle.
+func RenewClaim(townRoot, wantedID, rigHandle string, expiresAt time.Time) error {
 	script := fmt.Sprintf(`USE %s;
-UPDATE wanted SET status='in_review', evidence_url='%s', updated_at=NOW()
+UPDATE wanted SET expires_at='%s', updated_at=NOW()
   WHERE id='%s' AND status='claimed' AND claimed_by='%s';
-INSERT IGNORE INTO completions (id, wanted_id, completed_by, evidence, completed_at)
-  SELECT '%s', '%s', '%s', '%s', NOW()
-  FROM wanted WHERE id='%s' AND status='in_review' AND claimed_by='%s'
-  AND NOT EXISTS (SELECT 1 FROM completions WHERE wanted_id='%s');
 CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl done: %s');
-`,
-		WLCommonsDB,
-		EscapeSQL(evidence), EscapeSQL(wantedID), EscapeSQL(rigHandle),
-		EscapeSQL(completionID), EscapeSQL(wantedID), EscapeSQL(rigHandle), EscapeSQL(evidence),
-		EscapeSQL(wantedID), EscapeSQL(rigHandle), EscapeSQL(wantedID),
-		EscapeSQL(wantedID))
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB,
+		expiresAt.UTC().Format("2006-01-02 15:04:05"), EscapeSQL(wantedID), EscapeSQL(rigHandle),
+		EscapeSQL(wlCommitMessage("renew", wantedID, rigHandle, "until "+expiresAt.UTC().Format(time.RFC3339))))
 
 	err := doltSQLScriptWithRetry(townRoot, script)
 	if err == nil {
@@ -346,13 +944,138 @@ CALL DOLT_COMMIT('-m', 'wl done: %s');
 	if isNothingToCommit(err) {
 		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
 	}
-	return fmt.Errorf("completion failed: %w", err)
+	return fmt.Errorf("renewing claim: %w", err)
+}
+
+// claimHolderCond is a SQL condition on wanted that holds when rigHandle may
+// act on the item's claim: it claimed the item itself, or the item is
+// claimed for a group rigHandle belongs to.
+func claimHolderCond(rigHandle string) string {
+	return BindSQL("(claimed_by=? OR claimed_group IN (SELECT group_name FROM group_members WHERE rig_handle=?))", rigHandle, rigHandle)
+}
+
+// ResubmitCompletion records a new completion that supersedes an earlier one,
+// e.g. after the first was rejected. The old completion's superseded_by is
+// set in the same transaction as the new insert, so the history never holds
+// two current completions for one item.
+//
+// The item must be claimed by rigHandle and supersedes must be a current
+// (not yet superseded) completion of wantedID; otherwise nothing is written.
+func ResubmitCompletion(townRoot, completionID, supersedes, wantedID, rigHandle, evidence string, draft bool) error {
+	status := "in_review"
+	if draft {
+		status = "draft"
+	}
+	holder := claimHolderCond(rigHandle)
+	script := "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE completions SET superseded_by=?
+  WHERE id=? AND wanted_id=? AND superseded_by IS NULL
+  AND EXISTS (SELECT 1 FROM wanted WHERE id=? AND status='claimed' AND `+holder+`);
+SET @superseded = ROW_COUNT();
+UPDATE wanted SET status=?, evidence_url=?, completion_count=COALESCE(completion_count, 0)+1, updated_at=NOW()
+  WHERE id=? AND status='claimed' AND `+holder+` AND @superseded > 0;
+INSERT INTO completions (id, wanted_id, completed_by, evidence, completed_at)
+  SELECT ?, ?, ?, ?, NOW() FROM dual WHERE @superseded > 0;
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		completionID, supersedes, wantedID,
+		wantedID,
+		status, evidence, wantedID,
+		completionID, wantedID, rigHandle, evidence,
+		wlCommitMessage(doneCommitAction(status), wantedID, rigHandle, completionID, "supersedes "+supersedes))
+
+	err := doltSQLScriptWithRetry(townRoot, script)
+	if err == nil {
+		return nil
+	}
+	if isDuplicateKey(err) {
+		return completionIDTakenError(completionID)
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("completion %q is not a current completion of %q, or the item is not claimed by %q", supersedes, wantedID, rigHandle)
+	}
+	return fmt.Errorf("resubmission failed: %w", err)
+}
+
+// FinalizeCompletion promotes a draft completion to review. The item must
+// have status='draft' and be held by rigHandle (see claimHolderCond). A
+// non-empty evidence replaces the evidence recorded with rigHandle's current
+// completion; empty evidence keeps it. The item moves first and the evidence
+// write depends on it, so evidence never changes on an item that did not
+// move. Both writes share one SQL transaction.
+func FinalizeCompletion(townRoot, wantedID, rigHandle, evidence string) error {
+	err := doltSQLScriptWithRetry(townRoot, FinalizeCompletionScript(wantedID, rigHandle, evidence))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q has no draft by %q or does not exist", wantedID, rigHandle)
+	}
+	return fmt.Errorf("finalizing completion: %w", err)
+}
+
+// FinalizeCompletionScript returns the SQL script FinalizeCompletion executes.
+func FinalizeCompletionScript(wantedID, rigHandle, evidence string) string {
+	evidenceSet, evidenceUpdate := "", ""
+	if evidence != "" {
+		evidenceSet = BindSQL(", evidence_url=?", evidence)
+		evidenceUpdate = BindSQL(`UPDATE completions SET evidence=?
+  WHERE wanted_id=? AND completed_by=? AND superseded_by IS NULL AND @finalized > 0;
+`, evidence, wantedID, rigHandle)
+	}
+
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE wanted SET status='in_review'`+evidenceSet+`, updated_at=NOW()
+  WHERE id=? AND status='draft' AND `+claimHolderCond(rigHandle)+`;
+SET @finalized = ROW_COUNT();
+`, wantedID) + evidenceUpdate + BindSQL(`COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`, wlCommitMessage("done --final", wantedID, rigHandle))
+}
+
+// AmendCompletion replaces the evidence on rigHandle's current completion of
+// wantedID, and the item's evidence_url, while the item is in review. Only
+// the rig that submitted the completion may amend it; group members cannot
+// amend each other's submissions. An 'amend' event is recorded in
+// wanted_history, all in one SQL transaction and one Dolt commit.
+func AmendCompletion(townRoot, wantedID, rigHandle, evidence string) error {
+	err := doltSQLScriptWithRetry(townRoot, AmendCompletionScript(wantedID, rigHandle, evidence))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is not in review with a completion by %q, or the evidence is unchanged", wantedID, rigHandle)
+	}
+	return fmt.Errorf("amending completion: %w", err)
+}
+
+// AmendCompletionScript returns the SQL script AmendCompletion executes.
+func AmendCompletionScript(wantedID, rigHandle, evidence string) string {
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE completions SET evidence=?
+  WHERE wanted_id=? AND completed_by=? AND superseded_by IS NULL
+  AND EXISTS (SELECT 1 FROM wanted WHERE id=? AND status='in_review');
+SET @amended = ROW_COUNT();
+UPDATE wanted SET evidence_url=?, updated_at=NOW() WHERE id=? AND @amended > 0;
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), ?, 'amend', ?, ?, NOW() FROM dual WHERE @amended > 0;
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		evidence, wantedID, rigHandle,
+		wantedID,
+		evidence, wantedID,
+		wantedID, rigHandle, "evidence: "+evidence,
+		wlCommitMessage("done --amend", wantedID, rigHandle))
 }
 
 // QueryWanted fetches a wanted item by ID. Returns nil if not found.
 func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
-	query := fmt.Sprintf(`USE %s; SELECT id, title, status, COALESCE(claimed_by, '') as claimed_by FROM wanted WHERE id='%s';`,
-		WLCommonsDB, EscapeSQL(wantedID))
+	query := "USE " + WLCommonsDB + "; " + BindSQL(`SELECT id, title, status, priority, COALESCE(claimed_by, '') as claimed_by, COALESCE(claimed_group, '') as claimed_group FROM wanted WHERE id=?;`, wantedID)
 
 	output, err := doltSQLQuery(townRoot, query)
 	if err != nil {
@@ -365,24 +1088,334 @@ func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
 	}
 
 	row := rows[0]
+	priority, _ := strconv.Atoi(row["priority"])
 	item := &WantedItem{
 		ID:        row["id"],
 		Title:     row["title"],
 		Status:    row["status"],
+		Priority:  priority,
 		ClaimedBy: row["claimed_by"],
+
+		ClaimedGroup: row["claimed_group"],
 	}
 	return item, nil
 }
 
-// doltSQLQuery executes a SQL query and returns the raw CSV output.
+// QueryBlockers returns the items that wantedID depends on, with their current
+// status. Returns nil when the item has no dependencies or the wasteland
+// predates the wanted_deps table.
+func QueryBlockers(townRoot, wantedID string) ([]*WantedItem, error) {
+	query := fmt.Sprintf(`USE %s; SELECT w.id, w.title, w.status, COALESCE(w.claimed_by, '') as claimed_by FROM wanted_deps d JOIN wanted w ON w.id = d.depends_on WHERE d.wanted_id='%s' ORDER BY w.id;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var blockers []*WantedItem
+	for _, row := range parseSimpleCSV(output) {
+		blockers = append(blockers, &WantedItem{
+			ID:        row["id"],
+			Title:     row["title"],
+			Status:    row["status"],
+			ClaimedBy: row["claimed_by"],
+		})
+	}
+	return blockers, nil
+}
+
+// QueryDependents returns the items that depend on wantedID, with their
+// current status and claimant: the reverse of QueryBlockers. Returns nil
+// when nothing depends on it or the wasteland predates the wanted_deps
+// table.
+func QueryDependents(townRoot, wantedID string) ([]*WantedItem, error) {
+	query := fmt.Sprintf(`USE %s; SELECT w.id, w.title, w.status, COALESCE(w.claimed_by, '') as claimed_by FROM wanted_deps d JOIN wanted w ON w.id = d.wanted_id WHERE d.depends_on='%s' ORDER BY w.id;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var dependents []*WantedItem
+	for _, row := range parseSimpleCSV(output) {
+		dependents = append(dependents, &WantedItem{
+			ID:        row["id"],
+			Title:     row["title"],
+			Status:    row["status"],
+			ClaimedBy: row["claimed_by"],
+		})
+	}
+	return dependents, nil
+}
+
+// parseWantedRow builds a WantedItem from a full wanted row. Columns missing
+// from row, e.g. in an older schema, are left at their zero values.
+func parseWantedRow(row map[string]string) *WantedItem {
+	priority, _ := strconv.Atoi(row["priority"])
+	completionCount, _ := strconv.Atoi(row["completion_count"])
+	updatedAt, _ := time.Parse(doltTimestampLayout, row["updated_at"])
+	return &WantedItem{
+		ID:            row["id"],
+		Title:         row["title"],
+		Description:   row["description"],
+		Project:       row["project"],
+		Type:          row["type"],
+		Priority:      priority,
+		Tags:          parseTagsJSON(row["tags"]),
+		PostedBy:      row["posted_by"],
+		ClaimedBy:     row["claimed_by"],
+		ClaimedVia:    row["claimed_via"],
+		ClaimedGroup:  row["claimed_group"],
+		Assignee:      row["assignee"],
+		Status:        row["status"],
+		EffortLevel:   row["effort_level"],
+		TimeoutAction: row["timeout_action"],
+
+		CompletionCount: completionCount,
+		MergedInto:      row["merged_into"],
+		EvidenceURL:     row["evidence_url"],
+		ExpiresAt:       parseDoltTimestamp(row["expires_at"]),
+		ClaimedAt:       parseDoltTimestamp(row["claimed_at"]),
+		ReopenedBy:      row["reopened_by"],
+		ReopenedAt:      parseDoltTimestamp(row["reopened_at"]),
+		CreatedAt:       parseDoltTimestamp(row["created_at"]),
+		UpdatedAt:       updatedAt,
+	}
+}
+
+// validCommitRefRe matches the commit references QueryWantedAsOf accepts:
+// hashes, branch names, and ancestry such as HEAD~2 or main^.
+var validCommitRefRe = regexp.MustCompile(`^[A-Za-z0-9._/~^-]+$`)
+
+// QueryWantedAsOf returns wantedID's row as it was at commit, using Dolt's
+// AS OF time travel. It returns nil, nil when the item did not exist at that
+// commit. The row is selected with SELECT * so commits that predate newer
+// columns still read; those fields come back empty.
+func QueryWantedAsOf(townRoot, wantedID, commit string) (*WantedItem, error) {
+	if !validCommitRefRe.MatchString(commit) {
+		return nil, fmt.Errorf("invalid commit reference %q", commit)
+	}
+	query := fmt.Sprintf(`USE %s; SELECT * FROM wanted AS OF '%s' WHERE id='%s';`,
+		WLCommonsDB, EscapeSQL(commit), EscapeSQL(wantedID))
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying %s as of %s: %w", wantedID, commit, err)
+	}
+	rows := parseSimpleCSV(output)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return parseWantedRow(rows[0]), nil
+}
+
+// QueryWantedDetail returns a wanted item with its full row, dependencies,
+// comments, and completions. Related tables missing from older wastelands
+// are treated as empty.
+func QueryWantedDetail(townRoot, wantedID string) (*WantedDetail, error) {
+	query := fmt.Sprintf(`USE %s; SELECT id, title, COALESCE(description, '') as description, COALESCE(project, '') as project, COALESCE(type, '') as type, priority, COALESCE(tags, '') as tags, COALESCE(posted_by, '') as posted_by, COALESCE(claimed_by, '') as claimed_by, COALESCE(claimed_via, '') as claimed_via, COALESCE(claimed_group, '') as claimed_group, COALESCE(assignee, '') as assignee, status, COALESCE(effort_level, '') as effort_level, COALESCE(timeout_action, '') as timeout_action, COALESCE(completion_count, 0) as completion_count, COALESCE(merged_into, '') as merged_into, COALESCE(evidence_url, '') as evidence_url, expires_at, claimed_at, COALESCE(reopened_by, '') as reopened_by, reopened_at, created_at, updated_at FROM wanted WHERE id='%s';`,
+		WLCommonsDB, EscapeSQL(wantedID))
+
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, err
+	}
+	rows := parseSimpleCSV(output)
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("wanted item %q not found", wantedID)
+	}
+	item := parseWantedRow(rows[0])
+
+	detail := &WantedDetail{Item: item}
+
+	if detail.Dependencies, err = QueryBlockers(townRoot, wantedID); err != nil {
+		return nil, fmt.Errorf("querying dependencies: %w", err)
+	}
+	for _, dep := range detail.Dependencies {
+		item.DependsOn = append(item.DependsOn, dep.ID)
+	}
+
+	commentQuery := fmt.Sprintf(`USE %s; SELECT COALESCE(actor, '') as actor, COALESCE(detail, '') as detail, created_at FROM wanted_history WHERE wanted_id='%s' AND action IN ('comment', 'reject') ORDER BY created_at, id;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+	output, err = doltSQLQuery(townRoot, commentQuery)
+	if err != nil && !isTableNotFound(err) {
+		return nil, fmt.Errorf("querying comments: %w", err)
+	}
+	for _, r := range parseSimpleCSV(output) {
+		detail.Comments = append(detail.Comments, WantedComment{
+			Author:    r["actor"],
+			Body:      r["detail"],
+			CreatedAt: r["created_at"],
+		})
+	}
+
+	completionQuery := fmt.Sprintf(`USE %s; SELECT id, COALESCE(completed_by, '') as completed_by, COALESCE(evidence, '') as evidence, COALESCE(completed_at, '') as completed_at, COALESCE(validated_by, '') as validated_by, COALESCE(validated_at, '') as validated_at, COALESCE(superseded_by, '') as superseded_by FROM completions WHERE wanted_id='%s' ORDER BY completed_at, id;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+	output, err = doltSQLQuery(townRoot, completionQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying completions: %w", err)
+	}
+	for _, r := range parseSimpleCSV(output) {
+		detail.Completions = append(detail.Completions, WantedCompletion{
+			ID:           r["id"],
+			CompletedBy:  r["completed_by"],
+			Evidence:     r["evidence"],
+			CompletedAt:  r["completed_at"],
+			ValidatedBy:  r["validated_by"],
+			ValidatedAt:  r["validated_at"],
+			SupersededBy: r["superseded_by"],
+		})
+	}
+
+	watchers, err := QueryWatchers(townRoot, wantedID)
+	if err != nil {
+		return nil, err
+	}
+	detail.WatcherCount = len(watchers)
+
+	if detail.Labels, err = QueryWantedLabels(townRoot, wantedID); err != nil {
+		return nil, err
+	}
+
+	return detail, nil
+}
+
+// parseTagsJSON decodes the wanted.tags JSON array. Malformed or empty
+// values yield no tags.
+func parseTagsJSON(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+		return nil
+	}
+	return tags
+}
+
+// QuerySettings returns the wasteland-wide settings stored in the _meta table.
+// Settings are shared by every rig on the wasteland, so they carry governance
+// rules (e.g. claim strictness) rather than per-rig preferences.
+func QuerySettings(townRoot string) (map[string]string, error) {
+	query := fmt.Sprintf("USE %s; SELECT %s, value FROM _meta;", WLCommonsDB, backtickKey())
+
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]string)
+	for _, row := range parseSimpleCSV(output) {
+		settings[row["key"]] = row["value"]
+	}
+	return settings, nil
+}
+
+// ListWanted returns wanted items matching filter, highest priority (lowest
+// number) first, oldest first within a priority.
+func ListWanted(townRoot string, filter WantedFilter) ([]*WantedItem, error) {
+	output, err := doltSQLQuery(townRoot, buildListWantedQuery(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*WantedItem
+	for _, row := range parseSimpleCSV(output) {
+		priority, _ := strconv.Atoi(row["priority"])
+		items = append(items, &WantedItem{
+			ID:           row["id"],
+			Title:        row["title"],
+			Status:       row["status"],
+			Priority:     priority,
+			PostedBy:     row["posted_by"],
+			ClaimedBy:    row["claimed_by"],
+			ClaimedGroup: row["claimed_group"],
+			Assignee:     row["assignee"],
+			Tags:         parseTagsJSON(row["tags"]),
+		})
+	}
+	return items, nil
+}
+
+// buildListWantedQuery builds the SELECT used by ListWanted.
+func buildListWantedQuery(f WantedFilter) string {
+	var conds []string
+	if f.Status != "" {
+		conds = append(conds, fmt.Sprintf("status = '%s'", EscapeSQL(f.Status)))
+	}
+	if len(f.Statuses) > 0 {
+		quoted := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			quoted[i] = "'" + EscapeSQL(s) + "'"
+		}
+		conds = append(conds, "status IN ("+strings.Join(quoted, ", ")+")")
+	}
+	if f.Title != "" {
+		conds = append(conds, fmt.Sprintf("title = '%s'", EscapeSQL(f.Title)))
+	}
+	if f.HeldBy != "" {
+		conds = append(conds, claimHolderCond(f.HeldBy))
+	}
+	if f.Search != "" {
+		pattern := EscapeSQL("%" + escapeSQLLike(strings.ToLower(f.Search)) + "%")
+		conds = append(conds, fmt.Sprintf("(LOWER(title) LIKE '%s' ESCAPE '%s' OR LOWER(COALESCE(description, '')) LIKE '%s' ESCAPE '%s')", pattern, likeEscapeChar, pattern, likeEscapeChar))
+	}
+	if f.MinPriority >= 0 {
+		conds = append(conds, fmt.Sprintf("priority >= %d", f.MinPriority))
+	}
+	if f.MaxPriority >= 0 {
+		conds = append(conds, fmt.Sprintf("priority <= %d", f.MaxPriority))
+	}
+	if tc := TagCondition(f.Tags, f.TagsMatchAll); tc != "" {
+		conds = append(conds, tc)
+	}
+
+	query := fmt.Sprintf("USE %s; SELECT id, title, status, priority, COALESCE(posted_by, '') as posted_by, COALESCE(claimed_by, '') as claimed_by, COALESCE(claimed_group, '') as claimed_group, COALESCE(assignee, '') as assignee, COALESCE(tags, '') as tags, COALESCE(created_at, '') as created_at, COALESCE(updated_at, '') as updated_at FROM wanted", WLCommonsDB)
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	if f.NewestFirst {
+		query += " ORDER BY created_at DESC, id DESC"
+	} else {
+		query += " ORDER BY priority ASC, created_at ASC"
+	}
+	if f.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", f.Limit)
+	}
+	return query + ";"
+}
+
+// doltSQLQuery executes a SQL query and returns the raw CSV output. A
+// dropped server connection is reconnected and the query retried.
 func doltSQLQuery(townRoot, query string) (string, error) {
+	var out string
+	err := withReconnect(townRoot, func() error {
+		var err error
+		out, err = doltSQLQueryOnce(townRoot, query)
+		return err
+	})
+	return out, err
+}
+
+func doltSQLQueryOnce(townRoot, query string) (string, error) {
 	config := DefaultConfig(townRoot)
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	limit := effectiveSQLTimeout(DefaultSQLQueryTimeout)
+	ctx, cancel := context.WithTimeout(sqlContext, limit)
 	defer cancel()
 
 	cmd := buildDoltSQLCmd(ctx, config, "-r", "csv", "-q", query)
-	output, err := cmd.CombinedOutput()
+	output, err := runDoltCmd(cmd)
 	if err != nil {
+		if terr := sqlTimeoutError(ctx, limit); terr != nil {
+			return "", terr
+		}
 		return "", fmt.Errorf("dolt sql query failed: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
 	return string(output), nil
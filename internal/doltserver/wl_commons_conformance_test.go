@@ -1,8 +1,10 @@
 package doltserver
 
 import (
+	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 // wlCommonsConformance is a shared test suite that validates any WLCommonsStore
@@ -329,6 +331,719 @@ func wlCommonsConformance(t *testing.T, newStore func(t *testing.T) WLCommonsSto
 			t.Errorf("ClaimedBy = %q, want to contain %q", got.ClaimedBy, "specific-rig")
 		}
 	})
+
+	t.Run("QueryBlockersReportsStatus", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf12", Title: "Blocker"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf13", Title: "Dependent", DependsOn: []string{"w-conf12"}}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+
+		blockers, err := store.QueryBlockers("w-conf13")
+		if err != nil {
+			t.Fatalf("QueryBlockers() error: %v", err)
+		}
+		if len(blockers) != 1 {
+			t.Fatalf("QueryBlockers() returned %d items, want 1", len(blockers))
+		}
+		if blockers[0].ID != "w-conf12" || blockers[0].Status != "open" {
+			t.Errorf("blocker = %s (%s), want w-conf12 (open)", blockers[0].ID, blockers[0].Status)
+		}
+
+		none, err := store.QueryBlockers("w-conf12")
+		if err != nil {
+			t.Fatalf("QueryBlockers() error: %v", err)
+		}
+		if len(none) != 0 {
+			t.Errorf("QueryBlockers() on item without deps = %d items, want 0", len(none))
+		}
+	})
+
+	t.Run("QueryDependentsIsReverseOfBlockers", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf14", Title: "Blocker"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf15", Title: "Dependent", DependsOn: []string{"w-conf14"}}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWanted("w-conf15", "dep-rig"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+
+		dependents, err := store.QueryDependents("w-conf14")
+		if err != nil {
+			t.Fatalf("QueryDependents() error: %v", err)
+		}
+		if len(dependents) != 1 {
+			t.Fatalf("QueryDependents() returned %d items, want 1", len(dependents))
+		}
+		if d := dependents[0]; d.ID != "w-conf15" || d.Status != "claimed" || d.ClaimedBy != "dep-rig" {
+			t.Errorf("dependent = %s (%s, %s), want w-conf15 (claimed, dep-rig)", d.ID, d.Status, d.ClaimedBy)
+		}
+
+		none, err := store.QueryDependents("w-conf15")
+		if err != nil {
+			t.Fatalf("QueryDependents() error: %v", err)
+		}
+		if len(none) != 0 {
+			t.Errorf("QueryDependents() on item nothing depends on = %d items, want 0", len(none))
+		}
+	})
+
+	t.Run("AddCommentRequiresItem", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf14", Title: "Commented"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.AddComment("w-conf14", "rig-a", "looking into it"); err != nil {
+			t.Errorf("AddComment() error: %v", err)
+		}
+		if err := store.AddComment("w-nonexistent", "rig-a", "hello"); err == nil {
+			t.Error("AddComment() on missing item should fail")
+		}
+		if err := store.AddComment("w-conf14", "rig-a", "  "); err == nil {
+			t.Error("AddComment() with empty body should fail")
+		}
+	})
+
+	t.Run("WatchersSubscribeAndUnsubscribe", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf15", Title: "Watched"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.AddWatcher("w-conf15", "rig-a", "mayor/"); err != nil {
+			t.Fatalf("AddWatcher() error: %v", err)
+		}
+		if err := store.AddWatcher("w-conf15", "rig-a", "mayor/"); err != nil {
+			t.Errorf("repeat AddWatcher() should be a no-op, got %v", err)
+		}
+		watchers, err := store.QueryWatchers("w-conf15")
+		if err != nil {
+			t.Fatalf("QueryWatchers() error: %v", err)
+		}
+		if len(watchers) != 1 || watchers[0].RigHandle != "rig-a" || watchers[0].Address != "mayor/" {
+			t.Errorf("watchers = %+v", watchers)
+		}
+		if err := store.RemoveWatcher("w-conf15", "rig-a"); err != nil {
+			t.Errorf("RemoveWatcher() error: %v", err)
+		}
+		if err := store.RemoveWatcher("w-conf15", "rig-a"); err == nil {
+			t.Error("RemoveWatcher() when not subscribed should fail")
+		}
+	})
+
+	t.Run("LabelsSetAndReplace", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf16", Title: "Labelled"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.SetLabels("w-conf16", "rig-a", []WantedLabel{{Key: "ticket", Value: "OPS-1"}, {Key: "sprint", Value: "s1"}}); err != nil {
+			t.Fatalf("SetLabels() error: %v", err)
+		}
+		if err := store.SetLabels("w-conf16", "rig-b", []WantedLabel{{Key: "ticket", Value: "OPS-2"}}); err != nil {
+			t.Fatalf("SetLabels() replace error: %v", err)
+		}
+		detail, err := store.QueryWantedDetail("w-conf16")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		want := []WantedLabel{{Key: "sprint", Value: "s1", SetBy: "rig-a"}, {Key: "ticket", Value: "OPS-2", SetBy: "rig-b"}}
+		if len(detail.Labels) != 2 || detail.Labels[0] != want[0] || detail.Labels[1] != want[1] {
+			t.Errorf("Labels = %+v, want %+v", detail.Labels, want)
+		}
+	})
+
+	t.Run("CompletionCountIncrements", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf17", Title: "Contested"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWanted("w-conf17", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.SubmitDraftCompletion("c-conf17a", "w-conf17", "rig-a", "draft"); err != nil {
+			t.Fatalf("SubmitDraftCompletion() error: %v", err)
+		}
+		if err := store.FinalizeCompletion("w-conf17", "rig-a", ""); err != nil {
+			t.Fatalf("FinalizeCompletion() error: %v", err)
+		}
+		detail, err := store.QueryWantedDetail("w-conf17")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		if detail.Item.CompletionCount != 1 {
+			t.Errorf("CompletionCount after finalize = %d, want 1", detail.Item.CompletionCount)
+		}
+	})
+
+	t.Run("MergeWithdrawsOpenDuplicates", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		for _, id := range []string{"w-conf18a", "w-conf18b", "w-conf18c"} {
+			if err := store.InsertWanted(&WantedItem{ID: id, Title: "Fix login"}); err != nil {
+				t.Fatalf("InsertWanted(%s) error: %v", id, err)
+			}
+		}
+		if err := store.ClaimWanted("w-conf18c", "rig-b"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.MergeWanted("w-conf18a", []string{"w-conf18b", "w-conf18c"}, "rig-a"); err != nil {
+			t.Fatalf("MergeWanted() error: %v", err)
+		}
+
+		dup, err := store.QueryWantedDetail("w-conf18b")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		if dup.Item.Status != StatusWithdrawn || dup.Item.MergedInto != "w-conf18a" {
+			t.Errorf("duplicate = status %q merged_into %q, want withdrawn into w-conf18a", dup.Item.Status, dup.Item.MergedInto)
+		}
+		claimed, err := store.QueryWantedDetail("w-conf18c")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		if claimed.Item.Status != StatusClaimed || claimed.Item.MergedInto != "" {
+			t.Errorf("claimed item should be left alone, got status %q merged_into %q", claimed.Item.Status, claimed.Item.MergedInto)
+		}
+		if err := store.MergeWanted("w-conf18a", []string{"w-conf18c"}, "rig-a"); err == nil {
+			t.Error("MergeWanted() with no open duplicates should fail")
+		}
+	})
+
+	t.Run("ApproveIsAllOrNothing", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		for i, id := range []string{"w-conf19a", "w-conf19b", "w-conf19c"} {
+			if err := store.InsertWanted(&WantedItem{ID: id, Title: "Approve me", PostedBy: "poster"}); err != nil {
+				t.Fatalf("InsertWanted(%s) error: %v", id, err)
+			}
+			if err := store.ClaimWanted(id, "rig-a"); err != nil {
+				t.Fatalf("ClaimWanted(%s) error: %v", id, err)
+			}
+			if i < 2 {
+				if err := store.SubmitCompletion("c-conf19"+id[len(id)-1:], id, "rig-a", "https://example.com/pr"); err != nil {
+					t.Fatalf("SubmitCompletion(%s) error: %v", id, err)
+				}
+			}
+		}
+
+		if err := store.ApproveCompletions([]string{"w-conf19a", "w-conf19c"}, "poster"); err == nil {
+			t.Error("ApproveCompletions() with an item not in review should fail")
+		}
+		if item, _ := store.QueryWanted("w-conf19a"); item.Status != StatusInReview {
+			t.Errorf("failed batch changed w-conf19a to %q", item.Status)
+		}
+
+		if err := store.ApproveCompletions([]string{"w-conf19a", "w-conf19b"}, "poster"); err != nil {
+			t.Fatalf("ApproveCompletions() error: %v", err)
+		}
+		detail, err := store.QueryWantedDetail("w-conf19b")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		if detail.Item.Status != StatusCompleted {
+			t.Errorf("status = %q, want completed", detail.Item.Status)
+		}
+		if len(detail.Completions) != 1 || detail.Completions[0].ValidatedBy != "poster" {
+			t.Errorf("completions = %+v, want one validated by poster", detail.Completions)
+		}
+	})
+
+	t.Run("GroupClaim", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.AddGroupMembers("conf19", "rig-a", []string{"rig-a", "rig-b"}); err != nil {
+			t.Fatalf("AddGroupMembers() error: %v", err)
+		}
+		members, err := store.QueryGroupMembers("conf19")
+		if err != nil {
+			t.Fatalf("QueryGroupMembers() error: %v", err)
+		}
+		if len(members) != 2 || members[0] != "rig-a" || members[1] != "rig-b" {
+			t.Errorf("members = %v, want [rig-a rig-b]", members)
+		}
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf19", Title: "Team effort"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWantedForGroup("w-conf19", "rig-c", "conf19"); err == nil {
+			t.Error("ClaimWantedForGroup() by a non-member should fail")
+		}
+		if err := store.ClaimWantedForGroup("w-conf19", "rig-a", "conf19"); err != nil {
+			t.Fatalf("ClaimWantedForGroup() error: %v", err)
+		}
+		item, err := store.QueryWanted("w-conf19")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.ClaimedBy != "rig-a" || item.ClaimedGroup != "conf19" {
+			t.Errorf("claim = %q for group %q, want rig-a for conf19", item.ClaimedBy, item.ClaimedGroup)
+		}
+
+		if err := store.SubmitCompletion("c-conf19c", "w-conf19", "rig-c", "https://example.com"); err == nil {
+			t.Error("SubmitCompletion() by a non-member should fail")
+		}
+		if err := store.SubmitCompletion("c-conf19b", "w-conf19", "rig-b", "https://example.com"); err != nil {
+			t.Fatalf("SubmitCompletion() by a member error: %v", err)
+		}
+	})
+
+	t.Run("UnclaimReturnsItemToBoard", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf20", Title: "Unclaim me", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.UnclaimWanted("w-conf20", "rig-a", false); err == nil {
+			t.Error("UnclaimWanted() on an open item should fail")
+		}
+		if err := store.ClaimWanted("w-conf20", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.UnclaimWanted("w-conf20", "rig-b", false); err == nil {
+			t.Error("UnclaimWanted() by a rig not holding the claim should fail")
+		}
+		if err := store.UnclaimWanted("w-conf20", "rig-a", false); err != nil {
+			t.Fatalf("UnclaimWanted() error: %v", err)
+		}
+		item, err := store.QueryWanted("w-conf20")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Status != StatusOpen || item.ClaimedBy != "" {
+			t.Errorf("after unclaim: status %q claimed by %q, want open and unclaimed", item.Status, item.ClaimedBy)
+		}
+
+		if err := store.ClaimWanted("w-conf20", "rig-a"); err != nil {
+			t.Fatalf("re-ClaimWanted() error: %v", err)
+		}
+		if err := store.UnclaimWanted("w-conf20", "rig-b", true); err != nil {
+			t.Fatalf("forced UnclaimWanted() error: %v", err)
+		}
+		if item, _ := store.QueryWanted("w-conf20"); item.Status != StatusOpen {
+			t.Errorf("after forced unclaim: status %q, want open", item.Status)
+		}
+	})
+
+	t.Run("RejectReturnsItemToClaimant", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf21", Title: "Reject me", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWanted("w-conf21", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.RejectCompletion("w-conf21", "poster", "no tests"); err == nil {
+			t.Error("RejectCompletion() on an item not in review should fail")
+		}
+		if err := store.SubmitCompletion("c-conf21", "w-conf21", "rig-a", "https://example.com/pr"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+		if err := store.RejectCompletion("w-conf21", "poster", "no tests"); err != nil {
+			t.Fatalf("RejectCompletion() error: %v", err)
+		}
+
+		detail, err := store.QueryWantedDetail("w-conf21")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		if detail.Item.Status != StatusClaimed || detail.Item.ClaimedBy != "rig-a" {
+			t.Errorf("after reject: status %q claimed by %q, want claimed by rig-a", detail.Item.Status, detail.Item.ClaimedBy)
+		}
+		if n := len(detail.Comments); n != 1 || detail.Comments[0].Body != "rejected: no tests" {
+			t.Errorf("comments = %+v, want the rejection reason", detail.Comments)
+		}
+		if err := store.ResubmitCompletion("c-conf21b", "c-conf21", "w-conf21", "rig-a", "https://example.com/pr2", false); err != nil {
+			t.Errorf("ResubmitCompletion() after reject error: %v", err)
+		}
+	})
+
+	t.Run("ReapReleasesOnlyStaleClaims", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf22", Title: "Abandon me", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWanted("w-conf22", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.ReapClaim("w-conf22", time.Now().Add(-time.Hour), "janitor"); err == nil {
+			t.Error("ReapClaim() of a fresh claim should fail")
+		}
+
+		later := time.Now().Add(time.Hour)
+		stale, err := store.QueryStaleClaims(later)
+		if err != nil {
+			t.Fatalf("QueryStaleClaims() error: %v", err)
+		}
+		if len(stale) != 1 || stale[0].ID != "w-conf22" || stale[0].ClaimedBy != "rig-a" {
+			t.Fatalf("QueryStaleClaims() = %v, want w-conf22 claimed by rig-a", stale)
+		}
+		if err := store.ReapClaim("w-conf22", later, "janitor"); err != nil {
+			t.Fatalf("ReapClaim() error: %v", err)
+		}
+		item, err := store.QueryWanted("w-conf22")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Status != StatusOpen || item.ClaimedBy != "" {
+			t.Errorf("after reap: status %q claimed by %q, want open and unclaimed", item.Status, item.ClaimedBy)
+		}
+		if err := store.ReapClaim("w-conf22", later, "janitor"); err == nil {
+			t.Error("second ReapClaim() should fail")
+		}
+	})
+
+	t.Run("ListWantedHeldBy", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.AddGroupMembers("conf23", "rig-b", []string{"rig-a", "rig-b"}); err != nil {
+			t.Fatalf("AddGroupMembers() error: %v", err)
+		}
+		for _, id := range []string{"w-conf23a", "w-conf23b", "w-conf23c", "w-conf23d"} {
+			if err := store.InsertWanted(&WantedItem{ID: id, Title: id}); err != nil {
+				t.Fatalf("InsertWanted(%s) error: %v", id, err)
+			}
+		}
+		if err := store.ClaimWanted("w-conf23a", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.ClaimWantedForGroup("w-conf23b", "rig-b", "conf23"); err != nil {
+			t.Fatalf("ClaimWantedForGroup() error: %v", err)
+		}
+		if err := store.ClaimWanted("w-conf23c", "rig-c"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-conf23a", "w-conf23a", "rig-a", "https://example.com"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+
+		items, err := store.ListWanted(WantedFilter{
+			HeldBy:      "rig-a",
+			Statuses:    []string{StatusClaimed, StatusInReview},
+			MinPriority: -1,
+			MaxPriority: -1,
+		})
+		if err != nil {
+			t.Fatalf("ListWanted() error: %v", err)
+		}
+		var ids []string
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if strings.Join(ids, ",") != "w-conf23a,w-conf23b" {
+			t.Errorf("ListWanted(HeldBy rig-a) = %v, want [w-conf23a w-conf23b]", ids)
+		}
+		for _, item := range items {
+			if item.ID == "w-conf23b" && item.ClaimedGroup != "conf23" {
+				t.Errorf("w-conf23b claimed group = %q, want conf23", item.ClaimedGroup)
+			}
+		}
+
+		items, err = store.ListWanted(WantedFilter{HeldBy: "rig-a", Statuses: []string{StatusClaimed}, MinPriority: -1, MaxPriority: -1})
+		if err != nil {
+			t.Fatalf("ListWanted() error: %v", err)
+		}
+		if len(items) != 1 || items[0].ID != "w-conf23b" {
+			t.Errorf("ListWanted(claimed) = %v, want only w-conf23b", items)
+		}
+	})
+
+	t.Run("CompletionIDCollisionWritesNothing", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		for _, id := range []string{"w-conf24a", "w-conf24b"} {
+			if err := store.InsertWanted(&WantedItem{ID: id, Title: id}); err != nil {
+				t.Fatalf("InsertWanted(%s) error: %v", id, err)
+			}
+			if err := store.ClaimWanted(id, "rig-a"); err != nil {
+				t.Fatalf("ClaimWanted(%s) error: %v", id, err)
+			}
+		}
+		if err := store.SubmitCompletion("c-conf24", "w-conf24a", "rig-a", "https://example.com/a"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+		err := store.SubmitCompletion("c-conf24", "w-conf24b", "rig-a", "https://example.com/b")
+		if err == nil || !strings.Contains(err.Error(), "already taken") {
+			t.Fatalf("SubmitCompletion() with a taken ID error = %v, want it reported as taken", err)
+		}
+		item, err := store.QueryWanted("w-conf24b")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Status != StatusClaimed {
+			t.Errorf("after a colliding completion, status = %q, want claimed", item.Status)
+		}
+
+	})
+
+	t.Run("ReopenRetiresCompletion", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf25", Title: "Reopen me", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ReopenWanted("w-conf25", "poster", false); err == nil {
+			t.Error("ReopenWanted() on an open item should fail")
+		}
+		if err := store.ClaimWanted("w-conf25", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-conf25a", "w-conf25", "rig-a", "https://example.com/a"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+		if err := store.ReopenWanted("w-conf25", "rig-a", false); err == nil {
+			t.Error("ReopenWanted() by a town that did not post the item should fail")
+		}
+		if err := store.ReopenWanted("w-conf25", "poster", false); err != nil {
+			t.Fatalf("ReopenWanted() error: %v", err)
+		}
+
+		detail, err := store.QueryWantedDetail("w-conf25")
+		if err != nil {
+			t.Fatalf("QueryWantedDetail() error: %v", err)
+		}
+		if item := detail.Item; item.Status != StatusOpen || item.ClaimedBy != "" || item.ReopenedBy != "poster" {
+			t.Errorf("after reopen: status %q claimed by %q reopened by %q, want open, unclaimed, reopened by poster",
+				item.Status, item.ClaimedBy, item.ReopenedBy)
+		}
+		for _, c := range detail.Completions {
+			if c.SupersededBy == "" {
+				t.Errorf("completion %s is still current after reopen", c.ID)
+			}
+		}
+
+		// The next claimant can complete the reopened item afresh.
+		if err := store.ClaimWanted("w-conf25", "rig-b"); err != nil {
+			t.Fatalf("ClaimWanted() after reopen error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-conf25b", "w-conf25", "rig-b", "https://example.com/b"); err != nil {
+			t.Fatalf("SubmitCompletion() after reopen error: %v", err)
+		}
+		if err := store.ApproveCompletions([]string{"w-conf25"}, "poster"); err != nil {
+			t.Fatalf("ApproveCompletions() error: %v", err)
+		}
+		if err := store.ReopenWanted("w-conf25", "rig-c", true); err != nil {
+			t.Fatalf("forced ReopenWanted() of a completed item error: %v", err)
+		}
+		item, err := store.QueryWanted("w-conf25")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Status != StatusOpen {
+			t.Errorf("after forced reopen: status %q, want open", item.Status)
+		}
+	})
+
+	t.Run("HistoryRecordsActions", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf26", Title: "Audit me", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if events, err := store.QueryWantedHistory("w-conf26"); err != nil || len(events) != 0 {
+			t.Fatalf("QueryWantedHistory() on a new item = %v, %v; want no events", events, err)
+		}
+		if err := store.ClaimWantedFor("w-conf26", "rig-a", "coord"); err != nil {
+			t.Fatalf("ClaimWantedFor() error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-conf26", "w-conf26", "rig-a", "https://example.com"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+		if err := store.RejectCompletion("w-conf26", "poster", "no tests"); err != nil {
+			t.Fatalf("RejectCompletion() error: %v", err)
+		}
+
+		events, err := store.QueryWantedHistory("w-conf26")
+		if err != nil {
+			t.Fatalf("QueryWantedHistory() error: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Action+" by "+e.Actor)
+		}
+		// Both events may land in the same second, so compare as a set.
+		sort.Strings(got)
+		if strings.Join(got, ", ") != "claim by coord, reject by poster" {
+			t.Errorf("history = %v, want the delegated claim and the rejection", got)
+		}
+	})
+
+	t.Run("AssignRequiresClaimHolder", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf27", Title: "Assign me", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.AssignWanted("w-conf27", "rig-a", "polecat-1"); err == nil {
+			t.Fatal("AssignWanted() on an open item should fail")
+		}
+		if err := store.ClaimWanted("w-conf27", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.AssignWanted("w-conf27", "rig-b", "polecat-2"); err == nil {
+			t.Error("AssignWanted() by a town that does not hold the claim should fail")
+		}
+		if err := store.AssignWanted("w-conf27", "rig-a", "polecat-1"); err != nil {
+			t.Fatalf("AssignWanted() error: %v", err)
+		}
+		item, err := store.QueryWanted("w-conf27")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Assignee != "polecat-1" {
+			t.Errorf("Assignee = %q, want polecat-1", item.Assignee)
+		}
+
+		// Releasing the claim clears the assignee with it.
+		if err := store.UnclaimWanted("w-conf27", "rig-a", false); err != nil {
+			t.Fatalf("UnclaimWanted() error: %v", err)
+		}
+		item, err = store.QueryWanted("w-conf27")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Assignee != "" {
+			t.Errorf("after unclaim: Assignee = %q, want empty", item.Assignee)
+		}
+	})
+
+	t.Run("PendingReviewsJoinCurrentCompletion", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		for _, item := range []*WantedItem{
+			{ID: "w-conf28a", Title: "Review me", PostedBy: "poster"},
+			{ID: "w-conf28b", Title: "Someone else's", PostedBy: "other"},
+			{ID: "w-conf28c", Title: "Still claimed", PostedBy: "poster"},
+		} {
+			if err := store.InsertWanted(item); err != nil {
+				t.Fatalf("InsertWanted(%s) error: %v", item.ID, err)
+			}
+			if err := store.ClaimWanted(item.ID, "rig-a"); err != nil {
+				t.Fatalf("ClaimWanted(%s) error: %v", item.ID, err)
+			}
+		}
+		for _, id := range []string{"w-conf28a", "w-conf28b"} {
+			if err := store.SubmitCompletion("c-"+id, id, "rig-a", "https://example.com/"+id); err != nil {
+				t.Fatalf("SubmitCompletion(%s) error: %v", id, err)
+			}
+		}
+
+		reviews, err := store.QueryPendingReviews("poster")
+		if err != nil {
+			t.Fatalf("QueryPendingReviews() error: %v", err)
+		}
+		if len(reviews) != 1 {
+			t.Fatalf("QueryPendingReviews(poster) = %+v, want only w-conf28a", reviews)
+		}
+		r := reviews[0]
+		if r.WantedID != "w-conf28a" || r.Title != "Review me" || r.Completion.ID != "c-w-conf28a" || r.Completion.CompletedBy != "rig-a" || r.Completion.Evidence != "https://example.com/w-conf28a" {
+			t.Errorf("review = %+v", r)
+		}
+
+		all, err := store.QueryPendingReviews("")
+		if err != nil {
+			t.Fatalf("QueryPendingReviews(all) error: %v", err)
+		}
+		var ids []string
+		for _, r := range all {
+			if strings.HasPrefix(r.WantedID, "w-conf28") {
+				ids = append(ids, r.WantedID)
+			}
+		}
+		sort.Strings(ids)
+		if strings.Join(ids, ",") != "w-conf28a,w-conf28b" {
+			t.Errorf("QueryPendingReviews(all) items = %v, want both in-review items", ids)
+		}
+
+		if err := store.ApproveCompletions([]string{"w-conf28a"}, "poster"); err != nil {
+			t.Fatalf("ApproveCompletions() error: %v", err)
+		}
+		if reviews, err := store.QueryPendingReviews("poster"); err != nil || len(reviews) != 0 {
+			t.Errorf("QueryPendingReviews() after approval = %+v, %v; want none", reviews, err)
+		}
+	})
+
+	t.Run("ClaimLockExcludesOtherTowns", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.AcquireClaimLock("w-conf29", "rig-a", time.Minute); err != nil {
+			t.Fatalf("AcquireClaimLock(rig-a) error: %v", err)
+		}
+		err := store.AcquireClaimLock("w-conf29", "rig-b", time.Minute)
+		if !IsClaimLockHeld(err) || !strings.Contains(err.Error(), "locked by rig-a") {
+			t.Fatalf("AcquireClaimLock(rig-b) error = %v, want locked by rig-a", err)
+		}
+		if err := store.AcquireClaimLock("w-conf29", "rig-a", time.Minute); err != nil {
+			t.Errorf("re-locking as the holder error: %v", err)
+		}
+		if err := store.ReleaseClaimLock("w-conf29", "rig-b"); err != nil {
+			t.Errorf("ReleaseClaimLock(non-holder) error: %v", err)
+		}
+		if err := store.AcquireClaimLock("w-conf29", "rig-b", time.Minute); !IsClaimLockHeld(err) {
+			t.Errorf("a non-holder's release dropped the lock: %v", err)
+		}
+		if err := store.ReleaseClaimLock("w-conf29", "rig-a"); err != nil {
+			t.Fatalf("ReleaseClaimLock(rig-a) error: %v", err)
+		}
+		if err := store.AcquireClaimLock("w-conf29", "rig-b", time.Minute); err != nil {
+			t.Errorf("AcquireClaimLock(rig-b) after release error: %v", err)
+		}
+		_ = store.ReleaseClaimLock("w-conf29", "rig-b")
+	})
+
+	t.Run("SubmitCompletionRefusesWhileCurrentCompletion", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.InsertWanted(&WantedItem{ID: "w-conf30", Title: "Rejected once", PostedBy: "poster"}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWanted("w-conf30", "rig-a"); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-conf30a", "w-conf30", "rig-a", "https://pr/30a"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+		if err := store.RejectCompletion("w-conf30", "poster", "try again"); err != nil {
+			t.Fatalf("RejectCompletion() error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-conf30b", "w-conf30", "rig-a", "https://pr/30b"); err == nil {
+			t.Fatal("SubmitCompletion() over a rejected, still-current completion should fail")
+		}
+		item, err := store.QueryWanted("w-conf30")
+		if err != nil {
+			t.Fatalf("QueryWanted() error: %v", err)
+		}
+		if item.Status != StatusClaimed {
+			t.Errorf("Status = %q, want claimed", item.Status)
+		}
+	})
 }
 
 // TestFakeWLCommonsStore_Conformance runs the conformance suite against the fake.
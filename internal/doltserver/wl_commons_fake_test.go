@@ -2,28 +2,74 @@ package doltserver
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 // fakeWLCommonsStore is an in-memory implementation of WLCommonsStore for testing.
 // It enforces the same business rules as the real SQL implementation.
 type fakeWLCommonsStore struct {
-	mu    sync.Mutex
-	items map[string]*WantedItem
-	dbOK  bool
+	mu          sync.Mutex
+	items       map[string]*WantedItem
+	settings    map[string]string
+	comments    map[string][]WantedComment
+	history     map[string][]WantedEvent
+	completions map[string][]WantedCompletion
+	watchers    map[string][]WantedWatcher
+	labels      map[string]map[string]WantedLabel
+	groups      map[string]map[string]bool
+	commits     map[string]map[string]WantedItem
+	locks       map[string]ClaimLock
+	dbOK        bool
 
 	// Error injection fields
 	EnsureDBErr         error
 	InsertWantedErr     error
 	ClaimWantedErr      error
 	SubmitCompletionErr error
+	FinalizeErr         error
+	AmendErr            error
 	QueryWantedErr      error
+	QueryBlockersErr    error
+	QueryDependentsErr  error
+	QuerySettingsErr    error
+	ListWantedErr       error
+	ExportErr           error
+	AddCommentErr       error
+	QueryDetailErr      error
+	HistoryErr          error
+	RenewClaimErr       error
+	WatchersErr         error
+	SetLabelsErr        error
+	MergeErr            error
+	ApproveErr          error
+	UnclaimErr          error
+	ReopenErr           error
+	AssignErr           error
+	RejectErr           error
+	GroupsErr           error
+	ReapErr             error
+	ReviewsErr          error
+	LockErr             error
 }
 
 func newFakeWLCommonsStore() *fakeWLCommonsStore {
 	return &fakeWLCommonsStore{
-		items: make(map[string]*WantedItem),
-		dbOK:  true,
+		items:       make(map[string]*WantedItem),
+		settings:    make(map[string]string),
+		comments:    make(map[string][]WantedComment),
+		history:     make(map[string][]WantedEvent),
+		completions: make(map[string][]WantedCompletion),
+		watchers:    make(map[string][]WantedWatcher),
+		labels:      make(map[string]map[string]WantedLabel),
+		groups:      make(map[string]map[string]bool),
+		commits:     make(map[string]map[string]WantedItem),
+		locks:       make(map[string]ClaimLock),
+		dbOK:        true,
 	}
 }
 
@@ -66,6 +112,10 @@ func (f *fakeWLCommonsStore) InsertWanted(item *WantedItem) error {
 }
 
 func (f *fakeWLCommonsStore) ClaimWanted(wantedID, rigHandle string) error {
+	return f.ClaimWantedFor(wantedID, rigHandle, "")
+}
+
+func (f *fakeWLCommonsStore) ClaimWantedFor(wantedID, rigHandle, actor string) error {
 	if f.ClaimWantedErr != nil {
 		return f.ClaimWantedErr
 	}
@@ -78,14 +128,27 @@ func (f *fakeWLCommonsStore) ClaimWanted(wantedID, rigHandle string) error {
 		return fmt.Errorf("wanted item %q not found", wantedID)
 	}
 	if item.Status != "open" {
-		return fmt.Errorf("wanted item %q is not open (status: %s)", wantedID, item.Status)
+		return fmt.Errorf("wanted item %q is %w (status: %s)", wantedID, ErrWantedNotOpen, item.Status)
 	}
 	item.Status = "claimed"
 	item.ClaimedBy = rigHandle
+	item.ClaimedVia = actor
+	item.ClaimedAt = time.Now()
+	if actor != "" {
+		f.recordEvent(wantedID, "claim", actor, "on behalf of "+rigHandle)
+	}
 	return nil
 }
 
 func (f *fakeWLCommonsStore) SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error {
+	return f.submitCompletion(completionID, wantedID, rigHandle, evidence, "in_review")
+}
+
+func (f *fakeWLCommonsStore) SubmitDraftCompletion(completionID, wantedID, rigHandle, evidence string) error {
+	return f.submitCompletion(completionID, wantedID, rigHandle, evidence, "draft")
+}
+
+func (f *fakeWLCommonsStore) submitCompletion(completionID, wantedID, rigHandle, evidence, status string) error {
 	if f.SubmitCompletionErr != nil {
 		return f.SubmitCompletionErr
 	}
@@ -100,10 +163,122 @@ func (f *fakeWLCommonsStore) SubmitCompletion(completionID, wantedID, rigHandle,
 	if item.Status != "claimed" {
 		return fmt.Errorf("wanted item %q is not claimed (status: %s)", wantedID, item.Status)
 	}
-	if item.ClaimedBy != rigHandle {
+	if !f.holdsClaim(item, rigHandle) {
 		return fmt.Errorf("wanted item %q is not claimed by %q (claimed by %q)", wantedID, rigHandle, item.ClaimedBy)
 	}
+	if idx := f.currentCompletion(wantedID); idx >= 0 {
+		return fmt.Errorf("wanted item %q already has current completion %q", wantedID, f.completions[wantedID][idx].ID)
+	}
+	if f.completionIDTaken(completionID) {
+		return fmt.Errorf("completion ID %q is already taken by another completion", completionID)
+	}
+	item.Status = status
+	item.CompletionCount++
+	item.EvidenceURL = evidence
+	f.completions[wantedID] = append(f.completions[wantedID], WantedCompletion{
+		ID:          completionID,
+		CompletedBy: rigHandle,
+		Evidence:    evidence,
+	})
+	return nil
+}
+
+// completionIDTaken reports whether any completion uses id, mirroring the
+// completions primary key. Callers must hold f.mu.
+func (f *fakeWLCommonsStore) completionIDTaken(id string) bool {
+	for _, cs := range f.completions {
+		for _, c := range cs {
+			if c.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// currentCompletion returns the index of wantedID's non-superseded
+// completion, or -1. Callers must hold f.mu.
+func (f *fakeWLCommonsStore) currentCompletion(wantedID string) int {
+	for i, c := range f.completions[wantedID] {
+		if c.SupersededBy == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeWLCommonsStore) ResubmitCompletion(completionID, supersedes, wantedID, rigHandle, evidence string, draft bool) error {
+	if f.SubmitCompletionErr != nil {
+		return f.SubmitCompletionErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	idx := f.currentCompletion(wantedID)
+	if !ok || item.Status != "claimed" || !f.holdsClaim(item, rigHandle) ||
+		idx < 0 || f.completions[wantedID][idx].ID != supersedes {
+		return fmt.Errorf("completion %q is not a current completion of %q, or the item is not claimed by %q", supersedes, wantedID, rigHandle)
+	}
+	if f.completionIDTaken(completionID) {
+		return fmt.Errorf("completion ID %q is already taken by another completion", completionID)
+	}
+	f.completions[wantedID][idx].SupersededBy = completionID
+	f.completions[wantedID] = append(f.completions[wantedID], WantedCompletion{
+		ID:          completionID,
+		CompletedBy: rigHandle,
+		Evidence:    evidence,
+	})
+	item.CompletionCount++
+	item.EvidenceURL = evidence
+	item.Status = "in_review"
+	if draft {
+		item.Status = "draft"
+	}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) FinalizeCompletion(wantedID, rigHandle, evidence string) error {
+	if f.FinalizeErr != nil {
+		return f.FinalizeErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok {
+		return fmt.Errorf("wanted item %q not found", wantedID)
+	}
+	if item.Status != "draft" || !f.holdsClaim(item, rigHandle) {
+		return fmt.Errorf("wanted item %q has no draft by %q", wantedID, rigHandle)
+	}
 	item.Status = "in_review"
+	if evidence != "" {
+		item.EvidenceURL = evidence
+	}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) AmendCompletion(wantedID, rigHandle, evidence string) error {
+	if f.AmendErr != nil {
+		return f.AmendErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	idx := f.currentCompletion(wantedID)
+	if !ok || item.Status != "in_review" || idx < 0 ||
+		f.completions[wantedID][idx].CompletedBy != rigHandle ||
+		f.completions[wantedID][idx].Evidence == evidence {
+		return fmt.Errorf("wanted item %q is not in review with a completion by %q, or the evidence is unchanged", wantedID, rigHandle)
+	}
+	f.completions[wantedID][idx].Evidence = evidence
+	item.EvidenceURL = evidence
+	f.recordEvent(wantedID, "amend", rigHandle, "evidence: "+evidence)
 	return nil
 }
 
@@ -122,3 +297,686 @@ func (f *fakeWLCommonsStore) QueryWanted(wantedID string) (*WantedItem, error) {
 	cp := *item
 	return &cp, nil
 }
+
+func (f *fakeWLCommonsStore) QueryBlockers(wantedID string) ([]*WantedItem, error) {
+	if f.QueryBlockersErr != nil {
+		return nil, f.QueryBlockersErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok {
+		return nil, nil
+	}
+	var blockers []*WantedItem
+	for _, dep := range item.DependsOn {
+		if b, ok := f.items[dep]; ok {
+			cp := *b
+			blockers = append(blockers, &cp)
+		}
+	}
+	return blockers, nil
+}
+
+func (f *fakeWLCommonsStore) QueryDependents(wantedID string) ([]*WantedItem, error) {
+	if f.QueryDependentsErr != nil {
+		return nil, f.QueryDependentsErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var dependents []*WantedItem
+	for _, item := range f.items {
+		if slices.Contains(item.DependsOn, wantedID) {
+			cp := *item
+			dependents = append(dependents, &cp)
+		}
+	}
+	sort.Slice(dependents, func(i, j int) bool { return dependents[i].ID < dependents[j].ID })
+	return dependents, nil
+}
+
+func (f *fakeWLCommonsStore) QuerySettings() (map[string]string, error) {
+	if f.QuerySettingsErr != nil {
+		return nil, f.QuerySettingsErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	settings := make(map[string]string, len(f.settings))
+	for k, v := range f.settings {
+		settings[k] = v
+	}
+	return settings, nil
+}
+
+func (f *fakeWLCommonsStore) ListWanted(filter WantedFilter) ([]*WantedItem, error) {
+	if f.ListWantedErr != nil {
+		return nil, f.ListWantedErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var items []*WantedItem
+	for _, item := range f.items {
+		if filter.Status != "" && item.Status != filter.Status {
+			continue
+		}
+		if filter.Title != "" && item.Title != filter.Title {
+			continue
+		}
+		if q := strings.ToLower(filter.Search); q != "" &&
+			!strings.Contains(strings.ToLower(item.Title), q) && !strings.Contains(strings.ToLower(item.Description), q) {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, item.Status) {
+			continue
+		}
+		if filter.HeldBy != "" && !f.holdsClaim(item, filter.HeldBy) {
+			continue
+		}
+		if filter.MinPriority >= 0 && item.Priority < filter.MinPriority {
+			continue
+		}
+		if filter.MaxPriority >= 0 && item.Priority > filter.MaxPriority {
+			continue
+		}
+		if !fakeTagsMatch(item.Tags, filter.Tags, filter.TagsMatchAll) {
+			continue
+		}
+		cp := *item
+		items = append(items, &cp)
+	}
+	// Items inserted without CreatedAt tie on it; break ties by ID for
+	// determinism.
+	sort.Slice(items, func(i, j int) bool {
+		if filter.NewestFirst {
+			if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+				return items[i].CreatedAt.After(items[j].CreatedAt)
+			}
+			return items[i].ID > items[j].ID
+		}
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority < items[j].Priority
+		}
+		return items[i].ID < items[j].ID
+	})
+	if filter.Limit > 0 && len(items) > filter.Limit {
+		items = items[:filter.Limit]
+	}
+	return items, nil
+}
+
+func (f *fakeWLCommonsStore) AddComment(wantedID, author, body string) error {
+	if f.AddCommentErr != nil {
+		return f.AddCommentErr
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment cannot be empty")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.items[wantedID]; !ok {
+		return fmt.Errorf("wanted item %q does not exist", wantedID)
+	}
+	f.comments[wantedID] = append(f.comments[wantedID], WantedComment{Author: author, Body: body})
+	f.recordEvent(wantedID, "comment", author, body)
+	return nil
+}
+
+// ExportWanted filters on UpdatedAt as stored; fake writes do not bump it.
+// ChangedSince compares against a snapshot taken with commit.
+func (f *fakeWLCommonsStore) ExportWanted(filter WantedExportFilter) ([]*WantedItem, error) {
+	if f.ExportErr != nil {
+		return nil, f.ExportErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var snap map[string]WantedItem
+	if filter.ChangedSince != "" {
+		var ok bool
+		if snap, ok = f.commits[filter.ChangedSince]; !ok {
+			return nil, fmt.Errorf("commit %q not found", filter.ChangedSince)
+		}
+	}
+	var out []*WantedItem
+	for _, item := range f.items {
+		if !filter.UpdatedAfter.IsZero() && !item.UpdatedAt.After(filter.UpdatedAfter) {
+			continue
+		}
+		if filter.Status != "" && item.Status != filter.Status {
+			continue
+		}
+		if snap != nil {
+			if old, ok := snap[item.ID]; ok && reflect.DeepEqual(old, *item) {
+				continue
+			}
+		}
+		cp := *item
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.Before(out[j].UpdatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// commit records the current items under ref, for QueryWantedAsOf.
+func (f *fakeWLCommonsStore) commit(ref string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snap := make(map[string]WantedItem, len(f.items))
+	for id, item := range f.items {
+		snap[id] = *item
+	}
+	f.commits[ref] = snap
+}
+
+func (f *fakeWLCommonsStore) QueryWantedAsOf(wantedID, ref string) (*WantedItem, error) {
+	if f.QueryDetailErr != nil {
+		return nil, f.QueryDetailErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snap, ok := f.commits[ref]
+	if !ok {
+		return nil, fmt.Errorf("commit %q not found", ref)
+	}
+	item, ok := snap[wantedID]
+	if !ok {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+func (f *fakeWLCommonsStore) QueryWantedDetail(wantedID string) (*WantedDetail, error) {
+	if f.QueryDetailErr != nil {
+		return nil, f.QueryDetailErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok {
+		return nil, fmt.Errorf("wanted item %q not found", wantedID)
+	}
+	cp := *item
+	detail := &WantedDetail{
+		Item:        &cp,
+		Comments:    append([]WantedComment(nil), f.comments[wantedID]...),
+		Completions: append([]WantedCompletion(nil), f.completions[wantedID]...),
+
+		WatcherCount: len(f.watchers[wantedID]),
+	}
+	for _, l := range f.labels[wantedID] {
+		detail.Labels = append(detail.Labels, l)
+	}
+	sort.Slice(detail.Labels, func(i, j int) bool { return detail.Labels[i].Key < detail.Labels[j].Key })
+	for _, dep := range item.DependsOn {
+		if d, ok := f.items[dep]; ok {
+			dcp := *d
+			detail.Dependencies = append(detail.Dependencies, &dcp)
+		}
+	}
+	return detail, nil
+}
+
+func (f *fakeWLCommonsStore) RenewClaim(wantedID, rigHandle string, expiresAt time.Time) error {
+	if f.RenewClaimErr != nil {
+		return f.RenewClaimErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || item.Status != "claimed" || item.ClaimedBy != rigHandle {
+		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+	}
+	item.ExpiresAt = expiresAt
+	return nil
+}
+
+func (f *fakeWLCommonsStore) AddWatcher(wantedID, rigHandle, address string) error {
+	if f.WatchersErr != nil {
+		return f.WatchersErr
+	}
+	if address == "" {
+		return fmt.Errorf("watcher address cannot be empty")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.items[wantedID]; !ok {
+		return nil // INSERT ... SELECT matches nothing, like the real store
+	}
+	for i, w := range f.watchers[wantedID] {
+		if w.RigHandle == rigHandle {
+			f.watchers[wantedID][i].Address = address
+			return nil
+		}
+	}
+	f.watchers[wantedID] = append(f.watchers[wantedID], WantedWatcher{RigHandle: rigHandle, Address: address})
+	return nil
+}
+
+func (f *fakeWLCommonsStore) RemoveWatcher(wantedID, rigHandle string) error {
+	if f.WatchersErr != nil {
+		return f.WatchersErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, w := range f.watchers[wantedID] {
+		if w.RigHandle == rigHandle {
+			f.watchers[wantedID] = append(f.watchers[wantedID][:i], f.watchers[wantedID][i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("rig %q is not subscribed to %q", rigHandle, wantedID)
+}
+
+func (f *fakeWLCommonsStore) QueryWatchers(wantedID string) ([]WantedWatcher, error) {
+	if f.WatchersErr != nil {
+		return nil, f.WatchersErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]WantedWatcher(nil), f.watchers[wantedID]...), nil
+}
+
+func (f *fakeWLCommonsStore) SetLabels(wantedID, rigHandle string, labels []WantedLabel) error {
+	if f.SetLabelsErr != nil {
+		return f.SetLabelsErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.labels[wantedID] == nil {
+		f.labels[wantedID] = make(map[string]WantedLabel)
+	}
+	for _, l := range labels {
+		l.SetBy = rigHandle
+		f.labels[wantedID][l.Key] = l
+	}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) MergeWanted(keepID string, duplicateIDs []string, rigHandle string) error {
+	if f.MergeErr != nil {
+		return f.MergeErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.items[keepID]; !ok {
+		return fmt.Errorf("wanted item %q does not exist", keepID)
+	}
+	for _, id := range duplicateIDs {
+		if id == keepID {
+			return fmt.Errorf("cannot merge %q into itself", keepID)
+		}
+	}
+	merged := 0
+	for _, id := range duplicateIDs {
+		if item, ok := f.items[id]; ok && item.Status == "open" {
+			item.Status = "withdrawn"
+			item.MergedInto = keepID
+			f.recordEvent(id, "merge", rigHandle, "merged into "+keepID)
+			merged++
+		}
+	}
+	if merged == 0 {
+		return fmt.Errorf("none of %s is open", strings.Join(duplicateIDs, ", "))
+	}
+	return nil
+}
+
+// holdsClaim mirrors claimHolderCond. Callers must hold f.mu.
+func (f *fakeWLCommonsStore) holdsClaim(item *WantedItem, rigHandle string) bool {
+	return item.ClaimedBy == rigHandle || (item.ClaimedGroup != "" && f.groups[item.ClaimedGroup][rigHandle])
+}
+
+func (f *fakeWLCommonsStore) ClaimWantedForGroup(wantedID, rigHandle, group string) error {
+	if f.ClaimWantedErr != nil {
+		return f.ClaimWantedErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || item.Status != "open" || !f.groups[group][rigHandle] {
+		return fmt.Errorf("wanted item %q is %w, or %q is not a member of group %q", wantedID, ErrWantedNotOpen, rigHandle, group)
+	}
+	item.Status = "claimed"
+	item.ClaimedBy = rigHandle
+	item.ClaimedGroup = group
+	item.ClaimedAt = time.Now()
+	return nil
+}
+
+func (f *fakeWLCommonsStore) AddGroupMembers(group, addedBy string, rigHandles []string) error {
+	if f.GroupsErr != nil {
+		return f.GroupsErr
+	}
+	if err := ValidateGroupName(group); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.groups[group] == nil {
+		f.groups[group] = make(map[string]bool)
+	}
+	for _, rig := range rigHandles {
+		f.groups[group][rig] = true
+	}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) QueryGroupMembers(group string) ([]string, error) {
+	if f.GroupsErr != nil {
+		return nil, f.GroupsErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var members []string
+	for rig := range f.groups[group] {
+		members = append(members, rig)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// fakeTagsMatch mirrors TagCondition: have must include any of want, or all
+// of them when all is set.
+func fakeTagsMatch(have, want []string, all bool) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if slices.Contains(have, w) != all {
+			return !all
+		}
+	}
+	return all
+}
+
+func (f *fakeWLCommonsStore) ApproveCompletions(wantedIDs []string, reviewer string) error {
+	if f.ApproveErr != nil {
+		return f.ApproveErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(wantedIDs) == 0 {
+		return fmt.Errorf("no items to approve")
+	}
+	for _, id := range wantedIDs {
+		if item, ok := f.items[id]; !ok || item.Status != "in_review" {
+			return fmt.Errorf("not every one of %s is still in review; nothing was approved", strings.Join(wantedIDs, ", "))
+		}
+	}
+	for _, id := range wantedIDs {
+		f.items[id].Status = "completed"
+		f.recordEvent(id, "approve", reviewer, "")
+		for i := range f.completions[id] {
+			if f.completions[id][i].SupersededBy == "" {
+				f.completions[id][i].ValidatedBy = reviewer
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) UnclaimWanted(wantedID, rigHandle string, force bool) error {
+	if f.UnclaimErr != nil {
+		return f.UnclaimErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || item.Status != "claimed" {
+		return fmt.Errorf("wanted item %q is not claimed or does not exist", wantedID)
+	}
+	if !force && !f.holdsClaim(item, rigHandle) {
+		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+	}
+	detail := ""
+	if force {
+		detail = "forced"
+	}
+	f.recordEvent(wantedID, "unclaim", rigHandle, detail)
+	item.Status = "open"
+	item.ClaimedBy = ""
+	item.ClaimedVia = ""
+	item.ClaimedGroup = ""
+	item.Assignee = ""
+	item.ExpiresAt = time.Time{}
+	item.ClaimedAt = time.Time{}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) RejectCompletion(wantedID, reviewer, reason string) error {
+	if f.RejectErr != nil {
+		return f.RejectErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || item.Status != "in_review" {
+		return fmt.Errorf("wanted item %q is not in review or does not exist", wantedID)
+	}
+	item.Status = "claimed"
+	item.ClaimedAt = time.Now()
+	f.comments[wantedID] = append(f.comments[wantedID], WantedComment{Author: reviewer, Body: "rejected: " + reason})
+	f.recordEvent(wantedID, "reject", reviewer, "rejected: "+reason)
+	return nil
+}
+
+// isStaleClaim mirrors staleClaimCond.
+func (f *fakeWLCommonsStore) isStaleClaim(item *WantedItem, claimedBefore time.Time) bool {
+	return item.Status == "claimed" && !item.ClaimedAt.IsZero() && item.ClaimedAt.Before(claimedBefore) &&
+		(item.ExpiresAt.IsZero() || item.ExpiresAt.Before(time.Now()))
+}
+
+func (f *fakeWLCommonsStore) QueryStaleClaims(claimedBefore time.Time) ([]*WantedItem, error) {
+	if f.ReapErr != nil {
+		return nil, f.ReapErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stale []*WantedItem
+	for _, item := range f.items {
+		if f.isStaleClaim(item, claimedBefore) {
+			cp := *item
+			stale = append(stale, &cp)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].ClaimedAt.Before(stale[j].ClaimedAt) })
+	return stale, nil
+}
+
+func (f *fakeWLCommonsStore) ReapClaim(wantedID string, claimedBefore time.Time, actor string) error {
+	if f.ReapErr != nil {
+		return f.ReapErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || !f.isStaleClaim(item, claimedBefore) {
+		return fmt.Errorf("wanted item %q no longer has a stale claim", wantedID)
+	}
+	f.recordEvent(wantedID, "reap", actor, "stale claim by "+item.ClaimedBy)
+	item.Status = "open"
+	item.ClaimedBy = ""
+	item.ClaimedVia = ""
+	item.ClaimedGroup = ""
+	item.Assignee = ""
+	item.ExpiresAt = time.Time{}
+	item.ClaimedAt = time.Time{}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) ReopenWanted(wantedID, actor string, force bool) error {
+	if f.ReopenErr != nil {
+		return f.ReopenErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || (item.Status != "in_review" && item.Status != "completed" && item.Status != "withdrawn") {
+		return fmt.Errorf("wanted item %q is not in review, completed, or withdrawn, or does not exist", wantedID)
+	}
+	if !force && item.PostedBy != actor {
+		return fmt.Errorf("wanted item %q is not reopenable by %q", wantedID, actor)
+	}
+	detail := ""
+	if force {
+		detail = "forced"
+	}
+	f.recordEvent(wantedID, "reopen", actor, detail)
+	item.Status = "open"
+	item.ClaimedBy = ""
+	item.ClaimedVia = ""
+	item.ClaimedGroup = ""
+	item.Assignee = ""
+	item.ExpiresAt = time.Time{}
+	item.ClaimedAt = time.Time{}
+	item.ReopenedBy = actor
+	item.ReopenedAt = time.Now()
+	for i := range f.completions[wantedID] {
+		if f.completions[wantedID][i].SupersededBy == "" {
+			f.completions[wantedID][i].SupersededBy = CompletionSupersededByReopen
+		}
+	}
+	return nil
+}
+
+// recordEvent appends a wanted_history event, as the real store's scripts
+// do. Callers must hold f.mu.
+func (f *fakeWLCommonsStore) AssignWanted(wantedID, rigHandle, assignee string) error {
+	if f.AssignErr != nil {
+		return f.AssignErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[wantedID]
+	if !ok || (item.Status != "claimed" && item.Status != "draft" && item.Status != "in_review") || !f.holdsClaim(item, rigHandle) {
+		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+	}
+	f.recordEvent(wantedID, "assign", rigHandle, assignee)
+	item.Assignee = assignee
+	return nil
+}
+
+func (f *fakeWLCommonsStore) recordEvent(wantedID, action, actor, detail string) {
+	f.history[wantedID] = append(f.history[wantedID], WantedEvent{Action: action, Actor: actor, Detail: detail, At: time.Now()})
+}
+
+func (f *fakeWLCommonsStore) QueryWantedHistory(wantedID string) ([]WantedEvent, error) {
+	if f.HistoryErr != nil {
+		return nil, f.HistoryErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]WantedEvent(nil), f.history[wantedID]...), nil
+}
+
+func (f *fakeWLCommonsStore) QueryPendingReviews(postedBy string) ([]PendingReview, error) {
+	if f.ReviewsErr != nil {
+		return nil, f.ReviewsErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var reviews []PendingReview
+	for id, item := range f.items {
+		if item.Status != "in_review" || (postedBy != "" && item.PostedBy != postedBy) {
+			continue
+		}
+		for _, c := range f.completions[id] {
+			if c.SupersededBy == "" && c.ValidatedBy == "" {
+				reviews = append(reviews, PendingReview{WantedID: id, Title: item.Title, Priority: item.Priority, PostedBy: item.PostedBy, Completion: c})
+			}
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if reviews[i].Completion.CompletedAt != reviews[j].Completion.CompletedAt {
+			return reviews[i].Completion.CompletedAt < reviews[j].Completion.CompletedAt
+		}
+		return reviews[i].WantedID < reviews[j].WantedID
+	})
+	return reviews, nil
+}
+
+func (f *fakeWLCommonsStore) AcquireClaimLock(wantedID, rigHandle string, ttl time.Duration) error {
+	if f.LockErr != nil {
+		return f.LockErr
+	}
+	if ttl <= 0 {
+		ttl = DefaultClaimLockTTL
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if lock, ok := f.locks[wantedID]; ok && lock.LockedBy != rigHandle && now.Before(lock.ExpiresAt) {
+		return &ClaimLockHeldError{Lock: lock}
+	}
+	f.locks[wantedID] = ClaimLock{WantedID: wantedID, LockedBy: rigHandle, ExpiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (f *fakeWLCommonsStore) ReleaseClaimLock(wantedID, rigHandle string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if lock, ok := f.locks[wantedID]; ok && lock.LockedBy == rigHandle {
+		delete(f.locks, wantedID)
+	}
+	return nil
+}
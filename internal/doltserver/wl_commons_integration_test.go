@@ -74,3 +74,49 @@ CALL DOLT_COMMIT('-m', 'noop');
 		t.Errorf("isNothingToCommit(%q) = false, want true — Dolt error text may have changed", err)
 	}
 }
+
+// TestScriptTransaction_RealDoltRollback verifies that a failing statement
+// discards the earlier writes of the same transaction, which the
+// START TRANSACTION scripts rely on.
+func TestScriptTransaction_RealDoltRollback(t *testing.T) {
+	requireDoltServer(t)
+	townRoot := setupTestTown(t)
+	if err := EnsureWLCommons(townRoot); err != nil {
+		t.Fatalf("EnsureWLCommons() error: %v", err)
+	}
+
+	err := doltSQLScript(townRoot, "USE "+WLCommonsDB+`;
+START TRANSACTION;
+INSERT INTO wanted (id, title) VALUES ('w-tx', 'first');
+INSERT INTO wanted (id, title) VALUES ('w-tx', 'duplicate');
+COMMIT;
+`)
+	if err == nil {
+		t.Fatal("doltSQLScript() expected duplicate key error")
+	}
+	if _, err := QueryWanted(townRoot, "w-tx"); err == nil {
+		t.Error("first insert should have been rolled back")
+	}
+}
+
+// TestExecTx_RealDoltRollback verifies that a failing statement discards the
+// earlier statements of the same batch.
+func TestExecTx_RealDoltRollback(t *testing.T) {
+	requireDoltServer(t)
+	townRoot := setupTestTown(t)
+	if err := EnsureWLCommons(townRoot); err != nil {
+		t.Fatalf("EnsureWLCommons() error: %v", err)
+	}
+	config := DefaultConfig(townRoot)
+
+	_, err := ExecTx(t.Context(), config, WLCommonsDB, "", []string{
+		"INSERT INTO wanted (id, title) VALUES ('w-tx', 'first')",
+		"INSERT INTO wanted (id, title) VALUES ('w-tx', 'duplicate')",
+	})
+	if err == nil {
+		t.Fatal("ExecTx() expected duplicate key error")
+	}
+	if _, err := QueryWanted(townRoot, "w-tx"); err == nil {
+		t.Error("first insert should have been rolled back")
+	}
+}
@@ -0,0 +1,163 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// fakeDoltResult is the canned output of one dolt invocation.
+type fakeDoltResult struct {
+	output string
+	err    error
+}
+
+// fakeDoltRunner stubs runDoltCmd, recording the SQL of each invocation
+// (the -q query or the --file script) and answering with results in order.
+// Once results run out, every further call repeats the last one.
+type fakeDoltRunner struct {
+	sql     []string
+	results []fakeDoltResult
+}
+
+func installFakeDoltRunner(t *testing.T, results ...fakeDoltResult) *fakeDoltRunner {
+	t.Helper()
+	t.Setenv("GT_DOLT_HOST", "")
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+
+	f := &fakeDoltRunner{results: results}
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		f.sql = append(f.sql, invokedSQL(t, cmd.Args))
+		if len(f.results) == 0 {
+			return nil, nil
+		}
+		res := f.results[0]
+		if len(f.results) > 1 {
+			f.results = f.results[1:]
+		}
+		return []byte(res.output), res.err
+	}
+	return f
+}
+
+func invokedSQL(t *testing.T, args []string) string {
+	t.Helper()
+	for i := 0; i+1 < len(args); i++ {
+		switch args[i] {
+		case "-q":
+			return args[i+1]
+		case "--file":
+			data, err := os.ReadFile(args[i+1])
+			if err != nil {
+				t.Fatalf("reading dolt script: %v", err)
+			}
+			return string(data)
+		}
+	}
+	return ""
+}
+
+func TestClaimWanted_Runner(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		result  fakeDoltResult
+		wantErr string
+	}{
+		{name: "claimed"},
+		{
+			name:    "not open",
+			result:  fakeDoltResult{"error: nothing to commit", fmt.Errorf("exit status 1")},
+			wantErr: `wanted item "w-1" is not open or does not exist`,
+		},
+		{
+			name:    "dolt failure",
+			result:  fakeDoltResult{"table not found: wanted", fmt.Errorf("exit status 1")},
+			wantErr: "claim failed: exit status 1 (output: table not found: wanted)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := installFakeDoltRunner(t, tc.result)
+
+			err := ClaimWanted(t.TempDir(), "w-1", "rig-o'brien")
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("ClaimWanted() error: %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("ClaimWanted() error = %v, want %q", err, tc.wantErr)
+			}
+			if len(runner.sql) != 1 {
+				t.Fatalf("dolt ran %d times, want 1", len(runner.sql))
+			}
+			if runner.sql[0] != ClaimWantedScript("w-1", "rig-o'brien") {
+				t.Errorf("script =\n%s\nwant ClaimWantedScript", runner.sql[0])
+			}
+			if !strings.Contains(runner.sql[0], "claimed_by='rig-o''brien'") {
+				t.Errorf("script does not escape the rig handle:\n%s", runner.sql[0])
+			}
+		})
+	}
+}
+
+func TestSubmitCompletion_Runner(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		result  fakeDoltResult
+		wantErr string
+	}{
+		{name: "submitted"},
+		{
+			name:    "not claimed",
+			result:  fakeDoltResult{"error: nothing to commit", fmt.Errorf("exit status 1")},
+			wantErr: `wanted item "w-1" is not claimed by "rig", already has a current completion, or does not exist`,
+		},
+		{
+			name:    "completion ID taken",
+			result:  fakeDoltResult{"duplicate primary key given: [c-1]", fmt.Errorf("exit status 1")},
+			wantErr: completionIDTakenError("c-1").Error(),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := installFakeDoltRunner(t, tc.result)
+
+			err := SubmitCompletion(t.TempDir(), "c-1", "w-1", "rig", "https://example.com/pr/1")
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("SubmitCompletion() error: %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("SubmitCompletion() error = %v, want %q", err, tc.wantErr)
+			}
+			if len(runner.sql) != 1 {
+				t.Fatalf("dolt ran %d times, want 1", len(runner.sql))
+			}
+			want := SubmitCompletionScript("c-1", "w-1", "rig", "https://example.com/pr/1", StatusInReview)
+			if runner.sql[0] != want {
+				t.Errorf("script =\n%s\nwant\n%s", runner.sql[0], want)
+			}
+		})
+	}
+}
+
+func TestQueryWanted_Runner(t *testing.T) {
+	runner := installFakeDoltRunner(t, fakeDoltResult{
+		output: "id,title,status,priority,claimed_by,claimed_group\nw-1,\"Fix it, properly\",claimed,1,rig,\n",
+	})
+
+	item, err := QueryWanted(t.TempDir(), "w-1")
+	if err != nil {
+		t.Fatalf("QueryWanted() error: %v", err)
+	}
+	if item.Title != "Fix it, properly" || item.Status != "claimed" || item.Priority != 1 || item.ClaimedBy != "rig" {
+		t.Errorf("QueryWanted() = %+v", item)
+	}
+	if len(runner.sql) != 1 || !strings.Contains(runner.sql[0], "WHERE id='w-1'") {
+		t.Errorf("queries = %q, want one selecting w-1", runner.sql)
+	}
+
+	installFakeDoltRunner(t, fakeDoltResult{output: "id,title,status,priority,claimed_by,claimed_group\n"})
+	if _, err := QueryWanted(t.TempDir(), "w-2"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("QueryWanted(missing) error = %v, want not found", err)
+	}
+}
@@ -3,6 +3,7 @@ package doltserver
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseSimpleCSV_Empty(t *testing.T) {
@@ -134,21 +135,66 @@ func TestEscapeSQL_Backslashes(t *testing.T) {
 	}
 }
 
-func TestGenerateWantedID_Format(t *testing.T) {
-	t.Parallel()
-	id := GenerateWantedID("Test Title")
-	if !strings.HasPrefix(id, "w-") {
-		t.Errorf("GenerateWantedID() = %q, want prefix 'w-'", id)
+// TestSetWLCommonsDB is not parallel: it changes the active database that
+// the parallel script tests read.
+func TestSetWLCommonsDB(t *testing.T) {
+	t.Cleanup(func() { WLCommonsDB = DefaultWLCommonsDB })
+
+	for _, bad := range []string{"", "wl-commons", "1db", "db; DROP TABLE wanted", "`db`"} {
+		if err := SetWLCommonsDB(bad); err == nil {
+			t.Errorf("SetWLCommonsDB(%q) = nil, want error", bad)
+		}
+	}
+	if WLCommonsDB != DefaultWLCommonsDB {
+		t.Fatalf("WLCommonsDB = %q after rejected names, want %q", WLCommonsDB, DefaultWLCommonsDB)
+	}
+
+	if err := SetWLCommonsDB("wl_other"); err != nil {
+		t.Fatalf("SetWLCommonsDB: %v", err)
+	}
+	if script := ClaimWantedScript("w-1", "rig"); !strings.HasPrefix(script, "USE wl_other;") {
+		t.Errorf("claim script does not use wl_other:\n%s", script)
+	}
+}
+
+func TestSetWLDryRun(t *testing.T) {
+	var buf strings.Builder
+	SetWLDryRun(&buf)
+	t.Cleanup(func() { SetWLDryRun(nil) })
+
+	if !WLDryRun() {
+		t.Fatal("WLDryRun() = false after SetWLDryRun")
 	}
-	// "w-" + 10 hex chars = 12 chars total
-	if len(id) != 12 {
-		t.Errorf("GenerateWantedID() length = %d, want 12", len(id))
+	if err := ClaimWanted(t.TempDir(), "w-1", "rig"); err != nil {
+		t.Fatalf("ClaimWanted() under dry run error: %v", err)
 	}
-	// Verify hex chars after prefix
-	hexPart := id[2:]
-	for _, c := range hexPart {
-		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
-			t.Errorf("GenerateWantedID() contains non-hex char %q in %q", string(c), id)
+	if want := ClaimWantedScript("w-1", "rig"); !strings.Contains(buf.String(), want) {
+		t.Errorf("dry run printed:\n%s\nwant the claim script:\n%s", buf.String(), want)
+	}
+
+	SetWLDryRun(nil)
+	if WLDryRun() {
+		t.Error("WLDryRun() = true after SetWLDryRun(nil)")
+	}
+}
+
+func TestGenerateWantedID_Format(t *testing.T) {
+	t.Parallel()
+	for _, prefix := range []string{"w", "task"} {
+		id := GenerateWantedID(prefix, "Test Title")
+		if !strings.HasPrefix(id, prefix+"-") {
+			t.Errorf("GenerateWantedID(%q) = %q, want prefix %q", prefix, id, prefix+"-")
+		}
+		// prefix + "-" + 10 hex chars
+		if want := len(prefix) + 11; len(id) != want {
+			t.Errorf("GenerateWantedID(%q) length = %d, want %d", prefix, len(id), want)
+		}
+		// Verify hex chars after prefix
+		hexPart := id[len(prefix)+1:]
+		for _, c := range hexPart {
+			if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
+				t.Errorf("GenerateWantedID(%q) contains non-hex char %q in %q", prefix, string(c), id)
+			}
 		}
 	}
 }
@@ -157,10 +203,447 @@ func TestGenerateWantedID_Uniqueness(t *testing.T) {
 	t.Parallel()
 	seen := make(map[string]bool)
 	for i := 0; i < 100; i++ {
-		id := GenerateWantedID("Same Title")
+		id := GenerateWantedID("w", "Same Title")
 		if seen[id] {
 			t.Fatalf("duplicate ID generated: %s", id)
 		}
 		seen[id] = true
 	}
 }
+
+func TestBuildListWantedQuery_PriorityBounds(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		filter WantedFilter
+		want   string
+	}{
+		{"unbounded", WantedFilter{Status: "open", MinPriority: -1, MaxPriority: -1}, "WHERE status = 'open' ORDER BY"},
+		{"min only", WantedFilter{Status: "open", MinPriority: 1, MaxPriority: -1}, "WHERE status = 'open' AND priority >= 1 ORDER BY"},
+		{"max only", WantedFilter{MinPriority: -1, MaxPriority: 2}, "WHERE priority <= 2 ORDER BY"},
+		{"both", WantedFilter{Status: "open", MinPriority: 1, MaxPriority: 3}, "WHERE status = 'open' AND priority >= 1 AND priority <= 3 ORDER BY"},
+		{"title", WantedFilter{Status: "open", Title: "Bob's bug", MinPriority: -1, MaxPriority: -1}, "WHERE status = 'open' AND title = 'Bob''s bug' ORDER BY"},
+		{"statuses", WantedFilter{Statuses: []string{"claimed", "in_review"}, MinPriority: -1, MaxPriority: -1}, "WHERE status IN ('claimed', 'in_review') ORDER BY"},
+		{"held by", WantedFilter{HeldBy: "bob's-rig", MinPriority: -1, MaxPriority: -1}, "WHERE (claimed_by='bob''s-rig' OR claimed_group IN (SELECT group_name FROM group_members WHERE rig_handle='bob''s-rig')) ORDER BY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildListWantedQuery(tt.filter)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("buildListWantedQuery(%+v) = %q, want substring %q", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildListWantedQuery_Tags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		filter WantedFilter
+		want   string
+	}{
+		{"single", WantedFilter{Status: "open", MinPriority: -1, MaxPriority: -1, Tags: []string{"go"}},
+			`WHERE status = 'open' AND JSON_CONTAINS(tags, '"go"') ORDER BY`},
+		{"any", WantedFilter{Status: "open", MinPriority: -1, MaxPriority: -1, Tags: []string{"go", "sql"}},
+			`WHERE status = 'open' AND (JSON_CONTAINS(tags, '"go"') OR JSON_CONTAINS(tags, '"sql"')) ORDER BY`},
+		{"all", WantedFilter{Status: "open", MinPriority: -1, MaxPriority: -1, Tags: []string{"go", "sql"}, TagsMatchAll: true},
+			`WHERE status = 'open' AND (JSON_CONTAINS(tags, '"go"') AND JSON_CONTAINS(tags, '"sql"')) ORDER BY`},
+		{"escaped", WantedFilter{MinPriority: -1, MaxPriority: -1, Tags: []string{`it's "x"`}},
+			`WHERE JSON_CONTAINS(tags, '"it''s \\"x\\""') ORDER BY`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildListWantedQuery(tt.filter)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("buildListWantedQuery(%+v) = %q, want substring %q", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildListWantedQuery_NewestFirst(t *testing.T) {
+	t.Parallel()
+	got := buildListWantedQuery(WantedFilter{Status: "open", MinPriority: -1, MaxPriority: -1, Tags: []string{"go"}, NewestFirst: true})
+	want := `WHERE status = 'open' AND JSON_CONTAINS(tags, '"go"') ORDER BY created_at DESC, id DESC;`
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("buildListWantedQuery() = %q, want suffix %q", got, want)
+	}
+	if strings.Contains(got, "priority ASC") {
+		t.Errorf("buildListWantedQuery() = %q, newest-first must not order by priority", got)
+	}
+
+	byPriority := buildListWantedQuery(WantedFilter{Status: "open", MinPriority: -1, MaxPriority: -1})
+	if !strings.HasSuffix(byPriority, "ORDER BY priority ASC, created_at ASC;") {
+		t.Errorf("buildListWantedQuery() default = %q, want priority order", byPriority)
+	}
+}
+
+func TestBuildListWantedQuery_Search(t *testing.T) {
+	t.Parallel()
+	got := buildListWantedQuery(WantedFilter{MinPriority: -1, MaxPriority: -1, Search: `50%_Off!\'s`})
+	pattern := `'%50!%!_off!!\\''s%' ESCAPE '!'`
+	want := "WHERE (LOWER(title) LIKE " + pattern + " OR LOWER(COALESCE(description, '')) LIKE " + pattern + ")"
+	if !strings.Contains(got, want) {
+		t.Errorf("buildListWantedQuery() = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestEscapeSQLLike(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"50%", "50!%"},
+		{"snake_case", "snake!_case"},
+		{"wow!", "wow!!"},
+		{"!%_", "!!!%!_"},
+		// Quotes and backslashes are EscapeSQL's job, not LIKE's.
+		{`it's a\path`, `it's a\path`},
+	}
+	for _, tt := range tests {
+		if got := escapeSQLLike(tt.in); got != tt.want {
+			t.Errorf("escapeSQLLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidTimeoutAction(t *testing.T) {
+	t.Parallel()
+	for _, action := range []string{TimeoutActionReopen, TimeoutActionNotify, TimeoutActionEscalate} {
+		if !ValidTimeoutAction(action) {
+			t.Errorf("ValidTimeoutAction(%q) = false, want true", action)
+		}
+	}
+	for _, action := range []string{"", "REOPEN", "delete"} {
+		if ValidTimeoutAction(action) {
+			t.Errorf("ValidTimeoutAction(%q) = true, want false", action)
+		}
+	}
+}
+
+func TestWLCommitMessage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		action  string
+		id, rig string
+		details []string
+		want    string
+	}{
+		{"claim", "claim", "w-abc123", "town-x", nil, "wl claim: w-abc123 by town-x"},
+		{"delegated claim", "claim", "w-abc123", "partner", []string{"via coord"}, "wl claim: w-abc123 by partner (via coord)"},
+		{"multiple details", "done", "w-abc123", "town-x", []string{"c-1", "supersedes c-0"}, "wl done: w-abc123 by town-x (c-1; supersedes c-0)"},
+		{"empty parts omitted", "post", "w-abc123", "", []string{"", ""}, "wl post: w-abc123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wlCommitMessage(tt.action, tt.id, tt.rig, tt.details...); got != tt.want {
+				t.Errorf("wlCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimWantedScript_CommitMessage(t *testing.T) {
+	t.Parallel()
+	script := ClaimWantedScript("w-abc123", "town-x")
+	if !strings.Contains(script, "CALL DOLT_COMMIT('-m', 'wl claim: w-abc123 by town-x');") {
+		t.Errorf("claim script commit message wrong:\n%s", script)
+	}
+	// Messages are SQL-escaped along with the rest of the script.
+	script = ClaimWantedForScript("w-abc123", "o'brien", "coord")
+	if !strings.Contains(script, "'wl claim: w-abc123 by o''brien (via coord)'") {
+		t.Errorf("delegated claim commit message not escaped:\n%s", script)
+	}
+}
+
+func TestMergeWantedScript(t *testing.T) {
+	t.Parallel()
+	script := MergeWantedScript("w-keep", []string{"w-dup1", "w-o'dup"}, "curator")
+	for _, want := range []string{
+		"SET @keep = (SELECT COUNT(*) FROM wanted WHERE id='w-keep');",
+		"merged_into='w-keep'",
+		"WHERE id='w-o''dup' AND status='open' AND @keep > 0;",
+		"'w-dup1', 'merge', 'curator', 'merged into w-keep'",
+		"CALL DOLT_COMMIT('-m', 'wl merge: w-keep by curator (duplicates: w-dup1, w-o''dup)');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("merge script missing %q:\n%s", want, script)
+		}
+	}
+	if n := strings.Count(script, "INSERT INTO wanted_history"); n != 2 {
+		t.Errorf("merge script has %d history inserts, want 2", n)
+	}
+	if err := MergeWanted(t.TempDir(), "w-keep", []string{"w-keep"}, "curator"); err == nil {
+		t.Error("MergeWanted() should refuse to merge an item into itself")
+	}
+}
+
+func TestApproveCompletionsScript(t *testing.T) {
+	t.Parallel()
+	script := ApproveCompletionsScript([]string{"w-1", "w-o'2"}, "poster")
+	for _, want := range []string{
+		"START TRANSACTION;",
+		"SET @ready = (SELECT COUNT(*) FROM wanted WHERE id IN ('w-1', 'w-o''2') AND status='in_review');",
+		"validated_by='poster', validated_at=NOW()",
+		"status='completed', updated_at=NOW()",
+		"AND @ready = 2;",
+		"CALL DOLT_COMMIT('-m', 'wl approve: w-1, w-o''2 by poster');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("approve script missing %q:\n%s", want, script)
+		}
+	}
+	if n := strings.Count(script, "INSERT INTO wanted_history"); n != 2 {
+		t.Errorf("approve script has %d history inserts, want 2", n)
+	}
+}
+
+func TestUnclaimWantedScript(t *testing.T) {
+	t.Parallel()
+	script := UnclaimWantedScript("w-abc", "my-rig", false)
+	for _, want := range []string{
+		"SET assignee=NULL, claimed_by=NULL, claimed_via=NULL, claimed_group=NULL, expires_at=NULL, claimed_at=NULL, status='open'",
+		"WHERE id='w-abc' AND status='claimed' AND (claimed_by='my-rig' OR claimed_group IN",
+		"'unclaim', 'my-rig', NULL, NOW() FROM dual WHERE @released > 0;",
+		"CALL DOLT_COMMIT('-m', 'wl unclaim: w-abc by my-rig');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("unclaim script missing %q:\n%s", want, script)
+		}
+	}
+
+	forced := UnclaimWantedScript("w-abc", "janitor", true)
+	if strings.Contains(forced, "claimed_by='janitor'") {
+		t.Errorf("forced unclaim should not require the claim holder:\n%s", forced)
+	}
+	if !strings.Contains(forced, "'wl unclaim: w-abc by janitor (forced)'") {
+		t.Errorf("forced unclaim commit message missing:\n%s", forced)
+	}
+}
+
+func TestRejectCompletionScript(t *testing.T) {
+	t.Parallel()
+	script := RejectCompletionScript("w-abc", "poster", `needs tests; see C:\ci -- logs`)
+	for _, want := range []string{
+		"UPDATE wanted SET status='claimed', claimed_at=NOW(), updated_at=NOW()\n  WHERE id='w-abc' AND status='in_review';",
+		`'reject', 'poster', 'rejected: needs tests; see C:\\ci -- logs', NOW() FROM dual WHERE @rejected > 0;`,
+		"CALL DOLT_COMMIT('-m', 'wl reject: w-abc by poster');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("reject script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestReapClaimScript(t *testing.T) {
+	t.Parallel()
+	cutoff := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	script := ReapClaimScript("w-abc", cutoff, "janitor")
+	for _, want := range []string{
+		"SET @holder = (SELECT claimed_by FROM wanted WHERE id='w-abc');",
+		"expires_at=NULL, claimed_at=NULL, status='open'",
+		"WHERE id='w-abc' AND status='claimed' AND claimed_at IS NOT NULL AND claimed_at < '2026-03-01 09:00:00' AND (expires_at IS NULL OR expires_at < NOW());",
+		"'reap', 'janitor', CONCAT('stale claim by ', @holder), NOW() FROM dual WHERE @reaped > 0;",
+		"CALL DOLT_COMMIT('-m', 'wl reap: w-abc by janitor');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("reap script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestFinalizeCompletionScript(t *testing.T) {
+	t.Parallel()
+	script := FinalizeCompletionScript("w-abc", "my-rig", "https://example.com/2")
+	finalize := strings.Index(script, "UPDATE wanted SET status='in_review', evidence_url='https://example.com/2'")
+	evidence := strings.Index(script, "UPDATE completions SET evidence='https://example.com/2'\n  WHERE wanted_id='w-abc' AND completed_by='my-rig' AND superseded_by IS NULL AND @finalized > 0;")
+	if finalize < 0 || evidence < 0 || evidence < finalize {
+		t.Errorf("finalize script should move the item before rewriting current evidence:\n%s", script)
+	}
+	if !strings.Contains(script, "SET @finalized = ROW_COUNT();") {
+		t.Errorf("finalize script should gate the evidence write on the item moving:\n%s", script)
+	}
+
+	if kept := FinalizeCompletionScript("w-abc", "my-rig", ""); strings.Contains(kept, "UPDATE completions") {
+		t.Errorf("finalize without evidence should keep the draft's evidence:\n%s", kept)
+	}
+}
+
+// Multi-statement claim and done writes run in one SQL transaction, so a
+// failure part-way leaves nothing behind: COMMIT must precede DOLT_ADD.
+func TestClaimAndDoneScriptsAreTransactional(t *testing.T) {
+	t.Parallel()
+	scripts := map[string]string{
+		"delegated claim": ClaimWantedForScript("w-abc", "my-rig", "coord"),
+		"done":            SubmitCompletionScript("c-1", "w-abc", "my-rig", "https://example.com", StatusInReview),
+		"done --draft":    SubmitCompletionScript("c-1", "w-abc", "my-rig", "https://example.com", StatusDraft),
+		"amend":           AmendCompletionScript("w-abc", "my-rig", "https://example.com/2"),
+		"done --final":    FinalizeCompletionScript("w-abc", "my-rig", "https://example.com/2"),
+		"reopen":          ReopenWantedScript("w-abc", "poster", false),
+		"assign":          AssignWantedScript("w-abc", "my-rig", "polecat-1"),
+	}
+	for name, script := range scripts {
+		_, body, _ := strings.Cut(script, "\n")
+		commit := strings.Index(body, "\nCOMMIT;\n")
+		if !strings.HasPrefix(body, "START TRANSACTION;\n") || commit < 0 || strings.Index(body, "CALL DOLT_ADD") < commit {
+			t.Errorf("%s script is not wrapped in a transaction ahead of the Dolt commit:\n%s", name, script)
+		}
+	}
+}
+
+func TestReopenWantedScript(t *testing.T) {
+	t.Parallel()
+	script := ReopenWantedScript("w-abc", "poster", false)
+	for _, want := range []string{
+		"claimed_at=NULL, status='open', reopened_by='poster', reopened_at=NOW()",
+		"WHERE id='w-abc' AND status IN ('in_review', 'completed', 'withdrawn') AND posted_by='poster';",
+		"UPDATE completions SET superseded_by='reopen'\n  WHERE wanted_id='w-abc' AND superseded_by IS NULL AND @reopened > 0;",
+		"'reopen', 'poster', NULL, NOW() FROM dual WHERE @reopened > 0;",
+		"CALL DOLT_COMMIT('-m', 'wl reopen: w-abc by poster');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("reopen script missing %q:\n%s", want, script)
+		}
+	}
+
+	forced := ReopenWantedScript("w-abc", "janitor", true)
+	if strings.Contains(forced, "posted_by=") {
+		t.Errorf("forced reopen should not require the poster:\n%s", forced)
+	}
+	if !strings.Contains(forced, "'wl reopen: w-abc by janitor (forced)'") {
+		t.Errorf("forced reopen commit message missing:\n%s", forced)
+	}
+}
+
+func TestAssignWantedScript(t *testing.T) {
+	t.Parallel()
+	script := AssignWantedScript("w-abc", "my-rig", "polecat-1")
+	for _, want := range []string{
+		"UPDATE wanted SET assignee='polecat-1', updated_at=NOW()",
+		"WHERE id='w-abc' AND status IN ('claimed', 'draft', 'in_review') AND (claimed_by='my-rig' OR claimed_group IN",
+		"'assign', 'my-rig', 'polecat-1', NOW() FROM dual WHERE @assigned > 0;",
+		"CALL DOLT_COMMIT('-m', 'wl assign: w-abc by my-rig (polecat-1)');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("assign script missing %q:\n%s", want, script)
+		}
+	}
+
+	cleared := AssignWantedScript("w-abc", "my-rig", "")
+	if !strings.Contains(cleared, "SET assignee=NULL,") || !strings.Contains(cleared, "'assign', 'my-rig', NULL, NOW()") {
+		t.Errorf("clearing the assignee should write NULL:\n%s", cleared)
+	}
+}
+
+func TestClaimWantedForGroupScript(t *testing.T) {
+	t.Parallel()
+	script := ClaimWantedForGroupScript("w-abc", "my-rig", "auth-squad")
+	for _, want := range []string{
+		"claimed_by='my-rig', claimed_group='auth-squad', status='claimed'",
+		"EXISTS (SELECT 1 FROM group_members WHERE group_name='auth-squad' AND rig_handle='my-rig')",
+		"CALL DOLT_COMMIT('-m', 'wl claim: w-abc by my-rig (group auth-squad)');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("group claim script missing %q:\n%s", want, script)
+		}
+	}
+	for _, name := range []string{"auth-squad", "a", "team.core_2"} {
+		if err := ValidateGroupName(name); err != nil {
+			t.Errorf("ValidateGroupName(%q) error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Auth", "1team", "a b", "x'y"} {
+		if err := ValidateGroupName(name); err == nil {
+			t.Errorf("ValidateGroupName(%q) should fail", name)
+		}
+	}
+}
+
+func TestAmendCompletionScript(t *testing.T) {
+	t.Parallel()
+	script := AmendCompletionScript("w-abc", "my-rig", "https://example.com/pr/2")
+	for _, want := range []string{
+		"UPDATE completions SET evidence='https://example.com/pr/2'",
+		"WHERE wanted_id='w-abc' AND completed_by='my-rig' AND superseded_by IS NULL",
+		"status='in_review'",
+		"evidence_url='https://example.com/pr/2', updated_at=NOW() WHERE id='w-abc' AND @amended > 0;",
+		"'w-abc', 'amend', 'my-rig', 'evidence: https://example.com/pr/2'",
+		"CALL DOLT_COMMIT('-m', 'wl done --amend: w-abc by my-rig');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("amend script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestAmendCompletionScript_MultilineEvidence(t *testing.T) {
+	t.Parallel()
+	evidence := "## Report\n- it's done; see C:\\ci\n-- end"
+	script := AmendCompletionScript("w-abc", "my-rig", evidence)
+	want := `UPDATE completions SET evidence='## Report\n- it''s done; see C:\\ci\n-- end'`
+	if !strings.Contains(script, want) {
+		t.Errorf("amend script missing %q:\n%s", want, script)
+	}
+	if strings.Count(script, "\n-- end") != 0 {
+		t.Errorf("evidence newlines must be escaped, not raw:\n%s", script)
+	}
+}
+
+func TestQueryWantedAsOf_RejectsUnsafeCommit(t *testing.T) {
+	t.Parallel()
+	for _, ref := range []string{"", "abc'; DROP TABLE wanted; --", "HEAD 1"} {
+		if _, err := QueryWantedAsOf(t.TempDir(), "w-abc", ref); err == nil || !strings.Contains(err.Error(), "invalid commit reference") {
+			t.Errorf("QueryWantedAsOf(%q) error = %v, want invalid commit reference", ref, err)
+		}
+	}
+}
+
+func TestBuildExportWantedQuery(t *testing.T) {
+	t.Parallel()
+	q, err := buildExportWantedQuery(WantedExportFilter{})
+	if err != nil || strings.Contains(q, "WHERE") {
+		t.Errorf("unfiltered query = %q, %v; want no WHERE", q, err)
+	}
+
+	q, err = buildExportWantedQuery(WantedExportFilter{
+		UpdatedAfter: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		ChangedSince: "HEAD~3",
+		Status:       "open",
+	})
+	if err != nil {
+		t.Fatalf("buildExportWantedQuery() error: %v", err)
+	}
+	for _, want := range []string{
+		"updated_at > '2026-10-01 12:00:00'",
+		"id IN (SELECT to_id FROM dolt_diff('HEAD~3', 'HEAD', 'wanted') WHERE to_id IS NOT NULL)",
+		"status = 'open'",
+		"ORDER BY updated_at ASC, id ASC;",
+	} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query missing %q:\n%s", want, q)
+		}
+	}
+
+	if _, err := buildExportWantedQuery(WantedExportFilter{ChangedSince: "x'); DROP"}); err == nil {
+		t.Error("buildExportWantedQuery() should reject an unsafe commit reference")
+	}
+}
+
+func TestParseWantedLabel(t *testing.T) {
+	t.Parallel()
+	got, err := ParseWantedLabel(" jira.ticket = OPS-142 ")
+	if err != nil || got != (WantedLabel{Key: "jira.ticket", Value: "OPS-142"}) {
+		t.Errorf("ParseWantedLabel() = %+v, %v", got, err)
+	}
+	if got, err := ParseWantedLabel("url=https://x.test/?a=b"); err != nil || got.Value != "https://x.test/?a=b" {
+		t.Errorf("value containing '=' = %+v, %v", got, err)
+	}
+	for _, bad := range []string{"", "novalue", "k=", "=v", "9lives=x", "UPPER=x", "gt.internal=x", "delivery:pending=x", "k=two\nlines"} {
+		if _, err := ParseWantedLabel(bad); err == nil {
+			t.Errorf("ParseWantedLabel(%q) expected error", bad)
+		}
+	}
+}
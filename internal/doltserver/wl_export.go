@@ -0,0 +1,66 @@
+// Package doltserver - wl_export.go reads full wanted rows for bulk export.
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WantedExportFilter selects rows for ExportWanted. The zero value exports
+// every row; set fields narrow the export to rows matching all of them.
+type WantedExportFilter struct {
+	// UpdatedAfter keeps rows whose updated_at is after it.
+	UpdatedAfter time.Time
+
+	// ChangedSince keeps rows added or modified since this Dolt commit (a
+	// hash, branch, or ancestry such as HEAD~3), per dolt_diff against HEAD.
+	// Deleted rows are not reported.
+	ChangedSince string
+
+	// Status keeps rows in this status.
+	Status string
+}
+
+// ExportWanted returns full wanted rows matching filter, least recently
+// updated first, so an incremental consumer can resume from the last row's
+// UpdatedAt.
+func ExportWanted(townRoot string, filter WantedExportFilter) ([]*WantedItem, error) {
+	query, err := buildExportWantedQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("exporting wanted items: %w", err)
+	}
+	var items []*WantedItem
+	for _, row := range parseSimpleCSV(output) {
+		items = append(items, parseWantedRow(row))
+	}
+	return items, nil
+}
+
+// buildExportWantedQuery builds the SELECT used by ExportWanted.
+func buildExportWantedQuery(f WantedExportFilter) (string, error) {
+	var conds []string
+	if !f.UpdatedAfter.IsZero() {
+		conds = append(conds, fmt.Sprintf("updated_at > '%s'", f.UpdatedAfter.UTC().Format(doltTimestampLayout)))
+	}
+	if f.ChangedSince != "" {
+		if !validCommitRefRe.MatchString(f.ChangedSince) {
+			return "", fmt.Errorf("invalid commit reference %q", f.ChangedSince)
+		}
+		conds = append(conds, fmt.Sprintf("id IN (SELECT to_id FROM dolt_diff('%s', 'HEAD', 'wanted') WHERE to_id IS NOT NULL)",
+			EscapeSQL(f.ChangedSince)))
+	}
+	if f.Status != "" {
+		conds = append(conds, fmt.Sprintf("status = '%s'", EscapeSQL(f.Status)))
+	}
+
+	query := fmt.Sprintf("USE %s; SELECT * FROM wanted", WLCommonsDB)
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return query + " ORDER BY updated_at ASC, id ASC;", nil
+}
@@ -0,0 +1,96 @@
+// Package doltserver - wl_groups.go stores named groups of rigs that can
+// hold a claim together.
+package doltserver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// groupNameRe matches valid group names: the same shape as label keys, so
+// they are safe in commit messages and claim output.
+var groupNameRe = regexp.MustCompile(`^[a-z][a-z0-9._-]{0,63}$`)
+
+// ValidateGroupName rejects names that cannot be stored as a group.
+func ValidateGroupName(group string) error {
+	if !groupNameRe.MatchString(group) {
+		return fmt.Errorf("invalid group name %q: use lowercase letters, digits, '.', '_' or '-', starting with a letter", group)
+	}
+	return nil
+}
+
+// AddGroupMembers adds rigHandles to group, creating it if needed, in one
+// commit. Existing members are left as they are.
+func AddGroupMembers(townRoot, group, addedBy string, rigHandles []string) error {
+	if err := ValidateGroupName(group); err != nil {
+		return err
+	}
+	if len(rigHandles) == 0 {
+		return fmt.Errorf("no rigs to add to group %q", group)
+	}
+	var stmts strings.Builder
+	for _, rig := range rigHandles {
+		fmt.Fprintf(&stmts, "INSERT IGNORE INTO group_members (group_name, rig_handle, added_by, created_at) VALUES ('%s', '%s', '%s', NOW());\n",
+			EscapeSQL(group), EscapeSQL(rig), EscapeSQL(addedBy))
+	}
+	script := fmt.Sprintf(`USE %s;
+%sCALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB, stmts.String(),
+		EscapeSQL(wlCommitMessage("group", group, addedBy, "add "+strings.Join(rigHandles, ", "))))
+
+	err := doltSQLScriptWithRetry(townRoot, script)
+	if err == nil || isNothingToCommit(err) {
+		return nil
+	}
+	return fmt.Errorf("adding group members: %w", err)
+}
+
+// QueryGroupMembers returns group's member rigs, sorted. An unknown group,
+// or a database without the group_members table, has no members.
+func QueryGroupMembers(townRoot, group string) ([]string, error) {
+	query := fmt.Sprintf(`USE %s; SELECT rig_handle FROM group_members WHERE group_name='%s' ORDER BY rig_handle;`,
+		WLCommonsDB, EscapeSQL(group))
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying group members: %w", err)
+	}
+	var members []string
+	for _, r := range parseSimpleCSV(output) {
+		members = append(members, r["rig_handle"])
+	}
+	return members, nil
+}
+
+// ClaimWantedForGroup claims wantedID for group. rigHandle, which must be a
+// member of group, is recorded as claimed_by; any member may then complete
+// the item. A non-member, or an item that is not open, matches no rows and
+// DOLT_COMMIT reports "nothing to commit".
+func ClaimWantedForGroup(townRoot, wantedID, rigHandle, group string) error {
+	err := doltSQLScriptWithRetry(townRoot, ClaimWantedForGroupScript(wantedID, rigHandle, group))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q is %w, or %q is not a member of group %q", wantedID, ErrWantedNotOpen, rigHandle, group)
+	}
+	return fmt.Errorf("claim failed: %w", err)
+}
+
+// ClaimWantedForGroupScript returns the SQL script ClaimWantedForGroup executes.
+func ClaimWantedForGroupScript(wantedID, rigHandle, group string) string {
+	return fmt.Sprintf(`USE %s;
+UPDATE wanted SET claimed_by='%s', claimed_group='%s', status='claimed', claimed_at=NOW(), updated_at=NOW()
+  WHERE id='%s' AND status='open'
+  AND EXISTS (SELECT 1 FROM group_members WHERE group_name='%s' AND rig_handle='%s');
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB,
+		EscapeSQL(rigHandle), EscapeSQL(group), EscapeSQL(wantedID),
+		EscapeSQL(group), EscapeSQL(rigHandle),
+		EscapeSQL(wlCommitMessage("claim", wantedID, rigHandle, "group "+group)))
+}
@@ -0,0 +1,45 @@
+package doltserver
+
+import (
+	"fmt"
+	"time"
+)
+
+// WantedEvent is a row in wanted_history: one recorded action on an item,
+// such as a delegated claim, a rejection, or a comment.
+type WantedEvent struct {
+	Action string
+	Actor  string
+	Detail string
+	At     time.Time
+}
+
+// QueryWantedHistory returns every wanted_history event for wantedID,
+// oldest first. A wasteland without the table has no history.
+func QueryWantedHistory(townRoot, wantedID string) ([]WantedEvent, error) {
+	query := fmt.Sprintf(`USE %s; SELECT action, COALESCE(actor, '') as actor, COALESCE(detail, '') as detail, created_at FROM wanted_history WHERE wanted_id='%s' ORDER BY created_at, id;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying history: %w", err)
+	}
+	var events []WantedEvent
+	for _, r := range parseSimpleCSV(output) {
+		events = append(events, WantedEvent{
+			Action: r["action"],
+			Actor:  r["actor"],
+			Detail: r["detail"],
+			At:     parseDoltTimestamp(r["created_at"]),
+		})
+	}
+	return events, nil
+}
+
+// ParseDoltTimestamp parses a timestamp as the dolt CLI prints it, such as
+// WantedCompletion.CompletedAt. Anything else yields the zero time.
+func ParseDoltTimestamp(s string) time.Time {
+	return parseDoltTimestamp(s)
+}
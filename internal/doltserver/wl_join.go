@@ -0,0 +1,96 @@
+// Package doltserver - wl_join.go attaches a joined wasteland's commons to
+// the town's Dolt server.
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// wlCommonsRequiredTables are the tables a database must have to be used
+// as a wasteland commons.
+var wlCommonsRequiredTables = []string{"wanted", "completions"}
+
+// CloneWLCommons clones the DoltHub database org/db into the town's data
+// directory as the active wl-commons database. It is a no-op when that
+// database already exists. A clone lacking the commons tables is removed
+// again and reported as not a wasteland.
+func CloneWLCommons(townRoot, org, db string) error {
+	if DatabaseExists(townRoot, WLCommonsDB) {
+		return nil
+	}
+
+	remote := DoltHubRemoteURL(org, db)
+	running, _, _ := IsRunning(townRoot)
+	if running {
+		// DOLT_CLONE registers the database with the live server.
+		if err := serverExecSQL(townRoot, BindSQL("CALL DOLT_CLONE(?, ?)", remote, WLCommonsDB)); err != nil {
+			return fmt.Errorf("cloning %s/%s: %w", org, db, err)
+		}
+		if err := waitForCatalog(townRoot, WLCommonsDB); err != nil {
+			return err
+		}
+	} else {
+		config := DefaultConfig(townRoot)
+		cmd := exec.Command("dolt", "clone", remote, filepath.Join(config.DataDir, WLCommonsDB))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("cloning %s/%s: %w (%s)", org, db, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	tables, err := listWLCommonsTables(townRoot)
+	if err != nil {
+		return err
+	}
+	if missing := missingWLCommonsTables(tables); len(missing) > 0 {
+		removeWLCommons(townRoot, running)
+		return fmt.Errorf("%s/%s is not a wasteland commons: missing table(s) %s", org, db, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// listWLCommonsTables returns the tables in the wl-commons database.
+func listWLCommonsTables(townRoot string) ([]string, error) {
+	output, err := doltSQLQuery(townRoot, BindSQL("SELECT table_name FROM information_schema.tables WHERE table_schema = ?;", WLCommonsDB))
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	var tables []string
+	for _, row := range parseSimpleCSV(output) {
+		tables = append(tables, strings.ToLower(firstNonEmpty(row["table_name"], row["TABLE_NAME"])))
+	}
+	return tables, nil
+}
+
+// missingWLCommonsTables returns the required commons tables absent from
+// tables, in wlCommonsRequiredTables order.
+func missingWLCommonsTables(tables []string) []string {
+	have := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		have[t] = true
+	}
+	var missing []string
+	for _, t := range wlCommonsRequiredTables {
+		if !have[t] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+// removeWLCommons discards a clone that turned out not to be a commons.
+// Failures are only warned about: the caller is already reporting an error.
+func removeWLCommons(townRoot string, running bool) {
+	var err error
+	if running {
+		err = serverExecSQL(townRoot, fmt.Sprintf("DROP DATABASE `%s`", WLCommonsDB))
+	} else {
+		err = os.RemoveAll(filepath.Join(DefaultConfig(townRoot).DataDir, WLCommonsDB))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not remove database %q: %v\n", WLCommonsDB, err)
+	}
+}
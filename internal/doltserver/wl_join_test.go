@@ -0,0 +1,28 @@
+package doltserver
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMissingWLCommonsTables(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		tables []string
+		want   []string
+	}{
+		{"commons", []string{"rigs", "wanted", "completions", "_meta"}, nil},
+		{"empty", nil, []string{"wanted", "completions"}},
+		{"beads database", []string{"issues", "dependencies"}, []string{"wanted", "completions"}},
+		{"no completions", []string{"wanted"}, []string{"completions"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := missingWLCommonsTables(tt.tables); !slices.Equal(got, tt.want) {
+				t.Errorf("missingWLCommonsTables(%v) = %v, want %v", tt.tables, got, tt.want)
+			}
+		})
+	}
+}
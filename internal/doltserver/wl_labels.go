@@ -0,0 +1,95 @@
+// Package doltserver - wl_labels.go stores key=value metadata on wanted items.
+package doltserver
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Label keys are lowercase identifiers; dots, dashes, and underscores let
+// integrators group their own keys (e.g. jira.ticket).
+var labelKeyRe = regexp.MustCompile(`^[a-z][a-z0-9._-]{0,63}$`)
+
+// reservedLabelPrefixes are key namespaces not available to --label: gt. is
+// kept for Gas Town itself and delivery keeps wanted labels from being
+// mistaken for mail delivery-tracking labels.
+var reservedLabelPrefixes = []string{"gt.", "delivery"}
+
+// WantedLabel is a row in the wanted_labels table.
+type WantedLabel struct {
+	Key   string
+	Value string
+	// SetBy is the rig that last wrote the label.
+	SetBy string
+}
+
+// ParseWantedLabel parses and validates a key=value label.
+func ParseWantedLabel(s string) (WantedLabel, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || key == "" || value == "" {
+		return WantedLabel{}, fmt.Errorf("invalid label %q: want key=value", s)
+	}
+	if !labelKeyRe.MatchString(key) {
+		return WantedLabel{}, fmt.Errorf("invalid label key %q: use lowercase letters, digits, '.', '-', '_' (max 64, starting with a letter)", key)
+	}
+	for _, prefix := range reservedLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return WantedLabel{}, fmt.Errorf("label key %q uses the reserved %q namespace", key, prefix)
+		}
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return WantedLabel{}, fmt.Errorf("label %q: value must be a single line", key)
+	}
+	return WantedLabel{Key: key, Value: value}, nil
+}
+
+// SetWantedLabels writes labels on wantedID in one commit, replacing the
+// value of any key already set. Rewriting identical values is a no-op.
+func SetWantedLabels(townRoot, wantedID, rigHandle string, labels []WantedLabel) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	var stmts strings.Builder
+	keys := make([]string, 0, len(labels))
+	for _, l := range labels {
+		fmt.Fprintf(&stmts, `INSERT INTO wanted_labels (wanted_id, label_key, label_value, set_by, created_at)
+  VALUES ('%s', '%s', '%s', '%s', NOW())
+  ON DUPLICATE KEY UPDATE label_value=VALUES(label_value), set_by=VALUES(set_by);
+`, EscapeSQL(wantedID), EscapeSQL(l.Key), EscapeSQL(l.Value), EscapeSQL(rigHandle))
+		keys = append(keys, l.Key)
+	}
+	script := fmt.Sprintf(`USE %s;
+%sCALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB, stmts.String(),
+		EscapeSQL(wlCommitMessage("label", wantedID, rigHandle, strings.Join(keys, ", "))))
+
+	err := doltSQLScriptWithRetry(townRoot, script)
+	if err == nil || isNothingToCommit(err) {
+		return nil
+	}
+	return fmt.Errorf("setting labels: %w", err)
+}
+
+// QueryWantedLabels returns wantedID's labels sorted by key. A database
+// without the wanted_labels table has no labels.
+func QueryWantedLabels(townRoot, wantedID string) ([]WantedLabel, error) {
+	query := fmt.Sprintf(`USE %s; SELECT label_key, label_value, COALESCE(set_by, '') as set_by FROM wanted_labels WHERE wanted_id='%s' ORDER BY label_key;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying labels: %w", err)
+	}
+	var labels []WantedLabel
+	for _, r := range parseSimpleCSV(output) {
+		labels = append(labels, WantedLabel{Key: r["label_key"], Value: r["label_value"], SetBy: r["set_by"]})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Key < labels[j].Key })
+	return labels, nil
+}
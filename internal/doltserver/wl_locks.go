@@ -0,0 +1,124 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultClaimLockTTL is how long a claim lock is honoured when the town
+// holding it never releases it (e.g. it crashed mid-claim).
+const DefaultClaimLockTTL = 10 * time.Minute
+
+// wlLocksTableSQL creates the claim lock table. It is not part of the
+// wl-commons schema: locking is opt-in, so the table is created by the
+// first lock taken rather than demanded of every board. Its times are
+// written and compared as UTC_TIMESTAMP(), whatever the server's zone, so
+// QueryClaimLock can read them back as UTC.
+const wlLocksTableSQL = `CREATE TABLE IF NOT EXISTS wl_locks (
+    wanted_id VARCHAR(64) PRIMARY KEY,
+    locked_by VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);`
+
+// ClaimLock is an advisory lock a town holds on a wanted item while it
+// claims it (gt wl claim --lock).
+type ClaimLock struct {
+	WantedID  string
+	LockedBy  string
+	ExpiresAt time.Time
+}
+
+// ClaimLockHeldError is returned when another town holds an unexpired lock
+// on the item.
+type ClaimLockHeldError struct {
+	Lock ClaimLock
+}
+
+func (e *ClaimLockHeldError) Error() string {
+	if e.Lock.ExpiresAt.IsZero() {
+		return fmt.Sprintf("wanted item %s is locked by %s", e.Lock.WantedID, e.Lock.LockedBy)
+	}
+	return fmt.Sprintf("wanted item %s is locked by %s until %s",
+		e.Lock.WantedID, e.Lock.LockedBy, e.Lock.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+// IsClaimLockHeld reports whether err is a ClaimLockHeldError.
+func IsClaimLockHeld(err error) bool {
+	var held *ClaimLockHeldError
+	return errors.As(err, &held)
+}
+
+// AcquireClaimLock takes the lock on wantedID for rigHandle for ttl. An
+// expired lock, or one rigHandle already holds, is replaced; a live lock
+// held by another town fails with a ClaimLockHeldError.
+//
+// The lock is a row keyed by the item, so two towns inserting it on one
+// database collide on the primary key, and two towns inserting it on
+// separate clones collide when the clones merge.
+func AcquireClaimLock(townRoot, wantedID, rigHandle string, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = DefaultClaimLockTTL
+	}
+	err := doltSQLScriptWithRetry(townRoot, AcquireClaimLockScript(wantedID, rigHandle, ttl))
+	if err == nil {
+		return nil
+	}
+	if isDuplicateKey(err) {
+		lock, qerr := QueryClaimLock(townRoot, wantedID)
+		if qerr != nil || lock == nil {
+			// Released or expired in between; report the holder as unknown.
+			return &ClaimLockHeldError{Lock: ClaimLock{WantedID: wantedID, LockedBy: "another town"}}
+		}
+		return &ClaimLockHeldError{Lock: *lock}
+	}
+	return fmt.Errorf("locking %s: %w", wantedID, err)
+}
+
+// AcquireClaimLockScript returns the SQL script AcquireClaimLock executes.
+func AcquireClaimLockScript(wantedID, rigHandle string, ttl time.Duration) string {
+	return "USE " + WLCommonsDB + ";\n" + wlLocksTableSQL + "\n" + BindSQL(`START TRANSACTION;
+DELETE FROM wl_locks WHERE wanted_id=? AND (expires_at < UTC_TIMESTAMP() OR locked_by=?);
+INSERT INTO wl_locks (wanted_id, locked_by, acquired_at, expires_at)
+  VALUES (?, ?, UTC_TIMESTAMP(), DATE_ADD(UTC_TIMESTAMP(), INTERVAL ? SECOND));
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`, wantedID, rigHandle, wantedID, rigHandle, int(ttl.Seconds()), wlCommitMessage("lock", wantedID, rigHandle))
+}
+
+// ReleaseClaimLock drops rigHandle's lock on wantedID. Releasing a lock
+// that is gone (expired and taken over, or never taken) is not an error.
+func ReleaseClaimLock(townRoot, wantedID, rigHandle string) error {
+	err := doltSQLScriptWithRetry(townRoot, "USE "+WLCommonsDB+";\n"+BindSQL(`DELETE FROM wl_locks WHERE wanted_id=? AND locked_by=?;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`, wantedID, rigHandle, wlCommitMessage("unlock", wantedID, rigHandle)))
+	if err == nil || isNothingToCommit(err) || isTableNotFound(err) {
+		return nil
+	}
+	return fmt.Errorf("releasing lock on %s: %w", wantedID, err)
+}
+
+// QueryClaimLock returns the unexpired lock on wantedID, or nil when there
+// is none.
+func QueryClaimLock(townRoot, wantedID string) (*ClaimLock, error) {
+	query := "USE " + WLCommonsDB + "; " + BindSQL(`SELECT wanted_id, locked_by, DATE_FORMAT(expires_at, '%Y-%m-%d %H:%i:%s') AS expires_at FROM wl_locks WHERE wanted_id=? AND expires_at >= UTC_TIMESTAMP();`, wantedID)
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	rows := parseSimpleCSV(output)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	lock := &ClaimLock{WantedID: rows[0]["wanted_id"], LockedBy: rows[0]["locked_by"]}
+	if t, err := time.Parse("2006-01-02 15:04:05", rows[0]["expires_at"]); err == nil {
+		lock.ExpiresAt = t
+	}
+	return lock, nil
+}
@@ -0,0 +1,51 @@
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquireClaimLockScript(t *testing.T) {
+	t.Parallel()
+	script := AcquireClaimLockScript("w-1", "rig-o'brien", 90*time.Second)
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS wl_locks",
+		"DELETE FROM wl_locks WHERE wanted_id='w-1' AND (expires_at < UTC_TIMESTAMP() OR locked_by='rig-o''brien');",
+		"DATE_ADD(UTC_TIMESTAMP(), INTERVAL 90 SECOND)",
+		"CALL DOLT_COMMIT('-m', 'wl lock: w-1 by rig-o''brien');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestAcquireClaimLock_HeldNamesHolder(t *testing.T) {
+	runner := installFakeDoltRunner(t,
+		fakeDoltResult{"duplicate primary key given: [w-1]", fmt.Errorf("exit status 1")},
+		fakeDoltResult{output: "wanted_id,locked_by,expires_at\nw-1,rig-a,2026-10-17 12:30:00\n"},
+	)
+
+	err := AcquireClaimLock(t.TempDir(), "w-1", "rig-b", 0)
+	if !IsClaimLockHeld(err) {
+		t.Fatalf("AcquireClaimLock() error = %v, want ClaimLockHeldError", err)
+	}
+	if want := "wanted item w-1 is locked by rig-a until 2026-10-17T12:30:00Z"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if len(runner.sql) != 2 || !strings.Contains(runner.sql[0], "INTERVAL 600 SECOND") {
+		t.Errorf("dolt calls = %q, want the default TTL lock then the holder query", runner.sql)
+	}
+	if !strings.Contains(runner.sql[1], "expires_at >= UTC_TIMESTAMP()") {
+		t.Errorf("holder query does not compare in UTC:\n%s", runner.sql[1])
+	}
+}
+
+func TestReleaseClaimLock_NothingToRelease(t *testing.T) {
+	installFakeDoltRunner(t, fakeDoltResult{"error: nothing to commit", fmt.Errorf("exit status 1")})
+	if err := ReleaseClaimLock(t.TempDir(), "w-1", "rig-a"); err != nil {
+		t.Errorf("ReleaseClaimLock() error: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package doltserver - wl_merge.go folds duplicate wanted items into one.
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeWanted withdraws each of duplicateIDs, pointing its merged_into at
+// keepID and recording a 'merge' event in wanted_history, in a single Dolt
+// commit. Only open duplicates are merged; ROW_COUNT() gates each history
+// insert so items that were claimed in the meantime are left untouched.
+// keepID must exist.
+func MergeWanted(townRoot, keepID string, duplicateIDs []string, rigHandle string) error {
+	if len(duplicateIDs) == 0 {
+		return fmt.Errorf("no duplicates to merge into %q", keepID)
+	}
+	for _, id := range duplicateIDs {
+		if id == keepID {
+			return fmt.Errorf("cannot merge %q into itself", keepID)
+		}
+	}
+
+	err := doltSQLScriptWithRetry(townRoot, MergeWantedScript(keepID, duplicateIDs, rigHandle))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("none of %s is open, or %q does not exist", strings.Join(duplicateIDs, ", "), keepID)
+	}
+	return fmt.Errorf("merge failed: %w", err)
+}
+
+// MergeWantedScript returns the SQL script MergeWanted executes.
+func MergeWantedScript(keepID string, duplicateIDs []string, rigHandle string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "USE %s;\n", WLCommonsDB)
+	fmt.Fprintf(&b, "SET @keep = (SELECT COUNT(*) FROM wanted WHERE id='%s');\n", EscapeSQL(keepID))
+	for _, id := range duplicateIDs {
+		fmt.Fprintf(&b, `UPDATE wanted SET status='%s', merged_into='%s', updated_at=NOW()
+  WHERE id='%s' AND status='%s' AND @keep > 0;
+SET @merged = ROW_COUNT();
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), '%s', 'merge', '%s', '%s', NOW() FROM dual WHERE @merged > 0;
+`, StatusWithdrawn, EscapeSQL(keepID), EscapeSQL(id), StatusOpen,
+			EscapeSQL(id), EscapeSQL(rigHandle), EscapeSQL("merged into "+keepID))
+	}
+	fmt.Fprintf(&b, "CALL DOLT_ADD('-A');\nCALL DOLT_COMMIT('-m', '%s');\n",
+		EscapeSQL(wlCommitMessage("merge", keepID, rigHandle, "duplicates: "+strings.Join(duplicateIDs, ", "))))
+	return b.String()
+}
@@ -0,0 +1,131 @@
+// Package doltserver - wl_migrate.go brings a wl-commons database created by
+// an older gt up to the schema version this build writes.
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WLCommonsSchemaVersion is the _meta schema_version of a wl-commons
+// database created or migrated by this build.
+const WLCommonsSchemaVersion = "1.3"
+
+// wlCommonsMigration adds the columns introduced at one schema version.
+// Column definitions are taken from wlCommonsSchemaSQL, so a migration
+// cannot drift from the schema new databases get.
+type wlCommonsMigration struct {
+	Version string
+	// Columns are "table.column" names.
+	Columns []string
+}
+
+// wlCommonsMigrations lists every schema version after 1.0, oldest first.
+// The last entry's Version must equal WLCommonsSchemaVersion.
+var wlCommonsMigrations = []wlCommonsMigration{
+	// Claims record when and through which rig they were made.
+	{Version: "1.1", Columns: []string{"wanted.claimed_at", "wanted.claimed_via"}},
+	// Submissions count how often an item has been completed.
+	{Version: "1.2", Columns: []string{"wanted.completion_count"}},
+	// Amended completions point at the completion that replaced them.
+	{Version: "1.3", Columns: []string{"completions.superseded_by"}},
+}
+
+// MigrateWLCommons applies the migrations the commons in townRoot has not
+// yet had, adding their columns and recording the new schema_version in
+// one Dolt commit. It returns the versions applied, none when the database
+// is current.
+func MigrateWLCommons(townRoot string) ([]string, error) {
+	current, err := queryWLSchemaVersion(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := readWLCommonsColumns(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	script, applied := buildWLMigrationScript(current, actual)
+	if len(applied) == 0 {
+		return nil, nil
+	}
+	if err := doltSQLScriptWithRetry(townRoot, script); err != nil {
+		return nil, fmt.Errorf("migrating wl-commons schema to %s: %w", applied[len(applied)-1], err)
+	}
+	return applied, nil
+}
+
+// queryWLSchemaVersion returns the commons' schema_version; a database
+// without one predates versioning and counts as 1.0.
+func queryWLSchemaVersion(townRoot string) (string, error) {
+	query := fmt.Sprintf("USE %s; SELECT value FROM _meta WHERE %s = 'schema_version';", WLCommonsDB, backtickKey())
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return "", fmt.Errorf("reading schema version: %w", err)
+	}
+	if rows := parseSimpleCSV(output); len(rows) > 0 && rows[0]["value"] != "" {
+		return rows[0]["value"], nil
+	}
+	return "1.0", nil
+}
+
+// buildWLMigrationScript renders the migrations newer than current. Columns
+// already present in actual (table -> columns, lower-cased) are skipped, so
+// a database patched by hand migrates cleanly. It returns the versions the
+// script applies.
+func buildWLMigrationScript(current string, actual map[string]map[string]bool) (string, []string) {
+	columns := make(map[string]schemaColumn)
+	for _, t := range expectedWLCommonsSchema() {
+		for _, c := range t.Columns {
+			columns[t.Name+"."+c.Name] = c
+		}
+	}
+
+	var sb strings.Builder
+	var applied []string
+	for _, m := range wlCommonsMigrations {
+		if compareSchemaVersions(m.Version, current) <= 0 {
+			continue
+		}
+		for _, name := range m.Columns {
+			table, _, _ := strings.Cut(name, ".")
+			c := columns[name]
+			if actual[table][strings.ToLower(c.Name)] {
+				continue
+			}
+			fmt.Fprintf(&sb, "ALTER TABLE %s ADD COLUMN `%s` %s;\n", table, c.Name, c.Definition)
+		}
+		applied = append(applied, m.Version)
+	}
+	if len(applied) == 0 {
+		return "", nil
+	}
+
+	target := applied[len(applied)-1]
+	return fmt.Sprintf("USE %s;\n%sREPLACE INTO _meta (%s, value) VALUES ('schema_version', '%s');\n"+
+		"CALL DOLT_ADD('-A');\nCALL DOLT_COMMIT('-m', 'Migrate wl-commons schema to v%s');\n",
+		WLCommonsDB, sb.String(), backtickKey(), target, target), applied
+}
+
+// compareSchemaVersions compares dotted versions numerically, so 1.10 is
+// newer than 1.9. A non-numeric part counts as 0.
+func compareSchemaVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
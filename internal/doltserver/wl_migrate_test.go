@@ -0,0 +1,113 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+// completeWLColumns is the column set of a database at the current schema.
+func completeWLColumns() map[string]map[string]bool {
+	actual := make(map[string]map[string]bool)
+	for _, tbl := range expectedWLCommonsSchema() {
+		actual[tbl.Name] = make(map[string]bool)
+		for _, c := range tbl.Columns {
+			actual[tbl.Name][strings.ToLower(c.Name)] = true
+		}
+	}
+	return actual
+}
+
+func TestWLCommonsMigrations_Consistent(t *testing.T) {
+	t.Parallel()
+	if last := wlCommonsMigrations[len(wlCommonsMigrations)-1].Version; last != WLCommonsSchemaVersion {
+		t.Errorf("last migration is %s, want WLCommonsSchemaVersion %s", last, WLCommonsSchemaVersion)
+	}
+	actual := completeWLColumns()
+	prev := "1.0"
+	for _, m := range wlCommonsMigrations {
+		if compareSchemaVersions(m.Version, prev) <= 0 {
+			t.Errorf("migration %s does not follow %s", m.Version, prev)
+		}
+		prev = m.Version
+		for _, name := range m.Columns {
+			table, col, _ := strings.Cut(name, ".")
+			if !actual[table][col] {
+				t.Errorf("migration %s adds %s, which the schema does not define", m.Version, name)
+			}
+		}
+	}
+	if !strings.Contains(wlCommonsSchemaSQL(), "'schema_version', '"+WLCommonsSchemaVersion+"'") {
+		t.Error("new databases should start at WLCommonsSchemaVersion")
+	}
+}
+
+func TestBuildWLMigrationScript(t *testing.T) {
+	t.Parallel()
+	actual := completeWLColumns()
+	delete(actual["wanted"], "claimed_at")
+	delete(actual["wanted"], "completion_count")
+	delete(actual["completions"], "superseded_by")
+
+	script, applied := buildWLMigrationScript("1.0", actual)
+	if len(applied) == 0 || applied[0] != "1.1" {
+		t.Fatalf("applied = %v, want it to start at 1.1", applied)
+	}
+	for _, want := range []string{
+		"ALTER TABLE wanted ADD COLUMN `claimed_at` TIMESTAMP;",
+		"ALTER TABLE wanted ADD COLUMN `completion_count` INT DEFAULT 0;",
+		"ALTER TABLE completions ADD COLUMN `superseded_by` VARCHAR(64);",
+		"REPLACE INTO _meta (`key`, value) VALUES ('schema_version', '" + WLCommonsSchemaVersion + "');",
+		"CALL DOLT_COMMIT('-m', 'Migrate wl-commons schema to v" + WLCommonsSchemaVersion + "');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("migration script missing %q:\n%s", want, script)
+		}
+	}
+	// Columns already present are not added again.
+	if strings.Contains(script, "`claimed_via`") {
+		t.Errorf("migration script re-adds an existing column:\n%s", script)
+	}
+}
+
+func TestBuildWLMigrationScript_Current(t *testing.T) {
+	t.Parallel()
+	if script, applied := buildWLMigrationScript(WLCommonsSchemaVersion, completeWLColumns()); script != "" || applied != nil {
+		t.Errorf("current database migrated: %v\n%s", applied, script)
+	}
+}
+
+func TestCompareSchemaVersions(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.1", "1.0", 1},
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"2", "1.9", 1},
+		{"1", "1.0", 0},
+	} {
+		if got := compareSchemaVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareSchemaVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBuildWLMigrationScript_FromIntermediate(t *testing.T) {
+	t.Parallel()
+	actual := completeWLColumns()
+	delete(actual["wanted"], "completion_count")
+
+	script, applied := buildWLMigrationScript("1.1", actual)
+	if len(applied) == 0 || applied[0] != "1.2" {
+		t.Fatalf("applied = %v, want it to start at 1.2", applied)
+	}
+	if !strings.Contains(script, "ADD COLUMN `completion_count`") {
+		t.Errorf("migration script missing completion_count:\n%s", script)
+	}
+	if strings.Contains(script, "`claimed_at`") {
+		t.Errorf("migration from 1.1 re-applies 1.1:\n%s", script)
+	}
+}
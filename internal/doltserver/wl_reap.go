@@ -0,0 +1,64 @@
+// Package doltserver - wl_reap.go releases claims that have gone stale.
+package doltserver
+
+import (
+	"fmt"
+	"time"
+)
+
+// staleClaimCond matches claimed items whose claim was taken before
+// claimedBefore and whose lease, if any, has lapsed. Claims without a
+// recorded claimed_at never match.
+func staleClaimCond(claimedBefore time.Time) string {
+	return BindSQL(`status=? AND claimed_at IS NOT NULL AND claimed_at < ? AND (expires_at IS NULL OR expires_at < NOW())`,
+		StatusClaimed, claimedBefore)
+}
+
+// QueryStaleClaims returns the claimed items ReapClaim would release for
+// claimedBefore, oldest claim first.
+func QueryStaleClaims(townRoot string, claimedBefore time.Time) ([]*WantedItem, error) {
+	query := fmt.Sprintf(`USE %s; SELECT id, title, status, priority, COALESCE(posted_by, '') as posted_by, COALESCE(claimed_by, '') as claimed_by, claimed_at, expires_at FROM wanted WHERE %s ORDER BY claimed_at;`,
+		WLCommonsDB, staleClaimCond(claimedBefore))
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying stale claims: %w", err)
+	}
+	var items []*WantedItem
+	for _, row := range parseSimpleCSV(output) {
+		items = append(items, parseWantedRow(row))
+	}
+	return items, nil
+}
+
+// ReapClaim returns wantedID to the board as open if its claim is still
+// stale for claimedBefore, recording a 'reap' event by actor. A claim that
+// was renewed, released, or retaken since it was found matches nothing and
+// is reported as no longer stale.
+func ReapClaim(townRoot, wantedID string, claimedBefore time.Time, actor string) error {
+	err := doltSQLScriptWithRetry(townRoot, ReapClaimScript(wantedID, claimedBefore, actor))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("wanted item %q no longer has a stale claim", wantedID)
+	}
+	return fmt.Errorf("reap failed: %w", err)
+}
+
+// ReapClaimScript returns the SQL script ReapClaim executes. The history
+// row keeps the released claimant as its detail.
+func ReapClaimScript(wantedID string, claimedBefore time.Time, actor string) string {
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`SET @holder = (SELECT claimed_by FROM wanted WHERE id=?);
+UPDATE wanted SET assignee=NULL, claimed_by=NULL, claimed_via=NULL, claimed_group=NULL, expires_at=NULL, claimed_at=NULL, status=?, updated_at=NOW()
+  WHERE id=? AND `+staleClaimCond(claimedBefore)+`;
+SET @reaped = ROW_COUNT();
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), ?, 'reap', ?, CONCAT('stale claim by ', @holder), NOW() FROM dual WHERE @reaped > 0;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		wantedID,
+		StatusOpen, wantedID,
+		wantedID, actor,
+		wlCommitMessage("reap", wantedID, actor))
+}
@@ -0,0 +1,130 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Derived columns gt wl reindex recomputes.
+const (
+	ReindexCompletionCount = "completion_count"
+	ReindexTags            = "tags"
+)
+
+// ReindexCorrection is one derived column on a wanted row that disagrees
+// with its source. Old and New are display forms.
+type ReindexCorrection struct {
+	WantedID string
+	Column   string
+	Old      string
+	New      string
+
+	// value is the SQL literal written for New.
+	value string
+}
+
+// reindexRow is what reindex reads for one wanted row: its stored derived
+// columns and the count of completion rows they derive from.
+type reindexRow struct {
+	ID              string
+	CompletionCount int
+	Completions     int
+	Tags            string
+}
+
+// PlanReindex compares every wanted row's derived columns with their
+// sources and returns the corrections needed, without writing:
+//
+//   - completion_count must equal the item's rows in completions,
+//     superseded ones included, since every submission increments it.
+//   - tags must be trimmed, non-empty, and free of duplicates, as
+//     gt wl post writes them.
+func PlanReindex(townRoot string) ([]ReindexCorrection, error) {
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT w.id, COALESCE(w.completion_count, 0) AS completion_count, COALESCE(c.n, 0) AS completions, COALESCE(w.tags, '') AS tags FROM wanted w LEFT JOIN (SELECT wanted_id, COUNT(*) AS n FROM completions GROUP BY wanted_id) c ON c.wanted_id = w.id ORDER BY w.id;`,
+		WLCommonsDB))
+	if err != nil {
+		return nil, fmt.Errorf("reading wanted rows: %w", err)
+	}
+	var rows []reindexRow
+	for _, r := range parseSimpleCSV(output) {
+		row := reindexRow{ID: r["id"], Tags: r["tags"]}
+		row.CompletionCount, _ = strconv.Atoi(r["completion_count"])
+		row.Completions, _ = strconv.Atoi(r["completions"])
+		rows = append(rows, row)
+	}
+	return reindexCorrections(rows), nil
+}
+
+// ApplyReindex writes corrections in one transaction and Dolt commit.
+// Empty corrections is a no-op.
+func ApplyReindex(townRoot string, corrections []ReindexCorrection) error {
+	if len(corrections) == 0 {
+		return nil
+	}
+	return doltSQLScriptWithRetry(townRoot, buildReindexScript(corrections))
+}
+
+// reindexCorrections returns the corrections for rows, in row order.
+func reindexCorrections(rows []reindexRow) []ReindexCorrection {
+	var out []ReindexCorrection
+	for _, row := range rows {
+		if row.CompletionCount != row.Completions {
+			out = append(out, ReindexCorrection{
+				WantedID: row.ID,
+				Column:   ReindexCompletionCount,
+				Old:      strconv.Itoa(row.CompletionCount),
+				New:      strconv.Itoa(row.Completions),
+				value:    strconv.Itoa(row.Completions),
+			})
+		}
+		stored := parseTagsJSON(row.Tags)
+		normalized := normalizeTags(stored)
+		if !slices.Equal(stored, normalized) {
+			c := ReindexCorrection{
+				WantedID: row.ID,
+				Column:   ReindexTags,
+				Old:      row.Tags,
+				New:      "NULL",
+				value:    "NULL",
+			}
+			if len(normalized) > 0 {
+				data, _ := json.Marshal(normalized)
+				c.New = string(data)
+				c.value = "'" + EscapeSQL(string(data)) + "'"
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// normalizeTags trims tags and drops empty and repeated ones, keeping the
+// first occurrence's position.
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// buildReindexScript renders corrections as one transaction followed by a
+// Dolt commit.
+func buildReindexScript(corrections []ReindexCorrection) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "USE %s;\nSTART TRANSACTION;\n", WLCommonsDB)
+	for _, c := range corrections {
+		fmt.Fprintf(&sb, "UPDATE wanted SET %s=%s WHERE id='%s';\n", c.Column, c.value, EscapeSQL(c.WantedID))
+	}
+	sb.WriteString("COMMIT;\nCALL DOLT_ADD('-A');\n")
+	fmt.Fprintf(&sb, "CALL DOLT_COMMIT('-m', '%s');\n",
+		EscapeSQL(fmt.Sprintf("wl reindex: corrected %d derived value(s)", len(corrections))))
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReindexCorrections_DriftedCounter(t *testing.T) {
+	t.Parallel()
+	rows := []reindexRow{
+		{ID: "w-ok", CompletionCount: 2, Completions: 2, Tags: `["go", "auth"]`},
+		{ID: "w-drift", CompletionCount: 5, Completions: 1},
+		{ID: "w-none", CompletionCount: 1, Completions: 0},
+	}
+
+	got := reindexCorrections(rows)
+	if len(got) != 2 {
+		t.Fatalf("corrections = %+v, want 2", got)
+	}
+	if c := got[0]; c.WantedID != "w-drift" || c.Column != ReindexCompletionCount || c.Old != "5" || c.New != "1" {
+		t.Errorf("first correction = %+v, want w-drift completion_count 5 -> 1", c)
+	}
+	if c := got[1]; c.WantedID != "w-none" || c.New != "0" {
+		t.Errorf("second correction = %+v, want w-none completion_count -> 0", c)
+	}
+
+	script := buildReindexScript(got)
+	for _, want := range []string{
+		"START TRANSACTION;",
+		"UPDATE wanted SET completion_count=1 WHERE id='w-drift';",
+		"UPDATE wanted SET completion_count=0 WHERE id='w-none';",
+		"COMMIT;",
+		"wl reindex: corrected 2 derived value(s)",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "w-ok") {
+		t.Errorf("script touches a consistent row:\n%s", script)
+	}
+}
+
+func TestReindexCorrections_Tags(t *testing.T) {
+	t.Parallel()
+	rows := []reindexRow{
+		{ID: "w-dup", Tags: `["go", " go", "auth", ""]`},
+		{ID: "w-blank", Tags: `["  "]`},
+		{ID: "w-null"},
+	}
+
+	got := reindexCorrections(rows)
+	if len(got) != 2 {
+		t.Fatalf("corrections = %+v, want 2", got)
+	}
+	if c := got[0]; c.Column != ReindexTags || c.New != `["go","auth"]` {
+		t.Errorf("w-dup correction = %+v, want [\"go\",\"auth\"]", c)
+	}
+	if c := got[1]; c.WantedID != "w-blank" || c.New != "NULL" {
+		t.Errorf("w-blank correction = %+v, want NULL", c)
+	}
+	if script := buildReindexScript(got); !strings.Contains(script, `SET tags='["go","auth"]' WHERE id='w-dup'`) {
+		t.Errorf("script does not write normalized tags:\n%s", script)
+	}
+}
@@ -0,0 +1,55 @@
+package doltserver
+
+import "fmt"
+
+// CompletionSupersededByReopen is the superseded_by marker ReopenWanted sets
+// on the completion it retires, so the next claimant can submit afresh.
+const CompletionSupersededByReopen = "reopen"
+
+// ReopenWanted returns an item in review, completed, or withdrawn to the
+// board as open. The claim is cleared, the current completion is retired,
+// and reopened_by/reopened_at record who reopened it. Only the poster may
+// reopen an item unless force is set.
+func ReopenWanted(townRoot, wantedID, actor string, force bool) error {
+	err := doltSQLScriptWithRetry(townRoot, ReopenWantedScript(wantedID, actor, force))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		if force {
+			return fmt.Errorf("wanted item %q is not in review, completed, or withdrawn, or does not exist", wantedID)
+		}
+		return fmt.Errorf("wanted item %q is not reopenable by %q (not its poster, not closed or in review, or does not exist)", wantedID, actor)
+	}
+	return fmt.Errorf("reopen failed: %w", err)
+}
+
+// ReopenWantedScript returns the SQL script ReopenWanted executes. ROW_COUNT()
+// gates the completion and history writes, so an item that cannot be
+// reopened leaves nothing to commit.
+func ReopenWantedScript(wantedID, actor string, force bool) string {
+	cond := BindSQL(`status IN (?, ?, ?)`, StatusInReview, StatusCompleted, StatusWithdrawn)
+	var historyDetail any
+	commitDetail := ""
+	if force {
+		historyDetail, commitDetail = "forced", "forced"
+	} else {
+		cond += BindSQL(` AND posted_by=?`, actor)
+	}
+	return "USE " + WLCommonsDB + ";\n" + BindSQL(`START TRANSACTION;
+UPDATE wanted SET assignee=NULL, claimed_by=NULL, claimed_via=NULL, claimed_group=NULL, expires_at=NULL, claimed_at=NULL, status=?, reopened_by=?, reopened_at=NOW(), updated_at=NOW()
+  WHERE id=? AND `+cond+`;
+SET @reopened = ROW_COUNT();
+UPDATE completions SET superseded_by=?
+  WHERE wanted_id=? AND superseded_by IS NULL AND @reopened > 0;
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), ?, 'reopen', ?, ?, NOW() FROM dual WHERE @reopened > 0;
+COMMIT;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', ?);
+`,
+		StatusOpen, actor, wantedID,
+		CompletionSupersededByReopen, wantedID,
+		wantedID, actor, historyDetail,
+		wlCommitMessage("reopen", wantedID, actor, commitDetail))
+}
@@ -0,0 +1,57 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PendingReview is an in-review wanted item and the completion awaiting
+// its poster's review.
+type PendingReview struct {
+	WantedID   string
+	Title      string
+	Priority   int
+	PostedBy   string
+	Completion WantedCompletion
+}
+
+// QueryPendingReviews returns the in-review items posted by postedBy, or
+// every in-review item when postedBy is empty, each joined with its
+// current, unvalidated completion. The oldest completion comes first.
+func QueryPendingReviews(townRoot, postedBy string) ([]PendingReview, error) {
+	output, err := doltSQLQuery(townRoot, buildPendingReviewsQuery(postedBy))
+	if err != nil {
+		return nil, fmt.Errorf("querying pending reviews: %w", err)
+	}
+	var reviews []PendingReview
+	for _, r := range parseSimpleCSV(output) {
+		priority, _ := strconv.Atoi(r["priority"])
+		reviews = append(reviews, PendingReview{
+			WantedID: r["wanted_id"],
+			Title:    r["title"],
+			Priority: priority,
+			PostedBy: r["posted_by"],
+			Completion: WantedCompletion{
+				ID:          r["completion_id"],
+				CompletedBy: r["completed_by"],
+				Evidence:    r["evidence"],
+				CompletedAt: r["completed_at"],
+			},
+		})
+	}
+	return reviews, nil
+}
+
+func buildPendingReviewsQuery(postedBy string) string {
+	where := ""
+	if postedBy != "" {
+		where = BindSQL(" AND w.posted_by = ?", postedBy)
+	}
+	return "USE " + WLCommonsDB + "; " +
+		`SELECT w.id AS wanted_id, w.title, w.priority, COALESCE(w.posted_by, '') AS posted_by, ` +
+		`c.id AS completion_id, COALESCE(c.completed_by, '') AS completed_by, COALESCE(c.evidence, '') AS evidence, COALESCE(c.completed_at, '') AS completed_at ` +
+		`FROM wanted w JOIN completions c ON c.wanted_id = w.id ` +
+		BindSQL(`WHERE w.status = ?`, StatusInReview) +
+		` AND (c.superseded_by IS NULL OR c.superseded_by = '') AND (c.validated_by IS NULL OR c.validated_by = '')` + where +
+		` ORDER BY c.completed_at, w.id;`
+}
@@ -0,0 +1,27 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildPendingReviewsQuery(t *testing.T) {
+	t.Parallel()
+	q := buildPendingReviewsQuery("")
+	for _, want := range []string{
+		"FROM wanted w JOIN completions c ON c.wanted_id = w.id WHERE w.status = 'in_review'",
+		"AND (c.superseded_by IS NULL OR c.superseded_by = '') AND (c.validated_by IS NULL OR c.validated_by = '') ORDER BY c.completed_at, w.id;",
+	} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query missing %q:\n%s", want, q)
+		}
+	}
+	if strings.Contains(q, "posted_by = ") {
+		t.Errorf("unfiltered query filters by poster:\n%s", q)
+	}
+
+	q = buildPendingReviewsQuery("o'rig")
+	if !strings.Contains(q, "AND w.posted_by = 'o''rig' ORDER BY") {
+		t.Errorf("poster filter not applied or not escaped:\n%s", q)
+	}
+}
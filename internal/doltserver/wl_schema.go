@@ -0,0 +1,136 @@
+// Package doltserver - wl_schema.go checks a wl-commons database against the
+// schema this build of gt expects.
+package doltserver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SchemaIssue is a table or column the CLI relies on that the database lacks.
+type SchemaIssue struct {
+	Table string
+	// Column is empty when the whole table is missing.
+	Column string
+	// Fix is SQL that would bring the database in line.
+	Fix string
+}
+
+func (i SchemaIssue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("missing table %s", i.Table)
+	}
+	return fmt.Sprintf("missing column %s.%s", i.Table, i.Column)
+}
+
+// schemaColumn is a column definition parsed from the schema script.
+type schemaColumn struct {
+	Name       string
+	Definition string
+}
+
+// schemaTable is a table parsed from the schema script.
+type schemaTable struct {
+	Name      string
+	Columns   []schemaColumn
+	CreateSQL string
+}
+
+var createTableRe = regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\w+) \((.*?)\n\);`)
+
+// expectedWLCommonsSchema parses wlCommonsSchemaSQL into tables and columns,
+// so the validator can never drift from the schema that EnsureWLCommons
+// creates.
+func expectedWLCommonsSchema() []schemaTable {
+	var tables []schemaTable
+	for _, m := range createTableRe.FindAllStringSubmatch(wlCommonsSchemaSQL(), -1) {
+		t := schemaTable{Name: m[1], CreateSQL: m[0]}
+		for _, line := range strings.Split(m[2], "\n") {
+			line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+			if line == "" {
+				continue
+			}
+			fields := strings.Fields(line)
+			// Constraint lines start with a keyword; a backticked first
+			// field is always a column (e.g. `key`).
+			if !strings.HasPrefix(fields[0], "`") {
+				switch strings.ToUpper(fields[0]) {
+				case "PRIMARY", "CHECK", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN":
+					continue
+				}
+			}
+			name := strings.Trim(fields[0], "`")
+			t.Columns = append(t.Columns, schemaColumn{
+				Name:       name,
+				Definition: strings.TrimSpace(strings.TrimPrefix(line, fields[0])),
+			})
+		}
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// diffWLCommonsSchema compares actual (table -> set of columns) with the
+// expected schema and returns what is missing, in schema order.
+func diffWLCommonsSchema(actual map[string]map[string]bool) []SchemaIssue {
+	var issues []SchemaIssue
+	for _, t := range expectedWLCommonsSchema() {
+		cols, ok := actual[t.Name]
+		if !ok {
+			issues = append(issues, SchemaIssue{Table: t.Name, Fix: t.CreateSQL})
+			continue
+		}
+		for _, c := range t.Columns {
+			if !cols[strings.ToLower(c.Name)] {
+				issues = append(issues, SchemaIssue{
+					Table:  t.Name,
+					Column: c.Name,
+					Fix:    fmt.Sprintf("ALTER TABLE %s ADD COLUMN `%s` %s;", t.Name, c.Name, c.Definition),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// ValidateWLCommonsSchema introspects the wl-commons database and reports
+// tables and columns the CLI expects but the database lacks. Extra tables
+// and columns are allowed.
+func ValidateWLCommonsSchema(townRoot string) ([]SchemaIssue, error) {
+	actual, err := readWLCommonsColumns(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return diffWLCommonsSchema(actual), nil
+}
+
+// readWLCommonsColumns returns the columns of each wl-commons table, keyed
+// by lower-cased table and column name.
+func readWLCommonsColumns(townRoot string) (map[string]map[string]bool, error) {
+	query := fmt.Sprintf("SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = '%s';", WLCommonsDB)
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+
+	actual := make(map[string]map[string]bool)
+	for _, row := range parseSimpleCSV(output) {
+		table := strings.ToLower(firstNonEmpty(row["table_name"], row["TABLE_NAME"]))
+		column := strings.ToLower(firstNonEmpty(row["column_name"], row["COLUMN_NAME"]))
+		if actual[table] == nil {
+			actual[table] = make(map[string]bool)
+		}
+		actual[table][column] = true
+	}
+	return actual, nil
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
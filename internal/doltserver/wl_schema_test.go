@@ -0,0 +1,85 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpectedWLCommonsSchema(t *testing.T) {
+	t.Parallel()
+	tables := expectedWLCommonsSchema()
+	byName := make(map[string]schemaTable)
+	for _, tbl := range tables {
+		byName[tbl.Name] = tbl
+	}
+	for _, name := range wlCommonsTables {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("schema missing table %s", name)
+		}
+	}
+
+	wanted := byName["wanted"]
+	cols := make(map[string]string)
+	for _, c := range wanted.Columns {
+		cols[c.Name] = c.Definition
+	}
+	for _, name := range []string{"id", "status", "claimed_by", "timeout_action", "expires_at"} {
+		if _, ok := cols[name]; !ok {
+			t.Errorf("wanted missing column %s (got %v)", name, cols)
+		}
+	}
+	if _, ok := cols["PRIMARY"]; ok {
+		t.Error("PRIMARY KEY line parsed as a column")
+	}
+	if got := cols["timeout_action"]; !strings.Contains(got, "DEFAULT 'reopen'") {
+		t.Errorf("timeout_action definition = %q", got)
+	}
+	metaCols := make(map[string]bool)
+	for _, c := range byName["_meta"].Columns {
+		metaCols[c.Name] = true
+	}
+	if !metaCols["key"] {
+		t.Errorf("_meta backticked key column not parsed: %v", byName["_meta"].Columns)
+	}
+}
+
+func TestDiffWLCommonsSchema(t *testing.T) {
+	t.Parallel()
+	actual := make(map[string]map[string]bool)
+	for _, tbl := range expectedWLCommonsSchema() {
+		actual[tbl.Name] = make(map[string]bool)
+		for _, c := range tbl.Columns {
+			actual[tbl.Name][strings.ToLower(c.Name)] = true
+		}
+	}
+	if issues := diffWLCommonsSchema(actual); len(issues) != 0 {
+		t.Fatalf("complete schema reported issues: %v", issues)
+	}
+
+	delete(actual, "badges")
+	delete(actual["wanted"], "expires_at")
+	actual["wanted"]["legacy_col"] = true
+
+	issues := diffWLCommonsSchema(actual)
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2: %v", len(issues), issues)
+	}
+	var sawColumn, sawTable bool
+	for _, is := range issues {
+		switch {
+		case is.Table == "wanted" && is.Column == "expires_at":
+			sawColumn = true
+			if !strings.HasPrefix(is.Fix, "ALTER TABLE wanted ADD COLUMN `expires_at` TIMESTAMP") {
+				t.Errorf("column fix = %q", is.Fix)
+			}
+		case is.Table == "badges" && is.Column == "":
+			sawTable = true
+			if !strings.HasPrefix(is.Fix, "CREATE TABLE IF NOT EXISTS badges") {
+				t.Errorf("table fix = %q", is.Fix)
+			}
+		}
+	}
+	if !sawColumn || !sawTable {
+		t.Errorf("issues = %v", issues)
+	}
+}
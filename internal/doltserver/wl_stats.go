@@ -0,0 +1,60 @@
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// doltTimestampLayout is how dolt renders TIMESTAMP columns in CSV output.
+const doltTimestampLayout = "2006-01-02 15:04:05"
+
+// WantedTimeline carries the lifecycle timestamps of one wanted item.
+type WantedTimeline struct {
+	ID        string
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// CompletedAt is when the first completion was submitted. Zero when
+	// the item has no completions.
+	CompletedAt time.Time
+}
+
+// QueryWantedTimelines returns the lifecycle timestamps of every wanted item,
+// oldest first. Timestamps that are NULL or unparseable are left zero.
+func QueryWantedTimelines(townRoot string) ([]WantedTimeline, error) {
+	query := fmt.Sprintf(`USE %s; SELECT w.id, w.status, COALESCE(w.created_at, '') as created_at, COALESCE(w.updated_at, '') as updated_at, COALESCE((SELECT MIN(c.completed_at) FROM completions c WHERE c.wanted_id = w.id), '') as completed_at FROM wanted w ORDER BY w.created_at, w.id;`,
+		WLCommonsDB)
+
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var timelines []WantedTimeline
+	for _, row := range parseSimpleCSV(output) {
+		timelines = append(timelines, WantedTimeline{
+			ID:          row["id"],
+			Status:      row["status"],
+			CreatedAt:   parseDoltTimestamp(row["created_at"]),
+			UpdatedAt:   parseDoltTimestamp(row["updated_at"]),
+			CompletedAt: parseDoltTimestamp(row["completed_at"]),
+		})
+	}
+	return timelines, nil
+}
+
+// parseDoltTimestamp parses a dolt TIMESTAMP value as UTC, dropping any
+// fractional seconds. It returns the zero time for empty or malformed input.
+func parseDoltTimestamp(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+	t, err := time.ParseInLocation(doltTimestampLayout, s, time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,25 @@
+package doltserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDoltTimestamp(t *testing.T) {
+	t.Parallel()
+	want := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-04 05:06:07", want},
+		{" 2026-03-04 05:06:07.123456 ", want},
+		{"", time.Time{}},
+		{"not a time", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseDoltTimestamp(tt.in); !got.Equal(tt.want) {
+			t.Errorf("parseDoltTimestamp(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
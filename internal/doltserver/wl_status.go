@@ -0,0 +1,201 @@
+package doltserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Wanted item statuses in the default workflow.
+const (
+	StatusOpen      = "open"
+	StatusClaimed   = "claimed"
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusCompleted = "completed"
+	StatusWithdrawn = "withdrawn"
+)
+
+// Wasteland settings (_meta keys) that customize the status workflow. Each
+// one falls back to the default model independently when unset.
+const (
+	// SettingWorkflowStatuses is a comma-separated list of allowed statuses.
+	SettingWorkflowStatuses = "workflow.statuses"
+	// SettingWorkflowTransitions is a comma-separated list of legal
+	// from>to transitions, e.g. "open>claimed,claimed>in_review".
+	SettingWorkflowTransitions = "workflow.transitions"
+	// SettingWorkflowClosed is a comma-separated list of terminal statuses,
+	// which no longer block dependents.
+	SettingWorkflowClosed = "workflow.closed"
+)
+
+// defaultTransitions is the built-in workflow, keyed by source status.
+var defaultTransitions = map[string][]string{
+	StatusOpen:      {StatusClaimed, StatusWithdrawn},
+	StatusClaimed:   {StatusOpen, StatusDraft, StatusInReview, StatusWithdrawn},
+	StatusDraft:     {StatusInReview, StatusClaimed},
+	StatusInReview:  {StatusCompleted, StatusClaimed, StatusOpen},
+	StatusCompleted: {StatusOpen},
+	StatusWithdrawn: {StatusOpen},
+}
+
+// StatusModel is a wasteland's status vocabulary: the allowed statuses, the
+// legal transitions between them, and which statuses are terminal. Every
+// model includes StatusOpen, the status new items are posted with.
+//
+// Mutating commands check their transition against the model before
+// writing. The writes keep their own status guards for concurrency, so a
+// custom model can forbid built-in transitions and add statuses, but cannot
+// make a command write from a state it does not already handle.
+type StatusModel struct {
+	statuses []string
+	next     map[string]map[string]bool
+	closed   map[string]bool
+}
+
+// DefaultStatusModel returns the built-in workflow.
+func DefaultStatusModel() *StatusModel {
+	m := &StatusModel{next: make(map[string]map[string]bool), closed: make(map[string]bool)}
+	for from, tos := range defaultTransitions {
+		m.statuses = append(m.statuses, from)
+		m.next[from] = make(map[string]bool)
+		for _, to := range tos {
+			m.next[from][to] = true
+		}
+	}
+	sort.Strings(m.statuses)
+	m.closed[StatusCompleted] = true
+	m.closed[StatusWithdrawn] = true
+	return m
+}
+
+// StatusModelFromSettings builds the model described by the workflow.*
+// settings, using the default for any setting that is missing. It errors on
+// transitions or closed statuses that name unknown statuses.
+func StatusModelFromSettings(settings map[string]string) (*StatusModel, error) {
+	m := DefaultStatusModel()
+	statusesRaw := strings.TrimSpace(settings[SettingWorkflowStatuses])
+	transitionsRaw := strings.TrimSpace(settings[SettingWorkflowTransitions])
+	closedRaw := strings.TrimSpace(settings[SettingWorkflowClosed])
+
+	if statusesRaw != "" {
+		m.statuses = nil
+		seen := make(map[string]bool)
+		for _, s := range splitSettingList(statusesRaw) {
+			if !seen[s] {
+				seen[s] = true
+				m.statuses = append(m.statuses, s)
+			}
+		}
+		sort.Strings(m.statuses)
+		if !seen[StatusOpen] {
+			return nil, fmt.Errorf("%s must include %q", SettingWorkflowStatuses, StatusOpen)
+		}
+		// Keep only default transitions and closed statuses that still
+		// apply; explicit settings below replace them wholesale.
+		for from, tos := range m.next {
+			if !seen[from] {
+				delete(m.next, from)
+				continue
+			}
+			for to := range tos {
+				if !seen[to] {
+					delete(tos, to)
+				}
+			}
+		}
+		for s := range m.closed {
+			if !seen[s] {
+				delete(m.closed, s)
+			}
+		}
+	}
+
+	if transitionsRaw != "" {
+		m.next = make(map[string]map[string]bool)
+		for _, t := range splitSettingList(transitionsRaw) {
+			from, to, ok := strings.Cut(t, ">")
+			from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+			if !ok || from == "" || to == "" {
+				return nil, fmt.Errorf("invalid %s entry %q: want from>to", SettingWorkflowTransitions, t)
+			}
+			for _, s := range []string{from, to} {
+				if !m.Known(s) {
+					return nil, fmt.Errorf("%s entry %q names unknown status %q", SettingWorkflowTransitions, t, s)
+				}
+			}
+			if m.next[from] == nil {
+				m.next[from] = make(map[string]bool)
+			}
+			m.next[from][to] = true
+		}
+	}
+
+	if closedRaw != "" {
+		m.closed = make(map[string]bool)
+		for _, s := range splitSettingList(closedRaw) {
+			if !m.Known(s) {
+				return nil, fmt.Errorf("%s names unknown status %q", SettingWorkflowClosed, s)
+			}
+			m.closed[s] = true
+		}
+	}
+
+	return m, nil
+}
+
+// QueryStatusModel loads the wasteland's status model from its settings.
+func QueryStatusModel(store WLCommonsStore) (*StatusModel, error) {
+	settings, err := store.QuerySettings()
+	if err != nil {
+		return nil, fmt.Errorf("loading wasteland settings: %w", err)
+	}
+	return StatusModelFromSettings(settings)
+}
+
+// Statuses returns the allowed statuses in sorted order.
+func (m *StatusModel) Statuses() []string {
+	return append([]string(nil), m.statuses...)
+}
+
+// Known reports whether status is part of the model.
+func (m *StatusModel) Known(status string) bool {
+	for _, s := range m.statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Closed reports whether status is terminal.
+func (m *StatusModel) Closed(status string) bool {
+	return m.closed[status]
+}
+
+// CanTransition reports whether an item may move from one status to another.
+func (m *StatusModel) CanTransition(from, to string) bool {
+	return m.next[from][to]
+}
+
+// CheckTransition returns a descriptive error when wantedID may not move
+// from one status to another.
+func (m *StatusModel) CheckTransition(wantedID, from, to string) error {
+	if m.CanTransition(from, to) {
+		return nil
+	}
+	if !m.Known(from) {
+		return fmt.Errorf("wanted item %s has status %q, which this wasteland's workflow does not define", wantedID, from)
+	}
+	return fmt.Errorf("wanted item %s cannot move from %s to %s (see setting %s)", wantedID, from, to, SettingWorkflowTransitions)
+}
+
+func splitSettingList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
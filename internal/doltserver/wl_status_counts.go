@@ -0,0 +1,33 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// QueryStatusCounts returns how many wanted items have each status. Statuses
+// no item has are absent. The database does the counting, so this stays
+// cheap on a large board.
+func QueryStatusCounts(townRoot string) (map[string]int, error) {
+	output, err := doltSQLQuery(townRoot, buildStatusCountsQuery())
+	if err != nil {
+		return nil, fmt.Errorf("counting wanted items: %w", err)
+	}
+	return parseStatusCounts(output), nil
+}
+
+func buildStatusCountsQuery() string {
+	return fmt.Sprintf("USE %s; SELECT status, COUNT(*) AS n FROM wanted GROUP BY status;", WLCommonsDB)
+}
+
+func parseStatusCounts(output string) map[string]int {
+	counts := make(map[string]int)
+	for _, row := range parseSimpleCSV(output) {
+		n, err := strconv.Atoi(row["n"])
+		if err != nil || row["status"] == "" {
+			continue
+		}
+		counts[row["status"]] += n
+	}
+	return counts
+}
@@ -0,0 +1,28 @@
+package doltserver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildStatusCountsQuery(t *testing.T) {
+	t.Parallel()
+	q := buildStatusCountsQuery()
+	if !strings.Contains(q, "SELECT status, COUNT(*) AS n FROM wanted GROUP BY status;") {
+		t.Errorf("unexpected query:\n%s", q)
+	}
+}
+
+func TestParseStatusCounts(t *testing.T) {
+	t.Parallel()
+	got := parseStatusCounts("status,n\nopen,12\nclaimed,3\nin_review,1\n,2\nbroken,x\n")
+	want := map[string]int{"open": 12, "claimed": 3, "in_review": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStatusCounts() = %v, want %v", got, want)
+	}
+
+	if got := parseStatusCounts(""); len(got) != 0 {
+		t.Errorf("empty output = %v, want no counts", got)
+	}
+}
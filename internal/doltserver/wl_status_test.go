@@ -0,0 +1,113 @@
+package doltserver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultStatusModel(t *testing.T) {
+	t.Parallel()
+	m := DefaultStatusModel()
+	valid := [][2]string{
+		{StatusOpen, StatusClaimed},
+		{StatusClaimed, StatusInReview},
+		{StatusClaimed, StatusDraft},
+		{StatusDraft, StatusInReview},
+		{StatusInReview, StatusCompleted},
+		{StatusInReview, StatusClaimed},
+		{StatusCompleted, StatusOpen},
+	}
+	for _, tr := range valid {
+		if !m.CanTransition(tr[0], tr[1]) {
+			t.Errorf("CanTransition(%s, %s) = false, want true", tr[0], tr[1])
+		}
+	}
+	invalid := [][2]string{
+		{StatusOpen, StatusInReview},
+		{StatusOpen, StatusCompleted},
+		{StatusCompleted, StatusClaimed},
+		{"bogus", StatusOpen},
+	}
+	for _, tr := range invalid {
+		if m.CanTransition(tr[0], tr[1]) {
+			t.Errorf("CanTransition(%s, %s) = true, want false", tr[0], tr[1])
+		}
+	}
+	if !m.Closed(StatusCompleted) || !m.Closed(StatusWithdrawn) || m.Closed(StatusInReview) {
+		t.Error("default closed statuses should be completed and withdrawn")
+	}
+}
+
+func TestStatusModelFromSettings_Empty(t *testing.T) {
+	t.Parallel()
+	m, err := StatusModelFromSettings(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Statuses(), DefaultStatusModel().Statuses()) {
+		t.Errorf("Statuses() = %v, want defaults", m.Statuses())
+	}
+}
+
+func TestStatusModelFromSettings_Custom(t *testing.T) {
+	t.Parallel()
+	m, err := StatusModelFromSettings(map[string]string{
+		SettingWorkflowStatuses:    "open, claimed, in_review, verified, completed",
+		SettingWorkflowTransitions: "open>claimed, claimed>in_review, in_review>verified, verified>completed, in_review>claimed",
+		SettingWorkflowClosed:      "completed",
+	})
+	if err != nil {
+		t.Fatalf("StatusModelFromSettings() error: %v", err)
+	}
+
+	if want := []string{"claimed", "completed", "in_review", "open", "verified"}; !reflect.DeepEqual(m.Statuses(), want) {
+		t.Errorf("Statuses() = %v, want %v", m.Statuses(), want)
+	}
+	if !m.CanTransition("in_review", "verified") || !m.CanTransition("verified", "completed") {
+		t.Error("custom transitions should be allowed")
+	}
+	if m.CanTransition("in_review", "completed") {
+		t.Error("in_review>completed is not in the custom model")
+	}
+	if m.CanTransition(StatusClaimed, StatusDraft) || m.Known(StatusDraft) {
+		t.Error("draft is not in the custom model")
+	}
+	if err := m.CheckTransition("w-1", "in_review", "completed"); err == nil || !strings.Contains(err.Error(), "cannot move from in_review to completed") {
+		t.Errorf("CheckTransition() error = %v", err)
+	}
+	if err := m.CheckTransition("w-1", "draft", "in_review"); err == nil || !strings.Contains(err.Error(), "does not define") {
+		t.Errorf("CheckTransition() from unknown status error = %v", err)
+	}
+}
+
+func TestStatusModelFromSettings_StatusesOnlyPrunesDefaults(t *testing.T) {
+	t.Parallel()
+	m, err := StatusModelFromSettings(map[string]string{
+		SettingWorkflowStatuses: "open,claimed,in_review,completed",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.CanTransition(StatusClaimed, StatusInReview) || m.CanTransition(StatusClaimed, StatusDraft) {
+		t.Error("default transitions should be kept only between listed statuses")
+	}
+	if !m.Closed(StatusCompleted) || m.Closed(StatusWithdrawn) {
+		t.Error("closed statuses should be pruned to listed statuses")
+	}
+}
+
+func TestStatusModelFromSettings_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := map[string]map[string]string{
+		"missing open":          {SettingWorkflowStatuses: "claimed,completed"},
+		"malformed transition":  {SettingWorkflowTransitions: "open-claimed"},
+		"unknown in transition": {SettingWorkflowTransitions: "open>archived"},
+		"unknown closed":        {SettingWorkflowClosed: "archived"},
+	}
+	for name, settings := range tests {
+		if _, err := StatusModelFromSettings(settings); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TownStats is one town's contribution to the wasteland, as ranked by
+// gt wl stats --towns.
+type TownStats struct {
+	Handle string
+
+	// Posted counts wanted items the town posted.
+	Posted int
+	// Claimed counts wanted items the town holds or held to completion.
+	Claimed int
+	// Submitted counts the town's current (non-superseded) completions.
+	Submitted int
+	// Accepted counts those of Submitted a reviewer has validated.
+	Accepted int
+}
+
+// QueryTownStats aggregates posted, claimed, submitted, and accepted
+// counts per town, ranked by accepted completions, then submitted, then
+// claimed, then posted. A non-empty handle limits the result to that town.
+func QueryTownStats(townRoot, handle string) ([]TownStats, error) {
+	output, err := doltSQLQuery(townRoot, buildTownStatsQuery(handle))
+	if err != nil {
+		return nil, fmt.Errorf("querying town stats: %w", err)
+	}
+	var stats []TownStats
+	for _, row := range parseSimpleCSV(output) {
+		s := TownStats{Handle: row["handle"]}
+		s.Posted, _ = strconv.Atoi(row["posted"])
+		s.Claimed, _ = strconv.Atoi(row["claimed"])
+		s.Submitted, _ = strconv.Atoi(row["submitted"])
+		s.Accepted, _ = strconv.Atoi(row["accepted"])
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// buildTownStatsQuery counts each measure with its own GROUP BY and sums
+// the per-town rows, so the database does the counting.
+func buildTownStatsQuery(handle string) string {
+	where := ""
+	if handle != "" {
+		where = fmt.Sprintf(" WHERE handle = '%s'", EscapeSQL(handle))
+	}
+	return fmt.Sprintf(`USE %s; SELECT handle, SUM(posted) AS posted, SUM(claimed) AS claimed, SUM(submitted) AS submitted, SUM(accepted) AS accepted FROM (`+
+		`SELECT posted_by AS handle, COUNT(*) AS posted, 0 AS claimed, 0 AS submitted, 0 AS accepted FROM wanted WHERE posted_by IS NOT NULL AND posted_by <> '' GROUP BY posted_by`+
+		` UNION ALL SELECT claimed_by, 0, COUNT(*), 0, 0 FROM wanted WHERE claimed_by IS NOT NULL AND claimed_by <> '' GROUP BY claimed_by`+
+		` UNION ALL SELECT completed_by, 0, 0, COUNT(*), SUM(CASE WHEN validated_by IS NOT NULL AND validated_by <> '' THEN 1 ELSE 0 END) FROM completions WHERE superseded_by IS NULL OR superseded_by = '' GROUP BY completed_by`+
+		`) t%s GROUP BY handle ORDER BY accepted DESC, submitted DESC, claimed DESC, posted DESC, handle ASC;`,
+		WLCommonsDB, where)
+}
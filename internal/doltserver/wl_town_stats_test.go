@@ -0,0 +1,26 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildTownStatsQuery(t *testing.T) {
+	t.Parallel()
+	q := buildTownStatsQuery("")
+	for _, want := range []string{
+		"FROM wanted WHERE posted_by IS NOT NULL AND posted_by <> '' GROUP BY posted_by",
+		"FROM wanted WHERE claimed_by IS NOT NULL AND claimed_by <> '' GROUP BY claimed_by",
+		"FROM completions WHERE superseded_by IS NULL OR superseded_by = '' GROUP BY completed_by",
+		") t GROUP BY handle ORDER BY accepted DESC, submitted DESC, claimed DESC, posted DESC, handle ASC;",
+	} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query missing %q:\n%s", want, q)
+		}
+	}
+
+	q = buildTownStatsQuery("o'rig")
+	if !strings.Contains(q, ") t WHERE handle = 'o''rig' GROUP BY handle") {
+		t.Errorf("town filter not applied or not escaped:\n%s", q)
+	}
+}
@@ -0,0 +1,46 @@
+package doltserver
+
+import "fmt"
+
+// UnclaimWanted releases the claim on a claimed wanted item and returns it
+// to the board as open, clearing the claimant, group, lease, and claim time. Only a rig
+// holding the claim may release it unless force is set, which lets any rig
+// clear abandoned work. Items already in review are never released: a
+// completion has been submitted against the claim.
+func UnclaimWanted(townRoot, wantedID, rigHandle string, force bool) error {
+	err := doltSQLScriptWithRetry(townRoot, UnclaimWantedScript(wantedID, rigHandle, force))
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		if force {
+			return fmt.Errorf("wanted item %q is not claimed or does not exist", wantedID)
+		}
+		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+	}
+	return fmt.Errorf("unclaim failed: %w", err)
+}
+
+// UnclaimWantedScript returns the SQL script UnclaimWanted executes.
+// ROW_COUNT() gates the history insert, so an item that is not claimed (or
+// not held by rigHandle) leaves nothing to commit.
+func UnclaimWantedScript(wantedID, rigHandle string, force bool) string {
+	cond := "status='" + StatusClaimed + "'"
+	historyDetail, commitDetail := "NULL", ""
+	if force {
+		historyDetail, commitDetail = "'forced'", "forced"
+	} else {
+		cond += " AND " + claimHolderCond(rigHandle)
+	}
+	return fmt.Sprintf(`USE %s;
+UPDATE wanted SET assignee=NULL, claimed_by=NULL, claimed_via=NULL, claimed_group=NULL, expires_at=NULL, claimed_at=NULL, status='%s', updated_at=NOW()
+  WHERE id='%s' AND %s;
+SET @released = ROW_COUNT();
+INSERT INTO wanted_history (id, wanted_id, action, actor, detail, created_at)
+  SELECT UUID(), '%s', 'unclaim', '%s', %s, NOW() FROM dual WHERE @released > 0;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB, StatusOpen, EscapeSQL(wantedID), cond,
+		EscapeSQL(wantedID), EscapeSQL(rigHandle), historyDetail,
+		EscapeSQL(wlCommitMessage("unclaim", wantedID, rigHandle, commitDetail)))
+}
@@ -0,0 +1,83 @@
+// Package doltserver - wl_watchers.go manages subscriptions to wanted items.
+package doltserver
+
+import "fmt"
+
+// WantedWatcher is a row in the wl_watchers table: a rig that wants to hear
+// about changes to a wanted item it did not claim.
+type WantedWatcher struct {
+	RigHandle string
+	// Address is the mail address notifications are sent to.
+	Address   string
+	CreatedAt string
+}
+
+// AddWatcher subscribes rigHandle to wantedID, delivering notifications to
+// address. Re-subscribing updates the address; re-subscribing with the same
+// address is a no-op.
+//
+// The insert selects from wanted, so an unknown ID also writes nothing;
+// callers that need to tell the two apart should check the item first.
+func AddWatcher(townRoot, wantedID, rigHandle, address string) error {
+	if address == "" {
+		return fmt.Errorf("watcher address cannot be empty")
+	}
+	script := fmt.Sprintf(`USE %s;
+INSERT INTO wl_watchers (wanted_id, rig_handle, address, created_at)
+  SELECT id, '%s', '%s', NOW() FROM wanted WHERE id='%s'
+  ON DUPLICATE KEY UPDATE address=VALUES(address);
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB,
+		EscapeSQL(rigHandle), EscapeSQL(address), EscapeSQL(wantedID),
+		EscapeSQL(wlCommitMessage("subscribe", wantedID, rigHandle)))
+
+	err := doltSQLScriptWithRetry(townRoot, script)
+	if err == nil || isNothingToCommit(err) {
+		return nil
+	}
+	return fmt.Errorf("subscribing: %w", err)
+}
+
+// RemoveWatcher unsubscribes rigHandle from wantedID.
+func RemoveWatcher(townRoot, wantedID, rigHandle string) error {
+	script := fmt.Sprintf(`USE %s;
+DELETE FROM wl_watchers WHERE wanted_id='%s' AND rig_handle='%s';
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', '%s');
+`, WLCommonsDB,
+		EscapeSQL(wantedID), EscapeSQL(rigHandle),
+		EscapeSQL(wlCommitMessage("unsubscribe", wantedID, rigHandle)))
+
+	err := doltSQLScriptWithRetry(townRoot, script)
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("rig %q is not subscribed to %q", rigHandle, wantedID)
+	}
+	return fmt.Errorf("unsubscribing: %w", err)
+}
+
+// QueryWatchers returns the rigs subscribed to wantedID, oldest first. A
+// database without the wl_watchers table has no watchers.
+func QueryWatchers(townRoot, wantedID string) ([]WantedWatcher, error) {
+	query := fmt.Sprintf(`USE %s; SELECT rig_handle, address, COALESCE(created_at, '') as created_at FROM wl_watchers WHERE wanted_id='%s' ORDER BY created_at, rig_handle;`,
+		WLCommonsDB, EscapeSQL(wantedID))
+	output, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying watchers: %w", err)
+	}
+	var watchers []WantedWatcher
+	for _, r := range parseSimpleCSV(output) {
+		watchers = append(watchers, WantedWatcher{
+			RigHandle: r["rig_handle"],
+			Address:   r["address"],
+			CreatedAt: r["created_at"],
+		})
+	}
+	return watchers, nil
+}
@@ -0,0 +1,186 @@
+package doltserver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// RigProfile is a rig's footprint on the wasteland, as shown by gt wl whois.
+type RigProfile struct {
+	Handle string
+
+	// Registered is false when the rig has no rigs row; the registry
+	// fields are then empty but its work is still reported.
+	Registered   bool
+	DisplayName  string
+	RigType      string
+	TrustLevel   int
+	RegisteredAt time.Time
+	LastSeen     time.Time
+
+	// Skills are the skill tags of stamps the rig has received, most
+	// frequent first.
+	Skills []SkillCount
+
+	// Claimed lists items the rig currently holds (claimed, draft, or in
+	// review), most urgent first.
+	Claimed []*WantedItem
+
+	// RecentCompletions are the rig's latest current completions, newest
+	// first.
+	RecentCompletions []RigCompletion
+
+	Standing LeaderboardStanding
+	Badges   []string
+}
+
+// SkillCount is how many stamps carried a skill tag.
+type SkillCount struct {
+	Tag   string
+	Count int
+}
+
+// RigCompletion is one completion submitted by a rig.
+type RigCompletion struct {
+	ID          string
+	WantedID    string
+	Title       string
+	CompletedAt time.Time
+	Validated   bool
+}
+
+// LeaderboardStanding is a rig's place among rigs ranked by current
+// (non-superseded) completions. Rank is 1-based and 0 when the rig has
+// none; ties share a rank.
+type LeaderboardStanding struct {
+	Completions int
+	Rank        int
+	RankedRigs  int
+}
+
+// QueryRigProfile gathers handle's profile from the rigs, wanted,
+// completions, stamps, and badges tables. recent caps RecentCompletions.
+// Tables missing from older schemas are skipped.
+func QueryRigProfile(townRoot, handle string, recent int) (*RigProfile, error) {
+	h := EscapeSQL(handle)
+	p := &RigProfile{Handle: handle}
+
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT COALESCE(display_name, '') AS display_name, COALESCE(rig_type, '') AS rig_type, COALESCE(trust_level, 0) AS trust_level, COALESCE(registered_at, '') AS registered_at, COALESCE(last_seen, '') AS last_seen FROM rigs WHERE handle = '%s';`,
+		WLCommonsDB, h))
+	if err != nil {
+		return nil, fmt.Errorf("querying rig: %w", err)
+	}
+	if rows := parseSimpleCSV(output); len(rows) > 0 {
+		row := rows[0]
+		p.Registered = true
+		p.DisplayName = row["display_name"]
+		p.RigType = row["rig_type"]
+		p.TrustLevel, _ = strconv.Atoi(row["trust_level"])
+		p.RegisteredAt = parseDoltTimestamp(row["registered_at"])
+		p.LastSeen = parseDoltTimestamp(row["last_seen"])
+	}
+
+	output, err = doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT id, title, status, priority FROM wanted WHERE claimed_by = '%s' AND status IN ('%s', '%s', '%s') ORDER BY priority ASC, created_at ASC;`,
+		WLCommonsDB, h, StatusClaimed, StatusDraft, StatusInReview))
+	if err != nil {
+		return nil, fmt.Errorf("querying claimed items: %w", err)
+	}
+	for _, row := range parseSimpleCSV(output) {
+		priority, _ := strconv.Atoi(row["priority"])
+		p.Claimed = append(p.Claimed, &WantedItem{ID: row["id"], Title: row["title"], Status: row["status"], Priority: priority})
+	}
+
+	output, err = doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT c.id, c.wanted_id, COALESCE(w.title, '') AS title, COALESCE(c.completed_at, '') AS completed_at, COALESCE(c.validated_by, '') AS validated_by FROM completions c LEFT JOIN wanted w ON w.id = c.wanted_id WHERE c.completed_by = '%s' AND (c.superseded_by IS NULL OR c.superseded_by = '') ORDER BY c.completed_at DESC LIMIT %d;`,
+		WLCommonsDB, h, recent))
+	if err != nil {
+		return nil, fmt.Errorf("querying completions: %w", err)
+	}
+	for _, row := range parseSimpleCSV(output) {
+		p.RecentCompletions = append(p.RecentCompletions, RigCompletion{
+			ID:          row["id"],
+			WantedID:    row["wanted_id"],
+			Title:       row["title"],
+			CompletedAt: parseDoltTimestamp(row["completed_at"]),
+			Validated:   row["validated_by"] != "",
+		})
+	}
+
+	output, err = doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT completed_by, COUNT(*) AS n FROM completions WHERE superseded_by IS NULL OR superseded_by = '' GROUP BY completed_by;`,
+		WLCommonsDB))
+	if err != nil {
+		return nil, fmt.Errorf("querying leaderboard: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, row := range parseSimpleCSV(output) {
+		counts[row["completed_by"]], _ = strconv.Atoi(row["n"])
+	}
+	p.Standing = leaderboardStanding(counts, handle)
+
+	output, err = doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT COALESCE(skill_tags, '') AS skill_tags FROM stamps WHERE subject = '%s';`,
+		WLCommonsDB, h))
+	if err != nil && !isTableNotFound(err) {
+		return nil, fmt.Errorf("querying stamps: %w", err)
+	}
+	var tagLists [][]string
+	for _, row := range parseSimpleCSV(output) {
+		tagLists = append(tagLists, parseTagsJSON(row["skill_tags"]))
+	}
+	p.Skills = countSkillTags(tagLists)
+
+	output, err = doltSQLQuery(townRoot, fmt.Sprintf(
+		`USE %s; SELECT badge_type FROM badges WHERE rig_handle = '%s' ORDER BY awarded_at ASC;`,
+		WLCommonsDB, h))
+	if err != nil && !isTableNotFound(err) {
+		return nil, fmt.Errorf("querying badges: %w", err)
+	}
+	for _, row := range parseSimpleCSV(output) {
+		p.Badges = append(p.Badges, row["badge_type"])
+	}
+
+	return p, nil
+}
+
+// leaderboardStanding ranks handle among counts (rig → completions). Rigs
+// with equal counts share a rank.
+func leaderboardStanding(counts map[string]int, handle string) LeaderboardStanding {
+	s := LeaderboardStanding{Completions: counts[handle], RankedRigs: len(counts)}
+	if s.Completions == 0 {
+		return s
+	}
+	s.Rank = 1
+	for rig, n := range counts {
+		if rig != handle && n > s.Completions {
+			s.Rank++
+		}
+	}
+	return s
+}
+
+// countSkillTags tallies tags across stamps, most frequent first, then by
+// name.
+func countSkillTags(tagLists [][]string) []SkillCount {
+	counts := make(map[string]int)
+	for _, tags := range tagLists {
+		for _, tag := range tags {
+			counts[tag]++
+		}
+	}
+	skills := make([]SkillCount, 0, len(counts))
+	for tag, n := range counts {
+		skills = append(skills, SkillCount{Tag: tag, Count: n})
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Count != skills[j].Count {
+			return skills[i].Count > skills[j].Count
+		}
+		return skills[i].Tag < skills[j].Tag
+	})
+	return skills
+}
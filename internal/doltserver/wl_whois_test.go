@@ -0,0 +1,35 @@
+package doltserver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLeaderboardStanding(t *testing.T) {
+	t.Parallel()
+	counts := map[string]int{"ace": 9, "bee": 4, "cat": 4, "dog": 1}
+	tests := []struct {
+		handle string
+		want   LeaderboardStanding
+	}{
+		{"ace", LeaderboardStanding{Completions: 9, Rank: 1, RankedRigs: 4}},
+		{"bee", LeaderboardStanding{Completions: 4, Rank: 2, RankedRigs: 4}},
+		{"cat", LeaderboardStanding{Completions: 4, Rank: 2, RankedRigs: 4}},
+		{"dog", LeaderboardStanding{Completions: 1, Rank: 4, RankedRigs: 4}},
+		{"newcomer", LeaderboardStanding{Completions: 0, Rank: 0, RankedRigs: 4}},
+	}
+	for _, tt := range tests {
+		if got := leaderboardStanding(counts, tt.handle); got != tt.want {
+			t.Errorf("leaderboardStanding(%q) = %+v, want %+v", tt.handle, got, tt.want)
+		}
+	}
+}
+
+func TestCountSkillTags(t *testing.T) {
+	t.Parallel()
+	got := countSkillTags([][]string{{"go", "sql"}, {"go"}, nil, {"rust", "sql", "go"}})
+	want := []SkillCount{{"go", 3}, {"sql", 2}, {"rust", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("countSkillTags() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrWriteQueued is returned, wrapped, by a write that could not reach a
+// remote server and was queued for replay instead (see SetWLWriteQueue).
+var ErrWriteQueued = errors.New("dolt server unreachable; the write was queued, run 'gt wl sync' to replay it")
+
+// wlWriteQueue, when set, receives write scripts that could not reach a
+// remote server (see SetWLWriteQueue).
+var wlWriteQueue func(townRoot, script string) error
+
+// SetWLWriteQueue makes every write script that follows hand itself to
+// queue when the town's server is remote and cannot be reached, even after
+// the network retries, and fail with ErrWriteQueued rather than the network
+// error. SQL errors, and failures against a local server, are returned as
+// before. A nil queue restores normal execution.
+func SetWLWriteQueue(queue func(townRoot, script string) error) {
+	wlWriteQueue = queue
+}
+
+// queueUnreachableWrite queues script when err says the remote server
+// could not be reached, returning the error to report in its place.
+func queueUnreachableWrite(townRoot, script string, err error) error {
+	if wlWriteQueue == nil || !isDoltNetworkError(err) || !DefaultConfig(townRoot).IsRemote() {
+		return err
+	}
+	if qerr := wlWriteQueue(townRoot, script); qerr != nil {
+		return fmt.Errorf("%w (queueing the write for replay also failed: %v)", err, qerr)
+	}
+	return fmt.Errorf("%w: %v", ErrWriteQueued, err)
+}
+
+// ReplayWLWrite runs a queued write script with every NOW() pinned to
+// queuedAt, so the rows it writes carry the time the write was made rather
+// than the time it was replayed. It is never queued again. applied is false,
+// with no error, when the script no longer changes anything, e.g. because
+// the item was claimed by someone else in the meantime.
+func ReplayWLWrite(townRoot, script string, queuedAt time.Time) (applied bool, err error) {
+	err = retryDoltSQLScript(townRoot, pinSQLNow(script, queuedAt))
+	if isNothingToCommit(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// pinSQLNow replaces each NOW() call in script with at as a DATETIME
+// literal. Quoted strings and identifiers are left alone, so evidence or a
+// title that mentions NOW() is not rewritten.
+func pinSQLNow(script string, at time.Time) string {
+	const call = "NOW()"
+	literal := bindLiteral(at)
+
+	var b strings.Builder
+	b.Grow(len(script))
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			b.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(script) {
+				i++
+				b.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case strings.HasPrefix(script[i:], call):
+			b.WriteString(literal)
+			i += len(call) - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubWriteQueue installs a write queue that records what it is given.
+func stubWriteQueue(t *testing.T) *[]string {
+	t.Helper()
+	var queued []string
+	SetWLWriteQueue(func(_, script string) error {
+		queued = append(queued, script)
+		return nil
+	})
+	t.Cleanup(func() { SetWLWriteQueue(nil) })
+	return &queued
+}
+
+func TestDoltSQLScript_QueuesUnreachableRemoteWrite(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+	stubReconnect(t, func(string) error { return nil })
+	SetSQLRetries(0)
+	t.Cleanup(func() { SetSQLRetries(-1) })
+	queued := stubWriteQueue(t)
+	stubDoltRuns(t, "ERR dial tcp 203.0.113.7:3306: connect: connection refused")
+
+	script := "UPDATE wanted SET status='claimed' WHERE id='w-1';"
+	err := doltSQLScriptWithRetry(t.TempDir(), script)
+	if !errors.Is(err, ErrWriteQueued) {
+		t.Fatalf("doltSQLScriptWithRetry() error = %v, want ErrWriteQueued", err)
+	}
+	if !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("error %q lost the network failure", err)
+	}
+	if len(*queued) != 1 || (*queued)[0] != script {
+		t.Errorf("queued = %q, want the script once", *queued)
+	}
+}
+
+func TestDoltSQLScript_DoesNotQueueOtherFailures(t *testing.T) {
+	stubReconnect(t, func(string) error { return nil })
+	SetSQLRetries(0)
+	t.Cleanup(func() { SetSQLRetries(-1) })
+	queued := stubWriteQueue(t)
+
+	t.Run("SQL error on a remote server", func(t *testing.T) {
+		t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+		stubDoltRuns(t, "ERR duplicate primary key given: [c-1]")
+		if err := doltSQLScriptWithRetry(t.TempDir(), "INSERT INTO completions (id) VALUES ('c-1');"); errors.Is(err, ErrWriteQueued) {
+			t.Errorf("SQL error was queued: %v", err)
+		}
+	})
+	t.Run("local server down", func(t *testing.T) {
+		t.Setenv("GT_DOLT_HOST", "")
+		stubDoltRuns(t, "ERR dial tcp 127.0.0.1:3307: connect: connection refused")
+		if err := doltSQLScriptWithRetry(t.TempDir(), "UPDATE wanted SET status='open';"); errors.Is(err, ErrWriteQueued) {
+			t.Errorf("local failure was queued: %v", err)
+		}
+	})
+	if len(*queued) != 0 {
+		t.Errorf("queued = %q, want nothing", *queued)
+	}
+}
+
+func TestPinSQLNow(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	script := BindSQL("UPDATE wanted SET claimed_at=NOW(), updated_at=NOW() WHERE id=?;\nINSERT INTO wanted_history (detail, created_at) SELECT ?, NOW();",
+		"w-1", "it's NOW() or never")
+
+	got := pinSQLNow(script, at)
+	want := "UPDATE wanted SET claimed_at='2026-03-01 11:30:00', updated_at='2026-03-01 11:30:00' WHERE id='w-1';\n" +
+		"INSERT INTO wanted_history (detail, created_at) SELECT 'it''s NOW() or never', '2026-03-01 11:30:00';"
+	if got != want {
+		t.Errorf("pinSQLNow() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReplayWLWrite(t *testing.T) {
+	stubReconnect(t, func(string) error { return nil })
+	SetSQLRetries(0)
+	t.Cleanup(func() { SetSQLRetries(-1) })
+	queued := stubWriteQueue(t)
+
+	var ran string
+	reply := "ERR nothing to commit"
+	orig := runDoltCmd
+	t.Cleanup(func() { runDoltCmd = orig })
+	runDoltCmd = func(cmd *exec.Cmd) ([]byte, error) {
+		for i, arg := range cmd.Args {
+			if arg == "--file" && i+1 < len(cmd.Args) {
+				data, _ := os.ReadFile(cmd.Args[i+1])
+				ran = string(data)
+			}
+		}
+		if msg, ok := strings.CutPrefix(reply, "ERR "); ok {
+			return []byte(msg), errors.New("exit status 1")
+		}
+		return []byte(reply), nil
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	applied, err := ReplayWLWrite(t.TempDir(), "UPDATE wanted SET updated_at=NOW();", at)
+	if err != nil || applied {
+		t.Errorf("ReplayWLWrite(nothing to commit) = %v, %v; want false, nil", applied, err)
+	}
+	if ran != "UPDATE wanted SET updated_at='2026-03-01 12:00:00';" {
+		t.Errorf("ran %q, want NOW() pinned to the queue time", ran)
+	}
+
+	reply = ""
+	if applied, err := ReplayWLWrite(t.TempDir(), "UPDATE wanted SET updated_at=NOW();", at); err != nil || !applied {
+		t.Errorf("ReplayWLWrite() = %v, %v; want true, nil", applied, err)
+	}
+
+	t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+	reply = "ERR dial tcp 203.0.113.7:3306: connect: connection refused"
+	if _, err := ReplayWLWrite(t.TempDir(), "UPDATE wanted SET updated_at=NOW();", at); err == nil || errors.Is(err, ErrWriteQueued) {
+		t.Errorf("ReplayWLWrite(unreachable) error = %v, want the network error, not a requeue", err)
+	}
+	if len(*queued) != 0 {
+		t.Errorf("replay queued %q, want nothing", *queued)
+	}
+}
@@ -919,6 +919,19 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// NotesShow returns the note attached to ref under the default notes ref,
+// or "" when ref has no note.
+func (g *Git) NotesShow(ref string) (string, error) {
+	out, err := g.run("notes", "show", ref)
+	if err != nil {
+		if strings.Contains(err.Error(), "no note found") {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -298,6 +298,36 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestNotesShow(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	note, err := g.NotesShow("HEAD")
+	if err != nil {
+		t.Fatalf("NotesShow without a note: %v", err)
+	}
+	if note != "" {
+		t.Errorf("note = %q, want empty", note)
+	}
+
+	cmd := exec.Command("git", "-c", "user.email=test@test.com", "-c", "user.name=Test User", "notes", "add", "-m", "PR: https://example.com/pr/1", "HEAD")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git notes add: %v\n%s", err, out)
+	}
+	note, err = g.NotesShow("HEAD")
+	if err != nil {
+		t.Fatalf("NotesShow: %v", err)
+	}
+	if note != "PR: https://example.com/pr/1" {
+		t.Errorf("note = %q", note)
+	}
+
+	if _, err := g.NotesShow("no-such-ref"); err == nil {
+		t.Error("NotesShow on a bad ref should fail")
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
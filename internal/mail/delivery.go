@@ -2,8 +2,10 @@ package mail
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 )
@@ -14,17 +16,202 @@ const (
 	DeliveryStatePending = "pending"
 	// DeliveryStateAcked indicates receipt has been acknowledged.
 	DeliveryStateAcked = "acked"
+	// DeliveryStateExpired indicates a message sent with a TTL was not
+	// acknowledged before its expiry. A later ack still moves it to acked.
+	DeliveryStateExpired = "expired"
+	// DeliveryStateNacked indicates the recipient rejected the message or
+	// failed to process it (see DeliveryNackLabelSequence).
+	DeliveryStateNacked = "nacked"
 
 	// Label keys used for two-phase delivery tracking.
 	DeliveryLabelPending       = "delivery:pending"
 	DeliveryLabelAcked         = "delivery:acked"
 	DeliveryLabelAckedByPrefix = "delivery-acked-by:"
 	DeliveryLabelAckedAtPrefix = "delivery-acked-at:"
+
+	// DeliveryLabelSentAtPrefix records when the message was sent. It is
+	// informational: delivery:pending remains the marker that the message
+	// was durably written.
+	DeliveryLabelSentAtPrefix = "delivery-sent-at:"
+
+	// Label keys used for negative acknowledgement.
+	DeliveryLabelNacked           = "delivery:nacked"
+	DeliveryLabelNackedByPrefix   = "delivery-nacked-by:"
+	DeliveryLabelNackedAtPrefix   = "delivery-nacked-at:"
+	DeliveryLabelNackReasonPrefix = "delivery-nack-reason:"
+
+	// DeliveryLabelPendingForPrefix names one expected recipient of a
+	// fan-out delivery (see BuildFanoutSendLabels).
+	DeliveryLabelPendingForPrefix = "delivery-pending-for:"
 )
 
-// DeliverySendLabels returns labels written during phase-1 (send).
+// DeliverySendLabels returns labels written during phase-1 (send): the
+// pending state, the label schema version, and the send time (now).
 func DeliverySendLabels() []string {
-	return []string{DeliveryLabelPending}
+	return DeliverySendLabelsAt(timeNow())
+}
+
+// DeliverySendLabelsAt is DeliverySendLabels for a message sent at at.
+func DeliverySendLabelsAt(at time.Time) []string {
+	return []string{
+		DeliveryLabelPending,
+		DeliverySchemaLabel(DeliverySchemaVersion),
+		DeliverySentAtLabel(at),
+	}
+}
+
+// DeliverySentAtLabel returns the label recording that a message was sent
+// at t.
+func DeliverySentAtLabel(t time.Time) string {
+	return DeliveryLabelSentAtPrefix + t.UTC().Format(time.RFC3339)
+}
+
+// DeliverySendLabelsWithTTL returns DeliverySendLabels plus an expiry label
+// (see DeliveryExpiryLabel) ttl from now, after which an unacked message
+// parses as expired and SweepExpired picks it up. A ttl of zero or less
+// means no expiry.
+func DeliverySendLabelsWithTTL(ttl time.Duration) []string {
+	now := timeNow()
+	labels := DeliverySendLabelsAt(now)
+	if ttl > 0 {
+		labels = append(labels, DeliveryExpiryLabel(now.Add(ttl)))
+	}
+	return labels
+}
+
+// BuildFanoutSendLabels returns phase-1 labels for one message delivered to
+// many recipients: the usual send labels plus one pending-for label per
+// recipient. Recipients are trimmed, deduplicated, and sorted so the
+// recipient labels are stable across retries; empty entries are dropped.
+//
+// Each recipient acks with the normal DeliveryAckLabelSequence. Because that
+// sequence writes delivery:acked, ParseDeliveryLabels reports a fan-out
+// message as acked once any recipient acks; use ParseFanoutDeliveryLabels
+// for per-recipient accounting.
+func BuildFanoutSendLabels(recipients []string) []string {
+	seen := make(map[string]bool, len(recipients))
+	var unique []string
+	for _, r := range recipients {
+		r = strings.TrimSpace(r)
+		if r == "" || seen[r] {
+			continue
+		}
+		seen[r] = true
+		unique = append(unique, r)
+	}
+	sort.Strings(unique)
+
+	labels := make([]string, 0, len(unique)+3)
+	labels = append(labels, DeliverySendLabels()...)
+	for _, r := range unique {
+		labels = append(labels, DeliveryLabelPendingForPrefix+r)
+	}
+	return labels
+}
+
+// FanoutDeliveryStatus is per-recipient ack accounting for a fan-out
+// delivery. All slices are sorted.
+type FanoutDeliveryStatus struct {
+	// Recipients are the recipients named at send time.
+	Recipients []string
+	// Acked are the recipients that have acked.
+	Acked []string
+	// Pending are the recipients that have not acked yet.
+	Pending []string
+}
+
+// Complete reports whether every recipient has acked. A delivery with no
+// recipients is never complete.
+func (s FanoutDeliveryStatus) Complete() bool {
+	return len(s.Recipients) > 0 && len(s.Pending) == 0
+}
+
+// ParseFanoutDeliveryLabels reports which fan-out recipients have and
+// haven't acked. Like ParseDeliveryLabels it is order-independent. Acks from
+// identities that were not named at send time are ignored.
+func ParseFanoutDeliveryLabels(labels []string) FanoutDeliveryStatus {
+	expected := make(map[string]bool)
+	for _, label := range labels {
+		if strings.HasPrefix(label, DeliveryLabelPendingForPrefix) {
+			expected[strings.TrimPrefix(label, DeliveryLabelPendingForPrefix)] = true
+		}
+	}
+	acked := make(map[string]bool)
+	for _, ack := range ParseDeliveryAcks(labels) {
+		acked[ack.By] = true
+	}
+
+	var status FanoutDeliveryStatus
+	for r := range expected {
+		status.Recipients = append(status.Recipients, r)
+		if acked[r] {
+			status.Acked = append(status.Acked, r)
+		} else {
+			status.Pending = append(status.Pending, r)
+		}
+	}
+	sort.Strings(status.Recipients)
+	sort.Strings(status.Acked)
+	sort.Strings(status.Pending)
+	return status
+}
+
+// DeliveryAck is one recipient's acknowledgement of a message.
+type DeliveryAck struct {
+	// By is the acking recipient's identity.
+	By string
+	// At is when it acked, or nil when the labels don't say (see
+	// ParseDeliveryAcks).
+	At *time.Time
+}
+
+// ParseDeliveryAcks returns every recipient that has acked, once each, in
+// the order they first appear in labels.
+//
+// Acked-by and acked-at labels are paired positionally: each ack appends
+// DeliveryAckLabelSequence, so an acked-at belongs to the nearest acked-by
+// before it. That only holds while labels are in the order they were
+// appended; bd show --json returns them sorted, which puts every acked-at
+// ahead of every acked-by and loses the pairing. At is then nil, except
+// when there is a single acker, whose At is the earliest acked-at. A
+// recipient that acked more than once (a retry) keeps its earliest time.
+//
+// ParseDeliveryLabels still reports a single acker for compatibility; this
+// is the function fan-out delivery uses.
+func ParseDeliveryAcks(labels []string) []DeliveryAck {
+	var acks []DeliveryAck
+	index := make(map[string]int)
+	var earliest *time.Time
+	open := -1
+	for _, label := range labels {
+		switch {
+		case strings.HasPrefix(label, DeliveryLabelAckedByPrefix):
+			by := strings.TrimPrefix(label, DeliveryLabelAckedByPrefix)
+			i, ok := index[by]
+			if !ok {
+				i = len(acks)
+				index[by] = i
+				acks = append(acks, DeliveryAck{By: by})
+			}
+			open = i
+		case strings.HasPrefix(label, DeliveryLabelAckedAtPrefix):
+			t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, DeliveryLabelAckedAtPrefix))
+			if err != nil {
+				continue
+			}
+			if earliest == nil || t.Before(*earliest) {
+				earliest = &t
+			}
+			if open >= 0 && (acks[open].At == nil || t.Before(*acks[open].At)) {
+				acks[open].At = &t
+			}
+			open = -1
+		}
+	}
+	if len(acks) == 1 && acks[0].At == nil {
+		acks[0].At = earliest
+	}
+	return acks
 }
 
 // DeliveryAckLabelSequence returns labels for phase-2 (ack). The ordering is
@@ -39,6 +226,20 @@ func DeliveryAckLabelSequence(recipientIdentity string, at time.Time) []string {
 	}
 }
 
+// DeliveryNackLabelSequence returns labels for a negative ack. Like
+// DeliveryAckLabelSequence it writes the terminal delivery:nacked label
+// last, so a crash part-way leaves the message in its prior state. The
+// reason is collapsed onto one line.
+func DeliveryNackLabelSequence(recipientIdentity, reason string, at time.Time) []string {
+	nackedAt := at.UTC().Format(time.RFC3339)
+	return []string{
+		DeliveryLabelNackedByPrefix + recipientIdentity,
+		DeliveryLabelNackedAtPrefix + nackedAt,
+		DeliveryLabelNackReasonPrefix + strings.Join(strings.Fields(reason), " "),
+		DeliveryLabelNacked,
+	}
+}
+
 // DeliveryAckLabelSequenceIdempotent returns ack labels, reusing an existing
 // timestamp from existingLabels if one is present AND the recipient identity
 // matches. This ensures retries produce the exact same label set instead of
@@ -122,17 +323,87 @@ func readBeadLabelsShared(workDir, beadsDir, id string) ([]string, error) {
 	return bms[0].Labels, nil
 }
 
-// ParseDeliveryLabels derives delivery state and ack metadata from labels.
-// The state is append-only:
+// DeliveryRecord is everything the delivery labels on a message say about
+// its delivery. Ack and nack metadata is only set in the matching state, so
+// a half-written ack or nack leaves no trace.
+type DeliveryRecord struct {
+	// State is pending, acked, nacked, expired, or empty for a message
+	// without delivery tracking.
+	State string
+
+	// AckedBy and AckedAt name who acknowledged receipt, and when. Set only
+	// when State is acked.
+	AckedBy string
+	AckedAt *time.Time
+
+	// NackedBy, NackedAt, and NackReason name who rejected the message,
+	// when, and why. Set only when State is nacked.
+	NackedBy   string
+	NackedAt   *time.Time
+	NackReason string
+
+	// SentAt is when the message was sent, or nil for messages sent before
+	// the sent-at label existed. It is set in every state.
+	SentAt *time.Time
+
+	// ExpiresAt is when an unacked delivery expires, or nil without a TTL.
+	ExpiresAt *time.Time
+
+	// Attempt is the delivery attempt the message is on (see
+	// ParseDeliveryAttempts).
+	Attempt int
+
+	// DeadLetter is set once the sweeper has given up on the delivery.
+	DeadLetter bool
+
+	// Err reports every sent-at, acked-at, nacked-at, and expires-at label
+	// whose timestamp failed to parse. Those labels are otherwise ignored,
+	// so an acked record with a nil AckedAt and a nil Err had no timestamp
+	// written, while a non-nil Err means one was written but is corrupt,
+	// e.g. truncated by a partial write. Callers that don't care can
+	// ignore it.
+	Err error
+}
+
+// ParseDelivery derives a DeliveryRecord from labels. The state is
+// append-only:
 // - `delivery:pending` means pending
-// - once `delivery:acked` appears, state is acked (even if pending remains)
+// - past the `delivery-expires-at:` time with no ack, state is expired
+// - once `delivery:nacked` appears, state is nacked (expiry ignored)
+// - once `delivery:acked` appears, state is acked (all of the above ignored)
+//
+// Acked wins over nacked: a message processed successfully once stays
+// delivered, whatever a retry reported afterwards.
 //
 // Note: bd show --json returns labels in lexicographic order, so this parser
-// must be order-independent. It uses last-wins for both acked-by and acked-at.
-// For RFC3339 timestamps, lexicographic last-wins is chronologically correct.
-func ParseDeliveryLabels(labels []string) (state, ackedBy string, ackedAt *time.Time) {
+// must be order-independent. nacked-by and the nack reason are last-wins.
+// acked-at keeps the latest timestamp: a retried ack (say, a worker
+// re-acking after a crash) leaves another acked-at label, and the newest is
+// reported whatever order the labels come in. acked-by is the acker paired
+// with that timestamp when labels are in append order (each acked-by
+// directly precedes its acked-at); sorted labels lose the pairing, and the
+// last acked-by is used. sent-at keeps the earliest, the original send.
+// nacked-at keeps the latest, the most recent rejection. A retried send can
+// likewise leave two expires-at labels; the latest one counts.
+func ParseDelivery(labels []string) DeliveryRecord {
 	hasPending := false
 	hasAcked := false
+	hasNacked := false
+	var ackedBy, nackedBy, reason string
+	// openAcker is the acked-by awaiting its acked-at; latestAcker is the
+	// one paired with the latest acked-at so far.
+	var openAcker, latestAcker string
+	var ackedAt, nackedAt *time.Time
+	var errs []error
+	parseTime := func(label, prefix string) (time.Time, bool) {
+		t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, prefix))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("malformed delivery label %q: %w", label, err))
+			return time.Time{}, false
+		}
+		return t, true
+	}
+	rec := DeliveryRecord{Attempt: ParseDeliveryAttempts(labels)}
 
 	for _, label := range labels {
 		switch {
@@ -140,21 +411,85 @@ func ParseDeliveryLabels(labels []string) (state, ackedBy string, ackedAt *time.
 			hasPending = true
 		case label == DeliveryLabelAcked:
 			hasAcked = true
+		case label == DeliveryLabelNacked:
+			hasNacked = true
+		case label == DeliveryLabelDeadLetter:
+			rec.DeadLetter = true
 		case strings.HasPrefix(label, DeliveryLabelAckedByPrefix):
 			ackedBy = strings.TrimPrefix(label, DeliveryLabelAckedByPrefix)
+			openAcker = ackedBy
 		case strings.HasPrefix(label, DeliveryLabelAckedAtPrefix):
-			ts := strings.TrimPrefix(label, DeliveryLabelAckedAtPrefix)
-			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			if t, ok := parseTime(label, DeliveryLabelAckedAtPrefix); ok && (ackedAt == nil || t.After(*ackedAt)) {
 				ackedAt = &t
+				latestAcker = openAcker
+			}
+			openAcker = ""
+		case strings.HasPrefix(label, DeliveryLabelSentAtPrefix):
+			if t, ok := parseTime(label, DeliveryLabelSentAtPrefix); ok && (rec.SentAt == nil || t.Before(*rec.SentAt)) {
+				rec.SentAt = &t
+			}
+		case strings.HasPrefix(label, DeliveryLabelNackedByPrefix):
+			nackedBy = strings.TrimPrefix(label, DeliveryLabelNackedByPrefix)
+		case strings.HasPrefix(label, DeliveryLabelNackedAtPrefix):
+			if t, ok := parseTime(label, DeliveryLabelNackedAtPrefix); ok && (nackedAt == nil || t.After(*nackedAt)) {
+				nackedAt = &t
+			}
+		case strings.HasPrefix(label, DeliveryLabelNackReasonPrefix):
+			reason = strings.TrimPrefix(label, DeliveryLabelNackReasonPrefix)
+		case strings.HasPrefix(label, DeliveryLabelExpiresAtPrefix):
+			if t, ok := parseTime(label, DeliveryLabelExpiresAtPrefix); ok && (rec.ExpiresAt == nil || t.After(*rec.ExpiresAt)) {
+				rec.ExpiresAt = &t
 			}
 		}
 	}
+	rec.Err = errors.Join(errs...)
+	if latestAcker != "" {
+		ackedBy = latestAcker
+	}
 
-	if hasAcked {
-		return DeliveryStateAcked, ackedBy, ackedAt
+	switch {
+	case hasAcked:
+		rec.State, rec.AckedBy, rec.AckedAt = DeliveryStateAcked, ackedBy, ackedAt
+	case hasNacked:
+		rec.State, rec.NackedBy, rec.NackedAt, rec.NackReason = DeliveryStateNacked, nackedBy, nackedAt, reason
+	case rec.ExpiresAt != nil && timeNow().After(*rec.ExpiresAt):
+		rec.State = DeliveryStateExpired
+	case hasPending:
+		rec.State = DeliveryStatePending
 	}
-	if hasPending {
-		return DeliveryStatePending, "", nil
+	return rec
+}
+
+// Age is how long ago, as of now, the message was sent. ok is false when
+// the record has no sent-at time.
+func (r DeliveryRecord) Age(now time.Time) (age time.Duration, ok bool) {
+	if r.SentAt == nil {
+		return 0, false
+	}
+	return now.Sub(*r.SentAt), true
+}
+
+// Latency is how long the message took to be acknowledged: AckedAt minus
+// SentAt. ok is false unless the record is acked and has both times.
+func (r DeliveryRecord) Latency() (latency time.Duration, ok bool) {
+	if r.State != DeliveryStateAcked || r.SentAt == nil || r.AckedAt == nil {
+		return 0, false
+	}
+	return r.AckedAt.Sub(*r.SentAt), true
+}
+
+// ParseDeliveryLabels is ParseDelivery flattened to the state, who settled
+// it and when (the acker when acked, the nacker when nacked), and the nack
+// reason. It silently ignores malformed timestamps, which suits hot paths;
+// use ParseDelivery and check DeliveryRecord.Err to see them.
+// New code should use ParseDelivery.
+func ParseDeliveryLabels(labels []string) (state, by string, at *time.Time, reason string) {
+	rec := ParseDelivery(labels)
+	switch rec.State {
+	case DeliveryStateAcked:
+		return rec.State, rec.AckedBy, rec.AckedAt, ""
+	case DeliveryStateNacked:
+		return rec.State, rec.NackedBy, rec.NackedAt, rec.NackReason
 	}
-	return "", "", nil
+	return rec.State, "", nil, ""
 }
@@ -0,0 +1,200 @@
+package mail
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// deliveryTimePrefixes are the delivery labels that carry a timestamp.
+var deliveryTimePrefixes = []string{
+	DeliveryLabelSentAtPrefix,
+	DeliveryLabelAckedAtPrefix,
+	DeliveryLabelNackedAtPrefix,
+	DeliveryLabelExpiresAtPrefix,
+}
+
+// CompactDeliveryLabels collapses the delivery labels a long-lived message
+// has accumulated into one canonical set: each state label once, the
+// highest schema and attempt, the sent-at ParseDelivery keeps (earliest),
+// the expires-at and nacked-at it keeps (latest), the last nacked-by and
+// nack reason, and the winning acked-by/acked-at pair. Every other acker is
+// kept as a bare acked-by, so fan-out accounting still sees it, and
+// fan-out recipients are kept once each, sorted.
+//
+// ParseDelivery, ParseFanoutDeliveryLabels, and ParseDeliverySchema return
+// the same results for the compacted labels as for the originals, and
+// compacting twice changes nothing. Timestamps are kept as written, and
+// malformed timestamp labels are kept as they are (so DeliveryRecord.Err
+// still reports them); malformed attempt and schema labels, which nothing
+// reads, are dropped. Labels outside the delivery namespace come first, in
+// their original order. Per-recipient ack times (ParseDeliveryAcks) are
+// not preserved for any acker but the winner.
+//
+// DeliveryLabelDiff never removes delivery labels, so applying the result
+// means replacing the message's labels outright.
+func CompactDeliveryLabels(labels []string) []string {
+	var out, malformed, unknown []string
+	seenUnknown := make(map[string]bool)
+	recipients := make(map[string]bool)
+	var pending, acked, nacked, deadLetter bool
+	schema, attempt := -1, 0
+	for _, label := range labels {
+		switch {
+		case !isDeliveryLabel(label):
+			out = append(out, label)
+		case label == DeliveryLabelPending:
+			pending = true
+		case label == DeliveryLabelAcked:
+			acked = true
+		case label == DeliveryLabelNacked:
+			nacked = true
+		case label == DeliveryLabelDeadLetter:
+			deadLetter = true
+		case strings.HasPrefix(label, DeliveryLabelSchemaPrefix):
+			if n, err := strconv.Atoi(strings.TrimPrefix(label, DeliveryLabelSchemaPrefix)); err == nil && n > schema {
+				schema = n
+			}
+		case strings.HasPrefix(label, DeliveryLabelAttemptPrefix):
+			if n, err := strconv.Atoi(strings.TrimPrefix(label, DeliveryLabelAttemptPrefix)); err == nil && n > attempt {
+				attempt = n
+			}
+		case strings.HasPrefix(label, DeliveryLabelPendingForPrefix):
+			recipients[strings.TrimPrefix(label, DeliveryLabelPendingForPrefix)] = true
+		case isDeliveryTimeLabel(label):
+			if _, ok := parseDeliveryTimeLabel(label); !ok {
+				malformed = append(malformed, label)
+			}
+		case strings.HasPrefix(label, DeliveryLabelAckedByPrefix),
+			strings.HasPrefix(label, DeliveryLabelNackedByPrefix),
+			strings.HasPrefix(label, DeliveryLabelNackReasonPrefix):
+			// Rebuilt below from the parsed record.
+		default:
+			if !seenUnknown[label] {
+				seenUnknown[label] = true
+				unknown = append(unknown, label)
+			}
+		}
+	}
+
+	// Malformed timestamps go ahead of every acked-by, where they cannot
+	// break the pairing of an acked-by with the acked-at after it.
+	out = append(out, malformed...)
+	if pending {
+		out = append(out, DeliveryLabelPending)
+	}
+	if schema >= 0 {
+		out = append(out, DeliverySchemaLabel(schema))
+	}
+	if l := pickDeliveryTimeLabel(labels, DeliveryLabelSentAtPrefix, false); l != "" {
+		out = append(out, l)
+	}
+	if l := pickDeliveryTimeLabel(labels, DeliveryLabelExpiresAtPrefix, true); l != "" {
+		out = append(out, l)
+	}
+	if attempt > 0 {
+		out = append(out, DeliveryAttemptLabel(attempt))
+	}
+	names := make([]string, 0, len(recipients))
+	for r := range recipients {
+		names = append(names, r)
+	}
+	sort.Strings(names)
+	for _, r := range names {
+		out = append(out, DeliveryLabelPendingForPrefix+r)
+	}
+	out = append(out, unknown...)
+
+	// Parse as if the message had been nacked but not acked, so the nack
+	// metadata comes back whatever the real state is.
+	nack := ParseDelivery(append(withoutLabel(labels, DeliveryLabelAcked), DeliveryLabelNacked))
+	if nack.NackedBy != "" {
+		out = append(out, DeliveryLabelNackedByPrefix+nack.NackedBy)
+	}
+	if l := pickDeliveryTimeLabel(labels, DeliveryLabelNackedAtPrefix, true); l != "" {
+		out = append(out, l)
+	}
+	if nack.NackReason != "" {
+		out = append(out, DeliveryLabelNackReasonPrefix+nack.NackReason)
+	}
+	if nacked {
+		out = append(out, DeliveryLabelNacked)
+	}
+
+	// Likewise for the ack: the winner is who ParseDelivery would report
+	// once delivery:acked is present. Other ackers come first without a
+	// time, so the winner's acked-by is the one paired with the acked-at.
+	ack := ParseDelivery(append(append([]string{}, labels...), DeliveryLabelAcked))
+	for _, a := range ParseDeliveryAcks(labels) {
+		if a.By != ack.AckedBy {
+			out = append(out, DeliveryLabelAckedByPrefix+a.By)
+		}
+	}
+	if ack.AckedBy != "" {
+		out = append(out, DeliveryLabelAckedByPrefix+ack.AckedBy)
+	}
+	if l := pickDeliveryTimeLabel(labels, DeliveryLabelAckedAtPrefix, true); l != "" {
+		out = append(out, l)
+	}
+	if acked {
+		out = append(out, DeliveryLabelAcked)
+	}
+	if deadLetter {
+		out = append(out, DeliveryLabelDeadLetter)
+	}
+	return out
+}
+
+func isDeliveryTimeLabel(label string) bool {
+	for _, prefix := range deliveryTimePrefixes {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDeliveryTimeLabel parses the timestamp of a label with one of
+// deliveryTimePrefixes.
+func parseDeliveryTimeLabel(label string) (time.Time, bool) {
+	for _, prefix := range deliveryTimePrefixes {
+		if strings.HasPrefix(label, prefix) {
+			t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, prefix))
+			return t, err == nil
+		}
+	}
+	return time.Time{}, false
+}
+
+// pickDeliveryTimeLabel returns the label with prefix whose time
+// ParseDelivery keeps: the first latest when latest is set, else the first
+// earliest. It returns "" when no label with prefix parses.
+func pickDeliveryTimeLabel(labels []string, prefix string, latest bool) string {
+	var best string
+	var bestAt time.Time
+	for _, label := range labels {
+		if !strings.HasPrefix(label, prefix) {
+			continue
+		}
+		t, ok := parseDeliveryTimeLabel(label)
+		if !ok {
+			continue
+		}
+		if best == "" || (latest && t.After(bestAt)) || (!latest && t.Before(bestAt)) {
+			best, bestAt = label, t
+		}
+	}
+	return best
+}
+
+// withoutLabel returns a copy of labels with every drop removed.
+func withoutLabel(labels []string, drop string) []string {
+	out := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label != drop {
+			out = append(out, label)
+		}
+	}
+	return out
+}
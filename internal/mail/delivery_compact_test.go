@@ -0,0 +1,156 @@
+package mail
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCompactDeliveryLabels(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	orig := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = orig })
+
+	sent := DeliverySendLabelsAt(now.Add(-time.Hour))
+	tests := []struct {
+		name   string
+		labels []string
+		want   []string
+	}{
+		{
+			name:   "fresh send is already compact",
+			labels: sent,
+			want:   sent,
+		},
+		{
+			name: "retried send and ack collapse",
+			labels: concatLabels(
+				[]string{"from:mayor/"},
+				sent,
+				DeliverySendLabelsAt(now.Add(-30*time.Minute)),
+				[]string{DeliveryExpiryLabel(now.Add(time.Hour)), DeliveryExpiryLabel(now.Add(2 * time.Hour))},
+				[]string{DeliveryAttemptLabel(2), DeliveryAttemptLabel(3)},
+				DeliveryAckLabelSequence("gastown/worker", now.Add(-20*time.Minute)),
+				DeliveryAckLabelSequence("gastown/worker", now.Add(-10*time.Minute)),
+				[]string{"thread:t-1"},
+			),
+			want: []string{
+				"from:mayor/", "thread:t-1",
+				DeliveryLabelPending,
+				DeliverySchemaLabel(DeliverySchemaVersion),
+				DeliverySentAtLabel(now.Add(-time.Hour)),
+				DeliveryExpiryLabel(now.Add(2 * time.Hour)),
+				DeliveryAttemptLabel(3),
+				DeliveryLabelAckedByPrefix + "gastown/worker",
+				DeliveryLabelAckedAtPrefix + now.Add(-10*time.Minute).Format(time.RFC3339),
+				DeliveryLabelAcked,
+			},
+		},
+		{
+			name: "nack then ack keeps both",
+			labels: concatLabels(
+				sent,
+				DeliveryNackLabelSequence("gastown/worker", "busy", now.Add(-40*time.Minute)),
+				DeliveryNackLabelSequence("gastown/worker", "still busy", now.Add(-30*time.Minute)),
+				DeliveryAckLabelSequence("gastown/worker", now.Add(-10*time.Minute)),
+			),
+		},
+		{
+			name: "fan-out keeps every acker",
+			labels: concatLabels(
+				BuildFanoutSendLabels([]string{"b/", "a/", "c/"}),
+				DeliveryAckLabelSequence("a/", now.Add(-20*time.Minute)),
+				DeliveryAckLabelSequence("c/", now.Add(-10*time.Minute)),
+				DeliveryAckLabelSequence("a/", now.Add(-5*time.Minute)),
+			),
+		},
+		{
+			name: "sorted labels keep the last acker",
+			labels: sortedLabels(concatLabels(
+				sent,
+				DeliveryAckLabelSequence("gastown/a", now.Add(-10*time.Minute)),
+				DeliveryAckLabelSequence("gastown/b", now.Add(-20*time.Minute)),
+			)),
+		},
+		{
+			name: "partial ack stays pending",
+			labels: concatLabels(
+				sent,
+				[]string{DeliveryLabelAckedByPrefix + "gastown/worker", DeliveryLabelAckedAtPrefix + now.Format(time.RFC3339)},
+			),
+		},
+		{
+			name: "expired and dead-lettered",
+			labels: concatLabels(
+				sent,
+				[]string{DeliveryExpiryLabel(now.Add(-time.Minute)), DeliveryLabelDeadLetter, DeliveryLabelDeadLetter, DeliveryAttemptLabel(4)},
+			),
+		},
+		{
+			name: "offset timestamps are kept as written",
+			labels: []string{
+				DeliveryLabelPending,
+				DeliveryLabelSentAtPrefix + "2026-03-01T13:00:00+02:00",
+				DeliveryLabelSentAtPrefix + "2026-03-01T12:00:00Z",
+				DeliveryLabelAckedByPrefix + "gastown/worker",
+				DeliveryLabelAckedAtPrefix + "2026-03-01T14:00:00+02:00",
+				DeliveryLabelAcked,
+			},
+		},
+		{
+			name: "malformed timestamps are kept",
+			labels: concatLabels(
+				sent,
+				[]string{
+					DeliveryLabelAckedByPrefix + "gastown/worker",
+					DeliveryLabelAckedAtPrefix + "2026-03-01T12:0",
+					DeliveryLabelAckedAtPrefix + "2026-03-01T12:0",
+					DeliveryAttemptLabel(2),
+					DeliveryLabelAttemptPrefix + "x",
+					DeliveryLabelSchemaPrefix + "x",
+					DeliveryLabelAcked,
+				},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompactDeliveryLabels(tt.labels)
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompactDeliveryLabels() = %q, want %q", got, tt.want)
+			}
+
+			if before, after := ParseDelivery(tt.labels), ParseDelivery(got); !reflect.DeepEqual(before, after) {
+				t.Errorf("ParseDelivery changed:\nbefore %+v\nafter  %+v\nlabels %q", before, after, got)
+			}
+			if before, after := ParseFanoutDeliveryLabels(tt.labels), ParseFanoutDeliveryLabels(got); !reflect.DeepEqual(before, after) {
+				t.Errorf("ParseFanoutDeliveryLabels changed: before %+v, after %+v", before, after)
+			}
+			if before, after := ParseDeliverySchema(tt.labels), ParseDeliverySchema(got); before != after {
+				t.Errorf("ParseDeliverySchema changed: before %d, after %d", before, after)
+			}
+			if again := CompactDeliveryLabels(got); !reflect.DeepEqual(again, got) {
+				t.Errorf("compacting twice = %q, want %q", again, got)
+			}
+			if len(got) > len(tt.labels) {
+				t.Errorf("compacted to %d labels, more than the %d given", len(got), len(tt.labels))
+			}
+		})
+	}
+}
+
+func concatLabels(groups ...[]string) []string {
+	var out []string
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+func sortedLabels(labels []string) []string {
+	out := append([]string(nil), labels...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,49 @@
+package mail
+
+// Reasons a delivery is dead-lettered, as returned by
+// DeliveryRecord.DeadLetterReason.
+const (
+	// DeadLetterSwept means the sweeper gave up on the delivery and wrote
+	// DeliveryLabelDeadLetter.
+	DeadLetterSwept = "swept"
+	// DeadLetterExpired means the delivery outlived its TTL without an ack.
+	DeadLetterExpired = "expired"
+	// DeadLetterNacked means the recipient nacked the delivery on its last
+	// allowed attempt (MaxDeliveryAttempts).
+	DeadLetterNacked = "nacked"
+)
+
+// DeadLetterReason reports why the delivery failed for good, or "" when it
+// has not. The predicate, checked in order:
+//   - a delivery:dead-letter label is DeadLetterSwept, whatever else the
+//     labels say, since the sweeper only writes it once it has given up;
+//   - an acked delivery is never dead-lettered;
+//   - a nacked delivery on attempt MaxDeliveryAttempts or later is
+//     DeadLetterNacked (earlier nacks may still be retried);
+//   - an expired delivery (past its TTL with no ack or nack) is
+//     DeadLetterExpired;
+//   - anything else, including pending and untracked messages, is "".
+//
+// An expired delivery with attempts left is still reported: it failed
+// within its window, even if the sweeper will re-deliver it.
+func (r DeliveryRecord) DeadLetterReason() string {
+	switch {
+	case r.DeadLetter:
+		return DeadLetterSwept
+	case r.State == DeliveryStateAcked:
+		return ""
+	case r.State == DeliveryStateNacked && r.Attempt >= MaxDeliveryAttempts:
+		return DeadLetterNacked
+	case r.State == DeliveryStateExpired:
+		return DeadLetterExpired
+	}
+	return ""
+}
+
+// IsDeadLettered reports whether the delivery labels describe a message
+// whose delivery failed for good. It is ParseDelivery followed by
+// DeliveryRecord.DeadLetterReason; a scanner that already holds the record
+// should call the method instead of parsing twice.
+func IsDeadLettered(labels []string) bool {
+	return ParseDelivery(labels).DeadLetterReason() != ""
+}
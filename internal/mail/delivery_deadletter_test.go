@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadLetterReason(t *testing.T) {
+	now := time.Now()
+	past := DeliveryExpiryLabel(now.Add(-time.Hour))
+	future := DeliveryExpiryLabel(now.Add(time.Hour))
+	nack := DeliveryNackLabelSequence("gastown/worker", "bad payload", now)
+	ack := DeliveryAckLabelSequence("gastown/worker", now)
+	lastAttempt := DeliveryAttemptLabel(MaxDeliveryAttempts)
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   string
+	}{
+		{"untracked", nil, ""},
+		{"pending", DeliverySendLabels(), ""},
+		{"pending within ttl", []string{DeliveryLabelPending, future}, ""},
+		{"acked", append([]string{DeliveryLabelPending}, ack...), ""},
+		{"acked after expiry", append([]string{DeliveryLabelPending, past}, ack...), ""},
+		{"acked after last nack", append(append([]string{DeliveryLabelPending, lastAttempt}, nack...), ack...), ""},
+		{"expired", []string{DeliveryLabelPending, past}, DeadLetterExpired},
+		{"expired on last attempt", []string{DeliveryLabelPending, past, lastAttempt}, DeadLetterExpired},
+		{"nacked with attempts left", append([]string{DeliveryLabelPending}, nack...), ""},
+		{"nacked on last attempt", append([]string{DeliveryLabelPending, lastAttempt}, nack...), DeadLetterNacked},
+		{"nacked past expiry with attempts left", append([]string{DeliveryLabelPending, past}, nack...), ""},
+		{"swept", []string{DeliveryLabelPending, past, lastAttempt, DeliveryLabelDeadLetter}, DeadLetterSwept},
+		{"swept fan-out with a partial ack", append(append(BuildFanoutSendLabels([]string{"a/", "b/"}), past, DeliveryLabelDeadLetter),
+			DeliveryAckLabelSequence("a/", now)...), DeadLetterSwept},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseDelivery(tt.labels).DeadLetterReason(); got != tt.want {
+				t.Errorf("DeadLetterReason() = %q, want %q", got, tt.want)
+			}
+			if got := IsDeadLettered(tt.labels); got != (tt.want != "") {
+				t.Errorf("IsDeadLettered() = %v, want %v", got, tt.want != "")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package mail
+
+import "strings"
+
+// isDeliveryLabel reports whether label belongs to the delivery-tracking
+// namespace (delivery:* state labels and delivery-*: metadata labels).
+func isDeliveryLabel(label string) bool {
+	return strings.HasPrefix(label, "delivery:") || strings.HasPrefix(label, "delivery-")
+}
+
+// DeliveryLabelDiff returns the labels to add to and remove from have so
+// that it converges on want, for reconciling one message across two stores.
+//
+// Delivery labels are forward-only: once written they record something that
+// happened (a send, an ack, an attempt, a dead-letter), so they are never
+// removed, even when want lacks them. A want that is behind have therefore
+// produces no delivery removals and cannot regress an acked message to
+// pending. Other labels are diffed as plain sets.
+//
+// add keeps want's order, except that delivery:acked is moved last so that,
+// as with DeliveryAckLabelSequence, the state flips only after the ack
+// metadata is written. remove keeps have's order. Both are deduplicated.
+func DeliveryLabelDiff(have, want []string) (add, remove []string) {
+	haveSet := make(map[string]bool, len(have))
+	for _, l := range have {
+		haveSet[l] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, l := range want {
+		wantSet[l] = true
+	}
+
+	ackLast := false
+	seen := make(map[string]bool, len(want))
+	for _, l := range want {
+		if haveSet[l] || seen[l] {
+			continue
+		}
+		seen[l] = true
+		if l == DeliveryLabelAcked {
+			ackLast = true
+			continue
+		}
+		add = append(add, l)
+	}
+	if ackLast {
+		add = append(add, DeliveryLabelAcked)
+	}
+
+	seen = make(map[string]bool, len(have))
+	for _, l := range have {
+		if wantSet[l] || seen[l] || isDeliveryLabel(l) {
+			continue
+		}
+		seen[l] = true
+		remove = append(remove, l)
+	}
+	return add, remove
+}
@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDeliveryLabelDiff(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	acked := append([]string{DeliveryLabelPending}, DeliveryAckLabelSequence("gastown/worker", at)...)
+
+	tests := []struct {
+		name       string
+		have, want []string
+		add, rm    []string
+	}{
+		{
+			name: "converged",
+			have: acked,
+			want: acked,
+		},
+		{
+			name: "want ahead acks with acked label last",
+			have: []string{DeliveryLabelPending},
+			// bd returns labels sorted, putting delivery:acked first.
+			want: []string{DeliveryLabelAcked, DeliveryLabelPending, DeliveryLabelAckedAtPrefix + "2026-03-01T12:00:00Z", DeliveryLabelAckedByPrefix + "gastown/worker"},
+			add:  []string{DeliveryLabelAckedAtPrefix + "2026-03-01T12:00:00Z", DeliveryLabelAckedByPrefix + "gastown/worker", DeliveryLabelAcked},
+		},
+		{
+			name: "want behind have emits no regression",
+			have: acked,
+			want: []string{DeliveryLabelPending},
+		},
+		{
+			name: "want missing send state leaves delivery labels alone",
+			have: []string{DeliveryLabelPending, DeliveryAttemptLabel(2), DeliveryLabelDeadLetter},
+			want: nil,
+		},
+		{
+			name: "non-delivery labels diff as sets",
+			have: []string{"from:mayor/", "thread:t-1", "thread:t-1", DeliveryLabelPending},
+			want: []string{"from:mayor/", "cc:crew/", "cc:crew/", DeliveryLabelPending},
+			add:  []string{"cc:crew/"},
+			rm:   []string{"thread:t-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, rm := DeliveryLabelDiff(tt.have, tt.want)
+			if !reflect.DeepEqual(add, tt.add) {
+				t.Errorf("add = %q, want %q", add, tt.add)
+			}
+			if !reflect.DeepEqual(rm, tt.rm) {
+				t.Errorf("remove = %q, want %q", rm, tt.rm)
+			}
+
+			// Applying the diff never moves delivery state backwards.
+			applied := append(append([]string(nil), tt.have...), add...)
+			applied = withoutLabels(applied, rm)
+			haveState, _, _, _ := ParseDeliveryLabels(tt.have)
+			gotState, _, _, _ := ParseDeliveryLabels(applied)
+			if haveState == DeliveryStateAcked && gotState != DeliveryStateAcked {
+				t.Errorf("applying diff regressed state %q to %q", haveState, gotState)
+			}
+		})
+	}
+}
+
+func withoutLabels(labels, drop []string) []string {
+	dropSet := make(map[string]bool, len(drop))
+	for _, l := range drop {
+		dropSet[l] = true
+	}
+	var out []string
+	for _, l := range labels {
+		if !dropSet[l] {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Delivery label schema versions. The send constructors stamp each message
+// with the version that produced its labels, so readers of old messages can
+// tell which vocabulary to expect.
+//
+// Version history:
+//
+//	1  Unversioned labels: delivery:pending, delivery:acked,
+//	   delivery-acked-by:, delivery-acked-at:, delivery-pending-for:
+//	   (fan-out), delivery-expires-at:, delivery-attempt:, and
+//	   delivery:dead-letter. Messages with no delivery-schema label are
+//	   version 1.
+//	2  Adds delivery-schema:<n>, written at send time. The other labels
+//	   keep their version 1 meaning.
+//	3  Adds delivery-sent-at:<RFC3339>, written at send time.
+const (
+	// DeliverySchemaUnversioned is the schema of messages sent before
+	// labels carried a version.
+	DeliverySchemaUnversioned = 1
+
+	// DeliverySchemaVersion is the schema written by DeliverySendLabels and
+	// BuildFanoutSendLabels.
+	DeliverySchemaVersion = 3
+
+	// DeliveryLabelSchemaPrefix records the schema version of a message's
+	// delivery labels.
+	DeliveryLabelSchemaPrefix = "delivery-schema:"
+)
+
+// DeliverySchemaLabel returns the label recording schema version n.
+func DeliverySchemaLabel(n int) string {
+	return DeliveryLabelSchemaPrefix + strconv.Itoa(n)
+}
+
+// ParseDeliverySchema reports the schema version of a message's delivery
+// labels. Messages without a valid delivery-schema label are
+// DeliverySchemaUnversioned. If several are present (a message re-sent by a
+// newer gt), the highest wins, since labels are only ever added.
+func ParseDeliverySchema(labels []string) int {
+	version := DeliverySchemaUnversioned
+	for _, label := range labels {
+		if !strings.HasPrefix(label, DeliveryLabelSchemaPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(label, DeliveryLabelSchemaPrefix))
+		if err == nil && n > version {
+			version = n
+		}
+	}
+	return version
+}
@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DeliveryLabelExpiresAtPrefix records when an unacked delivery should
+	// be swept. Re-delivery appends a later one; the latest wins.
+	DeliveryLabelExpiresAtPrefix = "delivery-expires-at:"
+	// DeliveryLabelAttemptPrefix records which delivery attempt a message
+	// is on, starting at 1. The highest value wins.
+	DeliveryLabelAttemptPrefix = "delivery-attempt:"
+	// DeliveryLabelDeadLetter marks a delivery the sweeper gave up on.
+	DeliveryLabelDeadLetter = "delivery:dead-letter"
+
+	// MaxDeliveryAttempts is how many times a message is delivered before an
+	// expired, still-unacked delivery is dead-lettered instead of retried.
+	MaxDeliveryAttempts = 3
+)
+
+// DeliveryExpiryLabel returns the label that expires a delivery at t.
+func DeliveryExpiryLabel(t time.Time) string {
+	return DeliveryLabelExpiresAtPrefix + t.UTC().Format(time.RFC3339)
+}
+
+// DeliveryAttemptLabel returns the label recording delivery attempt n.
+func DeliveryAttemptLabel(n int) string {
+	return DeliveryLabelAttemptPrefix + strconv.Itoa(n)
+}
+
+// ParseDeliveryAttempts returns which delivery attempt a message is on: the
+// highest attempt label, since re-delivery appends labels rather than
+// replacing them and bd returns them in lexicographic order (where
+// "delivery-attempt:10" sorts before "delivery-attempt:2"). A message with
+// no valid attempt label is on attempt 1.
+func ParseDeliveryAttempts(labels []string) int {
+	attempt := 1
+	for _, label := range labels {
+		if !strings.HasPrefix(label, DeliveryLabelAttemptPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(label, DeliveryLabelAttemptPrefix))
+		if err == nil && n > attempt {
+			attempt = n
+		}
+	}
+	return attempt
+}
+
+// NextAttemptLabel returns the attempt label to append when re-delivering a
+// message with labels.
+func NextAttemptLabel(labels []string) string {
+	return DeliveryAttemptLabel(ParseDeliveryAttempts(labels) + 1)
+}
+
+// SweepDecision is what a reaper should do with a batch of deliveries.
+// Both slices hold message IDs in sorted order.
+type SweepDecision struct {
+	// Redeliver are expired, unacked messages with attempts left.
+	Redeliver []string
+	// DeadLetter are expired, unacked messages out of attempts.
+	DeadLetter []string
+}
+
+// SweepExpired decides which messages in labelsByID (message ID to labels)
+// have outlived their delivery TTL. Messages that are acked (for fan-out
+// deliveries, acked by every recipient), already dead-lettered, carry no
+// expiry, or have not yet expired at now are left alone. Expired messages
+// are re-delivered until they reach MaxDeliveryAttempts, then dead-lettered.
+//
+// SweepExpired does no I/O; callers read labels, act on the decision, and
+// write the follow-up labels (DeliveryAttemptLabel, DeliveryExpiryLabel,
+// DeliveryLabelDeadLetter) themselves.
+func SweepExpired(labelsByID map[string][]string, now time.Time) SweepDecision {
+	var d SweepDecision
+	for id, labels := range labelsByID {
+		rec := ParseDelivery(labels)
+		if rec.DeadLetter || rec.ExpiresAt == nil || now.Before(*rec.ExpiresAt) || deliveryComplete(labels, rec) {
+			continue
+		}
+		if rec.Attempt >= MaxDeliveryAttempts {
+			d.DeadLetter = append(d.DeadLetter, id)
+		} else {
+			d.Redeliver = append(d.Redeliver, id)
+		}
+	}
+	sort.Strings(d.Redeliver)
+	sort.Strings(d.DeadLetter)
+	return d
+}
+
+// deliveryComplete reports whether a delivery needs no more sweeping. A
+// fan-out delivery is complete only once every named recipient has acked.
+func deliveryComplete(labels []string, rec DeliveryRecord) bool {
+	if fanout := ParseFanoutDeliveryLabels(labels); len(fanout.Recipients) > 0 {
+		return fanout.Complete()
+	}
+	return rec.State == DeliveryStateAcked
+}
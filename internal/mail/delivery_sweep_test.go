@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSweepExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := DeliveryExpiryLabel(now.Add(-time.Minute))
+	future := DeliveryExpiryLabel(now.Add(time.Minute))
+	acked := DeliveryAckLabelSequence("gastown/worker", now.Add(-time.Hour))
+
+	labelsByID := map[string][]string{
+		"live":           {DeliveryLabelPending, future},
+		"no-ttl":         {DeliveryLabelPending},
+		"expired":        {DeliveryLabelPending, past},
+		"expired-at-now": {DeliveryLabelPending, DeliveryExpiryLabel(now)},
+		"acked":          append([]string{DeliveryLabelPending, past}, acked...),
+		"retry-extended": {DeliveryLabelPending, past, DeliveryAttemptLabel(2), future},
+		"second-try":     {DeliveryLabelPending, past, DeliveryAttemptLabel(2)},
+		"exhausted":      {DeliveryLabelPending, past, DeliveryAttemptLabel(2), DeliveryAttemptLabel(MaxDeliveryAttempts)},
+		"already-dead":   {DeliveryLabelPending, past, DeliveryAttemptLabel(MaxDeliveryAttempts), DeliveryLabelDeadLetter},
+		"bad-expiry":     {DeliveryLabelPending, DeliveryLabelExpiresAtPrefix + "soon"},
+		"fanout-partial": append(append(BuildFanoutSendLabels([]string{"a/", "b/"}), past),
+			DeliveryAckLabelSequence("a/", now)...),
+		"fanout-complete": append(append(append(BuildFanoutSendLabels([]string{"a/", "b/"}), past),
+			DeliveryAckLabelSequence("a/", now)...), DeliveryAckLabelSequence("b/", now)...),
+	}
+
+	got := SweepExpired(labelsByID, now)
+	want := SweepDecision{
+		Redeliver:  []string{"expired", "expired-at-now", "fanout-partial", "second-try"},
+		DeadLetter: []string{"exhausted"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SweepExpired() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSweepExpired_Empty(t *testing.T) {
+	got := SweepExpired(nil, time.Now())
+	if got.Redeliver != nil || got.DeadLetter != nil {
+		t.Fatalf("SweepExpired(nil) = %+v, want empty decision", got)
+	}
+}
+
+func TestParseDeliveryAttempts(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   int
+	}{
+		{"no attempt labels", DeliverySendLabels(), 1},
+		{"single", []string{"delivery-attempt:2"}, 2},
+		{"out of order", []string{"delivery-attempt:3", "delivery-attempt:1", "delivery-attempt:2"}, 3},
+		{"lexicographic", []string{"delivery-attempt:10", "delivery-attempt:2", "delivery-attempt:9"}, 10},
+		{"duplicates", []string{"delivery-attempt:2", "delivery-attempt:2", DeliveryLabelPending}, 2},
+		{"malformed ignored", []string{"delivery-attempt:x", "delivery-attempt:", "delivery-attempt:-4"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseDeliveryAttempts(tt.labels); got != tt.want {
+				t.Errorf("ParseDeliveryAttempts(%v) = %d, want %d", tt.labels, got, tt.want)
+			}
+			if got, want := NextAttemptLabel(tt.labels), DeliveryAttemptLabel(tt.want+1); got != want {
+				t.Errorf("NextAttemptLabel(%v) = %q, want %q", tt.labels, got, want)
+			}
+		})
+	}
+}
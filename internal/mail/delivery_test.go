@@ -2,6 +2,8 @@ package mail
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -21,7 +23,7 @@ func TestDeliveryAckLabelSequenceOrder(t *testing.T) {
 
 func TestParseDeliveryLabels_CrashAndRetryStates(t *testing.T) {
 	t.Run("pending only", func(t *testing.T) {
-		state, by, at := ParseDeliveryLabels([]string{
+		state, by, at, _ := ParseDeliveryLabels([]string{
 			DeliveryLabelPending,
 		})
 		if state != DeliveryStatePending {
@@ -33,7 +35,7 @@ func TestParseDeliveryLabels_CrashAndRetryStates(t *testing.T) {
 	})
 
 	t.Run("partial ack write keeps pending", func(t *testing.T) {
-		state, by, at := ParseDeliveryLabels([]string{
+		state, by, at, _ := ParseDeliveryLabels([]string{
 			DeliveryLabelPending,
 			"delivery-acked-by:gastown/worker",
 			"delivery-acked-at:2026-02-17T12:00:00Z",
@@ -47,7 +49,7 @@ func TestParseDeliveryLabels_CrashAndRetryStates(t *testing.T) {
 	})
 
 	t.Run("acked label flips state", func(t *testing.T) {
-		state, by, at := ParseDeliveryLabels([]string{
+		state, by, at, _ := ParseDeliveryLabels([]string{
 			DeliveryLabelPending,
 			"delivery-acked-by:gastown/worker",
 			"delivery-acked-at:2026-02-17T12:00:00Z",
@@ -66,7 +68,7 @@ func TestParseDeliveryLabels_CrashAndRetryStates(t *testing.T) {
 
 	t.Run("lexicographic label order still parses correctly", func(t *testing.T) {
 		// bd show --json returns labels in lexicographic order.
-		state, by, at := ParseDeliveryLabels([]string{
+		state, by, at, _ := ParseDeliveryLabels([]string{
 			"delivery-acked-at:2026-02-17T12:00:00Z",
 			"delivery-acked-by:gastown/worker",
 			"delivery:acked",
@@ -82,6 +84,66 @@ func TestParseDeliveryLabels_CrashAndRetryStates(t *testing.T) {
 			t.Fatal("ackedAt should be populated for acked state with lex-ordered labels")
 		}
 	})
+
+	t.Run("retried ack keeps latest timestamp", func(t *testing.T) {
+		// Retried acks leave several acked-at labels behind, in any order.
+		for _, labels := range [][]string{
+			{
+				"delivery-acked-at:2026-02-17T12:00:00Z",
+				"delivery-acked-at:2026-02-17T12:10:00Z",
+				"delivery-acked-at:2026-02-17T12:05:00Z",
+				"delivery-acked-by:gastown/worker",
+				"delivery:acked",
+			},
+			{
+				"delivery-acked-at:2026-02-17T12:05:00Z",
+				"delivery-acked-at:2026-02-17T12:10:00Z",
+				"delivery-acked-at:2026-02-17T12:00:00Z",
+				"delivery-acked-by:gastown/worker",
+				"delivery:acked",
+			},
+			{
+				"delivery-acked-at:2026-02-17T12:10:00Z",
+				"delivery-acked-at:2026-02-17T12:00:00Z",
+				"delivery-acked-at:2026-02-17T12:05:00Z",
+				"delivery-acked-by:gastown/worker",
+				"delivery:acked",
+			},
+		} {
+			state, _, at, _ := ParseDeliveryLabels(labels)
+			if state != DeliveryStateAcked {
+				t.Fatalf("state = %q, want %q", state, DeliveryStateAcked)
+			}
+			want := time.Date(2026, 2, 17, 12, 10, 0, 0, time.UTC)
+			if at == nil || !at.Equal(want) {
+				t.Fatalf("ackedAt = %v, want latest %v (labels %v)", at, want, labels)
+			}
+		}
+	})
+
+	t.Run("latest ack names its acker in append order", func(t *testing.T) {
+		first := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+		second := first.Add(5 * time.Minute)
+		// A later-appended ack with an older clock does not win.
+		labels := append(DeliveryAckLabelSequence("gastown/bob", second), DeliveryAckLabelSequence("gastown/alice", first)...)
+		state, by, at, _ := ParseDeliveryLabels(labels)
+		if state != DeliveryStateAcked || by != "gastown/bob" || at == nil || !at.Equal(second) {
+			t.Fatalf("got %s by %q at %v, want acked by gastown/bob at %v", state, by, at, second)
+		}
+	})
+
+	t.Run("latest acked-at without acked label stays pending", func(t *testing.T) {
+		state, by, at, _ := ParseDeliveryLabels([]string{
+			DeliveryLabelPending,
+			"delivery-acked-by:gastown/worker",
+			"delivery-acked-at:2026-02-17T12:00:00Z",
+			"delivery-acked-by:gastown/worker",
+			"delivery-acked-at:2026-02-17T12:05:00Z",
+		})
+		if state != DeliveryStatePending || by != "" || at != nil {
+			t.Fatalf("got %s by %q at %v, want pending with no ack metadata", state, by, at)
+		}
+	})
 }
 
 func TestDeliveryAckLabelSequenceIdempotent(t *testing.T) {
@@ -180,3 +242,447 @@ func TestDeliveryAckLabelSequenceIdempotent(t *testing.T) {
 		}
 	})
 }
+
+// withoutSentAt drops the delivery-sent-at label, whose timestamp varies
+// between calls.
+func withoutSentAt(labels []string) []string {
+	var out []string
+	for _, l := range labels {
+		if !strings.HasPrefix(l, DeliveryLabelSentAtPrefix) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func TestBuildFanoutSendLabels(t *testing.T) {
+	got := withoutSentAt(BuildFanoutSendLabels([]string{"town-b/mayor", " town-a/mayor ", "", "town-b/mayor"}))
+	want := []string{
+		"delivery:pending",
+		"delivery-schema:3",
+		"delivery-pending-for:town-a/mayor",
+		"delivery-pending-for:town-b/mayor",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BuildFanoutSendLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestParseFanoutDeliveryLabels_PartialAcks(t *testing.T) {
+	at := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	labels := BuildFanoutSendLabels([]string{"town-a/mayor", "town-b/mayor", "town-c/mayor"})
+
+	status := ParseFanoutDeliveryLabels(labels)
+	if !reflect.DeepEqual(status.Pending, status.Recipients) || len(status.Acked) != 0 || status.Complete() {
+		t.Fatalf("fresh fan-out status = %+v, want all pending", status)
+	}
+
+	// town-b acks, plus an ack from an identity that was never a recipient.
+	labels = append(labels, DeliveryAckLabelSequence("town-b/mayor", at)...)
+	labels = append(labels, DeliveryAckLabelSequence("stranger/mayor", at)...)
+
+	status = ParseFanoutDeliveryLabels(labels)
+	if want := []string{"town-b/mayor"}; !reflect.DeepEqual(status.Acked, want) {
+		t.Errorf("Acked = %v, want %v", status.Acked, want)
+	}
+	if want := []string{"town-a/mayor", "town-c/mayor"}; !reflect.DeepEqual(status.Pending, want) {
+		t.Errorf("Pending = %v, want %v", status.Pending, want)
+	}
+	if status.Complete() {
+		t.Error("Complete() = true with recipients still pending")
+	}
+
+	// The single-recipient parser already sees the message as acked.
+	if state, _, _, _ := ParseDeliveryLabels(labels); state != DeliveryStateAcked {
+		t.Errorf("ParseDeliveryLabels state = %q, want %q", state, DeliveryStateAcked)
+	}
+
+	labels = append(labels, DeliveryAckLabelSequence("town-a/mayor", at)...)
+	labels = append(labels, DeliveryAckLabelSequence("town-c/mayor", at)...)
+	if status = ParseFanoutDeliveryLabels(labels); !status.Complete() {
+		t.Errorf("status = %+v, want complete after all acks", status)
+	}
+}
+
+func TestParseFanoutDeliveryLabels_NoRecipients(t *testing.T) {
+	status := ParseFanoutDeliveryLabels(DeliverySendLabels())
+	if len(status.Recipients) != 0 || status.Complete() {
+		t.Errorf("status = %+v, want no recipients and not complete", status)
+	}
+}
+
+func TestParseDeliveryAcks(t *testing.T) {
+	first := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	third := second.Add(time.Hour)
+
+	// Fan-out acks in the order they were appended: bob acks first, then
+	// alice, then bob retries.
+	appended := BuildFanoutSendLabels([]string{"gastown/alice", "gastown/bob"})
+	appended = append(appended, DeliveryAckLabelSequence("gastown/bob", first)...)
+	appended = append(appended, DeliveryAckLabelSequence("gastown/alice", second)...)
+	appended = append(appended, DeliveryAckLabelSequence("gastown/bob", third)...)
+
+	t.Run("appended order pairs each acker with its time", func(t *testing.T) {
+		acks := ParseDeliveryAcks(appended)
+		want := []struct {
+			by string
+			at time.Time
+		}{{"gastown/bob", first}, {"gastown/alice", second}}
+		if len(acks) != len(want) {
+			t.Fatalf("got %d acks, want %d: %+v", len(acks), len(want), acks)
+		}
+		for i, w := range want {
+			if acks[i].By != w.by || acks[i].At == nil || !acks[i].At.Equal(w.at) {
+				t.Errorf("acks[%d] = %s at %v, want %s at %v", i, acks[i].By, acks[i].At, w.by, w.at)
+			}
+		}
+	})
+
+	t.Run("sorted order drops times it cannot pair", func(t *testing.T) {
+		labels := append([]string{}, appended...)
+		sort.Strings(labels)
+		acks := ParseDeliveryAcks(labels)
+		if len(acks) != 2 {
+			t.Fatalf("got %d acks, want 2: %+v", len(acks), acks)
+		}
+		for _, ack := range acks {
+			if ack.At != nil {
+				t.Errorf("%s at = %v, want nil", ack.By, ack.At)
+			}
+		}
+	})
+
+	t.Run("sorted single acker keeps earliest time", func(t *testing.T) {
+		labels := append(DeliveryAckLabelSequence("gastown/bob", second), DeliveryAckLabelSequence("gastown/bob", first)...)
+		sort.Strings(labels)
+		acks := ParseDeliveryAcks(labels)
+		if len(acks) != 1 || acks[0].By != "gastown/bob" || acks[0].At == nil || !acks[0].At.Equal(first) {
+			t.Fatalf("got %+v, want gastown/bob at %v", acks, first)
+		}
+	})
+
+	t.Run("no acks", func(t *testing.T) {
+		if acks := ParseDeliveryAcks(DeliverySendLabels()); len(acks) != 0 {
+			t.Fatalf("got %+v, want none", acks)
+		}
+	})
+}
+
+func TestParseDeliverySchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   int
+	}{
+		{"unversioned send", []string{DeliveryLabelPending}, DeliverySchemaUnversioned},
+		{"unversioned acked", append([]string{DeliveryLabelPending}, DeliveryAckLabelSequence("a/", time.Now())...), 1},
+		{"versioned send", DeliverySendLabels(), DeliverySchemaVersion},
+		{"versioned fan-out", BuildFanoutSendLabels([]string{"a/", "b/"}), DeliverySchemaVersion},
+		{"highest wins", []string{"delivery-schema:2", "delivery-schema:3", DeliveryLabelPending}, 3},
+		{"malformed ignored", []string{"delivery-schema:next", "delivery-schema:0"}, DeliverySchemaUnversioned},
+		{"no labels", nil, DeliverySchemaUnversioned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseDeliverySchema(tt.labels); got != tt.want {
+				t.Errorf("ParseDeliverySchema(%v) = %d, want %d", tt.labels, got, tt.want)
+			}
+		})
+	}
+
+	// The schema label must not disturb delivery state parsing.
+	if state, _, _, _ := ParseDeliveryLabels(DeliverySendLabels()); state != DeliveryStatePending {
+		t.Errorf("ParseDeliveryLabels(send labels) state = %q, want pending", state)
+	}
+}
+
+func TestDeliverySendLabelsWithTTL(t *testing.T) {
+	if got := DeliverySendLabelsWithTTL(0); !reflect.DeepEqual(withoutSentAt(got), withoutSentAt(DeliverySendLabels())) {
+		t.Errorf("DeliverySendLabelsWithTTL(0) = %v, want the plain send labels", got)
+	}
+
+	before := time.Now().UTC().Truncate(time.Second)
+	labels := DeliverySendLabelsWithTTL(time.Hour)
+	if !reflect.DeepEqual(withoutSentAt(labels[:len(labels)-1]), withoutSentAt(DeliverySendLabels())) {
+		t.Fatalf("DeliverySendLabelsWithTTL() = %v, want the send labels first", labels)
+	}
+	ts := strings.TrimPrefix(labels[len(labels)-1], DeliveryLabelExpiresAtPrefix)
+	expiresAt, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		t.Fatalf("last label %q is not an expiry: %v", labels[len(labels)-1], err)
+	}
+	if d := expiresAt.Sub(before); d < time.Hour || d > time.Hour+time.Minute {
+		t.Errorf("expiry %s is not an hour from now", expiresAt)
+	}
+
+	if state, _, _, _ := ParseDeliveryLabels(labels); state != DeliveryStatePending {
+		t.Errorf("fresh TTL delivery state = %q, want %q", state, DeliveryStatePending)
+	}
+}
+
+func TestParseDeliveryLabels_Expiry(t *testing.T) {
+	past := DeliveryExpiryLabel(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	future := DeliveryExpiryLabel(time.Now().Add(time.Hour))
+
+	t.Run("expired without ack", func(t *testing.T) {
+		state, by, at, _ := ParseDeliveryLabels([]string{DeliveryLabelPending, past})
+		if state != DeliveryStateExpired || by != "" || at != nil {
+			t.Fatalf("got (%q, %q, %v), want expired with no ack metadata", state, by, at)
+		}
+	})
+
+	t.Run("not yet expired", func(t *testing.T) {
+		if state, _, _, _ := ParseDeliveryLabels([]string{DeliveryLabelPending, future}); state != DeliveryStatePending {
+			t.Fatalf("state = %q, want %q", state, DeliveryStatePending)
+		}
+	})
+
+	t.Run("re-delivery extends expiry", func(t *testing.T) {
+		if state, _, _, _ := ParseDeliveryLabels([]string{DeliveryLabelPending, past, future}); state != DeliveryStatePending {
+			t.Fatalf("state = %q, want %q: the latest expiry counts", state, DeliveryStatePending)
+		}
+	})
+
+	t.Run("partial ack after expiry stays expired", func(t *testing.T) {
+		state, _, _, _ := ParseDeliveryLabels([]string{
+			DeliveryLabelPending, past,
+			"delivery-acked-by:gastown/worker",
+			"delivery-acked-at:2026-02-17T12:00:00Z",
+		})
+		if state != DeliveryStateExpired {
+			t.Fatalf("state = %q, want %q until delivery:acked is written", state, DeliveryStateExpired)
+		}
+	})
+
+	t.Run("expired then acked is acked", func(t *testing.T) {
+		// Lexicographic order, as bd show --json returns labels.
+		labels := []string{DeliveryLabelPending, past}
+		labels = append(labels, DeliveryAckLabelSequence("gastown/worker", time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC))...)
+		sort.Strings(labels)
+		state, by, at, _ := ParseDeliveryLabels(labels)
+		if state != DeliveryStateAcked {
+			t.Fatalf("state = %q, want %q", state, DeliveryStateAcked)
+		}
+		if by != "gastown/worker" || at == nil {
+			t.Fatalf("ack metadata = (%q, %v), want gastown/worker with a time", by, at)
+		}
+	})
+}
+
+func TestDeliveryNackLabelSequenceOrder(t *testing.T) {
+	at := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	got := DeliveryNackLabelSequence("gastown/worker", "  bad\npayload ", at)
+	want := []string{
+		"delivery-nacked-by:gastown/worker",
+		"delivery-nacked-at:2026-02-17T12:00:00Z",
+		"delivery-nack-reason:bad payload",
+		"delivery:nacked",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DeliveryNackLabelSequence() = %v, want %v", got, want)
+	}
+}
+
+func TestParseDeliveryLabels_Nack(t *testing.T) {
+	first := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	nack := DeliveryNackLabelSequence("gastown/worker", "bad payload", first)
+
+	t.Run("nacked", func(t *testing.T) {
+		labels := append([]string{DeliveryLabelPending}, nack...)
+		sort.Strings(labels)
+		state, by, at, reason := ParseDeliveryLabels(labels)
+		if state != DeliveryStateNacked || by != "gastown/worker" || reason != "bad payload" {
+			t.Fatalf("got (%q, %q, %q), want nacked by gastown/worker for bad payload", state, by, reason)
+		}
+		if at == nil || !at.Equal(first) {
+			t.Fatalf("nacked at = %v, want %v", at, first)
+		}
+	})
+
+	t.Run("partial nack write keeps pending", func(t *testing.T) {
+		state, _, _, reason := ParseDeliveryLabels(append([]string{DeliveryLabelPending}, nack[:3]...))
+		if state != DeliveryStatePending || reason != "" {
+			t.Fatalf("got (%q, %q), want pending with no reason", state, reason)
+		}
+	})
+
+	t.Run("nack beats expiry", func(t *testing.T) {
+		labels := append([]string{DeliveryLabelPending, DeliveryExpiryLabel(first.AddDate(-1, 0, 0))}, nack...)
+		if state, _, _, _ := ParseDeliveryLabels(labels); state != DeliveryStateNacked {
+			t.Fatalf("state = %q, want %q", state, DeliveryStateNacked)
+		}
+	})
+
+	t.Run("latest nack time", func(t *testing.T) {
+		labels := append(append([]string{}, nack...), DeliveryNackLabelSequence("gastown/worker", "bad payload", second)...)
+		if _, _, at, _ := ParseDeliveryLabels(labels); at == nil || !at.Equal(second) {
+			t.Fatalf("nacked at = %v, want the latest, %v", at, second)
+		}
+	})
+
+	// Acked wins over nacked in either order: a successful ack is final.
+	for name, labels := range map[string][]string{
+		"nack then ack": append(append([]string{DeliveryLabelPending}, nack...), DeliveryAckLabelSequence("gastown/worker", second)...),
+		"ack then nack": append(append([]string{DeliveryLabelPending}, DeliveryAckLabelSequence("gastown/worker", first)...),
+			DeliveryNackLabelSequence("gastown/worker", "late failure", second)...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sort.Strings(labels)
+			state, by, _, reason := ParseDeliveryLabels(labels)
+			if state != DeliveryStateAcked || by != "gastown/worker" || reason != "" {
+				t.Fatalf("got (%q, %q, %q), want acked by gastown/worker with no reason", state, by, reason)
+			}
+		})
+	}
+}
+
+func TestParseDelivery(t *testing.T) {
+	at := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	expiry := at.Add(24 * time.Hour)
+
+	t.Run("acked", func(t *testing.T) {
+		labels := append([]string{DeliveryLabelPending}, DeliveryAckLabelSequence("gastown/worker", at)...)
+		labels = append(labels, DeliveryExpiryLabel(at), DeliveryExpiryLabel(expiry), NextAttemptLabel(labels))
+		sort.Strings(labels)
+		rec := ParseDelivery(labels)
+		if rec.State != DeliveryStateAcked || rec.AckedBy != "gastown/worker" || rec.AckedAt == nil || !rec.AckedAt.Equal(at) {
+			t.Fatalf("got %+v, want acked by gastown/worker at %v", rec, at)
+		}
+		if rec.NackedBy != "" || rec.NackedAt != nil || rec.NackReason != "" {
+			t.Fatalf("nack fields set on an acked record: %+v", rec)
+		}
+		if rec.ExpiresAt == nil || !rec.ExpiresAt.Equal(expiry) {
+			t.Fatalf("expires at = %v, want the latest, %v", rec.ExpiresAt, expiry)
+		}
+		if rec.Attempt != 2 || rec.DeadLetter {
+			t.Fatalf("attempt = %d, dead letter = %v; want 2, false", rec.Attempt, rec.DeadLetter)
+		}
+	})
+
+	t.Run("nacked", func(t *testing.T) {
+		labels := append([]string{DeliveryLabelPending}, DeliveryNackLabelSequence("gastown/worker", "bad payload", at)...)
+		rec := ParseDelivery(labels)
+		if rec.State != DeliveryStateNacked || rec.NackedBy != "gastown/worker" || rec.NackReason != "bad payload" || rec.NackedAt == nil {
+			t.Fatalf("got %+v, want nacked by gastown/worker for bad payload", rec)
+		}
+		if rec.AckedBy != "" || rec.AckedAt != nil {
+			t.Fatalf("ack fields set on a nacked record: %+v", rec)
+		}
+	})
+
+	t.Run("untracked", func(t *testing.T) {
+		rec := ParseDelivery([]string{"from:mayor/"})
+		if rec.State != "" || rec.Attempt != 1 || rec.ExpiresAt != nil {
+			t.Fatalf("got %+v, want an empty record on attempt 1", rec)
+		}
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		if rec := ParseDelivery([]string{DeliveryLabelPending, DeliveryLabelDeadLetter}); !rec.DeadLetter {
+			t.Fatalf("got %+v, want dead letter", rec)
+		}
+	})
+
+	// ParseDeliveryLabels must keep returning what it did before DeliveryRecord.
+	t.Run("wrapper", func(t *testing.T) {
+		for _, labels := range [][]string{
+			append([]string{DeliveryLabelPending}, DeliveryAckLabelSequence("gastown/worker", at)...),
+			append([]string{DeliveryLabelPending}, DeliveryNackLabelSequence("gastown/worker", "bad payload", at)...),
+			{DeliveryLabelPending},
+			nil,
+		} {
+			rec := ParseDelivery(labels)
+			state, by, gotAt, reason := ParseDeliveryLabels(labels)
+			wantBy, wantAt := rec.AckedBy, rec.AckedAt
+			if rec.State == DeliveryStateNacked {
+				wantBy, wantAt = rec.NackedBy, rec.NackedAt
+			}
+			if state != rec.State || by != wantBy || gotAt != wantAt && !gotAt.Equal(*wantAt) || reason != rec.NackReason {
+				t.Fatalf("ParseDeliveryLabels(%v) = (%q, %q, %v, %q), ParseDelivery = %+v", labels, state, by, gotAt, reason, rec)
+			}
+		}
+	})
+}
+
+func TestParseDelivery_MalformedTimestamps(t *testing.T) {
+	truncated := DeliveryLabelAckedAtPrefix + "2026-02-17T12:0"
+	labels := []string{DeliveryLabelPending, DeliveryLabelAckedByPrefix + "gastown/worker", truncated, DeliveryLabelAcked}
+
+	rec := ParseDelivery(labels)
+	if rec.State != DeliveryStateAcked || rec.AckedAt != nil {
+		t.Fatalf("got %+v, want acked with no time", rec)
+	}
+	if rec.Err == nil || !strings.Contains(rec.Err.Error(), truncated) {
+		t.Fatalf("err = %v, want it to name %q", rec.Err, truncated)
+	}
+
+	// The lenient wrapper is unchanged.
+	if state, by, at, _ := ParseDeliveryLabels(labels); state != DeliveryStateAcked || by != "gastown/worker" || at != nil {
+		t.Fatalf("ParseDeliveryLabels = (%q, %q, %v), want acked by gastown/worker with no time", state, by, at)
+	}
+
+	// A missing timestamp is not an error.
+	if rec := ParseDelivery(labels[:1]); rec.Err != nil {
+		t.Fatalf("err = %v for well-formed labels, want nil", rec.Err)
+	}
+}
+
+func TestParseDelivery_SentAt(t *testing.T) {
+	sent := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	acked := sent.Add(90 * time.Second)
+	labels := append(DeliverySendLabelsAt(sent), DeliveryAckLabelSequence("town/mayor", acked)...)
+
+	rec := ParseDelivery(labels)
+	if rec.Err != nil {
+		t.Fatalf("ParseDelivery() Err = %v", rec.Err)
+	}
+	if rec.SentAt == nil || !rec.SentAt.Equal(sent) {
+		t.Fatalf("SentAt = %v, want %s", rec.SentAt, sent)
+	}
+	if age, ok := rec.Age(sent.Add(time.Hour)); !ok || age != time.Hour {
+		t.Errorf("Age() = %s, %v; want 1h, true", age, ok)
+	}
+	if lat, ok := rec.Latency(); !ok || lat != 90*time.Second {
+		t.Errorf("Latency() = %s, %v; want 1m30s, true", lat, ok)
+	}
+
+	// A retried send keeps the original send time.
+	retried := append(labels, DeliverySentAtLabel(sent.Add(time.Minute)))
+	if rec := ParseDelivery(retried); rec.SentAt == nil || !rec.SentAt.Equal(sent) {
+		t.Errorf("retried send SentAt = %v, want the earliest %s", rec.SentAt, sent)
+	}
+
+	// Still pending: age is known, latency is not.
+	pending := ParseDelivery(DeliverySendLabelsAt(sent))
+	if _, ok := pending.Age(sent); !ok {
+		t.Error("pending Age() ok = false, want true")
+	}
+	if _, ok := pending.Latency(); ok {
+		t.Error("pending Latency() ok = true, want false")
+	}
+}
+
+func TestParseDelivery_MissingOrMalformedSentAt(t *testing.T) {
+	// Messages sent before the label existed parse as before.
+	old := ParseDelivery([]string{DeliveryLabelPending, DeliveryLabelAcked})
+	if old.State != DeliveryStateAcked || old.SentAt != nil || old.Err != nil {
+		t.Errorf("unlabelled send = %+v, want acked with no SentAt and no Err", old)
+	}
+	if _, ok := old.Age(time.Now()); ok {
+		t.Error("Age() without sent-at ok = true, want false")
+	}
+
+	// A corrupt sent-at is reported but does not disturb the state.
+	rec := ParseDelivery([]string{DeliveryLabelPending, DeliveryLabelSentAtPrefix + "2026-10-"})
+	if rec.State != DeliveryStatePending || rec.SentAt != nil {
+		t.Errorf("malformed sent-at = %+v, want pending with no SentAt", rec)
+	}
+	if rec.Err == nil || !strings.Contains(rec.Err.Error(), "delivery-sent-at:2026-10-") {
+		t.Errorf("Err = %v, want the malformed sent-at label", rec.Err)
+	}
+	if _, ok := rec.Latency(); ok {
+		t.Error("Latency() with malformed sent-at ok = true, want false")
+	}
+}
@@ -0,0 +1,56 @@
+package mail
+
+import "fmt"
+
+// deliveryTransitions lists, for each delivery state, the states it may
+// move to. They follow from how ParseDelivery reads the append-only labels:
+//   - acked is final: once delivery:acked is written, nothing outranks it;
+//   - nacked can still be acked (acked wins over nacked), but a nack label
+//     is never undone, so it cannot return to pending or expire;
+//   - expired returns to pending when the sweeper re-delivers it with a
+//     later expiry, and can still be acked or nacked late;
+//   - pending may be acked, nacked, or expire.
+//
+// Every state may also "move" to itself, e.g. a retried ack.
+var deliveryTransitions = map[string][]string{
+	DeliveryStatePending: {DeliveryStateAcked, DeliveryStateNacked, DeliveryStateExpired},
+	DeliveryStateExpired: {DeliveryStatePending, DeliveryStateAcked, DeliveryStateNacked},
+	DeliveryStateNacked:  {DeliveryStateAcked},
+	DeliveryStateAcked:   nil,
+}
+
+// CanTransition reports whether a delivery in state from may move to state
+// to. Producers should check it before writing labels for the new state.
+// Same-state moves are allowed; unknown states are not.
+func CanTransition(from, to string) bool {
+	next, ok := deliveryTransitions[from]
+	if !ok {
+		return false
+	}
+	if _, ok := deliveryTransitions[to]; !ok {
+		return false
+	}
+	if from == to {
+		return true
+	}
+	for _, s := range next {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition is CanTransition with an error describing the illegal
+// move.
+func ValidateTransition(from, to string) error {
+	if CanTransition(from, to) {
+		return nil
+	}
+	for _, s := range []string{from, to} {
+		if _, ok := deliveryTransitions[s]; !ok {
+			return fmt.Errorf("unknown delivery state %q", s)
+		}
+	}
+	return fmt.Errorf("illegal delivery transition %s -> %s", from, to)
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{DeliveryStatePending, DeliveryStatePending, true},
+		{DeliveryStatePending, DeliveryStateAcked, true},
+		{DeliveryStatePending, DeliveryStateNacked, true},
+		{DeliveryStatePending, DeliveryStateExpired, true},
+
+		{DeliveryStateExpired, DeliveryStateExpired, true},
+		{DeliveryStateExpired, DeliveryStatePending, true},
+		{DeliveryStateExpired, DeliveryStateAcked, true},
+		{DeliveryStateExpired, DeliveryStateNacked, true},
+
+		{DeliveryStateNacked, DeliveryStateNacked, true},
+		{DeliveryStateNacked, DeliveryStateAcked, true},
+		{DeliveryStateNacked, DeliveryStatePending, false},
+		{DeliveryStateNacked, DeliveryStateExpired, false},
+
+		{DeliveryStateAcked, DeliveryStateAcked, true},
+		{DeliveryStateAcked, DeliveryStatePending, false},
+		{DeliveryStateAcked, DeliveryStateNacked, false},
+		{DeliveryStateAcked, DeliveryStateExpired, false},
+
+		{"", DeliveryStatePending, false},
+		{DeliveryStatePending, "delivered", false},
+		{"bogus", "bogus", false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+		err := ValidateTransition(tt.from, tt.to)
+		if (err == nil) != tt.want {
+			t.Errorf("ValidateTransition(%q, %q) = %v, want error %v", tt.from, tt.to, err, !tt.want)
+		}
+	}
+}
+
+func TestValidateTransition_Messages(t *testing.T) {
+	t.Parallel()
+	if err := ValidateTransition(DeliveryStateAcked, DeliveryStatePending); err == nil || !strings.Contains(err.Error(), "illegal delivery transition acked -> pending") {
+		t.Errorf("acked -> pending error = %v", err)
+	}
+	if err := ValidateTransition(DeliveryStatePending, "delivered"); err == nil || !strings.Contains(err.Error(), `unknown delivery state "delivered"`) {
+		t.Errorf("unknown state error = %v", err)
+	}
+}
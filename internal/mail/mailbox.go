@@ -848,7 +848,9 @@ func (m *Mailbox) AcknowledgeDeliveries(recipientAddress string, messages []*Mes
 		if AddressToIdentity(msg.To) != recipientIdentity {
 			continue
 		}
-		if msg.DeliveryState == "" || msg.DeliveryState == DeliveryStateAcked {
+		// A nack is the recipient's answer; reading it again must not
+		// turn it into an ack.
+		if msg.DeliveryState == "" || msg.DeliveryState == DeliveryStateAcked || msg.DeliveryState == DeliveryStateNacked {
 			continue
 		}
 		toAck = append(toAck, msg)
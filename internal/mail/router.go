@@ -901,7 +901,18 @@ func (r *Router) sendToSingle(msg *Message) error {
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
-	labels = append(labels, DeliverySendLabels()...)
+	if msg.Fanout {
+		recipients := []string{toIdentity}
+		for _, cc := range msg.CC {
+			recipients = append(recipients, AddressToIdentity(cc))
+		}
+		labels = append(labels, BuildFanoutSendLabels(recipients)...)
+		if msg.DeliveryTTL > 0 {
+			labels = append(labels, DeliveryExpiryLabel(timeNow().Add(msg.DeliveryTTL)))
+		}
+	} else {
+		labels = append(labels, DeliverySendLabelsWithTTL(msg.DeliveryTTL)...)
+	}
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
 	}
@@ -1030,7 +1041,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
 	labels = append(labels, "queue:"+queueName)
-	labels = append(labels, DeliverySendLabels()...)
+	labels = append(labels, DeliverySendLabelsWithTTL(msg.DeliveryTTL)...)
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
 	}
@@ -107,6 +107,16 @@ type Message struct {
 	// CC'd recipients see the message in their inbox but are not the primary recipient.
 	CC []string `json:"cc,omitempty"`
 
+	// Fanout tracks acks per recipient (To plus every CC) on the single
+	// delivered message, using BuildFanoutSendLabels. Without it, the first
+	// ack marks the whole message delivered.
+	Fanout bool `json:"fanout,omitempty"`
+
+	// DeliveryTTL, when positive, is how long the message may go unacked
+	// before its delivery state becomes expired. It is recorded as a
+	// delivery-expires-at label at send time.
+	DeliveryTTL time.Duration `json:"-"`
+
 	// Queue is the queue name for queue-routed messages.
 	// Mutually exclusive with To and Channel - a message is either direct, queued, or broadcast.
 	Queue string `json:"queue,omitempty"`
@@ -123,12 +133,16 @@ type Message struct {
 	// Only set for queue messages after claiming.
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
 
-	// DeliveryState tracks two-phase mailbox delivery state: pending or acked.
+	// DeliveryState tracks two-phase mailbox delivery state: pending, acked,
+	// nacked, or expired.
 	DeliveryState string `json:"delivery_state,omitempty"`
-	// DeliveryAckedBy is the recipient identity that acknowledged receipt.
+	// DeliveryAckedBy is the recipient identity that acknowledged receipt,
+	// or that rejected the message when DeliveryState is nacked.
 	DeliveryAckedBy string `json:"delivery_acked_by,omitempty"`
-	// DeliveryAckedAt is when receipt was acknowledged.
+	// DeliveryAckedAt is when receipt was acknowledged or rejected.
 	DeliveryAckedAt *time.Time `json:"delivery_acked_at,omitempty"`
+	// DeliveryNackReason is why the recipient rejected the message.
+	DeliveryNackReason string `json:"delivery_nack_reason,omitempty"`
 
 	// SuppressNotify tells the router to skip all recipient notification
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
@@ -314,9 +328,10 @@ type BeadsMessage struct {
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
 	// Two-phase delivery metadata
-	deliveryState   string
-	deliveryAckedBy string
-	deliveryAckedAt *time.Time
+	deliveryState      string
+	deliveryAckedBy    string
+	deliveryAckedAt    *time.Time
+	deliveryNackReason string
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -334,6 +349,7 @@ func (bm *BeadsMessage) ParseLabels() {
 	bm.deliveryState = ""
 	bm.deliveryAckedBy = ""
 	bm.deliveryAckedAt = nil
+	bm.deliveryNackReason = ""
 
 	for _, label := range bm.Labels {
 		if strings.HasPrefix(label, "from:") {
@@ -360,7 +376,7 @@ func (bm *BeadsMessage) ParseLabels() {
 		}
 	}
 
-	bm.deliveryState, bm.deliveryAckedBy, bm.deliveryAckedAt = ParseDeliveryLabels(bm.Labels)
+	bm.deliveryState, bm.deliveryAckedBy, bm.deliveryAckedAt, bm.deliveryNackReason = ParseDeliveryLabels(bm.Labels)
 }
 
 // GetCC returns the parsed CC recipients.
@@ -430,6 +446,8 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		DeliveryState:   bm.deliveryState,
 		DeliveryAckedBy: bm.deliveryAckedBy,
 		DeliveryAckedAt: bm.deliveryAckedAt,
+
+		DeliveryNackReason: bm.deliveryNackReason,
 	}
 }
 
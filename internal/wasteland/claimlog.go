@@ -0,0 +1,129 @@
+package wasteland
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ClaimLogFile is the town-local log of wanted-board actions, kept in the
+// wasteland directory. It survives remote resets and works offline.
+const ClaimLogFile = "claims.jsonl"
+
+// MaxClaimLogBytes bounds the claim log. When an append would grow the file
+// past this size, it is rotated to ClaimLogFile + ".1" (replacing any older
+// rotation), so at most two files' worth of history is kept.
+const MaxClaimLogBytes int64 = 1 << 20
+
+// Claim log outcomes.
+const (
+	ClaimLogOK    = "ok"
+	ClaimLogError = "error"
+)
+
+// ClaimLogEntry is one line of the claim log.
+type ClaimLogEntry struct {
+	Timestamp time.Time `json:"ts"`
+	WantedID  string    `json:"wanted_id"`
+	Action    string    `json:"action"`
+	Outcome   string    `json:"outcome"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// ClaimLogPath returns the path of a town's claim log.
+func ClaimLogPath(townRoot string) string {
+	return filepath.Join(WastelandDir(townRoot), ClaimLogFile)
+}
+
+// AppendClaimLog appends entry to the town's claim log, rotating it when it
+// would exceed MaxClaimLogBytes.
+func AppendClaimLog(townRoot string, entry ClaimLogEntry) error {
+	return appendClaimLog(ClaimLogPath(townRoot), entry, MaxClaimLogBytes)
+}
+
+func appendClaimLog(path string, entry ClaimLogEntry, maxBytes int64) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling claim log entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating claim log directory: %w", err)
+	}
+
+	// Several gt processes in one town can append at once.
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring claim log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 && info.Size()+int64(len(data)) > maxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotating claim log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: claim log is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening claim log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing claim log: %w", err)
+	}
+	return nil
+}
+
+// ReadClaimLog returns the town's claim log entries, oldest first, including
+// the rotated file. A missing log yields no entries. Malformed lines (e.g.
+// from a write interrupted mid-line) are skipped.
+func ReadClaimLog(townRoot string) ([]ClaimLogEntry, error) {
+	path := ClaimLogPath(townRoot)
+	var entries []ClaimLogEntry
+	for _, p := range []string{path + ".1", path} {
+		got, err := readClaimLogFile(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, got...)
+	}
+	return entries, nil
+}
+
+func readClaimLogFile(path string) ([]ClaimLogEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening claim log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []ClaimLogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var e ClaimLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading claim log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,83 @@
+package wasteland
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClaimLog_AppendAndRead(t *testing.T) {
+	townRoot := t.TempDir()
+
+	entries, err := ReadClaimLog(townRoot)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadClaimLog(empty) = %v, %v; want none", entries, err)
+	}
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := AppendClaimLog(townRoot, ClaimLogEntry{Timestamp: ts, WantedID: "w-1", Action: "claim", Outcome: ClaimLogOK}); err != nil {
+		t.Fatalf("AppendClaimLog() error: %v", err)
+	}
+	if err := AppendClaimLog(townRoot, ClaimLogEntry{WantedID: "w-2", Action: "done", Outcome: ClaimLogError, Detail: "not claimed"}); err != nil {
+		t.Fatalf("AppendClaimLog() error: %v", err)
+	}
+
+	entries, err = ReadClaimLog(townRoot)
+	if err != nil {
+		t.Fatalf("ReadClaimLog() error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if !entries[0].Timestamp.Equal(ts) || entries[0].WantedID != "w-1" {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	if entries[1].Timestamp.IsZero() || entries[1].Detail != "not claimed" {
+		t.Errorf("entries[1] = %+v, want timestamp filled and detail kept", entries[1])
+	}
+}
+
+func TestClaimLog_RotatesAtMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ClaimLogFile)
+
+	entry := ClaimLogEntry{WantedID: "w-1", Action: "claim", Outcome: ClaimLogOK}
+	for i := 0; i < 5; i++ {
+		if err := appendClaimLog(path, entry, 200); err != nil {
+			t.Fatalf("appendClaimLog() error: %v", err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 200 {
+		t.Errorf("log size = %d, want <= 200 after rotation", info.Size())
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("rotated file missing: %v", err)
+	}
+}
+
+func TestClaimLog_SkipsMalformedLines(t *testing.T) {
+	townRoot := t.TempDir()
+	path := ClaimLogPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"ts":"2026-01-02T03:04:05Z","wanted_id":"w-1","action":"claim","outcome":"ok"}
+{"ts":"2026-01-02T03:05:
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadClaimLog(townRoot)
+	if err != nil {
+		t.Fatalf("ReadClaimLog() error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].WantedID != "w-1" {
+		t.Errorf("entries = %+v, want only the intact line", entries)
+	}
+}
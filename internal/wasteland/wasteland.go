@@ -41,6 +41,10 @@ type Config struct {
 	// RigHandle is the rig's handle in the registry.
 	RigHandle string `json:"rig_handle"`
 
+	// Database is the local dolt database holding the commons. Empty means
+	// the default, wl_commons.
+	Database string `json:"database,omitempty"`
+
 	// JoinedAt is when the town joined the wasteland.
 	JoinedAt time.Time `json:"joined_at"`
 }
@@ -256,10 +260,20 @@ func LocalCloneDir(townRoot, upstreamOrg, upstreamDB string) string {
 	return filepath.Join(WastelandDir(townRoot), upstreamOrg, upstreamDB)
 }
 
-// escapeSQLString escapes backslashes and single quotes for SQL string literals.
+// sqlEscaper escapes backslashes, single quotes, and the control characters
+// MySQL's client escapes, matching doltserver.EscapeSQL.
+var sqlEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"'", "''",
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+// escapeSQLString escapes s for use inside a single-quoted SQL string literal.
 func escapeSQLString(s string) string {
-	s = strings.ReplaceAll(s, `\`, `\\`)
-	return strings.ReplaceAll(s, "'", "''")
+	return sqlEscaper.Replace(s)
 }
 
 // DoltHubAPI abstracts DoltHub REST API operations.
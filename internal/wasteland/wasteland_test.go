@@ -104,6 +104,8 @@ func TestEscapeSQLString(t *testing.T) {
 		{"it's", "it''s"},
 		{"it''s", "it''''s"},
 		{"", ""},
+		{`C:\rigs\`, `C:\\rigs\\`},
+		{"line1\nline2\x00", `line1\nline2\0`},
 	}
 	for _, tt := range tests {
 		got := escapeSQLString(tt.input)
@@ -0,0 +1,167 @@
+package wasteland
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// WriteQueueFile is the town-local queue of wanted-board writes that could
+// not reach a remote Dolt server, kept in the wasteland directory until
+// gt wl sync replays them.
+const WriteQueueFile = "write-queue.jsonl"
+
+// QueuedWrite is one line of the write queue.
+type QueuedWrite struct {
+	// QueuedAt is when the write was attempted. Replay records it in place
+	// of the replay time.
+	QueuedAt time.Time `json:"queued_at"`
+	// Command is the gt command line that made the write, for reporting.
+	Command string `json:"command"`
+	// Script is the SQL script the write would have run.
+	Script string `json:"script"`
+}
+
+// WriteQueuePath returns the path of a town's write queue.
+func WriteQueuePath(townRoot string) string {
+	return filepath.Join(WastelandDir(townRoot), WriteQueueFile)
+}
+
+// QueueWrite appends w to the town's write queue.
+func QueueWrite(townRoot string, w QueuedWrite) error {
+	if w.QueuedAt.IsZero() {
+		w.QueuedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("marshaling queued write: %w", err)
+	}
+	data = append(data, '\n')
+
+	path := WriteQueuePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating write queue directory: %w", err)
+	}
+
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring write queue lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening write queue: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing write queue: %w", err)
+	}
+	return nil
+}
+
+// ReadWriteQueue returns the town's queued writes, oldest first. A missing
+// queue yields none. Malformed lines (e.g. from a write interrupted
+// mid-line) are skipped.
+func ReadWriteQueue(townRoot string) ([]QueuedWrite, error) {
+	f, err := os.Open(WriteQueuePath(townRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening write queue: %w", err)
+	}
+	defer f.Close()
+
+	var writes []QueuedWrite
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var w QueuedWrite
+		if err := json.Unmarshal(scanner.Bytes(), &w); err != nil || w.Script == "" {
+			continue
+		}
+		writes = append(writes, w)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading write queue: %w", err)
+	}
+	return writes, nil
+}
+
+// ReplayWriteQueue passes each queued write to apply, oldest first, and
+// removes the ones it accepts. It stops at the first error, returning it
+// with that write and the ones after it still queued. The queue stays
+// locked throughout, so writes queued meanwhile wait and land after the
+// replayed ones. It returns how many writes were removed.
+func ReplayWriteQueue(townRoot string, apply func(QueuedWrite) error) (int, error) {
+	path := WriteQueuePath(townRoot)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return 0, fmt.Errorf("acquiring write queue lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	writes, err := ReadWriteQueue(townRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	var applyErr error
+	for _, w := range writes {
+		if applyErr = apply(w); applyErr != nil {
+			break
+		}
+		done++
+	}
+
+	if err := rewriteWriteQueue(path, writes[done:]); err != nil {
+		return done, err
+	}
+	return done, applyErr
+}
+
+// rewriteWriteQueue replaces the queue at path with writes, removing it
+// when there are none. The caller holds the queue lock.
+func rewriteWriteQueue(path string, writes []QueuedWrite) error {
+	if len(writes) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing write queue: %w", err)
+		}
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("rewriting write queue: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, w := range writes {
+		if err := enc.Encode(w); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return fmt.Errorf("rewriting write queue: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rewriting write queue: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rewriting write queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package wasteland
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWriteQueue_QueueAndRead(t *testing.T) {
+	townRoot := t.TempDir()
+
+	writes, err := ReadWriteQueue(townRoot)
+	if err != nil || len(writes) != 0 {
+		t.Fatalf("ReadWriteQueue(empty) = %v, %v; want none", writes, err)
+	}
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := QueueWrite(townRoot, QueuedWrite{QueuedAt: ts, Command: "gt wl claim w-1", Script: "UPDATE wanted SET status='claimed';"}); err != nil {
+		t.Fatalf("QueueWrite() error: %v", err)
+	}
+	if err := QueueWrite(townRoot, QueuedWrite{Command: "gt wl done w-1", Script: "INSERT INTO completions VALUES ();"}); err != nil {
+		t.Fatalf("QueueWrite() error: %v", err)
+	}
+
+	writes, err = ReadWriteQueue(townRoot)
+	if err != nil {
+		t.Fatalf("ReadWriteQueue() error: %v", err)
+	}
+	if len(writes) != 2 {
+		t.Fatalf("got %d writes, want 2", len(writes))
+	}
+	if !writes[0].QueuedAt.Equal(ts) || writes[0].Command != "gt wl claim w-1" {
+		t.Errorf("writes[0] = %+v", writes[0])
+	}
+	if writes[1].QueuedAt.IsZero() {
+		t.Errorf("writes[1] = %+v, want timestamp filled", writes[1])
+	}
+}
+
+func TestReplayWriteQueue_StopsAtFirstError(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, script := range []string{"one;", "two;", "three;"} {
+		if err := QueueWrite(townRoot, QueuedWrite{Script: script}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hard := errors.New("constraint violation")
+	var seen []string
+	n, err := ReplayWriteQueue(townRoot, func(w QueuedWrite) error {
+		seen = append(seen, w.Script)
+		if w.Script == "two;" {
+			return hard
+		}
+		return nil
+	})
+	if !errors.Is(err, hard) || n != 1 {
+		t.Fatalf("ReplayWriteQueue() = %d, %v; want 1, %v", n, err, hard)
+	}
+	if len(seen) != 2 {
+		t.Errorf("applied %q, want replay to stop at the failing write", seen)
+	}
+
+	left, err := ReadWriteQueue(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 2 || left[0].Script != "two;" || left[1].Script != "three;" {
+		t.Errorf("still queued = %+v, want the failed write and the one after it", left)
+	}
+
+	n, err = ReplayWriteQueue(townRoot, func(QueuedWrite) error { return nil })
+	if err != nil || n != 2 {
+		t.Fatalf("second ReplayWriteQueue() = %d, %v; want 2, nil", n, err)
+	}
+	if _, err := os.Stat(WriteQueuePath(townRoot)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("queue file still present after draining: %v", err)
+	}
+}
+
+func TestReplayWriteQueue_NoQueue(t *testing.T) {
+	n, err := ReplayWriteQueue(t.TempDir(), func(QueuedWrite) error {
+		t.Fatal("apply called with no queue")
+		return nil
+	})
+	if n != 0 || err != nil {
+		t.Errorf("ReplayWriteQueue() = %d, %v; want 0, nil", n, err)
+	}
+}